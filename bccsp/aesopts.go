@@ -80,3 +80,12 @@ type AESCBCPKCS7ModeOpts struct {
 	// It is used only if different from nil.
 	PRNG io.Reader
 }
+
+// AESGCMModeOpts contains options for AES encryption in GCM mode.
+// The nonce is sampled using a cryptographic secure PRNG and is
+// prepended to the ciphertext.
+type AESGCMModeOpts struct {
+	// AdditionalData is authenticated along with the ciphertext,
+	// but is not encrypted. It can be nil.
+	AdditionalData []byte
+}
@@ -180,6 +180,44 @@ func AESCBCPKCS7Decrypt(key, src []byte) ([]byte, error) {
 	return nil, err
 }
 
+// AESGCMEncrypt encrypts src with AES in GCM mode, and authenticates it along
+// with additionalData. The nonce is sampled using a cryptographic secure PRNG
+// and is prepended to the ciphertext.
+func AESGCMEncrypt(key, src, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(src)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, src, additionalData), nil
+}
+
+// AESGCMDecrypt decrypts src, which was encrypted by AESGCMEncrypt,
+// and checks that it is authentic along with additionalData.
+func AESGCMDecrypt(key, src, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(src) < aead.NonceSize() {
+		return nil, errors.New("Invalid ciphertext. It must be longer than the nonce")
+	}
+	return aead.Open(nil, src[:aead.NonceSize()], src[aead.NonceSize():], additionalData)
+}
+
 type aescbcpkcs7Encryptor struct{}
 
 func (e *aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
@@ -202,6 +240,11 @@ func (e *aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp
 		return AESCBCPKCS7Encrypt(k.(*aesPrivateKey).privKey, plaintext)
 	case bccsp.AESCBCPKCS7ModeOpts:
 		return e.Encrypt(k, plaintext, &o)
+	case *bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+		return AESGCMEncrypt(k.(*aesPrivateKey).privKey, plaintext, o.AdditionalData)
+	case bccsp.AESGCMModeOpts:
+		return e.Encrypt(k, plaintext, &o)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
@@ -209,12 +252,17 @@ func (e *aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp
 
 type aescbcpkcs7Decryptor struct{}
 
-func (*aescbcpkcs7Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+func (d *aescbcpkcs7Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
 	// check for mode
-	switch opts.(type) {
+	switch o := opts.(type) {
 	case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
 		// AES in CBC mode with PKCS7 padding
 		return AESCBCPKCS7Decrypt(k.(*aesPrivateKey).privKey, ciphertext)
+	case *bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+		return AESGCMDecrypt(k.(*aesPrivateKey).privKey, ciphertext, o.AdditionalData)
+	case bccsp.AESGCMModeOpts:
+		return d.Decrypt(k, ciphertext, &o)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
@@ -677,3 +677,53 @@ func TestAESCBCPKCS7EncryptorWithRandSameCiphertext(t *testing.T) {
 
 	assert.Equal(t, ct, ct2)
 }
+
+// TestAESGCMEncryptorDecrypt tests the AES GCM mode of
+// aescbcpkcs7Encryptor and aescbcpkcs7Decryptor
+func TestAESGCMEncryptorDecrypt(t *testing.T) {
+	t.Parallel()
+
+	raw, err := GetRandomBytes(32)
+	assert.NoError(t, err)
+
+	k := &aesPrivateKey{privKey: raw, exportable: false}
+
+	msg := []byte("Hello World")
+	ad := []byte("header")
+	encryptor := &aescbcpkcs7Encryptor{}
+
+	ct, err := encryptor.Encrypt(k, msg, &bccsp.AESGCMModeOpts{AdditionalData: ad})
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(ct, msg))
+
+	ct2, err := encryptor.Encrypt(k, msg, bccsp.AESGCMModeOpts{AdditionalData: ad})
+	assert.NoError(t, err)
+	assert.NotEqual(t, ct, ct2)
+
+	decryptor := &aescbcpkcs7Decryptor{}
+
+	msg2, err := decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{AdditionalData: ad})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, msg2)
+
+	msg2, err = decryptor.Decrypt(k, ct2, bccsp.AESGCMModeOpts{AdditionalData: ad})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, msg2)
+
+	// the ciphertext and the additional data are authenticated
+	_, err = decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{AdditionalData: []byte("other")})
+	assert.EqualError(t, err, "cipher: message authentication failed")
+
+	ct[len(ct)-1] ^= 0x01
+	_, err = decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{AdditionalData: ad})
+	assert.EqualError(t, err, "cipher: message authentication failed")
+
+	_, err = decryptor.Decrypt(k, ct[:4], &bccsp.AESGCMModeOpts{AdditionalData: ad})
+	assert.EqualError(t, err, "Invalid ciphertext. It must be longer than the nonce")
+
+	_, err = AESGCMEncrypt([]byte{1}, msg, nil)
+	assert.Error(t, err)
+	_, err = AESGCMDecrypt([]byte{1}, ct, nil)
+	assert.Error(t, err)
+}
+
@@ -30,6 +30,14 @@ const (
 
 	// ApplicationResourcesTreeExperimental is the capabilties string for private data using the experimental feature of collections/sideDB.
 	ApplicationResourcesTreeExperimental = "V1_1_RESOURCETREE_EXPERIMENTAL"
+
+	// ApplicationKeyAccessIndex is the capabilties string for indexing the transactions that read or wrote each key
+	// in the history database of the peers, as queried by the key access history of qscc.
+	ApplicationKeyAccessIndex = "V1_4_KEY_ACCESS_INDEX"
+
+	// ApplicationLifecycleApproval is the capabilties string for the approval of chaincode definitions by the organizations
+	// of the channel and their commit through the lifecycle system chaincode.
+	ApplicationLifecycleApproval = "V1_4_LIFECYCLE_APPROVAL"
 )
 
 // ApplicationProvider provides capabilities information for application level config.
@@ -40,6 +48,8 @@ type ApplicationProvider struct {
 	v13                    bool
 	v142                   bool
 	v11PvtDataExperimental bool
+	keyAccessIndex         bool
+	lifecycleApproval      bool
 }
 
 // NewApplicationProvider creates a application capabilities provider.
@@ -51,6 +61,8 @@ func NewApplicationProvider(capabilities map[string]*cb.Capability) *Application
 	_, ap.v13 = capabilities[ApplicationV1_3]
 	_, ap.v142 = capabilities[ApplicationV1_4_2]
 	_, ap.v11PvtDataExperimental = capabilities[ApplicationPvtDataExperimental]
+	_, ap.keyAccessIndex = capabilities[ApplicationKeyAccessIndex]
+	_, ap.lifecycleApproval = capabilities[ApplicationLifecycleApproval]
 	return ap
 }
 
@@ -125,6 +137,19 @@ func (ap *ApplicationProvider) StorePvtDataOfInvalidTx() bool {
 	return ap.v142
 }
 
+// KeyAccessIndex returns true if the peers need to index the transactions that read or wrote each key,
+// including the invalid transactions, in the history database. The index is not enabled by any version
+// capability because it grows the history database considerably
+func (ap *ApplicationProvider) KeyAccessIndex() bool {
+	return ap.keyAccessIndex
+}
+
+// LifecycleApproval returns true if the organizations of the channel may approve chaincode definitions, which are
+// committed through the lifecycle system chaincode and validated against the approvals of the organizations
+func (ap *ApplicationProvider) LifecycleApproval() bool {
+	return ap.lifecycleApproval
+}
+
 // HasCapability returns true if the capability is supported by this binary.
 func (ap *ApplicationProvider) HasCapability(capability string) bool {
 	switch capability {
@@ -141,6 +166,10 @@ func (ap *ApplicationProvider) HasCapability(capability string) bool {
 		return true
 	case ApplicationResourcesTreeExperimental:
 		return true
+	case ApplicationKeyAccessIndex:
+		return true
+	case ApplicationLifecycleApproval:
+		return true
 	default:
 		return false
 	}
@@ -78,6 +78,34 @@ func TestApplicationPvtDataExperimental(t *testing.T) {
 	assert.True(t, ap.PrivateChannelData())
 }
 
+func TestApplicationKeyAccessIndex(t *testing.T) {
+	ap := NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV1_4_2: {},
+	})
+	assert.False(t, ap.KeyAccessIndex())
+
+	ap = NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV1_4_2:         {},
+		ApplicationKeyAccessIndex: {},
+	})
+	assert.NoError(t, ap.Supported())
+	assert.True(t, ap.KeyAccessIndex())
+}
+
+func TestApplicationLifecycleApproval(t *testing.T) {
+	ap := NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV1_4_2: {},
+	})
+	assert.False(t, ap.LifecycleApproval())
+
+	ap = NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV1_4_2:            {},
+		ApplicationLifecycleApproval: {},
+	})
+	assert.NoError(t, ap.Supported())
+	assert.True(t, ap.LifecycleApproval())
+}
+
 func TestFabToken(t *testing.T) {
 	ap := NewApplicationProvider(map[string]*cb.Capability{})
 	assert.False(t, ap.FabToken())
@@ -90,5 +118,7 @@ func TestHasCapability(t *testing.T) {
 	assert.True(t, ap.HasCapability(ApplicationV1_3))
 	assert.True(t, ap.HasCapability(ApplicationPvtDataExperimental))
 	assert.True(t, ap.HasCapability(ApplicationResourcesTreeExperimental))
+	assert.True(t, ap.HasCapability(ApplicationKeyAccessIndex))
+	assert.True(t, ap.HasCapability(ApplicationLifecycleApproval))
 	assert.False(t, ap.HasCapability("default"))
 }
@@ -25,6 +25,9 @@ const (
 
 	// ChannelV1_4_3 is the capabilities string for standard new non-backwards compatible fabric v1.4.3 channel capabilities.
 	ChannelV1_4_3 = "V1_4_3"
+
+	// ChannelV1_4_5 is the capabilities string for standard new non-backwards compatible fabric v1.4.5 channel capabilities.
+	ChannelV1_4_5 = "V1_4_5"
 )
 
 // ChannelProvider provides capabilities information for channel level config.
@@ -34,6 +37,7 @@ type ChannelProvider struct {
 	v13  bool
 	v142 bool
 	v143 bool
+	v145 bool
 }
 
 // NewChannelProvider creates a channel capabilities provider.
@@ -44,6 +48,7 @@ func NewChannelProvider(capabilities map[string]*cb.Capability) *ChannelProvider
 	_, cp.v13 = capabilities[ChannelV1_3]
 	_, cp.v142 = capabilities[ChannelV1_4_2]
 	_, cp.v143 = capabilities[ChannelV1_4_3]
+	_, cp.v145 = capabilities[ChannelV1_4_5]
 	return cp
 }
 
@@ -56,6 +61,8 @@ func (cp *ChannelProvider) Type() string {
 func (cp *ChannelProvider) HasCapability(capability string) bool {
 	switch capability {
 	// Add new capability names here
+	case ChannelV1_4_5:
+		return true
 	case ChannelV1_4_3:
 		return true
 	case ChannelV1_4_2:
@@ -72,7 +79,7 @@ func (cp *ChannelProvider) HasCapability(capability string) bool {
 // MSPVersion returns the level of MSP support required by this channel.
 func (cp *ChannelProvider) MSPVersion() msp.MSPVersion {
 	switch {
-	case cp.v143:
+	case cp.v145 || cp.v143:
 		return msp.MSPv1_4_3
 	case cp.v142:
 		return msp.MSPv1_3
@@ -87,10 +94,16 @@ func (cp *ChannelProvider) MSPVersion() msp.MSPVersion {
 
 // ConsensusTypeMigration return true if consensus-type migration is supported and permitted in both orderer and peer.
 func (cp *ChannelProvider) ConsensusTypeMigration() bool {
-	return cp.v142 || cp.v143
+	return cp.v142 || cp.v143 || cp.v145
 }
 
 // OrgSpecificOrdererEndpoints allows for individual orderer orgs to specify their external addresses for their OSNs.
 func (cp *ChannelProvider) OrgSpecificOrdererEndpoints() bool {
-	return cp.v142 || cp.v143
+	return cp.v142 || cp.v143 || cp.v145
+}
+
+// AdaptiveBatchCutting allows the orderer config to select how blocks are cut, which
+// both orderers and peers must understand to process the config.
+func (cp *ChannelProvider) AdaptiveBatchCutting() bool {
+	return cp.v145
 }
@@ -88,6 +88,28 @@ func TestChannelV143(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
 	assert.True(t, cp.ConsensusTypeMigration())
 	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.AdaptiveBatchCutting())
+}
+
+func TestChannelV145(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV1_4_3: {},
+		ChannelV1_4_5: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
+	assert.True(t, cp.ConsensusTypeMigration())
+	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.True(t, cp.AdaptiveBatchCutting())
+
+	cp = NewChannelProvider(map[string]*cb.Capability{
+		ChannelV1_4_5: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
+	assert.True(t, cp.ConsensusTypeMigration())
+	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.True(t, cp.AdaptiveBatchCutting())
 }
 
 func TestChannelNotSuported(t *testing.T) {
@@ -132,6 +132,13 @@ func SignedByMspAdmin(mspId string) *cb.SignaturePolicyEnvelope {
 
 //wrapper for generating "any of a given role" type policies
 func signedByAnyOfGivenRole(role msp.MSPRole_MSPRoleType, ids []string) *cb.SignaturePolicyEnvelope {
+	return SignedByNOutOfGivenRole(1, role, ids)
+}
+
+// SignedByNOutOfGivenRole returns a policy that requires N valid
+// signatures from entities, having the passed role, of distinct orgs
+// whose ids are listed in the supplied string array
+func SignedByNOutOfGivenRole(n int32, role msp.MSPRole_MSPRoleType, ids []string) *cb.SignaturePolicyEnvelope {
 	// we create an array of principals, one principal
 	// per application MSP defined on this chain
 	sort.Strings(ids)
@@ -144,10 +151,10 @@ func signedByAnyOfGivenRole(role msp.MSPRole_MSPRoleType, ids []string) *cb.Sign
 		sigspolicy[i] = SignedBy(int32(i))
 	}
 
-	// create the policy: it requires exactly 1 signature from any of the principals
+	// create the policy: it requires exactly n signatures from any of the principals
 	p := &cb.SignaturePolicyEnvelope{
 		Version:    0,
-		Rule:       NOutOf(1, sigspolicy),
+		Rule:       NOutOf(n, sigspolicy),
 		Identities: principals,
 	}
 
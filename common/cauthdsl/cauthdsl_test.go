@@ -337,6 +337,22 @@ func TestSignedByMspPeer(t *testing.T) {
 	assert.Equal(t, role.Role, mb.MSPRole_PEER)
 }
 
+func TestSignedByNOutOfGivenRole(t *testing.T) {
+	e := SignedByNOutOfGivenRole(2, mb.MSPRole_MEMBER, []string{"C", "A", "B"})
+	assert.Equal(t, 3, len(e.Identities))
+	assert.Equal(t, int32(2), e.Rule.GetNOutOf().N)
+	assert.Equal(t, 3, len(e.Rule.GetNOutOf().Rules))
+
+	for i, mspID := range []string{"A", "B", "C"} {
+		role := &mb.MSPRole{}
+		err := proto.Unmarshal(e.Identities[i].Principal, role)
+		assert.NoError(t, err)
+
+		assert.Equal(t, role.MspIdentifier, mspID)
+		assert.Equal(t, role.Role, mb.MSPRole_MEMBER)
+	}
+}
+
 func TestReturnNil(t *testing.T) {
 	policy := Envelope(And(SignedBy(-1), SignedBy(-2)), signers)
 
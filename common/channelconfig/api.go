@@ -103,6 +103,12 @@ type Orderer interface {
 	// BatchTimeout returns the amount of time to wait before creating a batch
 	BatchTimeout() time.Duration
 
+	// BatchCuttingMode returns whether blocks are cut statically or adaptively
+	BatchCuttingMode() ab.BatchCutting_Mode
+
+	// TargetLatency returns the latency adaptive block cutting aims for
+	TargetLatency() time.Duration
+
 	// MaxChannelsCount returns the maximum count of channels to allow for an ordering network
 	MaxChannelsCount() uint64
 
@@ -132,6 +138,9 @@ type ChannelCapabilities interface {
 
 	// OrgSpecificOrdererEndpoints return true if the channel config processing allows orderer orgs to specify their own endpoints
 	OrgSpecificOrdererEndpoints() bool
+
+	// AdaptiveBatchCutting return true if the channel config processing allows the orderer config to select how blocks are cut
+	AdaptiveBatchCutting() bool
 }
 
 // ApplicationCapabilities defines the capabilities for the application portion of a channel
@@ -184,6 +193,10 @@ type ApplicationCapabilities interface {
 
 	// FabToken returns true if this channel supports FabToken functions
 	FabToken() bool
+
+	// LifecycleApproval returns true if the organizations of this channel may approve chaincode
+	// definitions, which are committed through the lifecycle system chaincode
+	LifecycleApproval() bool
 }
 
 // OrdererCapabilities defines the capabilities for the orderer portion of a channel
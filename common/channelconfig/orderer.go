@@ -34,6 +34,9 @@ const (
 	// BatchTimeoutKey is the cb.ConfigItem type key name for the BatchTimeout message.
 	BatchTimeoutKey = "BatchTimeout"
 
+	// BatchCuttingKey is the cb.ConfigItem type key name for the BatchCutting message.
+	BatchCuttingKey = "BatchCutting"
+
 	// ChannelRestrictionsKey is the key name for the ChannelRestrictions message.
 	ChannelRestrictionsKey = "ChannelRestrictions"
 
@@ -49,6 +52,7 @@ type OrdererProtos struct {
 	ConsensusType       *ab.ConsensusType
 	BatchSize           *ab.BatchSize
 	BatchTimeout        *ab.BatchTimeout
+	BatchCutting        *ab.BatchCutting
 	KafkaBrokers        *ab.KafkaBrokers
 	ChannelRestrictions *ab.ChannelRestrictions
 	Capabilities        *cb.Capabilities
@@ -59,7 +63,8 @@ type OrdererConfig struct {
 	protos *OrdererProtos
 	orgs   map[string]OrdererOrg
 
-	batchTimeout time.Duration
+	batchTimeout  time.Duration
+	targetLatency time.Duration
 }
 
 // OrdererOrgProtos are deserialized from the Orderer org config values
@@ -130,6 +135,12 @@ func NewOrdererConfig(ordererGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler,
 		return nil, errors.Wrap(err, "failed to deserialize values")
 	}
 
+	if !channelCapabilities.AdaptiveBatchCutting() {
+		if _, ok := ordererGroup.Values[BatchCuttingKey]; ok {
+			return nil, errors.New("Orderer config cannot contain batch cutting value until V1_4_5+ capabilities have been enabled")
+		}
+	}
+
 	if err := oc.Validate(); err != nil {
 		return nil, err
 	}
@@ -168,6 +179,16 @@ func (oc *OrdererConfig) BatchTimeout() time.Duration {
 	return oc.batchTimeout
 }
 
+// BatchCuttingMode returns whether blocks are cut statically or adaptively.
+func (oc *OrdererConfig) BatchCuttingMode() ab.BatchCutting_Mode {
+	return oc.protos.BatchCutting.Mode
+}
+
+// TargetLatency returns the latency adaptive block cutting aims for.
+func (oc *OrdererConfig) TargetLatency() time.Duration {
+	return oc.targetLatency
+}
+
 // KafkaBrokers returns the addresses (IP:port notation) of a set of "bootstrap"
 // Kafka brokers, i.e. this is not necessarily the entire set of Kafka brokers
 // used for ordering.
@@ -194,6 +215,7 @@ func (oc *OrdererConfig) Validate() error {
 	for _, validator := range []func() error{
 		oc.validateBatchSize,
 		oc.validateBatchTimeout,
+		oc.validateBatchCutting,
 		oc.validateKafkaBrokers,
 	} {
 		if err := validator(); err != nil {
@@ -232,6 +254,31 @@ func (oc *OrdererConfig) validateBatchTimeout() error {
 	return nil
 }
 
+func (oc *OrdererConfig) validateBatchCutting() error {
+	switch oc.protos.BatchCutting.Mode {
+	case ab.BatchCutting_STATIC:
+		return nil
+	case ab.BatchCutting_ADAPTIVE:
+		// Kafka based orderers cut blocks independently from the same stream of
+		// messages, and must cut them at the same messages.
+		if oc.protos.ConsensusType.Type == "kafka" {
+			return fmt.Errorf("Attempted to set the batch cutting mode to adaptive, which is not supported by kafka")
+		}
+	default:
+		return fmt.Errorf("Attempted to set the batch cutting mode to an invalid value: %d", oc.protos.BatchCutting.Mode)
+	}
+
+	var err error
+	oc.targetLatency, err = time.ParseDuration(oc.protos.BatchCutting.TargetLatency)
+	if err != nil {
+		return fmt.Errorf("Attempted to set the target latency to a invalid value: %s", err)
+	}
+	if oc.targetLatency <= 0 {
+		return fmt.Errorf("Attempted to set the target latency to a non-positive value: %s", oc.targetLatency)
+	}
+	return nil
+}
+
 func (oc *OrdererConfig) validateKafkaBrokers() error {
 	for _, broker := range oc.protos.KafkaBrokers.Brokers {
 		if !brokerEntrySeemsValid(broker) {
@@ -8,6 +8,7 @@ package channelconfig
 
 import (
 	"testing"
+	"time"
 
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,30 @@ func TestBatchTimeout(t *testing.T) {
 	assert.Error(t, oc.validateBatchTimeout(), "Zero batch timeout")
 }
 
+func TestBatchCutting(t *testing.T) {
+	raft := &ab.ConsensusType{Type: "etcdraft"}
+
+	oc := &OrdererConfig{protos: &OrdererProtos{ConsensusType: raft, BatchCutting: &ab.BatchCutting{}}}
+	assert.NoError(t, oc.validateBatchCutting(), "Static batch cutting")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{ConsensusType: raft, BatchCutting: &ab.BatchCutting{Mode: ab.BatchCutting_ADAPTIVE, TargetLatency: "500ms"}}}
+	assert.NoError(t, oc.validateBatchCutting(), "Valid target latency")
+	assert.Equal(t, ab.BatchCutting_ADAPTIVE, oc.BatchCuttingMode())
+	assert.Equal(t, 500*time.Millisecond, oc.TargetLatency())
+
+	oc = &OrdererConfig{protos: &OrdererProtos{ConsensusType: raft, BatchCutting: &ab.BatchCutting{Mode: ab.BatchCutting_ADAPTIVE}}}
+	assert.Error(t, oc.validateBatchCutting(), "Missing target latency")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{ConsensusType: raft, BatchCutting: &ab.BatchCutting{Mode: ab.BatchCutting_ADAPTIVE, TargetLatency: "0s"}}}
+	assert.Error(t, oc.validateBatchCutting(), "Zero target latency")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{ConsensusType: raft, BatchCutting: &ab.BatchCutting{Mode: 2, TargetLatency: "1s"}}}
+	assert.Error(t, oc.validateBatchCutting(), "Unknown batch cutting mode")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "kafka"}, BatchCutting: &ab.BatchCutting{Mode: ab.BatchCutting_ADAPTIVE, TargetLatency: "1s"}}}
+	assert.Error(t, oc.validateBatchCutting(), "Adaptive batch cutting with kafka")
+}
+
 func TestKafkaBrokers(t *testing.T) {
 	oc := &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1:9092", "foo.bar:9092"}}}}
 	assert.NoError(t, oc.validateKafkaBrokers(), "Valid kafka brokers")
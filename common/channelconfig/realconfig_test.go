@@ -8,11 +8,13 @@ package channelconfig_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -82,3 +84,31 @@ func TestOrgSpecificOrdererEndpoints(t *testing.T) {
 		assert.NotEmpty(t, cc.OrdererConfig().Organizations()["SampleOrg"].Endpoints)
 	})
 }
+
+func TestBatchCuttingCapability(t *testing.T) {
+	t.Run("Without_Capability", func(t *testing.T) {
+		conf := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
+		conf.Capabilities = map[string]bool{"V1_4_3": true}
+		conf.Orderer.BatchCutting = genesisconfig.BatchCutting{Mode: "adaptive", TargetLatency: time.Second}
+
+		cg, err := encoder.NewChannelGroup(conf)
+		assert.NoError(t, err)
+
+		_, err = channelconfig.NewChannelConfig(cg)
+		assert.EqualError(t, err, "could not create channel Orderer sub-group config: Orderer config cannot contain batch cutting value until V1_4_5+ capabilities have been enabled")
+	})
+
+	t.Run("With_Capability", func(t *testing.T) {
+		conf := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
+		conf.Capabilities = map[string]bool{"V1_4_5": true}
+		conf.Orderer.BatchCutting = genesisconfig.BatchCutting{Mode: "adaptive", TargetLatency: time.Second}
+
+		cg, err := encoder.NewChannelGroup(conf)
+		assert.NoError(t, err)
+
+		cc, err := channelconfig.NewChannelConfig(cg)
+		assert.NoError(t, err)
+		assert.Equal(t, ab.BatchCutting_ADAPTIVE, cc.OrdererConfig().BatchCuttingMode())
+		assert.Equal(t, time.Second, cc.OrdererConfig().TargetLatency())
+	})
+}
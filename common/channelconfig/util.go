@@ -137,6 +137,18 @@ func BatchTimeoutValue(timeout string) *StandardConfigValue {
 	}
 }
 
+// BatchCuttingValue returns the config definition for the orderer batch cutting mode.
+// It is a value for the /Channel/Orderer group.
+func BatchCuttingValue(mode ab.BatchCutting_Mode, targetLatency string) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: BatchCuttingKey,
+		value: &ab.BatchCutting{
+			Mode:          mode,
+			TargetLatency: targetLatency,
+		},
+	}
+}
+
 // ChannelRestrictionsValue returns the config definition for the orderer channel restrictions.
 // It is a value for the /Channel/Orderer group.
 func ChannelRestrictionsValue(maxChannelCount uint64) *StandardConfigValue {
@@ -11,6 +11,7 @@ import (
 
 	cb "github.com/hyperledger/fabric/protos/common"
 	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,6 +36,7 @@ func TestUtilsBasic(t *testing.T) {
 	basicTest(t, ConsensusTypeValue("foo", []byte("bar")))
 	basicTest(t, BatchSizeValue(1, 2, 3))
 	basicTest(t, BatchTimeoutValue("1s"))
+	basicTest(t, BatchCuttingValue(ab.BatchCutting_ADAPTIVE, "500ms"))
 	basicTest(t, ChannelRestrictionsValue(7))
 	basicTest(t, KafkaBrokersValue([]string{"foo:1", "bar:2"}))
 	basicTest(t, MSPValue(&mspprotos.MSPConfig{}))
@@ -59,37 +59,37 @@ func TestValidChannelID(t *testing.T) {
 	rejectMsg := "Should have rejected invalid channel ID"
 
 	t.Run("ZeroLength", func(t *testing.T) {
-		if err := validateChannelID(""); err == nil {
+		if err := ValidateChannelID(""); err == nil {
 			t.Fatal(rejectMsg)
 		}
 	})
 
 	t.Run("LongerThanMaxAllowed", func(t *testing.T) {
-		if err := validateChannelID(randomLowerAlphaString(maxLength + 1)); err == nil {
+		if err := ValidateChannelID(randomLowerAlphaString(maxLength + 1)); err == nil {
 			t.Fatal(rejectMsg)
 		}
 	})
 
 	t.Run("ContainsIllegalCharacter", func(t *testing.T) {
-		if err := validateChannelID("foo_bar"); err == nil {
+		if err := ValidateChannelID("foo_bar"); err == nil {
 			t.Fatal(rejectMsg)
 		}
 	})
 
 	t.Run("StartsWithNumber", func(t *testing.T) {
-		if err := validateChannelID("8foo"); err == nil {
+		if err := ValidateChannelID("8foo"); err == nil {
 			t.Fatal(rejectMsg)
 		}
 	})
 
 	t.Run("StartsWithDot", func(t *testing.T) {
-		if err := validateChannelID(".foo"); err == nil {
+		if err := ValidateChannelID(".foo"); err == nil {
 			t.Fatal(rejectMsg)
 		}
 	})
 
 	t.Run("ValidName", func(t *testing.T) {
-		if err := validateChannelID("f-oo.bar"); err != nil {
+		if err := ValidateChannelID("f-oo.bar"); err != nil {
 			t.Fatal(acceptMsg)
 		}
 	})
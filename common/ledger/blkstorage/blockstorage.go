@@ -38,14 +38,66 @@ var (
 
 	// ErrAttrNotIndexed is used to indicate that an attribute is not indexed
 	ErrAttrNotIndexed = errors.New("attribute not indexed")
+
+	// ErrNotAvailableBeforeSnapshot is used to indicate that the requested block or transaction was committed
+	// prior to the snapshot from which the block store was bootstrapped and hence it is not present in the block store
+	ErrNotAvailableBeforeSnapshot = errors.New("data not available, as it precedes the snapshot from which the block store was bootstrapped")
+
+	// ErrBlockArchived is used to indicate that the requested block or transaction is present in a block file
+	// that has been moved to the block archive and the block store is not configured to read from the archive
+	ErrBlockArchived = errors.New("data not available, as it has been moved to the block archive")
 )
 
+// SnapshotInfo encapsulates the blocks that a block store retains when it is bootstrapped from a snapshot.
+// LastBlock is the last block covered by the snapshot and LastConfigBlock is the most recent config block
+// at the time of the snapshot (possibly the same as LastBlock)
+type SnapshotInfo struct {
+	LastBlock       *common.Block
+	LastConfigBlock *common.Block
+}
+
+// TxIDInfo encloses a transaction id and the validation code of the corresponding transaction
+type TxIDInfo struct {
+	TxID           string
+	ValidationCode peer.TxValidationCode
+}
+
+// TxIDInfoIterator supplies the transaction ids that are imported into a block store during
+// the bootstrap from a snapshot. Next returns nil when the iterator is exhausted
+type TxIDInfoIterator interface {
+	Next() (*TxIDInfo, error)
+}
+
+const (
+	// SeverityError marks an inconsistency that the peer cannot recover from on its own
+	SeverityError = "error"
+	// SeverityWarning marks an inconsistency that the peer resolves at the next start, e.g., a lagging index
+	SeverityWarning = "warning"
+)
+
+// Inconsistency describes a discrepancy detected during the offline verification of a ledger.
+// Component names the store in which the discrepancy is detected and TxNum is set only if
+// the discrepancy pertains to a specific transaction of the block
+type Inconsistency struct {
+	Component   string  `json:"component"`
+	Severity    string  `json:"severity"`
+	BlockNum    uint64  `json:"blockNum"`
+	TxNum       *uint64 `json:"txNum,omitempty"`
+	Description string  `json:"description"`
+}
+
 // BlockStoreProvider provides an handle to a BlockStore
 type BlockStoreProvider interface {
 	CreateBlockStore(ledgerid string) (BlockStore, error)
 	OpenBlockStore(ledgerid string) (BlockStore, error)
+	// BootstrapFromSnapshot creates a block store that starts right after the last block covered by
+	// the snapshot. The transaction ids supplied by txIDs are indexed so that a duplicate txid can be
+	// detected and the validation code of a pre-snapshot transaction can be queried
+	BootstrapFromSnapshot(ledgerid string, snapshotInfo *SnapshotInfo, txIDs TxIDInfoIterator) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
+	// Remove removes the block store of the given ledger. The block store must not be in use
+	Remove(ledgerid string) error
 	Close()
 }
 
@@ -62,5 +114,8 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// ExportTxIDs invokes the function `handle` for each of the transaction ids present in the index,
+	// in the order of the txids
+	ExportTxIDs(handle func(*TxIDInfo) error) error
 	Shutdown()
 }
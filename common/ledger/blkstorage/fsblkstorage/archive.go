@@ -0,0 +1,405 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+var (
+	archiveInfoKey = []byte("archiveInfo")
+	// archiveCheckInterval is the interval at which the block files are checked against the age criterion,
+	// in addition to the checks that are triggered by the block commits
+	archiveCheckInterval = time.Minute
+)
+
+// Archive is the cold storage to which the older block files are moved. The interface resembles an object
+// store in which a block file is stored as an object and the blocks are read via ranged reads on the object
+type Archive interface {
+	// Put stores the content of the named block file of the ledger. An existing file with the same name is replaced
+	Put(ledgerID, fileName string, content io.Reader) error
+	// GetRange returns the bytes of the named block file of the ledger starting at the given offset.
+	// Fewer than `length` bytes are returned only if the file ends before
+	GetRange(ledgerID, fileName string, offset int64, length int) ([]byte, error)
+}
+
+// fsArchive implements the interface Archive over a local directory,
+// for instance, a mount of a network file system or of a cheaper disk
+type fsArchive struct {
+	dir string
+}
+
+// NewFSArchive returns an Archive that stores the block files of each of the ledgers in a sub-directory of the given directory
+func NewFSArchive(dir string) Archive {
+	return &fsArchive{dir: dir}
+}
+
+func (a *fsArchive) Put(ledgerID, fileName string, content io.Reader) error {
+	ledgerDir := filepath.Join(a.dir, ledgerID)
+	if _, err := util.CreateDirIfMissing(ledgerDir); err != nil {
+		return errors.Wrapf(err, "error creating the archive dir [%s]", ledgerDir)
+	}
+	filePath := filepath.Join(ledgerDir, fileName)
+	tmpFilePath := filePath + ".tmp"
+	file, err := os.OpenFile(tmpFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return errors.Wrapf(err, "error creating the file [%s] in the archive", tmpFilePath)
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "error writing the file [%s] in the archive", tmpFilePath)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "error syncing the file [%s] in the archive", tmpFilePath)
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrapf(err, "error closing the file [%s] in the archive", tmpFilePath)
+	}
+	return errors.Wrapf(os.Rename(tmpFilePath, filePath), "error renaming the file [%s] in the archive", tmpFilePath)
+}
+
+func (a *fsArchive) GetRange(ledgerID, fileName string, offset int64, length int) ([]byte, error) {
+	filePath := filepath.Join(a.dir, ledgerID, fileName)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening the file [%s] in the archive", filePath)
+	}
+	defer file.Close()
+	b := make([]byte, length)
+	n, err := file.ReadAt(b, offset)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrapf(err, "error reading the file [%s] in the archive for offset %d and length %d", filePath, offset, length)
+	}
+	return b[:n], nil
+}
+
+// archiveInfo records the block files that have been moved to the archive. The block files are always
+// archived in the order of the files and hence, the archived files and blocks form a prefix of the block store
+type archiveInfo struct {
+	// numArchivedFiles is the number of the block files archived, i.e., the files with a lower suffix are archived
+	numArchivedFiles int
+	// archivedBlocksHeight is the number of the first block that is not archived
+	archivedBlocksHeight uint64
+}
+
+func (i *archiveInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	if err := buffer.EncodeVarint(uint64(i.numArchivedFiles)); err != nil {
+		return nil, errors.Wrapf(err, "error encoding the numArchivedFiles [%d]", i.numArchivedFiles)
+	}
+	if err := buffer.EncodeVarint(i.archivedBlocksHeight); err != nil {
+		return nil, errors.Wrapf(err, "error encoding the archivedBlocksHeight [%d]", i.archivedBlocksHeight)
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *archiveInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	val, err := buffer.DecodeVarint()
+	if err != nil {
+		return err
+	}
+	i.numArchivedFiles = int(val)
+	if i.archivedBlocksHeight, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *archiveInfo) String() string {
+	return fmt.Sprintf("numArchivedFiles=[%d], archivedBlocksHeight=[%d]", i.numArchivedFiles, i.archivedBlocksHeight)
+}
+
+// getArchiveInfo returns the information about the archived block files. An empty archiveInfo is
+// returned if no block file has been archived
+func (index *blockIndex) getArchiveInfo() (*archiveInfo, error) {
+	return loadArchiveInfo(index.db)
+}
+
+// recordArchivedFiles records in the index that the blocks below the `archivedBlocksHeight` have been archived
+func (index *blockIndex) recordArchivedFiles(info *archiveInfo) error {
+	b, err := info.marshal()
+	if err != nil {
+		return err
+	}
+	return index.db.Put(archiveInfoKey, b, true)
+}
+
+func loadArchiveInfo(db *leveldbhelper.DBHandle) (*archiveInfo, error) {
+	b, err := db.Get(archiveInfoKey)
+	if err != nil {
+		return nil, err
+	}
+	info := &archiveInfo{}
+	if b == nil {
+		return info, nil
+	}
+	if err := info.unmarshal(b); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling the archive info")
+	}
+	return info, nil
+}
+
+// blockArchiver moves the eligible block files of a ledger to the archive in the background.
+// A check is triggered after each block commit and periodically, for the age based criterion
+type blockArchiver struct {
+	mgr       *blockfileMgr
+	conf      *ArchiveConf
+	archiving sync.Mutex
+	trigger   chan struct{}
+	stop      chan struct{}
+	done      sync.WaitGroup
+}
+
+func newBlockArchiver(mgr *blockfileMgr, conf *ArchiveConf) *blockArchiver {
+	a := &blockArchiver{
+		mgr:     mgr,
+		conf:    conf,
+		trigger: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+	a.done.Add(1)
+	go a.run()
+	a.notify()
+	return a
+}
+
+func (a *blockArchiver) run() {
+	defer a.done.Done()
+	ticker := time.NewTicker(archiveCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-a.trigger:
+		case <-ticker.C:
+		}
+		if err := a.archive(); err != nil {
+			logger.Errorf("Error while archiving the block files of the ledger [%s]: %s", a.mgr.ledgerID, err)
+		}
+	}
+}
+
+// archive moves the eligible block files to the archive. Only one archiving is in progress at a time
+func (a *blockArchiver) archive() error {
+	a.archiving.Lock()
+	defer a.archiving.Unlock()
+	return a.mgr.archiveEligibleFiles(a.conf)
+}
+
+// notify triggers a check for the eligible block files without blocking the caller
+func (a *blockArchiver) notify() {
+	select {
+	case a.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (a *blockArchiver) close() {
+	close(a.stop)
+	a.done.Wait()
+}
+
+// loadArchiveInfo loads the information about the archived block files and removes
+// the local copies of the archived files that may be left behind by a crash
+func (mgr *blockfileMgr) loadArchiveInfo() error {
+	info, err := mgr.index.getArchiveInfo()
+	if err != nil {
+		return err
+	}
+	for fileNum := 0; fileNum < info.numArchivedFiles; fileNum++ {
+		filePath := deriveBlockfilePath(mgr.rootDir, fileNum)
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error removing the archived block file [%s]", filePath)
+		}
+	}
+	mgr.archiveInfo = info
+	return nil
+}
+
+// archiveEligibleFiles moves the block files that meet the archive criteria to the archive, in the order of the files
+func (mgr *blockfileMgr) archiveEligibleFiles(conf *ArchiveConf) error {
+	for {
+		mgr.archiveLock.RLock()
+		fileNum := mgr.archiveInfo.numArchivedFiles
+		mgr.archiveLock.RUnlock()
+
+		eligible, archivedBlocksHeight, err := mgr.isEligibleForArchive(fileNum, conf)
+		if err != nil || !eligible {
+			return err
+		}
+		if err := mgr.archiveFile(fileNum, archivedBlocksHeight, conf.Archive); err != nil {
+			return err
+		}
+	}
+}
+
+// isEligibleForArchive checks the archive criteria for the given block file and, if the file is eligible,
+// returns the number of the first block in the next file. The file currently being appended to and the file
+// preceding it are never archived, as the block store reconstructs its checkpoint from these two files
+func (mgr *blockfileMgr) isEligibleForArchive(fileNum int, conf *ArchiveConf) (bool, uint64, error) {
+	mgr.cpInfoCond.L.Lock()
+	latestFileNum := mgr.cpInfo.latestFileChunkSuffixNum
+	mgr.cpInfoCond.L.Unlock()
+	if fileNum >= latestFileNum-1 {
+		return false, 0, nil
+	}
+
+	nextFileStream, err := newBlockfileStream(mgr.rootDir, fileNum+1, 0)
+	if err != nil {
+		return false, 0, err
+	}
+	defer nextFileStream.close()
+	blockBytes, err := nextFileStream.nextBlockBytes()
+	if err != nil || blockBytes == nil {
+		return false, 0, err
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return false, 0, err
+	}
+	nextFileFirstBlockNum := info.blockHeader.Number
+
+	height := mgr.getBlockchainInfo().Height
+	if conf.RetainBlocks > 0 && height >= conf.RetainBlocks && nextFileFirstBlockNum <= height-conf.RetainBlocks {
+		return true, nextFileFirstBlockNum, nil
+	}
+	if conf.MaxAge > 0 {
+		fileInfo, err := os.Stat(deriveBlockfilePath(mgr.rootDir, fileNum))
+		if err != nil {
+			return false, 0, errors.Wrapf(err, "error reading the attributes of the block file [%d]", fileNum)
+		}
+		if time.Since(fileInfo.ModTime()) > conf.MaxAge {
+			return true, nextFileFirstBlockNum, nil
+		}
+	}
+	return false, 0, nil
+}
+
+// archiveFile copies the block file to the archive, records it in the index and then removes the local file.
+// The readers of the block files hold the archiveLock while reading a local file and hence, the file is
+// removed only after the ongoing reads finish
+func (mgr *blockfileMgr) archiveFile(fileNum int, archivedBlocksHeight uint64, archive Archive) error {
+	filePath := deriveBlockfilePath(mgr.rootDir, fileNum)
+	logger.Infof("Archiving the block file [%s] of the ledger [%s]", filePath, mgr.ledgerID)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "error opening the block file [%s]", filePath)
+	}
+	err = archive.Put(mgr.ledgerID, blockfileName(fileNum), file)
+	file.Close()
+	if err != nil {
+		return errors.WithMessage(err, "error moving the block file to the archive")
+	}
+
+	info := &archiveInfo{numArchivedFiles: fileNum + 1, archivedBlocksHeight: archivedBlocksHeight}
+	mgr.archiveLock.Lock()
+	defer mgr.archiveLock.Unlock()
+	if err := mgr.index.recordArchivedFiles(info); err != nil {
+		return err
+	}
+	mgr.archiveInfo = info
+	if err := os.Remove(filePath); err != nil {
+		// the file is removed again when the block store is opened next time
+		logger.Warningf("Error removing the archived block file [%s]: %s", filePath, err)
+	}
+	logger.Infof("Archived the block file [%s] of the ledger [%s]. Blocks below [%d] are archived", filePath, mgr.ledgerID, archivedBlocksHeight)
+	return nil
+}
+
+// isBlockArchived returns true if the given block is present in a block file that has been archived
+func (mgr *blockfileMgr) isBlockArchived(blockNum uint64) bool {
+	mgr.archiveLock.RLock()
+	defer mgr.archiveLock.RUnlock()
+	return blockNum < mgr.archiveInfo.archivedBlocksHeight
+}
+
+// canFetchArchived returns true if the blocks present in the archived block files are served from the archive
+func (mgr *blockfileMgr) canFetchArchived() bool {
+	archiveConf := mgr.conf.archiveConf
+	return archiveConf != nil && archiveConf.Archive != nil && archiveConf.FetchArchived
+}
+
+// fetchArchivedBlockBytes reads the block from the archive. The caller is expected to hold the archiveLock.
+// The length of the block is read first from the varint that precedes the block bytes in the file
+func (mgr *blockfileMgr) fetchArchivedBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	if !mgr.canFetchArchived() {
+		return nil, errors.Wrapf(blkstorage.ErrBlockArchived, "cannot read the block file [%d]", lp.fileSuffixNum)
+	}
+	archive, fileName := mgr.conf.archiveConf.Archive, blockfileName(lp.fileSuffixNum)
+	b, err := archive.GetRange(mgr.ledgerID, fileName, int64(lp.offset), binary.MaxVarintLen64)
+	if err != nil {
+		return nil, err
+	}
+	length, n := proto.DecodeVarint(b)
+	if n == 0 {
+		return nil, errors.Errorf("error decoding the length of the block at offset [%d] in the archived block file [%s]", lp.offset, fileName)
+	}
+	return mgr.readArchivedFile(fileName, int64(lp.offset+n), int(length))
+}
+
+// fetchArchivedRawBytes reads the bytes from the archive. The caller is expected to hold the archiveLock
+func (mgr *blockfileMgr) fetchArchivedRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if !mgr.canFetchArchived() {
+		return nil, errors.Wrapf(blkstorage.ErrBlockArchived, "cannot read the block file [%d]", lp.fileSuffixNum)
+	}
+	return mgr.readArchivedFile(blockfileName(lp.fileSuffixNum), int64(lp.offset), lp.bytesLength)
+}
+
+func (mgr *blockfileMgr) readArchivedFile(fileName string, offset int64, length int) ([]byte, error) {
+	b, err := mgr.conf.archiveConf.Archive.GetRange(mgr.ledgerID, fileName, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != length {
+		return nil, errors.Errorf("unexpected end of the archived block file [%s], read [%d] bytes at offset [%d] instead of [%d]",
+			fileName, len(b), offset, length)
+	}
+	return b, nil
+}
+
+// assertNoBlockFilesArchived returns an error if any of the ledgers has block files moved to the archive
+func assertNoBlockFilesArchived(conf *Conf) error {
+	chainsDirExists, err := pathExists(conf.getChainsDir())
+	if err != nil || !chainsDirExists {
+		return err
+	}
+	indexDirExists, err := pathExists(conf.getIndexDir())
+	if err != nil || !indexDirExists {
+		return err
+	}
+	ledgerIDs, err := util.ListSubdirs(conf.getChainsDir())
+	if err != nil {
+		return err
+	}
+	p := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir()})
+	defer p.Close()
+	for _, ledgerID := range ledgerIDs {
+		info, err := loadArchiveInfo(p.GetDBHandle(ledgerID))
+		if err != nil {
+			return err
+		}
+		if info.numArchivedFiles > 0 {
+			return errors.Errorf("the ledger [%s] has block files moved to the archive", ledgerID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveBlockFiles(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	blockStorageDir, archiveDir := testPath(), testPath()
+	defer os.RemoveAll(archiveDir)
+	maxFileSize := testutilBlocksSize(t, blocks[:10])
+	archive := NewFSArchive(archiveDir)
+	env := newTestEnv(t, NewConfWithArchive(blockStorageDir, maxFileSize,
+		&ArchiveConf{Archive: archive, RetainBlocks: 50, FetchArchived: true}))
+	defer env.Cleanup()
+
+	w := newTestBlockfileWrapper(env, "testLedger")
+	w.addBlocks(blocks)
+	require.NoError(t, w.blockfileMgr.archiver.archive())
+	info := w.blockfileMgr.archiveInfo
+	assert.True(t, info.numArchivedFiles > 0)
+	assert.True(t, info.archivedBlocksHeight > 0 && info.archivedBlocksHeight <= 50)
+	assert.True(t, w.blockfileMgr.isBlockArchived(0))
+	assert.False(t, w.blockfileMgr.isBlockArchived(50))
+	for fileNum := 0; fileNum < info.numArchivedFiles; fileNum++ {
+		_, err := os.Stat(deriveBlockfilePath(w.blockfileMgr.rootDir, fileNum))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(archiveDir, "testLedger", blockfileName(fileNum)))
+		assert.NoError(t, err)
+	}
+	_, err := os.Stat(deriveBlockfilePath(w.blockfileMgr.rootDir, info.numArchivedFiles))
+	assert.NoError(t, err)
+
+	// the archived blocks are read from the archive transparently
+	testutilVerifyBlocksRetrieval(t, w, blocks)
+	w.close()
+
+	// the archive info is persisted in the index
+	w = newTestBlockfileWrapper(env, "testLedger")
+	assert.Equal(t, info, w.blockfileMgr.archiveInfo)
+	testutilVerifyBlocksRetrieval(t, w, blocks)
+	w.close()
+	env.provider.Close()
+
+	// the archived blocks are not served if the block store is not configured to read from the archive
+	env = newTestEnv(t, NewConfWithArchive(blockStorageDir, maxFileSize, &ArchiveConf{Archive: archive}))
+	w = newTestBlockfileWrapper(env, "testLedger")
+	_, err = w.blockfileMgr.retrieveBlockByNumber(0)
+	assert.Equal(t, blkstorage.ErrBlockArchived, errors.Cause(err))
+	_, err = w.blockfileMgr.retrieveTransactionByID(testutilTxID(t, blocks[0], 0))
+	assert.Equal(t, blkstorage.ErrBlockArchived, errors.Cause(err))
+	_, err = w.blockfileMgr.retrieveBlocks(0)
+	assert.Equal(t, blkstorage.ErrBlockArchived, errors.Cause(err))
+	validationCode, err := w.blockfileMgr.retrieveTxValidationCodeByTxID(testutilTxID(t, blocks[0], 0))
+	assert.NoError(t, err)
+	assert.NotEqual(t, -1, int(validationCode))
+	block, err := w.blockfileMgr.retrieveBlockByNumber(99)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[99], block)
+	w.close()
+	env.provider.Close()
+
+	// the blocks cannot be rolled back to an archived block and the block store cannot be reset
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	err = Rollback(blockStorageDir, "testLedger", 2, indexConfig)
+	assert.Contains(t, err.Error(), "have been moved to the archive")
+	err = ResetBlockStore(blockStorageDir)
+	assert.EqualError(t, err, "cannot reset the block store: the ledger [testLedger] has block files moved to the archive")
+}
+
+func TestArchiveBlockFilesByAge(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 50)
+	archiveDir := testPath()
+	defer os.RemoveAll(archiveDir)
+	env := newTestEnv(t, NewConfWithArchive(testPath(), testutilBlocksSize(t, blocks[:10]),
+		&ArchiveConf{Archive: NewFSArchive(archiveDir), MaxAge: time.Nanosecond, FetchArchived: true}))
+	defer env.Cleanup()
+
+	w := newTestBlockfileWrapper(env, "testLedger")
+	defer w.close()
+	w.addBlocks(blocks)
+	require.NoError(t, w.blockfileMgr.archiver.archive())
+	// all the files except the current file and the file preceding it are archived
+	assert.Equal(t, w.blockfileMgr.cpInfo.latestFileChunkSuffixNum-1, w.blockfileMgr.archiveInfo.numArchivedFiles)
+	testutilVerifyBlocksRetrieval(t, w, blocks)
+}
+
+func TestNoArchiveWithinRetention(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 50)
+	archiveDir := testPath()
+	defer os.RemoveAll(archiveDir)
+	env := newTestEnv(t, NewConfWithArchive(testPath(), testutilBlocksSize(t, blocks[:10]),
+		&ArchiveConf{Archive: NewFSArchive(archiveDir), RetainBlocks: 100, MaxAge: time.Hour}))
+	defer env.Cleanup()
+
+	w := newTestBlockfileWrapper(env, "testLedger")
+	defer w.close()
+	w.addBlocks(blocks)
+	require.NoError(t, w.blockfileMgr.archiver.archive())
+	assert.Equal(t, &archiveInfo{}, w.blockfileMgr.archiveInfo)
+	w.testGetBlockByNumber(blocks, 0, nil)
+}
+
+func TestFSArchive(t *testing.T) {
+	archiveDir := testPath()
+	defer os.RemoveAll(archiveDir)
+	archive := NewFSArchive(archiveDir)
+
+	require.NoError(t, archive.Put("ledger1", "file1", bytes.NewReader([]byte("old-content"))))
+	require.NoError(t, archive.Put("ledger1", "file1", bytes.NewReader([]byte("0123456789"))))
+	b, err := archive.GetRange("ledger1", "file1", 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("234"), b)
+	b, err = archive.GetRange("ledger1", "file1", 8, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("89"), b)
+
+	_, err = archive.GetRange("ledger2", "file1", 0, 1)
+	assert.Contains(t, err.Error(), "error opening the file")
+}
+
+func TestArchiveInfoMarshaling(t *testing.T) {
+	info := &archiveInfo{numArchivedFiles: 3, archivedBlocksHeight: 1000}
+	b, err := info.marshal()
+	require.NoError(t, err)
+	unmarshaled := &archiveInfo{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	assert.Equal(t, info, unmarshaled)
+}
+
+func testutilVerifyBlocksRetrieval(t *testing.T, w *testBlockfileMgrWrapper, blocks []*common.Block) {
+	w.testGetBlockByNumber(blocks, 0, nil)
+	w.testGetBlockByHash(blocks, nil)
+	w.testGetBlockByTxID(blocks, nil)
+	envelope, err := w.blockfileMgr.retrieveTransactionByID(testutilTxID(t, blocks[1], 0))
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(utils.ExtractEnvelopeOrPanic(blocks[1], 0), envelope))
+
+	itr, err := w.blockfileMgr.retrieveBlocks(0)
+	require.NoError(t, err)
+	defer itr.Close()
+	for _, expectedBlock := range blocks {
+		block, err := itr.Next()
+		require.NoError(t, err)
+		assert.Equal(t, expectedBlock, block)
+	}
+}
+
+func testutilBlocksSize(t *testing.T, blocks []*common.Block) int {
+	size := 0
+	for _, block := range blocks {
+		blockBytes, _, err := serializeBlock(block)
+		require.NoError(t, err)
+		size += len(blockBytes) + len(proto.EncodeVarint(uint64(len(blockBytes))))
+	}
+	return size
+}
+
+func testutilTxID(t *testing.T, block *common.Block, txNum int) string {
+	txID, err := utils.GetOrComputeTxIDFromEnvelope(block.Data.Data[txNum])
+	require.NoError(t, err)
+	return txID
+}
@@ -36,6 +36,7 @@ var (
 )
 
 type blockfileMgr struct {
+	ledgerID          string
 	rootDir           string
 	conf              *Conf
 	db                *leveldbhelper.DBHandle
@@ -44,6 +45,14 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	// bootstrappingSnapshotInfo is non-nil only if the block store was bootstrapped from a snapshot
+	bootstrappingSnapshotInfo *bootstrappingSnapshotInfo
+	// archiveInfo records the block files moved to the archive. The archiveLock is held while reading
+	// a block file so that a file is not removed by the archiver during an ongoing read
+	archiveLock sync.RWMutex
+	archiveInfo *archiveInfo
+	// archiver is non-nil only if the archiving of the block files is configured
+	archiver *blockArchiver
 }
 
 /*
@@ -96,7 +105,10 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		panic(fmt.Sprintf("Error creating block storage root dir [%s]: %s", rootDir, err))
 	}
 	// Instantiate the manager, i.e. blockFileMgr structure
-	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
+	mgr := &blockfileMgr{ledgerID: id, rootDir: rootDir, conf: conf, db: indexStore}
+	if mgr.bootstrappingSnapshotInfo, err = loadBootstrappingSnapshotInfo(rootDir); err != nil {
+		panic(fmt.Sprintf("Could not load bootstrapping snapshot info: %s", err))
+	}
 
 	// cp = checkpointInfo, retrieve from the database the file suffix or number of where blocks were stored.
 	// It also retrieves the current size of that file and the last block number that was written to that file.
@@ -115,6 +127,11 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		logger.Debug(`Synching block information from block storage (if needed)`)
 		syncCPInfoFromFS(rootDir, cpInfo)
 	}
+	if cpInfo.isChainEmpty && mgr.bootstrappingSnapshotInfo != nil {
+		// no block has been added after the bootstrapping from the snapshot
+		cpInfo.isChainEmpty = false
+		cpInfo.lastBlockNumber = mgr.bootstrappingSnapshotInfo.lastBlockNum
+	}
 	err = mgr.saveCurrentInfo(cpInfo, true)
 	if err != nil {
 		panic(fmt.Sprintf("Could not save next block file info to db: %s", err))
@@ -136,6 +153,9 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	if mgr.index, err = newBlockIndex(indexConfig, indexStore); err != nil {
 		panic(fmt.Sprintf("error in block index: %s", err))
 	}
+	if err := mgr.loadArchiveInfo(); err != nil {
+		panic(fmt.Sprintf("Could not load the archive info: %s", err))
+	}
 
 	// Update the manager with the checkpoint info and the file writer
 	mgr.cpInfo = cpInfo
@@ -152,19 +172,30 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 
 	if !cpInfo.isChainEmpty {
 		//If start up is a restart of an existing storage, sync the index from block storage and update BlockchainInfo for external API's
-		mgr.syncIndex()
-		lastBlockHeader, err := mgr.retrieveBlockHeaderByNumber(cpInfo.lastBlockNumber)
-		if err != nil {
-			panic(fmt.Sprintf("Could not retrieve header of the last block form file: %s", err))
+		if err := mgr.syncIndex(); err != nil {
+			panic(fmt.Sprintf("Could not sync the index with the block files: %s", err))
+		}
+		var lastBlockHash, previousBlockHash []byte
+		if snapshotInfo := mgr.bootstrappingSnapshotInfo; snapshotInfo != nil && snapshotInfo.lastBlockNum == cpInfo.lastBlockNumber {
+			lastBlockHash = snapshotInfo.lastBlockHash
+			previousBlockHash = snapshotInfo.previousBlockHash
+		} else {
+			lastBlockHeader, err := mgr.retrieveBlockHeaderByNumber(cpInfo.lastBlockNumber)
+			if err != nil {
+				panic(fmt.Sprintf("Could not retrieve header of the last block form file: %s", err))
+			}
+			lastBlockHash = lastBlockHeader.Hash()
+			previousBlockHash = lastBlockHeader.PreviousHash
 		}
-		lastBlockHash := lastBlockHeader.Hash()
-		previousBlockHash := lastBlockHeader.PreviousHash
 		bcInfo = &common.BlockchainInfo{
 			Height:            cpInfo.lastBlockNumber + 1,
 			CurrentBlockHash:  lastBlockHash,
 			PreviousBlockHash: previousBlockHash}
 	}
 	mgr.bcInfo.Store(bcInfo)
+	if archiveConf := conf.archiveConf; archiveConf != nil && archiveConf.Archive != nil {
+		mgr.archiver = newBlockArchiver(mgr, archiveConf)
+	}
 	return mgr
 }
 
@@ -209,10 +240,17 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 }
 
 func deriveBlockfilePath(rootDir string, suffixNum int) string {
-	return rootDir + "/" + blockfilePrefix + fmt.Sprintf("%06d", suffixNum)
+	return rootDir + "/" + blockfileName(suffixNum)
+}
+
+func blockfileName(suffixNum int) string {
+	return blockfilePrefix + fmt.Sprintf("%06d", suffixNum)
 }
 
 func (mgr *blockfileMgr) close() {
+	if mgr.archiver != nil {
+		mgr.archiver.close()
+	}
 	mgr.currentFileWriter.close()
 }
 
@@ -322,6 +360,9 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//update the checkpoint info (for storage) and the blockchain info (for APIs) in the manager
 	mgr.updateCheckpoint(newCPInfo)
 	mgr.updateBlockchainInfo(blockHash, block)
+	if mgr.archiver != nil {
+		mgr.archiver.notify()
+	}
 	return nil
 }
 
@@ -345,12 +386,21 @@ func (mgr *blockfileMgr) syncIndex() error {
 	endFileNum := mgr.cpInfo.latestFileChunkSuffixNum
 	startingBlockNum := uint64(0)
 
-	//if the index stored in the db has value, update the index information with those values
-	if !indexEmpty {
-		if lastBlockIndexed == mgr.cpInfo.lastBlockNumber {
-			logger.Debug("Both the block files and indices are in sync.")
-			return nil
+	if !indexEmpty && lastBlockIndexed == mgr.cpInfo.lastBlockNumber {
+		logger.Debug("Both the block files and indices are in sync.")
+		return nil
+	}
+
+	snapshotInfo := mgr.bootstrappingSnapshotInfo
+	switch {
+	case snapshotInfo != nil && (indexEmpty || lastBlockIndexed == snapshotInfo.lastBlockNum):
+		// the block files begin with the block that follows the last block in the snapshot
+		if indexEmpty {
+			logger.Warnf("Index is empty for a block store bootstrapped from snapshot. The txids present in the snapshot are not available in the index")
 		}
+		startingBlockNum = snapshotInfo.lastBlockNum + 1
+	case !indexEmpty:
+		//if the index stored in the db has value, update the index information with those values
 		logger.Debugf("Last block indexed [%d], Last block present in block files [%d]", lastBlockIndexed, mgr.cpInfo.lastBlockNumber)
 		var flp *fileLocPointer
 		if flp, err = mgr.index.getBlockLocByBlockNum(lastBlockIndexed); err != nil {
@@ -360,7 +410,7 @@ func (mgr *blockfileMgr) syncIndex() error {
 		startOffset = flp.locPointer.offset
 		skipFirstBlock = true
 		startingBlockNum = lastBlockIndexed + 1
-	} else {
+	default:
 		logger.Debugf("No block indexed, Last block present in block files=[%d]", mgr.cpInfo.lastBlockNumber)
 	}
 
@@ -466,6 +516,10 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
 
+	if mgr.precedesBootstrappingSnapshot(blockNum) {
+		return mgr.bootstrappingSnapshotInfo.retrieveBlock(blockNum)
+	}
+
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -491,6 +545,13 @@ func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxVal
 
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
+	if mgr.precedesBootstrappingSnapshot(blockNum) {
+		block, err := mgr.bootstrappingSnapshotInfo.retrieveBlock(blockNum)
+		if err != nil {
+			return nil, err
+		}
+		return block.Header, nil
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -506,7 +567,21 @@ func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.B
 	return info.blockHeader, nil
 }
 
+// precedesBootstrappingSnapshot returns true if the block store was bootstrapped from a snapshot and
+// the given block is covered by the snapshot, i.e., the block is not present in the block files
+func (mgr *blockfileMgr) precedesBootstrappingSnapshot(blockNum uint64) bool {
+	return mgr.bootstrappingSnapshotInfo != nil && blockNum <= mgr.bootstrappingSnapshotInfo.lastBlockNum
+}
+
 func (mgr *blockfileMgr) retrieveBlocks(startNum uint64) (*blocksItr, error) {
+	if mgr.precedesBootstrappingSnapshot(startNum) {
+		return nil, errors.Wrapf(blkstorage.ErrNotAvailableBeforeSnapshot,
+			"cannot serve block [%d]. The ledger was bootstrapped from a snapshot with last block [%d]",
+			startNum, mgr.bootstrappingSnapshotInfo.lastBlockNum)
+	}
+	if mgr.isBlockArchived(startNum) && !mgr.canFetchArchived() {
+		return nil, errors.Wrapf(blkstorage.ErrBlockArchived, "cannot serve block [%d]", startNum)
+	}
 	return newBlockItr(mgr, startNum), nil
 }
 
@@ -552,6 +627,11 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	mgr.archiveLock.RLock()
+	defer mgr.archiveLock.RUnlock()
+	if lp.fileSuffixNum < mgr.archiveInfo.numArchivedFiles {
+		return mgr.fetchArchivedBlockBytes(lp)
+	}
 	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
 		return nil, err
@@ -565,6 +645,11 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	mgr.archiveLock.RLock()
+	defer mgr.archiveLock.RUnlock()
+	if lp.fileSuffixNum < mgr.archiveInfo.numArchivedFiles {
+		return mgr.fetchArchivedRawBytes(lp)
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
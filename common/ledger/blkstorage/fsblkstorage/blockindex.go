@@ -43,6 +43,9 @@ type index interface {
 	getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
 	isAttributeIndexed(attribute blkstorage.IndexableAttr) bool
+	exportTxIDs(handle func(*blkstorage.TxIDInfo) error) error
+	getArchiveInfo() (*archiveInfo, error)
+	recordArchivedFiles(info *archiveInfo) error
 }
 
 type blockIdxInfo struct {
@@ -192,7 +195,7 @@ func (index *blockIndex) markDuplicateTxids(blockIdxInfo *blockIdxInfo) error {
 		}
 
 		loc, err := index.getTxLoc(txid)
-		if loc != nil { // txid is duplicate of a previous tx in the index
+		if loc != nil || err == blkstorage.ErrNotAvailableBeforeSnapshot { // txid is duplicate of a previous tx in the index
 			txIdxInfo.isDuplicate = true
 			continue
 		}
@@ -247,6 +250,10 @@ func (index *blockIndex) getTxLoc(txID string) (*fileLocPointer, error) {
 	if b == nil {
 		return nil, blkstorage.ErrNotFoundInIndex
 	}
+	if len(b) == 0 {
+		// txid imported from a snapshot, the transaction itself is not present in the block files
+		return nil, blkstorage.ErrNotAvailableBeforeSnapshot
+	}
 	txFLP := &fileLocPointer{}
 	txFLP.unmarshal(b)
 	return txFLP, nil
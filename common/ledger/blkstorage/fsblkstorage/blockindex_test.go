@@ -64,6 +64,18 @@ func (i *noopIndex) isAttributeIndexed(attribute blkstorage.IndexableAttr) bool
 	return true
 }
 
+func (i *noopIndex) exportTxIDs(handle func(*blkstorage.TxIDInfo) error) error {
+	return nil
+}
+
+func (i *noopIndex) getArchiveInfo() (*archiveInfo, error) {
+	return &archiveInfo{}, nil
+}
+
+func (i *noopIndex) recordArchivedFiles(info *archiveInfo) error {
+	return nil
+}
+
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
 	testBlockIndexSync(t, 10, 5, true)
@@ -53,6 +53,9 @@ func (itr *blocksItr) waitForBlock(blockNum uint64) uint64 {
 func (itr *blocksItr) initStream() error {
 	var lp *fileLocPointer
 	var err error
+	// hold the archive lock so that the block file is not archived before the stream opens it
+	itr.mgr.archiveLock.RLock()
+	defer itr.mgr.archiveLock.RUnlock()
 	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
 		return err
 	}
@@ -79,6 +82,9 @@ func (itr *blocksItr) Next() (ledger.QueryResult, error) {
 		return nil, nil
 	}
 	if itr.stream == nil {
+		if itr.mgr.isBlockArchived(itr.blockNumToRetrieve) {
+			return itr.nextArchivedBlock()
+		}
 		logger.Debugf("Initializing block stream for iterator. itr.maxBlockNumAvailable=%d", itr.maxBlockNumAvailable)
 		if err := itr.initStream(); err != nil {
 			return nil, err
@@ -86,12 +92,28 @@ func (itr *blocksItr) Next() (ledger.QueryResult, error) {
 	}
 	nextBlockBytes, err := itr.stream.nextBlockBytes()
 	if err != nil {
-		return nil, err
+		if !itr.mgr.isBlockArchived(itr.blockNumToRetrieve) {
+			return nil, err
+		}
+		// the next block file was archived while the iterator was reading the preceding files
+		itr.stream.close()
+		itr.stream = nil
+		return itr.nextArchivedBlock()
 	}
 	itr.blockNumToRetrieve++
 	return deserializeBlock(nextBlockBytes)
 }
 
+// nextArchivedBlock retrieves the next block from the archive, one block at a time
+func (itr *blocksItr) nextArchivedBlock() (ledger.QueryResult, error) {
+	block, err := itr.mgr.retrieveBlockByNumber(itr.blockNumToRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	itr.blockNumToRetrieve++
+	return block, nil
+}
+
 // Close releases any resources held by the iterator
 func (itr *blocksItr) Close() {
 	itr.mgr.cpInfoCond.L.Lock()
@@ -0,0 +1,250 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	bootstrappingSnapshotInfoFile    = "bootstrappingSnapshot.info"
+	bootstrappingSnapshotInfoTmpFile = "bootstrappingSnapshot.info.tmp"
+	maxTxIDsInImportBatch            = 10000
+)
+
+// bootstrappingSnapshotInfo is persisted in the ledger's block directory when a block store
+// is bootstrapped from a snapshot. Keeping this in the block directory (as opposed to the index)
+// ensures that the information survives a rebuild of the index. The last block and the last config
+// block are retained so that the consumers that look up the most recent config block can continue to do so
+type bootstrappingSnapshotInfo struct {
+	lastBlockNum         uint64
+	lastBlockHash        []byte
+	previousBlockHash    []byte
+	lastBlockBytes       []byte
+	lastConfigBlockBytes []byte
+}
+
+func newBootstrappingSnapshotInfo(snapshotInfo *blkstorage.SnapshotInfo) (*bootstrappingSnapshotInfo, error) {
+	lastBlock := snapshotInfo.LastBlock
+	if lastBlock == nil || lastBlock.Header == nil {
+		return nil, errors.New("last block of the snapshot is missing")
+	}
+	lastBlockBytes, err := proto.Marshal(lastBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling the last block of the snapshot")
+	}
+	info := &bootstrappingSnapshotInfo{
+		lastBlockNum:      lastBlock.Header.Number,
+		lastBlockHash:     lastBlock.Header.Hash(),
+		previousBlockHash: lastBlock.Header.PreviousHash,
+		lastBlockBytes:    lastBlockBytes,
+	}
+	lastConfigBlock := snapshotInfo.LastConfigBlock
+	if lastConfigBlock == nil || lastConfigBlock.Header == nil {
+		return nil, errors.New("last config block of the snapshot is missing")
+	}
+	if lastConfigBlock.Header.Number > lastBlock.Header.Number {
+		return nil, errors.Errorf("last config block [%d] cannot be after the last block [%d] of the snapshot",
+			lastConfigBlock.Header.Number, lastBlock.Header.Number)
+	}
+	if lastConfigBlock.Header.Number != lastBlock.Header.Number {
+		if info.lastConfigBlockBytes, err = proto.Marshal(lastConfigBlock); err != nil {
+			return nil, errors.Wrap(err, "error marshaling the last config block of the snapshot")
+		}
+	}
+	return info, nil
+}
+
+func (i *bootstrappingSnapshotInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	if err := buffer.EncodeVarint(i.lastBlockNum); err != nil {
+		return nil, errors.Wrapf(err, "error encoding the lastBlockNum [%d]", i.lastBlockNum)
+	}
+	for _, b := range [][]byte{i.lastBlockHash, i.previousBlockHash, i.lastBlockBytes, i.lastConfigBlockBytes} {
+		if err := buffer.EncodeRawBytes(b); err != nil {
+			return nil, errors.Wrap(err, "error encoding the bootstrapping snapshot info")
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *bootstrappingSnapshotInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	var err error
+	if i.lastBlockNum, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	for _, field := range []*[]byte{&i.lastBlockHash, &i.previousBlockHash, &i.lastBlockBytes, &i.lastConfigBlockBytes} {
+		if *field, err = buffer.DecodeRawBytes(true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retrieveBlock returns one of the blocks retained from the snapshot. For any other block
+// preceding the snapshot, error `ErrNotAvailableBeforeSnapshot` is returned
+func (i *bootstrappingSnapshotInfo) retrieveBlock(blockNum uint64) (*common.Block, error) {
+	var blockBytes []byte
+	if blockNum == i.lastBlockNum {
+		blockBytes = i.lastBlockBytes
+	} else if len(i.lastConfigBlockBytes) > 0 {
+		blockBytes = i.lastConfigBlockBytes
+	}
+	if blockBytes == nil {
+		return nil, blkstorage.ErrNotAvailableBeforeSnapshot
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling block retained from the snapshot")
+	}
+	if block.Header.Number != blockNum {
+		return nil, blkstorage.ErrNotAvailableBeforeSnapshot
+	}
+	return block, nil
+}
+
+// loadBootstrappingSnapshotInfo returns nil if the block store was not bootstrapped from a snapshot
+func loadBootstrappingSnapshotInfo(rootDir string) (*bootstrappingSnapshotInfo, error) {
+	b, err := ioutil.ReadFile(filepath.Join(rootDir, bootstrappingSnapshotInfoFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading bootstrapping snapshot info from dir [%s]", rootDir)
+	}
+	info := &bootstrappingSnapshotInfo{}
+	if err := info.unmarshal(b); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling bootstrapping snapshot info from dir [%s]", rootDir)
+	}
+	return info, nil
+}
+
+func saveBootstrappingSnapshotInfo(rootDir string, info *bootstrappingSnapshotInfo) error {
+	b, err := info.marshal()
+	if err != nil {
+		return err
+	}
+	tmpFile := filepath.Join(rootDir, bootstrappingSnapshotInfoTmpFile)
+	if err := ioutil.WriteFile(tmpFile, b, 0640); err != nil {
+		return errors.Wrapf(err, "error writing file [%s]", tmpFile)
+	}
+	return errors.Wrap(
+		os.Rename(tmpFile, filepath.Join(rootDir, bootstrappingSnapshotInfoFile)),
+		"error while renaming the bootstrapping snapshot info file",
+	)
+}
+
+// IsBootstrappedFromSnapshot returns true if the block store for the given ledger was bootstrapped from a snapshot
+func IsBootstrappedFromSnapshot(blockStorageDir, ledgerID string) (bool, error) {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	exists, _, err := util.FileExists(filepath.Join(conf.getLedgerBlockDir(ledgerID), bootstrappingSnapshotInfoFile))
+	return exists, err
+}
+
+// bootstrapFromSnapshot prepares the block files directory and the index for a block store that starts
+// at the block that follows the last block covered by the snapshot. The txids are imported first and the
+// bootstrapping snapshot info is saved last so that a crash in between leaves the ledger dir in a state
+// on which the bootstrap can be retried
+func bootstrapFromSnapshot(ledgerID string, conf *Conf, indexConfig *blkstorage.IndexConfig,
+	indexStore *leveldbhelper.DBHandle, snapshotInfo *blkstorage.SnapshotInfo, txIDs blkstorage.TxIDInfoIterator) error {
+	rootDir := conf.getLedgerBlockDir(ledgerID)
+	info, err := newBootstrappingSnapshotInfo(snapshotInfo)
+	if err != nil {
+		return err
+	}
+	if _, err := util.CreateDirIfMissing(rootDir); err != nil {
+		return errors.Wrapf(err, "error creating block storage root dir [%s]", rootDir)
+	}
+	existingInfo, err := loadBootstrappingSnapshotInfo(rootDir)
+	if err != nil {
+		return err
+	}
+	lastFileNum, err := retrieveLastFileSuffix(rootDir)
+	if err != nil {
+		return err
+	}
+	if existingInfo != nil || lastFileNum != -1 {
+		return errors.Errorf("block store for ledger [%s] already exists", ledgerID)
+	}
+
+	index, err := newBlockIndex(indexConfig, indexStore)
+	if err != nil {
+		return err
+	}
+	if err := index.importTxIDs(txIDs, info.lastBlockNum); err != nil {
+		return err
+	}
+	if err := saveBootstrappingSnapshotInfo(rootDir, info); err != nil {
+		return err
+	}
+	logger.Infof("Bootstrapped block store for ledger [%s] from snapshot. Last block in snapshot = [%d]",
+		ledgerID, info.lastBlockNum)
+	return nil
+}
+
+// importTxIDs indexes the txids supplied from a snapshot. As no block location is available for these
+// transactions, an empty value is stored against the txid and the validation code is indexed as usual.
+// Finally, the index checkpoint is set to the last block in the snapshot
+func (index *blockIndex) importTxIDs(txIDs blkstorage.TxIDInfoIterator, lastBlockNum uint64) error {
+	txIDIndexed := index.isAttributeIndexed(blkstorage.IndexableAttrTxID)
+	validationCodeIndexed := index.isAttributeIndexed(blkstorage.IndexableAttrTxValidationCode)
+	batch := leveldbhelper.NewUpdateBatch()
+	for {
+		txIDInfo, err := txIDs.Next()
+		if err != nil {
+			return err
+		}
+		if txIDInfo == nil {
+			break
+		}
+		if txIDIndexed {
+			batch.Put(constructTxIDKey(txIDInfo.TxID), []byte{})
+		}
+		if validationCodeIndexed {
+			batch.Put(constructTxValidationCodeIDKey(txIDInfo.TxID), []byte{byte(txIDInfo.ValidationCode)})
+		}
+		if batch.Len() >= maxTxIDsInImportBatch {
+			if err := index.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	batch.Put(indexCheckpointKey, encodeBlockNum(lastBlockNum))
+	return index.db.WriteBatch(batch, true)
+}
+
+// exportTxIDs invokes the function `handle` for each of the txids present in the txid index
+func (index *blockIndex) exportTxIDs(handle func(*blkstorage.TxIDInfo) error) error {
+	if !index.isAttributeIndexed(blkstorage.IndexableAttrTxID) ||
+		!index.isAttributeIndexed(blkstorage.IndexableAttrTxValidationCode) {
+		return blkstorage.ErrAttrNotIndexed
+	}
+	itr := index.db.GetIterator([]byte{txIDIdxKeyPrefix}, []byte{txIDIdxKeyPrefix + 1})
+	defer itr.Release()
+	for itr.Next() {
+		txID := string(itr.Key()[1:])
+		validationCode, err := index.getTxValidationCodeByTxID(txID)
+		if err != nil {
+			return err
+		}
+		if err := handle(&blkstorage.TxIDInfo{TxID: txID, ValidationCode: validationCode}); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(itr.Error(), "error while iterating over the txid index")
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/peer"
+	putil "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type txIDInfoSliceIterator struct {
+	txIDs []*blkstorage.TxIDInfo
+}
+
+func (i *txIDInfoSliceIterator) Next() (*blkstorage.TxIDInfo, error) {
+	if len(i.txIDs) == 0 {
+		return nil, nil
+	}
+	txID := i.txIDs[0]
+	i.txIDs = i.txIDs[1:]
+	return txID, nil
+}
+
+func TestBootstrapFromSnapshot(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	blocks := testutil.ConstructTestBlocks(t, 15)
+	sourceStore, err := env.provider.OpenBlockStore("sourceLedger")
+	require.NoError(t, err)
+	for _, b := range blocks[:10] {
+		require.NoError(t, sourceStore.AddBlock(b))
+	}
+
+	var txIDs []*blkstorage.TxIDInfo
+	require.NoError(t, sourceStore.ExportTxIDs(func(txIDInfo *blkstorage.TxIDInfo) error {
+		txIDs = append(txIDs, txIDInfo)
+		return nil
+	}))
+	numTxs := 0
+	for _, b := range blocks[:10] {
+		numTxs += len(b.Data.Data)
+	}
+	assert.Len(t, txIDs, numTxs)
+
+	snapshotInfo := &blkstorage.SnapshotInfo{
+		LastBlock:       blocks[9],
+		LastConfigBlock: blocks[0],
+	}
+	store, err := env.provider.BootstrapFromSnapshot("bootstrappedLedger", snapshotInfo, &txIDInfoSliceIterator{txIDs})
+	require.NoError(t, err)
+
+	verifyBootstrappedStore := func(store blkstorage.BlockStore, lastBlockNum int) {
+		bcInfo, err := store.GetBlockchainInfo()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(lastBlockNum+1), bcInfo.Height)
+		assert.Equal(t, blocks[lastBlockNum].Header.Hash(), bcInfo.CurrentBlockHash)
+		assert.Equal(t, blocks[lastBlockNum].Header.PreviousHash, bcInfo.PreviousBlockHash)
+
+		for _, blockNum := range []int{0, 9} {
+			block, err := store.RetrieveBlockByNumber(uint64(blockNum))
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(blocks[blockNum], block))
+		}
+		_, err = store.RetrieveBlockByNumber(5)
+		assert.Equal(t, blkstorage.ErrNotAvailableBeforeSnapshot, err)
+		_, err = store.RetrieveBlocks(5)
+		assert.Contains(t, err.Error(), blkstorage.ErrNotAvailableBeforeSnapshot.Error())
+
+		txID, err := putil.GetOrComputeTxIDFromEnvelope(blocks[5].Data.Data[0])
+		require.NoError(t, err)
+		_, err = store.RetrieveTxByID(txID)
+		assert.Equal(t, blkstorage.ErrNotAvailableBeforeSnapshot, err)
+		validationCode, err := store.RetrieveTxValidationCodeByTxID(txID)
+		require.NoError(t, err)
+		assert.Equal(t, peer.TxValidationCode_VALID, validationCode)
+
+		for blockNum := 10; blockNum <= lastBlockNum; blockNum++ {
+			block, err := store.RetrieveBlockByNumber(uint64(blockNum))
+			require.NoError(t, err)
+			assert.Equal(t, blocks[blockNum], block)
+		}
+	}
+
+	verifyBootstrappedStore(store, 9)
+	for _, b := range blocks[10:] {
+		require.NoError(t, store.AddBlock(b))
+	}
+	verifyBootstrappedStore(store, 14)
+	itr, err := store.RetrieveBlocks(10)
+	require.NoError(t, err)
+	for _, b := range blocks[10:] {
+		block, err := itr.Next()
+		require.NoError(t, err)
+		assert.Equal(t, b, block)
+	}
+	itr.Close()
+
+	// reopen and verify
+	store.Shutdown()
+	env.provider.Close()
+	env = newTestEnv(t, env.provider.conf)
+	store, err = env.provider.OpenBlockStore("bootstrappedLedger")
+	require.NoError(t, err)
+	verifyBootstrappedStore(store, 14)
+
+	bootstrapped, err := IsBootstrappedFromSnapshot(env.provider.conf.blockStorageDir, "bootstrappedLedger")
+	require.NoError(t, err)
+	assert.True(t, bootstrapped)
+	bootstrapped, err = IsBootstrappedFromSnapshot(env.provider.conf.blockStorageDir, "sourceLedger")
+	require.NoError(t, err)
+	assert.False(t, bootstrapped)
+	store.Shutdown()
+	env.provider.Close()
+
+	assert.EqualError(t,
+		ResetBlockStore(env.provider.conf.blockStorageDir),
+		"cannot reset the block store, as the ledger [bootstrappedLedger] was bootstrapped from a snapshot",
+	)
+	assert.EqualError(t,
+		ValidateRollbackParams(env.provider.conf.blockStorageDir, "bootstrappedLedger", 12),
+		"cannot rollback the ledger [bootstrappedLedger], as it was bootstrapped from a snapshot",
+	)
+}
+
+func TestBootstrapFromSnapshotErrors(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	blocks := testutil.ConstructTestBlocks(t, 3)
+	store, err := env.provider.OpenBlockStore("existingLedger")
+	require.NoError(t, err)
+	require.NoError(t, store.AddBlock(blocks[0]))
+	store.Shutdown()
+
+	snapshotInfo := &blkstorage.SnapshotInfo{LastBlock: blocks[2], LastConfigBlock: blocks[0]}
+	_, err = env.provider.BootstrapFromSnapshot("existingLedger", snapshotInfo, &txIDInfoSliceIterator{})
+	assert.EqualError(t, err, "block store for ledger [existingLedger] already exists")
+
+	_, err = env.provider.BootstrapFromSnapshot("newLedger", &blkstorage.SnapshotInfo{LastConfigBlock: blocks[0]}, &txIDInfoSliceIterator{})
+	assert.EqualError(t, err, "last block of the snapshot is missing")
+
+	_, err = env.provider.BootstrapFromSnapshot("newLedger", &blkstorage.SnapshotInfo{LastBlock: blocks[0], LastConfigBlock: blocks[2]}, &txIDInfoSliceIterator{})
+	assert.EqualError(t, err, "last config block [2] cannot be after the last block [0] of the snapshot")
+}
+
+func TestBootstrappingSnapshotInfoMarshaling(t *testing.T) {
+	info := &bootstrappingSnapshotInfo{
+		lastBlockNum:         20,
+		lastBlockHash:        []byte("last-block-hash"),
+		previousBlockHash:    []byte("previous-block-hash"),
+		lastBlockBytes:       []byte("last-block"),
+		lastConfigBlockBytes: []byte("last-config-block"),
+	}
+	b, err := info.marshal()
+	require.NoError(t, err)
+	unmarshaled := &bootstrappingSnapshotInfo{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	assert.Equal(t, info, unmarshaled)
+}
@@ -16,7 +16,10 @@ limitations under the License.
 
 package fsblkstorage
 
-import "path/filepath"
+import (
+	"path/filepath"
+	"time"
+)
 
 const (
 	// ChainsDir is the name of the directory containing the channel ledgers.
@@ -30,15 +33,34 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	archiveConf      *ArchiveConf
+}
+
+// ArchiveConf encapsulates the configurations for moving the older block files to an `Archive`.
+// A block file is archived when all of its blocks are older than the latest `RetainBlocks` blocks
+// or when the file was last written before `MaxAge`. A zero value disables the corresponding criterion.
+// The blocks present in the archived files are read from the archive if `FetchArchived` is true,
+// otherwise the error `blkstorage.ErrBlockArchived` is returned for these blocks
+type ArchiveConf struct {
+	Archive       Archive
+	RetainBlocks  uint64
+	MaxAge        time.Duration
+	FetchArchived bool
 }
 
 // NewConf constructs new `Conf`.
 // blockStorageDir is the top level folder under which `FsBlockStore` manages its data
 func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
+	return NewConfWithArchive(blockStorageDir, maxBlockfileSize, nil)
+}
+
+// NewConfWithArchive constructs new `Conf` that archives the older block files as per the archiveConf.
+// A nil archiveConf disables the archiving of the block files
+func NewConfWithArchive(blockStorageDir string, maxBlockfileSize int, archiveConf *ArchiveConf) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir, maxBlockfileSize, archiveConf}
 }
 
 func (conf *Conf) getIndexDir() string {
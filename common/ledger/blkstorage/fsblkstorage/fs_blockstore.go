@@ -112,3 +112,8 @@ func (store *fsBlockStore) updateBlockStats(blockNum uint64, blockstorageCommitT
 	store.stats.updateBlockchainHeight(blockNum + 1)
 	store.stats.updateBlockstorageCommitTime(blockstorageCommitTime)
 }
+
+// ExportTxIDs invokes the function `handle` for each of the txids present in the block store
+func (store *fsBlockStore) ExportTxIDs(handle func(*blkstorage.TxIDInfo) error) error {
+	return store.fileMgr.index.exportTxIDs(handle)
+}
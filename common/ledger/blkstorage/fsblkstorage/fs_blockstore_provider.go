@@ -17,10 +17,14 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
 )
 
 // FsBlockstoreProvider provides handle to block storage - this is not thread-safe
@@ -52,6 +56,18 @@ func (p *FsBlockstoreProvider) OpenBlockStore(ledgerid string) (blkstorage.Block
 	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle, p.stats), nil
 }
 
+// BootstrapFromSnapshot creates a block store for the given ledgerid that starts after the last block
+// covered by the snapshot. The supplied txids are added to the index so that the duplicate txids can
+// be detected for the future blocks. This method returns error if the block store already exists
+func (p *FsBlockstoreProvider) BootstrapFromSnapshot(ledgerid string, snapshotInfo *blkstorage.SnapshotInfo,
+	txIDs blkstorage.TxIDInfoIterator) (blkstorage.BlockStore, error) {
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
+	if err := bootstrapFromSnapshot(ledgerid, p.conf, p.indexConfig, indexStoreHandle, snapshotInfo, txIDs); err != nil {
+		return nil, err
+	}
+	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle, p.stats), nil
+}
+
 // Exists tells whether the BlockStore with given id exists
 func (p *FsBlockstoreProvider) Exists(ledgerid string) (bool, error) {
 	exists, _, err := util.FileExists(p.conf.getLedgerBlockDir(ledgerid))
@@ -63,6 +79,19 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// Remove removes the block files and the index of the given ledger. The index is removed first, so that
+// an interrupted removal leaves the ledger listed and the removal can be repeated. The block files that
+// were moved to the archive are not removed
+func (p *FsBlockstoreProvider) Remove(ledgerid string) error {
+	if err := p.leveldbProvider.GetDBHandle(ledgerid).DeleteAll(); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error removing the block index of ledger [%s]", ledgerid))
+	}
+	if err := os.RemoveAll(p.conf.getLedgerBlockDir(ledgerid)); err != nil {
+		return errors.Wrapf(err, "error removing the block files of ledger [%s]", ledgerid)
+	}
+	return nil
+}
+
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
 	p.leveldbProvider.Close()
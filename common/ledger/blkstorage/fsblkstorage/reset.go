@@ -13,10 +13,17 @@ import (
 	"strconv"
 
 	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/pkg/errors"
 )
 
 func ResetBlockStore(blockStorageDir string) error {
 	conf := &Conf{blockStorageDir: blockStorageDir}
+	if err := assertNoLedgerBootstrappedFromSnapshot(conf); err != nil {
+		return err
+	}
+	if err := assertNoBlockFilesArchived(conf); err != nil {
+		return errors.WithMessage(err, "cannot reset the block store")
+	}
 	indexDir := conf.getIndexDir()
 	logger.Infof("Dropping the index dir [%s]... if present", indexDir)
 	if err := os.RemoveAll(indexDir); err != nil {
@@ -52,6 +59,30 @@ func ResetBlockStore(blockStorageDir string) error {
 	return nil
 }
 
+// assertNoLedgerBootstrappedFromSnapshot returns an error if any of the ledgers was bootstrapped from a snapshot.
+// Such a ledger does not have the genesis block and the index carries the txids imported from the snapshot
+func assertNoLedgerBootstrappedFromSnapshot(conf *Conf) error {
+	chainsDir := conf.getChainsDir()
+	chainsDirExists, err := pathExists(chainsDir)
+	if err != nil || !chainsDirExists {
+		return err
+	}
+	ledgerIDs, err := util.ListSubdirs(chainsDir)
+	if err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		bootstrapped, err := IsBootstrappedFromSnapshot(conf.blockStorageDir, ledgerID)
+		if err != nil {
+			return err
+		}
+		if bootstrapped {
+			return errors.Errorf("cannot reset the block store, as the ledger [%s] was bootstrapped from a snapshot", ledgerID)
+		}
+	}
+	return nil
+}
+
 func resetToGenesisBlk(ledgerDir string) error {
 	logger.Infof("Resetting ledger [%s] to genesis block", ledgerDir)
 	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
@@ -31,6 +31,15 @@ func Rollback(blockStorageDir, ledgerID string, targetBlockNum uint64, indexConf
 	}
 	defer r.dbProvider.Close()
 
+	archiveInfo, err := r.indexStore.getArchiveInfo()
+	if err != nil {
+		return err
+	}
+	if targetBlockNum < archiveInfo.archivedBlocksHeight {
+		return errors.Errorf("cannot rollback the ledger [%s] to block [%d], as the blocks below [%d] have been moved to the archive",
+			ledgerID, targetBlockNum, archiveInfo.archivedBlocksHeight)
+	}
+
 	if err := recordHeightIfGreaterThanPreviousRecording(r.ledgerDir); err != nil {
 		return err
 	}
@@ -270,6 +279,13 @@ func ValidateRollbackParams(blockStorageDir, ledgerID string, targetBlockNum uin
 	if err := validateLedgerID(ledgerDir, ledgerID); err != nil {
 		return err
 	}
+	bootstrapped, err := IsBootstrappedFromSnapshot(blockStorageDir, ledgerID)
+	if err != nil {
+		return err
+	}
+	if bootstrapped {
+		return errors.Errorf("cannot rollback the ledger [%s], as it was bootstrapped from a snapshot", ledgerID)
+	}
 	if err := validateTargetBlkNum(ledgerDir, targetBlockNum); err != nil {
 		return err
 	}
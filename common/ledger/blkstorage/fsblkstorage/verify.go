@@ -0,0 +1,357 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	componentBlockFiles = "blockfiles"
+	componentBlockIndex = "blockindex"
+)
+
+// BlockVerifier is invoked for each block read from the block files during the verification of a block store.
+// This allows the other stores of a ledger to be cross-checked against the blocks in the same pass
+type BlockVerifier func(block *common.Block) ([]*blkstorage.Inconsistency, error)
+
+// VerificationResult captures the outcome of the verification of the block store of a ledger.
+// BlockHeight is the height recorded in the checkpoint and FirstBlockNum is the first block
+// present in the local block files, i.e., the blocks below it are either archived or covered
+// by the snapshot from which the block store was bootstrapped
+type VerificationResult struct {
+	BlockHeight     uint64
+	FirstBlockNum   uint64
+	BlocksVerified  uint64
+	Inconsistencies []*blkstorage.Inconsistency
+}
+
+// Verify walks the local block files of a ledger and rechecks the hash chain and the data hash of
+// each block, and cross-checks the entries in the block index against the location of the blocks and
+// the transactions in the block files. The block store is opened only for reading and hence, the
+// peer is expected to be offline. The inconsistencies are reported in the result, whereas an error
+// is returned only if the verification itself cannot be carried out
+func Verify(blockStorageDir, ledgerID string, indexConfig *blkstorage.IndexConfig, blockVerifier BlockVerifier) (*VerificationResult, error) {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	rootDir := conf.getLedgerBlockDir(ledgerID)
+	exists, err := pathExists(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Errorf("ledger [%s] does not exist in the block store", ledgerID)
+	}
+
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir()})
+	defer dbProvider.Close()
+	v, err := newVerifier(rootDir, ledgerID, indexConfig, dbProvider.GetDBHandle(ledgerID))
+	if err != nil {
+		return nil, err
+	}
+	v.blockVerifier = blockVerifier
+	if err := v.verifyBlockFiles(); err != nil {
+		return nil, err
+	}
+	v.verifyCheckpoints()
+	return v.result, nil
+}
+
+type verifier struct {
+	ledgerID          string
+	rootDir           string
+	index             *blockIndex
+	cpInfo            *checkpointInfo
+	archiveInfo       *archiveInfo
+	snapshotInfo      *bootstrappingSnapshotInfo
+	lastBlockIndexed  uint64
+	indexEmpty        bool
+	blockVerifier     BlockVerifier
+	expectedBlockNum  uint64
+	previousBlockHash []byte
+	result            *VerificationResult
+}
+
+func newVerifier(rootDir, ledgerID string, indexConfig *blkstorage.IndexConfig, db *leveldbhelper.DBHandle) (*verifier, error) {
+	v := &verifier{ledgerID: ledgerID, rootDir: rootDir, result: &VerificationResult{}}
+	var err error
+	if v.index, err = newBlockIndex(indexConfig, db); err != nil {
+		return nil, err
+	}
+	if v.snapshotInfo, err = loadBootstrappingSnapshotInfo(rootDir); err != nil {
+		return nil, err
+	}
+	if v.archiveInfo, err = loadArchiveInfo(db); err != nil {
+		return nil, err
+	}
+	cpInfoBytes, err := db.Get(blkMgrInfoKey)
+	if err != nil {
+		return nil, err
+	}
+	if cpInfoBytes == nil {
+		v.addInconsistency(componentBlockIndex, blkstorage.SeverityWarning, 0, nil,
+			"the checkpoint info is missing, it is reconstructed from the block files")
+		if v.cpInfo, err = constructCheckpointInfoFromBlockFiles(rootDir); err != nil {
+			return nil, err
+		}
+		if v.cpInfo.isChainEmpty && v.snapshotInfo != nil {
+			v.cpInfo.isChainEmpty = false
+			v.cpInfo.lastBlockNumber = v.snapshotInfo.lastBlockNum
+		}
+	} else {
+		v.cpInfo = &checkpointInfo{}
+		if err := v.cpInfo.unmarshal(cpInfoBytes); err != nil {
+			return nil, errors.Wrap(err, "error unmarshaling the checkpoint info")
+		}
+	}
+	if !v.cpInfo.isChainEmpty {
+		v.result.BlockHeight = v.cpInfo.lastBlockNumber + 1
+	}
+
+	v.lastBlockIndexed, err = v.index.getLastBlockIndexed()
+	switch {
+	case err == errIndexEmpty:
+		v.indexEmpty = true
+	case err != nil:
+		return nil, err
+	}
+
+	switch {
+	case v.archiveInfo.numArchivedFiles > 0:
+		// the hash of the last archived block is not available locally and hence,
+		// the previous hash of the first local block is not verified
+		v.expectedBlockNum = v.archiveInfo.archivedBlocksHeight
+	case v.snapshotInfo != nil:
+		v.expectedBlockNum = v.snapshotInfo.lastBlockNum + 1
+		v.previousBlockHash = v.snapshotInfo.lastBlockHash
+	}
+	v.result.FirstBlockNum = v.expectedBlockNum
+	return v, nil
+}
+
+func (v *verifier) verifyBlockFiles() error {
+	startFileNum := v.archiveInfo.numArchivedFiles
+	if exists, err := pathExists(deriveBlockfilePath(v.rootDir, startFileNum)); err != nil || !exists {
+		if err == nil && v.result.BlockHeight > v.result.FirstBlockNum {
+			v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, v.expectedBlockNum, nil,
+				fmt.Sprintf("the block file [%s] is missing", blockfileName(startFileNum)))
+		}
+		return err
+	}
+	stream, err := newBlockStream(v.rootDir, startFileNum, 0, v.cpInfo.latestFileChunkSuffixNum)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	for {
+		blockBytes, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err == ErrUnexpectedEndOfBlockfile {
+			v.addInconsistency(componentBlockFiles, blkstorage.SeverityWarning, v.expectedBlockNum, nil,
+				"the block files end with a partially written block, which is truncated at the peer start")
+			return nil
+		}
+		if err != nil {
+			v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, v.expectedBlockNum, nil,
+				fmt.Sprintf("error reading the block files: %s", err))
+			return nil
+		}
+		if blockBytes == nil {
+			return nil
+		}
+		block, err := deserializeBlock(blockBytes)
+		if err != nil {
+			v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, v.expectedBlockNum, nil,
+				fmt.Sprintf("error deserializing the block at offset [%d] in the block file [%s]: %s",
+					placementInfo.blockStartOffset, blockfileName(placementInfo.fileNum), err))
+			return nil
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		if err := v.verifyBlock(block, info, placementInfo); err != nil {
+			return err
+		}
+	}
+}
+
+func (v *verifier) verifyBlock(block *common.Block, info *serializedBlockInfo, placementInfo *blockPlacementInfo) error {
+	blockNum := block.Header.Number
+	if blockNum != v.expectedBlockNum {
+		v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, blockNum, nil,
+			fmt.Sprintf("the block number should have been [%d]", v.expectedBlockNum))
+	}
+	if v.previousBlockHash != nil && !bytes.Equal(block.Header.PreviousHash, v.previousBlockHash) {
+		v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, blockNum, nil,
+			fmt.Sprintf("the previous hash [%x] does not match the hash [%x] of the preceding block",
+				block.Header.PreviousHash, v.previousBlockHash))
+	}
+	if !bytes.Equal(block.Header.DataHash, block.Data.Hash()) {
+		v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, blockNum, nil,
+			fmt.Sprintf("the data hash [%x] does not match the hash [%x] of the block data",
+				block.Header.DataHash, block.Data.Hash()))
+	}
+	var txsFilter ledgerUtil.TxValidationFlags
+	if len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txsFilter = block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+	if len(txsFilter) != len(block.Data.Data) {
+		v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, blockNum, nil,
+			fmt.Sprintf("the transactions filter has [%d] entries for [%d] transactions", len(txsFilter), len(block.Data.Data)))
+		txsFilter = nil
+	}
+
+	if !v.indexEmpty && blockNum <= v.lastBlockIndexed {
+		if err := v.verifyIndexEntries(blockNum, block.Header.Hash(), info, placementInfo, txsFilter); err != nil {
+			return err
+		}
+	}
+	if v.blockVerifier != nil {
+		inconsistencies, err := v.blockVerifier(block)
+		if err != nil {
+			return err
+		}
+		v.result.Inconsistencies = append(v.result.Inconsistencies, inconsistencies...)
+	}
+
+	v.result.BlocksVerified++
+	v.expectedBlockNum = blockNum + 1
+	v.previousBlockHash = block.Header.Hash()
+	return nil
+}
+
+func (v *verifier) verifyIndexEntries(blockNum uint64, blockHash []byte, info *serializedBlockInfo,
+	placementInfo *blockPlacementInfo, txsFilter ledgerUtil.TxValidationFlags) error {
+	blockFLP := &fileLocPointer{fileSuffixNum: placementInfo.fileNum,
+		locPointer: locPointer{offset: int(placementInfo.blockStartOffset)}}
+
+	flp, err := v.index.getBlockLocByBlockNum(blockNum)
+	if err := v.checkLocation("block number", blockNum, nil, blockFLP, flp, err); err != nil {
+		return err
+	}
+	flp, err = v.index.getBlockLocByHash(blockHash)
+	if err := v.checkLocation("block hash", blockNum, nil, blockFLP, flp, err); err != nil {
+		return err
+	}
+
+	// the offsets of the transactions are relative to the block bytes, which follow the length of the block
+	numBytesToShift := int(placementInfo.blockBytesOffset - placementInfo.blockStartOffset)
+	for txNum, txOffset := range info.txOffsets {
+		txNum := uint64(txNum)
+		txFLP := newFileLocationPointer(blockFLP.fileSuffixNum, blockFLP.offset+numBytesToShift, txOffset.loc)
+		flp, err := v.index.getTXLocByBlockNumTranNum(blockNum, txNum)
+		if err := v.checkLocation("block number and transaction number", blockNum, &txNum, txFLP, flp, err); err != nil {
+			return err
+		}
+
+		flp, err = v.index.getTxLoc(txOffset.txID)
+		if err == blkstorage.ErrAttrNotIndexed || err == blkstorage.ErrNotAvailableBeforeSnapshot {
+			continue
+		}
+		if err := v.checkLocation("txid", blockNum, &txNum, txFLP, flp, err); err != nil {
+			return err
+		}
+		if flp == nil || !sameLocation(flp, txFLP) {
+			// the transaction is either not indexed (reported above) or a duplicate of a preceding transaction
+			continue
+		}
+
+		flp, err = v.index.getBlockLocByTxID(txOffset.txID)
+		if err := v.checkLocation("block txid", blockNum, &txNum, blockFLP, flp, err); err != nil {
+			return err
+		}
+		if txsFilter == nil {
+			continue
+		}
+		validationCode, err := v.index.getTxValidationCodeByTxID(txOffset.txID)
+		switch {
+		case err == blkstorage.ErrAttrNotIndexed:
+		case err == blkstorage.ErrNotFoundInIndex:
+			v.addInconsistency(componentBlockIndex, blkstorage.SeverityError, blockNum, &txNum,
+				"the validation code of the transaction is missing in the index")
+		case err != nil:
+			return err
+		case validationCode != txsFilter.Flag(int(txNum)):
+			v.addInconsistency(componentBlockIndex, blkstorage.SeverityError, blockNum, &txNum,
+				fmt.Sprintf("the indexed validation code [%s] does not match the validation code [%s] in the block",
+					validationCode, txsFilter.Flag(int(txNum))))
+		}
+	}
+	return nil
+}
+
+// checkLocation compares the location looked up in the index with the actual location in the block files.
+// An index entry that points to an earlier location is legitimate for a txid, as only the first
+// occurrence of a duplicate txid is indexed, and hence, it is not reported for the txid
+func (v *verifier) checkLocation(entry string, blockNum uint64, txNum *uint64, expected, indexed *fileLocPointer, err error) error {
+	switch {
+	case err == blkstorage.ErrAttrNotIndexed:
+		return nil
+	case err == blkstorage.ErrNotFoundInIndex:
+		v.addInconsistency(componentBlockIndex, blkstorage.SeverityError, blockNum, txNum,
+			fmt.Sprintf("the %s index entry is missing", entry))
+		return nil
+	case err != nil:
+		return err
+	}
+	if sameLocation(indexed, expected) || (entry == "txid" && precedes(indexed, expected)) {
+		return nil
+	}
+	v.addInconsistency(componentBlockIndex, blkstorage.SeverityError, blockNum, txNum,
+		fmt.Sprintf("the %s index entry points to [%s] instead of [%s]", entry, indexed, expected))
+	return nil
+}
+
+func (v *verifier) verifyCheckpoints() {
+	switch {
+	case v.expectedBlockNum < v.result.BlockHeight:
+		v.addInconsistency(componentBlockFiles, blkstorage.SeverityError, v.expectedBlockNum, nil,
+			fmt.Sprintf("the block files end before the block height [%d] recorded in the checkpoint", v.result.BlockHeight))
+	case v.expectedBlockNum > v.result.BlockHeight:
+		v.addInconsistency(componentBlockFiles, blkstorage.SeverityWarning, v.result.BlockHeight, nil,
+			fmt.Sprintf("the block files contain [%d] blocks beyond the checkpoint, which are recovered at the peer start",
+				v.expectedBlockNum-v.result.BlockHeight))
+	}
+	if v.result.BlocksVerified == 0 || len(v.index.indexItemsMap) == 0 {
+		return
+	}
+	lastBlockNum := v.expectedBlockNum - 1
+	switch {
+	case v.indexEmpty || v.lastBlockIndexed < lastBlockNum:
+		v.addInconsistency(componentBlockIndex, blkstorage.SeverityWarning, lastBlockNum, nil,
+			"the index lags behind the block files, the missing entries are added at the peer start")
+	case v.lastBlockIndexed > lastBlockNum:
+		v.addInconsistency(componentBlockIndex, blkstorage.SeverityError, v.lastBlockIndexed, nil,
+			fmt.Sprintf("the index is ahead of the last block [%d] in the block files", lastBlockNum))
+	}
+}
+
+func (v *verifier) addInconsistency(component, severity string, blockNum uint64, txNum *uint64, description string) {
+	v.result.Inconsistencies = append(v.result.Inconsistencies, &blkstorage.Inconsistency{
+		Component:   component,
+		Severity:    severity,
+		BlockNum:    blockNum,
+		TxNum:       txNum,
+		Description: description,
+	})
+}
+
+func sameLocation(flp1, flp2 *fileLocPointer) bool {
+	return flp1.fileSuffixNum == flp2.fileSuffixNum && flp1.offset == flp2.offset
+}
+
+func precedes(flp1, flp2 *fileLocPointer) bool {
+	return flp1.fileSuffixNum < flp2.fileSuffixNum ||
+		(flp1.fileSuffixNum == flp2.fileSuffixNum && flp1.offset < flp2.offset)
+}
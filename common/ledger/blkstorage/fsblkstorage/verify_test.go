@@ -0,0 +1,147 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	blocks := testutil.ConstructTestBlocks(t, 10)
+
+	env := newTestEnv(t, NewConf(blockStorageDir, testutilBlocksSize(t, blocks[:4])))
+	w := newTestBlockfileWrapper(env, "testLedger")
+	w.addBlocks(blocks)
+	w.close()
+	env.provider.Close()
+
+	var verifiedBlocks []uint64
+	result, err := Verify(blockStorageDir, "testLedger", indexConfig, func(block *common.Block) ([]*blkstorage.Inconsistency, error) {
+		verifiedBlocks = append(verifiedBlocks, block.Header.Number)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &VerificationResult{BlockHeight: 10, BlocksVerified: 10}, result)
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, verifiedBlocks)
+
+	_, err = Verify(blockStorageDir, "nonExistingLedger", indexConfig, nil)
+	assert.EqualError(t, err, "ledger [nonExistingLedger] does not exist in the block store")
+}
+
+func TestVerifyDetectsInconsistencies(t *testing.T) {
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	// tamper the data of a block after its header is constructed
+	blocks[5].Data.Data[0] = []byte("tampered transaction")
+
+	env := newTestEnv(t, NewConf(blockStorageDir, 0))
+	w := newTestBlockfileWrapper(env, "testLedger")
+	w.addBlocks(blocks)
+	db := w.blockfileMgr.db
+	require.NoError(t, db.Delete(constructBlockNumKey(3), true))
+	txID := testutilTxID(t, blocks[7], 0)
+	require.NoError(t, db.Put(constructTxValidationCodeIDKey(txID), []byte{byte(peer.TxValidationCode_MVCC_READ_CONFLICT)}, true))
+	txLoc, err := w.blockfileMgr.index.getTXLocByBlockNumTranNum(2, 0)
+	require.NoError(t, err)
+	actualTxLoc, err := w.blockfileMgr.index.getTXLocByBlockNumTranNum(8, 0)
+	require.NoError(t, err)
+	txLocBytes, err := txLoc.marshal()
+	require.NoError(t, err)
+	require.NoError(t, db.Put(constructBlockNumTranNumKey(8, 0), txLocBytes, true))
+	w.close()
+	env.provider.Close()
+
+	result, err := Verify(blockStorageDir, "testLedger", indexConfig, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), result.BlocksVerified)
+	txNum := uint64(0)
+	assert.Equal(t, []*blkstorage.Inconsistency{
+		{
+			Component:   "blockindex",
+			Severity:    blkstorage.SeverityError,
+			BlockNum:    3,
+			Description: "the block number index entry is missing",
+		},
+		{
+			Component: "blockfiles",
+			Severity:  blkstorage.SeverityError,
+			BlockNum:  5,
+			Description: fmt.Sprintf("the data hash [%x] does not match the hash [%x] of the block data",
+				blocks[5].Header.DataHash, blocks[5].Data.Hash()),
+		},
+		{
+			Component:   "blockindex",
+			Severity:    blkstorage.SeverityError,
+			BlockNum:    7,
+			TxNum:       &txNum,
+			Description: "the indexed validation code [MVCC_READ_CONFLICT] does not match the validation code [VALID] in the block",
+		},
+		{
+			Component: "blockindex",
+			Severity:  blkstorage.SeverityError,
+			BlockNum:  8,
+			TxNum:     &txNum,
+			Description: fmt.Sprintf("the block number and transaction number index entry points to [%s] instead of [%s]",
+				txLoc, actualTxLoc),
+		},
+	}, result.Inconsistencies)
+}
+
+func TestVerifyLaggingStores(t *testing.T) {
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	blocks := testutil.ConstructTestBlocks(t, 10)
+
+	env := newTestEnv(t, NewConf(blockStorageDir, 0))
+	w := newTestBlockfileWrapper(env, "testLedger")
+	w.addBlocks(blocks[:8])
+	// simulate a crash after the blocks are appended to the block file but before the index is updated
+	cpInfo := w.blockfileMgr.cpInfo
+	lastBlockIndexed, err := w.blockfileMgr.index.getLastBlockIndexed()
+	require.NoError(t, err)
+	w.addBlocks(blocks[8:])
+	require.NoError(t, w.blockfileMgr.saveCurrentInfo(cpInfo, true))
+	require.NoError(t, w.blockfileMgr.db.Put(indexCheckpointKey, encodeBlockNum(lastBlockIndexed), true))
+	w.close()
+	env.provider.Close()
+
+	result, err := Verify(blockStorageDir, "testLedger", indexConfig, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &VerificationResult{
+		BlockHeight:    8,
+		BlocksVerified: 10,
+		Inconsistencies: []*blkstorage.Inconsistency{
+			{
+				Component:   "blockfiles",
+				Severity:    blkstorage.SeverityWarning,
+				BlockNum:    8,
+				Description: "the block files contain [2] blocks beyond the checkpoint, which are recovered at the peer start",
+			},
+			{
+				Component:   "blockindex",
+				Severity:    blkstorage.SeverityWarning,
+				BlockNum:    9,
+				Description: "the index lags behind the block files, the missing entries are added at the peer start",
+			},
+		},
+	}, result)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compactedledger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// Archive stores the blocks which are no longer kept by the ledger.
+// Implementations may store them remotely, as long as a block that
+// was put in the archive can be retrieved from it afterwards.
+type Archive interface {
+	// Put stores a block of the given chain. It may be called
+	// several times with the same block.
+	Put(chainID string, block *cb.Block) error
+
+	// Get returns the block of the given chain with the given number.
+	Get(chainID string, number uint64) (*cb.Block, error)
+
+	// Remove removes the blocks of the given chain.
+	Remove(chainID string) error
+}
+
+// DirArchive archives blocks in the sub-directories of a directory,
+// which may be backed by remote storage.
+type DirArchive struct {
+	Directory string
+}
+
+// NewDirArchive creates an archive in the given directory.
+func NewDirArchive(directory string) (*DirArchive, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, errors.Wrapf(err, "error creating archive directory %s", directory)
+	}
+	return &DirArchive{Directory: directory}, nil
+}
+
+// Put writes a block to the directory of its chain.
+func (da *DirArchive) Put(chainID string, block *cb.Block) error {
+	directory := da.chainDirectory(chainID)
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return errors.Wrapf(err, "error creating archive of channel %s", chainID)
+	}
+	return writeBlock(directory, block)
+}
+
+// Get reads a block from the directory of its chain.
+func (da *DirArchive) Get(chainID string, number uint64) (*cb.Block, error) {
+	block, err := readBlock(da.chainDirectory(chainID), number)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error reading block %d of channel %s from archive", number, chainID))
+	}
+	return block, nil
+}
+
+// Remove removes the directory of a chain.
+func (da *DirArchive) Remove(chainID string) error {
+	if err := os.RemoveAll(da.chainDirectory(chainID)); err != nil {
+		return errors.Wrapf(err, "error removing archive of channel %s", chainID)
+	}
+	return nil
+}
+
+func (da *DirArchive) chainDirectory(chainID string) string {
+	return filepath.Join(da.Directory, fmt.Sprintf(chainDirectoryFormatString, chainID))
+}
@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compactedledger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/pkg/errors"
+)
+
+type compactedLedgerFactory struct {
+	directory string
+	retention uint64
+	archive   Archive
+	ledgers   map[string]*compactedLedger
+	mutex     sync.Mutex
+}
+
+// GetOrCreate gets an existing ledger (if it exists) or creates it if it does not
+func (clf *compactedLedgerFactory) GetOrCreate(chainID string) (blockledger.ReadWriter, error) {
+	clf.mutex.Lock()
+	defer clf.mutex.Unlock()
+
+	l, ok := clf.ledgers[chainID]
+	if ok {
+		return l, nil
+	}
+
+	directory := filepath.Join(clf.directory, fmt.Sprintf(chainDirectoryFormatString, chainID))
+
+	logger.Debugf("Initializing chain %s at: %s", chainID, directory)
+
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		logger.Errorf("Error initializing channel %s: %s", chainID, err)
+		return nil, errors.Wrapf(err, "error initializing channel %s", chainID)
+	}
+
+	cl, err := newChain(chainID, directory, clf.retention, clf.archive)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error initializing channel %s", chainID))
+	}
+	clf.ledgers[chainID] = cl
+	return cl, nil
+}
+
+// newChain creates a new chain backed by a compacted ledger, and
+// schedules the archiving of the blocks left behind by an interrupted
+// compaction
+func newChain(chainID, directory string, retention uint64, archive Archive) (*compactedLedger, error) {
+	cl := &compactedLedger{
+		chainID:   chainID,
+		directory: directory,
+		retention: retention,
+		archive:   archive,
+		signal:    make(chan struct{}),
+		queue:     make(chan uint64, archiveQueueSize),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+
+	infos, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading directory %s", directory)
+	}
+	go cl.archiveBlocks()
+	blocks := map[uint64]bool{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(info.Name(), ".tmp") {
+			os.Remove(filepath.Join(directory, info.Name()))
+			continue
+		}
+		var number uint64
+		if _, err := fmt.Sscanf(info.Name(), blockFileFormatString, &number); err != nil {
+			continue
+		}
+		blocks[number] = true
+		if number >= cl.height {
+			cl.height = number + 1
+		}
+	}
+	if cl.height == 0 {
+		return cl, nil
+	}
+
+	last, err := readBlock(directory, cl.height-1)
+	if err != nil {
+		cl.stop()
+		return nil, err
+	}
+	cl.lastHash = last.Header.Hash()
+	cl.lastConfig = lastConfigIndex(last, 0)
+
+	cl.oldest = cl.height - 1
+	for cl.oldest > 0 && blocks[cl.oldest-1] {
+		cl.oldest--
+	}
+	for number := range blocks {
+		if number < cl.oldest && number != cl.lastConfig {
+			cl.released = append(cl.released, number)
+		}
+	}
+	sort.Slice(cl.released, func(i, j int) bool { return cl.released[i] < cl.released[j] })
+	cl.mutex.Lock()
+	cl.compactWindow()
+	cl.mutex.Unlock()
+
+	logger.Debugf("Initialized to block height %d with hash %x, keeping blocks from %d", cl.height-1, cl.lastHash, cl.oldest)
+	return cl, nil
+}
+
+// ChainIDs returns the chain IDs the factory is aware of
+func (clf *compactedLedgerFactory) ChainIDs() []string {
+	clf.mutex.Lock()
+	defer clf.mutex.Unlock()
+	ids := make([]string, 0, len(clf.ledgers))
+	for key := range clf.ledgers {
+		ids = append(ids, key)
+	}
+	return ids
+}
+
+// Remove removes the directory and the archived blocks of the given chain
+func (clf *compactedLedgerFactory) Remove(chainID string) error {
+	clf.mutex.Lock()
+	defer clf.mutex.Unlock()
+
+	if cl, ok := clf.ledgers[chainID]; ok {
+		cl.stop()
+		delete(clf.ledgers, chainID)
+	}
+	directory := filepath.Join(clf.directory, fmt.Sprintf(chainDirectoryFormatString, chainID))
+	if err := os.RemoveAll(directory); err != nil {
+		return errors.Wrapf(err, "error removing channel %s", chainID)
+	}
+	if clf.archive != nil {
+		return clf.archive.Remove(chainID)
+	}
+	return nil
+}
+
+// Close stops the archiving of the blocks of all the chains
+func (clf *compactedLedgerFactory) Close() {
+	clf.mutex.Lock()
+	defer clf.mutex.Unlock()
+	for _, cl := range clf.ledgers {
+		cl.stop()
+	}
+}
+
+// New creates a new ledger factory which keeps the given number of most recent
+// blocks of each chain, along with its latest config block, in the directory,
+// and moves older blocks to the archive. The older blocks are discarded if the
+// archive is nil.
+func New(directory string, retention uint64, archive Archive) blockledger.Factory {
+	logger.Debugf("Initializing ledger at: %s", directory)
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		logger.Panicf("Could not create directory %s: %s", directory, err)
+	}
+	if retention == 0 {
+		logger.Panicf("The number of blocks to retain must be positive")
+	}
+
+	clf := &compactedLedgerFactory{
+		directory: directory,
+		retention: retention,
+		archive:   archive,
+		ledgers:   make(map[string]*compactedLedger),
+	}
+
+	infos, err := ioutil.ReadDir(clf.directory)
+	if err != nil {
+		logger.Panicf("Error reading from directory %s while initializing ledger: %s", clf.directory, err)
+	}
+
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		var chainID string
+		_, err := fmt.Sscanf(info.Name(), chainDirectoryFormatString, &chainID)
+		if err != nil {
+			continue
+		}
+		if _, err := clf.GetOrCreate(chainID); err != nil {
+			logger.Panicf("Error initializing ledger: %s", err)
+		}
+	}
+
+	return clf
+}
@@ -0,0 +1,347 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compactedledger
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("common.ledger.blockledger.compacted")
+
+const (
+	blockFileFormatString      = "block_%020d.pb"
+	chainDirectoryFormatString = "chain_%s"
+
+	// archiveQueueSize is the number of blocks which may wait to be compacted.
+	archiveQueueSize = 64
+)
+
+// archiveRetryInterval is the time to wait before compacting again a block
+// which could not be archived.
+var archiveRetryInterval = 5 * time.Second
+
+type cursor struct {
+	cl          *compactedLedger
+	blockNumber uint64
+}
+
+// compactedLedger keeps the most recent blocks of a chain, along with its
+// latest config block, in a directory, and moves the older blocks to an
+// archive.
+type compactedLedger struct {
+	chainID   string
+	directory string
+	retention uint64
+	archive   Archive
+
+	mutex      sync.Mutex
+	height     uint64
+	lastHash   []byte
+	lastConfig uint64
+	// oldest is the number of the oldest block which has not been
+	// scheduled for compaction, besides the latest config block.
+	oldest uint64
+	// released holds the config blocks which are no longer the latest
+	// one and have not been scheduled for compaction yet.
+	released []uint64
+	signal   chan struct{}
+
+	// The blocks are compacted by a background worker, which receives
+	// them from the queue, so that appending a block never waits for
+	// the archive.
+	queue    chan uint64
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// Next blocks until there is a new block available, or returns an error if the
+// next block is no longer retrievable
+func (cu *cursor) Next() (*cb.Block, cb.Status) {
+	for {
+		cu.cl.mutex.Lock()
+		height := cu.cl.height
+		signal := cu.cl.signal
+		cu.cl.mutex.Unlock()
+
+		if cu.blockNumber < height {
+			break
+		}
+		<-signal
+	}
+
+	block, err := cu.cl.block(cu.blockNumber)
+	if err != nil {
+		logger.Warningf("[channel: %s] Could not retrieve block %d: %s", cu.cl.chainID, cu.blockNumber, err)
+		return nil, cb.Status_NOT_FOUND
+	}
+	cu.blockNumber++
+	return block, cb.Status_SUCCESS
+}
+
+func (cu *cursor) Close() {}
+
+// Iterator returns an Iterator, as specified by a ab.SeekInfo message, and its
+// starting block number
+func (cl *compactedLedger) Iterator(startPosition *ab.SeekPosition) (blockledger.Iterator, uint64) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	switch start := startPosition.Type.(type) {
+	case *ab.SeekPosition_Oldest:
+		oldest := uint64(0)
+		if cl.archive == nil {
+			oldest = cl.oldest
+		}
+		return &cursor{cl: cl, blockNumber: oldest}, oldest
+	case *ab.SeekPosition_Newest:
+		newest := cl.height - 1
+		return &cursor{cl: cl, blockNumber: newest}, newest
+	case *ab.SeekPosition_Specified:
+		if start.Specified.Number > cl.height {
+			return &blockledger.NotFoundErrorIterator{}, 0
+		}
+		return &cursor{cl: cl, blockNumber: start.Specified.Number}, start.Specified.Number
+	default:
+		return &blockledger.NotFoundErrorIterator{}, 0
+	}
+}
+
+// Height returns the number of blocks on the ledger
+func (cl *compactedLedger) Height() uint64 {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	return cl.height
+}
+
+// Append appends a new block to the ledger, and schedules the archiving
+// of the blocks which fall out of the retention window
+func (cl *compactedLedger) Append(block *cb.Block) error {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	if block.Header.Number != cl.height {
+		return errors.Errorf("block number should have been %d but was %d", cl.height, block.Header.Number)
+	}
+
+	if !bytes.Equal(block.Header.PreviousHash, cl.lastHash) {
+		return errors.Errorf("block should have had previous hash of %x but was %x", cl.lastHash, block.Header.PreviousHash)
+	}
+
+	if err := writeBlock(cl.directory, block); err != nil {
+		return err
+	}
+	cl.lastHash = block.Header.Hash()
+	cl.height++
+
+	previousConfig := cl.lastConfig
+	cl.lastConfig = lastConfigIndex(block, cl.lastConfig)
+	if previousConfig != cl.lastConfig && previousConfig < cl.oldest {
+		cl.released = append(cl.released, previousConfig)
+	}
+	cl.compactWindow()
+
+	close(cl.signal)
+	cl.signal = make(chan struct{})
+	return nil
+}
+
+// compactWindow schedules the compaction of the released config blocks and of
+// the blocks which fall out of the retention window, except the latest config
+// block. The blocks which do not fit in the queue are scheduled again after the
+// next block is appended.
+func (cl *compactedLedger) compactWindow() {
+	for len(cl.released) > 0 {
+		if !cl.schedule(cl.released[0]) {
+			return
+		}
+		cl.released = cl.released[1:]
+	}
+	for cl.height-cl.oldest > cl.retention {
+		if cl.oldest != cl.lastConfig && !cl.schedule(cl.oldest) {
+			return
+		}
+		cl.oldest++
+	}
+}
+
+// schedule queues a block for compaction, and returns
+// false if the queue is full.
+func (cl *compactedLedger) schedule(number uint64) bool {
+	select {
+	case cl.queue <- number:
+		return true
+	default:
+		logger.Debugf("[channel: %s] Archive queue is full, deferring block %d", cl.chainID, number)
+		return false
+	}
+}
+
+// archiveBlocks compacts the blocks received from the queue until the ledger
+// is stopped. A block which cannot be archived is kept in the directory and
+// compacted again after a while, before the blocks queued after it.
+func (cl *compactedLedger) archiveBlocks() {
+	defer close(cl.stopped)
+	for {
+		select {
+		case number := <-cl.queue:
+			for {
+				err := cl.compact(number)
+				if err == nil {
+					break
+				}
+				logger.Warningf("[channel: %s] Failed archiving block %d, retrying in %s: %s", cl.chainID, number, archiveRetryInterval, err)
+				select {
+				case <-time.After(archiveRetryInterval):
+				case <-cl.done:
+					return
+				}
+			}
+		case <-cl.done:
+			return
+		}
+	}
+}
+
+// stop stops the compaction of the blocks of the ledger. The blocks which
+// are still queued are kept in the directory, and scheduled again when the
+// ledger is reinitialized.
+func (cl *compactedLedger) stop() {
+	cl.stopOnce.Do(func() {
+		close(cl.done)
+	})
+	<-cl.stopped
+}
+
+// compact moves a block from the directory of the ledger to the archive, if any.
+func (cl *compactedLedger) compact(number uint64) error {
+	if cl.archive != nil {
+		block, err := readBlock(cl.directory, number)
+		if err != nil {
+			return err
+		}
+		if err := cl.archive.Put(cl.chainID, block); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(blockFilename(cl.directory, number)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing block %d", number)
+	}
+	logger.Debugf("[channel: %s] Compacted block %d", cl.chainID, number)
+	return nil
+}
+
+// block returns the block with the given number from the
+// directory of the ledger, or from the archive.
+func (cl *compactedLedger) block(number uint64) (*cb.Block, error) {
+	block, err := readBlock(cl.directory, number)
+	if err == nil || !os.IsNotExist(errors.Cause(err)) {
+		return block, err
+	}
+	if cl.archive == nil {
+		return nil, errors.Errorf("block %d has been compacted", number)
+	}
+	return cl.archive.Get(cl.chainID, number)
+}
+
+// lastConfigIndex returns the index of the last config block as encoded in the
+// block metadata, or the given index if the block does not encode it.
+func lastConfigIndex(block *cb.Block, index uint64) uint64 {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_LAST_CONFIG) {
+		return index
+	}
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return index
+	}
+	return lastConfig
+}
+
+// readBlock reads the block with the given number from a directory.
+func readBlock(directory string, number uint64) (*cb.Block, error) {
+	data, err := ioutil.ReadFile(blockFilename(directory, number))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading block %d", number)
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(data, block); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling block %d", number)
+	}
+	return block, nil
+}
+
+// writeBlock writes a block to a directory, replacing the previous
+// copy of the block, if any, atomically and durably.
+func writeBlock(directory string, block *cb.Block) error {
+	// Marshal a copy of the block, since marshaling caches
+	// the sizes of its messages and the block may be shared.
+	data, err := proto.Marshal(proto.Clone(block))
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling block %d", block.Header.Number)
+	}
+
+	name := blockFilename(directory, block.Header.Number)
+	if err := writeFileSync(name+".tmp", data); err != nil {
+		return errors.Wrapf(err, "error writing block %d", block.Header.Number)
+	}
+	if err := os.Rename(name+".tmp", name); err != nil {
+		return errors.Wrapf(err, "error writing block %d", block.Header.Number)
+	}
+	if err := syncDir(directory); err != nil {
+		return errors.Wrapf(err, "error writing block %d", block.Header.Number)
+	}
+	logger.Debugf("Wrote block %d", block.Header.Number)
+	return nil
+}
+
+// writeFileSync writes data to a file, and flushes it to disk.
+func writeFileSync(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir flushes the entries of a directory, such as a renamed file, to disk.
+func syncDir(directory string) error {
+	dir, err := os.Open(directory)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// blockFilename returns the fully qualified path to where a block
+// of a given number is stored in a directory
+func blockFilename(directory string, number uint64) string {
+	return filepath.Join(directory, fmt.Sprintf(blockFileFormatString, number))
+}
@@ -0,0 +1,220 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compactedledger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingArchive struct {
+	Archive
+	mutex    sync.Mutex
+	err      error
+	failures int
+}
+
+func (fa *failingArchive) Put(chainID string, block *cb.Block) error {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+	if fa.err != nil {
+		fa.failures++
+		return fa.err
+	}
+	return fa.Archive.Put(chainID, block)
+}
+
+func (fa *failingArchive) fail(err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+	fa.err = err
+	fa.failures = 0
+}
+
+func (fa *failingArchive) failed() bool {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+	return fa.failures > 0
+}
+
+// eventually waits for a condition to hold, since the
+// blocks are compacted in the background.
+func eventually(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// assertBlocks asserts that the directory eventually holds the given blocks.
+func assertBlocks(t *testing.T, expected []uint64, directory string) {
+	eventually(t, func() bool { return assert.ObjectsAreEqual(expected, localBlocks(t, directory)) })
+	assert.Equal(t, expected, localBlocks(t, directory))
+}
+
+// appendBlocks appends blocks to the ledger, where the blocks with the
+// given numbers are config blocks.
+func appendBlocks(t *testing.T, rw blockledger.ReadWriter, count int, configBlocks ...uint64) {
+	for i := 0; i < count; i++ {
+		block := blockledger.CreateNextBlock(rw, []*cb.Envelope{{Payload: []byte("tx")}})
+		lastConfig := uint64(0)
+		for _, number := range configBlocks {
+			if number <= block.Header.Number {
+				lastConfig = number
+			}
+		}
+		block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+			Value: utils.MarshalOrPanic(&cb.LastConfig{Index: lastConfig}),
+		})
+		require.NoError(t, rw.Append(block))
+	}
+}
+
+func localBlocks(t *testing.T, directory string) []uint64 {
+	infos, err := ioutil.ReadDir(filepath.Join(directory, "chain_mychannel"))
+	require.NoError(t, err)
+	var numbers []uint64
+	for _, info := range infos {
+		var number uint64
+		if _, err := fmt.Sscanf(info.Name(), blockFileFormatString, &number); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+func readAll(t *testing.T, rw blockledger.ReadWriter, start uint64) []uint64 {
+	it, _ := rw.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: start}}})
+	defer it.Close()
+	var numbers []uint64
+	for number := start; number < rw.Height(); number++ {
+		block, status := it.Next()
+		if status != cb.Status_SUCCESS {
+			break
+		}
+		numbers = append(numbers, block.Header.Number)
+	}
+	return numbers
+}
+
+func TestCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compactedledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(interval time.Duration) { archiveRetryInterval = interval }(archiveRetryInterval)
+	archiveRetryInterval = 10 * time.Millisecond
+
+	archive, err := NewDirArchive(filepath.Join(dir, "archive"))
+	require.NoError(t, err)
+	failing := &failingArchive{Archive: archive}
+	clf := New(filepath.Join(dir, "ledger"), 3, failing)
+	defer clf.Close()
+	rw, err := clf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+
+	appendBlocks(t, rw, 8, 0, 2)
+	assert.Equal(t, uint64(8), rw.Height())
+	assertBlocks(t, []uint64{2, 5, 6, 7}, filepath.Join(dir, "ledger"))
+	assert.Equal(t, []uint64{0, 1, 3, 4}, localBlocks(t, filepath.Join(dir, "archive")))
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4, 5, 6, 7}, readAll(t, rw, 0))
+
+	// A new config block releases the previous one.
+	appendBlocks(t, rw, 1, 0, 2, 8)
+	assertBlocks(t, []uint64{6, 7, 8}, filepath.Join(dir, "ledger"))
+
+	// Blocks which cannot be archived are kept until they are.
+	failing.fail(errors.New("unavailable"))
+	appendBlocks(t, rw, 2, 0, 2, 8)
+	eventually(t, failing.failed)
+	assert.True(t, failing.failed())
+	assert.Equal(t, []uint64{6, 7, 8, 9, 10}, localBlocks(t, filepath.Join(dir, "ledger")))
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, readAll(t, rw, 0))
+	failing.fail(nil)
+	assertBlocks(t, []uint64{8, 9, 10}, filepath.Join(dir, "ledger"))
+	appendBlocks(t, rw, 1, 0, 2, 8)
+	assertBlocks(t, []uint64{8, 9, 10, 11}, filepath.Join(dir, "ledger"))
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, readAll(t, rw, 0))
+
+	it, number := rw.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	assert.Equal(t, uint64(0), number)
+	it.Close()
+
+	require.NoError(t, clf.Remove("mychannel"))
+	_, err = os.Stat(filepath.Join(dir, "archive", "chain_mychannel"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCompactionWithoutArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compactedledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clf := New(dir, 2, nil)
+	defer clf.Close()
+	rw, err := clf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	appendBlocks(t, rw, 5, 0)
+	assertBlocks(t, []uint64{0, 3, 4}, dir)
+
+	it, number := rw.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	assert.Equal(t, uint64(3), number)
+	block, status := it.Next()
+	assert.Equal(t, cb.Status_SUCCESS, status)
+	assert.Equal(t, uint64(3), block.Header.Number)
+
+	assert.Equal(t, []uint64{0}, readAll(t, rw, 0))
+	assert.Nil(t, blockledger.GetBlock(rw, 1))
+}
+
+func TestReinitialization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compactedledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	archive, err := NewDirArchive(filepath.Join(dir, "archive"))
+	require.NoError(t, err)
+	clf := New(filepath.Join(dir, "ledger"), 3, archive)
+	rw, err := clf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	appendBlocks(t, rw, 6, 0, 1)
+	assertBlocks(t, []uint64{1, 3, 4, 5}, filepath.Join(dir, "ledger"))
+	clf.Close()
+
+	// A compaction which was interrupted before the block was archived.
+	chainDir := filepath.Join(dir, "ledger", "chain_mychannel")
+	block, err := archive.Get("mychannel", 2)
+	require.NoError(t, err)
+	require.NoError(t, writeBlock(chainDir, block))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "archive", "chain_mychannel")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(chainDir, "block_00000000000000000006.pb.tmp"), []byte("partial"), 0600))
+
+	clf = New(filepath.Join(dir, "ledger"), 3, archive)
+	defer clf.Close()
+	rw, err = clf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), rw.Height())
+	assertBlocks(t, []uint64{1, 3, 4, 5}, filepath.Join(dir, "ledger"))
+	assert.Equal(t, []uint64{2}, localBlocks(t, filepath.Join(dir, "archive")))
+
+	appendBlocks(t, rw, 1, 0, 1)
+	assertBlocks(t, []uint64{1, 4, 5, 6}, filepath.Join(dir, "ledger"))
+	assert.Equal(t, uint64(7), rw.Height())
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockledger_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/hyperledger/fabric/common/ledger/blockledger"
+	compactedledger "github.com/hyperledger/fabric/common/ledger/blockledger/compacted"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+)
+
+func init() {
+	testables = append(testables, &compactedLedgerTestEnv{})
+}
+
+type compactedLedgerTestFactory struct {
+	location string
+}
+
+type compactedLedgerTestEnv struct {
+}
+
+func (env *compactedLedgerTestEnv) Initialize() (ledgerTestFactory, error) {
+	location, err := ioutil.TempDir("", "hyperledger")
+	if err != nil {
+		return nil, err
+	}
+	return &compactedLedgerTestFactory{location: location}, nil
+}
+
+func (env *compactedLedgerTestEnv) Name() string {
+	return "compactedledger"
+}
+
+func (env *compactedLedgerTestFactory) Destroy() error {
+	err := os.RemoveAll(env.location)
+	return err
+}
+
+func (env *compactedLedgerTestFactory) Persistent() bool {
+	return true
+}
+
+func (env *compactedLedgerTestFactory) New() (Factory, ReadWriter) {
+	archive, err := compactedledger.NewDirArchive(filepath.Join(env.location, "archive"))
+	if err != nil {
+		panic(err)
+	}
+	clf := compactedledger.New(filepath.Join(env.location, "ledger"), 1, archive)
+	cl, err := clf.GetOrCreate(genesisconfig.TestChainID)
+	if err != nil {
+		panic(err)
+	}
+	if cl.Height() == 0 {
+		if err = cl.Append(genesisBlock); err != nil {
+			panic(err)
+		}
+	}
+	return clf, cl
+}
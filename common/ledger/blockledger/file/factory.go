@@ -61,6 +61,22 @@ func (flf *fileLedgerFactory) ChainIDs() []string {
 	return chainIDs
 }
 
+// Remove shuts down the block store of the given chain and removes it
+func (flf *fileLedgerFactory) Remove(chainID string) error {
+	flf.mutex.Lock()
+	defer flf.mutex.Unlock()
+
+	if ledger, ok := flf.ledgers[chainID]; ok {
+		if fl, ok := ledger.(*FileLedger); ok {
+			if store, ok := fl.blockStore.(blkstorage.BlockStore); ok {
+				store.Shutdown()
+			}
+		}
+		delete(flf.ledgers, chainID)
+	}
+	return flf.blkstorageProvider.Remove(chainID)
+}
+
 // Close releases all resources acquired by the factory
 func (flf *fileLedgerFactory) Close() {
 	flf.blkstorageProvider.Close()
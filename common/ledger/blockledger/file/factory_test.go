@@ -19,12 +19,14 @@ package fileledger
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +45,11 @@ func (mbsp *mockBlockStoreProvider) OpenBlockStore(ledgerid string) (blkstorage.
 	return mbsp.blockstore, mbsp.error
 }
 
+func (mbsp *mockBlockStoreProvider) BootstrapFromSnapshot(ledgerid string, snapshotInfo *blkstorage.SnapshotInfo,
+	txIDs blkstorage.TxIDInfoIterator) (blkstorage.BlockStore, error) {
+	return mbsp.blockstore, mbsp.error
+}
+
 func (mbsp *mockBlockStoreProvider) Exists(ledgerid string) (bool, error) {
 	return mbsp.exists, mbsp.error
 }
@@ -51,6 +58,10 @@ func (mbsp *mockBlockStoreProvider) List() ([]string, error) {
 	return mbsp.list, mbsp.error
 }
 
+func (mbsp *mockBlockStoreProvider) Remove(ledgerid string) error {
+	return mbsp.error
+}
+
 func (mbsp *mockBlockStoreProvider) Close() {
 }
 
@@ -93,3 +104,32 @@ func TestMultiReinitialization(t *testing.T) {
 	assert.Equal(t, 3, len(flf.ChainIDs()), "Expected chain to be recovered")
 	flf.Close()
 }
+
+func TestRemove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.NoError(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(dir)
+
+	flf := New(dir, &disabled.Provider{})
+	defer flf.Close()
+	for _, chainID := range []string{"foo", "bar"} {
+		fl, err := flf.GetOrCreate(chainID)
+		assert.NoError(t, err, "Error creating chain")
+		assert.NoError(t, fl.Append(blockledger.CreateNextBlock(fl, []*cb.Envelope{{Payload: []byte("tx")}})))
+	}
+
+	assert.NoError(t, flf.Remove("foo"))
+	assert.Equal(t, []string{"bar"}, flf.ChainIDs())
+	fl, err := flf.GetOrCreate("foo")
+	assert.NoError(t, err, "Error creating chain")
+	assert.Zero(t, fl.Height(), "Expected the removed chain to be recreated empty")
+	fl, err = flf.GetOrCreate("bar")
+	assert.NoError(t, err, "Error retrieving chain")
+	assert.Equal(t, uint64(1), fl.Height())
+
+	flf = &fileLedgerFactory{
+		blkstorageProvider: &mockBlockStoreProvider{error: fmt.Errorf("blockstorage provider error")},
+		ledgers:            make(map[string]blockledger.ReadWriter),
+	}
+	assert.EqualError(t, flf.Remove("foo"), "blockstorage provider error")
+}
@@ -113,6 +113,19 @@ func (jlf *jsonLedgerFactory) ChainIDs() []string {
 	return ids
 }
 
+// Remove removes the directory of the given chain
+func (jlf *jsonLedgerFactory) Remove(chainID string) error {
+	jlf.mutex.Lock()
+	defer jlf.mutex.Unlock()
+
+	delete(jlf.ledgers, chainID)
+	directory := filepath.Join(jlf.directory, fmt.Sprintf(chainDirectoryFormatString, chainID))
+	if err := os.RemoveAll(directory); err != nil {
+		return errors.Wrapf(err, "error removing channel %s", chainID)
+	}
+	return nil
+}
+
 // Close is a no-op for the JSON ledger
 func (jlf *jsonLedgerFactory) Close() {
 	return // nothing to do
@@ -23,6 +23,8 @@ import (
 	"path"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -114,3 +116,21 @@ func TestClose(t *testing.T) {
 	jlf := New(name)
 	assert.NotPanics(t, func() { jlf.Close() }, "Noop should not pannic")
 }
+
+func TestRemove(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.Nil(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(name)
+
+	jlf := New(name)
+	for _, chainID := range []string{"foo", "bar"} {
+		jl, err := jlf.GetOrCreate(chainID)
+		assert.NoError(t, err, "Error creating chain")
+		assert.NoError(t, jl.Append(blockledger.CreateNextBlock(jl, []*cb.Envelope{{Payload: []byte("tx")}})))
+	}
+
+	assert.NoError(t, jlf.Remove("foo"))
+	assert.Equal(t, []string{"bar"}, jlf.ChainIDs())
+	jlf = New(name)
+	assert.Equal(t, []string{"bar"}, jlf.ChainIDs(), "Expected the removed chain not to be recovered")
+}
@@ -20,6 +20,10 @@ type Factory interface {
 	// ChainIDs returns the chain IDs the Factory is aware of
 	ChainIDs() []string
 
+	// Remove removes the ledger of the given chain. The ledger must
+	// not be in use, and it is recreated empty by a later GetOrCreate
+	Remove(chainID string) error
+
 	// Close releases all resources acquired by the factory
 	Close()
 }
@@ -47,3 +47,19 @@ func TestChainIDs(t *testing.T) {
 	}
 	rlf.Close()
 }
+
+func TestRemove(t *testing.T) {
+	rlf := New(3)
+	channel, _ := rlf.GetOrCreate("channel1")
+	rlf.GetOrCreate("channel2")
+	if err := rlf.Remove("channel1"); err != nil {
+		t.Fatalf("Unexpected error removing channel: %s", err)
+	}
+	if len(rlf.ChainIDs()) != 1 || rlf.ChainIDs()[0] != "channel2" {
+		t.Fatalf("Expecting only channel2, got %v", rlf.ChainIDs())
+	}
+	channel2, _ := rlf.GetOrCreate("channel1")
+	if channel == channel2 {
+		t.Fatalf("Expecting the removed channel to be recreated")
+	}
+}
@@ -11,6 +11,10 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/common/util"
 	lutils "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
@@ -25,6 +29,7 @@ type BlockGenerator struct {
 	blockNum     uint64
 	previousHash []byte
 	signTxs      bool
+	ledgerID     string
 	t            *testing.T
 }
 
@@ -45,7 +50,7 @@ func NewBlockGenerator(t *testing.T, ledgerID string, signTxs bool) (*BlockGener
 	gb, err := test.MakeGenesisBlock(ledgerID)
 	assert.NoError(t, err)
 	gb.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = lutils.NewTxValidationFlagsSetValue(len(gb.Data.Data), pb.TxValidationCode_VALID)
-	return &BlockGenerator{1, gb.GetHeader().Hash(), signTxs, t}, gb
+	return &BlockGenerator{1, gb.GetHeader().Hash(), signTxs, ledgerID, t}, gb
 }
 
 // NextBlock constructs next block in sequence that includes a number of transactions - one per simulationResults
@@ -56,6 +61,14 @@ func (bg *BlockGenerator) NextBlock(simulationResults [][]byte) *common.Block {
 	return block
 }
 
+// NextConfigBlock constructs next block in sequence that carries a config transaction setting the given application capabilities
+func (bg *BlockGenerator) NextConfigBlock(applicationCapabilities ...string) *common.Block {
+	block := ConstructConfigBlock(bg.t, bg.blockNum, bg.previousHash, bg.ledgerID, applicationCapabilities...)
+	bg.blockNum++
+	bg.previousHash = block.Header.Hash()
+	return block
+}
+
 // NextBlockWithTxid constructs next block in sequence that includes a number of transactions - one per simulationResults
 func (bg *BlockGenerator) NextBlockWithTxid(simulationResults [][]byte, txids []string) *common.Block {
 	// Length of simulationResults should be same as the length of txids.
@@ -155,6 +168,20 @@ func ConstructBlock(t *testing.T, blockNum uint64, previousHash []byte, simulati
 	return NewBlock(envs, blockNum, previousHash)
 }
 
+// ConstructConfigBlock constructs a block that carries a config transaction with the test channel config
+// and the given application capabilities set in addition to those of the test config
+func ConstructConfigBlock(t *testing.T, blockNum uint64, previousHash []byte, ledgerID string, applicationCapabilities ...string) *common.Block {
+	profile := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
+	for _, capability := range applicationCapabilities {
+		profile.Application.Capabilities[capability] = true
+	}
+	channelGroup, err := encoder.NewChannelGroup(profile)
+	assert.NoError(t, err)
+	env, err := utils.ExtractEnvelope(genesis.NewFactoryImpl(channelGroup).Block(ledgerID), 0)
+	assert.NoError(t, err)
+	return NewBlock([]*common.Envelope{env}, blockNum, previousHash)
+}
+
 //ConstructTestBlock constructs a single block with random contents
 func ConstructTestBlock(t *testing.T, blockNum uint64, numTx int, txSize int) *common.Block {
 	simulationResults := [][]byte{}
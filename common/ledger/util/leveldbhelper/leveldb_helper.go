@@ -32,6 +32,8 @@ const (
 // Conf configuration for `DB`
 type Conf struct {
 	DBPath string
+	// Options tunes the underlying goleveldb engine. The goleveldb defaults are used if nil
+	Options *opt.Options
 }
 
 // DB - a wrapper on an actual store
@@ -69,6 +71,10 @@ func (dbInst *DB) Open() {
 		return
 	}
 	dbOpts := &opt.Options{}
+	if dbInst.conf.Options != nil {
+		opts := *dbInst.conf.Options
+		dbOpts = &opts
+	}
 	dbPath := dbInst.conf.DBPath
 	var err error
 	var dirEmpty bool
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -159,7 +160,7 @@ func TestFileLock(t *testing.T) {
 func TestCreateDBInEmptyDir(t *testing.T) {
 	assert.NoError(t, os.RemoveAll(testDBPath), "")
 	assert.NoError(t, os.MkdirAll(testDBPath, 0775), "")
-	db := CreateDB(&Conf{testDBPath})
+	db := CreateDB(&Conf{DBPath: testDBPath})
 	defer db.Close()
 	defer func() {
 		if r := recover(); r != nil {
@@ -175,7 +176,7 @@ func TestCreateDBInNonEmptyDir(t *testing.T) {
 	file, err := os.Create(filepath.Join(testDBPath, "dummyfile.txt"))
 	assert.NoError(t, err, "")
 	file.Close()
-	db := CreateDB(&Conf{testDBPath})
+	db := CreateDB(&Conf{DBPath: testDBPath})
 	defer db.Close()
 	defer func() {
 		if r := recover(); r == nil {
@@ -184,3 +185,18 @@ func TestCreateDBInNonEmptyDir(t *testing.T) {
 	}()
 	db.Open()
 }
+
+func TestCreateDBWithOptions(t *testing.T) {
+	assert.NoError(t, os.RemoveAll(testDBPath), "")
+	defer os.RemoveAll(testDBPath)
+	options := &opt.Options{WriteBuffer: 8 * opt.MiB}
+	db := CreateDB(&Conf{DBPath: testDBPath, Options: options})
+	db.Open()
+	defer db.Close()
+	assert.NoError(t, db.Put([]byte("key1"), []byte("value1"), true))
+	val, err := db.Get([]byte("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), val)
+	// the options supplied in the conf are not modified while opening the db
+	assert.Equal(t, &opt.Options{WriteBuffer: 8 * opt.MiB}, options)
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"sync"
 
+	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
@@ -27,6 +28,9 @@ import (
 var dbNameKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 
+// maxDeleteBatchSize is the number of keys that are deleted in a single batch by DBHandle.DeleteAll
+var maxDeleteBatchSize = 1000
+
 // Provider enables to use a single leveldb as multiple logical leveldbs
 type Provider struct {
 	db        *DB
@@ -99,6 +103,32 @@ func (h *DBHandle) WriteBatch(batch *UpdateBatch, sync bool) error {
 	return nil
 }
 
+// DeleteAll deletes all the keys that belong to the db. The keys are deleted in batches of
+// `maxDeleteBatchSize` so that dropping a large db does not accumulate all its keys in memory
+func (h *DBHandle) DeleteAll() error {
+	itr := h.GetIterator(nil, nil)
+	defer itr.Release()
+	batch := &leveldb.Batch{}
+	for itr.Next() {
+		// the level key is copied as the iterator reuses the underlying buffer
+		batch.Delete(append([]byte{}, itr.Iterator.Key()...))
+		if batch.Len() < maxDeleteBatchSize {
+			continue
+		}
+		if err := h.db.WriteBatch(batch, true); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrapf(err, "internal leveldb error while iterating over the db [%s]", h.dbName)
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	return h.db.WriteBatch(batch, true)
+}
+
 // GetIterator gets an handle to iterator. The iterator should be released after the use.
 // The resultset contains all the keys that are present in the db between the startKey (inclusive) and the endKey (exclusive).
 // A nil startKey represents the first available key and a nil endKey represent a logical key after the last available key
@@ -90,6 +90,31 @@ func TestBatchedUpdates(t *testing.T) {
 	}
 }
 
+func TestDeleteAll(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	p := env.provider
+
+	defer func(size int) { maxDeleteBatchSize = size }(maxDeleteBatchSize)
+	maxDeleteBatchSize = 3
+
+	db1 := p.GetDBHandle("db1")
+	db10 := p.GetDBHandle("db10")
+	for i := 0; i < 10; i++ {
+		db1.Put([]byte(createTestKey(i)), []byte(createTestValue("db1", i)), false)
+		db10.Put([]byte(createTestKey(i)), []byte(createTestValue("db10", i)), false)
+	}
+
+	assert.NoError(t, db1.DeleteAll())
+	itr := db1.GetIterator(nil, nil)
+	defer itr.Release()
+	assert.False(t, itr.Next())
+	checkItrResults(t, db10.GetIterator(nil, nil), createTestKeys(0, 9), createTestValues("db10", 0, 9))
+
+	// deleting an empty db is a no-op
+	assert.NoError(t, db1.DeleteAll())
+}
+
 func testDBBasicWriteAndReads(t *testing.T, dbNames ...string) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
@@ -48,6 +48,7 @@ type MockApplicationCapabilities struct {
 	V1_3ValidationRv             bool
 	FabTokenRv                   bool
 	StorePvtDataOfInvalidTxRv    bool
+	LifecycleApprovalRv          bool
 }
 
 func (mac *MockApplicationCapabilities) Supported() error {
@@ -97,3 +98,7 @@ func (mac *MockApplicationCapabilities) FabToken() bool {
 func (mac *MockApplicationCapabilities) StorePvtDataOfInvalidTx() bool {
 	return mac.StorePvtDataOfInvalidTxRv
 }
+
+func (mac *MockApplicationCapabilities) LifecycleApproval() bool {
+	return mac.LifecycleApprovalRv
+}
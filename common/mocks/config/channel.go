@@ -78,6 +78,12 @@ func (cc *ChannelCapabilities) OrgSpecificOrdererEndpoints() bool {
 	return false
 }
 
+func (cc *ChannelCapabilities) AdaptiveBatchCutting() bool {
+	// refusing to extend this bespoke mock
+	// If you want to override this return value, generate your own mock..
+	return false
+}
+
 // Supported returns SupportedErr
 func (cc *ChannelCapabilities) Supported() error {
 	return cc.SupportedErr
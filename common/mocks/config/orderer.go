@@ -26,6 +26,10 @@ type Orderer struct {
 	BatchSizeVal *ab.BatchSize
 	// BatchTimeoutVal is returned as the result of BatchTimeout()
 	BatchTimeoutVal time.Duration
+	// BatchCuttingModeVal is returned as the result of BatchCuttingMode()
+	BatchCuttingModeVal ab.BatchCutting_Mode
+	// TargetLatencyVal is returned as the result of TargetLatency()
+	TargetLatencyVal time.Duration
 	// KafkaBrokersVal is returned as the result of KafkaBrokers()
 	KafkaBrokersVal []string
 	// MaxChannelsCountVal is returns as the result of MaxChannelsCount()
@@ -61,6 +65,16 @@ func (o *Orderer) BatchTimeout() time.Duration {
 	return o.BatchTimeoutVal
 }
 
+// BatchCuttingMode returns the BatchCuttingModeVal
+func (o *Orderer) BatchCuttingMode() ab.BatchCutting_Mode {
+	return o.BatchCuttingModeVal
+}
+
+// TargetLatency returns the TargetLatencyVal
+func (o *Orderer) TargetLatency() time.Duration {
+	return o.TargetLatencyVal
+}
+
 // KafkaBrokers returns the KafkaBrokersVal
 func (o *Orderer) KafkaBrokers() []string {
 	return o.KafkaBrokersVal
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package encoder
 
 import (
+	"strings"
+
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -18,6 +20,9 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -202,6 +207,10 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		conf.BatchSize.PreferredMaxBytes,
 	), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.BatchTimeoutValue(conf.BatchTimeout.String()), channelconfig.AdminsPolicyKey)
+	if conf.BatchCutting.Mode != "" {
+		mode := ab.BatchCutting_Mode(ab.BatchCutting_Mode_value[strings.ToUpper(conf.BatchCutting.Mode)])
+		addValue(ordererGroup, channelconfig.BatchCuttingValue(mode, conf.BatchCutting.TargetLatency.String()), channelconfig.AdminsPolicyKey)
+	}
 	addValue(ordererGroup, channelconfig.ChannelRestrictionsValue(conf.MaxChannels), channelconfig.AdminsPolicyKey)
 
 	if len(conf.Capabilities) > 0 {
@@ -219,6 +228,19 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		if consensusMetadata, err = etcdraft.Marshal(conf.EtcdRaft); err != nil {
 			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", etcdraft.TypeKey, err)
 		}
+	case bft.TypeKey:
+		if consensusMetadata, err = bft.Marshal(conf.Bft); err != nil {
+			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", bft.TypeKey, err)
+		}
+		// Blocks are only valid if a quorum of the consenters signed them.
+		blockValidationPolicy, err := bftBlockValidationPolicy(consensusMetadata)
+		if err != nil {
+			return nil, err
+		}
+		ordererGroup.Policies[BlockValidationPolicyKey] = &cb.ConfigPolicy{
+			Policy:    blockValidationPolicy,
+			ModPolicy: channelconfig.AdminsPolicyKey,
+		}
 	default:
 		return nil, errors.Errorf("unknown orderer type: %s", conf.OrdererType)
 	}
@@ -237,6 +259,28 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 	return ordererGroup, nil
 }
 
+// bftBlockValidationPolicy returns a policy which requires the signatures of
+// a quorum of the consenters of the given bft metadata.
+func bftBlockValidationPolicy(consensusMetadata []byte) (*cb.Policy, error) {
+	m := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusMetadata, m); err != nil {
+		return nil, errors.Wrapf(err, "cannot unmarshal metadata for orderer type %s", bft.TypeKey)
+	}
+
+	identities := make([][]byte, len(m.Consenters))
+	signedBy := make([]*cb.SignaturePolicy, len(m.Consenters))
+	for i, consenter := range m.Consenters {
+		identities[i] = utils.MarshalOrPanic(&mspprotos.SerializedIdentity{Mspid: consenter.MspId, IdBytes: consenter.Identity})
+		signedBy[i] = cauthdsl.SignedBy(int32(i))
+	}
+
+	quorum := bft.QuorumSize(len(m.Consenters))
+	return &cb.Policy{
+		Type:  int32(cb.Policy_SIGNATURE),
+		Value: utils.MarshalOrPanic(cauthdsl.Envelope(cauthdsl.NOutOf(int32(quorum), signedBy), identities)),
+	}, nil
+}
+
 // NewConsortiumsGroup returns an org component of the channel configuration.  It defines the crypto material for the
 // organization (its MSP).  It sets the mod_policy of all elements to "Admins".
 func NewConsortiumOrgGroup(conf *genesisconfig.Organization) (*cb.ConfigGroup, error) {
@@ -8,6 +8,10 @@ package encoder_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,6 +24,7 @@ import (
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -229,6 +234,26 @@ var _ = Describe("Encoder", func() {
 			})
 		})
 
+		Context("when batch cutting is adaptive", func() {
+			BeforeEach(func() {
+				conf.BatchCutting = genesisconfig.BatchCutting{
+					Mode:          "adaptive",
+					TargetLatency: 250 * time.Millisecond,
+				}
+			})
+
+			It("adds the batch cutting key", func() {
+				cg, err := encoder.NewOrdererGroup(conf)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(cg.Values)).To(Equal(6))
+				batchCutting := &ab.BatchCutting{}
+				err = proto.Unmarshal(cg.Values["BatchCutting"].Value, batchCutting)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(batchCutting.Mode).To(Equal(ab.BatchCutting_ADAPTIVE))
+				Expect(batchCutting.TargetLatency).To(Equal("250ms"))
+			})
+		})
+
 		Context("when the consensus type is Kafka", func() {
 			BeforeEach(func() {
 				conf.OrdererType = "kafka"
@@ -282,6 +307,77 @@ var _ = Describe("Encoder", func() {
 			})
 		})
 
+		Context("when the consensus type is bft", func() {
+			var tmpDir string
+
+			BeforeEach(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "encoder-bft")
+				Expect(err).NotTo(HaveOccurred())
+
+				conf.OrdererType = "bft"
+				conf.Bft = &bft.ConfigMetadata{
+					Options: &bft.Options{
+						RequestTimeout:    "10s",
+						ViewChangeTimeout: "20s",
+					},
+				}
+				for i := 0; i < 4; i++ {
+					file := filepath.Join(tmpDir, fmt.Sprintf("cert%d", i))
+					Expect(ioutil.WriteFile(file, []byte(fmt.Sprintf("cert%d", i)), 0644)).To(Succeed())
+					conf.Bft.Consenters = append(conf.Bft.Consenters, &bft.Consenter{
+						Host:          fmt.Sprintf("bft%d.example.com", i),
+						Port:          7050,
+						ClientTlsCert: []byte(file),
+						ServerTlsCert: []byte(file),
+						MspId:         "SampleOrg",
+						Identity:      []byte(file),
+					})
+				}
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(tmpDir)
+			})
+
+			It("adds the bft metadata and requires a quorum of consenters to sign blocks", func() {
+				cg, err := encoder.NewOrdererGroup(conf)
+				Expect(err).NotTo(HaveOccurred())
+				consensusType := &ab.ConsensusType{}
+				err = proto.Unmarshal(cg.Values["ConsensusType"].Value, consensusType)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(consensusType.Type).To(Equal("bft"))
+				metadata := &bft.ConfigMetadata{}
+				err = proto.Unmarshal(consensusType.Metadata, metadata)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata.Consenters[1].Identity).To(Equal([]byte("cert1")))
+
+				Expect(cg.Policies["BlockValidation"].Policy.Type).To(Equal(int32(cb.Policy_SIGNATURE)))
+				policy := &cb.SignaturePolicyEnvelope{}
+				err = proto.Unmarshal(cg.Policies["BlockValidation"].Policy.Value, policy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy.Rule.GetNOutOf().N).To(Equal(int32(3)))
+				Expect(policy.Rule.GetNOutOf().Rules).To(HaveLen(4))
+				Expect(policy.Identities).To(HaveLen(4))
+				Expect(policy.Identities[2].PrincipalClassification).To(Equal(msp.MSPPrincipal_IDENTITY))
+				Expect(policy.Identities[2].Principal).To(Equal(utils.MarshalOrPanic(&msp.SerializedIdentity{
+					Mspid:   "SampleOrg",
+					IdBytes: []byte("cert2"),
+				})))
+			})
+
+			Context("when the bft configuration is bad", func() {
+				BeforeEach(func() {
+					conf.Bft.Consenters[0].Identity = nil
+				})
+
+				It("wraps and returns the error", func() {
+					_, err := encoder.NewOrdererGroup(conf)
+					Expect(err).To(MatchError("cannot marshal metadata for orderer type bft: cannot load identity for consenter bft0.example.com:7050: open : no such file or directory"))
+				})
+			})
+		})
+
 		Context("when the consensus type is unknown", func() {
 			BeforeEach(func() {
 				conf.OrdererType = "bad-type"
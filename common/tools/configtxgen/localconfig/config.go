@@ -17,6 +17,8 @@ import (
 	"github.com/hyperledger/fabric/common/viperutil"
 	cf "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/spf13/viper"
 )
@@ -160,8 +162,10 @@ type Orderer struct {
 	Addresses     []string                 `yaml:"Addresses"`
 	BatchTimeout  time.Duration            `yaml:"BatchTimeout"`
 	BatchSize     BatchSize                `yaml:"BatchSize"`
+	BatchCutting  BatchCutting             `yaml:"BatchCutting"`
 	Kafka         Kafka                    `yaml:"Kafka"`
 	EtcdRaft      *etcdraft.ConfigMetadata `yaml:"EtcdRaft"`
+	Bft           *bft.ConfigMetadata      `yaml:"BFT"`
 	Organizations []*Organization          `yaml:"Organizations"`
 	MaxChannels   uint64                   `yaml:"MaxChannels"`
 	Capabilities  map[string]bool          `yaml:"Capabilities"`
@@ -175,6 +179,12 @@ type BatchSize struct {
 	PreferredMaxBytes uint32 `yaml:"PreferredMaxBytes"`
 }
 
+// BatchCutting contains configuration affecting how batches are cut.
+type BatchCutting struct {
+	Mode          string        `yaml:"Mode"`
+	TargetLatency time.Duration `yaml:"TargetLatency"`
+}
+
 // Kafka contains configuration for the Kafka-based orderer.
 type Kafka struct {
 	Brokers []string `yaml:"Brokers"`
@@ -190,6 +200,9 @@ var genesisDefaults = TopLevel{
 			AbsoluteMaxBytes:  10 * 1024 * 1024,
 			PreferredMaxBytes: 2 * 1024 * 1024,
 		},
+		BatchCutting: BatchCutting{
+			TargetLatency: 500 * time.Millisecond,
+		},
 		Kafka: Kafka{
 			Brokers: []string{"127.0.0.1:9092"},
 		},
@@ -202,6 +215,12 @@ var genesisDefaults = TopLevel{
 				SnapshotIntervalSize: 20 * 1024 * 1024, // 20 MB
 			},
 		},
+		Bft: &bft.ConfigMetadata{
+			Options: &bft.Options{
+				RequestTimeout:    "10s",
+				ViewChangeTimeout: "20s",
+			},
+		},
 	},
 }
 
@@ -377,11 +396,18 @@ loop:
 		case ord.BatchSize.PreferredMaxBytes == 0:
 			logger.Infof("Orderer.BatchSize.PreferredMaxBytes unset, setting to %v", genesisDefaults.Orderer.BatchSize.PreferredMaxBytes)
 			ord.BatchSize.PreferredMaxBytes = genesisDefaults.Orderer.BatchSize.PreferredMaxBytes
+		case ord.BatchCutting.Mode != "" && ord.BatchCutting.TargetLatency == 0:
+			logger.Infof("Orderer.BatchCutting.TargetLatency unset, setting to %s", genesisDefaults.Orderer.BatchCutting.TargetLatency)
+			ord.BatchCutting.TargetLatency = genesisDefaults.Orderer.BatchCutting.TargetLatency
 		default:
 			break loop
 		}
 	}
 
+	if _, ok := ab.BatchCutting_Mode_value[strings.ToUpper(ord.BatchCutting.Mode)]; ord.BatchCutting.Mode != "" && !ok {
+		logger.Panicf("unknown batch cutting mode: %s", ord.BatchCutting.Mode)
+	}
+
 	logger.Infof("orderer type: %s", ord.OrdererType)
 	// Additional, consensus type-dependent initialization goes here
 	// Also using this to panic on unknown orderer type.
@@ -461,6 +487,61 @@ loop:
 			cf.TranslatePathInPlace(configDir, &serverCertPath)
 			c.ServerTlsCert = []byte(serverCertPath)
 		}
+	case bft.TypeKey:
+		if ord.Bft == nil {
+			logger.Panicf("%s configuration missing", bft.TypeKey)
+		}
+		if ord.Bft.Options == nil {
+			logger.Infof("Orderer.BFT.Options unset, setting to %v", genesisDefaults.Orderer.Bft.Options)
+			ord.Bft.Options = genesisDefaults.Orderer.Bft.Options
+		}
+		if ord.Bft.Options.RequestTimeout == "" {
+			logger.Infof("Orderer.BFT.Options.RequestTimeout unset, setting to %v", genesisDefaults.Orderer.Bft.Options.RequestTimeout)
+			ord.Bft.Options.RequestTimeout = genesisDefaults.Orderer.Bft.Options.RequestTimeout
+		}
+		if ord.Bft.Options.ViewChangeTimeout == "" {
+			logger.Infof("Orderer.BFT.Options.ViewChangeTimeout unset, setting to %v", genesisDefaults.Orderer.Bft.Options.ViewChangeTimeout)
+			ord.Bft.Options.ViewChangeTimeout = genesisDefaults.Orderer.Bft.Options.ViewChangeTimeout
+		}
+		if _, err := time.ParseDuration(ord.Bft.Options.RequestTimeout); err != nil {
+			logger.Panicf("BFT RequestTimeout (%s) must be in time duration format", ord.Bft.Options.RequestTimeout)
+		}
+		if _, err := time.ParseDuration(ord.Bft.Options.ViewChangeTimeout); err != nil {
+			logger.Panicf("BFT ViewChangeTimeout (%s) must be in time duration format", ord.Bft.Options.ViewChangeTimeout)
+		}
+		if len(ord.Bft.Consenters) < 4 {
+			logger.Panicf("%s configuration must specify at least 4 consenters to tolerate a faulty one", bft.TypeKey)
+		}
+
+		for _, c := range ord.Bft.GetConsenters() {
+			if c.Host == "" {
+				logger.Panicf("consenter info in %s configuration did not specify host", bft.TypeKey)
+			}
+			if c.Port == 0 {
+				logger.Panicf("consenter info in %s configuration did not specify port", bft.TypeKey)
+			}
+			if c.ClientTlsCert == nil {
+				logger.Panicf("consenter info in %s configuration did not specify client TLS cert", bft.TypeKey)
+			}
+			if c.ServerTlsCert == nil {
+				logger.Panicf("consenter info in %s configuration did not specify server TLS cert", bft.TypeKey)
+			}
+			if c.MspId == "" {
+				logger.Panicf("consenter info in %s configuration did not specify MSP ID", bft.TypeKey)
+			}
+			if c.Identity == nil {
+				logger.Panicf("consenter info in %s configuration did not specify identity", bft.TypeKey)
+			}
+			clientCertPath := string(c.GetClientTlsCert())
+			cf.TranslatePathInPlace(configDir, &clientCertPath)
+			c.ClientTlsCert = []byte(clientCertPath)
+			serverCertPath := string(c.GetServerTlsCert())
+			cf.TranslatePathInPlace(configDir, &serverCertPath)
+			c.ServerTlsCert = []byte(serverCertPath)
+			identityPath := string(c.GetIdentity())
+			cf.TranslatePathInPlace(configDir, &identityPath)
+			c.Identity = []byte(identityPath)
+		}
 	default:
 		logger.Panicf("unknown orderer type: %s", ord.OrdererType)
 	}
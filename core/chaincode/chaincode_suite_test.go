@@ -43,6 +43,11 @@ type chaincodeRuntime interface {
 	chaincode.Runtime
 }
 
+//go:generate counterfeiter -o mock/chaincode_servers.go --fake-name ChaincodeServers . chaincodeServers
+type chaincodeServers interface {
+	chaincode.ChaincodeServers
+}
+
 //go:generate counterfeiter -o mock/cert_generator.go --fake-name CertGenerator . certGenerator
 type certGenerator interface {
 	chaincode.CertGenerator
@@ -58,6 +58,7 @@ type ChaincodeSupport struct {
 	appConfig        ApplicationConfigRetriever
 	HandlerMetrics   *HandlerMetrics
 	LaunchMetrics    *LaunchMetrics
+	ResourceLimits   *ResourceLimitsConfig
 }
 
 // NewChaincodeSupport creates a new ChaincodeSupport instance.
@@ -87,6 +88,7 @@ func NewChaincodeSupport(
 		appConfig:        appConfig,
 		HandlerMetrics:   NewHandlerMetrics(metricsProvider),
 		LaunchMetrics:    NewLaunchMetrics(metricsProvider),
+		ResourceLimits:   config.ResourceLimits,
 	}
 
 	// Keep TestQueries working
@@ -107,13 +109,24 @@ func NewChaincodeSupport(
 		},
 	}
 
-	cs.Launcher = &RuntimeLauncher{
-		Runtime:         cs.Runtime,
-		Registry:        cs.HandlerRegistry,
-		PackageProvider: packageProvider,
-		StartupTimeout:  config.StartupTimeout,
-		Metrics:         cs.LaunchMetrics,
+	launcher := &RuntimeLauncher{
+		Registry:          cs.HandlerRegistry,
+		PackageProvider:   packageProvider,
+		StartupTimeout:    config.StartupTimeout,
+		Metrics:           cs.LaunchMetrics,
+		ReconnectInterval: config.ServerReconnectInterval,
 	}
+	if len(config.ChaincodeServers) != 0 {
+		serverRuntime := &ServerRuntime{
+			Servers:       config.ChaincodeServers,
+			StreamHandler: cs,
+			Fallback:      cs.Runtime,
+		}
+		cs.Runtime = serverRuntime
+		launcher.Servers = serverRuntime
+	}
+	launcher.Runtime = cs.Runtime
+	cs.Launcher = launcher
 
 	return cs
 }
@@ -186,6 +199,7 @@ func (cs *ChaincodeSupport) HandleChaincodeStream(stream ccintf.ChaincodeStream)
 		LedgerGetter:               peer.Default,
 		AppConfig:                  cs.appConfig,
 		Metrics:                    cs.HandlerMetrics,
+		ResourceLimits:             cs.ResourceLimits,
 	}
 
 	return handler.ProcessStream(stream)
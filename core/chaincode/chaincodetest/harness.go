@@ -0,0 +1,631 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package chaincodetest provides a harness to unit test chaincode without a
+// peer or docker. Unlike shim.MockStub, the harness runs the chaincode on the
+// shim and the chaincode support of the peer, simulates transactions with the
+// transaction simulator of a real ledger and commits them to the ledger. As on
+// a peer, the transactions are validated with the built-in validation plugin,
+// which evaluates their endorsement policies, and the ledger invalidates the
+// conflicting ones. Chaincode is deployed through the lifecycle system
+// chaincode.
+package chaincodetest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/platforms"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/common/ccpackage"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/inproccontroller"
+	"github.com/hyperledger/fabric/core/handlers/validation/builtin"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	lutil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/semaphore"
+)
+
+// Endorsement is the endorsed result of the simulation of a proposal.
+type Endorsement struct {
+	// TxID is the ID of the transaction.
+	TxID string
+	// Response is the response of the chaincode.
+	Response *pb.Response
+	// Event is the event set by the chaincode, if any.
+	Event *pb.ChaincodeEvent
+	// Results are the results of the simulation, including the private
+	// data written by the chaincode, which is not part of the transaction.
+	Results *ledger.TxSimulationResults
+	// Transaction is the endorsed transaction. It is nil if the chaincode
+	// returned an error response, as such proposals are not endorsed.
+	Transaction *common.Envelope
+}
+
+// Harness hosts chaincode in process on a channel of a ledger stored in a
+// temporary directory. Proposals are endorsed by a member of the SampleOrg
+// organization of the sample configuration, which is the only organization
+// of the channel.
+//
+// The ledger and the chaincode install path are configured process wide, so
+// only one Harness may be open at a time.
+type Harness struct {
+	channel          *channel
+	dir              string
+	restoreConfig    func()
+	signer           msp.SigningIdentity
+	creator          []byte
+	ledgerProvider   ledger.PeerLedgerProvider
+	registry         *inproccontroller.Registry
+	lscc             *lscc.LifeCycleSysCC
+	chaincodeSupport *chaincode.ChaincodeSupport
+	validator        *txvalidator.TxValidator
+
+	mutex    sync.Mutex
+	launched []*ccprovider.ChaincodeContainerInfo
+}
+
+// New creates a harness with a ledger for the named channel.
+func New(channelID string) (*Harness, error) {
+	dir, err := ioutil.TempDir("", "chaincodetest")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ledger directory")
+	}
+
+	h := &Harness{
+		channel: &channel{id: channelID},
+		dir:     dir,
+		restoreConfig: setConfig(map[string]interface{}{
+			"peer.fileSystemPath":                  dir,
+			"ledger.history.enableHistoryDatabase": true,
+		}),
+	}
+	if err := h.initialize(); err != nil {
+		h.Close()
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *Harness) initialize() error {
+	var err error
+	if h.signer, err = newSigner(); err != nil {
+		return err
+	}
+	if h.creator, err = h.signer.Serialize(); err != nil {
+		return errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	genesisBlock, err := configtxtest.MakeGenesisBlock(h.channel.id)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create genesis block")
+	}
+	env, err := utils.ExtractEnvelope(genesisBlock, 0)
+	if err != nil {
+		return err
+	}
+	if h.channel.bundle, err = channelconfig.NewBundleFromEnvelope(env); err != nil {
+		return errors.WithMessage(err, "failed to create channel configuration")
+	}
+	// the chaincode handler checks access to private data with the MSP
+	// manager of the channel registered like the peer registers it
+	mspmgmt.XXXSetMSPManager(h.channel.id, h.channel.bundle.MSPManager())
+
+	ccprovider.SetChaincodesPath(filepath.Join(h.dir, "chaincodes"))
+
+	selfSignedData, err := signData(h.signer)
+	if err != nil {
+		return err
+	}
+	if h.ledgerProvider, err = kvledger.NewProvider(); err != nil {
+		return errors.WithMessage(err, "failed to create ledger provider")
+	}
+	err = h.ledgerProvider.Initialize(&ledger.Initializer{
+		DeployedChaincodeInfoProvider: &lscc.DeployedCCInfoProvider{},
+		MembershipInfoProvider:        privdata.NewMembershipInfoProvider(selfSignedData, h.channel.GetIdentityDeserializer),
+		MetricsProvider:               &disabled.Provider{},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to initialize ledger provider")
+	}
+	if h.channel.ledger, err = h.ledgerProvider.Create(genesisBlock); err != nil {
+		return errors.WithMessage(err, "failed to create ledger")
+	}
+
+	// chaincode is run in process like system chaincode
+	h.registry = inproccontroller.NewRegistry()
+	h.lscc = lscc.New(h.channel, aclProvider{}, platforms.NewRegistry(&golang.Platform{}))
+	lsccID := &ccintf.CCID{Name: lsccNamespace, Version: util.GetSysCCVersion()}
+	if err := h.registry.Register(lsccID, h.lscc); err != nil {
+		return errors.WithMessage(err, "failed to register lifecycle system chaincode")
+	}
+	h.chaincodeSupport = chaincode.NewChaincodeSupport(
+		&chaincode.Config{
+			ExecuteTimeout: 30 * time.Second,
+			StartupTimeout: 10 * time.Second,
+			LogLevel:       "INFO",
+			ShimLogLevel:   "INFO",
+		},
+		"",
+		false,
+		nil,
+		nil,
+		nil,
+		h.lscc,
+		aclProvider{},
+		container.NewVMController(map[string]container.VMProvider{
+			inproccontroller.ContainerType: h.registry,
+		}),
+		h.channel,
+		nil,
+		h.channel,
+		&disabled.Provider{},
+	)
+	h.registry.ChaincodeSupport = h.chaincodeSupport
+	if err := h.deployLSCC(); err != nil {
+		return err
+	}
+
+	support := &validatorSupport{
+		Weighted: semaphore.NewWeighted(int64(runtime.NumCPU())),
+		channel:  h.channel,
+	}
+	h.validator = txvalidator.NewTxValidator(h.channel.id, support, h.channel, txvalidator.MapBasedPluginMapper{
+		"vscc": &builtin.DefaultValidationFactory{},
+	})
+
+	return nil
+}
+
+// deployLSCC launches and initializes the lifecycle system chaincode, as the
+// peer deploys the system chaincodes when it starts.
+func (h *Harness) deployLSCC() error {
+	cds := &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: h.lscc.Name(), Path: h.lscc.Path()},
+			Input:       &pb.ChaincodeInput{Args: h.lscc.InitArgs()},
+		},
+		ExecEnv: pb.ChaincodeDeploymentSpec_SYSTEM,
+	}
+	txParams := &ccprovider.TransactionParams{TxID: util.GenerateUUID()}
+	cccid := &ccprovider.CCContext{Name: h.lscc.Name(), Version: util.GetSysCCVersion()}
+
+	h.launchedChaincode(&ccprovider.ChaincodeContainerInfo{
+		Name:          cccid.Name,
+		Version:       cccid.Version,
+		Path:          h.lscc.Path(),
+		Type:          pb.ChaincodeSpec_GOLANG.String(),
+		ContainerType: inproccontroller.ContainerType,
+	})
+	res, _, err := h.chaincodeSupport.ExecuteLegacyInit(txParams, cccid, cds)
+	if err != nil {
+		return errors.WithMessage(err, "failed to deploy lifecycle system chaincode")
+	}
+	if res.Status != shim.OK {
+		return errors.Errorf("failed to deploy lifecycle system chaincode: %s", res.Message)
+	}
+	return nil
+}
+
+// Close stops the chaincodes, closes the ledger and removes its directory.
+func (h *Harness) Close() {
+	h.mutex.Lock()
+	launched := h.launched
+	h.launched = nil
+	h.mutex.Unlock()
+	for _, ccci := range launched {
+		h.chaincodeSupport.Stop(ccci)
+	}
+
+	if h.channel.ledger != nil {
+		h.channel.ledger.Close()
+	}
+	if h.ledgerProvider != nil {
+		h.ledgerProvider.Close()
+	}
+	h.restoreConfig()
+	os.RemoveAll(h.dir)
+}
+
+// Ledger returns the ledger of the channel.
+func (h *Harness) Ledger() ledger.PeerLedger {
+	return h.channel.ledger
+}
+
+// Deploy installs the chaincode at the given version and endorses and commits
+// the lifecycle system chaincode transaction instantiating it, or upgrading it
+// if the chaincode is already deployed. The chaincode is initialized with the
+// given arguments, and its endorsement policy is satisfied by any member of
+// the organization of the channel.
+func (h *Harness) Deploy(name, version string, cc shim.Chaincode, collections *common.CollectionConfigPackage, args ...[]byte) error {
+	cds := &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: name, Version: version, Path: name},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+		ExecEnv: pb.ChaincodeDeploymentSpec_SYSTEM,
+	}
+	if err := h.install(cds, cc); err != nil {
+		return err
+	}
+
+	policy, err := proto.Marshal(cauthdsl.SignedByAnyMember([]string{h.signer.GetMSPIdentifier()}))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal endorsement policy")
+	}
+	var collectionBytes []byte
+	if collections != nil {
+		if collectionBytes, err = proto.Marshal(collections); err != nil {
+			return errors.Wrap(err, "failed to marshal collection configuration")
+		}
+	}
+
+	deployed, err := h.deployed(name)
+	if err != nil {
+		return err
+	}
+	createProposal := utils.CreateDeployProposalFromCDS
+	if deployed {
+		createProposal = utils.CreateUpgradeProposalFromCDS
+	}
+	prop, txID, err := createProposal(h.channel.id, cds, h.creator, policy, []byte("escc"), []byte("vscc"), collectionBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create deploy proposal")
+	}
+	cis, err := utils.GetChaincodeInvocationSpec(prop)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get deploy invocation spec")
+	}
+
+	endorsement, err := h.endorse(prop, txID, func(txParams *ccprovider.TransactionParams) (*pb.ChaincodeID, *pb.Response, *pb.ChaincodeEvent, error) {
+		ccid := &pb.ChaincodeID{Name: lsccNamespace, Version: util.GetSysCCVersion()}
+		cccid := &ccprovider.CCContext{Name: ccid.Name, Version: ccid.Version}
+		res, event, err := h.chaincodeSupport.Execute(txParams, cccid, cis.ChaincodeSpec.Input)
+		if err != nil || res.Status >= shim.ERRORTHRESHOLD {
+			return ccid, res, event, err
+		}
+
+		// the chaincode is initialized in the simulation in which its
+		// definition is written, as the endorser does
+		h.launchedChaincode(ccprovider.DeploymentSpecToChaincodeContainerInfo(cds))
+		_, _, err = h.chaincodeSupport.ExecuteLegacyInit(txParams, &ccprovider.CCContext{Name: name, Version: version}, cds)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return ccid, res, event, nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to endorse deploy proposal")
+	}
+
+	codes, err := h.Commit(endorsement)
+	if err != nil {
+		return err
+	}
+	if codes[0] != pb.TxValidationCode_VALID {
+		return errors.Errorf("deploy transaction %s is invalid: %s", txID, codes[0])
+	}
+	return nil
+}
+
+// Endorse simulates the invocation of the chaincode with the given arguments
+// and endorses the results.
+func (h *Harness) Endorse(chaincodeName string, args ...[]byte) (*Endorsement, error) {
+	return h.EndorseWithTransient(chaincodeName, nil, args...)
+}
+
+// EndorseWithTransient simulates the invocation of the chaincode with the
+// given arguments and transient data, and endorses the results. The transient
+// data is passed to the chaincode but is not part of the transaction.
+func (h *Harness) EndorseWithTransient(chaincodeName string, transient map[string][]byte, args ...[]byte) (*Endorsement, error) {
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: chaincodeName},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+	prop, txID, err := utils.CreateChaincodeProposalWithTransient(common.HeaderType_ENDORSER_TRANSACTION, h.channel.id, cis, h.creator, transient)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create proposal")
+	}
+
+	return h.endorse(prop, txID, func(txParams *ccprovider.TransactionParams) (*pb.ChaincodeID, *pb.Response, *pb.ChaincodeEvent, error) {
+		// the definition is read with the simulator, so that the transaction
+		// is invalidated by a concurrent upgrade of the chaincode
+		cd, err := h.lscc.ChaincodeDefinition(chaincodeName, txParams.TXSimulator)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ccci, err := h.lscc.ChaincodeContainerInfo(chaincodeName, txParams.TXSimulator)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		h.launchedChaincode(ccci)
+
+		cccid := &ccprovider.CCContext{Name: chaincodeName, Version: cd.CCVersion()}
+		res, event, err := h.chaincodeSupport.Execute(txParams, cccid, cis.ChaincodeSpec.Input)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return &pb.ChaincodeID{Name: chaincodeName, Version: cd.CCVersion()}, res, event, nil
+	})
+}
+
+// Commit orders the endorsed transactions into the next block, validates it
+// and commits it, together with the private data written by the transactions.
+// It returns the validation code of each transaction.
+func (h *Harness) Commit(endorsements ...*Endorsement) ([]pb.TxValidationCode, error) {
+	info, err := h.channel.ledger.GetBlockchainInfo()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get blockchain info")
+	}
+
+	block := common.NewBlock(info.Height, info.CurrentBlockHash)
+	pvtData := ledger.TxPvtDataMap{}
+	for i, endorsement := range endorsements {
+		if endorsement.Transaction == nil {
+			return nil, errors.Errorf("transaction %s was not endorsed, the chaincode returned status %d: %s",
+				endorsement.TxID, endorsement.Response.Status, endorsement.Response.Message)
+		}
+		envBytes, err := proto.Marshal(endorsement.Transaction)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal transaction %s", endorsement.TxID)
+		}
+		block.Data.Data = append(block.Data.Data, envBytes)
+		if endorsement.Results.ContainsPvtWrites() {
+			pvtData[uint64(i)] = &ledger.TxPvtData{
+				SeqInBlock: uint64(i),
+				WriteSet:   endorsement.Results.PvtSimulationResults,
+			}
+		}
+	}
+	block.Header.DataHash = block.Data.Hash()
+
+	// the validator checks the signatures and endorsement policies of the
+	// transactions, the ledger then invalidates the conflicting ones
+	if err := h.validator.Validate(block); err != nil {
+		return nil, errors.WithMessage(err, "failed to validate block")
+	}
+
+	err = h.channel.ledger.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block, PvtData: pvtData}, &ledger.CommitOptions{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to commit block")
+	}
+
+	committed, err := h.channel.ledger.GetBlockByNumber(block.Header.Number)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to retrieve committed block")
+	}
+	txsFilter := lutil.TxValidationFlags(committed.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	codes := make([]pb.TxValidationCode, len(endorsements))
+	for i := range codes {
+		codes[i] = txsFilter.Flag(i)
+	}
+	return codes, nil
+}
+
+// Invoke endorses the invocation of the chaincode with the given arguments and
+// commits the transaction in a block of its own. It returns the response of
+// the chaincode and the validation code of the transaction.
+func (h *Harness) Invoke(chaincodeName string, args ...[]byte) (*pb.Response, pb.TxValidationCode, error) {
+	endorsement, err := h.Endorse(chaincodeName, args...)
+	if err != nil {
+		return nil, pb.TxValidationCode_INVALID_OTHER_REASON, err
+	}
+	codes, err := h.Commit(endorsement)
+	if err != nil {
+		return endorsement.Response, pb.TxValidationCode_INVALID_OTHER_REASON, err
+	}
+	return endorsement.Response, codes[0], nil
+}
+
+type executeFunc func(txParams *ccprovider.TransactionParams) (*pb.ChaincodeID, *pb.Response, *pb.ChaincodeEvent, error)
+
+// endorse simulates the proposal with the given function, which executes the
+// chaincode, and endorses the results unless the chaincode failed.
+func (h *Harness) endorse(prop *pb.Proposal, txID string, execute executeFunc) (*Endorsement, error) {
+	signedProp, err := utils.GetSignedProposal(prop, h.signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign proposal")
+	}
+
+	sim, err := h.channel.ledger.NewTxSimulator(txID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create transaction simulator")
+	}
+	defer sim.Done()
+	hqe, err := h.channel.ledger.NewHistoryQueryExecutor()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create history query executor")
+	}
+
+	txParams := &ccprovider.TransactionParams{
+		TxID:                 txID,
+		ChannelID:            h.channel.id,
+		SignedProp:           signedProp,
+		Proposal:             prop,
+		TXSimulator:          sim,
+		HistoryQueryExecutor: hqe,
+	}
+	ccid, res, event, err := execute(txParams)
+	if err != nil {
+		return nil, err
+	}
+
+	endorsement := &Endorsement{
+		TxID:     txID,
+		Response: res,
+		Event:    event,
+	}
+	if res.Status >= shim.ERRORTHRESHOLD {
+		return endorsement, nil
+	}
+
+	if endorsement.Results, err = sim.GetTxSimulationResults(); err != nil {
+		return nil, errors.WithMessage(err, "failed to get simulation results")
+	}
+	pubSimResBytes, err := endorsement.Results.GetPubSimulationBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal simulation results")
+	}
+	var eventBytes []byte
+	if event != nil {
+		if eventBytes, err = proto.Marshal(event); err != nil {
+			return nil, errors.Wrap(err, "failed to marshal chaincode event")
+		}
+	}
+
+	propResp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, res, pubSimResBytes, eventBytes, ccid, nil, h.signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to endorse proposal")
+	}
+	if endorsement.Transaction, err = utils.CreateSignedTx(prop, h.signer, propResp); err != nil {
+		return nil, errors.WithMessage(err, "failed to create transaction")
+	}
+
+	return endorsement, nil
+}
+
+// install registers the chaincode with the in process VM and puts its
+// package on the file system, from which the lifecycle system chaincode
+// reads it. The package is signed by the member of SampleOrg, whose
+// instantiation policy is satisfied by any admin of SampleOrg.
+func (h *Harness) install(cds *pb.ChaincodeDeploymentSpec, cc shim.Chaincode) error {
+	ccid := &ccintf.CCID{Name: cds.Name(), Version: cds.Version()}
+	if err := h.registry.Register(ccid, cc); err != nil {
+		return errors.WithMessage(err, "failed to install chaincode")
+	}
+
+	instantiationPolicy := cauthdsl.SignedByAnyAdmin([]string{h.signer.GetMSPIdentifier()})
+	env, err := ccpackage.OwnerCreateSignedCCDepSpec(cds, instantiationPolicy, h.signer)
+	if err != nil {
+		return errors.WithMessage(err, "failed to package chaincode")
+	}
+	envBytes, err := proto.Marshal(env)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chaincode package")
+	}
+	ccpack := &ccprovider.SignedCDSPackage{}
+	if _, err := ccpack.InitFromBuffer(envBytes); err != nil {
+		return errors.WithMessage(err, "failed to package chaincode")
+	}
+	if err := ccpack.PutChaincodeToFS(); err != nil {
+		return errors.WithMessage(err, "failed to install chaincode")
+	}
+	return nil
+}
+
+func (h *Harness) deployed(chaincodeName string) (bool, error) {
+	qe, err := h.channel.ledger.NewQueryExecutor()
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to create query executor")
+	}
+	defer qe.Done()
+
+	cdBytes, err := qe.GetState(lsccNamespace, chaincodeName)
+	if err != nil {
+		return false, errors.WithMessage(err, "could not retrieve chaincode definition")
+	}
+	return cdBytes != nil, nil
+}
+
+// launchedChaincode records chaincode which may have been launched, so that
+// it is stopped when the harness is closed.
+func (h *Harness) launchedChaincode(ccci *ccprovider.ChaincodeContainerInfo) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, l := range h.launched {
+		if l.Name == ccci.Name && l.Version == ccci.Version {
+			return
+		}
+	}
+	h.launched = append(h.launched, ccci)
+}
+
+// newSigner returns the signing identity of the SampleOrg member of the
+// sample configuration.
+func newSigner() (msp.SigningIdentity, error) {
+	dir, err := configtest.GetDevMspDir()
+	if err != nil {
+		return nil, err
+	}
+	conf, err := msp.GetLocalMspConfig(dir, nil, "SampleOrg")
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load MSP configuration")
+	}
+	localMSP, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_0}})
+	if err != nil {
+		return nil, err
+	}
+	if err := localMSP.Setup(conf); err != nil {
+		return nil, errors.WithMessage(err, "failed to set up MSP")
+	}
+
+	return localMSP.GetDefaultSigningIdentity()
+}
+
+// signData returns data signed by the identity, which stands for the peer
+// in the evaluation of collection membership.
+func signData(signer msp.SigningIdentity) (common.SignedData, error) {
+	data := make([]byte, 32)
+	sig, err := signer.Sign(data)
+	if err != nil {
+		return common.SignedData{}, errors.WithMessage(err, "failed to sign data")
+	}
+	identity, err := signer.Serialize()
+	if err != nil {
+		return common.SignedData{}, errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	return common.SignedData{Data: data, Signature: sig, Identity: identity}, nil
+}
+
+// setConfig sets the given viper configuration and returns a function
+// restoring the previous configuration.
+func setConfig(config map[string]interface{}) (restore func()) {
+	previous := map[string]interface{}{}
+	for key, value := range config {
+		previous[key] = viper.Get(key)
+		viper.Set(key, value)
+	}
+
+	return func() {
+		for key, value := range previous {
+			viper.Set(key, value)
+		}
+	}
+}
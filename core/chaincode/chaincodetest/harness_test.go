@@ -0,0 +1,391 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincodetest_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/chaincodetest"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// kvChaincode exposes the state APIs of the shim to the tests
+type kvChaincode struct {
+	version string
+}
+
+func (kv *kvChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	_, args := stub.GetFunctionAndParameters()
+	for i := 0; i+1 < len(args); i += 2 {
+		if err := stub.PutState(args[i], []byte(args[i+1])); err != nil {
+			return shim.Error(err.Error())
+		}
+	}
+	return shim.Success(nil)
+}
+
+func (kv *kvChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	fn, args := stub.GetFunctionAndParameters()
+	switch fn {
+	case "put":
+		if err := stub.PutState(args[0], []byte(args[1])); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+
+	case "get":
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(value)
+
+	case "putAndGet":
+		if err := stub.PutState(args[0], []byte(args[1])); err != nil {
+			return shim.Error(err.Error())
+		}
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(value)
+
+	case "increment":
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		n, _ := strconv.Atoi(string(value))
+		if err := stub.PutState(args[0], []byte(strconv.Itoa(n+1))); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+
+	case "count":
+		iter, err := stub.GetStateByRange(args[0], args[1])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		defer iter.Close()
+		n := 0
+		for iter.HasNext() {
+			if _, err := iter.Next(); err != nil {
+				return shim.Error(err.Error())
+			}
+			n++
+		}
+		if err := stub.PutState("count", []byte(strconv.Itoa(n))); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success([]byte(strconv.Itoa(n)))
+
+	case "page":
+		pageSize, _ := strconv.Atoi(args[2])
+		iter, metadata, err := stub.GetStateByRangeWithPagination(args[0], args[1], int32(pageSize), args[3])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		defer iter.Close()
+		keys := []string{}
+		for iter.HasNext() {
+			kv, err := iter.Next()
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			keys = append(keys, kv.Key)
+		}
+		return shim.Success([]byte(strings.Join(append(keys, metadata.Bookmark), ",")))
+
+	case "history":
+		iter, err := stub.GetHistoryForKey(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		defer iter.Close()
+		values := []string{}
+		for iter.HasNext() {
+			modification, err := iter.Next()
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			values = append(values, string(modification.Value))
+		}
+		return shim.Success([]byte(strings.Join(values, ",")))
+
+	case "putPrivate":
+		transient, err := stub.GetTransient()
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if err := stub.PutPrivateData(args[0], args[1], transient["value"]); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+
+	case "getPrivate":
+		value, err := stub.GetPrivateData(args[0], args[1])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(value)
+
+	case "invoke":
+		return stub.InvokeChaincode(args[0], util.ToChaincodeArgs(args[1:]...), "")
+
+	case "version":
+		return shim.Success([]byte(kv.version))
+
+	default:
+		return shim.Error("unknown function " + fn)
+	}
+}
+
+func newHarness(t *testing.T) *chaincodetest.Harness {
+	h, err := chaincodetest.New("testchannel")
+	require.NoError(t, err)
+	return h
+}
+
+func TestDeployAndInvoke(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	err := h.Deploy("kv", "1.0", &kvChaincode{}, nil, util.ToChaincodeArgs("init", "a", "100")...)
+	require.NoError(t, err)
+
+	res, code, err := h.Invoke("kv", util.ToChaincodeArgs("put", "b", "200")...)
+	require.NoError(t, err)
+	assert.Equal(t, int32(shim.OK), res.Status)
+	assert.Equal(t, pb.TxValidationCode_VALID, code)
+
+	for key, value := range map[string]string{"a": "100", "b": "200"} {
+		endorsement, err := h.Endorse("kv", util.ToChaincodeArgs("get", key)...)
+		require.NoError(t, err)
+		assert.Equal(t, value, string(endorsement.Response.Payload))
+	}
+
+	// init, deploy and invoke blocks
+	info, err := h.Ledger().GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), info.Height)
+
+	_, err = h.Endorse("missing", util.ToChaincodeArgs("get", "a")...)
+	assert.EqualError(t, err, "chaincode missing not found")
+}
+
+func TestChaincodeError(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, nil))
+
+	endorsement, err := h.Endorse("kv", util.ToChaincodeArgs("unknown")...)
+	require.NoError(t, err)
+	assert.Equal(t, int32(shim.ERROR), endorsement.Response.Status)
+	assert.Equal(t, "unknown function unknown", endorsement.Response.Message)
+	assert.Nil(t, endorsement.Transaction)
+
+	_, err = h.Commit(endorsement)
+	assert.EqualError(t, err, "transaction "+endorsement.TxID+" was not endorsed, the chaincode returned status 500: unknown function unknown")
+
+	err = h.Deploy("kv", "1.0", &kvChaincode{}, nil)
+	assert.EqualError(t, err, "failed to install chaincode: kv-1.0 already registered")
+}
+
+func TestValidation(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, nil))
+
+	endorsement, err := h.Endorse("kv", util.ToChaincodeArgs("put", "a", "100")...)
+	require.NoError(t, err)
+	codes, err := h.Commit(endorsement)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID}, codes)
+	codes, err = h.Commit(endorsement)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_DUPLICATE_TXID}, codes)
+
+	endorsement, err = h.Endorse("kv", util.ToChaincodeArgs("put", "a", "200")...)
+	require.NoError(t, err)
+	endorsement.Transaction.Signature[len(endorsement.Transaction.Signature)-1] ^= 0xff
+	codes, err = h.Commit(endorsement)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_BAD_CREATOR_SIGNATURE}, codes)
+}
+
+func TestReadYourOwnWrites(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, nil, util.ToChaincodeArgs("init", "a", "100")...))
+
+	// the transaction simulator reads the committed state, not the writes
+	// of the transaction being simulated
+	endorsement, err := h.Endorse("kv", util.ToChaincodeArgs("putAndGet", "a", "200")...)
+	require.NoError(t, err)
+	assert.Equal(t, "100", string(endorsement.Response.Payload))
+}
+
+func TestMVCCConflict(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, nil, util.ToChaincodeArgs("init", "counter", "0")...))
+
+	first, err := h.Endorse("kv", util.ToChaincodeArgs("increment", "counter")...)
+	require.NoError(t, err)
+	second, err := h.Endorse("kv", util.ToChaincodeArgs("increment", "counter")...)
+	require.NoError(t, err)
+	codes, err := h.Commit(first, second)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT}, codes)
+
+	stale, err := h.Endorse("kv", util.ToChaincodeArgs("increment", "counter")...)
+	require.NoError(t, err)
+	_, code, err := h.Invoke("kv", util.ToChaincodeArgs("increment", "counter")...)
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, code)
+	codes, err = h.Commit(stale)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_MVCC_READ_CONFLICT}, codes)
+
+	endorsement, err := h.Endorse("kv", util.ToChaincodeArgs("get", "counter")...)
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(endorsement.Response.Payload))
+}
+
+func TestPhantomReadConflict(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, nil, util.ToChaincodeArgs("init", "key1", "1", "key3", "3")...))
+
+	count, err := h.Endorse("kv", util.ToChaincodeArgs("count", "key1", "key9")...)
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(count.Response.Payload))
+	insert, err := h.Endorse("kv", util.ToChaincodeArgs("put", "key2", "2")...)
+	require.NoError(t, err)
+
+	codes, err := h.Commit(insert, count)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_PHANTOM_READ_CONFLICT}, codes)
+}
+
+func TestPagination(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, nil, util.ToChaincodeArgs("init", "key1", "1", "key2", "2", "key3", "3")...))
+
+	endorsement, err := h.Endorse("kv", util.ToChaincodeArgs("page", "key1", "key9", "2", "")...)
+	require.NoError(t, err)
+	assert.Equal(t, "key1,key2,key3", string(endorsement.Response.Payload))
+
+	endorsement, err = h.Endorse("kv", util.ToChaincodeArgs("page", "key1", "key9", "2", "key3")...)
+	require.NoError(t, err)
+	assert.Equal(t, "key3,", string(endorsement.Response.Payload))
+}
+
+func TestHistory(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, nil, util.ToChaincodeArgs("init", "a", "1")...))
+	for _, value := range []string{"2", "3"} {
+		_, code, err := h.Invoke("kv", util.ToChaincodeArgs("put", "a", value)...)
+		require.NoError(t, err)
+		require.Equal(t, pb.TxValidationCode_VALID, code)
+	}
+
+	endorsement, err := h.Endorse("kv", util.ToChaincodeArgs("history", "a")...)
+	require.NoError(t, err)
+	assert.Equal(t, "1,2,3", string(endorsement.Response.Payload))
+}
+
+func TestPrivateData(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	collections := &common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{{
+			Payload: &common.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &common.StaticCollectionConfig{
+					Name: "private",
+					MemberOrgsPolicy: &common.CollectionPolicyConfig{
+						Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+							SignaturePolicy: cauthdsl.SignedByAnyMember([]string{"SampleOrg"}),
+						},
+					},
+					MaximumPeerCount: 1,
+					MemberOnlyRead:   true,
+				},
+			},
+		}},
+	}
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{}, collections))
+
+	transient := map[string][]byte{"value": []byte("secret")}
+	endorsement, err := h.EndorseWithTransient("kv", transient, util.ToChaincodeArgs("putPrivate", "private", "a")...)
+	require.NoError(t, err)
+	require.True(t, endorsement.Results.ContainsPvtWrites())
+
+	// only the hashes of the private data are part of the transaction
+	require.NotNil(t, endorsement.Transaction)
+	for _, ns := range endorsement.Results.PubSimulationResults.NsRwset {
+		if ns.Namespace == "kv" {
+			require.Len(t, ns.CollectionHashedRwset, 1)
+			assert.Equal(t, "private", ns.CollectionHashedRwset[0].CollectionName)
+			assert.NotEmpty(t, ns.CollectionHashedRwset[0].PvtRwsetHash)
+		}
+	}
+	assert.NotContains(t, string(endorsement.Transaction.Payload), "secret")
+
+	codes, err := h.Commit(endorsement)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID}, codes)
+
+	read, err := h.Endorse("kv", util.ToChaincodeArgs("getPrivate", "private", "a")...)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(read.Response.Payload))
+
+	undefined, err := h.Endorse("kv", util.ToChaincodeArgs("getPrivate", "undefined", "a")...)
+	require.NoError(t, err)
+	assert.Equal(t, int32(shim.ERROR), undefined.Response.Status)
+}
+
+func TestUpgradeAndInvokeChaincode(t *testing.T) {
+	h := newHarness(t)
+	defer h.Close()
+
+	require.NoError(t, h.Deploy("kv", "1.0", &kvChaincode{version: "1.0"}, nil))
+	require.NoError(t, h.Deploy("caller", "1.0", &kvChaincode{version: "1.0"}, nil))
+
+	// a proposal endorsed before the upgrade is invalidated, as it was
+	// endorsed by the previous version of the chaincode
+	stale, err := h.Endorse("kv", util.ToChaincodeArgs("version")...)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0", string(stale.Response.Payload))
+
+	require.NoError(t, h.Deploy("kv", "2.0", &kvChaincode{version: "2.0"}, nil))
+	codes, err := h.Commit(stale)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_EXPIRED_CHAINCODE}, codes)
+
+	endorsement, err := h.Endorse("caller", util.ToChaincodeArgs("invoke", "kv", "version")...)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", string(endorsement.Response.Payload))
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincodetest
+
+import (
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/semaphore"
+)
+
+// lsccNamespace is the name of the lifecycle system chaincode and the
+// namespace in which it stores the chaincode definitions.
+const lsccNamespace = "lscc"
+
+// aclProvider allows every chaincode to invoke any other chaincode.
+type aclProvider struct{}
+
+func (aclProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	return nil
+}
+
+// channel provides the configuration and ledger of the channel of the
+// harness to the chaincode support, the lifecycle system chaincode, the
+// transaction validator and the collection store.
+type channel struct {
+	id     string
+	bundle *channelconfig.Bundle
+	ledger ledger.PeerLedger
+}
+
+// IsSysCC returns true for the lifecycle system chaincode, the only system
+// chaincode hosted by the harness.
+func (c *channel) IsSysCC(name string) bool {
+	return name == lsccNamespace
+}
+
+func (c *channel) IsSysCCAndNotInvokableCC2CC(name string) bool {
+	return false
+}
+
+func (c *channel) IsSysCCAndNotInvokableExternal(name string) bool {
+	return false
+}
+
+func (c *channel) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error) {
+	if cid != c.id {
+		return nil, errors.Errorf("channel %s does not exist", cid)
+	}
+	return c.ledger.NewQueryExecutor()
+}
+
+func (c *channel) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
+	if cid != c.id {
+		return nil, false
+	}
+	return c.bundle.ApplicationConfig()
+}
+
+func (c *channel) PolicyManager(channelID string) (policies.Manager, bool) {
+	if channelID != c.id {
+		return nil, false
+	}
+	return c.bundle.PolicyManager(), true
+}
+
+func (c *channel) GetIdentityDeserializer(chainID string) msp.IdentityDeserializer {
+	return c.bundle.MSPManager()
+}
+
+// validatorSupport provides the channel of the harness to the transaction
+// validator, as the chain support of the peer does.
+type validatorSupport struct {
+	*semaphore.Weighted
+	channel *channel
+}
+
+func (v *validatorSupport) Ledger() ledger.PeerLedger {
+	return v.channel.ledger
+}
+
+func (v *validatorSupport) MSPManager() msp.MSPManager {
+	return v.channel.bundle.MSPManager()
+}
+
+// Apply returns an error, as the harness does not update the configuration
+// of its channel.
+func (v *validatorSupport) Apply(configtx *common.ConfigEnvelope) error {
+	return errors.New("configuration updates are not supported")
+}
+
+// GetMSPIDs returns the IDs of the MSPs of the application organizations of
+// the channel.
+func (v *validatorSupport) GetMSPIDs(cid string) []string {
+	ac, ok := v.channel.GetApplicationConfig(cid)
+	if !ok {
+		return nil
+	}
+	var mspIDs []string
+	for _, org := range ac.Organizations() {
+		mspIDs = append(mspIDs, org.MSPID())
+	}
+	return mspIDs
+}
+
+func (v *validatorSupport) Capabilities() channelconfig.ApplicationCapabilities {
+	ac, _ := v.channel.bundle.ApplicationConfig()
+	return ac.Capabilities()
+}
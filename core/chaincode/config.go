@@ -12,13 +12,17 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/viperutil"
+	"github.com/mitchellh/mapstructure"
 	logging "github.com/op/go-logging"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
 const (
-	defaultExecutionTimeout = 30 * time.Second
-	minimumStartupTimeout   = 5 * time.Second
+	defaultExecutionTimeout        = 30 * time.Second
+	minimumStartupTimeout          = 5 * time.Second
+	defaultServerReconnectInterval = 5 * time.Second
 )
 
 type Config struct {
@@ -29,6 +33,18 @@ type Config struct {
 	LogFormat      string
 	LogLevel       string
 	ShimLogLevel   string
+
+	// ChaincodeServers are the chaincodes run as servers, by chaincode name.
+	// They are not loaded from viper, as the client TLS configuration is
+	// assembled by the peer.
+	ChaincodeServers        map[string]*ChaincodeServerConfig
+	ServerReconnectInterval time.Duration
+
+	// ResourceLimits holds the limits on the resources user chaincodes
+	// may use to simulate a transaction. They are not loaded from viper,
+	// as invalid limits must fail the start of the peer; see
+	// ResourceLimitsFromViper.
+	ResourceLimits *ResourceLimitsConfig
 }
 
 func GlobalConfig() *Config {
@@ -55,6 +71,11 @@ func (c *Config) load() {
 		c.StartupTimeout = minimumStartupTimeout
 	}
 
+	c.ServerReconnectInterval = viper.GetDuration("chaincode.serverReconnectInterval")
+	if c.ServerReconnectInterval <= 0 {
+		c.ServerReconnectInterval = defaultServerReconnectInterval
+	}
+
 	c.LogFormat = viper.GetString("chaincode.logging.format")
 	c.LogLevel = getLogLevelFromViper("chaincode.logging.level")
 	c.ShimLogLevel = getLogLevelFromViper("chaincode.logging.shim")
@@ -81,6 +102,57 @@ func getLogLevelFromViper(key string) string {
 	return levelString
 }
 
+// chaincodeResourceLimits are the resource limits of a chaincode in the
+// chaincode.resourceLimits.chaincodes list
+type chaincodeResourceLimits struct {
+	Name           string `mapstructure:"name" yaml:"name"`
+	ResourceLimits `mapstructure:",squash" yaml:",inline"`
+}
+
+// ResourceLimitsFromViper gets the resource limits of user chaincodes from
+// viper. An error is returned if the list of the limits of individual
+// chaincodes cannot be parsed, or lists a chaincode without a name or more
+// than once.
+func ResourceLimitsFromViper() (*ResourceLimitsConfig, error) {
+	config := &ResourceLimitsConfig{
+		Default: ResourceLimits{
+			MaxStateReads:        viper.GetInt("chaincode.resourceLimits.maxStateReads"),
+			MaxRangeQueryResults: viper.GetInt("chaincode.resourceLimits.maxRangeQueryResults"),
+			MaxWrites:            viper.GetInt("chaincode.resourceLimits.maxWrites"),
+			MaxTotalBytes:        viper.GetInt("chaincode.resourceLimits.maxTotalBytes"),
+		},
+		Chaincodes: map[string]ResourceLimits{},
+	}
+
+	var entries []map[string]interface{}
+	if err := viperutil.EnhancedExactUnmarshalKey("chaincode.resourceLimits.chaincodes", &entries); err != nil {
+		return nil, errors.Wrap(err, "invalid chaincode.resourceLimits.chaincodes")
+	}
+	for i, entry := range entries {
+		var c chaincodeResourceLimits
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			ErrorUnused:      true,
+			WeaklyTypedInput: true,
+			Result:           &c,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create decoder")
+		}
+		if err := decoder.Decode(entry); err != nil {
+			return nil, errors.Wrapf(err, "invalid chaincode.resourceLimits.chaincodes: entry %d", i)
+		}
+		if c.Name == "" {
+			return nil, errors.Errorf("invalid chaincode.resourceLimits.chaincodes: entry %d has no chaincode name", i)
+		}
+		if _, exists := config.Chaincodes[c.Name]; exists {
+			return nil, errors.Errorf("invalid chaincode.resourceLimits.chaincodes: chaincode %s is listed more than once", c.Name)
+		}
+		config.Chaincodes[c.Name] = c.ResourceLimits
+	}
+
+	return config, nil
+}
+
 // DevModeUserRunsChaincode enables chaincode execution in a development
 // environment
 const DevModeUserRunsChaincode string = "dev"
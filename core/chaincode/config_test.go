@@ -93,6 +93,85 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Describe("ResourceLimitsFromViper", func() {
+		Context("when resource limits are configured", func() {
+			BeforeEach(func() {
+				viper.Set("chaincode.resourceLimits.maxStateReads", 10)
+				viper.Set("chaincode.resourceLimits.maxRangeQueryResults", 20)
+				viper.Set("chaincode.resourceLimits.maxWrites", 30)
+				viper.Set("chaincode.resourceLimits.maxTotalBytes", 40)
+				viper.Set("chaincode.resourceLimits.chaincodes", []map[string]interface{}{
+					{"name": "mycc", "maxWrites": 300},
+				})
+			})
+
+			It("captures the default and chaincode specific limits", func() {
+				resourceLimits, err := chaincode.ResourceLimitsFromViper()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resourceLimits.Default).To(Equal(chaincode.ResourceLimits{
+					MaxStateReads:        10,
+					MaxRangeQueryResults: 20,
+					MaxWrites:            30,
+					MaxTotalBytes:        40,
+				}))
+				Expect(resourceLimits.Chaincodes).To(Equal(map[string]chaincode.ResourceLimits{
+					"mycc": {MaxWrites: 300},
+				}))
+			})
+		})
+
+		Context("when no resource limits are configured", func() {
+			It("does not limit chaincodes", func() {
+				resourceLimits, err := chaincode.ResourceLimitsFromViper()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resourceLimits.Limits("mycc")).To(Equal(chaincode.ResourceLimits{}))
+			})
+		})
+
+		Context("when the limits of a chaincode are malformed", func() {
+			BeforeEach(func() {
+				viper.Set("chaincode.resourceLimits.chaincodes", []map[string]interface{}{
+					{"name": "mycc", "maxWrite": 300},
+				})
+			})
+
+			It("returns an error", func() {
+				_, err := chaincode.ResourceLimitsFromViper()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("invalid chaincode.resourceLimits.chaincodes: entry 0: "))
+				Expect(err.Error()).To(ContainSubstring("maxWrite"))
+			})
+		})
+
+		Context("when a chaincode has no name", func() {
+			BeforeEach(func() {
+				viper.Set("chaincode.resourceLimits.chaincodes", []map[string]interface{}{
+					{"name": "mycc", "maxWrites": 300},
+					{"maxWrites": 100},
+				})
+			})
+
+			It("returns an error", func() {
+				_, err := chaincode.ResourceLimitsFromViper()
+				Expect(err).To(MatchError("invalid chaincode.resourceLimits.chaincodes: entry 1 has no chaincode name"))
+			})
+		})
+
+		Context("when a chaincode is listed more than once", func() {
+			BeforeEach(func() {
+				viper.Set("chaincode.resourceLimits.chaincodes", []map[string]interface{}{
+					{"name": "mycc", "maxWrites": 300},
+					{"name": "mycc", "maxWrites": 100},
+				})
+			})
+
+			It("returns an error", func() {
+				_, err := chaincode.ResourceLimitsFromViper()
+				Expect(err).To(MatchError("invalid chaincode.resourceLimits.chaincodes: chaincode mycc is listed more than once"))
+			})
+		})
+	})
+
 	Describe("IsDevMode", func() {
 		It("returns true when iff the mode equals 'dev'", func() {
 			viper.Set("chaincode.mode", chaincode.DevModeUserRunsChaincode)
@@ -111,14 +190,19 @@ var _ = Describe("Config", func() {
 func capture() (restore func()) {
 	viper.SetEnvPrefix("CORE")
 	viper.AutomaticEnv()
-	config := map[string]string{
-		"peer.tls.enabled":         viper.GetString("peer.tls.enabled"),
-		"chaincode.keepalive":      viper.GetString("chaincode.keepalive"),
-		"chaincode.executetimeout": viper.GetString("chaincode.executetimeout"),
-		"chaincode.startuptimeout": viper.GetString("chaincode.startuptimeout"),
-		"chaincode.logging.format": viper.GetString("chaincode.logging.format"),
-		"chaincode.logging.level":  viper.GetString("chaincode.logging.level"),
-		"chaincode.logging.shim":   viper.GetString("chaincode.logging.shim"),
+	config := map[string]interface{}{
+		"peer.tls.enabled":                              viper.Get("peer.tls.enabled"),
+		"chaincode.keepalive":                           viper.Get("chaincode.keepalive"),
+		"chaincode.executetimeout":                      viper.Get("chaincode.executetimeout"),
+		"chaincode.startuptimeout":                      viper.Get("chaincode.startuptimeout"),
+		"chaincode.logging.format":                      viper.Get("chaincode.logging.format"),
+		"chaincode.logging.level":                       viper.Get("chaincode.logging.level"),
+		"chaincode.logging.shim":                        viper.Get("chaincode.logging.shim"),
+		"chaincode.resourceLimits.maxStateReads":        viper.Get("chaincode.resourceLimits.maxStateReads"),
+		"chaincode.resourceLimits.maxRangeQueryResults": viper.Get("chaincode.resourceLimits.maxRangeQueryResults"),
+		"chaincode.resourceLimits.maxWrites":            viper.Get("chaincode.resourceLimits.maxWrites"),
+		"chaincode.resourceLimits.maxTotalBytes":        viper.Get("chaincode.resourceLimits.maxTotalBytes"),
+		"chaincode.resourceLimits.chaincodes":           viper.Get("chaincode.resourceLimits.chaincodes"),
 	}
 
 	return func() {
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
@@ -151,6 +152,8 @@ type Handler struct {
 	AppConfig ApplicationConfigRetriever
 	// Metrics holds chaincode handler metrics
 	Metrics *HandlerMetrics
+	// ResourceLimits holds the resource limits of user chaincodes
+	ResourceLimits *ResourceLimitsConfig
 
 	// state holds the current handler state. It will be created, established, or
 	// ready.
@@ -654,6 +657,9 @@ func (h *Handler) HandleGetState(msg *pb.ChaincodeMessage, txContext *Transactio
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if err := txContext.ResourceMeter.StateRead(len(res)); err != nil {
+		return nil, err
+	}
 	if res == nil {
 		chaincodeLogger.Debugf("[%s] No state associated with key: %s. Sending %s with an empty payload", shorttxid(msg.Txid), getState.Key, pb.ChaincodeMessage_RESPONSE)
 	}
@@ -680,6 +686,9 @@ func (h *Handler) HandleGetPrivateDataHash(msg *pb.ChaincodeMessage, txContext *
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if err := txContext.ResourceMeter.StateRead(len(res)); err != nil {
+		return nil, err
+	}
 	if res == nil {
 		chaincodeLogger.Debugf("[%s] No state associated with key: %s. Sending %s with an empty payload", shorttxid(msg.Txid), getState.Key, pb.ChaincodeMessage_RESPONSE)
 	}
@@ -728,6 +737,9 @@ func (h *Handler) HandleGetStateMetadata(msg *pb.ChaincodeMessage, txContext *Tr
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if err := txContext.ResourceMeter.StateRead(len(res)); err != nil {
+		return nil, err
+	}
 
 	// Send response msg back to chaincode. GetState will not trigger event
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
@@ -934,15 +946,35 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 		return nil, errors.Wrap(err, "unmarshal failed")
 	}
 
-	historyIter, err := txContext.HistoryQueryExecutor.GetHistoryForKey(chaincodeName, getHistoryForKey.Key)
+	metadata, err := getHistoryQueryMetadataFromBytes(getHistoryForKey.Metadata)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
 	}
 
+	var historyIter commonledger.ResultsIterator
+	isPaginated := false
 	totalReturnLimit := calculateTotalReturnLimit(nil)
 
+	if metadata == nil {
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKey(chaincodeName, getHistoryForKey.Key)
+	} else {
+		isPaginated = isMetadataSetForPagination(&pb.QueryMetadata{PageSize: metadata.PageSize, Bookmark: metadata.Bookmark})
+		if isPaginated {
+			totalReturnLimit = calculateTotalReturnLimit(&pb.QueryMetadata{PageSize: metadata.PageSize})
+		}
+		var options *ledger.HistoryQueryOptions
+		options, err = createHistoryQueryOptionsFromMetadata(metadata, isPaginated, totalReturnLimit)
+		if err != nil {
+			return nil, err
+		}
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKeyWithOptions(chaincodeName, getHistoryForKey.Key, options)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	txContext.InitializeQueryContext(iterID, historyIter)
-	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, false, totalReturnLimit)
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, isPaginated, totalReturnLimit)
 	if err != nil {
 		txContext.CleanupQueryContext(iterID)
 		return nil, errors.WithStack(err)
@@ -1002,6 +1034,45 @@ func createPaginationInfoFromMetadata(metadata *pb.QueryMetadata, totalReturnLim
 	return paginationInfoMap, nil
 }
 
+func getHistoryQueryMetadataFromBytes(metadataBytes []byte) (*pb.HistoryQueryMetadata, error) {
+	if metadataBytes != nil {
+		metadata := &pb.HistoryQueryMetadata{}
+		err := proto.Unmarshal(metadataBytes, metadata)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal failed")
+		}
+		return metadata, nil
+	}
+	return nil, nil
+}
+
+func createHistoryQueryOptionsFromMetadata(metadata *pb.HistoryQueryMetadata, isPaginated bool, totalReturnLimit int32) (*ledger.HistoryQueryOptions, error) {
+	options := &ledger.HistoryQueryOptions{
+		StartBlock:  metadata.StartBlock,
+		EndBlock:    metadata.EndBlock,
+		NewestFirst: metadata.NewestFirst,
+		Bookmark:    metadata.Bookmark,
+	}
+	if metadata.StartTime != nil {
+		startTime, err := ptypes.Timestamp(metadata.StartTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid start time")
+		}
+		options.StartTime = startTime
+	}
+	if metadata.EndTime != nil {
+		endTime, err := ptypes.Timestamp(metadata.EndTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid end time")
+		}
+		options.EndTime = endTime
+	}
+	if isPaginated {
+		options.PageSize = totalReturnLimit
+	}
+	return options, nil
+}
+
 func calculateTotalReturnLimit(metadata *pb.QueryMetadata) int32 {
 	totalReturnLimit := int32(ledgerconfig.GetTotalQueryLimit())
 	if metadata != nil {
@@ -1055,6 +1126,10 @@ func (h *Handler) HandlePutState(msg *pb.ChaincodeMessage, txContext *Transactio
 		return nil, errors.Wrap(err, "unmarshal failed")
 	}
 
+	if err := txContext.ResourceMeter.Write(len(putState.Key) + len(putState.Value)); err != nil {
+		return nil, err
+	}
+
 	chaincodeName := h.ChaincodeName()
 	collection := putState.Collection
 	if isCollectionSet(collection) {
@@ -1087,6 +1162,11 @@ func (h *Handler) HandlePutStateMetadata(msg *pb.ChaincodeMessage, txContext *Tr
 	metadata := make(map[string][]byte)
 	metadata[putStateMetadata.Metadata.Metakey] = putStateMetadata.Metadata.Value
 
+	size := len(putStateMetadata.Key) + len(putStateMetadata.Metadata.Metakey) + len(putStateMetadata.Metadata.Value)
+	if err := txContext.ResourceMeter.Write(size); err != nil {
+		return nil, err
+	}
+
 	chaincodeName := h.ChaincodeName()
 	collection := putStateMetadata.Collection
 	if isCollectionSet(collection) {
@@ -1111,6 +1191,10 @@ func (h *Handler) HandleDelState(msg *pb.ChaincodeMessage, txContext *Transactio
 		return nil, errors.Wrap(err, "unmarshal failed")
 	}
 
+	if err := txContext.ResourceMeter.Write(len(delState.Key)); err != nil {
+		return nil, err
+	}
+
 	chaincodeName := h.ChaincodeName()
 	collection := delState.Collection
 	if isCollectionSet(collection) {
@@ -1172,6 +1256,7 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 		Proposal:             txContext.Proposal,
 		TXSimulator:          txContext.TXSimulator,
 		HistoryQueryExecutor: txContext.HistoryQueryExecutor,
+		ResourceUsage:        txContext.ResourceUsage,
 	}
 
 	if targetInstance.ChainID != txContext.ChainID {
@@ -1224,6 +1309,10 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 	// Execute the chaincode... this CANNOT be an init at least for now
 	responseMessage, err := h.Invoker.Invoke(txParams, cccid, chaincodeSpec.Input)
 	if err != nil {
+		// the transaction fails even if the calling chaincode ignores the error
+		if limitErr, ok := errors.Cause(err).(*ResourceLimitExceededError); ok {
+			txContext.ResourceMeter.Abort(limitErr)
+		}
 		return nil, errors.Wrap(err, "execute failed")
 	}
 
@@ -1250,6 +1339,12 @@ func (h *Handler) Execute(txParams *ccprovider.TransactionParams, cccid *ccprovi
 	}
 	defer h.TXContexts.Delete(msg.ChannelId, msg.Txid)
 
+	ccName := cccid.Name + ":" + cccid.Version
+	txctx.ResourceMeter = &ResourceMeter{
+		Chaincode: ccName,
+		Limits:    h.resourceLimits(cccid.Name),
+	}
+
 	if err := h.setChaincodeProposal(txParams.SignedProp, txParams.Proposal, msg); err != nil {
 		return nil, err
 	}
@@ -1263,15 +1358,40 @@ func (h *Handler) Execute(txParams *ccprovider.TransactionParams, cccid *ccprovi
 		// are typically treated as error
 	case <-time.After(timeout):
 		err = errors.New("timeout expired while executing transaction")
-		ccName := cccid.Name + ":" + cccid.Version
 		h.Metrics.ExecuteTimeouts.With("chaincode", ccName).Add(1)
 	case <-h.streamDone():
 		err = errors.New("chaincode stream terminated")
 	}
 
+	usage := txctx.ResourceMeter.Usage()
+	txParams.ResourceUsage.Record(usage)
+	meterLabels := []string{"channel", msg.ChannelId, "chaincode", ccName}
+	h.Metrics.StateReads.With(meterLabels...).Add(float64(usage.StateReads))
+	h.Metrics.RangeQueryResults.With(meterLabels...).Add(float64(usage.RangeQueryResults))
+	h.Metrics.StateWrites.With(meterLabels...).Add(float64(usage.Writes))
+	h.Metrics.StateBytes.With(meterLabels...).Add(float64(usage.TotalBytes))
+
+	// a chaincode exceeding its resource limits fails the transaction,
+	// even if it ignores the errors returned to it and completes
+	if limitErr := txctx.ResourceMeter.Err(); limitErr != nil {
+		h.Metrics.ResourceLimitsExceeded.With(meterLabels...).Add(1)
+		if err == nil {
+			err = limitErr
+		}
+	}
+
 	return ccresp, err
 }
 
+// resourceLimits returns the resource limits of the named chaincode.
+// System chaincodes are not limited.
+func (h *Handler) resourceLimits(chaincodeName string) ResourceLimits {
+	if h.SystemCCProvider.IsSysCC(chaincodeName) {
+		return ResourceLimits{}
+	}
+	return h.ResourceLimits.Limits(chaincodeName)
+}
+
 func (h *Handler) setChaincodeProposal(signedProp *pb.SignedProposal, prop *pb.Proposal, msg *pb.ChaincodeMessage) error {
 	if prop != nil && signedProp == nil {
 		return errors.New("failed getting proposal context. Signed proposal is nil")
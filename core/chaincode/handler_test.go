@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/util"
@@ -20,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/mock"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -48,6 +50,11 @@ var _ = Describe("Handler", func() {
 		fakeShimRequestsCompleted      *metricsfakes.Counter
 		fakeShimRequestDuration        *metricsfakes.Histogram
 		fakeExecuteTimeouts            *metricsfakes.Counter
+		fakeStateReads                 *metricsfakes.Counter
+		fakeRangeQueryResults          *metricsfakes.Counter
+		fakeStateWrites                *metricsfakes.Counter
+		fakeStateBytes                 *metricsfakes.Counter
+		fakeResourceLimitsExceeded     *metricsfakes.Counter
 
 		responseNotifier chan *pb.ChaincodeMessage
 		txContext        *chaincode.TransactionContext
@@ -102,12 +109,27 @@ var _ = Describe("Handler", func() {
 		fakeShimRequestDuration.WithReturns(fakeShimRequestDuration)
 		fakeExecuteTimeouts = &metricsfakes.Counter{}
 		fakeExecuteTimeouts.WithReturns(fakeExecuteTimeouts)
+		fakeStateReads = &metricsfakes.Counter{}
+		fakeStateReads.WithReturns(fakeStateReads)
+		fakeRangeQueryResults = &metricsfakes.Counter{}
+		fakeRangeQueryResults.WithReturns(fakeRangeQueryResults)
+		fakeStateWrites = &metricsfakes.Counter{}
+		fakeStateWrites.WithReturns(fakeStateWrites)
+		fakeStateBytes = &metricsfakes.Counter{}
+		fakeStateBytes.WithReturns(fakeStateBytes)
+		fakeResourceLimitsExceeded = &metricsfakes.Counter{}
+		fakeResourceLimitsExceeded.WithReturns(fakeResourceLimitsExceeded)
 
 		chaincodeMetrics := &chaincode.HandlerMetrics{
-			ShimRequestsReceived:  fakeShimRequestsReceived,
-			ShimRequestsCompleted: fakeShimRequestsCompleted,
-			ShimRequestDuration:   fakeShimRequestDuration,
-			ExecuteTimeouts:       fakeExecuteTimeouts,
+			ShimRequestsReceived:   fakeShimRequestsReceived,
+			ShimRequestsCompleted:  fakeShimRequestsCompleted,
+			ShimRequestDuration:    fakeShimRequestDuration,
+			ExecuteTimeouts:        fakeExecuteTimeouts,
+			StateReads:             fakeStateReads,
+			RangeQueryResults:      fakeRangeQueryResults,
+			StateWrites:            fakeStateWrites,
+			StateBytes:             fakeStateBytes,
+			ResourceLimitsExceeded: fakeResourceLimitsExceeded,
 		}
 
 		handler = &chaincode.Handler{
@@ -551,6 +573,32 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the transaction has a resource meter", func() {
+			BeforeEach(func() {
+				txContext.ResourceMeter = &chaincode.ResourceMeter{
+					Chaincode: "cc-instance-name:1.0",
+					Limits:    chaincode.ResourceLimits{MaxWrites: 1},
+				}
+			})
+
+			It("accounts for the write", func() {
+				_, err := handler.HandlePutState(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				usage := txContext.ResourceMeter.Usage()
+				Expect(usage.Writes).To(Equal(uint64(1)))
+				Expect(usage.TotalBytes).To(Equal(uint64(len("put-state-key") + len("put-state-value"))))
+			})
+
+			It("fails the write exceeding the write limit", func() {
+				_, err := handler.HandlePutState(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = handler.HandlePutState(incomingMessage, txContext)
+				Expect(err).To(MatchError("chaincode cc-instance-name:1.0 exceeded its limit of 1 writes per transaction"))
+				Expect(fakeTxSimulator.SetStateCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("when the collection is not provided", func() {
 			It("calls SetState on the transaction simulator", func() {
 				_, err := handler.HandlePutState(incomingMessage, txContext)
@@ -798,6 +846,23 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the write limit has been reached", func() {
+			BeforeEach(func() {
+				txContext.ResourceMeter = &chaincode.ResourceMeter{
+					Chaincode: "cc-instance-name:1.0",
+					Limits:    chaincode.ResourceLimits{MaxWrites: 1},
+				}
+				err := txContext.ResourceMeter.Write(1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error without deleting the key", func() {
+				_, err := handler.HandleDelState(incomingMessage, txContext)
+				Expect(err).To(MatchError("chaincode cc-instance-name:1.0 exceeded its limit of 1 writes per transaction"))
+				Expect(fakeTxSimulator.DeleteStateCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when collection is not set", func() {
 			It("calls DeleteState on the transaction simulator", func() {
 				_, err := handler.HandleDelState(incomingMessage, txContext)
@@ -903,6 +968,32 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the transaction has a resource meter", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetStateReturns([]byte("get-state-response"), nil)
+				txContext.ResourceMeter = &chaincode.ResourceMeter{
+					Chaincode: "cc-instance-name:1.0",
+					Limits:    chaincode.ResourceLimits{MaxStateReads: 1},
+				}
+			})
+
+			It("accounts for the read", func() {
+				_, err := handler.HandleGetState(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				usage := txContext.ResourceMeter.Usage()
+				Expect(usage.StateReads).To(Equal(uint64(1)))
+				Expect(usage.TotalBytes).To(Equal(uint64(len("get-state-response"))))
+			})
+
+			It("fails the read exceeding the state read limit", func() {
+				_, err := handler.HandleGetState(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = handler.HandleGetState(incomingMessage, txContext)
+				Expect(err).To(MatchError("chaincode cc-instance-name:1.0 exceeded its limit of 1 state reads per transaction"))
+			})
+		})
+
 		Context("when collection is set", func() {
 			BeforeEach(func() {
 				request.Collection = "collection-name"
@@ -2000,6 +2091,88 @@ var _ = Describe("Handler", func() {
 			Expect(iterID).To(Equal("generated-query-id"))
 		})
 
+		Context("when history query metadata is provided", func() {
+			var startTime *timestamp.Timestamp
+
+			BeforeEach(func() {
+				startTime = &timestamp.Timestamp{Seconds: 1500000000}
+				metadata, err := proto.Marshal(&pb.HistoryQueryMetadata{
+					StartBlock:  2,
+					EndBlock:    5,
+					StartTime:   startTime,
+					NewestFirst: true,
+					PageSize:    10,
+					Bookmark:    "0101",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				request.Metadata = metadata
+				incomingMessage.Payload, err = proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsReturns(fakeIterator, nil)
+			})
+
+			It("calls GetHistoryForKeyWithOptions on the history query executor", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyCallCount()).To(Equal(0))
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsCallCount()).To(Equal(1))
+				ccname, key, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(key).To(Equal("history-key"))
+				Expect(options).To(Equal(&ledger.HistoryQueryOptions{
+					StartBlock:  2,
+					EndBlock:    5,
+					StartTime:   time.Unix(1500000000, 0).UTC(),
+					NewestFirst: true,
+					PageSize:    10,
+					Bookmark:    "0101",
+				}))
+			})
+
+			It("builds a paginated query response", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+				_, iter, _, isPaginated, totalReturnLimit := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+				Expect(iter).To(Equal(fakeIterator))
+				Expect(isPaginated).To(BeTrue())
+				Expect(totalReturnLimit).To(Equal(int32(10)))
+			})
+
+			Context("when the start time is invalid", func() {
+				BeforeEach(func() {
+					startTime.Nanos = -1
+					metadata, err := proto.Marshal(&pb.HistoryQueryMetadata{StartTime: startTime})
+					Expect(err).NotTo(HaveOccurred())
+					request.Metadata = metadata
+					incomingMessage.Payload, err = proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError(ContainSubstring("invalid start time")))
+				})
+			})
+
+			Context("when unmarshalling the metadata fails", func() {
+				BeforeEach(func() {
+					var err error
+					request.Metadata = []byte("this-is-a-bogus-payload")
+					incomingMessage.Payload, err = proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+				})
+			})
+		})
+
 		Context("when unmarshalling the request fails", func() {
 			BeforeEach(func() {
 				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
@@ -2380,6 +2553,32 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the invoked chaincode exceeds its resource limits", func() {
+			var limitErr *chaincode.ResourceLimitExceededError
+
+			BeforeEach(func() {
+				limitErr = &chaincode.ResourceLimitExceededError{Chaincode: "target-chaincode-name:1.0", Resource: "writes", Limit: 1}
+				fakeInvoker.InvokeReturns(nil, errors.WithMessage(limitErr, "error sending"))
+				txContext.ResourceMeter = &chaincode.ResourceMeter{Chaincode: "cc-instance-name:1.0"}
+			})
+
+			It("fails the transaction of the calling chaincode", func() {
+				_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+				Expect(err).To(MatchError("execute failed: error sending: chaincode target-chaincode-name:1.0 exceeded its limit of 1 writes per transaction"))
+				Expect(txContext.ResourceMeter.Err()).To(Equal(limitErr))
+			})
+		})
+
+		It("shares the resource usage of the transaction with the invoked chaincode", func() {
+			txContext.ResourceUsage = &ccprovider.ResourceUsage{}
+			_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeInvoker.InvokeCallCount()).To(Equal(1))
+			txParams, _, _ := fakeInvoker.InvokeArgsForCall(0)
+			Expect(txParams.ResourceUsage).To(BeIdenticalTo(txContext.ResourceUsage))
+		})
+
 		Context("when unmarshaling the request fails", func() {
 			BeforeEach(func() {
 				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
@@ -2503,6 +2702,83 @@ var _ = Describe("Handler", func() {
 			Expect(txid).To(Equal("tx-id"))
 		})
 
+		Context("when the chaincode uses resources", func() {
+			BeforeEach(func() {
+				txParams.ResourceUsage = &ccprovider.ResourceUsage{}
+				handler.ResourceLimits = &chaincode.ResourceLimitsConfig{
+					Default: chaincode.ResourceLimits{MaxWrites: 1},
+				}
+				fakeChatStream.SendStub = func(*pb.ChaincodeMessage) error {
+					txContext.ResourceMeter.StateRead(10)
+					txContext.ResourceMeter.Write(5)
+					responseNotifier <- &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED}
+					return nil
+				}
+			})
+
+			It("records the resources used by the chaincode", func() {
+				_, err := handler.Execute(txParams, cccid, incomingMessage, time.Second)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(txParams.ResourceUsage.Usage()).To(Equal([]*pb.ChaincodeResourceUsage{{
+					Chaincode:  "chaincode-name:chaincode-version",
+					StateReads: 1,
+					Writes:     1,
+					TotalBytes: 15,
+				}}))
+			})
+
+			It("records the resource usage metrics", func() {
+				_, err := handler.Execute(txParams, cccid, incomingMessage, time.Second)
+				Expect(err).NotTo(HaveOccurred())
+
+				labelValues := []string{"channel", "channel-id", "chaincode", "chaincode-name:chaincode-version"}
+				Expect(fakeStateReads.WithArgsForCall(0)).To(Equal(labelValues))
+				Expect(fakeStateReads.AddArgsForCall(0)).To(BeNumerically("~", 1.0))
+				Expect(fakeStateWrites.WithArgsForCall(0)).To(Equal(labelValues))
+				Expect(fakeStateWrites.AddArgsForCall(0)).To(BeNumerically("~", 1.0))
+				Expect(fakeRangeQueryResults.WithArgsForCall(0)).To(Equal(labelValues))
+				Expect(fakeRangeQueryResults.AddArgsForCall(0)).To(BeNumerically("~", 0.0))
+				Expect(fakeStateBytes.WithArgsForCall(0)).To(Equal(labelValues))
+				Expect(fakeStateBytes.AddArgsForCall(0)).To(BeNumerically("~", 15.0))
+				Expect(fakeResourceLimitsExceeded.AddCallCount()).To(Equal(0))
+			})
+
+			Context("and exceeds its resource limits", func() {
+				BeforeEach(func() {
+					fakeChatStream.SendStub = func(*pb.ChaincodeMessage) error {
+						txContext.ResourceMeter.Write(5)
+						txContext.ResourceMeter.Write(5)
+						responseNotifier <- &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED}
+						return nil
+					}
+				})
+
+				It("fails the transaction even though the chaincode completed", func() {
+					_, err := handler.Execute(txParams, cccid, incomingMessage, time.Second)
+					Expect(err).To(MatchError("chaincode chaincode-name:chaincode-version exceeded its limit of 1 writes per transaction"))
+
+					Expect(fakeResourceLimitsExceeded.WithCallCount()).To(Equal(1))
+					Expect(fakeResourceLimitsExceeded.WithArgsForCall(0)).To(Equal([]string{
+						"channel", "channel-id", "chaincode", "chaincode-name:chaincode-version",
+					}))
+					Expect(fakeResourceLimitsExceeded.AddArgsForCall(0)).To(BeNumerically("~", 1.0))
+				})
+
+				Context("when the chaincode is a system chaincode", func() {
+					BeforeEach(func() {
+						fakeSystemCCProvider.IsSysCCReturns(true)
+					})
+
+					It("does not limit the chaincode", func() {
+						_, err := handler.Execute(txParams, cccid, incomingMessage, time.Second)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(txParams.ResourceUsage.Usage()[0].Writes).To(Equal(uint64(2)))
+					})
+				})
+			})
+		})
+
 		Context("when the serial send fails", func() {
 			BeforeEach(func() {
 				fakeChatStream.SendReturns(errors.New("where-is-waldo?"))
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// ChaincodePublicLedgerShim gives access to the public state
+// of the namespace of the chaincode through the stub
+type ChaincodePublicLedgerShim struct {
+	shim.ChaincodeStubInterface
+}
+
+// ChaincodePrivateLedgerShim gives access to the private state of
+// a collection of the namespace of the chaincode through the stub
+type ChaincodePrivateLedgerShim struct {
+	Stub       shim.ChaincodeStubInterface
+	Collection string
+}
+
+// GetState returns the value of the key in the collection
+func (cls *ChaincodePrivateLedgerShim) GetState(key string) ([]byte, error) {
+	return cls.Stub.GetPrivateData(cls.Collection, key)
+}
+
+// GetStateHash returns the hash of the value of the key in the collection,
+// which is available even if the peer is not a member of the collection
+func (cls *ChaincodePrivateLedgerShim) GetStateHash(key string) ([]byte, error) {
+	return cls.Stub.GetPrivateDataHash(cls.Collection, key)
+}
+
+// PutState sets the value of the key in the collection
+func (cls *ChaincodePrivateLedgerShim) PutState(key string, value []byte) error {
+	return cls.Stub.PutPrivateData(cls.Collection, key, value)
+}
+
+// PeerLedgers gives access to the ledgers of the channels the peer has joined
+type PeerLedgers interface {
+	// GetChannelsInfo returns the info of the channels the peer has joined
+	GetChannelsInfo() []*pb.ChannelInfo
+
+	// NewQueryExecutor returns a query executor on the ledger of the channel
+	NewQueryExecutor(channelID string) (StateQueryExecutor, error)
+}
+
+// StateQueryExecutor reads the committed public state of a ledger,
+// and is implemented by ledger.QueryExecutor
+type StateQueryExecutor interface {
+	GetState(namespace, key string) ([]byte, error)
+	Done()
+}
+
+// PeerLedgerShim gives access to the public state of the
+// channels the peer has joined through their ledgers
+type PeerLedgerShim struct {
+	Peer PeerLedgers
+}
+
+// ChannelIDs returns the IDs of the channels the peer has joined
+func (pls *PeerLedgerShim) ChannelIDs() []string {
+	var channelIDs []string
+	for _, channelInfo := range pls.Peer.GetChannelsInfo() {
+		channelIDs = append(channelIDs, channelInfo.ChannelId)
+	}
+	return channelIDs
+}
+
+// GetState returns the committed value of the key in the namespace on the channel
+func (pls *PeerLedgerShim) GetState(channelID, namespace, key string) ([]byte, error) {
+	qe, err := pls.Peer.NewQueryExecutor(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not create query executor")
+	}
+	defer qe.Done()
+
+	return qe.GetState(namespace, key)
+}
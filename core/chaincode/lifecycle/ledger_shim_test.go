@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle_test
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ChaincodePrivateLedgerShim", func() {
+	var (
+		fakeStub *mock.ChaincodeStub
+		cls      *lifecycle.ChaincodePrivateLedgerShim
+	)
+
+	BeforeEach(func() {
+		fakeStub = &mock.ChaincodeStub{}
+		cls = &lifecycle.ChaincodePrivateLedgerShim{
+			Stub:       fakeStub,
+			Collection: "fake-collection",
+		}
+	})
+
+	Describe("GetState", func() {
+		BeforeEach(func() {
+			fakeStub.GetPrivateDataReturns([]byte("fake-value"), fmt.Errorf("fake-error"))
+		})
+
+		It("passes through to the stub", func() {
+			res, err := cls.GetState("fake-key")
+			Expect(res).To(Equal([]byte("fake-value")))
+			Expect(err).To(MatchError(fmt.Errorf("fake-error")))
+			Expect(fakeStub.GetPrivateDataCallCount()).To(Equal(1))
+			collection, key := fakeStub.GetPrivateDataArgsForCall(0)
+			Expect(collection).To(Equal("fake-collection"))
+			Expect(key).To(Equal("fake-key"))
+		})
+	})
+
+	Describe("GetStateHash", func() {
+		BeforeEach(func() {
+			fakeStub.GetPrivateDataHashReturns([]byte("fake-hash"), fmt.Errorf("fake-error"))
+		})
+
+		It("passes through to the stub", func() {
+			res, err := cls.GetStateHash("fake-key")
+			Expect(res).To(Equal([]byte("fake-hash")))
+			Expect(err).To(MatchError(fmt.Errorf("fake-error")))
+			Expect(fakeStub.GetPrivateDataHashCallCount()).To(Equal(1))
+			collection, key := fakeStub.GetPrivateDataHashArgsForCall(0)
+			Expect(collection).To(Equal("fake-collection"))
+			Expect(key).To(Equal("fake-key"))
+		})
+	})
+
+	Describe("PutState", func() {
+		BeforeEach(func() {
+			fakeStub.PutPrivateDataReturns(fmt.Errorf("fake-error"))
+		})
+
+		It("passes through to the stub", func() {
+			err := cls.PutState("fake-key", []byte("fake-value"))
+			Expect(err).To(MatchError(fmt.Errorf("fake-error")))
+			Expect(fakeStub.PutPrivateDataCallCount()).To(Equal(1))
+			collection, key, value := fakeStub.PutPrivateDataArgsForCall(0)
+			Expect(collection).To(Equal("fake-collection"))
+			Expect(key).To(Equal("fake-key"))
+			Expect(value).To(Equal([]byte("fake-value")))
+		})
+	})
+})
+
+var _ = Describe("PeerLedgerShim", func() {
+	var (
+		fakePeer          *mock.PeerLedgers
+		fakeQueryExecutor *mock.StateQueryExecutor
+		pls               *lifecycle.PeerLedgerShim
+	)
+
+	BeforeEach(func() {
+		fakeQueryExecutor = &mock.StateQueryExecutor{}
+		fakePeer = &mock.PeerLedgers{}
+		fakePeer.NewQueryExecutorReturns(fakeQueryExecutor, nil)
+		pls = &lifecycle.PeerLedgerShim{Peer: fakePeer}
+	})
+
+	Describe("ChannelIDs", func() {
+		BeforeEach(func() {
+			fakePeer.GetChannelsInfoReturns([]*pb.ChannelInfo{{ChannelId: "channel1"}, {ChannelId: "channel2"}})
+		})
+
+		It("returns the IDs of the channels of the peer", func() {
+			Expect(pls.ChannelIDs()).To(Equal([]string{"channel1", "channel2"}))
+		})
+	})
+
+	Describe("GetState", func() {
+		BeforeEach(func() {
+			fakeQueryExecutor.GetStateReturns([]byte("fake-value"), fmt.Errorf("fake-error"))
+		})
+
+		It("passes through to a query executor of the ledger of the channel", func() {
+			res, err := pls.GetState("channel1", "fake-namespace", "fake-key")
+			Expect(res).To(Equal([]byte("fake-value")))
+			Expect(err).To(MatchError(fmt.Errorf("fake-error")))
+			Expect(fakePeer.NewQueryExecutorArgsForCall(0)).To(Equal("channel1"))
+			Expect(fakeQueryExecutor.GetStateCallCount()).To(Equal(1))
+			namespace, key := fakeQueryExecutor.GetStateArgsForCall(0)
+			Expect(namespace).To(Equal("fake-namespace"))
+			Expect(key).To(Equal("fake-key"))
+			Expect(fakeQueryExecutor.DoneCallCount()).To(Equal(1))
+		})
+
+		Context("when the query executor cannot be created", func() {
+			BeforeEach(func() {
+				fakePeer.NewQueryExecutorReturns(nil, fmt.Errorf("qe-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := pls.GetState("channel1", "fake-namespace", "fake-key")
+				Expect(err).To(MatchError("could not create query executor: qe-error"))
+			})
+		})
+	})
+})
@@ -7,27 +7,86 @@ SPDX-License-Identifier: Apache-2.0
 package lifecycle
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	"github.com/pkg/errors"
 )
 
+// legacyLifecycleNamespace is the namespace of the definitions of the
+// chaincodes instantiated through LSCC
+const legacyLifecycleNamespace = "lscc"
+
 // ChaincodeStore provides a way to persist chaincodes
 type ChaincodeStore interface {
 	Save(name, version string, ccInstallPkg []byte) (hash []byte, err error)
 	RetrieveHash(name, version string) (hash []byte, err error)
+	Delete(hash []byte) error
 }
 
 type PackageParser interface {
 	Parse(data []byte) (*persistence.ChaincodePackage, error)
 }
 
+// ReadableState is the state which chaincode definitions are read from
+type ReadableState interface {
+	GetState(key string) (value []byte, err error)
+}
+
+// ReadWritableState is the state which chaincode definitions are written to
+type ReadWritableState interface {
+	ReadableState
+	PutState(key string, value []byte) error
+}
+
+// OpaqueState is the state of which only the hashes of the values are
+// available, such as the implicit collection of another organization
+type OpaqueState interface {
+	GetStateHash(key string) (valueHash []byte, err error)
+}
+
+// ChannelStates gives read access to the public state of the channels
+// the peer has joined
+type ChannelStates interface {
+	// ChannelIDs returns the IDs of the channels the peer has joined
+	ChannelIDs() []string
+
+	// GetState returns the value of the key in the namespace on the channel
+	GetState(channelID, namespace, key string) (value []byte, err error)
+}
+
+// ChaincodeStopper stops the running instance of a chaincode, if any
+type ChaincodeStopper interface {
+	Stop(ccci *ccprovider.ChaincodeContainerInfo) error
+}
+
+// ImageRemover removes the image built for a chaincode
+type ImageRemover interface {
+	RemoveImage(ccid ccintf.CCID) error
+}
+
 // Lifecycle implements the lifecycle operations which are invoked
 // by the SCC as well as internally
 type Lifecycle struct {
 	ChaincodeStore ChaincodeStore
 	PackageParser  PackageParser
+	ChannelStates  ChannelStates
+
+	// ChaincodeStopper stops the running instances of uninstalled chaincodes
+	ChaincodeStopper ChaincodeStopper
+
+	// ImageRemover removes the images of uninstalled chaincodes, and is
+	// nil if the peer does not build images for chaincodes
+	ImageRemover ImageRemover
 }
 
 // InstallChaincode installs a given chaincode to the peer's chaincode store.
@@ -56,3 +115,213 @@ func (l *Lifecycle) QueryInstalledChaincode(name, version string) ([]byte, error
 
 	return hash, nil
 }
+
+// UninstallChaincode stops the chaincode of a given name and version if it is
+// running, and removes it from the peer's chaincode store, along with the image
+// built for it. It refuses
+// to remove a chaincode which is defined at that version on any channel the peer
+// has joined. It returns the hash of the removed chaincode.
+func (l *Lifecycle) UninstallChaincode(name, version string) ([]byte, error) {
+	hash, err := l.ChaincodeStore.RetrieveHash(name, version)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not retrieve hash for chaincode '%s:%s'", name, version))
+	}
+
+	channelIDs := l.ChannelStates.ChannelIDs()
+	sort.Strings(channelIDs)
+	for _, channelID := range channelIDs {
+		inUse, err := l.definedAtVersion(channelID, name, version)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not check definition of chaincode '%s' on channel '%s'", name, channelID))
+		}
+		if inUse {
+			return nil, errors.Errorf("chaincode '%s:%s' is in use by its definition on channel '%s'", name, version, channelID)
+		}
+	}
+
+	if l.ChaincodeStopper != nil {
+		err := l.ChaincodeStopper.Stop(&ccprovider.ChaincodeContainerInfo{
+			Name:          name,
+			Version:       version,
+			ContainerType: pb.ChaincodeDeploymentSpec_DOCKER.String(),
+		})
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not stop chaincode '%s:%s'", name, version))
+		}
+	}
+
+	if l.ImageRemover != nil {
+		if err := l.ImageRemover.RemoveImage(ccintf.CCID{Name: name, Version: version}); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not remove image of chaincode '%s:%s'", name, version))
+		}
+	}
+
+	if err := l.ChaincodeStore.Delete(hash); err != nil {
+		return nil, errors.WithMessage(err, "could not delete cc install package")
+	}
+
+	return hash, nil
+}
+
+// definedAtVersion returns whether the committed definition or the legacy
+// definition of the chaincode on the channel is for the given version
+func (l *Lifecycle) definedAtVersion(channelID, name, version string) (bool, error) {
+	cd, err := committedDefinition(name, &channelState{
+		ChannelStates: l.ChannelStates,
+		channelID:     channelID,
+		namespace:     privdata.LifecycleNamespace,
+	})
+	if err != nil {
+		return false, err
+	}
+	if cd != nil && cd.Version == version {
+		return true, nil
+	}
+
+	chaincodeDataBytes, err := l.ChannelStates.GetState(channelID, legacyLifecycleNamespace, name)
+	if err != nil {
+		return false, errors.WithMessage(err, "could not read legacy definition")
+	}
+	if chaincodeDataBytes == nil {
+		return false, nil
+	}
+	chaincodeData := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(chaincodeDataBytes, chaincodeData); err != nil {
+		return false, errors.Wrap(err, "could not unmarshal legacy definition")
+	}
+
+	return chaincodeData.Version == version, nil
+}
+
+// ApproveChaincodeDefinitionForOrg records the approval of the definition of the
+// chaincode with the given name by an organization in the organization's state.
+// Only the definition following the committed definition of the chaincode, if
+// any, may be approved.
+func (l *Lifecycle) ApproveChaincodeDefinitionForOrg(name string, cd *lb.ChaincodeDefinition, publicState ReadableState, orgState ReadWritableState) error {
+	definitionBytes, err := marshalNextDefinition(name, cd, publicState)
+	if err != nil {
+		return err
+	}
+
+	if err := orgState.PutState(privdata.ChaincodeApprovalKey(name, cd.Sequence), definitionBytes); err != nil {
+		return errors.WithMessage(err, "could not write approval to org state")
+	}
+
+	return nil
+}
+
+// QueryApprovalStatus returns whether each of the organizations whose states are
+// given by MSP ID approved the definition of the chaincode with the given name.
+func (l *Lifecycle) QueryApprovalStatus(name string, cd *lb.ChaincodeDefinition, publicState ReadableState, orgStates map[string]OpaqueState) (map[string]bool, error) {
+	definitionBytes, err := marshalNextDefinition(name, cd, publicState)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionHash := util.ComputeSHA256(definitionBytes)
+	approvals := map[string]bool{}
+	for mspID, orgState := range orgStates {
+		approvalHash, err := orgState.GetStateHash(privdata.ChaincodeApprovalKey(name, cd.Sequence))
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not read approval of org %s", mspID))
+		}
+		approvals[mspID] = bytes.Equal(approvalHash, definitionHash)
+	}
+
+	return approvals, nil
+}
+
+// CommitChaincodeDefinition records the definition of the chaincode with the given
+// name in the public state, and returns whether each of the organizations whose
+// states are given by MSP ID approved it. Whether enough organizations approved the
+// definition is checked when the transaction is validated. The committed definition
+// is a record of the agreement of the organizations only: the chaincode is still
+// executed and validated according to its definition instantiated through LSCC.
+func (l *Lifecycle) CommitChaincodeDefinition(name string, cd *lb.ChaincodeDefinition, publicState ReadWritableState, orgStates map[string]OpaqueState) (map[string]bool, error) {
+	approvals, err := l.QueryApprovalStatus(name, cd, publicState, orgStates)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionBytes, err := proto.Marshal(cd)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal chaincode definition")
+	}
+	if err := publicState.PutState(privdata.ChaincodeDefinitionKey(name), definitionBytes); err != nil {
+		return nil, errors.WithMessage(err, "could not write chaincode definition to public state")
+	}
+
+	return approvals, nil
+}
+
+// QueryChaincodeDefinition returns the committed definition of the chaincode with the given name.
+func (l *Lifecycle) QueryChaincodeDefinition(name string, publicState ReadableState) (*lb.ChaincodeDefinition, error) {
+	cd, err := committedDefinition(name, publicState)
+	if err != nil {
+		return nil, err
+	}
+	if cd == nil {
+		return nil, errors.Errorf("chaincode '%s' has no committed definition", name)
+	}
+
+	return cd, nil
+}
+
+// marshalNextDefinition checks that the definition of the chaincode follows its
+// committed definition, if any, and returns the marshaled definition
+func marshalNextDefinition(name string, cd *lb.ChaincodeDefinition, publicState ReadableState) ([]byte, error) {
+	if name == "" {
+		return nil, errors.New("chaincode name must be specified")
+	}
+	if cd == nil {
+		return nil, errors.New("chaincode definition must be specified")
+	}
+	if cd.Version == "" {
+		return nil, errors.New("chaincode version must be specified")
+	}
+
+	current, err := committedDefinition(name, publicState)
+	if err != nil {
+		return nil, err
+	}
+	nextSequence := current.GetSequence() + 1
+	if cd.Sequence != nextSequence {
+		return nil, errors.Errorf("requested sequence is %d, but new definition must be sequence %d", cd.Sequence, nextSequence)
+	}
+
+	definitionBytes, err := proto.Marshal(cd)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal chaincode definition")
+	}
+
+	return definitionBytes, nil
+}
+
+// committedDefinition returns the committed definition of the chaincode, or nil if there is none
+func committedDefinition(name string, publicState ReadableState) (*lb.ChaincodeDefinition, error) {
+	definitionBytes, err := publicState.GetState(privdata.ChaincodeDefinitionKey(name))
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not read committed definition of chaincode '%s'", name))
+	}
+	if definitionBytes == nil {
+		return nil, nil
+	}
+
+	cd := &lb.ChaincodeDefinition{}
+	if err := proto.Unmarshal(definitionBytes, cd); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal committed definition of chaincode '%s'", name)
+	}
+
+	return cd, nil
+}
+
+// channelState is the public state of a namespace on a channel
+type channelState struct {
+	ChannelStates
+	channelID string
+	namespace string
+}
+
+func (cs *channelState) GetState(key string) ([]byte, error) {
+	return cs.ChannelStates.GetState(cs.channelID, cs.namespace, key)
+}
@@ -35,6 +35,46 @@ type sccFunctions interface {
 	lifecycle.SCCFunctions
 }
 
+//go:generate counterfeiter -o mock/channel_config_source.go --fake-name ChannelConfigSource . channelConfigSource
+type channelConfigSource interface {
+	lifecycle.ChannelConfigSource
+}
+
+//go:generate counterfeiter -o mock/application_config_retriever.go --fake-name ApplicationConfigRetriever . applicationConfigRetriever
+type applicationConfigRetriever interface {
+	lifecycle.ApplicationConfigRetriever
+}
+
+//go:generate counterfeiter -o mock/policy_checker.go --fake-name PolicyChecker . policyChecker
+type policyChecker interface {
+	lifecycle.PolicyChecker
+}
+
+//go:generate counterfeiter -o mock/channel_states.go --fake-name ChannelStates . channelStates
+type channelStates interface {
+	lifecycle.ChannelStates
+}
+
+//go:generate counterfeiter -o mock/chaincode_stopper.go --fake-name ChaincodeStopper . chaincodeStopper
+type chaincodeStopper interface {
+	lifecycle.ChaincodeStopper
+}
+
+//go:generate counterfeiter -o mock/image_remover.go --fake-name ImageRemover . imageRemover
+type imageRemover interface {
+	lifecycle.ImageRemover
+}
+
+//go:generate counterfeiter -o mock/peer_ledgers.go --fake-name PeerLedgers . peerLedgers
+type peerLedgers interface {
+	lifecycle.PeerLedgers
+}
+
+//go:generate counterfeiter -o mock/state_query_executor.go --fake-name StateQueryExecutor . stateQueryExecutor
+type stateQueryExecutor interface {
+	lifecycle.StateQueryExecutor
+}
+
 func TestLifecycle(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Lifecycle Suite")
@@ -9,8 +9,13 @@ package lifecycle_test
 import (
 	"fmt"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -103,4 +108,392 @@ var _ = Describe("Lifecycle", func() {
 			})
 		})
 	})
+
+	Describe("UninstallChaincode", func() {
+		var (
+			fakeChannelStates    *mock.ChannelStates
+			fakeChaincodeStopper *mock.ChaincodeStopper
+			fakeImageRemover     *mock.ImageRemover
+			channelStates        map[string]mapState
+		)
+
+		BeforeEach(func() {
+			channelStates = map[string]mapState{
+				"channel1/+lifecycle": {},
+				"channel1/lscc":       {},
+				"channel2/+lifecycle": {},
+				"channel2/lscc":       {},
+			}
+			fakeChannelStates = &mock.ChannelStates{}
+			fakeChannelStates.ChannelIDsReturns([]string{"channel2", "channel1"})
+			fakeChannelStates.GetStateStub = func(channelID, namespace, key string) ([]byte, error) {
+				return channelStates[channelID+"/"+namespace].GetState(key)
+			}
+			fakeChaincodeStopper = &mock.ChaincodeStopper{}
+			fakeImageRemover = &mock.ImageRemover{}
+			fakeImageRemover.RemoveImageStub = func(ccintf.CCID) error {
+				Expect(fakeChaincodeStopper.StopCallCount()).To(Equal(1))
+				return nil
+			}
+			fakeCCStore.RetrieveHashReturns([]byte("fake-hash"), nil)
+
+			l.ChannelStates = fakeChannelStates
+			l.ChaincodeStopper = fakeChaincodeStopper
+			l.ImageRemover = fakeImageRemover
+		})
+
+		It("stops the chaincode, and removes its image and the chaincode", func() {
+			definitionBytes, err := proto.Marshal(&lb.ChaincodeDefinition{Sequence: 1, Version: "other-version"})
+			Expect(err).NotTo(HaveOccurred())
+			channelStates["channel1/+lifecycle"]["definitions/name"] = definitionBytes
+
+			hash, err := l.UninstallChaincode("name", "version")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal([]byte("fake-hash")))
+
+			Expect(fakeChaincodeStopper.StopCallCount()).To(Equal(1))
+			Expect(fakeChaincodeStopper.StopArgsForCall(0)).To(Equal(&ccprovider.ChaincodeContainerInfo{
+				Name:          "name",
+				Version:       "version",
+				ContainerType: "DOCKER",
+			}))
+			Expect(fakeImageRemover.RemoveImageCallCount()).To(Equal(1))
+			Expect(fakeImageRemover.RemoveImageArgsForCall(0)).To(Equal(ccintf.CCID{Name: "name", Version: "version"}))
+			Expect(fakeCCStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeCCStore.DeleteArgsForCall(0)).To(Equal([]byte("fake-hash")))
+		})
+
+		Context("when the peer does not build images", func() {
+			BeforeEach(func() {
+				l.ImageRemover = nil
+			})
+
+			It("removes the chaincode", func() {
+				_, err := l.UninstallChaincode("name", "version")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeCCStore.DeleteCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the chaincode is not installed", func() {
+			BeforeEach(func() {
+				fakeCCStore.RetrieveHashReturns(nil, fmt.Errorf("fake-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				hash, err := l.UninstallChaincode("name", "version")
+				Expect(hash).To(BeNil())
+				Expect(err).To(MatchError("could not retrieve hash for chaincode 'name:version': fake-error"))
+			})
+		})
+
+		Context("when the committed definition of the chaincode on a channel is for the version", func() {
+			BeforeEach(func() {
+				definitionBytes, err := proto.Marshal(&lb.ChaincodeDefinition{Sequence: 2, Version: "version"})
+				Expect(err).NotTo(HaveOccurred())
+				channelStates["channel2/+lifecycle"]["definitions/name"] = definitionBytes
+			})
+
+			It("refuses to remove the chaincode", func() {
+				hash, err := l.UninstallChaincode("name", "version")
+				Expect(hash).To(BeNil())
+				Expect(err).To(MatchError("chaincode 'name:version' is in use by its definition on channel 'channel2'"))
+				Expect(fakeChaincodeStopper.StopCallCount()).To(Equal(0))
+				Expect(fakeImageRemover.RemoveImageCallCount()).To(Equal(0))
+				Expect(fakeCCStore.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the legacy definition of the chaincode on a channel is for the version", func() {
+			BeforeEach(func() {
+				chaincodeDataBytes, err := proto.Marshal(&ccprovider.ChaincodeData{Name: "name", Version: "version"})
+				Expect(err).NotTo(HaveOccurred())
+				channelStates["channel1/lscc"]["name"] = chaincodeDataBytes
+			})
+
+			It("refuses to remove the chaincode", func() {
+				_, err := l.UninstallChaincode("name", "version")
+				Expect(err).To(MatchError("chaincode 'name:version' is in use by its definition on channel 'channel1'"))
+				Expect(fakeCCStore.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the legacy definition cannot be unmarshaled", func() {
+			BeforeEach(func() {
+				channelStates["channel1/lscc"]["name"] = []byte("garbage")
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := l.UninstallChaincode("name", "version")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("could not check definition of chaincode 'name' on channel 'channel1': could not unmarshal legacy definition"))
+			})
+		})
+
+		Context("when reading the state of a channel fails", func() {
+			BeforeEach(func() {
+				fakeChannelStates.GetStateStub = nil
+				fakeChannelStates.GetStateReturns(nil, fmt.Errorf("state-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := l.UninstallChaincode("name", "version")
+				Expect(err).To(MatchError("could not check definition of chaincode 'name' on channel 'channel1': could not read committed definition of chaincode 'name': state-error"))
+			})
+		})
+
+		Context("when stopping the chaincode fails", func() {
+			BeforeEach(func() {
+				fakeChaincodeStopper.StopReturns(fmt.Errorf("stop-error"))
+			})
+
+			It("keeps the image and the chaincode", func() {
+				_, err := l.UninstallChaincode("name", "version")
+				Expect(err).To(MatchError("could not stop chaincode 'name:version': stop-error"))
+				Expect(fakeImageRemover.RemoveImageCallCount()).To(Equal(0))
+				Expect(fakeCCStore.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when removing the image fails", func() {
+			BeforeEach(func() {
+				fakeImageRemover.RemoveImageReturns(fmt.Errorf("image-error"))
+			})
+
+			It("keeps the chaincode", func() {
+				_, err := l.UninstallChaincode("name", "version")
+				Expect(err).To(MatchError("could not remove image of chaincode 'name:version': image-error"))
+				Expect(fakeCCStore.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when deleting the chaincode fails", func() {
+			BeforeEach(func() {
+				fakeCCStore.DeleteReturns(fmt.Errorf("delete-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := l.UninstallChaincode("name", "version")
+				Expect(err).To(MatchError("could not delete cc install package: delete-error"))
+			})
+		})
+	})
+
+	Describe("Chaincode definitions", func() {
+		var (
+			publicState mapState
+			org1State   mapState
+			org2State   mapState
+			orgStates   map[string]lifecycle.OpaqueState
+			definition  *lb.ChaincodeDefinition
+		)
+
+		BeforeEach(func() {
+			publicState = mapState{}
+			org1State = mapState{}
+			org2State = mapState{}
+			orgStates = map[string]lifecycle.OpaqueState{
+				"org1": org1State,
+				"org2": org2State,
+			}
+			definition = &lb.ChaincodeDefinition{
+				Sequence:          1,
+				Version:           "version",
+				EndorsementPlugin: "escc",
+				ValidationPlugin:  "vscc",
+			}
+		})
+
+		Describe("ApproveChaincodeDefinitionForOrg", func() {
+			It("writes the approval to the org state", func() {
+				err := l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org1State)
+				Expect(err).NotTo(HaveOccurred())
+
+				approved := &lb.ChaincodeDefinition{}
+				err = proto.Unmarshal(org1State["approvals/name#1"], approved)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(approved, definition)).To(BeTrue())
+				Expect(publicState).To(BeEmpty())
+			})
+
+			Context("when the definition does not follow the committed definition", func() {
+				BeforeEach(func() {
+					definition.Sequence = 3
+				})
+
+				It("returns an error", func() {
+					err := l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org1State)
+					Expect(err).To(MatchError("requested sequence is 3, but new definition must be sequence 1"))
+				})
+			})
+
+			Context("when the version is missing", func() {
+				BeforeEach(func() {
+					definition.Version = ""
+				})
+
+				It("returns an error", func() {
+					err := l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org1State)
+					Expect(err).To(MatchError("chaincode version must be specified"))
+				})
+			})
+
+			Context("when reading the committed definition fails", func() {
+				It("wraps and returns the error", func() {
+					err := l.ApproveChaincodeDefinitionForOrg("name", definition, failingState{}, org1State)
+					Expect(err).To(MatchError("could not read committed definition of chaincode 'name': state-error"))
+				})
+			})
+
+			Context("when writing the approval fails", func() {
+				It("wraps and returns the error", func() {
+					err := l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, failingState{})
+					Expect(err).To(MatchError("could not write approval to org state: state-error"))
+				})
+			})
+		})
+
+		Describe("QueryApprovalStatus", func() {
+			BeforeEach(func() {
+				err := l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org1State)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns which orgs approved the definition", func() {
+				approvals, err := l.QueryApprovalStatus("name", definition, publicState, orgStates)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(approvals).To(Equal(map[string]bool{"org1": true, "org2": false}))
+			})
+
+			Context("when an org approved a different definition", func() {
+				BeforeEach(func() {
+					definition.Version = "other-version"
+					err := l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org2State)
+					Expect(err).NotTo(HaveOccurred())
+					definition.Version = "version"
+				})
+
+				It("does not count the approval", func() {
+					approvals, err := l.QueryApprovalStatus("name", definition, publicState, orgStates)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(approvals).To(Equal(map[string]bool{"org1": true, "org2": false}))
+				})
+			})
+
+			Context("when reading an approval fails", func() {
+				BeforeEach(func() {
+					orgStates["org2"] = failingState{}
+				})
+
+				It("wraps and returns the error", func() {
+					_, err := l.QueryApprovalStatus("name", definition, publicState, orgStates)
+					Expect(err).To(MatchError("could not read approval of org org2: state-error"))
+				})
+			})
+		})
+
+		Describe("CommitChaincodeDefinition", func() {
+			BeforeEach(func() {
+				err := l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org2State)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("writes the definition to the public state and returns the approvals", func() {
+				approvals, err := l.CommitChaincodeDefinition("name", definition, publicState, orgStates)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(approvals).To(Equal(map[string]bool{"org1": false, "org2": true}))
+
+				committed, err := l.QueryChaincodeDefinition("name", publicState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(committed, definition)).To(BeTrue())
+			})
+
+			It("requires the next definition to have the next sequence", func() {
+				_, err := l.CommitChaincodeDefinition("name", definition, publicState, orgStates)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org1State)
+				Expect(err).To(MatchError("requested sequence is 1, but new definition must be sequence 2"))
+
+				definition.Sequence = 2
+				err = l.ApproveChaincodeDefinitionForOrg("name", definition, publicState, org1State)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when writing the definition fails", func() {
+				It("wraps and returns the error", func() {
+					_, err := l.CommitChaincodeDefinition("name", definition, readOnlyState(publicState), orgStates)
+					Expect(err).To(MatchError("could not write chaincode definition to public state: state-error"))
+				})
+			})
+		})
+
+		Describe("QueryChaincodeDefinition", func() {
+			Context("when the chaincode has no committed definition", func() {
+				It("returns an error", func() {
+					_, err := l.QueryChaincodeDefinition("name", publicState)
+					Expect(err).To(MatchError("chaincode 'name' has no committed definition"))
+				})
+			})
+
+			Context("when the committed definition cannot be unmarshaled", func() {
+				BeforeEach(func() {
+					publicState["definitions/name"] = []byte("garbage")
+				})
+
+				It("returns an error", func() {
+					_, err := l.QueryChaincodeDefinition("name", publicState)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(HavePrefix("could not unmarshal committed definition of chaincode 'name'"))
+				})
+			})
+		})
+	})
 })
+
+// mapState is a state backed by a map, of which the hashes
+// of the values are computed the way the ledger computes them
+type mapState map[string][]byte
+
+func (m mapState) GetState(key string) ([]byte, error) {
+	return m[key], nil
+}
+
+func (m mapState) PutState(key string, value []byte) error {
+	m[key] = value
+	return nil
+}
+
+func (m mapState) GetStateHash(key string) ([]byte, error) {
+	value, ok := m[key]
+	if !ok {
+		return nil, nil
+	}
+	return util.ComputeSHA256(value), nil
+}
+
+// failingState fails every operation
+type failingState struct{}
+
+func (failingState) GetState(key string) ([]byte, error) {
+	return nil, fmt.Errorf("state-error")
+}
+
+func (failingState) PutState(key string, value []byte) error {
+	return fmt.Errorf("state-error")
+}
+
+func (failingState) GetStateHash(key string) ([]byte, error) {
+	return nil, fmt.Errorf("state-error")
+}
+
+// readOnlyState reads from a map state but fails writes
+type readOnlyState mapState
+
+func (r readOnlyState) GetState(key string) ([]byte, error) {
+	return r[key], nil
+}
+
+func (r readOnlyState) PutState(key string, value []byte) error {
+	return fmt.Errorf("state-error")
+}
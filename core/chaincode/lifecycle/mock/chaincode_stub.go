@@ -138,6 +138,22 @@ type ChaincodeStub struct {
 		result1 shim.HistoryQueryIteratorInterface
 		result2 error
 	}
+	GetHistoryForKeyWithOptionsStub        func(string, *peer.HistoryQueryMetadata) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)
+	getHistoryForKeyWithOptionsMutex       sync.RWMutex
+	getHistoryForKeyWithOptionsArgsForCall []struct {
+		arg1 string
+		arg2 *peer.HistoryQueryMetadata
+	}
+	getHistoryForKeyWithOptionsReturns struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
+	getHistoryForKeyWithOptionsReturnsOnCall map[int]struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
 	GetPrivateDataStub        func(string, string) ([]byte, error)
 	getPrivateDataMutex       sync.RWMutex
 	getPrivateDataArgsForCall []struct {
@@ -1123,6 +1139,73 @@ func (fake *ChaincodeStub) GetHistoryForKeyReturnsOnCall(i int, result1 shim.His
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptions(arg1 string, arg2 *peer.HistoryQueryMetadata) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithOptionsReturnsOnCall[len(fake.getHistoryForKeyWithOptionsArgsForCall)]
+	fake.getHistoryForKeyWithOptionsArgsForCall = append(fake.getHistoryForKeyWithOptionsArgsForCall, struct {
+		arg1 string
+		arg2 *peer.HistoryQueryMetadata
+	}{arg1, arg2})
+	fake.recordInvocation("GetHistoryForKeyWithOptions", []interface{}{arg1, arg2})
+	fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	if fake.GetHistoryForKeyWithOptionsStub != nil {
+		return fake.GetHistoryForKeyWithOptionsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getHistoryForKeyWithOptionsReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsCallCount() int {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithOptionsArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsCalls(stub func(string, *peer.HistoryQueryMetadata) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = stub
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsArgsForCall(i int) (string, *peer.HistoryQueryMetadata) {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsReturns(result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	fake.getHistoryForKeyWithOptionsReturns = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsReturnsOnCall(i int, result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	if fake.getHistoryForKeyWithOptionsReturnsOnCall == nil {
+		fake.getHistoryForKeyWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 shim.HistoryQueryIteratorInterface
+			result2 *peer.QueryResponseMetadata
+			result3 error
+		})
+	}
+	fake.getHistoryForKeyWithOptionsReturnsOnCall[i] = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *ChaincodeStub) GetPrivateData(arg1 string, arg2 string) ([]byte, error) {
 	fake.getPrivateDataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataReturnsOnCall[len(fake.getPrivateDataArgsForCall)]
@@ -2806,6 +2889,8 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getFunctionAndParametersMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
 	fake.getPrivateDataByPartialCompositeKeyMutex.RLock()
@@ -3,9 +3,42 @@ package mock
 
 import (
 	sync "sync"
+
+	lifecycle "github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	lifecyclea "github.com/hyperledger/fabric/protos/peer/lifecycle"
 )
 
 type SCCFunctions struct {
+	ApproveChaincodeDefinitionForOrgStub        func(string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadableState, lifecycle.ReadWritableState) error
+	approveChaincodeDefinitionForOrgMutex       sync.RWMutex
+	approveChaincodeDefinitionForOrgArgsForCall []struct {
+		arg1 string
+		arg2 *lifecyclea.ChaincodeDefinition
+		arg3 lifecycle.ReadableState
+		arg4 lifecycle.ReadWritableState
+	}
+	approveChaincodeDefinitionForOrgReturns struct {
+		result1 error
+	}
+	approveChaincodeDefinitionForOrgReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CommitChaincodeDefinitionStub        func(string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadWritableState, map[string]lifecycle.OpaqueState) (map[string]bool, error)
+	commitChaincodeDefinitionMutex       sync.RWMutex
+	commitChaincodeDefinitionArgsForCall []struct {
+		arg1 string
+		arg2 *lifecyclea.ChaincodeDefinition
+		arg3 lifecycle.ReadWritableState
+		arg4 map[string]lifecycle.OpaqueState
+	}
+	commitChaincodeDefinitionReturns struct {
+		result1 map[string]bool
+		result2 error
+	}
+	commitChaincodeDefinitionReturnsOnCall map[int]struct {
+		result1 map[string]bool
+		result2 error
+	}
 	InstallChaincodeStub        func(string, string, []byte) ([]byte, error)
 	installChaincodeMutex       sync.RWMutex
 	installChaincodeArgsForCall []struct {
@@ -21,6 +54,36 @@ type SCCFunctions struct {
 		result1 []byte
 		result2 error
 	}
+	QueryApprovalStatusStub        func(string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadableState, map[string]lifecycle.OpaqueState) (map[string]bool, error)
+	queryApprovalStatusMutex       sync.RWMutex
+	queryApprovalStatusArgsForCall []struct {
+		arg1 string
+		arg2 *lifecyclea.ChaincodeDefinition
+		arg3 lifecycle.ReadableState
+		arg4 map[string]lifecycle.OpaqueState
+	}
+	queryApprovalStatusReturns struct {
+		result1 map[string]bool
+		result2 error
+	}
+	queryApprovalStatusReturnsOnCall map[int]struct {
+		result1 map[string]bool
+		result2 error
+	}
+	QueryChaincodeDefinitionStub        func(string, lifecycle.ReadableState) (*lifecyclea.ChaincodeDefinition, error)
+	queryChaincodeDefinitionMutex       sync.RWMutex
+	queryChaincodeDefinitionArgsForCall []struct {
+		arg1 string
+		arg2 lifecycle.ReadableState
+	}
+	queryChaincodeDefinitionReturns struct {
+		result1 *lifecyclea.ChaincodeDefinition
+		result2 error
+	}
+	queryChaincodeDefinitionReturnsOnCall map[int]struct {
+		result1 *lifecyclea.ChaincodeDefinition
+		result2 error
+	}
 	QueryInstalledChaincodeStub        func(string, string) ([]byte, error)
 	queryInstalledChaincodeMutex       sync.RWMutex
 	queryInstalledChaincodeArgsForCall []struct {
@@ -35,10 +98,153 @@ type SCCFunctions struct {
 		result1 []byte
 		result2 error
 	}
+	UninstallChaincodeStub        func(string, string) ([]byte, error)
+	uninstallChaincodeMutex       sync.RWMutex
+	uninstallChaincodeArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	uninstallChaincodeReturns struct {
+		result1 []byte
+		result2 error
+	}
+	uninstallChaincodeReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrg(arg1 string, arg2 *lifecyclea.ChaincodeDefinition, arg3 lifecycle.ReadableState, arg4 lifecycle.ReadWritableState) error {
+	fake.approveChaincodeDefinitionForOrgMutex.Lock()
+	ret, specificReturn := fake.approveChaincodeDefinitionForOrgReturnsOnCall[len(fake.approveChaincodeDefinitionForOrgArgsForCall)]
+	fake.approveChaincodeDefinitionForOrgArgsForCall = append(fake.approveChaincodeDefinitionForOrgArgsForCall, struct {
+		arg1 string
+		arg2 *lifecyclea.ChaincodeDefinition
+		arg3 lifecycle.ReadableState
+		arg4 lifecycle.ReadWritableState
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("ApproveChaincodeDefinitionForOrg", []interface{}{arg1, arg2, arg3, arg4})
+	fake.approveChaincodeDefinitionForOrgMutex.Unlock()
+	if fake.ApproveChaincodeDefinitionForOrgStub != nil {
+		return fake.ApproveChaincodeDefinitionForOrgStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.approveChaincodeDefinitionForOrgReturns
+	return fakeReturns.result1
+}
+
+func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrgCallCount() int {
+	fake.approveChaincodeDefinitionForOrgMutex.RLock()
+	defer fake.approveChaincodeDefinitionForOrgMutex.RUnlock()
+	return len(fake.approveChaincodeDefinitionForOrgArgsForCall)
+}
+
+func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrgCalls(stub func(string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadableState, lifecycle.ReadWritableState) error) {
+	fake.approveChaincodeDefinitionForOrgMutex.Lock()
+	defer fake.approveChaincodeDefinitionForOrgMutex.Unlock()
+	fake.ApproveChaincodeDefinitionForOrgStub = stub
+}
+
+func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrgArgsForCall(i int) (string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadableState, lifecycle.ReadWritableState) {
+	fake.approveChaincodeDefinitionForOrgMutex.RLock()
+	defer fake.approveChaincodeDefinitionForOrgMutex.RUnlock()
+	argsForCall := fake.approveChaincodeDefinitionForOrgArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrgReturns(result1 error) {
+	fake.approveChaincodeDefinitionForOrgMutex.Lock()
+	defer fake.approveChaincodeDefinitionForOrgMutex.Unlock()
+	fake.ApproveChaincodeDefinitionForOrgStub = nil
+	fake.approveChaincodeDefinitionForOrgReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrgReturnsOnCall(i int, result1 error) {
+	fake.approveChaincodeDefinitionForOrgMutex.Lock()
+	defer fake.approveChaincodeDefinitionForOrgMutex.Unlock()
+	fake.ApproveChaincodeDefinitionForOrgStub = nil
+	if fake.approveChaincodeDefinitionForOrgReturnsOnCall == nil {
+		fake.approveChaincodeDefinitionForOrgReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.approveChaincodeDefinitionForOrgReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SCCFunctions) CommitChaincodeDefinition(arg1 string, arg2 *lifecyclea.ChaincodeDefinition, arg3 lifecycle.ReadWritableState, arg4 map[string]lifecycle.OpaqueState) (map[string]bool, error) {
+	fake.commitChaincodeDefinitionMutex.Lock()
+	ret, specificReturn := fake.commitChaincodeDefinitionReturnsOnCall[len(fake.commitChaincodeDefinitionArgsForCall)]
+	fake.commitChaincodeDefinitionArgsForCall = append(fake.commitChaincodeDefinitionArgsForCall, struct {
+		arg1 string
+		arg2 *lifecyclea.ChaincodeDefinition
+		arg3 lifecycle.ReadWritableState
+		arg4 map[string]lifecycle.OpaqueState
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CommitChaincodeDefinition", []interface{}{arg1, arg2, arg3, arg4})
+	fake.commitChaincodeDefinitionMutex.Unlock()
+	if fake.CommitChaincodeDefinitionStub != nil {
+		return fake.CommitChaincodeDefinitionStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.commitChaincodeDefinitionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SCCFunctions) CommitChaincodeDefinitionCallCount() int {
+	fake.commitChaincodeDefinitionMutex.RLock()
+	defer fake.commitChaincodeDefinitionMutex.RUnlock()
+	return len(fake.commitChaincodeDefinitionArgsForCall)
+}
+
+func (fake *SCCFunctions) CommitChaincodeDefinitionCalls(stub func(string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadWritableState, map[string]lifecycle.OpaqueState) (map[string]bool, error)) {
+	fake.commitChaincodeDefinitionMutex.Lock()
+	defer fake.commitChaincodeDefinitionMutex.Unlock()
+	fake.CommitChaincodeDefinitionStub = stub
+}
+
+func (fake *SCCFunctions) CommitChaincodeDefinitionArgsForCall(i int) (string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadWritableState, map[string]lifecycle.OpaqueState) {
+	fake.commitChaincodeDefinitionMutex.RLock()
+	defer fake.commitChaincodeDefinitionMutex.RUnlock()
+	argsForCall := fake.commitChaincodeDefinitionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *SCCFunctions) CommitChaincodeDefinitionReturns(result1 map[string]bool, result2 error) {
+	fake.commitChaincodeDefinitionMutex.Lock()
+	defer fake.commitChaincodeDefinitionMutex.Unlock()
+	fake.CommitChaincodeDefinitionStub = nil
+	fake.commitChaincodeDefinitionReturns = struct {
+		result1 map[string]bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) CommitChaincodeDefinitionReturnsOnCall(i int, result1 map[string]bool, result2 error) {
+	fake.commitChaincodeDefinitionMutex.Lock()
+	defer fake.commitChaincodeDefinitionMutex.Unlock()
+	fake.CommitChaincodeDefinitionStub = nil
+	if fake.commitChaincodeDefinitionReturnsOnCall == nil {
+		fake.commitChaincodeDefinitionReturnsOnCall = make(map[int]struct {
+			result1 map[string]bool
+			result2 error
+		})
+	}
+	fake.commitChaincodeDefinitionReturnsOnCall[i] = struct {
+		result1 map[string]bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *SCCFunctions) InstallChaincode(arg1 string, arg2 string, arg3 []byte) ([]byte, error) {
 	var arg3Copy []byte
 	if arg3 != nil {
@@ -109,6 +315,136 @@ func (fake *SCCFunctions) InstallChaincodeReturnsOnCall(i int, result1 []byte, r
 	}{result1, result2}
 }
 
+func (fake *SCCFunctions) QueryApprovalStatus(arg1 string, arg2 *lifecyclea.ChaincodeDefinition, arg3 lifecycle.ReadableState, arg4 map[string]lifecycle.OpaqueState) (map[string]bool, error) {
+	fake.queryApprovalStatusMutex.Lock()
+	ret, specificReturn := fake.queryApprovalStatusReturnsOnCall[len(fake.queryApprovalStatusArgsForCall)]
+	fake.queryApprovalStatusArgsForCall = append(fake.queryApprovalStatusArgsForCall, struct {
+		arg1 string
+		arg2 *lifecyclea.ChaincodeDefinition
+		arg3 lifecycle.ReadableState
+		arg4 map[string]lifecycle.OpaqueState
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("QueryApprovalStatus", []interface{}{arg1, arg2, arg3, arg4})
+	fake.queryApprovalStatusMutex.Unlock()
+	if fake.QueryApprovalStatusStub != nil {
+		return fake.QueryApprovalStatusStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.queryApprovalStatusReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SCCFunctions) QueryApprovalStatusCallCount() int {
+	fake.queryApprovalStatusMutex.RLock()
+	defer fake.queryApprovalStatusMutex.RUnlock()
+	return len(fake.queryApprovalStatusArgsForCall)
+}
+
+func (fake *SCCFunctions) QueryApprovalStatusCalls(stub func(string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadableState, map[string]lifecycle.OpaqueState) (map[string]bool, error)) {
+	fake.queryApprovalStatusMutex.Lock()
+	defer fake.queryApprovalStatusMutex.Unlock()
+	fake.QueryApprovalStatusStub = stub
+}
+
+func (fake *SCCFunctions) QueryApprovalStatusArgsForCall(i int) (string, *lifecyclea.ChaincodeDefinition, lifecycle.ReadableState, map[string]lifecycle.OpaqueState) {
+	fake.queryApprovalStatusMutex.RLock()
+	defer fake.queryApprovalStatusMutex.RUnlock()
+	argsForCall := fake.queryApprovalStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *SCCFunctions) QueryApprovalStatusReturns(result1 map[string]bool, result2 error) {
+	fake.queryApprovalStatusMutex.Lock()
+	defer fake.queryApprovalStatusMutex.Unlock()
+	fake.QueryApprovalStatusStub = nil
+	fake.queryApprovalStatusReturns = struct {
+		result1 map[string]bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) QueryApprovalStatusReturnsOnCall(i int, result1 map[string]bool, result2 error) {
+	fake.queryApprovalStatusMutex.Lock()
+	defer fake.queryApprovalStatusMutex.Unlock()
+	fake.QueryApprovalStatusStub = nil
+	if fake.queryApprovalStatusReturnsOnCall == nil {
+		fake.queryApprovalStatusReturnsOnCall = make(map[int]struct {
+			result1 map[string]bool
+			result2 error
+		})
+	}
+	fake.queryApprovalStatusReturnsOnCall[i] = struct {
+		result1 map[string]bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) QueryChaincodeDefinition(arg1 string, arg2 lifecycle.ReadableState) (*lifecyclea.ChaincodeDefinition, error) {
+	fake.queryChaincodeDefinitionMutex.Lock()
+	ret, specificReturn := fake.queryChaincodeDefinitionReturnsOnCall[len(fake.queryChaincodeDefinitionArgsForCall)]
+	fake.queryChaincodeDefinitionArgsForCall = append(fake.queryChaincodeDefinitionArgsForCall, struct {
+		arg1 string
+		arg2 lifecycle.ReadableState
+	}{arg1, arg2})
+	fake.recordInvocation("QueryChaincodeDefinition", []interface{}{arg1, arg2})
+	fake.queryChaincodeDefinitionMutex.Unlock()
+	if fake.QueryChaincodeDefinitionStub != nil {
+		return fake.QueryChaincodeDefinitionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.queryChaincodeDefinitionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SCCFunctions) QueryChaincodeDefinitionCallCount() int {
+	fake.queryChaincodeDefinitionMutex.RLock()
+	defer fake.queryChaincodeDefinitionMutex.RUnlock()
+	return len(fake.queryChaincodeDefinitionArgsForCall)
+}
+
+func (fake *SCCFunctions) QueryChaincodeDefinitionCalls(stub func(string, lifecycle.ReadableState) (*lifecyclea.ChaincodeDefinition, error)) {
+	fake.queryChaincodeDefinitionMutex.Lock()
+	defer fake.queryChaincodeDefinitionMutex.Unlock()
+	fake.QueryChaincodeDefinitionStub = stub
+}
+
+func (fake *SCCFunctions) QueryChaincodeDefinitionArgsForCall(i int) (string, lifecycle.ReadableState) {
+	fake.queryChaincodeDefinitionMutex.RLock()
+	defer fake.queryChaincodeDefinitionMutex.RUnlock()
+	argsForCall := fake.queryChaincodeDefinitionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SCCFunctions) QueryChaincodeDefinitionReturns(result1 *lifecyclea.ChaincodeDefinition, result2 error) {
+	fake.queryChaincodeDefinitionMutex.Lock()
+	defer fake.queryChaincodeDefinitionMutex.Unlock()
+	fake.QueryChaincodeDefinitionStub = nil
+	fake.queryChaincodeDefinitionReturns = struct {
+		result1 *lifecyclea.ChaincodeDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) QueryChaincodeDefinitionReturnsOnCall(i int, result1 *lifecyclea.ChaincodeDefinition, result2 error) {
+	fake.queryChaincodeDefinitionMutex.Lock()
+	defer fake.queryChaincodeDefinitionMutex.Unlock()
+	fake.QueryChaincodeDefinitionStub = nil
+	if fake.queryChaincodeDefinitionReturnsOnCall == nil {
+		fake.queryChaincodeDefinitionReturnsOnCall = make(map[int]struct {
+			result1 *lifecyclea.ChaincodeDefinition
+			result2 error
+		})
+	}
+	fake.queryChaincodeDefinitionReturnsOnCall[i] = struct {
+		result1 *lifecyclea.ChaincodeDefinition
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *SCCFunctions) QueryInstalledChaincode(arg1 string, arg2 string) ([]byte, error) {
 	fake.queryInstalledChaincodeMutex.Lock()
 	ret, specificReturn := fake.queryInstalledChaincodeReturnsOnCall[len(fake.queryInstalledChaincodeArgsForCall)]
@@ -173,13 +509,87 @@ func (fake *SCCFunctions) QueryInstalledChaincodeReturnsOnCall(i int, result1 []
 	}{result1, result2}
 }
 
+func (fake *SCCFunctions) UninstallChaincode(arg1 string, arg2 string) ([]byte, error) {
+	fake.uninstallChaincodeMutex.Lock()
+	ret, specificReturn := fake.uninstallChaincodeReturnsOnCall[len(fake.uninstallChaincodeArgsForCall)]
+	fake.uninstallChaincodeArgsForCall = append(fake.uninstallChaincodeArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("UninstallChaincode", []interface{}{arg1, arg2})
+	fake.uninstallChaincodeMutex.Unlock()
+	if fake.UninstallChaincodeStub != nil {
+		return fake.UninstallChaincodeStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.uninstallChaincodeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SCCFunctions) UninstallChaincodeCallCount() int {
+	fake.uninstallChaincodeMutex.RLock()
+	defer fake.uninstallChaincodeMutex.RUnlock()
+	return len(fake.uninstallChaincodeArgsForCall)
+}
+
+func (fake *SCCFunctions) UninstallChaincodeCalls(stub func(string, string) ([]byte, error)) {
+	fake.uninstallChaincodeMutex.Lock()
+	defer fake.uninstallChaincodeMutex.Unlock()
+	fake.UninstallChaincodeStub = stub
+}
+
+func (fake *SCCFunctions) UninstallChaincodeArgsForCall(i int) (string, string) {
+	fake.uninstallChaincodeMutex.RLock()
+	defer fake.uninstallChaincodeMutex.RUnlock()
+	argsForCall := fake.uninstallChaincodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SCCFunctions) UninstallChaincodeReturns(result1 []byte, result2 error) {
+	fake.uninstallChaincodeMutex.Lock()
+	defer fake.uninstallChaincodeMutex.Unlock()
+	fake.UninstallChaincodeStub = nil
+	fake.uninstallChaincodeReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) UninstallChaincodeReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.uninstallChaincodeMutex.Lock()
+	defer fake.uninstallChaincodeMutex.Unlock()
+	fake.UninstallChaincodeStub = nil
+	if fake.uninstallChaincodeReturnsOnCall == nil {
+		fake.uninstallChaincodeReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.uninstallChaincodeReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *SCCFunctions) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.approveChaincodeDefinitionForOrgMutex.RLock()
+	defer fake.approveChaincodeDefinitionForOrgMutex.RUnlock()
+	fake.commitChaincodeDefinitionMutex.RLock()
+	defer fake.commitChaincodeDefinitionMutex.RUnlock()
 	fake.installChaincodeMutex.RLock()
 	defer fake.installChaincodeMutex.RUnlock()
+	fake.queryApprovalStatusMutex.RLock()
+	defer fake.queryApprovalStatusMutex.RUnlock()
+	fake.queryChaincodeDefinitionMutex.RLock()
+	defer fake.queryChaincodeDefinitionMutex.RUnlock()
 	fake.queryInstalledChaincodeMutex.RLock()
 	defer fake.queryInstalledChaincodeMutex.RUnlock()
+	fake.uninstallChaincodeMutex.RLock()
+	defer fake.uninstallChaincodeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
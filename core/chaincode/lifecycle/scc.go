@@ -9,7 +9,11 @@ package lifecycle
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/msp/mgmt"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	"github.com/pkg/errors"
@@ -21,6 +25,25 @@ const (
 
 	// QueryInstalledChaincodeFuncName is the chaincode function name used to query an installed chaincode
 	QueryInstalledChaincodeFuncName = "QueryInstalledChaincode"
+
+	// UninstallChaincodeFuncName is the chaincode function name used to uninstall a chaincode
+	UninstallChaincodeFuncName = "UninstallChaincode"
+
+	// ApproveChaincodeDefinitionForMyOrgFuncName is the chaincode function name used to
+	// approve a chaincode definition for the organization of the peer
+	ApproveChaincodeDefinitionForMyOrgFuncName = "ApproveChaincodeDefinitionForMyOrg"
+
+	// CommitChaincodeDefinitionFuncName is the chaincode function name used to
+	// commit a chaincode definition approved by the organizations of the channel
+	CommitChaincodeDefinitionFuncName = "CommitChaincodeDefinition"
+
+	// QueryApprovalStatusFuncName is the chaincode function name used to query
+	// which organizations of the channel approved a chaincode definition
+	QueryApprovalStatusFuncName = "QueryApprovalStatus"
+
+	// QueryChaincodeDefinitionFuncName is the chaincode function name used to
+	// query the committed definition of a chaincode
+	QueryChaincodeDefinitionFuncName = "QueryChaincodeDefinition"
 )
 
 // SCCFunctions provides a backing implementation with concrete arguments
@@ -31,13 +54,52 @@ type SCCFunctions interface {
 
 	// QueryInstalledChaincode returns the hash for a given name and version of an installed chaincode
 	QueryInstalledChaincode(name, version string) (hash []byte, err error)
+
+	// UninstallChaincode removes an installed chaincode which is not in use and returns its hash
+	UninstallChaincode(name, version string) (hash []byte, err error)
+
+	// ApproveChaincodeDefinitionForOrg records the approval of a chaincode definition in the state of an org
+	ApproveChaincodeDefinitionForOrg(name string, cd *lb.ChaincodeDefinition, publicState ReadableState, orgState ReadWritableState) error
+
+	// CommitChaincodeDefinition commits a chaincode definition and returns which orgs approved it
+	CommitChaincodeDefinition(name string, cd *lb.ChaincodeDefinition, publicState ReadWritableState, orgStates map[string]OpaqueState) (approvals map[string]bool, err error)
+
+	// QueryApprovalStatus returns which orgs approved a chaincode definition
+	QueryApprovalStatus(name string, cd *lb.ChaincodeDefinition, publicState ReadableState, orgStates map[string]OpaqueState) (approvals map[string]bool, err error)
+
+	// QueryChaincodeDefinition returns the committed definition of a chaincode
+	QueryChaincodeDefinition(name string, publicState ReadableState) (*lb.ChaincodeDefinition, error)
+}
+
+// ChannelConfigSource provides the organizations of the channels of the peer
+type ChannelConfigSource interface {
+	// GetMSPIDs returns the MSP IDs of the application organizations of the channel
+	GetMSPIDs(channelID string) []string
+}
+
+// ApplicationConfigRetriever retrieves the application config of the channels of the peer
+type ApplicationConfigRetriever interface {
+	// GetApplicationConfig returns the application config of the channel
+	GetApplicationConfig(cid string) (channelconfig.Application, bool)
+}
+
+// PolicyChecker checks signed proposals against the local policies of the peer
+type PolicyChecker interface {
+	CheckPolicyNoChannel(policyName string, signedProp *pb.SignedProposal) error
 }
 
 // SCC implements the required methods to satisfy the chaincode interface.
 // It routes the invocation calls to the backing implementations.
 type SCC struct {
-	Protobuf  Protobuf
-	Functions SCCFunctions
+	// OrgMSPID is the MSP ID of the organization of the peer, which
+	// chaincode definitions are approved for
+	OrgMSPID string
+
+	ChannelConfigSource ChannelConfigSource
+	AppConfig           ApplicationConfigRetriever
+	PolicyChecker       PolicyChecker
+	Protobuf            Protobuf
+	Functions           SCCFunctions
 }
 
 // Name returns "+lifecycle"
@@ -145,8 +207,181 @@ func (scc *SCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 			return shim.Error(err.Error())
 		}
 
+		return shim.Success(resultBytes)
+	case UninstallChaincodeFuncName:
+		input := &lb.UninstallChaincodeArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to UninstallChaincode")
+			return shim.Error(err.Error())
+		}
+
+		// only the admins of the organization of the peer may remove chaincodes from it
+		signedProp, err := stub.GetSignedProposal()
+		if err != nil {
+			err = errors.WithMessage(err, "failed to get signed proposal")
+			return shim.Error(err.Error())
+		}
+		if err := scc.PolicyChecker.CheckPolicyNoChannel(mgmt.Admins, signedProp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s]: %s", funcName, err))
+		}
+
+		hash, err := scc.Functions.UninstallChaincode(input.Name, input.Version)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing UninstallChaincode")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.UninstallChaincodeResult{
+			Hash: hash,
+		})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(resultBytes)
+	case ApproveChaincodeDefinitionForMyOrgFuncName:
+		input := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to ApproveChaincodeDefinitionForMyOrg")
+			return shim.Error(err.Error())
+		}
+
+		if err := scc.checkLifecycleApproval(stub.GetChannelID()); err != nil {
+			return shim.Error(err.Error())
+		}
+
+		// only the admins of the organization of the peer may approve for it
+		signedProp, err := stub.GetSignedProposal()
+		if err != nil {
+			err = errors.WithMessage(err, "failed to get signed proposal")
+			return shim.Error(err.Error())
+		}
+		if err := scc.PolicyChecker.CheckPolicyNoChannel(mgmt.Admins, signedProp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s]: %s", funcName, err))
+		}
+
+		orgState := &ChaincodePrivateLedgerShim{
+			Stub:       stub,
+			Collection: privdata.ImplicitCollectionNameForOrg(scc.OrgMSPID),
+		}
+		err = scc.Functions.ApproveChaincodeDefinitionForOrg(input.Name, input.Definition, &ChaincodePublicLedgerShim{stub}, orgState)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing ApproveChaincodeDefinitionForOrg")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.ApproveChaincodeDefinitionForMyOrgResult{})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(resultBytes)
+	case CommitChaincodeDefinitionFuncName:
+		input := &lb.CommitChaincodeDefinitionArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to CommitChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		if err := scc.checkLifecycleApproval(stub.GetChannelID()); err != nil {
+			return shim.Error(err.Error())
+		}
+
+		// the approvals are not checked here: when the transaction is validated,
+		// only the endorsements of the organizations which approved the definition
+		// count towards the lifecycle endorsement policy of the channel
+		_, err = scc.Functions.CommitChaincodeDefinition(input.Name, input.Definition, &ChaincodePublicLedgerShim{stub}, scc.orgStates(stub))
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing CommitChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.CommitChaincodeDefinitionResult{})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(resultBytes)
+	case QueryApprovalStatusFuncName:
+		input := &lb.QueryApprovalStatusArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to QueryApprovalStatus")
+			return shim.Error(err.Error())
+		}
+
+		approvals, err := scc.Functions.QueryApprovalStatus(input.Name, input.Definition, &ChaincodePublicLedgerShim{stub}, scc.orgStates(stub))
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing QueryApprovalStatus")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.QueryApprovalStatusResult{
+			Approved: approvals,
+		})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(resultBytes)
+	case QueryChaincodeDefinitionFuncName:
+		input := &lb.QueryChaincodeDefinitionArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to QueryChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		definition, err := scc.Functions.QueryChaincodeDefinition(input.Name, &ChaincodePublicLedgerShim{stub})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing QueryChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.QueryChaincodeDefinitionResult{
+			Definition: definition,
+		})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
 		return shim.Success(resultBytes)
 	default:
 		return shim.Error(fmt.Sprintf("unknown lifecycle function: %s", funcName))
 	}
 }
+
+// checkLifecycleApproval returns an error unless the channel has the capability
+// under which the approvals of chaincode definitions are enforced when the
+// transactions writing them are validated
+func (scc *SCC) checkLifecycleApproval(channelID string) error {
+	ac, ok := scc.AppConfig.GetApplicationConfig(channelID)
+	if !ok {
+		return errors.Errorf("could not get application config for channel '%s'", channelID)
+	}
+	if !ac.Capabilities().LifecycleApproval() {
+		return errors.Errorf("chaincode definitions cannot be approved or committed on channel '%s' without the %s application capability", channelID, capabilities.ApplicationLifecycleApproval)
+	}
+	return nil
+}
+
+// orgStates returns the states of the implicit collections of the
+// organizations of the channel, by MSP ID
+func (scc *SCC) orgStates(stub shim.ChaincodeStubInterface) map[string]OpaqueState {
+	orgStates := map[string]OpaqueState{}
+	for _, mspID := range scc.ChannelConfigSource.GetMSPIDs(stub.GetChannelID()) {
+		orgStates[mspID] = &ChaincodePrivateLedgerShim{
+			Stub:       stub,
+			Collection: privdata.ImplicitCollectionNameForOrg(mspID),
+		}
+	}
+	return orgStates
+}
@@ -10,9 +10,11 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,17 +22,30 @@ import (
 
 var _ = Describe("SCC", func() {
 	var (
-		scc          *lifecycle.SCC
-		fakeProto    *mock.Protobuf
-		fakeSCCFuncs *mock.SCCFunctions
+		scc                     *lifecycle.SCC
+		fakeProto               *mock.Protobuf
+		fakeSCCFuncs            *mock.SCCFunctions
+		fakeChannelConfigSource *mock.ChannelConfigSource
+		fakeAppConfig           *mock.ApplicationConfigRetriever
+		fakeCapabilities        *mockconfig.MockApplicationCapabilities
+		fakePolicyChecker       *mock.PolicyChecker
 	)
 
 	BeforeEach(func() {
 		fakeProto = &mock.Protobuf{}
 		fakeSCCFuncs = &mock.SCCFunctions{}
+		fakeChannelConfigSource = &mock.ChannelConfigSource{}
+		fakeCapabilities = &mockconfig.MockApplicationCapabilities{LifecycleApprovalRv: true}
+		fakeAppConfig = &mock.ApplicationConfigRetriever{}
+		fakeAppConfig.GetApplicationConfigReturns(&mockconfig.MockApplication{CapabilitiesRv: fakeCapabilities}, true)
+		fakePolicyChecker = &mock.PolicyChecker{}
 		scc = &lifecycle.SCC{
-			Protobuf:  fakeProto,
-			Functions: fakeSCCFuncs,
+			OrgMSPID:            "org1",
+			ChannelConfigSource: fakeChannelConfigSource,
+			AppConfig:           fakeAppConfig,
+			PolicyChecker:       fakePolicyChecker,
+			Protobuf:            fakeProto,
+			Functions:           fakeSCCFuncs,
 		}
 	})
 
@@ -268,5 +283,498 @@ var _ = Describe("SCC", func() {
 				})
 			})
 		})
+
+		Describe("UninstallChaincode", func() {
+			var (
+				arg          *lb.UninstallChaincodeArgs
+				marshaledArg []byte
+				signedProp   *pb.SignedProposal
+			)
+
+			BeforeEach(func() {
+				arg = &lb.UninstallChaincodeArgs{
+					Name:    "name",
+					Version: "version",
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				signedProp = &pb.SignedProposal{ProposalBytes: []byte("proposal")}
+				fakeStub.GetArgsReturns([][]byte{[]byte("UninstallChaincode"), marshaledArg})
+				fakeStub.GetSignedProposalReturns(signedProp, nil)
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+
+				fakeSCCFuncs.UninstallChaincodeReturns([]byte("fake-hash"), nil)
+			})
+
+			It("checks the creator is an admin and passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.UninstallChaincodeResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload.Hash).To(Equal([]byte("fake-hash")))
+
+				Expect(fakePolicyChecker.CheckPolicyNoChannelCallCount()).To(Equal(1))
+				policyName, sp := fakePolicyChecker.CheckPolicyNoChannelArgsForCall(0)
+				Expect(policyName).To(Equal("Admins"))
+				Expect(sp).To(Equal(signedProp))
+
+				Expect(fakeSCCFuncs.UninstallChaincodeCallCount()).To(Equal(1))
+				name, version := fakeSCCFuncs.UninstallChaincodeArgsForCall(0)
+				Expect(name).To(Equal("name"))
+				Expect(version).To(Equal("version"))
+			})
+
+			Context("when the creator is not an admin", func() {
+				BeforeEach(func() {
+					fakePolicyChecker.CheckPolicyNoChannelReturns(fmt.Errorf("not-admin"))
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("access denied for [UninstallChaincode]: not-admin"))
+					Expect(fakeSCCFuncs.UninstallChaincodeCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the signed proposal cannot be retrieved", func() {
+				BeforeEach(func() {
+					fakeStub.GetSignedProposalReturns(nil, fmt.Errorf("proposal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to get signed proposal: proposal-error"))
+				})
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.UninstallChaincodeReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing UninstallChaincode: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to UninstallChaincode: unmarshal-error"))
+				})
+			})
+
+			Context("when marshaling the output fails", func() {
+				BeforeEach(func() {
+					fakeProto.MarshalReturns(nil, fmt.Errorf("marshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to marshal result: marshal-error"))
+				})
+			})
+		})
+
+		Describe("ApproveChaincodeDefinitionForMyOrg", func() {
+			var (
+				arg          *lb.ApproveChaincodeDefinitionForMyOrgArgs
+				marshaledArg []byte
+				signedProp   *pb.SignedProposal
+			)
+
+			BeforeEach(func() {
+				arg = &lb.ApproveChaincodeDefinitionForMyOrgArgs{
+					Name: "name",
+					Definition: &lb.ChaincodeDefinition{
+						Sequence: 1,
+						Version:  "version",
+					},
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				signedProp = &pb.SignedProposal{ProposalBytes: []byte("proposal")}
+				fakeStub.GetArgsReturns([][]byte{[]byte("ApproveChaincodeDefinitionForMyOrg"), marshaledArg})
+				fakeStub.GetSignedProposalReturns(signedProp, nil)
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+			})
+
+			It("checks the creator is an admin and passes the states of the org to the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.ApproveChaincodeDefinitionForMyOrgResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePolicyChecker.CheckPolicyNoChannelCallCount()).To(Equal(1))
+				policyName, sp := fakePolicyChecker.CheckPolicyNoChannelArgsForCall(0)
+				Expect(policyName).To(Equal("Admins"))
+				Expect(sp).To(Equal(signedProp))
+
+				Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(1))
+				name, cd, publicState, orgState := fakeSCCFuncs.ApproveChaincodeDefinitionForOrgArgsForCall(0)
+				Expect(name).To(Equal("name"))
+				Expect(proto.Equal(cd, arg.Definition)).To(BeTrue())
+				Expect(publicState).To(Equal(&lifecycle.ChaincodePublicLedgerShim{ChaincodeStubInterface: fakeStub}))
+				Expect(orgState).To(Equal(&lifecycle.ChaincodePrivateLedgerShim{
+					Stub:       fakeStub,
+					Collection: "_implicit_org_org1",
+				}))
+			})
+
+			Context("when the creator is not an admin", func() {
+				BeforeEach(func() {
+					fakePolicyChecker.CheckPolicyNoChannelReturns(fmt.Errorf("not-admin"))
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("access denied for [ApproveChaincodeDefinitionForMyOrg]: not-admin"))
+					Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the signed proposal cannot be retrieved", func() {
+				BeforeEach(func() {
+					fakeStub.GetSignedProposalReturns(nil, fmt.Errorf("proposal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to get signed proposal: proposal-error"))
+				})
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.ApproveChaincodeDefinitionForOrgReturns(fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing ApproveChaincodeDefinitionForOrg: underlying-error"))
+				})
+			})
+
+			Context("when the channel does not have the lifecycle approval capability", func() {
+				BeforeEach(func() {
+					fakeCapabilities.LifecycleApprovalRv = false
+				})
+
+				It("returns an error without approving the definition", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("chaincode definitions cannot be approved or committed on channel '' without the V1_4_LIFECYCLE_APPROVAL application capability"))
+					Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to ApproveChaincodeDefinitionForMyOrg: unmarshal-error"))
+				})
+			})
+
+			Context("when marshaling the output fails", func() {
+				BeforeEach(func() {
+					fakeProto.MarshalReturns(nil, fmt.Errorf("marshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to marshal result: marshal-error"))
+				})
+			})
+		})
+
+		Describe("CommitChaincodeDefinition", func() {
+			var (
+				arg          *lb.CommitChaincodeDefinitionArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.CommitChaincodeDefinitionArgs{
+					Name: "name",
+					Definition: &lb.ChaincodeDefinition{
+						Sequence: 1,
+						Version:  "version",
+					},
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("CommitChaincodeDefinition"), marshaledArg})
+				fakeStub.GetChannelIDReturns("channel")
+				fakeChannelConfigSource.GetMSPIDsReturns([]string{"org1", "org2"})
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+
+				fakeSCCFuncs.CommitChaincodeDefinitionReturns(map[string]bool{"org1": true, "org2": false}, nil)
+			})
+
+			It("passes the states of the orgs of the channel to the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.CommitChaincodeDefinitionResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeChannelConfigSource.GetMSPIDsCallCount()).To(Equal(1))
+				Expect(fakeChannelConfigSource.GetMSPIDsArgsForCall(0)).To(Equal("channel"))
+
+				Expect(fakeSCCFuncs.CommitChaincodeDefinitionCallCount()).To(Equal(1))
+				name, cd, publicState, orgStates := fakeSCCFuncs.CommitChaincodeDefinitionArgsForCall(0)
+				Expect(name).To(Equal("name"))
+				Expect(proto.Equal(cd, arg.Definition)).To(BeTrue())
+				Expect(publicState).To(Equal(&lifecycle.ChaincodePublicLedgerShim{ChaincodeStubInterface: fakeStub}))
+				Expect(orgStates).To(Equal(map[string]lifecycle.OpaqueState{
+					"org1": &lifecycle.ChaincodePrivateLedgerShim{Stub: fakeStub, Collection: "_implicit_org_org1"},
+					"org2": &lifecycle.ChaincodePrivateLedgerShim{Stub: fakeStub, Collection: "_implicit_org_org2"},
+				}))
+			})
+
+			Context("when the org of the peer did not approve the definition", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.CommitChaincodeDefinitionReturns(map[string]bool{"org1": false, "org2": true}, nil)
+				})
+
+				It("endorses the commit, leaving the approvals to the validation", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(200)))
+				})
+			})
+
+			Context("when the channel does not have the lifecycle approval capability", func() {
+				BeforeEach(func() {
+					fakeCapabilities.LifecycleApprovalRv = false
+				})
+
+				It("returns an error without committing the definition", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("chaincode definitions cannot be approved or committed on channel 'channel' without the V1_4_LIFECYCLE_APPROVAL application capability"))
+					Expect(fakeSCCFuncs.CommitChaincodeDefinitionCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the application config of the channel cannot be retrieved", func() {
+				BeforeEach(func() {
+					fakeAppConfig.GetApplicationConfigReturns(nil, false)
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("could not get application config for channel 'channel'"))
+				})
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.CommitChaincodeDefinitionReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing CommitChaincodeDefinition: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to CommitChaincodeDefinition: unmarshal-error"))
+				})
+			})
+
+			Context("when marshaling the output fails", func() {
+				BeforeEach(func() {
+					fakeProto.MarshalReturns(nil, fmt.Errorf("marshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to marshal result: marshal-error"))
+				})
+			})
+		})
+
+		Describe("QueryApprovalStatus", func() {
+			var (
+				arg          *lb.QueryApprovalStatusArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.QueryApprovalStatusArgs{
+					Name: "name",
+					Definition: &lb.ChaincodeDefinition{
+						Sequence: 1,
+						Version:  "version",
+					},
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("QueryApprovalStatus"), marshaledArg})
+				fakeStub.GetChannelIDReturns("channel")
+				fakeChannelConfigSource.GetMSPIDsReturns([]string{"org1", "org2"})
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+
+				fakeSCCFuncs.QueryApprovalStatusReturns(map[string]bool{"org1": false, "org2": true}, nil)
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.QueryApprovalStatusResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload.Approved).To(Equal(map[string]bool{"org1": false, "org2": true}))
+
+				Expect(fakeSCCFuncs.QueryApprovalStatusCallCount()).To(Equal(1))
+				name, cd, _, orgStates := fakeSCCFuncs.QueryApprovalStatusArgsForCall(0)
+				Expect(name).To(Equal("name"))
+				Expect(proto.Equal(cd, arg.Definition)).To(BeTrue())
+				Expect(orgStates).To(HaveLen(2))
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryApprovalStatusReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing QueryApprovalStatus: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to QueryApprovalStatus: unmarshal-error"))
+				})
+			})
+		})
+
+		Describe("QueryChaincodeDefinition", func() {
+			var (
+				arg          *lb.QueryChaincodeDefinitionArgs
+				marshaledArg []byte
+				definition   *lb.ChaincodeDefinition
+			)
+
+			BeforeEach(func() {
+				arg = &lb.QueryChaincodeDefinitionArgs{
+					Name: "name",
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("QueryChaincodeDefinition"), marshaledArg})
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+
+				definition = &lb.ChaincodeDefinition{
+					Sequence: 2,
+					Version:  "version",
+				}
+				fakeSCCFuncs.QueryChaincodeDefinitionReturns(definition, nil)
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.QueryChaincodeDefinitionResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(payload.Definition, definition)).To(BeTrue())
+
+				Expect(fakeSCCFuncs.QueryChaincodeDefinitionCallCount()).To(Equal(1))
+				name, publicState := fakeSCCFuncs.QueryChaincodeDefinitionArgsForCall(0)
+				Expect(name).To(Equal("name"))
+				Expect(publicState).To(Equal(&lifecycle.ChaincodePublicLedgerShim{ChaincodeStubInterface: fakeStub}))
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryChaincodeDefinitionReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing QueryChaincodeDefinition: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to QueryChaincodeDefinition: unmarshal-error"))
+				})
+			})
+		})
 	})
 })
@@ -59,21 +59,66 @@ var (
 		LabelNames:   []string{"chaincode"},
 		StatsdFormat: "%{#fqname}.%{chaincode}",
 	}
+	stateReads = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Name:         "state_reads",
+		Help:         "The number of keys read from the state by chaincode executions.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+	rangeQueryResults = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Name:         "range_query_results",
+		Help:         "The number of range, rich and history query results returned to chaincode executions.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+	stateWrites = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Name:         "state_writes",
+		Help:         "The number of keys written to or deleted from the state by chaincode executions.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+	stateBytes = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Name:         "state_bytes",
+		Help:         "The number of bytes read from and written to the state by chaincode executions.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+	resourceLimitsExceeded = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Name:         "resource_limits_exceeded",
+		Help:         "The number of chaincode executions that have exceeded a resource limit.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
 )
 
 type HandlerMetrics struct {
-	ShimRequestsReceived  metrics.Counter
-	ShimRequestsCompleted metrics.Counter
-	ShimRequestDuration   metrics.Histogram
-	ExecuteTimeouts       metrics.Counter
+	ShimRequestsReceived   metrics.Counter
+	ShimRequestsCompleted  metrics.Counter
+	ShimRequestDuration    metrics.Histogram
+	ExecuteTimeouts        metrics.Counter
+	StateReads             metrics.Counter
+	RangeQueryResults      metrics.Counter
+	StateWrites            metrics.Counter
+	StateBytes             metrics.Counter
+	ResourceLimitsExceeded metrics.Counter
 }
 
 func NewHandlerMetrics(p metrics.Provider) *HandlerMetrics {
 	return &HandlerMetrics{
-		ShimRequestsReceived:  p.NewCounter(shimRequestsReceived),
-		ShimRequestsCompleted: p.NewCounter(shimRequestsCompleted),
-		ShimRequestDuration:   p.NewHistogram(shimRequestDuration),
-		ExecuteTimeouts:       p.NewCounter(executeTimeouts),
+		ShimRequestsReceived:   p.NewCounter(shimRequestsReceived),
+		ShimRequestsCompleted:  p.NewCounter(shimRequestsCompleted),
+		ShimRequestDuration:    p.NewHistogram(shimRequestDuration),
+		ExecuteTimeouts:        p.NewCounter(executeTimeouts),
+		StateReads:             p.NewCounter(stateReads),
+		RangeQueryResults:      p.NewCounter(rangeQueryResults),
+		StateWrites:            p.NewCounter(stateWrites),
+		StateBytes:             p.NewCounter(stateBytes),
+		ResourceLimitsExceeded: p.NewCounter(resourceLimitsExceeded),
 	}
 }
 
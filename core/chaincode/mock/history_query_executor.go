@@ -5,9 +5,25 @@ import (
 	sync "sync"
 
 	ledger "github.com/hyperledger/fabric/common/ledger"
+	ledgera "github.com/hyperledger/fabric/core/ledger"
 )
 
 type HistoryQueryExecutor struct {
+	GetAccessHistoryForKeyStub        func(string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)
+	getAccessHistoryForKeyMutex       sync.RWMutex
+	getAccessHistoryForKeyArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}
+	getAccessHistoryForKeyReturns struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
+	getAccessHistoryForKeyReturnsOnCall map[int]struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
 	GetHistoryForKeyStub        func(string, string) (ledger.ResultsIterator, error)
 	getHistoryForKeyMutex       sync.RWMutex
 	getHistoryForKeyArgsForCall []struct {
@@ -22,10 +38,90 @@ type HistoryQueryExecutor struct {
 		result1 ledger.ResultsIterator
 		result2 error
 	}
+	GetHistoryForKeyWithOptionsStub        func(string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)
+	getHistoryForKeyWithOptionsMutex       sync.RWMutex
+	getHistoryForKeyWithOptionsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}
+	getHistoryForKeyWithOptionsReturns struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
+	getHistoryForKeyWithOptionsReturnsOnCall map[int]struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *HistoryQueryExecutor) GetAccessHistoryForKey(arg1 string, arg2 string, arg3 *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error) {
+	fake.getAccessHistoryForKeyMutex.Lock()
+	ret, specificReturn := fake.getAccessHistoryForKeyReturnsOnCall[len(fake.getAccessHistoryForKeyArgsForCall)]
+	fake.getAccessHistoryForKeyArgsForCall = append(fake.getAccessHistoryForKeyArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetAccessHistoryForKey", []interface{}{arg1, arg2, arg3})
+	fake.getAccessHistoryForKeyMutex.Unlock()
+	if fake.GetAccessHistoryForKeyStub != nil {
+		return fake.GetAccessHistoryForKeyStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAccessHistoryForKeyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetAccessHistoryForKeyCallCount() int {
+	fake.getAccessHistoryForKeyMutex.RLock()
+	defer fake.getAccessHistoryForKeyMutex.RUnlock()
+	return len(fake.getAccessHistoryForKeyArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetAccessHistoryForKeyCalls(stub func(string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)) {
+	fake.getAccessHistoryForKeyMutex.Lock()
+	defer fake.getAccessHistoryForKeyMutex.Unlock()
+	fake.GetAccessHistoryForKeyStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetAccessHistoryForKeyArgsForCall(i int) (string, string, *ledgera.HistoryQueryOptions) {
+	fake.getAccessHistoryForKeyMutex.RLock()
+	defer fake.getAccessHistoryForKeyMutex.RUnlock()
+	argsForCall := fake.getAccessHistoryForKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HistoryQueryExecutor) GetAccessHistoryForKeyReturns(result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getAccessHistoryForKeyMutex.Lock()
+	defer fake.getAccessHistoryForKeyMutex.Unlock()
+	fake.GetAccessHistoryForKeyStub = nil
+	fake.getAccessHistoryForKeyReturns = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetAccessHistoryForKeyReturnsOnCall(i int, result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getAccessHistoryForKeyMutex.Lock()
+	defer fake.getAccessHistoryForKeyMutex.Unlock()
+	fake.GetAccessHistoryForKeyStub = nil
+	if fake.getAccessHistoryForKeyReturnsOnCall == nil {
+		fake.getAccessHistoryForKeyReturnsOnCall = make(map[int]struct {
+			result1 ledgera.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.getAccessHistoryForKeyReturnsOnCall[i] = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HistoryQueryExecutor) GetHistoryForKey(arg1 string, arg2 string) (ledger.ResultsIterator, error) {
 	fake.getHistoryForKeyMutex.Lock()
 	ret, specificReturn := fake.getHistoryForKeyReturnsOnCall[len(fake.getHistoryForKeyArgsForCall)]
@@ -90,11 +186,80 @@ func (fake *HistoryQueryExecutor) GetHistoryForKeyReturnsOnCall(i int, result1 l
 	}{result1, result2}
 }
 
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptions(arg1 string, arg2 string, arg3 *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithOptionsReturnsOnCall[len(fake.getHistoryForKeyWithOptionsArgsForCall)]
+	fake.getHistoryForKeyWithOptionsArgsForCall = append(fake.getHistoryForKeyWithOptionsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetHistoryForKeyWithOptions", []interface{}{arg1, arg2, arg3})
+	fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	if fake.GetHistoryForKeyWithOptionsStub != nil {
+		return fake.GetHistoryForKeyWithOptionsStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getHistoryForKeyWithOptionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsCallCount() int {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithOptionsArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsCalls(stub func(string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsArgsForCall(i int) (string, string, *ledgera.HistoryQueryOptions) {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturns(result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	fake.getHistoryForKeyWithOptionsReturns = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturnsOnCall(i int, result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	if fake.getHistoryForKeyWithOptionsReturnsOnCall == nil {
+		fake.getHistoryForKeyWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 ledgera.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.getHistoryForKeyWithOptionsReturnsOnCall[i] = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HistoryQueryExecutor) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getAccessHistoryForKeyMutex.RLock()
+	defer fake.getAccessHistoryForKeyMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -53,6 +53,18 @@ type PeerLedger struct {
 		result1 bool
 		result2 error
 	}
+	ExportSnapshotStub        func() (*ledger.SnapshotInfo, error)
+	exportSnapshotMutex       sync.RWMutex
+	exportSnapshotArgsForCall []struct {
+	}
+	exportSnapshotReturns struct {
+		result1 *ledger.SnapshotInfo
+		result2 error
+	}
+	exportSnapshotReturnsOnCall map[int]struct {
+		result1 *ledger.SnapshotInfo
+		result2 error
+	}
 	GetBlockByHashStub        func([]byte) (*common.Block, error)
 	getBlockByHashMutex       sync.RWMutex
 	getBlockByHashArgsForCall []struct {
@@ -232,6 +244,18 @@ type PeerLedger struct {
 		result1 ledger.TxSimulator
 		result2 error
 	}
+	PendingSnapshotRequestsStub        func() ([]uint64, error)
+	pendingSnapshotRequestsMutex       sync.RWMutex
+	pendingSnapshotRequestsArgsForCall []struct {
+	}
+	pendingSnapshotRequestsReturns struct {
+		result1 []uint64
+		result2 error
+	}
+	pendingSnapshotRequestsReturnsOnCall map[int]struct {
+		result1 []uint64
+		result2 error
+	}
 	PrivateDataMinBlockNumStub        func() (uint64, error)
 	privateDataMinBlockNumMutex       sync.RWMutex
 	privateDataMinBlockNumArgsForCall []struct {
@@ -266,6 +290,17 @@ type PeerLedger struct {
 	purgePrivateDataReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SubmitSnapshotRequestStub        func(uint64) error
+	submitSnapshotRequestMutex       sync.RWMutex
+	submitSnapshotRequestArgsForCall []struct {
+		arg1 uint64
+	}
+	submitSnapshotRequestReturns struct {
+		result1 error
+	}
+	submitSnapshotRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -485,6 +520,61 @@ func (fake *PeerLedger) DoesPvtDataInfoExistReturnsOnCall(i int, result1 bool, r
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) ExportSnapshot() (*ledger.SnapshotInfo, error) {
+	fake.exportSnapshotMutex.Lock()
+	ret, specificReturn := fake.exportSnapshotReturnsOnCall[len(fake.exportSnapshotArgsForCall)]
+	fake.exportSnapshotArgsForCall = append(fake.exportSnapshotArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ExportSnapshot", []interface{}{})
+	fake.exportSnapshotMutex.Unlock()
+	if fake.ExportSnapshotStub != nil {
+		return fake.ExportSnapshotStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.exportSnapshotReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) ExportSnapshotCallCount() int {
+	fake.exportSnapshotMutex.RLock()
+	defer fake.exportSnapshotMutex.RUnlock()
+	return len(fake.exportSnapshotArgsForCall)
+}
+
+func (fake *PeerLedger) ExportSnapshotCalls(stub func() (*ledger.SnapshotInfo, error)) {
+	fake.exportSnapshotMutex.Lock()
+	defer fake.exportSnapshotMutex.Unlock()
+	fake.ExportSnapshotStub = stub
+}
+
+func (fake *PeerLedger) ExportSnapshotReturns(result1 *ledger.SnapshotInfo, result2 error) {
+	fake.exportSnapshotMutex.Lock()
+	defer fake.exportSnapshotMutex.Unlock()
+	fake.ExportSnapshotStub = nil
+	fake.exportSnapshotReturns = struct {
+		result1 *ledger.SnapshotInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) ExportSnapshotReturnsOnCall(i int, result1 *ledger.SnapshotInfo, result2 error) {
+	fake.exportSnapshotMutex.Lock()
+	defer fake.exportSnapshotMutex.Unlock()
+	fake.ExportSnapshotStub = nil
+	if fake.exportSnapshotReturnsOnCall == nil {
+		fake.exportSnapshotReturnsOnCall = make(map[int]struct {
+			result1 *ledger.SnapshotInfo
+			result2 error
+		})
+	}
+	fake.exportSnapshotReturnsOnCall[i] = struct {
+		result1 *ledger.SnapshotInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetBlockByHash(arg1 []byte) (*common.Block, error) {
 	var arg1Copy []byte
 	if arg1 != nil {
@@ -1334,6 +1424,61 @@ func (fake *PeerLedger) NewTxSimulatorReturnsOnCall(i int, result1 ledger.TxSimu
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) PendingSnapshotRequests() ([]uint64, error) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	ret, specificReturn := fake.pendingSnapshotRequestsReturnsOnCall[len(fake.pendingSnapshotRequestsArgsForCall)]
+	fake.pendingSnapshotRequestsArgsForCall = append(fake.pendingSnapshotRequestsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("PendingSnapshotRequests", []interface{}{})
+	fake.pendingSnapshotRequestsMutex.Unlock()
+	if fake.PendingSnapshotRequestsStub != nil {
+		return fake.PendingSnapshotRequestsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.pendingSnapshotRequestsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsCallCount() int {
+	fake.pendingSnapshotRequestsMutex.RLock()
+	defer fake.pendingSnapshotRequestsMutex.RUnlock()
+	return len(fake.pendingSnapshotRequestsArgsForCall)
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsCalls(stub func() ([]uint64, error)) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	defer fake.pendingSnapshotRequestsMutex.Unlock()
+	fake.PendingSnapshotRequestsStub = stub
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsReturns(result1 []uint64, result2 error) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	defer fake.pendingSnapshotRequestsMutex.Unlock()
+	fake.PendingSnapshotRequestsStub = nil
+	fake.pendingSnapshotRequestsReturns = struct {
+		result1 []uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsReturnsOnCall(i int, result1 []uint64, result2 error) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	defer fake.pendingSnapshotRequestsMutex.Unlock()
+	fake.PendingSnapshotRequestsStub = nil
+	if fake.pendingSnapshotRequestsReturnsOnCall == nil {
+		fake.pendingSnapshotRequestsReturnsOnCall = make(map[int]struct {
+			result1 []uint64
+			result2 error
+		})
+	}
+	fake.pendingSnapshotRequestsReturnsOnCall[i] = struct {
+		result1 []uint64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) PrivateDataMinBlockNum() (uint64, error) {
 	fake.privateDataMinBlockNumMutex.Lock()
 	ret, specificReturn := fake.privateDataMinBlockNumReturnsOnCall[len(fake.privateDataMinBlockNumArgsForCall)]
@@ -1509,6 +1654,66 @@ func (fake *PeerLedger) PurgePrivateDataReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *PeerLedger) SubmitSnapshotRequest(arg1 uint64) error {
+	fake.submitSnapshotRequestMutex.Lock()
+	ret, specificReturn := fake.submitSnapshotRequestReturnsOnCall[len(fake.submitSnapshotRequestArgsForCall)]
+	fake.submitSnapshotRequestArgsForCall = append(fake.submitSnapshotRequestArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	fake.recordInvocation("SubmitSnapshotRequest", []interface{}{arg1})
+	fake.submitSnapshotRequestMutex.Unlock()
+	if fake.SubmitSnapshotRequestStub != nil {
+		return fake.SubmitSnapshotRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.submitSnapshotRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestCallCount() int {
+	fake.submitSnapshotRequestMutex.RLock()
+	defer fake.submitSnapshotRequestMutex.RUnlock()
+	return len(fake.submitSnapshotRequestArgsForCall)
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestCalls(stub func(uint64) error) {
+	fake.submitSnapshotRequestMutex.Lock()
+	defer fake.submitSnapshotRequestMutex.Unlock()
+	fake.SubmitSnapshotRequestStub = stub
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestArgsForCall(i int) uint64 {
+	fake.submitSnapshotRequestMutex.RLock()
+	defer fake.submitSnapshotRequestMutex.RUnlock()
+	argsForCall := fake.submitSnapshotRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestReturns(result1 error) {
+	fake.submitSnapshotRequestMutex.Lock()
+	defer fake.submitSnapshotRequestMutex.Unlock()
+	fake.SubmitSnapshotRequestStub = nil
+	fake.submitSnapshotRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestReturnsOnCall(i int, result1 error) {
+	fake.submitSnapshotRequestMutex.Lock()
+	defer fake.submitSnapshotRequestMutex.Unlock()
+	fake.SubmitSnapshotRequestStub = nil
+	if fake.submitSnapshotRequestReturnsOnCall == nil {
+		fake.submitSnapshotRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.submitSnapshotRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1520,6 +1725,8 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.commitWithPvtDataMutex.RUnlock()
 	fake.doesPvtDataInfoExistMutex.RLock()
 	defer fake.doesPvtDataInfoExistMutex.RUnlock()
+	fake.exportSnapshotMutex.RLock()
+	defer fake.exportSnapshotMutex.RUnlock()
 	fake.getBlockByHashMutex.RLock()
 	defer fake.getBlockByHashMutex.RUnlock()
 	fake.getBlockByNumberMutex.RLock()
@@ -1548,12 +1755,16 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.newQueryExecutorMutex.RUnlock()
 	fake.newTxSimulatorMutex.RLock()
 	defer fake.newTxSimulatorMutex.RUnlock()
+	fake.pendingSnapshotRequestsMutex.RLock()
+	defer fake.pendingSnapshotRequestsMutex.RUnlock()
 	fake.privateDataMinBlockNumMutex.RLock()
 	defer fake.privateDataMinBlockNumMutex.RUnlock()
 	fake.pruneMutex.RLock()
 	defer fake.pruneMutex.RUnlock()
 	fake.purgePrivateDataMutex.RLock()
 	defer fake.purgePrivateDataMutex.RUnlock()
+	fake.submitSnapshotRequestMutex.RLock()
+	defer fake.submitSnapshotRequestMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -127,6 +127,25 @@ func (s *Store) Load(hash []byte) (ccInstallPkg []byte, name, version string, er
 	return ccInstallPkg, name, version, nil
 }
 
+// Delete removes a persisted chaincode install package and its metadata
+// given the hash of the package
+func (s *Store) Delete(hash []byte) error {
+	hashString := hex.EncodeToString(hash)
+	ccInstallPkgPath := filepath.Join(s.Path, hashString+".bin")
+	if err := s.ReadWriter.Remove(ccInstallPkgPath); err != nil {
+		return errors.Wrapf(err, "error removing chaincode install package at %s", ccInstallPkgPath)
+	}
+
+	// the metadata is removed last so that the package remains listed as
+	// installed, and the removal can be retried, until it is fully removed
+	metadataPath := filepath.Join(s.Path, hashString+".json")
+	if err := s.ReadWriter.Remove(metadataPath); err != nil {
+		return errors.Wrapf(err, "error removing metadata file at %s", metadataPath)
+	}
+
+	return nil
+}
+
 // LoadMetadata loads the chaincode metadata stored at the specified path
 func (s *Store) LoadMetadata(path string) (name, version string, err error) {
 	metadataBytes, err := s.ReadWriter.ReadFile(path)
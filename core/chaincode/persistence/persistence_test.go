@@ -264,6 +264,53 @@ var _ = Describe("Persistence", func() {
 		})
 	})
 
+	Describe("Delete", func() {
+		var (
+			mockReadWriter *mock.IOReadWriter
+			store          *persistence.Store
+			hashString     string
+		)
+
+		BeforeEach(func() {
+			mockReadWriter = &mock.IOReadWriter{}
+			store = &persistence.Store{
+				ReadWriter: mockReadWriter,
+			}
+			hashString = hex.EncodeToString([]byte("hash"))
+		})
+
+		It("removes the chaincode install package and then the metadata", func() {
+			err := store.Delete([]byte("hash"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mockReadWriter.RemoveCallCount()).To(Equal(2))
+			Expect(mockReadWriter.RemoveArgsForCall(0)).To(Equal(hashString + ".bin"))
+			Expect(mockReadWriter.RemoveArgsForCall(1)).To(Equal(hashString + ".json"))
+		})
+
+		Context("when removing the chaincode install package fails", func() {
+			BeforeEach(func() {
+				mockReadWriter.RemoveReturnsOnCall(0, errors.New("offside"))
+			})
+
+			It("returns an error and keeps the metadata", func() {
+				err := store.Delete([]byte("hash"))
+				Expect(err).To(MatchError("error removing chaincode install package at " + hashString + ".bin: offside"))
+				Expect(mockReadWriter.RemoveCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when removing the metadata fails", func() {
+			BeforeEach(func() {
+				mockReadWriter.RemoveReturnsOnCall(1, errors.New("handball"))
+			})
+
+			It("returns an error", func() {
+				err := store.Delete([]byte("hash"))
+				Expect(err).To(MatchError("error removing metadata file at " + hashString + ".json: handball"))
+			})
+		})
+	})
+
 	Describe("RetrieveHash", func() {
 		var (
 			mockReadWriter *mock.IOReadWriter
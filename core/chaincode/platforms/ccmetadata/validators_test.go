@@ -30,6 +30,17 @@ func TestGoodIndexJSON(t *testing.T) {
 	assert.NoError(t, err, "Error validating a good index")
 }
 
+func TestGoodLevelDBIndexJSON(t *testing.T) {
+	fileName := "META-INF/statedb/leveldb/indexes/myIndex.json"
+	fileBytes := []byte(`{"index":{"fields":["data.docType","data.owner"]},"name":"indexOwner","type":"json"}`)
+	err := ValidateMetadataFile(fileName, fileBytes)
+	assert.NoError(t, err, "Error validating a good leveldb index")
+
+	fileName = "META-INF/statedb/leveldb/collections/collectionMarbles/indexes/myIndex.json"
+	err = ValidateMetadataFile(fileName, fileBytes)
+	assert.NoError(t, err, "Error validating a good leveldb collection index")
+}
+
 func TestBadIndexJSON(t *testing.T) {
 	testDir := filepath.Join(packageTestDir, "BadIndexJSON")
 	cleanupDir(testDir)
@@ -50,6 +50,10 @@ func (q *QueryResponseGenerator) BuildQueryResponse(txContext *TransactionContex
 				txContext.CleanupQueryContext(iterID)
 				return nil, err
 			}
+			if err := txContext.ResourceMeter.QueryResult(proto.Size(queryResult.(proto.Message))); err != nil {
+				txContext.CleanupQueryContext(iterID)
+				return nil, err
+			}
 			*totalReturnCount++
 			return &pb.QueryResponse{Results: batch, HasMore: true, Id: iterID}, nil
 
@@ -58,6 +62,10 @@ func (q *QueryResponseGenerator) BuildQueryResponse(txContext *TransactionContex
 				txContext.CleanupQueryContext(iterID)
 				return nil, err
 			}
+			if err := txContext.ResourceMeter.QueryResult(proto.Size(queryResult.(proto.Message))); err != nil {
+				txContext.CleanupQueryContext(iterID)
+				return nil, err
+			}
 			*totalReturnCount++
 		}
 	}
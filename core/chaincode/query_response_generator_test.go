@@ -161,3 +161,30 @@ func TestBuildQueryResponseErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildQueryResponseResourceLimits(t *testing.T) {
+	txSimulator := &mock.TxSimulator{}
+	transactionContext := &chaincode.TransactionContext{
+		TXSimulator: txSimulator,
+		ResourceMeter: &chaincode.ResourceMeter{
+			Chaincode: "chaincode-name",
+			Limits:    chaincode.ResourceLimits{MaxRangeQueryResults: 2},
+		},
+	}
+	resultsIterator := &mock.QueryResultsIterator{}
+	resultsIterator.NextReturns(&queryresult.KV{Key: "key-name"}, nil)
+	transactionContext.InitializeQueryContext("query-id", resultsIterator)
+
+	responseGenerator := &chaincode.QueryResponseGenerator{
+		MaxResultLimit: 3,
+	}
+	resp, err := responseGenerator.BuildQueryResponse(transactionContext, resultsIterator, "query-id", false, totalQueryLimit)
+	assert.EqualError(t, err, "chaincode chaincode-name exceeded its limit of 2 range query results per transaction")
+	assert.Nil(t, resp)
+	assert.Equal(t, 1, resultsIterator.CloseCallCount())
+	assert.Nil(t, transactionContext.GetQueryIterator("query-id"))
+
+	usage := transactionContext.ResourceMeter.Usage()
+	assert.Equal(t, uint64(3), usage.RangeQueryResults)
+	assert.NotZero(t, usage.TotalBytes)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"sync"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// ResourceLimits are the limits on the resources a chaincode may use to
+// simulate a transaction. A limit of zero or less means no limit.
+type ResourceLimits struct {
+	MaxStateReads        int `mapstructure:"maxStateReads" yaml:"maxStateReads"`
+	MaxRangeQueryResults int `mapstructure:"maxRangeQueryResults" yaml:"maxRangeQueryResults"`
+	MaxWrites            int `mapstructure:"maxWrites" yaml:"maxWrites"`
+	MaxTotalBytes        int `mapstructure:"maxTotalBytes" yaml:"maxTotalBytes"`
+}
+
+// ResourceLimitsConfig holds the resource limits of user chaincodes.
+type ResourceLimitsConfig struct {
+	// Default holds the limits of chaincodes without limits of their own.
+	Default ResourceLimits
+	// Chaincodes holds the limits of individual chaincodes by name.
+	Chaincodes map[string]ResourceLimits
+}
+
+// Limits returns the resource limits of the named chaincode.
+func (r *ResourceLimitsConfig) Limits(chaincodeName string) ResourceLimits {
+	if r == nil {
+		return ResourceLimits{}
+	}
+	if limits, ok := r.Chaincodes[chaincodeName]; ok {
+		return limits
+	}
+	return r.Default
+}
+
+// ResourceLimitExceededError is returned when a chaincode exceeds one of
+// its resource limits.
+type ResourceLimitExceededError struct {
+	Chaincode string
+	Resource  string
+	Limit     int
+}
+
+func (e *ResourceLimitExceededError) Error() string {
+	return fmt.Sprintf("chaincode %s exceeded its limit of %d %s per transaction", e.Chaincode, e.Limit, e.Resource)
+}
+
+// ResourceMeter counts the resources used by a chaincode to simulate a
+// transaction and enforces the resource limits of the chaincode. Once a
+// limit has been exceeded, every further use of resources fails, and so
+// does the transaction. The methods of a nil ResourceMeter do nothing.
+type ResourceMeter struct {
+	Chaincode string
+	Limits    ResourceLimits
+
+	mutex             sync.Mutex
+	stateReads        int
+	rangeQueryResults int
+	writes            int
+	totalBytes        int
+	err               error
+}
+
+// StateRead accounts for a read of a key of the state whose value has
+// the given size.
+func (m *ResourceMeter) StateRead(size int) error {
+	return m.use(1, 0, 0, size)
+}
+
+// QueryResult accounts for a result of a range, rich or history query
+// of the given size.
+func (m *ResourceMeter) QueryResult(size int) error {
+	return m.use(0, 1, 0, size)
+}
+
+// Write accounts for a write or a deletion of a key of the state of the
+// given size.
+func (m *ResourceMeter) Write(size int) error {
+	return m.use(0, 0, 1, size)
+}
+
+// Abort fails the transaction with the given error, which is returned by
+// every further use of resources. It is used when a chaincode invoked by
+// the chaincode exceeded its resource limits.
+func (m *ResourceMeter) Abort(err error) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	if m.err == nil {
+		m.err = err
+	}
+	m.mutex.Unlock()
+}
+
+// Err returns the error which fails the transaction, if a resource limit
+// has been exceeded.
+func (m *ResourceMeter) Err() error {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.err
+}
+
+// Usage returns the resources used by the chaincode.
+func (m *ResourceMeter) Usage() *pb.ChaincodeResourceUsage {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return &pb.ChaincodeResourceUsage{
+		Chaincode:         m.Chaincode,
+		StateReads:        uint64(m.stateReads),
+		RangeQueryResults: uint64(m.rangeQueryResults),
+		Writes:            uint64(m.writes),
+		TotalBytes:        uint64(m.totalBytes),
+	}
+}
+
+func (m *ResourceMeter) use(stateReads, rangeQueryResults, writes, size int) error {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+
+	m.stateReads += stateReads
+	m.rangeQueryResults += rangeQueryResults
+	m.writes += writes
+	m.totalBytes += size
+
+	switch {
+	case exceeds(m.stateReads, m.Limits.MaxStateReads):
+		m.err = m.limitExceeded("state reads", m.Limits.MaxStateReads)
+	case exceeds(m.rangeQueryResults, m.Limits.MaxRangeQueryResults):
+		m.err = m.limitExceeded("range query results", m.Limits.MaxRangeQueryResults)
+	case exceeds(m.writes, m.Limits.MaxWrites):
+		m.err = m.limitExceeded("writes", m.Limits.MaxWrites)
+	case exceeds(m.totalBytes, m.Limits.MaxTotalBytes):
+		m.err = m.limitExceeded("bytes", m.Limits.MaxTotalBytes)
+	}
+	return m.err
+}
+
+func (m *ResourceMeter) limitExceeded(resource string, limit int) error {
+	return &ResourceLimitExceededError{Chaincode: m.Chaincode, Resource: resource, Limit: limit}
+}
+
+func exceeds(count, limit int) bool {
+	return limit > 0 && count > limit
+}
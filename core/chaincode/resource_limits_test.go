@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceLimitsConfig(t *testing.T) {
+	config := &chaincode.ResourceLimitsConfig{
+		Default: chaincode.ResourceLimits{MaxWrites: 10},
+		Chaincodes: map[string]chaincode.ResourceLimits{
+			"mycc": {MaxWrites: 100},
+		},
+	}
+	assert.Equal(t, chaincode.ResourceLimits{MaxWrites: 100}, config.Limits("mycc"))
+	assert.Equal(t, chaincode.ResourceLimits{MaxWrites: 10}, config.Limits("othercc"))
+
+	config = nil
+	assert.Equal(t, chaincode.ResourceLimits{}, config.Limits("mycc"))
+}
+
+func TestResourceMeterLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      chaincode.ResourceLimits
+		use         func(*chaincode.ResourceMeter) error
+		expectedErr string
+	}{
+		{
+			name:        "state reads",
+			limits:      chaincode.ResourceLimits{MaxStateReads: 2},
+			use:         func(m *chaincode.ResourceMeter) error { return m.StateRead(1) },
+			expectedErr: "chaincode mycc exceeded its limit of 2 state reads per transaction",
+		},
+		{
+			name:        "range query results",
+			limits:      chaincode.ResourceLimits{MaxRangeQueryResults: 2},
+			use:         func(m *chaincode.ResourceMeter) error { return m.QueryResult(1) },
+			expectedErr: "chaincode mycc exceeded its limit of 2 range query results per transaction",
+		},
+		{
+			name:        "writes",
+			limits:      chaincode.ResourceLimits{MaxWrites: 2},
+			use:         func(m *chaincode.ResourceMeter) error { return m.Write(1) },
+			expectedErr: "chaincode mycc exceeded its limit of 2 writes per transaction",
+		},
+		{
+			name:        "bytes",
+			limits:      chaincode.ResourceLimits{MaxTotalBytes: 20},
+			use:         func(m *chaincode.ResourceMeter) error { return m.StateRead(10) },
+			expectedErr: "chaincode mycc exceeded its limit of 20 bytes per transaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meter := &chaincode.ResourceMeter{Chaincode: "mycc", Limits: tt.limits}
+			assert.NoError(t, tt.use(meter))
+			assert.NoError(t, tt.use(meter))
+			assert.NoError(t, meter.Err())
+
+			err := tt.use(meter)
+			assert.EqualError(t, err, tt.expectedErr)
+			assert.IsType(t, &chaincode.ResourceLimitExceededError{}, err)
+			assert.Equal(t, err, meter.Err())
+
+			// the error is sticky and no more resources are counted
+			usage := meter.Usage()
+			assert.Equal(t, err, meter.Write(0))
+			assert.Equal(t, usage, meter.Usage())
+		})
+	}
+}
+
+func TestResourceMeterUsage(t *testing.T) {
+	meter := &chaincode.ResourceMeter{Chaincode: "mycc"}
+	assert.NoError(t, meter.StateRead(5))
+	assert.NoError(t, meter.StateRead(6))
+	assert.NoError(t, meter.QueryResult(7))
+	assert.NoError(t, meter.Write(8))
+	assert.NoError(t, meter.Err())
+
+	assert.Equal(t, &pb.ChaincodeResourceUsage{
+		Chaincode:         "mycc",
+		StateReads:        2,
+		RangeQueryResults: 1,
+		Writes:            1,
+		TotalBytes:        26,
+	}, meter.Usage())
+}
+
+func TestResourceMeterAbort(t *testing.T) {
+	meter := &chaincode.ResourceMeter{Chaincode: "mycc"}
+	meter.Abort(errors.New("callee-limit-exceeded"))
+	meter.Abort(errors.New("another-error"))
+	assert.EqualError(t, meter.Err(), "callee-limit-exceeded")
+	assert.EqualError(t, meter.StateRead(1), "callee-limit-exceeded")
+	assert.Equal(t, uint64(0), meter.Usage().StateReads)
+}
+
+func TestNilResourceMeter(t *testing.T) {
+	var meter *chaincode.ResourceMeter
+	assert.NoError(t, meter.StateRead(1))
+	assert.NoError(t, meter.QueryResult(1))
+	assert.NoError(t, meter.Write(1))
+	meter.Abort(errors.New("error"))
+	assert.NoError(t, meter.Err())
+	assert.Nil(t, meter.Usage())
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"context"
 	"strconv"
 	"time"
 
@@ -26,6 +27,14 @@ type PackageProvider interface {
 	GetChaincodeCodePackage(ccname string, ccversion string) ([]byte, error)
 }
 
+// ChaincodeServers tells which chaincodes run as servers the peer connects to.
+type ChaincodeServers interface {
+	// Serves returns whether the chaincode runs as a server.
+	Serves(ccName string) bool
+	// HealthCheck checks that the chaincode servers are reachable.
+	HealthCheck(ctx context.Context) error
+}
+
 // RuntimeLauncher is responsible for launching chaincode runtimes.
 type RuntimeLauncher struct {
 	Runtime         Runtime
@@ -33,6 +42,11 @@ type RuntimeLauncher struct {
 	PackageProvider PackageProvider
 	StartupTimeout  time.Duration
 	Metrics         *LaunchMetrics
+	// Servers, if set, are the chaincodes run as servers. Launching such
+	// chaincode connects to its server, which is retried every
+	// ReconnectInterval until the startup timeout expires.
+	Servers           ChaincodeServers
+	ReconnectInterval time.Duration
 }
 
 func (r *RuntimeLauncher) Launch(ccci *ccprovider.ChaincodeContainerInfo) error {
@@ -46,15 +60,34 @@ func (r *RuntimeLauncher) Launch(ccci *ccprovider.ChaincodeContainerInfo) error
 		startFailCh = make(chan error, 1)
 		timeoutCh = time.NewTimer(r.StartupTimeout).C
 
-		codePackage, err := r.getCodePackage(ccci)
-		if err != nil {
-			return err
+		isServer := r.Servers != nil && r.Servers.Serves(ccci.Name)
+		var codePackage []byte
+		if !isServer {
+			var err error
+			codePackage, err = r.getCodePackage(ccci)
+			if err != nil {
+				return err
+			}
 		}
 
 		go func() {
-			if err := r.Runtime.Start(ccci, codePackage); err != nil {
-				startFailCh <- errors.WithMessage(err, "error starting container")
-				return
+			for {
+				err := r.Runtime.Start(ccci, codePackage)
+				if err == nil {
+					break
+				}
+				if !isServer {
+					startFailCh <- errors.WithMessage(err, "error starting container")
+					return
+				}
+
+				// the chaincode server may not be up yet, or restarting
+				chaincodeLogger.Warningf("failed to connect to chaincode server %s, retrying in %s: %s", cname, r.ReconnectInterval, err)
+				select {
+				case <-launchState.Done():
+					return
+				case <-time.After(r.ReconnectInterval):
+				}
 			}
 			exitCode, err := r.Runtime.Wait(ccci)
 			if err != nil {
@@ -96,6 +129,14 @@ func (r *RuntimeLauncher) Launch(ccci *ccprovider.ChaincodeContainerInfo) error
 	return err
 }
 
+// HealthCheck checks that the chaincode servers, if any, are reachable.
+func (r *RuntimeLauncher) HealthCheck(ctx context.Context) error {
+	if r.Servers == nil {
+		return nil
+	}
+	return r.Servers.HealthCheck(ctx)
+}
+
 func (r *RuntimeLauncher) getCodePackage(ccci *ccprovider.ChaincodeContainerInfo) ([]byte, error) {
 	if ccci.ContainerType == inproccontroller.ContainerType {
 		return nil, nil
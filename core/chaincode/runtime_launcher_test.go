@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode_test
 
 import (
+	"context"
 	"time"
 
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
@@ -329,6 +330,68 @@ var _ = Describe("RuntimeLauncher", func() {
 		})
 	})
 
+	Context("when the chaincode runs as a server", func() {
+		var fakeServers *mock.ChaincodeServers
+
+		BeforeEach(func() {
+			fakeServers = &mock.ChaincodeServers{}
+			fakeServers.ServesReturns(true)
+			runtimeLauncher.Servers = fakeServers
+			runtimeLauncher.ReconnectInterval = 10 * time.Millisecond
+		})
+
+		It("connects without a code package", func() {
+			err := runtimeLauncher.Launch(ccci)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeServers.ServesCallCount()).To(Equal(1))
+			Expect(fakeServers.ServesArgsForCall(0)).To(Equal("chaincode-name"))
+			Expect(fakePackageProvider.GetChaincodeCodePackageCallCount()).To(Equal(0))
+			Expect(fakeRuntime.StartCallCount()).To(Equal(1))
+			_, codePackage := fakeRuntime.StartArgsForCall(0)
+			Expect(codePackage).To(BeNil())
+		})
+
+		Context("when connecting fails", func() {
+			BeforeEach(func() {
+				fakeRuntime.StartStub = nil
+				fakeRuntime.StartReturnsOnCall(0, errors.New("connection-refused"))
+				fakeRuntime.StartReturnsOnCall(1, errors.New("connection-refused"))
+			})
+
+			It("reconnects until connected", func() {
+				errCh := make(chan error, 1)
+				go func() { errCh <- runtimeLauncher.Launch(ccci) }()
+
+				Eventually(fakeRuntime.StartCallCount).Should(Equal(3))
+				launchState.Notify(nil)
+				Eventually(errCh).Should(Receive(BeNil()))
+				Expect(fakeLaunchFailures.AddCallCount()).To(Equal(0))
+			})
+
+			It("stops reconnecting when the startup timeout expires", func() {
+				fakeRuntime.StartReturns(errors.New("connection-refused"))
+				runtimeLauncher.StartupTimeout = 250 * time.Millisecond
+
+				err := runtimeLauncher.Launch(ccci)
+				Expect(err).To(MatchError("timeout expired while starting chaincode chaincode-name:chaincode-version for transaction"))
+				startCount := fakeRuntime.StartCallCount()
+				Consistently(fakeRuntime.StartCallCount).Should(Equal(startCount))
+			})
+		})
+
+		It("health checks the chaincode servers", func() {
+			fakeServers.HealthCheckReturns(errors.New("unreachable"))
+			err := runtimeLauncher.HealthCheck(context.Background())
+			Expect(err).To(MatchError("unreachable"))
+			Expect(fakeServers.HealthCheckCallCount()).To(Equal(1))
+		})
+	})
+
+	It("is healthy without chaincode servers", func() {
+		Expect(runtimeLauncher.HealthCheck(context.Background())).To(Succeed())
+	})
+
 	Context("when stopping the runtime fails", func() {
 		BeforeEach(func() {
 			fakeRuntime.StartReturns(errors.New("whirled-peas"))
@@ -0,0 +1,215 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// ChaincodeServerConfig is the address of chaincode run as a server and the
+// configuration of the client the peer connects to it with.
+type ChaincodeServerConfig struct {
+	Address      string
+	ClientConfig comm.ClientConfig
+}
+
+// StreamHandler runs the chaincode handler over a stream to chaincode.
+type StreamHandler interface {
+	HandleChaincodeStream(stream ccintf.ChaincodeStream) error
+}
+
+// ServerRuntime is responsible for chaincode run as a server. Instead of
+// starting the chaincode, it connects to the chaincode server and runs the
+// chaincode handler over the connection. Chaincode which does not run as a
+// server is passed to the Fallback runtime.
+type ServerRuntime struct {
+	// Servers are the chaincode servers by chaincode name
+	Servers       map[string]*ChaincodeServerConfig
+	StreamHandler StreamHandler
+	Fallback      Runtime
+
+	mutex       sync.Mutex
+	connections map[string]*serverConnection
+}
+
+// serverConnection is a connection of the peer to a chaincode server
+type serverConnection struct {
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Serves returns whether the chaincode runs as a server.
+func (s *ServerRuntime) Serves(ccName string) bool {
+	_, ok := s.Servers[ccName]
+	return ok
+}
+
+// Start connects to the chaincode server and runs the chaincode handler over
+// the connection until the connection is closed.
+func (s *ServerRuntime) Start(ccci *ccprovider.ChaincodeContainerInfo, codePackage []byte) error {
+	config, ok := s.Servers[ccci.Name]
+	if !ok {
+		return s.Fallback.Start(ccci, codePackage)
+	}
+
+	cname := ccci.Name + ":" + ccci.Version
+	client, err := comm.NewGRPCClient(config.ClientConfig)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to create client for chaincode server %s", cname))
+	}
+	conn, err := client.NewConnection(config.Address, "")
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to connect to chaincode server %s at %s", cname, config.Address))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := pb.NewChaincodeClient(conn).Connect(ctx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return errors.Wrapf(err, "failed to open stream to chaincode server %s at %s", cname, config.Address)
+	}
+
+	sc := &serverConnection{conn: conn, cancel: cancel, done: make(chan struct{})}
+	s.setConnection(cname, sc)
+
+	go func() {
+		sc.err = s.StreamHandler.HandleChaincodeStream(&registrationCheckingStream{Chaincode_ConnectClient: stream, cname: cname})
+		cancel()
+		conn.Close()
+		close(sc.done)
+	}()
+
+	chaincodeLogger.Debugf("connected to chaincode server %s at %s", cname, config.Address)
+	return nil
+}
+
+// Stop closes the connection to the chaincode server.
+func (s *ServerRuntime) Stop(ccci *ccprovider.ChaincodeContainerInfo) error {
+	if !s.Serves(ccci.Name) {
+		return s.Fallback.Stop(ccci)
+	}
+
+	cname := ccci.Name + ":" + ccci.Version
+	sc := s.connection(cname)
+	if sc == nil {
+		return nil
+	}
+	s.setConnection(cname, nil)
+	sc.cancel()
+	<-sc.done
+
+	return nil
+}
+
+// Wait waits for the connection to the chaincode server to be closed.
+func (s *ServerRuntime) Wait(ccci *ccprovider.ChaincodeContainerInfo) (int, error) {
+	if !s.Serves(ccci.Name) {
+		return s.Fallback.Wait(ccci)
+	}
+
+	cname := ccci.Name + ":" + ccci.Version
+	sc := s.connection(cname)
+	if sc == nil {
+		return 0, errors.Errorf("not connected to chaincode server %s", cname)
+	}
+	<-sc.done
+
+	return 0, sc.err
+}
+
+// HealthCheck checks that all chaincode servers accept connections.
+func (s *ServerRuntime) HealthCheck(ctx context.Context) error {
+	var failed []string
+	for ccName, config := range s.Servers {
+		clientConfig := config.ClientConfig
+		if deadline, ok := ctx.Deadline(); ok {
+			clientConfig.Timeout = time.Until(deadline)
+		}
+		client, err := comm.NewGRPCClient(clientConfig)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", ccName, err))
+			continue
+		}
+		conn, err := client.NewConnection(config.Address, "")
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", ccName, err))
+			continue
+		}
+		conn.Close()
+	}
+
+	if len(failed) != 0 {
+		sort.Strings(failed)
+		return errors.Errorf("chaincode servers unreachable: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (s *ServerRuntime) connection(cname string) *serverConnection {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.connections[cname]
+}
+
+func (s *ServerRuntime) setConnection(cname string, sc *serverConnection) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if sc == nil {
+		delete(s.connections, cname)
+		return
+	}
+	if s.connections == nil {
+		s.connections = map[string]*serverConnection{}
+	}
+	s.connections[cname] = sc
+}
+
+// registrationCheckingStream ensures the chaincode server registers as the
+// chaincode the peer connected to it for. Chaincode connecting to the peer is
+// authenticated by its TLS client certificate instead.
+type registrationCheckingStream struct {
+	pb.Chaincode_ConnectClient
+	cname      string
+	registered bool
+}
+
+func (r *registrationCheckingStream) Recv() (*pb.ChaincodeMessage, error) {
+	msg, err := r.Chaincode_ConnectClient.Recv()
+	if err != nil || r.registered {
+		return msg, err
+	}
+	r.registered = true
+
+	if msg.Type != pb.ChaincodeMessage_REGISTER {
+		return nil, errors.Errorf("chaincode server %s sent %s instead of registering", r.cname, msg.Type)
+	}
+	chaincodeID := &pb.ChaincodeID{}
+	if err := proto.Unmarshal(msg.Payload, chaincodeID); err != nil {
+		return nil, errors.Wrapf(err, "chaincode server %s sent a malformed registration", r.cname)
+	}
+	if chaincodeID.Name != r.cname {
+		return nil, errors.Errorf("chaincode server %s registered as %s", r.cname, chaincodeID.Name)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/mock"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamHandlerFunc handles the chaincode stream with a function
+type streamHandlerFunc func(stream ccintf.ChaincodeStream) error
+
+func (f streamHandlerFunc) HandleChaincodeStream(stream ccintf.ChaincodeStream) error {
+	return f(stream)
+}
+
+// registeringHandler receives the registration of the chaincode and then
+// waits for the stream to be closed
+func registeringHandler(registered chan<- *pb.ChaincodeID) streamHandlerFunc {
+	return func(stream ccintf.ChaincodeStream) error {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		chaincodeID := &pb.ChaincodeID{}
+		if err := proto.Unmarshal(msg.Payload, chaincodeID); err != nil {
+			return err
+		}
+		registered <- chaincodeID
+
+		_, err = stream.Recv()
+		return err
+	}
+}
+
+type testChaincode struct{}
+
+func (testChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response   { return shim.Success(nil) }
+func (testChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response { return shim.Success(nil) }
+
+// startChaincodeServer starts a chaincode server with mutual TLS and
+// returns its address
+func startChaincodeServer(t *testing.T, ca tlsgen.CA, ccid string) (string, *shim.ChaincodeServer) {
+	serverKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+
+	server := &shim.ChaincodeServer{
+		CCID:    ccid,
+		Address: address,
+		CC:      testChaincode{},
+		TLSProps: shim.TLSProperties{
+			Key:           serverKeyPair.Key,
+			Cert:          serverKeyPair.Cert,
+			ClientCACerts: ca.CertBytes(),
+		},
+	}
+	go server.Start()
+
+	return address, server
+}
+
+func serverConfig(t *testing.T, ca tlsgen.CA, address string) *chaincode.ChaincodeServerConfig {
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+
+	return &chaincode.ChaincodeServerConfig{
+		Address: address,
+		ClientConfig: comm.ClientConfig{
+			SecOpts: &comm.SecureOptions{
+				UseTLS:            true,
+				RequireClientCert: true,
+				Key:               clientKeyPair.Key,
+				Certificate:       clientKeyPair.Cert,
+				ServerRootCAs:     [][]byte{ca.CertBytes()},
+			},
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+func TestServerRuntime(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	address, server := startChaincodeServer(t, ca, "mycc:1.0")
+	defer server.Stop()
+
+	registered := make(chan *pb.ChaincodeID, 1)
+	sr := &chaincode.ServerRuntime{
+		Servers:       map[string]*chaincode.ChaincodeServerConfig{"mycc": serverConfig(t, ca, address)},
+		StreamHandler: registeringHandler(registered),
+	}
+	ccci := &ccprovider.ChaincodeContainerInfo{Name: "mycc", Version: "1.0"}
+	assert.True(t, sr.Serves("mycc"))
+
+	// the server may take a moment to start listening
+	for i := 0; i < 50; i++ {
+		if err = sr.Start(ccci, nil); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+
+	select {
+	case chaincodeID := <-registered:
+		assert.Equal(t, "mycc:1.0", chaincodeID.Name)
+	case <-time.After(10 * time.Second):
+		t.Fatal("chaincode did not register")
+	}
+	assert.NoError(t, sr.HealthCheck(context.Background()))
+
+	waitErr := make(chan error, 1)
+	go func() {
+		_, err := sr.Wait(ccci)
+		waitErr <- err
+	}()
+
+	err = sr.Stop(ccci)
+	assert.NoError(t, err)
+	select {
+	case err := <-waitErr:
+		assert.Error(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("wait did not return after stop")
+	}
+
+	_, err = sr.Wait(ccci)
+	assert.EqualError(t, err, "not connected to chaincode server mycc:1.0")
+}
+
+func TestServerRuntimeRegistrationMismatch(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	address, server := startChaincodeServer(t, ca, "othercc:1.0")
+	defer server.Stop()
+
+	sr := &chaincode.ServerRuntime{
+		Servers:       map[string]*chaincode.ChaincodeServerConfig{"mycc": serverConfig(t, ca, address)},
+		StreamHandler: registeringHandler(make(chan *pb.ChaincodeID, 1)),
+	}
+	ccci := &ccprovider.ChaincodeContainerInfo{Name: "mycc", Version: "1.0"}
+
+	for i := 0; i < 50; i++ {
+		if err = sr.Start(ccci, nil); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+
+	_, err = sr.Wait(ccci)
+	assert.EqualError(t, err, "chaincode server mycc:1.0 registered as othercc:1.0")
+}
+
+func TestServerRuntimeUnreachable(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+
+	config := serverConfig(t, ca, address)
+	config.ClientConfig.Timeout = 100 * time.Millisecond
+	sr := &chaincode.ServerRuntime{
+		Servers: map[string]*chaincode.ChaincodeServerConfig{"mycc": config},
+	}
+
+	err = sr.Start(&ccprovider.ChaincodeContainerInfo{Name: "mycc", Version: "1.0"}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to chaincode server mycc:1.0 at "+address)
+
+	err = sr.HealthCheck(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "chaincode servers unreachable: mycc: ")
+}
+
+func TestServerRuntimeFallback(t *testing.T) {
+	fakeRuntime := &mock.Runtime{}
+	fakeRuntime.WaitReturns(7, nil)
+	sr := &chaincode.ServerRuntime{
+		Servers:  map[string]*chaincode.ChaincodeServerConfig{"mycc": {}},
+		Fallback: fakeRuntime,
+	}
+	ccci := &ccprovider.ChaincodeContainerInfo{Name: "othercc", Version: "1.0"}
+	assert.False(t, sr.Serves("othercc"))
+
+	err := sr.Start(ccci, []byte("code-package"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fakeRuntime.StartCallCount())
+	_, codePackage := fakeRuntime.StartArgsForCall(0)
+	assert.Equal(t, []byte("code-package"), codePackage)
+
+	exitCode, err := sr.Wait(ccci)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, exitCode)
+
+	err = sr.Stop(ccci)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fakeRuntime.StopCallCount())
+}
@@ -708,13 +708,39 @@ func (stub *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryI
 
 // GetHistoryForKey documentation can be found in interfaces.go
 func (stub *ChaincodeStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetHistoryForKey(key, stub.ChannelId, stub.TxID)
+	response, err := stub.handler.handleGetHistoryForKey(key, nil, stub.ChannelId, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
 	return &HistoryQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
 }
 
+// GetHistoryForKeyWithOptions documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetHistoryForKeyWithOptions(key string,
+	options *pb.HistoryQueryMetadata) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+
+	if options == nil {
+		options = &pb.HistoryQueryMetadata{}
+	}
+	metadata, err := proto.Marshal(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := stub.handler.handleGetHistoryForKey(key, metadata, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iterator := &HistoryQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}
+	responseMetadata, err := createQueryResponseMetadata(response.Metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return iterator, responseMetadata, nil
+}
+
 //CreateCompositeKey documentation can be found in interfaces.go
 func (stub *ChaincodeStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
 	return createCompositeKey(objectType, attributes)
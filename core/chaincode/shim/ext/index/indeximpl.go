@@ -0,0 +1,362 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package index
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/pkg/errors"
+)
+
+// indexObjectType is the object type of the composite keys of the index entries.
+// The attributes of a composite key are the value type, the index name, the field
+// values, and the key of the record
+const indexObjectType = "~index"
+
+// compositeKeyNamespace is the first character of the composite keys
+const compositeKeyNamespace = "\x00"
+
+// indexEntryValue is the value of an index entry, as an empty value deletes the key
+var indexEntryValue = []byte{0x00}
+
+// store implements the Store
+type store struct {
+	stub      shim.ChaincodeStubInterface
+	valueType string
+	indexes   map[string]*Index
+	// pending holds the values of the records written through the store in the
+	// ongoing transaction, a nil value marks a deleted record
+	pending map[string][]byte
+}
+
+// NewStore constructs a Store for the records of the given value type with the given secondary indexes.
+// The value type scopes the index entries, so that different value types may declare indexes with the
+// same name
+func NewStore(stub shim.ChaincodeStubInterface, valueType string, indexes ...*Index) (Store, error) {
+	if valueType == "" {
+		return nil, errors.New("value type must not be empty")
+	}
+	s := &store{
+		stub:      stub,
+		valueType: valueType,
+		indexes:   make(map[string]*Index),
+		pending:   make(map[string][]byte),
+	}
+	for _, index := range indexes {
+		if index.Name == "" {
+			return nil, errors.New("index name must not be empty")
+		}
+		if len(index.Fields) == 0 {
+			return nil, errors.Errorf("index [%s] must have at least one field", index.Name)
+		}
+		if _, ok := s.indexes[index.Name]; ok {
+			return nil, errors.Errorf("index [%s] is declared more than once", index.Name)
+		}
+		s.indexes[index.Name] = index
+	}
+	return s, nil
+}
+
+// GetState returns the value of the record with the given key
+func (s *store) GetState(key string) ([]byte, error) {
+	if value, ok := s.pending[key]; ok {
+		return value, nil
+	}
+	return s.stub.GetState(key)
+}
+
+// PutState writes the value of the record and replaces the index entries
+// of the previous value with the index entries of the new value
+func (s *store) PutState(key string, value []byte) error {
+	if len(value) == 0 {
+		return errors.Errorf("value of the record [%s] must not be empty, use DelState to delete the record", key)
+	}
+	newEntries, err := s.indexEntries(key, value)
+	if err != nil {
+		return err
+	}
+	oldEntries, err := s.currentIndexEntries(key)
+	if err != nil {
+		return err
+	}
+	for entry := range oldEntries {
+		if _, ok := newEntries[entry]; ok {
+			continue
+		}
+		if err := s.stub.DelState(entry); err != nil {
+			return err
+		}
+	}
+	for entry := range newEntries {
+		if _, ok := oldEntries[entry]; ok {
+			continue
+		}
+		if err := s.stub.PutState(entry, indexEntryValue); err != nil {
+			return err
+		}
+	}
+	if err := s.stub.PutState(key, value); err != nil {
+		return err
+	}
+	s.pending[key] = value
+	return nil
+}
+
+// DelState deletes the record and its index entries
+func (s *store) DelState(key string) error {
+	oldEntries, err := s.currentIndexEntries(key)
+	if err != nil {
+		return err
+	}
+	for entry := range oldEntries {
+		if err := s.stub.DelState(entry); err != nil {
+			return err
+		}
+	}
+	if err := s.stub.DelState(key); err != nil {
+		return err
+	}
+	s.pending[key] = nil
+	return nil
+}
+
+// GetByIndex returns the records that match the given field values in the index
+func (s *store) GetByIndex(indexName string, fieldValues ...string) (Iterator, error) {
+	index, ok := s.indexes[indexName]
+	if !ok {
+		return nil, errors.Errorf("index [%s] is not declared for the value type [%s]", indexName, s.valueType)
+	}
+	if len(fieldValues) > len(index.Fields) {
+		return nil, errors.Errorf("index [%s] has %d field(s) but %d value(s) are supplied",
+			indexName, len(index.Fields), len(fieldValues))
+	}
+	attributes := append([]string{s.valueType, indexName}, fieldValues...)
+	itr, err := s.stub.GetStateByPartialCompositeKey(indexObjectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{store: s, index: index, itr: itr}, nil
+}
+
+// Reindex adds the index entries of the records in the given key range. The index entries
+// that already exist are written again, which does not change their values. The composite
+// keys are skipped, as these hold the index entries and not the records
+func (s *store) Reindex(startKey, endKey string) error {
+	itr, err := s.stub.GetStateByRange(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for itr.HasNext() {
+		kv, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(kv.Key, compositeKeyNamespace) {
+			continue
+		}
+		entries, err := s.indexEntries(kv.Key, kv.Value)
+		if err != nil {
+			return err
+		}
+		for entry := range entries {
+			if err := s.stub.PutState(entry, indexEntryValue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// currentIndexEntries returns the index entries of the current value of the record. A value that
+// cannot be indexed, e.g., a value written directly through the stub, has no index entries
+func (s *store) currentIndexEntries(key string) (map[string]struct{}, error) {
+	value, err := s.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return map[string]struct{}{}, nil
+	}
+	entries, err := s.indexEntries(key, value)
+	if err != nil {
+		return map[string]struct{}{}, nil
+	}
+	return entries, nil
+}
+
+// indexEntries returns the keys of the index entries for the given value of the record
+func (s *store) indexEntries(key string, value []byte) (map[string]struct{}, error) {
+	fields, err := decodeValue(key, value)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]struct{})
+	for _, index := range s.indexes {
+		fieldValues, indexed, err := indexFieldValues(index, fields)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error indexing the record [%s]", key))
+		}
+		if !indexed {
+			continue
+		}
+		attributes := append(append([]string{s.valueType, index.Name}, fieldValues...), key)
+		entry, err := s.stub.CreateCompositeKey(indexObjectType, attributes)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error constructing the entry of the index [%s] for the record [%s]", index.Name, key))
+		}
+		entries[entry] = struct{}{}
+	}
+	return entries, nil
+}
+
+// decodeValue decodes the JSON value of a record, retaining the numbers in their original form
+func decodeValue(key string, value []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	fields := make(map[string]interface{})
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, errors.Wrapf(err, "value of the record [%s] is not a JSON object", key)
+	}
+	return fields, nil
+}
+
+// indexFieldValues returns the values of the fields of the index. The boolean result is false
+// if any of the fields is absent or null, in which case the record is not added to the index
+func indexFieldValues(index *Index, fields map[string]interface{}) ([]string, bool, error) {
+	fieldValues := make([]string, 0, len(index.Fields))
+	for _, field := range index.Fields {
+		value, ok := lookupField(fields, field)
+		if !ok || value == nil {
+			return nil, false, nil
+		}
+		switch v := value.(type) {
+		case string:
+			fieldValues = append(fieldValues, v)
+		case json.Number:
+			fieldValues = append(fieldValues, v.String())
+		case bool:
+			fieldValues = append(fieldValues, strconv.FormatBool(v))
+		default:
+			return nil, false, errors.Errorf("field [%s] of the index [%s] holds a JSON object or an array", field, index.Name)
+		}
+	}
+	return fieldValues, true, nil
+}
+
+// lookupField returns the value at the dotted path of the field
+func lookupField(fields map[string]interface{}, field string) (interface{}, bool) {
+	path := strings.Split(field, ".")
+	var value interface{} = fields
+	for _, name := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// iterator implements the Iterator by looking up the record of each index entry. An entry is
+// skipped if the record does not exist or its current value does not match the entry
+type iterator struct {
+	store *store
+	index *Index
+	itr   shim.StateQueryIteratorInterface
+	next  *queryresult.KV
+	err   error
+}
+
+// HasNext returns true if the iterator has one more record
+func (i *iterator) HasNext() bool {
+	if i.next == nil && i.err == nil {
+		i.next, i.err = i.fetchNext()
+	}
+	return i.next != nil || i.err != nil
+}
+
+// Next returns the key and the value of the next record
+func (i *iterator) Next() (*queryresult.KV, error) {
+	if !i.HasNext() {
+		return nil, errors.New("no more records")
+	}
+	next, err := i.next, i.err
+	i.next, i.err = nil, nil
+	return next, err
+}
+
+// NextInto unmarshals the value of the next record into v and returns the key of the record
+func (i *iterator) NextInto(v interface{}) (string, error) {
+	kv, err := i.Next()
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(kv.Value, v); err != nil {
+		return "", errors.Wrapf(err, "error unmarshaling the value of the record [%s]", kv.Key)
+	}
+	return kv.Key, nil
+}
+
+// Close closes the underlying iterator over the index entries
+func (i *iterator) Close() error {
+	return i.itr.Close()
+}
+
+func (i *iterator) fetchNext() (*queryresult.KV, error) {
+	for i.itr.HasNext() {
+		entry, err := i.itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		_, attributes, err := i.store.stub.SplitCompositeKey(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		key := attributes[len(attributes)-1]
+		value, err := i.store.GetState(key)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		fields, err := decodeValue(key, value)
+		if err != nil {
+			return nil, err
+		}
+		fieldValues, indexed, err := indexFieldValues(i.index, fields)
+		if err != nil {
+			return nil, err
+		}
+		if !indexed || !equal(fieldValues, attributes[2:len(attributes)-1]) {
+			continue
+		}
+		return &queryresult.KV{Key: key, Value: value}, nil
+	}
+	return nil, nil
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
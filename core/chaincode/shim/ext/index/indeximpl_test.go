@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package index_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/chaincode/shim/ext/index"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type car struct {
+	Owner string `json:"owner"`
+	Color string `json:"color"`
+	Make  string `json:"make,omitempty"`
+}
+
+var carIndexes = []*index.Index{
+	{Name: "owner", Fields: []string{"owner"}},
+	{Name: "colorMake", Fields: []string{"color", "make"}},
+}
+
+func TestNewStore(t *testing.T) {
+	stub := shim.NewMockStub("indextest", nil)
+
+	_, err := index.NewStore(stub, "")
+	assert.EqualError(t, err, "value type must not be empty")
+	_, err = index.NewStore(stub, "car", &index.Index{Fields: []string{"owner"}})
+	assert.EqualError(t, err, "index name must not be empty")
+	_, err = index.NewStore(stub, "car", &index.Index{Name: "owner"})
+	assert.EqualError(t, err, "index [owner] must have at least one field")
+	_, err = index.NewStore(stub, "car", carIndexes[0], carIndexes[0])
+	assert.EqualError(t, err, "index [owner] is declared more than once")
+
+	s, err := index.NewStore(stub, "car", carIndexes...)
+	assert.NoError(t, err)
+	assert.NotNil(t, s)
+}
+
+func TestGetByIndex(t *testing.T) {
+	stub := shim.NewMockStub("indextest", nil)
+	stub.MockTransactionStart("tx1")
+	s, err := index.NewStore(stub, "car", carIndexes...)
+	require.NoError(t, err)
+	require.NoError(t, s.PutState("car1", []byte(`{"owner":"alice","color":"red","make":"ford"}`)))
+	require.NoError(t, s.PutState("car2", []byte(`{"owner":"bob","color":"red","make":"vw"}`)))
+	require.NoError(t, s.PutState("car3", []byte(`{"owner":"alice","color":"blue","make":null}`)))
+	stub.MockTransactionEnd("tx1")
+
+	assert.Equal(t, []string{"car1", "car3"}, queryKeys(t, s, "owner", "alice"))
+	assert.Equal(t, []string{"car2"}, queryKeys(t, s, "owner", "bob"))
+	assert.Empty(t, queryKeys(t, s, "owner", "carol"))
+	// the record without a make is not present in the index on color and make
+	assert.Equal(t, []string{"car1", "car2"}, queryKeys(t, s, "colorMake"))
+	assert.Equal(t, []string{"car1", "car2"}, queryKeys(t, s, "colorMake", "red"))
+	assert.Equal(t, []string{"car2"}, queryKeys(t, s, "colorMake", "red", "vw"))
+	assert.Empty(t, queryKeys(t, s, "colorMake", "blue"))
+
+	itr, err := s.GetByIndex("owner", "bob")
+	require.NoError(t, err)
+	defer itr.Close()
+	c := &car{}
+	key, err := itr.NextInto(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "car2", key)
+	assert.Equal(t, &car{Owner: "bob", Color: "red", Make: "vw"}, c)
+	assert.False(t, itr.HasNext())
+	_, err = itr.Next()
+	assert.EqualError(t, err, "no more records")
+
+	_, err = s.GetByIndex("make", "ford")
+	assert.EqualError(t, err, "index [make] is not declared for the value type [car]")
+	_, err = s.GetByIndex("owner", "alice", "bob")
+	assert.EqualError(t, err, "index [owner] has 1 field(s) but 2 value(s) are supplied")
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	stub := shim.NewMockStub("indextest", nil)
+	stub.MockTransactionStart("tx1")
+	s, err := index.NewStore(stub, "car", carIndexes...)
+	require.NoError(t, err)
+	require.NoError(t, s.PutState("car1", []byte(`{"owner":"alice","color":"red","make":"ford"}`)))
+	require.NoError(t, s.PutState("car2", []byte(`{"owner":"bob","color":"red","make":"vw"}`)))
+	stub.MockTransactionEnd("tx1")
+
+	// the record is written twice in the same transaction and only the
+	// index entries of the last value remain
+	stub.MockTransactionStart("tx2")
+	s, err = index.NewStore(stub, "car", carIndexes...)
+	require.NoError(t, err)
+	require.NoError(t, s.PutState("car1", []byte(`{"owner":"bob","color":"red","make":"ford"}`)))
+	require.NoError(t, s.PutState("car1", []byte(`{"owner":"carol","color":"red","make":"ford"}`)))
+	value, err := s.GetState("car1")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"owner":"carol","color":"red","make":"ford"}`, string(value))
+	require.NoError(t, s.DelState("car2"))
+	value, err = s.GetState("car2")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	stub.MockTransactionEnd("tx2")
+
+	assert.Empty(t, queryKeys(t, s, "owner", "alice"))
+	assert.Empty(t, queryKeys(t, s, "owner", "bob"))
+	assert.Equal(t, []string{"car1"}, queryKeys(t, s, "owner", "carol"))
+	assert.Equal(t, []string{"car1"}, queryKeys(t, s, "colorMake", "red"))
+	assert.Len(t, indexEntries(stub), 2)
+}
+
+func TestStaleIndexEntries(t *testing.T) {
+	stub := shim.NewMockStub("indextest", nil)
+	stub.MockTransactionStart("tx1")
+	s, err := index.NewStore(stub, "car", carIndexes...)
+	require.NoError(t, err)
+	require.NoError(t, s.PutState("car1", []byte(`{"owner":"alice","color":"red","make":"ford"}`)))
+	require.NoError(t, s.PutState("car2", []byte(`{"owner":"alice","color":"red","make":"vw"}`)))
+	// the records are modified directly through the stub, bypassing the store
+	require.NoError(t, stub.PutState("car1", []byte(`{"owner":"bob","color":"red","make":"ford"}`)))
+	require.NoError(t, stub.DelState("car2"))
+	stub.MockTransactionEnd("tx1")
+
+	s, err = index.NewStore(stub, "car", carIndexes...)
+	require.NoError(t, err)
+	assert.Empty(t, queryKeys(t, s, "owner", "alice"))
+	assert.Empty(t, queryKeys(t, s, "owner", "bob"))
+	assert.Equal(t, []string{"car1"}, queryKeys(t, s, "colorMake", "red"))
+}
+
+func TestInvalidValues(t *testing.T) {
+	stub := shim.NewMockStub("indextest", nil)
+	stub.MockTransactionStart("tx1")
+	defer stub.MockTransactionEnd("tx1")
+	s, err := index.NewStore(stub, "car", carIndexes...)
+	require.NoError(t, err)
+
+	err = s.PutState("car1", []byte("not-json"))
+	assert.Contains(t, err.Error(), "value of the record [car1] is not a JSON object")
+	err = s.PutState("car1", []byte(`{"owner":{"name":"alice"}}`))
+	assert.EqualError(t, err, "error indexing the record [car1]: field [owner] of the index [owner] holds a JSON object or an array")
+	err = s.PutState("car1", nil)
+	assert.EqualError(t, err, "value of the record [car1] must not be empty, use DelState to delete the record")
+	assert.Empty(t, stub.State)
+
+	// a value written directly through the stub that cannot be indexed is replaced without errors
+	require.NoError(t, stub.PutState("car1", []byte("not-json")))
+	require.NoError(t, s.PutState("car1", []byte(`{"owner":"alice"}`)))
+	assert.Equal(t, []string{"car1"}, queryKeys(t, s, "owner", "alice"))
+}
+
+func TestNestedFieldsAndReindex(t *testing.T) {
+	stub := shim.NewMockStub("indextest", nil)
+	stub.MockTransactionStart("tx1")
+	require.NoError(t, stub.PutState("car1", []byte(`{"owner":{"name":"alice"},"year":2018,"electric":true}`)))
+	require.NoError(t, stub.PutState("car2", []byte(`{"owner":{"name":"bob"},"year":2019,"electric":false}`)))
+	require.NoError(t, stub.PutState("truck1", []byte(`{"owner":{"name":"alice"},"year":2019}`)))
+	stub.MockTransactionEnd("tx1")
+
+	s, err := index.NewStore(stub, "car",
+		&index.Index{Name: "ownerName", Fields: []string{"owner.name"}},
+		&index.Index{Name: "yearElectric", Fields: []string{"year", "electric"}},
+	)
+	require.NoError(t, err)
+	// the records written before the indexes are declared are not indexed until reindexed
+	assert.Empty(t, queryKeys(t, s, "ownerName", "alice"))
+
+	stub.MockTransactionStart("tx2")
+	require.NoError(t, s.Reindex("car", "cas"))
+	stub.MockTransactionEnd("tx2")
+	assert.Equal(t, []string{"car1"}, queryKeys(t, s, "ownerName", "alice"))
+	assert.Equal(t, []string{"car2"}, queryKeys(t, s, "yearElectric", "2019", "false"))
+	assert.Equal(t, []string{"car1"}, queryKeys(t, s, "yearElectric", "2018", "true"))
+
+	// reindexing the entire key range skips the index entries
+	stub.MockTransactionStart("tx3")
+	require.NoError(t, s.Reindex("", ""))
+	stub.MockTransactionEnd("tx3")
+	assert.Equal(t, []string{"car1", "truck1"}, queryKeys(t, s, "ownerName", "alice"))
+	assert.Len(t, indexEntries(stub), 5)
+}
+
+func queryKeys(t *testing.T, s index.Store, indexName string, fieldValues ...string) []string {
+	itr, err := s.GetByIndex(indexName, fieldValues...)
+	require.NoError(t, err)
+	defer itr.Close()
+	var keys []string
+	for itr.HasNext() {
+		kv, err := itr.Next()
+		require.NoError(t, err)
+		keys = append(keys, kv.Key)
+	}
+	return keys
+}
+
+func indexEntries(stub *shim.MockStub) []string {
+	var entries []string
+	for key := range stub.State {
+		if strings.HasPrefix(key, "\x00~index") {
+			entries = append(entries, key)
+		}
+	}
+	return entries
+}
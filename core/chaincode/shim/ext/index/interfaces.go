@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package index
+
+import "github.com/hyperledger/fabric/protos/ledger/queryresult"
+
+// Index declares a secondary index on one or more fields of the JSON values of a
+// value type. A field is identified by its name and the fields of a nested object
+// are identified by a dotted path, e.g., "owner.name". A record is added to the
+// index only if all the fields are present in its value and hold a string, a number,
+// or a boolean. The records are ordered in the index by the string representation
+// of the field values, i.e., the numbers are ordered lexically
+type Index struct {
+	Name   string
+	Fields []string
+}
+
+// Store maintains the records of a value type in the world state along with the
+// secondary indexes declared for the value type. The records are stored under the
+// keys supplied by the chaincode whereas the index entries are stored as composite
+// keys, so the store works the same on all the state databases. The indexes are kept
+// consistent only if the records are modified through the store.
+//
+// As the world state does not reflect the writes of the ongoing transaction, the store
+// remembers the records written through it so that a record that is written more than
+// once in a transaction leaves no stale index entries. Hence, a transaction is expected
+// to use a single Store instance for a value type
+type Store interface {
+	// GetState returns the value of the record with the given key
+	GetState(key string) ([]byte, error)
+
+	// PutState writes the JSON value of the record with the given key and
+	// updates the index entries of the record
+	PutState(key string, value []byte) error
+
+	// DelState deletes the record with the given key and its index entries
+	DelState(key string) error
+
+	// GetByIndex returns the records whose leading fields of the given index match the given
+	// field values. Passing fewer values than the fields of the index matches on a prefix of
+	// the fields and passing no value returns all the records in the index
+	GetByIndex(indexName string, fieldValues ...string) (Iterator, error)
+
+	// Reindex adds the index entries for the records in the key range [startKey, endKey).
+	// This is used to index the records written before an index was declared
+	Reindex(startKey, endKey string) error
+}
+
+// Iterator iterates over the records returned by a query on an index, in the order of the index.
+// The entries that no longer match the value of the record, e.g., if the record was modified
+// directly through the stub, are skipped
+type Iterator interface {
+	// HasNext returns true if the iterator has one more record
+	HasNext() bool
+
+	// Next returns the key and the value of the next record
+	Next() (*queryresult.KV, error)
+
+	// NextInto unmarshals the JSON value of the next record into v and returns the key of the record
+	NextInto(v interface{}) (string, error)
+
+	// Close closes the iterator
+	Close() error
+}
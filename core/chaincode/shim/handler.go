@@ -660,7 +660,7 @@ func (handler *Handler) handleGetQueryResult(collection string, query string, me
 	return nil, errors.Errorf("incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
 }
 
-func (handler *Handler) handleGetHistoryForKey(key string, channelId string, txid string) (*pb.QueryResponse, error) {
+func (handler *Handler) handleGetHistoryForKey(key string, metadata []byte, channelId string, txid string) (*pb.QueryResponse, error) {
 	// Create the channel on which to communicate the response from validating peer
 	var respChan chan pb.ChaincodeMessage
 	var err error
@@ -673,7 +673,7 @@ func (handler *Handler) handleGetHistoryForKey(key string, channelId string, txi
 
 	// Send GET_HISTORY_FOR_KEY message to peer chaincode support
 	//we constructed a valid object. No need to check for error
-	payloadBytes, _ := proto.Marshal(&pb.GetHistoryForKey{Key: key})
+	payloadBytes, _ := proto.Marshal(&pb.GetHistoryForKey{Key: key, Metadata: metadata})
 
 	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
 	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_HISTORY_FOR_KEY)
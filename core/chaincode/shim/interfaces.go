@@ -233,6 +233,25 @@ type ChaincodeStubInterface interface {
 	// update ledger, and should limit use to read-only chaincode operations.
 	GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error)
 
+	// GetHistoryForKeyWithOptions returns a history of key values across time,
+	// restricted and ordered as specified by the options. The history can be
+	// bounded to a range of block numbers [startBlock, endBlock], where an
+	// endBlock of zero denotes an open range, and to a range of transaction
+	// timestamps [startTime, endTime), where an unset timestamp denotes an open
+	// range. As the timestamps are set by the clients, the modifications
+	// within a range of timestamps are ordered by timestamp rather than by
+	// block number, except on the peers whose history database predates the
+	// index of the timestamps and was not rebuilt since, which order them by
+	// block number. When newestFirst is set, the most recent modification is
+	// returned first. When a pageSize or a bookmark is set, the returned iterator can be
+	// used to fetch the first `pageSize` records after the bookmark, and the
+	// returned QueryResponseMetadata carries the bookmark of the next page.
+	// An empty bookmark fetches the first page. Passing nil options returns
+	// the same results as GetHistoryForKey.
+	// The same configuration requirements and restrictions as for
+	// GetHistoryForKey apply.
+	GetHistoryForKeyWithOptions(key string, options *pb.HistoryQueryMetadata) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetPrivateData returns the value of the specified `key` from the specified
 	// `collection`. Note that GetPrivateData doesn't read data from the
 	// private writeset, which has not been committed to the `collection`. In
@@ -296,6 +296,13 @@ func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterfac
 	return nil, errors.New("not implemented")
 }
 
+// GetHistoryForKeyWithOptions function can be invoked by a chaincode to return a
+// bounded, ordered or paginated history of key values across time.
+func (stub *MockStub) GetHistoryForKeyWithOptions(key string,
+	options *pb.HistoryQueryMetadata) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
 //state based on a given partial composite key. This function returns an
 //iterator which can be used to iterate over all composite keys whose prefix
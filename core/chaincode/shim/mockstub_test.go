@@ -314,6 +314,7 @@ func TestMockMock(t *testing.T) {
 	stub.GetArgsSlice()
 	stub.SetEvent("e", nil)
 	stub.GetHistoryForKey("k")
+	stub.GetHistoryForKeyWithOptions("k", nil)
 	iter := &MockStateRangeQueryIterator{}
 	iter.HasNext()
 	iter.Close()
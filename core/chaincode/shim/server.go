@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// TLSProperties are the TLS configuration of a ChaincodeServer
+type TLSProperties struct {
+	// Disabled disables TLS, which should only be done for testing
+	Disabled bool
+	// Key is the PEM encoded private key of the server
+	Key []byte
+	// Cert is the PEM encoded certificate of the server
+	Cert []byte
+	// ClientCACerts are the PEM encoded certificates of the CAs which issue
+	// the TLS client certificates of the peers. If set, peers must present
+	// a certificate issued by one of them.
+	ClientCACerts []byte
+}
+
+// ChaincodeServer runs chaincode as a server the peer connects to, as an
+// alternative to the chaincode connecting to the peer with Start. This allows
+// chaincode to be run as a long-lived service by an orchestrator of choice.
+type ChaincodeServer struct {
+	// CCID is the name, in the form name:version, the chaincode registers
+	// with the peer
+	CCID string
+	// Address is the listen address of the server
+	Address string
+	// CC is the chaincode run by the server
+	CC Chaincode
+	// TLSProps is the TLS configuration of the server
+	TLSProps TLSProperties
+
+	server *comm.GRPCServer
+}
+
+// Connect runs the chaincode over the stream opened by a peer. It is the
+// implementation of the Chaincode service, and not an API for chaincodes.
+func (cs *ChaincodeServer) Connect(stream pb.Chaincode_ConnectServer) error {
+	return chatWithPeer(cs.CCID, &serverStream{stream}, cs.CC)
+}
+
+// Start starts the server and blocks until the server is stopped or fails
+func (cs *ChaincodeServer) Start() error {
+	switch {
+	case cs.CCID == "":
+		return errors.New("ccid must be specified")
+	case cs.Address == "":
+		return errors.New("address must be specified")
+	case cs.CC == nil:
+		return errors.New("chaincode must be specified")
+	case !cs.TLSProps.Disabled && (cs.TLSProps.Key == nil || cs.TLSProps.Cert == nil):
+		return errors.New("key and cert must be specified unless TLS is disabled")
+	}
+
+	SetupChaincodeLogging()
+
+	err := factory.InitFactories(factory.GetDefaultOpts())
+	if err != nil {
+		return errors.WithMessage(err, "internal error, BCCSP could not be initialized with default options")
+	}
+
+	secOpts := &comm.SecureOptions{UseTLS: !cs.TLSProps.Disabled}
+	if secOpts.UseTLS {
+		secOpts.Key = cs.TLSProps.Key
+		secOpts.Certificate = cs.TLSProps.Cert
+		if cs.TLSProps.ClientCACerts != nil {
+			secOpts.RequireClientCert = true
+			secOpts.ClientRootCAs = [][]byte{cs.TLSProps.ClientCACerts}
+		}
+	}
+
+	server, err := comm.NewGRPCServer(cs.Address, comm.ServerConfig{
+		SecOpts: secOpts,
+		KaOpts:  comm.DefaultKeepaliveOptions,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to create chaincode server")
+	}
+	pb.RegisterChaincodeServer(server.Server(), cs)
+	cs.server = server
+
+	chaincodeLogger.Infof("Chaincode %s listening for peer connections on %s", cs.CCID, server.Address())
+	return server.Start()
+}
+
+// Stop stops the server
+func (cs *ChaincodeServer) Stop() {
+	if cs.server != nil {
+		cs.server.Stop()
+	}
+}
+
+// serverStream adapts the server side of the stream opened by the peer to the
+// stream interface of the chaincode handler. The server side is closed when
+// Connect returns.
+type serverStream struct {
+	pb.Chaincode_ConnectServer
+}
+
+func (s *serverStream) CloseSend() error {
+	return nil
+}
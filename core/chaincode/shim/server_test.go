@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaincodeServerStartValidation(t *testing.T) {
+	tests := []struct {
+		server      *ChaincodeServer
+		expectedErr string
+	}{
+		{&ChaincodeServer{Address: "127.0.0.1:0", CC: &shimTestCC{}}, "ccid must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", CC: &shimTestCC{}}, "address must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0"}, "chaincode must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0", CC: &shimTestCC{}}, "key and cert must be specified unless TLS is disabled"},
+	}
+
+	for _, tt := range tests {
+		err := tt.server.Start()
+		assert.EqualError(t, err, tt.expectedErr)
+	}
+}
+
+func TestChaincodeServerConnect(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+
+	server := &ChaincodeServer{
+		CCID:     "mycc:1.0",
+		Address:  address,
+		CC:       &shimTestCC{},
+		TLSProps: TLSProperties{Disabled: true},
+	}
+	go server.Start()
+	defer server.Stop()
+
+	client, err := comm.NewGRPCClient(comm.ClientConfig{Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	var stream pb.Chaincode_ConnectClient
+	for i := 0; i < 50; i++ {
+		conn, err := client.NewConnection(address, "")
+		if err == nil {
+			defer conn.Close()
+			stream, err = pb.NewChaincodeClient(conn).Connect(context.Background())
+			require.NoError(t, err)
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NotNil(t, stream, "could not connect to chaincode server")
+
+	// the chaincode registers with the peer over the stream opened by the peer
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.ChaincodeMessage_REGISTER, msg.Type)
+	chaincodeID := &pb.ChaincodeID{}
+	require.NoError(t, proto.Unmarshal(msg.Payload, chaincodeID))
+	assert.Equal(t, "mycc:1.0", chaincodeID.Name)
+}
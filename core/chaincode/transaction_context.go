@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -25,6 +26,11 @@ type TransactionContext struct {
 	CollectionStore      privdata.CollectionStore
 	IsInitTransaction    bool
 
+	// counts the resources used by the chaincode and enforces its limits
+	ResourceMeter *ResourceMeter
+	// collects the resources used by every chaincode of the transaction
+	ResourceUsage *ccprovider.ResourceUsage
+
 	// tracks open iterators used for range queries
 	queryMutex          sync.Mutex
 	queryIteratorMap    map[string]commonledger.ResultsIterator
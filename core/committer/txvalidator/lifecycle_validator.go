@@ -0,0 +1,216 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// validateLifecycleWrites checks the writes of a transaction to the namespace of
+// the lifecycle system chaincode, which the endorsement policy of the system
+// chaincode does not restrict enough: the implicit collection of an organization,
+// which holds the approvals of the organization, may only be written by endorsers
+// of that organization, and the public state may only hold committed chaincode
+// definitions, each of which is valid only if the endorsers of the organizations
+// which approved it satisfy the lifecycle endorsement policy of the channel.
+func (v *VsccValidatorImpl) validateLifecycleWrites(payload *common.Payload, channelID string, ns *rwsetutil.NsRwSet) (error, peer.TxValidationCode) {
+	var writtenOrgs []string
+	for _, coll := range ns.CollHashedRwSets {
+		if coll.HashedRwSet == nil || len(coll.HashedRwSet.HashedWrites) == 0 && len(coll.HashedRwSet.MetadataWrites) == 0 {
+			continue
+		}
+		mspID, ok := privdata.MSPIDIfImplicitCollection(coll.CollectionName)
+		if !ok {
+			return errors.Errorf("transaction attempted to write to collection %s of namespace %s, which is not an implicit collection", coll.CollectionName, ns.NameSpace),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+		writtenOrgs = append(writtenOrgs, mspID)
+	}
+
+	writesPublicState := ns.KvRwSet != nil && (len(ns.KvRwSet.Writes) > 0 || len(ns.KvRwSet.MetadataWrites) > 0)
+	if len(writtenOrgs) == 0 && !writesPublicState {
+		return nil, peer.TxValidationCode_VALID
+	}
+
+	signatureSet, err := endorsementSignatureSet(payload)
+	if err != nil {
+		return err, peer.TxValidationCode_BAD_PAYLOAD
+	}
+
+	for _, mspID := range writtenOrgs {
+		policy, err := v.signaturePolicy(cauthdsl.SignedByMspMember(mspID))
+		if err != nil {
+			return err, peer.TxValidationCode_INVALID_OTHER_REASON
+		}
+		if err := policy.Evaluate(signatureSet); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("implicit collection of org %s must be written by an endorser of the org", mspID)),
+				peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+		}
+	}
+
+	if !writesPublicState {
+		return nil, peer.TxValidationCode_VALID
+	}
+
+	if len(ns.KvRwSet.MetadataWrites) > 0 {
+		return errors.Errorf("transaction attempted to write metadata to the public state of namespace %s", ns.NameSpace),
+			peer.TxValidationCode_ILLEGAL_WRITESET
+	}
+
+	policy, err := v.lifecycleEndorsementPolicy(channelID)
+	if err != nil {
+		return err, peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+
+	for _, write := range ns.KvRwSet.Writes {
+		chaincodeName, ok := privdata.ChaincodeNameIfDefinitionKey(write.Key)
+		if !ok {
+			return errors.Errorf("transaction attempted to write key %s to the public state of namespace %s, which is not a chaincode definition", write.Key, ns.NameSpace),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+		if write.IsDelete {
+			return errors.Errorf("transaction attempted to delete the definition of chaincode %s", chaincodeName),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+		cd := &lb.ChaincodeDefinition{}
+		if err := proto.Unmarshal(write.Value, cd); err != nil {
+			return errors.Wrapf(err, "invalid definition of chaincode %s", chaincodeName),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+
+		approvingOrgs, err := v.approvingOrgs(channelID, chaincodeName, cd.Sequence, write.Value)
+		if err != nil {
+			return err, peer.TxValidationCode_INVALID_OTHER_REASON
+		}
+		if err := policy.Evaluate(v.signaturesOfOrgs(signatureSet, approvingOrgs)); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("definition of chaincode %s is not approved by endorsing orgs satisfying the lifecycle endorsement policy of the channel", chaincodeName)),
+				peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+		}
+	}
+
+	return nil, peer.TxValidationCode_VALID
+}
+
+// approvingOrgs returns the MSP IDs of the organizations of the channel which
+// approved the definition of the chaincode at the sequence, as recorded in the
+// hashes of their implicit collections
+func (v *VsccValidatorImpl) approvingOrgs(channelID, chaincodeName string, sequence int64, definition []byte) (map[string]bool, error) {
+	l := v.support.Ledger()
+	if l == nil {
+		return nil, errors.New("nil ledger instance")
+	}
+
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not retrieve QueryExecutor")
+	}
+	defer qe.Done()
+
+	definitionHash := util.ComputeSHA256(definition)
+	approvingOrgs := map[string]bool{}
+	for _, mspID := range v.support.GetMSPIDs(channelID) {
+		approvalHash, err := qe.GetPrivateDataHash(privdata.LifecycleNamespace, privdata.ImplicitCollectionNameForOrg(mspID), privdata.ChaincodeApprovalKey(chaincodeName, sequence))
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not read approval of org %s", mspID))
+		}
+		if bytes.Equal(approvalHash, definitionHash) {
+			approvingOrgs[mspID] = true
+		}
+	}
+
+	return approvingOrgs, nil
+}
+
+// signaturesOfOrgs returns the signatures of the signature set made by
+// identities of the organizations with the given MSP IDs
+func (v *VsccValidatorImpl) signaturesOfOrgs(signatureSet []*common.SignedData, mspIDs map[string]bool) []*common.SignedData {
+	var signatures []*common.SignedData
+	for _, sd := range signatureSet {
+		identity, err := v.support.MSPManager().DeserializeIdentity(sd.Identity)
+		if err != nil {
+			logger.Warningf("could not deserialize endorser identity: %s", err)
+			continue
+		}
+		if mspIDs[identity.GetIdentifier().Mspid] {
+			signatures = append(signatures, sd)
+		}
+	}
+	return signatures
+}
+
+// lifecycleEndorsementPolicy returns the lifecycle endorsement policy of the
+// channel or, if the channel does not define one, a policy requiring the
+// endorsement of a majority of the application organizations of the channel
+func (v *VsccValidatorImpl) lifecycleEndorsementPolicy(channelID string) (policies.Policy, error) {
+	if pm, ok := v.sccprovider.PolicyManager(channelID); ok {
+		if policy, ok := pm.GetPolicy(policies.ChannelApplicationLifecycleEndorsement); ok {
+			return policy, nil
+		}
+	}
+
+	mspIDs := v.support.GetMSPIDs(channelID)
+	return v.signaturePolicy(cauthdsl.SignedByNOutOfGivenRole(int32(len(mspIDs)/2+1), mb.MSPRole_MEMBER, mspIDs))
+}
+
+// signaturePolicy compiles the signature policy against the MSPs of the channel
+func (v *VsccValidatorImpl) signaturePolicy(envelope *common.SignaturePolicyEnvelope) (policies.Policy, error) {
+	policy, _, err := cauthdsl.NewPolicyProvider(v.support.MSPManager()).NewPolicy(utils.MarshalOrPanic(envelope))
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not compile signature policy")
+	}
+	return policy, nil
+}
+
+// endorsementSignatureSet returns the data signed by each of the
+// endorsers of the transaction in the payload
+func endorsementSignatureSet(payload *common.Payload) ([]*common.SignedData, error) {
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not unmarshal transaction")
+	}
+	if len(tx.Actions) != 1 {
+		return nil, errors.Errorf("transaction must have exactly one action, but has %d", len(tx.Actions))
+	}
+
+	ccActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not unmarshal chaincode action payload")
+	}
+	if ccActionPayload.Action == nil {
+		return nil, errors.New("chaincode action payload has no endorsed action")
+	}
+
+	prp := ccActionPayload.Action.ProposalResponsePayload
+	signatureSet := make([]*common.SignedData, 0, len(ccActionPayload.Action.Endorsements))
+	for _, endorsement := range ccActionPayload.Action.Endorsements {
+		data := make([]byte, len(prp)+len(endorsement.Endorser))
+		copy(data, prp)
+		copy(data[len(prp):], endorsement.Endorser)
+
+		signatureSet = append(signatureSet, &common.SignedData{
+			Data:      data,
+			Identity:  endorsement.Endorser,
+			Signature: endorsement.Signature,
+		})
+	}
+
+	return signatureSet, nil
+}
@@ -0,0 +1,347 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/mocks/scc"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/discovery/support/mocks"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	mb "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/semaphore"
+)
+
+// lifecycleTestSupport is the support of a channel of the orgs Org1MSP, Org2MSP and Org3MSP
+type lifecycleTestSupport struct {
+	*mocktxvalidator.Support
+	*semaphore.Weighted
+}
+
+func (s *lifecycleTestSupport) GetMSPIDs(cid string) []string {
+	return []string{"Org1MSP", "Org2MSP", "Org3MSP"}
+}
+
+// testDefinition is the definition of mycc at sequence 1
+var testDefinition = utils.MarshalOrPanic(&lb.ChaincodeDefinition{Sequence: 1, Version: "1.0"})
+
+// newLifecycleTestValidator returns a validator for a channel with the lifecycle
+// approval capability whose MSP manager deserializes the endorsers "<mspID>-endorser"
+// as members of mspID, and whose ledger holds the approvals of testDefinition by
+// the given orgs
+func newLifecycleTestValidator(pm policies.Manager, approvingOrgs ...string) (*VsccValidatorImpl, *mockconfig.MockApplicationCapabilities) {
+	mspManager := &mocks.MSPManager{}
+	mspManager.DeserializeIdentityStub = func(serializedIdentity []byte) (msp.Identity, error) {
+		mspID := string(serializedIdentity[:len(serializedIdentity)-len("-endorser")])
+		identity := &mocks.Identity{}
+		identity.GetIdentifierReturns(&msp.IdentityIdentifier{Mspid: mspID, Id: "endorser"})
+		identity.SatisfiesPrincipalStub = func(principal *mb.MSPPrincipal) error {
+			role := &mb.MSPRole{}
+			if err := proto.Unmarshal(principal.Principal, role); err != nil {
+				return err
+			}
+			if role.MspIdentifier != mspID {
+				return errors.New("identity is not a member of the org")
+			}
+			return nil
+		}
+		return identity, nil
+	}
+
+	approvals := map[string][]byte{}
+	for _, mspID := range approvingOrgs {
+		approvals["_implicit_org_"+mspID] = util.ComputeSHA256(testDefinition)
+	}
+	theLedger := &approvalsLedger{qe: &approvalsQueryExecutor{approvals: approvals}}
+
+	capabilities := &mockconfig.MockApplicationCapabilities{
+		LifecycleApprovalRv:   true,
+		PrivateChannelDataRv:  true,
+		KeyLevelEndorsementRv: true,
+	}
+
+	sccp := &scc.MocksccProviderImpl{
+		PolicyManagerRv:   pm,
+		PolicyManagerBool: pm != nil,
+		SysCCMap:          map[string]bool{"+lifecycle": true},
+	}
+	support := &lifecycleTestSupport{
+		Support:  &mocktxvalidator.Support{LedgerVal: theLedger, MSPManagerVal: mspManager, ACVal: capabilities},
+		Weighted: semaphore.NewWeighted(10),
+	}
+	pluginValidator := NewPluginValidator(acceptingPluginMapper{}, nil, nil, nil)
+	return newVSCCValidator("foochain", support, sccp, pluginValidator), capabilities
+}
+
+// approvalsLedger is a ledger whose query executors serve the approvals
+// of the definition of mycc at sequence 1
+type approvalsLedger struct {
+	ledger.PeerLedger
+	qe *approvalsQueryExecutor
+}
+
+func (l *approvalsLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
+	return l.qe, nil
+}
+
+// approvalsQueryExecutor serves the hashes of the approvals
+// of the definition of mycc at sequence 1, by collection
+type approvalsQueryExecutor struct {
+	ledger.QueryExecutor
+	approvals map[string][]byte
+}
+
+func (qe *approvalsQueryExecutor) GetPrivateDataHash(namespace, collection, key string) ([]byte, error) {
+	if namespace != "+lifecycle" || key != "approvals/mycc#1" {
+		return nil, nil
+	}
+	return qe.approvals[collection], nil
+}
+
+func (qe *approvalsQueryExecutor) Done() {}
+
+// acceptingPluginMapper maps every plugin name to a validation
+// plugin which accepts every transaction
+type acceptingPluginMapper struct{}
+
+func (acceptingPluginMapper) PluginFactoryByName(name PluginName) validation.PluginFactory {
+	return acceptingPluginMapper{}
+}
+
+func (acceptingPluginMapper) New() validation.Plugin {
+	return acceptingPluginMapper{}
+}
+
+func (acceptingPluginMapper) Init(dependencies ...validation.Dependency) error {
+	return nil
+}
+
+func (acceptingPluginMapper) Validate(block *common.Block, namespace string, txPosition int, actionPosition int, contextData ...validation.ContextDatum) error {
+	return nil
+}
+
+func endorsements(endorserMSPIDs ...string) []*peer.Endorsement {
+	var endorsements []*peer.Endorsement
+	for _, mspID := range endorserMSPIDs {
+		endorsements = append(endorsements, &peer.Endorsement{
+			Endorser:  []byte(mspID + "-endorser"),
+			Signature: []byte("signature"),
+		})
+	}
+	return endorsements
+}
+
+func endorsedPayload(endorserMSPIDs ...string) *common.Payload {
+	ccActionPayload := &peer.ChaincodeActionPayload{
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: []byte("proposal-response-payload"),
+			Endorsements:            endorsements(endorserMSPIDs...),
+		},
+	}
+	tx := &peer.Transaction{
+		Actions: []*peer.TransactionAction{
+			{Payload: utils.MarshalOrPanic(ccActionPayload)},
+		},
+	}
+	return &common.Payload{Data: utils.MarshalOrPanic(tx)}
+}
+
+// lifecycleEnvelope returns the payload and the marshaled envelope of a
+// transaction invoking the lifecycle system chaincode with the rwset
+func lifecycleEnvelope(t *testing.T, ns *rwsetutil.NsRwSet, endorserMSPIDs ...string) (*common.Payload, []byte) {
+	results, err := (&rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{ns}}).ToProtoBytes()
+	assert.NoError(t, err)
+	ccAction := &peer.ChaincodeAction{
+		Results:     results,
+		Response:    &peer.Response{Status: 200},
+		ChaincodeId: &peer.ChaincodeID{Name: "+lifecycle", Version: "1.0"},
+	}
+	prp := &peer.ProposalResponsePayload{
+		ProposalHash: []byte("proposal-hash"),
+		Extension:    utils.MarshalOrPanic(ccAction),
+	}
+	ccActionPayload := &peer.ChaincodeActionPayload{
+		ChaincodeProposalPayload: utils.MarshalOrPanic(&peer.ChaincodeProposalPayload{}),
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: utils.MarshalOrPanic(prp),
+			Endorsements:            endorsements(endorserMSPIDs...),
+		},
+	}
+	tx := &peer.Transaction{
+		Actions: []*peer.TransactionAction{
+			{Payload: utils.MarshalOrPanic(ccActionPayload)},
+		},
+	}
+	chdr := &common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		ChannelId: "foochain",
+		TxId:      "txid",
+		Extension: utils.MarshalOrPanic(&peer.ChaincodeHeaderExtension{
+			ChaincodeId: &peer.ChaincodeID{Name: "+lifecycle"},
+		}),
+	}
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader:   utils.MarshalOrPanic(chdr),
+			SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{}),
+		},
+		Data: utils.MarshalOrPanic(tx),
+	}
+	return payload, utils.MarshalOrPanic(&common.Envelope{Payload: utils.MarshalOrPanic(payload)})
+}
+
+func lifecycleNsRwSet(publicWrite bool, collections ...string) *rwsetutil.NsRwSet {
+	ns := &rwsetutil.NsRwSet{
+		NameSpace: "+lifecycle",
+		KvRwSet:   &kvrwset.KVRWSet{},
+	}
+	if publicWrite {
+		ns.KvRwSet.Writes = []*kvrwset.KVWrite{{Key: "definitions/mycc", Value: testDefinition}}
+	}
+	for _, collection := range collections {
+		ns.CollHashedRwSets = append(ns.CollHashedRwSets, &rwsetutil.CollHashedRwSet{
+			CollectionName: collection,
+			HashedRwSet: &kvrwset.HashedRWSet{
+				HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("key-hash"), ValueHash: []byte("value-hash")}},
+			},
+		})
+	}
+	return ns
+}
+
+func TestValidateLifecycleWritesImplicitCollections(t *testing.T) {
+	v, _ := newLifecycleTestValidator(nil)
+
+	err, code := v.validateLifecycleWrites(endorsedPayload("Org1MSP"), "foochain", lifecycleNsRwSet(false, "_implicit_org_Org1MSP"))
+	assert.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_VALID, code)
+
+	err, code = v.validateLifecycleWrites(endorsedPayload("Org2MSP"), "foochain", lifecycleNsRwSet(false, "_implicit_org_Org1MSP"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "implicit collection of org Org1MSP must be written by an endorser of the org")
+	assert.Equal(t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, code)
+
+	err, code = v.validateLifecycleWrites(endorsedPayload("Org1MSP"), "foochain", lifecycleNsRwSet(false, "mycollection"))
+	assert.EqualError(t, err, "transaction attempted to write to collection mycollection of namespace +lifecycle, which is not an implicit collection")
+	assert.Equal(t, peer.TxValidationCode_ILLEGAL_WRITESET, code)
+}
+
+func TestValidateLifecycleWritesDefaultPolicy(t *testing.T) {
+	// a majority of the three orgs of the channel is two orgs
+	v, _ := newLifecycleTestValidator(nil, "Org1MSP", "Org2MSP")
+
+	err, code := v.validateLifecycleWrites(endorsedPayload("Org1MSP", "Org2MSP"), "foochain", lifecycleNsRwSet(true))
+	assert.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_VALID, code)
+
+	// the endorsement of an org which did not approve the definition does not count
+	err, code = v.validateLifecycleWrites(endorsedPayload("Org1MSP", "Org3MSP"), "foochain", lifecycleNsRwSet(true))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "definition of chaincode mycc is not approved by endorsing orgs satisfying the lifecycle endorsement policy of the channel")
+	assert.Equal(t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, code)
+
+	// nor does the approval of an org which did not endorse the transaction
+	err, code = v.validateLifecycleWrites(endorsedPayload("Org1MSP"), "foochain", lifecycleNsRwSet(true))
+	assert.Error(t, err)
+	assert.Equal(t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, code)
+}
+
+func TestValidateLifecycleWritesChannelPolicy(t *testing.T) {
+	policy := &mockpolicies.Policy{}
+	pm := &mockpolicies.Manager{
+		PolicyMap: map[string]policies.Policy{
+			policies.ChannelApplicationLifecycleEndorsement: policy,
+		},
+	}
+	v, _ := newLifecycleTestValidator(pm, "Org1MSP")
+
+	err, code := v.validateLifecycleWrites(endorsedPayload("Org1MSP"), "foochain", lifecycleNsRwSet(true))
+	assert.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_VALID, code)
+
+	policy.Err = errors.New("policy-error")
+	err, code = v.validateLifecycleWrites(endorsedPayload("Org1MSP"), "foochain", lifecycleNsRwSet(true))
+	assert.EqualError(t, err, "definition of chaincode mycc is not approved by endorsing orgs satisfying the lifecycle endorsement policy of the channel: policy-error")
+	assert.Equal(t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, code)
+}
+
+func TestValidateLifecycleWritesIllegalPublicWrites(t *testing.T) {
+	v, _ := newLifecycleTestValidator(nil, "Org1MSP", "Org2MSP")
+	payload := endorsedPayload("Org1MSP", "Org2MSP")
+
+	ns := lifecycleNsRwSet(true)
+	ns.KvRwSet.Writes[0].Key = "mycc"
+	err, code := v.validateLifecycleWrites(payload, "foochain", ns)
+	assert.EqualError(t, err, "transaction attempted to write key mycc to the public state of namespace +lifecycle, which is not a chaincode definition")
+	assert.Equal(t, peer.TxValidationCode_ILLEGAL_WRITESET, code)
+
+	ns = lifecycleNsRwSet(true)
+	ns.KvRwSet.Writes[0] = &kvrwset.KVWrite{Key: "definitions/mycc", IsDelete: true}
+	err, code = v.validateLifecycleWrites(payload, "foochain", ns)
+	assert.EqualError(t, err, "transaction attempted to delete the definition of chaincode mycc")
+	assert.Equal(t, peer.TxValidationCode_ILLEGAL_WRITESET, code)
+
+	ns = lifecycleNsRwSet(true)
+	ns.KvRwSet.Writes[0].Value = []byte("garbage")
+	err, code = v.validateLifecycleWrites(payload, "foochain", ns)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid definition of chaincode mycc")
+	assert.Equal(t, peer.TxValidationCode_ILLEGAL_WRITESET, code)
+
+	ns = lifecycleNsRwSet(true)
+	ns.KvRwSet.MetadataWrites = []*kvrwset.KVMetadataWrite{{Key: "definitions/mycc"}}
+	err, code = v.validateLifecycleWrites(payload, "foochain", ns)
+	assert.EqualError(t, err, "transaction attempted to write metadata to the public state of namespace +lifecycle")
+	assert.Equal(t, peer.TxValidationCode_ILLEGAL_WRITESET, code)
+}
+
+func TestValidateLifecycleWritesNoWrites(t *testing.T) {
+	v, _ := newLifecycleTestValidator(nil)
+
+	// reads only, as by queries, are not checked
+	err, code := v.validateLifecycleWrites(&common.Payload{Data: []byte("garbage")}, "foochain", lifecycleNsRwSet(false))
+	assert.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_VALID, code)
+
+	err, code = v.validateLifecycleWrites(&common.Payload{Data: []byte("garbage")}, "foochain", lifecycleNsRwSet(true))
+	assert.Error(t, err)
+	assert.Equal(t, peer.TxValidationCode_BAD_PAYLOAD, code)
+}
+
+func TestVSCCValidateTxLifecycleApprovalCapability(t *testing.T) {
+	// the definition is endorsed by a single org and approved by none
+	v, capabilities := newLifecycleTestValidator(nil)
+	payload, envBytes := lifecycleEnvelope(t, lifecycleNsRwSet(true), "Org1MSP")
+
+	err, code := v.VSCCValidateTx(0, payload, envBytes, &common.Block{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "definition of chaincode mycc is not approved by endorsing orgs satisfying the lifecycle endorsement policy of the channel")
+	assert.Equal(t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, code)
+
+	// without the capability, the writes are only validated by the
+	// validation plugin like those of any other system chaincode
+	capabilities.LifecycleApprovalRv = false
+	err, code = v.VSCCValidateTx(0, payload, envBytes, &common.Block{})
+	assert.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_VALID, code)
+}
@@ -79,6 +79,20 @@ func (_m *Capabilities) KeyLevelEndorsement() bool {
 	return r0
 }
 
+// LifecycleApproval provides a mock function with given fields:
+func (_m *Capabilities) LifecycleApproval() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // MetadataLifecycle provides a mock function with given fields:
 func (_m *Capabilities) MetadataLifecycle() bool {
 	ret := _m.Called()
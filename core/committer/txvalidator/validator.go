@@ -673,6 +673,10 @@ func (ds *dynamicCapabilities) KeyLevelEndorsement() bool {
 	return ds.support.Capabilities().KeyLevelEndorsement()
 }
 
+func (ds *dynamicCapabilities) LifecycleApproval() bool {
+	return ds.support.Capabilities().LifecycleApproval()
+}
+
 func (ds *dynamicCapabilities) MetadataLifecycle() bool {
 	return ds.support.Capabilities().MetadataLifecycle()
 }
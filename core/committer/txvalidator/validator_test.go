@@ -1666,6 +1666,21 @@ func (m *mockLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	return args.Get(0).(bool), args.Error(1)
 }
 
+func (m *mockLedger) ExportSnapshot() (*ledger.SnapshotInfo, error) {
+	args := m.Called()
+	return args.Get(0).(*ledger.SnapshotInfo), args.Error(1)
+}
+
+func (m *mockLedger) SubmitSnapshotRequest(blockNum uint64) error {
+	args := m.Called(blockNum)
+	return args.Error(0)
+}
+
+func (m *mockLedger) PendingSnapshotRequests() ([]uint64, error) {
+	args := m.Called()
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
 func (m *mockLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
 	args := m.Called(blockNumber)
 	return args.Get(0).(*common.Block), nil
@@ -17,6 +17,7 @@ import (
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	coreUtil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
@@ -154,6 +155,16 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 		if !writesToNonInvokableSCC && v.sccprovider.IsSysCCAndNotInvokableExternal(ns.NameSpace) {
 			writesToNonInvokableSCC = true
 		}
+
+		// writes to the namespace of the lifecycle system chaincode, whether
+		// it was invoked directly or by an application chaincode, have to
+		// satisfy the approval policies of the chaincode lifecycle
+		if ns.NameSpace == privdata.LifecycleNamespace && v.support.Capabilities().LifecycleApproval() {
+			if err, code := v.validateLifecycleWrites(payload, chdr.ChannelId, ns); err != nil {
+				logger.Errorf("lifecycle validation for txId = %s failed: %+v", chdr.TxId, err)
+				return err, code
+			}
+		}
 	}
 
 	// we've gathered all the info required to proceed to validation;
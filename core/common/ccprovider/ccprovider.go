@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/golang/protobuf/proto"
@@ -515,6 +516,39 @@ type TransactionParams struct {
 
 	// this is additional data passed to the chaincode
 	ProposalDecorations map[string][]byte
+
+	// ResourceUsage collects the resources used by the chaincodes
+	// executed for the transaction, if set
+	ResourceUsage *ResourceUsage
+}
+
+// ResourceUsage collects the resources used by each chaincode
+// executed to simulate a transaction
+type ResourceUsage struct {
+	mutex sync.Mutex
+	usage []*pb.ChaincodeResourceUsage
+}
+
+// Record records the resources used by an execution of a chaincode.
+// It does nothing if the ResourceUsage is nil.
+func (r *ResourceUsage) Record(usage *pb.ChaincodeResourceUsage) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	r.usage = append(r.usage, usage)
+	r.mutex.Unlock()
+}
+
+// Usage returns the resources used by the chaincode executions recorded,
+// in the order in which the executions completed
+func (r *ResourceUsage) Usage() []*pb.ChaincodeResourceUsage {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*pb.ChaincodeResourceUsage(nil), r.usage...)
 }
 
 // ChaincodeProvider provides an abstraction layer that is
@@ -162,3 +162,18 @@ func setupDirectoryStructure(t *testing.T) (string, map[string][]byte) {
 
 	return tmp, hashes
 }
+
+func TestResourceUsage(t *testing.T) {
+	var nilUsage *ccprovider.ResourceUsage
+	nilUsage.Record(&peer.ChaincodeResourceUsage{Chaincode: "cc:1.0"})
+	assert.Nil(t, nilUsage.Usage())
+
+	usage := &ccprovider.ResourceUsage{}
+	assert.Empty(t, usage.Usage())
+	usage.Record(&peer.ChaincodeResourceUsage{Chaincode: "callee:1.0", StateReads: 2})
+	usage.Record(&peer.ChaincodeResourceUsage{Chaincode: "caller:1.0", Writes: 1, TotalBytes: 10})
+	assert.Equal(t, []*peer.ChaincodeResourceUsage{
+		{Chaincode: "callee:1.0", StateReads: 2},
+		{Chaincode: "caller:1.0", Writes: 1, TotalBytes: 10},
+	}, usage.Usage())
+}
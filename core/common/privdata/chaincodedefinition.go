@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// chaincodeDefinitionKeyPrefix prefixes the keys of the committed chaincode
+	// definitions in the public state of the lifecycle namespace
+	chaincodeDefinitionKeyPrefix = "definitions/"
+
+	// chaincodeApprovalKeyPrefix prefixes the keys of the approved chaincode
+	// definitions in the implicit collection of an organization
+	chaincodeApprovalKeyPrefix = "approvals/"
+)
+
+// ChaincodeDefinitionKey returns the key of the committed definition of the
+// chaincode in the public state of the lifecycle namespace
+func ChaincodeDefinitionKey(chaincodeName string) string {
+	return chaincodeDefinitionKeyPrefix + chaincodeName
+}
+
+// ChaincodeNameIfDefinitionKey returns the name of the chaincode whose committed
+// definition has the given key, and whether it is such a key
+func ChaincodeNameIfDefinitionKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, chaincodeDefinitionKeyPrefix) {
+		return "", false
+	}
+	chaincodeName := key[len(chaincodeDefinitionKeyPrefix):]
+	return chaincodeName, chaincodeName != ""
+}
+
+// ChaincodeApprovalKey returns the key of the approval of the definition of the
+// chaincode at the given sequence in the implicit collection of an organization
+func ChaincodeApprovalKey(chaincodeName string, sequence int64) string {
+	return fmt.Sprintf("%s%s#%d", chaincodeApprovalKeyPrefix, chaincodeName, sequence)
+}
@@ -0,0 +1,29 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaincodeDefinitionKeys(t *testing.T) {
+	key := ChaincodeDefinitionKey("mycc")
+	assert.Equal(t, "definitions/mycc", key)
+
+	name, ok := ChaincodeNameIfDefinitionKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, "mycc", name)
+
+	for _, key := range []string{"definitions/", "approvals/mycc#1", "mycc"} {
+		_, ok := ChaincodeNameIfDefinitionKey(key)
+		assert.False(t, ok, key)
+	}
+
+	assert.Equal(t, "approvals/mycc#3", ChaincodeApprovalKey("mycc", 3))
+}
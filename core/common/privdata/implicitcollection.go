@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// LifecycleNamespace is the namespace of the lifecycle system chaincode,
+// which has an implicit collection for each organization of the channel
+const LifecycleNamespace = "+lifecycle"
+
+const implicitCollectionNamePrefix = "_implicit_org_"
+
+// ImplicitCollectionNameForOrg returns the name of the implicit collection
+// of the organization with the given MSP ID
+func ImplicitCollectionNameForOrg(mspID string) string {
+	return implicitCollectionNamePrefix + mspID
+}
+
+// MSPIDIfImplicitCollection returns the MSP ID of the organization whose
+// implicit collection has the given name, and whether it is such a collection
+func MSPIDIfImplicitCollection(collectionName string) (string, bool) {
+	if !strings.HasPrefix(collectionName, implicitCollectionNamePrefix) {
+		return "", false
+	}
+	mspID := collectionName[len(implicitCollectionNamePrefix):]
+	return mspID, mspID != ""
+}
+
+// ImplicitCollectionConfig returns the configuration of the collection of the
+// namespace if it is an implicit collection, or nil otherwise. The members of
+// the implicit collection of an organization are the members of the organization,
+// and its data is neither disseminated at endorsement time nor purged.
+func ImplicitCollectionConfig(namespace, collectionName string) *common.StaticCollectionConfig {
+	if namespace != LifecycleNamespace {
+		return nil
+	}
+	mspID, ok := MSPIDIfImplicitCollection(collectionName)
+	if !ok {
+		return nil
+	}
+	return &common.StaticCollectionConfig{
+		Name: collectionName,
+		MemberOrgsPolicy: &common.CollectionPolicyConfig{
+			Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+				SignaturePolicy: cauthdsl.SignedByMspMember(mspID),
+			},
+		},
+		RequiredPeerCount: 0,
+		MaximumPeerCount:  0,
+		BlockToLive:       0,
+		MemberOnlyRead:    true,
+	}
+}
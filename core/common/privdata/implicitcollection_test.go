@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	lm "github.com/hyperledger/fabric/common/mocks/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplicitCollectionNames(t *testing.T) {
+	name := ImplicitCollectionNameForOrg("Org1MSP")
+	assert.Equal(t, "_implicit_org_Org1MSP", name)
+
+	mspID, ok := MSPIDIfImplicitCollection(name)
+	assert.True(t, ok)
+	assert.Equal(t, "Org1MSP", mspID)
+
+	for _, collectionName := range []string{"mycollection", "_implicit_org_", "implicit_org_Org1MSP"} {
+		_, ok := MSPIDIfImplicitCollection(collectionName)
+		assert.False(t, ok, collectionName)
+	}
+}
+
+func TestImplicitCollectionConfig(t *testing.T) {
+	conf := ImplicitCollectionConfig(LifecycleNamespace, "_implicit_org_Org1MSP")
+	require.NotNil(t, conf)
+	assert.Equal(t, "_implicit_org_Org1MSP", conf.Name)
+	assert.Equal(t, cauthdsl.SignedByMspMember("Org1MSP"), conf.MemberOrgsPolicy.GetSignaturePolicy())
+	assert.Zero(t, conf.RequiredPeerCount)
+	assert.Zero(t, conf.MaximumPeerCount)
+	assert.Zero(t, conf.BlockToLive)
+	assert.True(t, conf.MemberOnlyRead)
+
+	assert.Nil(t, ImplicitCollectionConfig("mycc", "_implicit_org_Org1MSP"))
+	assert.Nil(t, ImplicitCollectionConfig(LifecycleNamespace, "mycollection"))
+}
+
+func TestCollectionStoreImplicitCollection(t *testing.T) {
+	// There is no collection configuration in the state for implicit collections.
+	support := &mockStoreSupport{Qe: &lm.MockQueryExecutor{State: map[string]map[string][]byte{"lscc": {}}}}
+	cs := NewSimpleCollectionStore(support)
+
+	ccr := common.CollectionCriteria{Channel: "ch", Namespace: LifecycleNamespace, Collection: "_implicit_org_Org1MSP"}
+	c, err := cs.RetrieveCollection(ccr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Org1MSP"}, c.MemberOrgs())
+	assert.Equal(t, "_implicit_org_Org1MSP", c.CollectionID())
+
+	persistenceConfigs, err := cs.RetrieveCollectionPersistenceConfigs(ccr)
+	require.NoError(t, err)
+	assert.Zero(t, persistenceConfigs.BlockToLive())
+
+	ccr.Collection = "mycollection"
+	_, err = cs.RetrieveCollection(ccr)
+	assert.EqualError(t, err, "collection ch/+lifecycle/mycollection could not be found")
+}
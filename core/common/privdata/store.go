@@ -93,6 +93,9 @@ func ParseCollectionConfig(colBytes []byte) (*common.CollectionConfigPackage, er
 }
 
 func (c *simpleCollectionStore) retrieveCollectionConfig(cc common.CollectionCriteria, qe ledger.QueryExecutor) (*common.StaticCollectionConfig, error) {
+	if implicitCollection := ImplicitCollectionConfig(cc.Namespace, cc.Collection); implicitCollection != nil {
+		return implicitCollection, nil
+	}
 	collections, err := c.retrieveCollectionConfigPackage(cc, qe)
 	if err != nil {
 		return nil, err
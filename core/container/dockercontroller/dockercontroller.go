@@ -70,6 +70,9 @@ type dockerClient interface {
 	// BuildImage builds an image from a tarball's url or a Dockerfile in the input
 	// stream, returns an error in case of failure
 	BuildImage(opts docker.BuildImageOptions) error
+	// ListImages lists the docker images matching the options, returns an error
+	// in case of failure
+	ListImages(opts docker.ListImagesOptions) ([]docker.APIImages, error)
 	// RemoveImageExtended removes a docker image by its name or ID, returns an
 	// error in case of failure
 	RemoveImageExtended(id string, opts docker.RemoveImageOptions) error
@@ -417,6 +420,34 @@ func (vm *DockerVM) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// RemoveImage removes the image built for the chaincode. It succeeds
+// if no image was built for the chaincode.
+func (vm *DockerVM) RemoveImage(ccid ccintf.CCID) error {
+	client, err := vm.getClientFnc()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to Docker daemon")
+	}
+	imageName, err := vm.GetVMNameForDocker(ccid)
+	if err != nil {
+		return err
+	}
+
+	return removeImage(client, imageName)
+}
+
+func removeImage(client dockerClient, imageName string) error {
+	err := client.RemoveImageExtended(imageName, docker.RemoveImageOptions{})
+	if err == docker.ErrNoSuchImage {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove image %s", imageName)
+	}
+
+	dockerLogger.Infof("Removed image %s", imageName)
+	return nil
+}
+
 func (vm *DockerVM) stopInternal(client dockerClient, id string, timeout uint, dontkill, dontremove bool) error {
 	logger := dockerLogger.With("id", id)
 
@@ -436,6 +467,10 @@ func (vm *DockerVM) stopInternal(client dockerClient, id string, timeout uint, d
 		logger.Debugw("remove container result", "error", err)
 	}
 
+	// A container which does not exist is already stopped.
+	if _, ok := err.(*docker.NoSuchContainer); ok {
+		return nil
+	}
 	return err
 }
 
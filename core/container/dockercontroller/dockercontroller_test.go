@@ -281,6 +281,15 @@ func Test_Stop(t *testing.T) {
 	}
 	err = dvm.Stop(ccid, 10, true, true)
 	assert.NoError(t, err)
+
+	// A container which does not exist is already stopped
+	client.RemoveContainerReturns(&docker.NoSuchContainer{ID: "simple"})
+	err = dvm.Stop(ccid, 10, false, false)
+	assert.NoError(t, err)
+
+	client.RemoveContainerReturns(errors.New("remove failed"))
+	err = dvm.Stop(ccid, 10, false, false)
+	assert.EqualError(t, err, "remove failed")
 }
 
 func Test_Wait(t *testing.T) {
@@ -325,6 +334,39 @@ func Test_HealthCheck(t *testing.T) {
 	assert.Contains(t, err.Error(), "error pinging daemon")
 }
 
+func Test_RemoveImage(t *testing.T) {
+	dvm := DockerVM{PeerID: "peer0", NetworkID: "dev"}
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+	imageName, err := dvm.GetVMNameForDocker(ccid)
+	require.NoError(t, err)
+
+	// failure to get a client
+	dvm.getClientFnc = func() (dockerClient, error) {
+		return nil, errors.New("gorilla-goo")
+	}
+	err = dvm.RemoveImage(ccid)
+	assert.EqualError(t, err, "failed to connect to Docker daemon: gorilla-goo")
+
+	// happy path
+	client := &mock.DockerClient{}
+	dvm.getClientFnc = func() (dockerClient, error) { return client, nil }
+	err = dvm.RemoveImage(ccid)
+	assert.NoError(t, err)
+	require.Equal(t, 1, client.RemoveImageExtendedCallCount())
+	name, _ := client.RemoveImageExtendedArgsForCall(0)
+	assert.Equal(t, imageName, name)
+
+	// no image was built
+	client.RemoveImageExtendedReturns(docker.ErrNoSuchImage)
+	err = dvm.RemoveImage(ccid)
+	assert.NoError(t, err)
+
+	// removal fails
+	client.RemoveImageExtendedReturns(errors.New("image-in-use"))
+	err = dvm.RemoveImage(ccid)
+	assert.EqualError(t, err, "failed to remove image "+imageName+": image-in-use")
+}
+
 type testCase struct {
 	name           string
 	vm             *DockerVM
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dockercontroller
+
+import (
+	"strings"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hyperledger/fabric/common/metadata"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/pkg/errors"
+)
+
+// ImageCollector removes the chaincode images built by the peer for
+// chaincodes which are no longer installed on the peer.
+type ImageCollector struct {
+	VM *DockerVM
+	// Installed returns whether the chaincode is installed on the peer. It
+	// must fail when that cannot be determined, as the image is removed
+	// when the chaincode is reported as not installed.
+	Installed func(ccid ccintf.CCID) (bool, error)
+	// Interval is the time between collections
+	Interval time.Duration
+}
+
+// Run collects images every interval and never returns.
+func (ic *ImageCollector) Run() {
+	ticker := time.NewTicker(ic.Interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := ic.Collect(); err != nil {
+			dockerLogger.Warningf("Failed to collect chaincode images: %s", err)
+		}
+	}
+}
+
+// Collect removes the images built by the peer for chaincodes which are no
+// longer installed. Images built by other peers sharing the Docker daemon are
+// left alone. Images which cannot be removed, such as those of running
+// chaincode containers, are skipped.
+func (ic *ImageCollector) Collect() error {
+	client, err := ic.VM.getClientFnc()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to Docker daemon")
+	}
+	nameLabel := metadata.BaseDockerLabel + ".chaincode.id.name"
+	versionLabel := metadata.BaseDockerLabel + ".chaincode.id.version"
+	images, err := client.ListImages(docker.ListImagesOptions{
+		Filters: map[string][]string{"label": {nameLabel}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list chaincode images")
+	}
+
+	for _, image := range images {
+		ccid := ccintf.CCID{Name: image.Labels[nameLabel], Version: image.Labels[versionLabel]}
+		imageName, err := ic.VM.GetVMNameForDocker(ccid)
+		if err != nil || !hasRepository(image.RepoTags, imageName) {
+			continue
+		}
+		installed, err := ic.Installed(ccid)
+		if err != nil {
+			dockerLogger.Warningf("Failed to check whether chaincode %s is installed: %s", ccid.GetName(), err)
+			continue
+		}
+		if installed {
+			continue
+		}
+		if err := removeImage(client, imageName); err != nil {
+			dockerLogger.Warningf("Failed to collect image of chaincode %s: %s", ccid.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// hasRepository returns whether any of the tags is of the repository
+func hasRepository(repoTags []string, repository string) bool {
+	for _, repoTag := range repoTags {
+		if strings.HasPrefix(repoTag, repository+":") {
+			return true
+		}
+	}
+	return false
+}
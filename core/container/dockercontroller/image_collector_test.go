@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dockercontroller
+
+import (
+	"errors"
+	"testing"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/dockercontroller/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chaincodeImage(t *testing.T, vm *DockerVM, name, version string) docker.APIImages {
+	imageName, err := vm.GetVMNameForDocker(ccintf.CCID{Name: name, Version: version})
+	require.NoError(t, err)
+	return docker.APIImages{
+		RepoTags: []string{imageName + ":latest"},
+		Labels: map[string]string{
+			"org.hyperledger.fabric.chaincode.id.name":    name,
+			"org.hyperledger.fabric.chaincode.id.version": version,
+		},
+	}
+}
+
+func TestImageCollectorCollect(t *testing.T) {
+	client := &mock.DockerClient{}
+	vm := &DockerVM{PeerID: "peer0", NetworkID: "dev"}
+	vm.getClientFnc = func() (dockerClient, error) { return client, nil }
+	otherPeer := &DockerVM{PeerID: "peer1", NetworkID: "dev"}
+
+	installed := chaincodeImage(t, vm, "mycc", "1.0")
+	uninstalled := chaincodeImage(t, vm, "mycc", "0.9")
+	inUse := chaincodeImage(t, vm, "othercc", "1.0")
+	unknown := chaincodeImage(t, vm, "unknowncc", "1.0")
+	client.ListImagesReturns([]docker.APIImages{
+		installed,
+		uninstalled,
+		inUse,
+		unknown,
+		chaincodeImage(t, otherPeer, "mycc", "0.9"),
+	}, nil)
+	client.RemoveImageExtendedStub = func(name string, opts docker.RemoveImageOptions) error {
+		if name+":latest" == inUse.RepoTags[0] {
+			return errors.New("image-in-use")
+		}
+		return nil
+	}
+
+	ic := &ImageCollector{
+		VM: vm,
+		Installed: func(ccid ccintf.CCID) (bool, error) {
+			if ccid.Name == "unknowncc" {
+				return false, errors.New("unreadable-store")
+			}
+			return ccid == ccintf.CCID{Name: "mycc", Version: "1.0"}, nil
+		},
+	}
+	err := ic.Collect()
+	assert.NoError(t, err)
+
+	require.Equal(t, 1, client.ListImagesCallCount())
+	opts := client.ListImagesArgsForCall(0)
+	assert.Equal(t, map[string][]string{"label": {"org.hyperledger.fabric.chaincode.id.name"}}, opts.Filters)
+
+	// the images of the installed chaincode, of the chaincode which may be
+	// installed, and of the other peer are kept
+	require.Equal(t, 2, client.RemoveImageExtendedCallCount())
+	name, _ := client.RemoveImageExtendedArgsForCall(0)
+	assert.Equal(t, uninstalled.RepoTags[0], name+":latest")
+	name, _ = client.RemoveImageExtendedArgsForCall(1)
+	assert.Equal(t, inUse.RepoTags[0], name+":latest")
+}
+
+func TestImageCollectorCollectFailures(t *testing.T) {
+	client := &mock.DockerClient{}
+	vm := &DockerVM{}
+	vm.getClientFnc = func() (dockerClient, error) { return client, nil }
+	ic := &ImageCollector{VM: vm}
+
+	client.ListImagesReturns(nil, errors.New("docker-unavailable"))
+	err := ic.Collect()
+	assert.EqualError(t, err, "failed to list chaincode images: docker-unavailable")
+
+	vm.getClientFnc = func() (dockerClient, error) { return nil, errors.New("gorilla-goo") }
+	err = ic.Collect()
+	assert.EqualError(t, err, "failed to connect to Docker daemon: gorilla-goo")
+}
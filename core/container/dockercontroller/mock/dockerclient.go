@@ -55,6 +55,19 @@ type DockerClient struct {
 	killContainerReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ListImagesStub        func(docker.ListImagesOptions) ([]docker.APIImages, error)
+	listImagesMutex       sync.RWMutex
+	listImagesArgsForCall []struct {
+		arg1 docker.ListImagesOptions
+	}
+	listImagesReturns struct {
+		result1 []docker.APIImages
+		result2 error
+	}
+	listImagesReturnsOnCall map[int]struct {
+		result1 []docker.APIImages
+		result2 error
+	}
 	PingWithContextStub        func(context.Context) error
 	pingWithContextMutex       sync.RWMutex
 	pingWithContextArgsForCall []struct {
@@ -385,6 +398,69 @@ func (fake *DockerClient) KillContainerReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *DockerClient) ListImages(arg1 docker.ListImagesOptions) ([]docker.APIImages, error) {
+	fake.listImagesMutex.Lock()
+	ret, specificReturn := fake.listImagesReturnsOnCall[len(fake.listImagesArgsForCall)]
+	fake.listImagesArgsForCall = append(fake.listImagesArgsForCall, struct {
+		arg1 docker.ListImagesOptions
+	}{arg1})
+	fake.recordInvocation("ListImages", []interface{}{arg1})
+	fake.listImagesMutex.Unlock()
+	if fake.ListImagesStub != nil {
+		return fake.ListImagesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listImagesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DockerClient) ListImagesCallCount() int {
+	fake.listImagesMutex.RLock()
+	defer fake.listImagesMutex.RUnlock()
+	return len(fake.listImagesArgsForCall)
+}
+
+func (fake *DockerClient) ListImagesCalls(stub func(docker.ListImagesOptions) ([]docker.APIImages, error)) {
+	fake.listImagesMutex.Lock()
+	defer fake.listImagesMutex.Unlock()
+	fake.ListImagesStub = stub
+}
+
+func (fake *DockerClient) ListImagesArgsForCall(i int) docker.ListImagesOptions {
+	fake.listImagesMutex.RLock()
+	defer fake.listImagesMutex.RUnlock()
+	argsForCall := fake.listImagesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DockerClient) ListImagesReturns(result1 []docker.APIImages, result2 error) {
+	fake.listImagesMutex.Lock()
+	defer fake.listImagesMutex.Unlock()
+	fake.ListImagesStub = nil
+	fake.listImagesReturns = struct {
+		result1 []docker.APIImages
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DockerClient) ListImagesReturnsOnCall(i int, result1 []docker.APIImages, result2 error) {
+	fake.listImagesMutex.Lock()
+	defer fake.listImagesMutex.Unlock()
+	fake.ListImagesStub = nil
+	if fake.listImagesReturnsOnCall == nil {
+		fake.listImagesReturnsOnCall = make(map[int]struct {
+			result1 []docker.APIImages
+			result2 error
+		})
+	}
+	fake.listImagesReturnsOnCall[i] = struct {
+		result1 []docker.APIImages
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *DockerClient) PingWithContext(arg1 context.Context) error {
 	fake.pingWithContextMutex.Lock()
 	ret, specificReturn := fake.pingWithContextReturnsOnCall[len(fake.pingWithContextArgsForCall)]
@@ -823,6 +899,8 @@ func (fake *DockerClient) Invocations() map[string][][]interface{} {
 	defer fake.createContainerMutex.RUnlock()
 	fake.killContainerMutex.RLock()
 	defer fake.killContainerMutex.RUnlock()
+	fake.listImagesMutex.RLock()
+	defer fake.listImagesMutex.RUnlock()
 	fake.pingWithContextMutex.RLock()
 	defer fake.pingWithContextMutex.RUnlock()
 	fake.removeContainerMutex.RLock()
@@ -0,0 +1,426 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/pkg/errors"
+)
+
+var (
+	logger   = flogging.MustGetLogger("externalbuilder")
+	vmRegExp = regexp.MustCompile("[^a-zA-Z0-9-_.]")
+)
+
+// Builder is an operator configured external builder. The directory at Path
+// must contain the executables bin/detect, bin/build and bin/launch.
+type Builder struct {
+	Name string `mapstructure:"name" yaml:"name"`
+	Path string `mapstructure:"path" yaml:"path"`
+}
+
+// BuildMetadata describes the chaincode package handed to the external
+// builders. It is written as metadata.json to the metadata directory.
+type BuildMetadata struct {
+	Type    string `json:"type"`
+	Path    string `json:"path"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Provider implements container.VMProvider. The chaincode processes started by
+// the VMs it creates are tracked by the provider, so that a chaincode started
+// by one VM may be stopped or waited upon by another.
+type Provider struct {
+	// Builders are tried in order, the first one whose detect succeeds
+	// builds and launches the chaincode
+	Builders []*Builder
+	// WorkDir is the directory holding the build output and the run
+	// directories of the chaincodes
+	WorkDir string
+	// Timeout, if not zero, bounds the time the detect and build executables
+	// of the builders may run for, after which they are killed
+	Timeout time.Duration
+	// Fallback, if set, provides the VMs for the chaincodes which none of
+	// the builders detect
+	Fallback container.VMProvider
+
+	mutex     sync.Mutex
+	processes map[string]*process
+}
+
+// NewProvider creates a new instance of Provider
+func NewProvider(workDir string, builders []*Builder, timeout time.Duration, fallback container.VMProvider) *Provider {
+	return &Provider{
+		Builders:  builders,
+		WorkDir:   workDir,
+		Timeout:   timeout,
+		Fallback:  fallback,
+		processes: map[string]*process{},
+	}
+}
+
+// NewVM creates a new ExternalVM instance
+func (p *Provider) NewVM() container.VM {
+	vm := &ExternalVM{Provider: p}
+	if p.Fallback != nil {
+		vm.fallback = p.Fallback.NewVM()
+	}
+	return vm
+}
+
+func (p *Provider) process(name string) *process {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.processes[name]
+}
+
+func (p *Provider) setProcess(name string, proc *process) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if proc == nil {
+		delete(p.processes, name)
+		return
+	}
+	p.processes[name] = proc
+}
+
+// process is a chaincode running as a child process of the peer
+type process struct {
+	cmd      *exec.Cmd
+	output   *io.PipeWriter
+	runDir   string
+	done     chan struct{}
+	exitCode int
+	err      error
+}
+
+func (p *process) wait() {
+	err := p.cmd.Wait()
+	p.output.Close()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		p.exitCode = exitErr.ExitCode()
+		err = nil
+	}
+	p.err = err
+	close(p.done)
+}
+
+// ExternalVM is a vm which builds chaincode with external builders and runs
+// it as a local process
+type ExternalVM struct {
+	Provider *Provider
+	fallback container.VM
+}
+
+// Start builds the chaincode with the first of the builders which detects the
+// chaincode package, and runs the launch executable of that builder as a child
+// process. The launch executable is invoked with the build output directory and
+// the run directory, followed by the arguments the chaincode would be started
+// with in a container. The files to upload are written to the run directory,
+// and environment variables referring to them are rewritten to point there.
+func (vm *ExternalVM) Start(ccid ccintf.CCID, args, env []string, filesToUpload map[string][]byte, builder container.Builder) error {
+	name := vm.GetVMName(ccid)
+
+	pb, ok := builder.(*container.PlatformBuilder)
+	if !ok {
+		if vm.fallback != nil {
+			return vm.fallback.Start(ccid, args, env, filesToUpload, builder)
+		}
+		return errors.Errorf("chaincode %s does not provide a chaincode package to build", name)
+	}
+
+	vm.stopInternal(name, 0, false, false)
+
+	buildDir := filepath.Join(vm.Provider.WorkDir, name, "build")
+	if err := os.RemoveAll(buildDir); err != nil {
+		return errors.Wrapf(err, "failed to clean build directory of %s", name)
+	}
+	sourceDir, metadataDir, outputDir := filepath.Join(buildDir, "src"), filepath.Join(buildDir, "metadata"), filepath.Join(buildDir, "bld")
+	for _, dir := range []string{sourceDir, metadataDir, outputDir} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return errors.Wrapf(err, "failed to create build directory of %s", name)
+		}
+	}
+
+	if err := extractCodePackage(pb.CodePackage, sourceDir); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to extract chaincode package of %s", name))
+	}
+	metadata, err := json.Marshal(&BuildMetadata{Type: pb.Type, Path: pb.Path, Name: pb.Name, Version: pb.Version})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal build metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Join(metadataDir, "metadata.json"), metadata, 0640); err != nil {
+		return errors.Wrapf(err, "failed to write build metadata of %s", name)
+	}
+
+	b := vm.detect(name, sourceDir, metadataDir)
+	if b == nil {
+		if vm.fallback != nil {
+			logger.Debugf("No external builder detected %s, falling back", name)
+			return vm.fallback.Start(ccid, args, env, filesToUpload, builder)
+		}
+		return errors.Errorf("no external builder detected chaincode %s", name)
+	}
+
+	if err := vm.runBuilder(name, b, "build", sourceDir, metadataDir, outputDir); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("external builder %s failed to build %s", b.Name, name))
+	}
+
+	runDir := filepath.Join(vm.Provider.WorkDir, name, "run")
+	if err := os.RemoveAll(runDir); err != nil {
+		return errors.Wrapf(err, "failed to clean run directory of %s", name)
+	}
+	if err := os.MkdirAll(runDir, 0750); err != nil {
+		return errors.Wrapf(err, "failed to create run directory of %s", name)
+	}
+
+	// write the files, such as the TLS key and certificates, to the run
+	// directory instead of the paths they would have in a container
+	replacer, err := writeFiles(runDir, filesToUpload)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to write files of %s", name))
+	}
+	procEnv := make([]string, 0, len(env))
+	for _, e := range env {
+		procEnv = append(procEnv, replacer.Replace(e))
+	}
+
+	cmd := exec.Command(filepath.Join(b.Path, "bin", "launch"), append([]string{outputDir, runDir}, args...)...)
+	cmd.Env = procEnv
+	cmd.Dir = runDir
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	if err := cmd.Start(); err != nil {
+		w.Close()
+		return errors.Wrapf(err, "external builder %s failed to launch %s", b.Name, name)
+	}
+
+	// stream stdout and stderr to chaincode logger
+	go streamOutput(name, r, flogging.MustGetLogger("peer.chaincode."+name))
+
+	proc := &process{cmd: cmd, output: w, runDir: runDir, done: make(chan struct{})}
+	go proc.wait()
+	vm.Provider.setProcess(name, proc)
+
+	logger.Debugf("Started chaincode %s with external builder %s, pid %d", name, b.Name, cmd.Process.Pid)
+	return nil
+}
+
+// detect returns the first of the builders whose detect executable succeeds
+func (vm *ExternalVM) detect(name, sourceDir, metadataDir string) *Builder {
+	for _, b := range vm.Provider.Builders {
+		if err := vm.runBuilder(name, b, "detect", sourceDir, metadataDir); err != nil {
+			logger.Debugf("External builder %s did not detect %s: %s", b.Name, name, err)
+			continue
+		}
+		return b
+	}
+	return nil
+}
+
+// runBuilder runs an executable of the builder to completion, or until the
+// timeout of the provider expires, logging its output
+func (vm *ExternalVM) runBuilder(name string, b *Builder, executable string, args ...string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if vm.Provider.Timeout != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), vm.Provider.Timeout)
+	}
+	defer cancel()
+
+	// the output is written to a file rather than a pipe, so that waiting for
+	// a killed executable does not block on the processes it has spawned
+	output, err := ioutil.TempFile("", "externalbuilder-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create output file")
+	}
+	defer os.Remove(output.Name())
+	defer output.Close()
+
+	cmd := exec.CommandContext(ctx, filepath.Join(b.Path, "bin", executable), args...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = errors.Errorf("%s did not complete within %s", executable, vm.Provider.Timeout)
+	}
+
+	if out, readErr := ioutil.ReadFile(output.Name()); readErr == nil && len(out) != 0 {
+		logger.Debugf("%s %s of %s: %s", b.Name, executable, name, out)
+	}
+	return err
+}
+
+// Stop stops a running chaincode. The chaincode is terminated and, if it does
+// not exit within the timeout and dontkill is not set, killed. Unless dontremove
+// is set, its run directory is removed.
+func (vm *ExternalVM) Stop(ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
+	name := vm.GetVMName(ccid)
+	if vm.Provider.process(name) == nil && vm.fallback != nil {
+		return vm.fallback.Stop(ccid, timeout, dontkill, dontremove)
+	}
+
+	return vm.stopInternal(name, timeout, dontkill, dontremove)
+}
+
+func (vm *ExternalVM) stopInternal(name string, timeout uint, dontkill, dontremove bool) error {
+	proc := vm.Provider.process(name)
+	if proc == nil {
+		return nil
+	}
+
+	logger.Debugf("stopping chaincode %s", name)
+	err := proc.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-proc.done:
+	case <-time.After(time.Duration(timeout) * time.Second):
+		if !dontkill {
+			logger.Debugf("killing chaincode %s", name)
+			err = proc.cmd.Process.Kill()
+			<-proc.done
+		}
+	}
+	if err == os.ErrProcessDone {
+		err = nil
+	}
+	logger.Debugf("stop chaincode %s result: %v", name, err)
+
+	if !dontremove {
+		vm.Provider.setProcess(name, nil)
+		if rmErr := os.RemoveAll(proc.runDir); rmErr != nil {
+			logger.Debugf("remove run directory of %s result: %s", name, rmErr)
+		}
+	}
+
+	return err
+}
+
+// Wait blocks until the chaincode process exits and returns its exit code
+func (vm *ExternalVM) Wait(ccid ccintf.CCID) (int, error) {
+	name := vm.GetVMName(ccid)
+	proc := vm.Provider.process(name)
+	if proc == nil {
+		if vm.fallback != nil {
+			return vm.fallback.Wait(ccid)
+		}
+		return 0, errors.Errorf("chaincode %s is not running", name)
+	}
+
+	<-proc.done
+	return proc.exitCode, proc.err
+}
+
+// HealthCheck checks the health of the fallback VM, if any. Chaincode run by
+// the external builders has no daemon to check.
+func (vm *ExternalVM) HealthCheck(ctx context.Context) error {
+	if vm.fallback != nil {
+		return vm.fallback.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// GetVMName returns the name of the directory, below the work directory, of the
+// chaincode
+func (vm *ExternalVM) GetVMName(ccid ccintf.CCID) string {
+	return vmRegExp.ReplaceAllString(ccid.GetName(), "-")
+}
+
+// streamOutput mirrors the output of the chaincode process to a fabric logger
+func streamOutput(name string, r io.Reader, ccLogger *flogging.FabricLogger) {
+	is := bufio.NewReader(r)
+	for {
+		line, err := is.ReadString('\n')
+		switch err {
+		case nil:
+			ccLogger.Info(line)
+		case io.EOF:
+			logger.Infof("Chaincode %s has closed its IO channel", name)
+			return
+		default:
+			logger.Errorf("Error reading chaincode output: %s", err)
+			return
+		}
+	}
+}
+
+// writeFiles writes the files to the directory under their base names, and
+// returns a replacer of their original paths with the written ones
+func writeFiles(dir string, files map[string][]byte) (*strings.Replacer, error) {
+	var oldnew []string
+	for path, contents := range files {
+		target := filepath.Join(dir, filepath.Base(path))
+		if err := ioutil.WriteFile(target, contents, 0600); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s", target)
+		}
+		oldnew = append(oldnew, path, target)
+	}
+	return strings.NewReplacer(oldnew...), nil
+}
+
+// extractCodePackage extracts the gzipped tar of the chaincode package to dir
+func extractCodePackage(codePackage []byte, dir string) error {
+	gr, err := gzip.NewReader(bytes.NewReader(codePackage))
+	if err != nil {
+		return errors.Wrap(err, "failed to open chaincode package")
+	}
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read chaincode package")
+		}
+
+		target := filepath.Join(dir, filepath.Clean("/"+header.Name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return errors.Wrapf(err, "failed to create %s", target)
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+				return errors.Wrapf(err, "failed to create %s", filepath.Dir(target))
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm()|0600)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s", target)
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return errors.Wrapf(err, "failed to write %s", target)
+			}
+		default:
+			return errors.Errorf("chaincode package entry %s has unsupported type %d", header.Name, header.Typeflag)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func codePackage(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, contents := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func platformBuilder(t *testing.T, ccType string) *container.PlatformBuilder {
+	return &container.PlatformBuilder{
+		Type:        ccType,
+		Path:        "github.com/mycc",
+		Name:        "mycc",
+		Version:     "1.0",
+		CodePackage: codePackage(t, map[string]string{"src/github.com/mycc/main.go": "package main"}),
+	}
+}
+
+func newTestProvider(t *testing.T, fallback container.VMProvider) (*Provider, func()) {
+	workDir, err := ioutil.TempDir("", "externalbuilder")
+	require.NoError(t, err)
+
+	builders := []*Builder{
+		{Name: "fail", Path: "testdata/failbuilder"},
+		{Name: "slow", Path: "testdata/slowbuilder"},
+		{Name: "good", Path: "testdata/goodbuilder"},
+	}
+	for _, b := range builders {
+		b.Path, err = filepath.Abs(b.Path)
+		require.NoError(t, err)
+	}
+
+	return NewProvider(workDir, builders, time.Second, fallback), func() { os.RemoveAll(workDir) }
+}
+
+func TestStartWait(t *testing.T) {
+	p, cleanup := newTestProvider(t, nil)
+	defer cleanup()
+	vm := p.NewVM()
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+
+	files := map[string][]byte{"/etc/hyperledger/fabric/client.crt": []byte("cert")}
+	env := []string{"EXIT_CODE=3", "CORE_TLS_CLIENT_CERT_PATH=/etc/hyperledger/fabric/client.crt"}
+	err := vm.Start(ccid, []string{"chaincode", "-peer.address=peer:7052"}, env, files, platformBuilder(t, "GOLANG"))
+	require.NoError(t, err)
+
+	exitCode, err := vm.Wait(ccid)
+	require.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+
+	runDir := filepath.Join(p.WorkDir, "mycc-1.0", "run")
+	args, err := ioutil.ReadFile(filepath.Join(runDir, "args"))
+	require.NoError(t, err)
+	assert.Equal(t, "chaincode -peer.address=peer:7052\n", string(args))
+
+	cert, err := ioutil.ReadFile(filepath.Join(runDir, "client.crt"))
+	require.NoError(t, err)
+	assert.Equal(t, "cert", string(cert))
+	procEnv, err := ioutil.ReadFile(filepath.Join(runDir, "env"))
+	require.NoError(t, err)
+	assert.Contains(t, string(procEnv), "CORE_TLS_CLIENT_CERT_PATH="+filepath.Join(runDir, "client.crt"))
+
+	output, err := ioutil.ReadFile(filepath.Join(runDir, "output"))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "main.go")
+
+	err = vm.Stop(ccid, 0, false, false)
+	assert.NoError(t, err)
+	_, err = os.Stat(runDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStartStop(t *testing.T) {
+	p, cleanup := newTestProvider(t, nil)
+	defer cleanup()
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+
+	err := p.NewVM().Start(ccid, nil, nil, nil, platformBuilder(t, "GOLANG"))
+	require.NoError(t, err)
+
+	// the process is tracked by the provider across VMs
+	exited := make(chan int, 1)
+	go func() {
+		exitCode, _ := p.NewVM().Wait(ccid)
+		exited <- exitCode
+	}()
+
+	err = p.NewVM().Stop(ccid, 5, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, -1, <-exited)
+
+	_, err = p.NewVM().Wait(ccid)
+	assert.EqualError(t, err, "chaincode mycc-1.0 is not running")
+}
+
+func TestStartNotDetected(t *testing.T) {
+	p, cleanup := newTestProvider(t, nil)
+	defer cleanup()
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+
+	err := p.NewVM().Start(ccid, nil, nil, nil, platformBuilder(t, "NODE"))
+	assert.EqualError(t, err, "no external builder detected chaincode mycc-1.0")
+
+	err = p.NewVM().Start(ccid, nil, nil, nil, &mock.Builder{})
+	assert.EqualError(t, err, "chaincode mycc-1.0 does not provide a chaincode package to build")
+
+	pb := platformBuilder(t, "GOLANG")
+	pb.CodePackage = []byte("garbage")
+	err = p.NewVM().Start(ccid, nil, nil, nil, pb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to extract chaincode package of mycc-1.0")
+}
+
+func TestBuilderTimeout(t *testing.T) {
+	p, cleanup := newTestProvider(t, nil)
+	defer cleanup()
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+
+	// the detect of the slow builder is killed, and the next builder is tried
+	start := time.Now()
+	err := p.NewVM().Start(ccid, nil, nil, nil, platformBuilder(t, "GOLANG"))
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	require.NoError(t, p.NewVM().Stop(ccid, 5, false, false))
+
+	start = time.Now()
+	err = p.NewVM().Start(ccid, nil, nil, nil, platformBuilder(t, "SLOW"))
+	assert.EqualError(t, err, "external builder slow failed to build mycc-1.0: build did not complete within 1s")
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestFallback(t *testing.T) {
+	fallbackVM := &mock.VM{}
+	fallbackVM.WaitReturns(7, nil)
+	fallbackVM.HealthCheckReturns(errors.New("docker-unavailable"))
+	fallback := &mock.VMProvider{}
+	fallback.NewVMReturns(fallbackVM)
+
+	p, cleanup := newTestProvider(t, fallback)
+	defer cleanup()
+	vm := p.NewVM()
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+
+	err := vm.Start(ccid, []string{"arg"}, nil, nil, platformBuilder(t, "NODE"))
+	require.NoError(t, err)
+	require.Equal(t, 1, fallbackVM.StartCallCount())
+	startedCCID, args, _, _, _ := fallbackVM.StartArgsForCall(0)
+	assert.Equal(t, ccid, startedCCID)
+	assert.Equal(t, []string{"arg"}, args)
+
+	exitCode, err := vm.Wait(ccid)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, exitCode)
+
+	err = vm.Stop(ccid, 0, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fallbackVM.StopCallCount())
+
+	assert.EqualError(t, vm.HealthCheck(nil), "docker-unavailable")
+}
+
+func TestExtractCodePackagePathTraversal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = extractCodePackage(codePackage(t, map[string]string{"../../escaped": "contents"}), filepath.Join(dir, "src"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "src", "escaped"))
+	assert.NoError(t, err)
+}
@@ -488,6 +488,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		Proposal:             prop,
 		TXSimulator:          txsim,
 		HistoryQueryExecutor: historyQueryExecutor,
+		ResourceUsage:        &ccprovider.ResourceUsage{},
 	}
 	// this could be a request to a chainless SysCC
 
@@ -499,7 +500,10 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// 1 -- simulate
 	cd, res, simulationResult, ccevent, err := e.SimulateProposal(txParams, hdrExt.ChaincodeId)
 	if err != nil {
-		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
+		return &pb.ProposalResponse{
+			Response:      &pb.Response{Status: 500, Message: err.Error()},
+			ResourceUsage: txParams.ResourceUsage.Usage(),
+		}, nil
 	}
 	if res != nil {
 		if res.Status >= shim.ERROR {
@@ -515,6 +519,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 			if err != nil {
 				return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
 			}
+			pResp.ResourceUsage = txParams.ResourceUsage.Usage()
 
 			return pResp, nil
 		}
@@ -548,6 +553,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 			meterLabels = append(meterLabels, "chaincodeerror", strconv.FormatBool(true))
 			e.Metrics.EndorsementsFailed.With(meterLabels...).Add(1)
 			endorserLogger.Debugf("[%s][%s] endorseProposal() resulted in chaincode %s error for txid: %s", chainID, shorttxid(txid), hdrExt.ChaincodeId, txid)
+			pResp.ResourceUsage = txParams.ResourceUsage.Usage()
 			return pResp, nil
 		}
 	}
@@ -556,6 +562,9 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// contains the "return value" from the
 	// chaincode invocation
 	pResp.Response = res
+	// the resources used by the chaincodes are reported
+	// to the client, without being endorsed
+	pResp.ResourceUsage = txParams.ResourceUsage.Usage()
 
 	// total failed proposals = ProposalsReceived-SuccessfulProposals
 	e.Metrics.SuccessfulProposals.Add(1)
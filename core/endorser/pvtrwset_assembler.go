@@ -53,6 +53,15 @@ func (as *rwSetAssembler) AssemblePvtRWSet(privData *rwset.TxPvtReadWriteSet, tx
 	for _, pvtRwset := range privData.NsPvtRwset {
 		namespace := pvtRwset.Namespace
 		if _, found := txPvtRwSetWithConfig.CollectionConfigs[namespace]; !found {
+			if namespace == privdata.LifecycleNamespace {
+				colCP, err := implicitCollectionConfigs(pvtRwset)
+				if err != nil {
+					return nil, err
+				}
+				txPvtRwSetWithConfig.CollectionConfigs[namespace] = colCP
+				continue
+			}
+
 			cb, err := txsim.GetState("lscc", privdata.BuildCollectionKVSKey(namespace))
 			if err != nil {
 				return nil, errors.WithMessage(err, fmt.Sprintf("error while retrieving collection config for chaincode %#v", namespace))
@@ -74,6 +83,23 @@ func (as *rwSetAssembler) AssemblePvtRWSet(privData *rwset.TxPvtReadWriteSet, tx
 	return txPvtRwSetWithConfig, nil
 }
 
+// implicitCollectionConfigs returns the configs of the collections of the
+// lifecycle namespace, which are the implicit collections of the organizations
+// and are not stored in the state
+func implicitCollectionConfigs(pvtRwset *rwset.NsPvtReadWriteSet) (*common.CollectionConfigPackage, error) {
+	colCP := &common.CollectionConfigPackage{}
+	for _, col := range pvtRwset.CollectionPvtRwset {
+		conf := privdata.ImplicitCollectionConfig(pvtRwset.Namespace, col.CollectionName)
+		if conf == nil {
+			return nil, errors.Errorf("no collection config for collection %#v of chaincode %#v", col.CollectionName, pvtRwset.Namespace)
+		}
+		colCP.Config = append(colCP.Config, &common.CollectionConfig{
+			Payload: &common.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: conf},
+		})
+	}
+	return colCP, nil
+}
+
 func (as *rwSetAssembler) trimCollectionConfigs(pvtData *transientstore.TxPvtReadWriteSetWithConfigInfo) {
 	flags := make(map[string]map[string]struct{})
 	for _, pvtRWset := range pvtData.PvtRwset.NsPvtRwset {
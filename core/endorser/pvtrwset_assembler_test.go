@@ -88,3 +88,36 @@ func TestAssemblePvtRWSet(t *testing.T) {
 	assert.Equal(t, 1, len(pvtReadWriteSetWithConfigInfo.PvtRwset.NsPvtRwset))
 
 }
+
+func TestAssemblePvtRWSetImplicitCollections(t *testing.T) {
+	// The collections of the lifecycle namespace are not stored in lscc.
+	configRetriever := &mockCollectionConfigRetriever{}
+	assembler := rwSetAssembler{}
+
+	privData := &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{
+				Namespace: "+lifecycle",
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+					{
+						CollectionName: "_implicit_org_Org1MSP",
+						Rwset:          []byte{1, 2, 3, 4, 5, 6, 7, 8},
+					},
+				},
+			},
+		},
+	}
+
+	pvtReadWriteSetWithConfigInfo, err := assembler.AssemblePvtRWSet(privData, configRetriever)
+	assert.NoError(t, err)
+	configs, found := pvtReadWriteSetWithConfigInfo.CollectionConfigs["+lifecycle"]
+	assert.True(t, found)
+	assert.Equal(t, 1, len(configs.Config))
+	assert.Equal(t, privdata.ImplicitCollectionConfig("+lifecycle", "_implicit_org_Org1MSP"), configs.Config[0].GetStaticCollectionConfig())
+	configRetriever.AssertNotCalled(t, "GetState", mock.Anything, mock.Anything)
+
+	privData.NsPvtRwset[0].CollectionPvtRwset[0].CollectionName = "mycollection"
+	_, err = assembler.AssemblePvtRWSet(privData, configRetriever)
+	assert.EqualError(t, err, `no collection config for collection "mycollection" of chaincode "+lifecycle"`)
+}
@@ -58,4 +58,8 @@ type Capabilities interface {
 
 	// FabToken returns true if fabric token function is supported.
 	FabToken() bool
+
+	// LifecycleApproval returns true if the organizations of the channel may approve
+	// chaincode definitions, which are committed through the lifecycle system chaincode
+	LifecycleApproval() bool
 }
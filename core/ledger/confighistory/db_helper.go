@@ -95,6 +95,20 @@ func (d *db) entryAt(blockNum uint64, ns, key string) (*compositeKV, error) {
 	return &compositeKV{k, v}, nil
 }
 
+// iterateAllEntries invokes the function `handle` for each of the entries in the db
+func (d *db) iterateAllEntries(handle func(*compositeKV) error) error {
+	itr := d.GetIterator([]byte(keyPrefix), []byte{keyPrefix[0] + 1})
+	defer itr.Release()
+	for itr.Next() {
+		k := decodeCompositeKey(itr.Key())
+		v := append([]byte(nil), itr.Value()...)
+		if err := handle(&compositeKV{k, v}); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(itr.Error(), "error while iterating over the config history")
+}
+
 func encodeCompositeKey(ns, key string, blockNum uint64) []byte {
 	b := []byte(keyPrefix + ns)
 	b = append(b, separatorByte)
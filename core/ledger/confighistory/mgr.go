@@ -8,6 +8,7 @@ package confighistory
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -22,12 +23,18 @@ var logger = flogging.MustGetLogger("confighistory")
 
 const (
 	collectionConfigNamespace = "lscc" // lscc namespace was introduced in version 1.2 and we continue to use this in order to be compatible with existing data
+	collectionConfigKeySuffix = "~collection"
 )
 
 // Mgr should be registered as a state listener. The state listener builds the history and retriever helps in querying the history
 type Mgr interface {
 	ledger.StateListener
 	GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetriever) ledger.ConfigHistoryRetriever
+	ExportConfigHistory(ledgerID string, handle func(chaincodeName string, configInfo *ledger.CollectionConfigInfo) error) error
+	ImportConfigHistory(ledgerID string, chaincodeName string, configInfo *ledger.CollectionConfigInfo) error
+	// DropConfigHistory removes the config history of a ledger so that it can be rebuilt
+	// by committing the blocks of the ledger again
+	DropConfigHistory(ledgerID string) error
 	Close()
 }
 
@@ -96,6 +103,40 @@ func (m *mgr) GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetrie
 	return &retriever{dbHandle: m.dbProvider.getDB(ledgerID), ledgerInfoRetriever: ledgerInfoRetriever}
 }
 
+// ExportConfigHistory implements the function in the interface 'Mgr'. The function `handle` is invoked
+// for each of the collection config entries present in the history of the given ledger
+func (m *mgr) ExportConfigHistory(ledgerID string, handle func(chaincodeName string, configInfo *ledger.CollectionConfigInfo) error) error {
+	dbHandle := m.dbProvider.getDB(ledgerID)
+	return dbHandle.iterateAllEntries(func(compositeKV *compositeKV) error {
+		if compositeKV.ns != collectionConfigNamespace || !strings.HasSuffix(compositeKV.key, collectionConfigKeySuffix) {
+			return nil
+		}
+		configInfo, err := compositeKVToCollectionConfig(compositeKV)
+		if err != nil {
+			return err
+		}
+		return handle(strings.TrimSuffix(compositeKV.key, collectionConfigKeySuffix), configInfo)
+	})
+}
+
+// ImportConfigHistory implements the function in the interface 'Mgr'. This adds an entry to the history
+// of the given ledger, as if the collection config was committed in the block `configInfo.CommittingBlockNum`
+func (m *mgr) ImportConfigHistory(ledgerID string, chaincodeName string, configInfo *ledger.CollectionConfigInfo) error {
+	batch, err := prepareDBBatch(
+		map[string]*common.CollectionConfigPackage{chaincodeName: configInfo.CollectionConfig},
+		configInfo.CommittingBlockNum,
+	)
+	if err != nil {
+		return err
+	}
+	return m.dbProvider.getDB(ledgerID).writeBatch(batch, true)
+}
+
+// DropConfigHistory implements the function in the interface 'Mgr'
+func (m *mgr) DropConfigHistory(ledgerID string) error {
+	return m.dbProvider.getDB(ledgerID).DeleteAll()
+}
+
 // Close implements the function in the interface 'Mgr'
 func (m *mgr) Close() {
 	m.dbProvider.Close()
@@ -157,7 +198,7 @@ func compositeKVToCollectionConfig(compositeKV *compositeKV) (*ledger.Collection
 }
 
 func constructCollectionConfigKey(chaincodeName string) string {
-	return chaincodeName + "~collection" // collection config key as in version 1.2 and we continue to use this in order to be compatible with existing data
+	return chaincodeName + collectionConfigKeySuffix // collection config key as in version 1.2 and we continue to use this in order to be compatible with existing data
 }
 
 func dbPath() string {
@@ -115,6 +115,80 @@ func TestMgr(t *testing.T) {
 	})
 }
 
+func TestExportAndImportConfigHistory(t *testing.T) {
+	dbPath := "/tmp/fabric/core/ledger/confighistory"
+	mockCCInfoProvider := &mock.DeployedChaincodeInfoProvider{}
+	env := newTestEnv(t, dbPath, mockCCInfoProvider)
+	mgr := env.mgr
+	defer env.cleanup()
+
+	for _, chaincodeName := range []string{"chaincode1", "chaincode2"} {
+		for _, committingBlockNum := range []uint64{5, 10} {
+			testutilEquipMockCCInfoProviderToReturnDesiredCollConfig(mockCCInfoProvider, chaincodeName,
+				sampleCollectionConfigPackage(chaincodeName, committingBlockNum))
+			assert.NoError(t, mgr.HandleStateUpdates(&ledger.StateUpdateTrigger{
+				LedgerID:           "sourceLedger",
+				CommittingBlockNum: committingBlockNum},
+			))
+		}
+	}
+
+	exported := map[string][]*ledger.CollectionConfigInfo{}
+	err := mgr.ExportConfigHistory("sourceLedger", func(chaincodeName string, configInfo *ledger.CollectionConfigInfo) error {
+		exported[chaincodeName] = append(exported[chaincodeName], configInfo)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, exported, 2)
+	for chaincodeName, configInfos := range exported {
+		assert.Len(t, configInfos, 2)
+		for _, configInfo := range configInfos {
+			assert.NoError(t, mgr.ImportConfigHistory("targetLedger", chaincodeName, configInfo))
+		}
+	}
+
+	dummyLedgerInfoRetriever := &dummyLedgerInfoRetriever{info: &common.BlockchainInfo{Height: 20}}
+	retriever := mgr.GetRetriever("targetLedger", dummyLedgerInfoRetriever)
+	for _, chaincodeName := range []string{"chaincode1", "chaincode2"} {
+		for _, committingBlockNum := range []uint64{5, 10} {
+			retrievedConfig, err := retriever.CollectionConfigAt(committingBlockNum, chaincodeName)
+			assert.NoError(t, err)
+			assert.Equal(t, sampleCollectionConfigPackage(chaincodeName, committingBlockNum), retrievedConfig.CollectionConfig)
+		}
+	}
+
+	err = mgr.ExportConfigHistory("sourceLedger", func(chaincodeName string, configInfo *ledger.CollectionConfigInfo) error {
+		return fmt.Errorf("handler-error")
+	})
+	assert.EqualError(t, err, "handler-error")
+}
+
+func TestDropConfigHistory(t *testing.T) {
+	dbPath := "/tmp/fabric/core/ledger/confighistory"
+	mockCCInfoProvider := &mock.DeployedChaincodeInfoProvider{}
+	env := newTestEnv(t, dbPath, mockCCInfoProvider)
+	mgr := env.mgr
+	defer env.cleanup()
+
+	testutilEquipMockCCInfoProviderToReturnDesiredCollConfig(mockCCInfoProvider, "chaincode1",
+		sampleCollectionConfigPackage("chaincode1", 5))
+	for _, ledgerID := range []string{"ledger1", "ledger2"} {
+		assert.NoError(t, mgr.HandleStateUpdates(&ledger.StateUpdateTrigger{
+			LedgerID:           ledgerID,
+			CommittingBlockNum: 5},
+		))
+	}
+
+	assert.NoError(t, mgr.DropConfigHistory("ledger1"))
+	dummyLedgerInfoRetriever := &dummyLedgerInfoRetriever{info: &common.BlockchainInfo{Height: 10}}
+	collConfig, err := mgr.GetRetriever("ledger1", dummyLedgerInfoRetriever).CollectionConfigAt(5, "chaincode1")
+	assert.NoError(t, err)
+	assert.Nil(t, collConfig)
+	collConfig, err = mgr.GetRetriever("ledger2", dummyLedgerInfoRetriever).CollectionConfigAt(5, "chaincode1")
+	assert.NoError(t, err)
+	assert.Equal(t, sampleCollectionConfigPackage("chaincode1", 5), collConfig.CollectionConfig)
+}
+
 type testEnv struct {
 	dbPath string
 	mgr    Mgr
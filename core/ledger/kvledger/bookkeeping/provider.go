@@ -27,6 +27,8 @@ const (
 type Provider interface {
 	// GetDBHandle returns a db handle that can be used for maintaining the bookkeeping of a given category
 	GetDBHandle(ledgerID string, cat Category) *leveldbhelper.DBHandle
+	// Drop removes the bookkeeping of all the categories for a given ledger
+	Drop(ledgerID string) error
 	// Close closes the BookkeeperProvider
 	Close()
 }
@@ -46,6 +48,16 @@ func (provider *provider) GetDBHandle(ledgerID string, cat Category) *leveldbhel
 	return provider.dbProvider.GetDBHandle(fmt.Sprintf(ledgerID+"/%d", cat))
 }
 
+// Drop implements the function in the interface 'BookkeeperProvider'
+func (provider *provider) Drop(ledgerID string) error {
+	for _, cat := range []Category{PvtdataExpiry, MetadataPresenceIndicator} {
+		if err := provider.GetDBHandle(ledgerID, cat).DeleteAll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close implements the function in the interface 'BookKeeperProvider'
 func (provider *provider) Close() {
 	provider.dbProvider.Close()
@@ -29,3 +29,24 @@ func TestProvider(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []byte("value"), val)
 }
+
+func TestProviderDrop(t *testing.T) {
+	testEnv := NewTestEnv(t)
+	defer testEnv.Cleanup()
+	p := testEnv.TestProvider
+	for _, ledgerID := range []string{"TestLedger", "OtherLedger"} {
+		for _, cat := range []Category{PvtdataExpiry, MetadataPresenceIndicator} {
+			assert.NoError(t, p.GetDBHandle(ledgerID, cat).Put([]byte("key"), []byte("value"), true))
+		}
+	}
+
+	assert.NoError(t, p.Drop("TestLedger"))
+	for _, cat := range []Category{PvtdataExpiry, MetadataPresenceIndicator} {
+		val, err := p.GetDBHandle("TestLedger", cat).Get([]byte("key"))
+		assert.NoError(t, err)
+		assert.Nil(t, val)
+		val, err = p.GetDBHandle("OtherLedger", cat).Get([]byte("key"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), val)
+	}
+}
@@ -26,6 +26,9 @@ func dropDBs() error {
 	if err := dropStateLevelDB(); err != nil {
 		return err
 	}
+	if err := dropStateBadgerDB(); err != nil {
+		return err
+	}
 	if err := dropConfigHistoryDB(); err != nil {
 		return err
 	}
@@ -45,6 +48,13 @@ func dropStateLevelDB() error {
 	return errors.Wrapf(err, "error removing the StateLevelDB located at %s", stateLeveldbPath)
 }
 
+func dropStateBadgerDB() error {
+	stateBadgerDBPath := ledgerconfig.GetStateBadgerDBPath()
+	logger.Infof("Dropping StateBadgerDB at location [%s]", stateBadgerDBPath)
+	err := os.RemoveAll(stateBadgerDBPath)
+	return errors.Wrapf(err, "error removing the StateBadgerDB located at %s", stateBadgerDBPath)
+}
+
 func dropConfigHistoryDB() error {
 	configHistoryDBPath := ledgerconfig.GetConfigHistoryPath()
 	logger.Infof("Dropping ConfigHistoryDB at location [%s]", configHistoryDBPath)
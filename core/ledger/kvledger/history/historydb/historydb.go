@@ -36,6 +36,7 @@ type HistoryDB interface {
 	NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error)
 	Commit(block *common.Block) error
 	GetLastSavepoint() (*version.Height, error)
+	SetLastSavepoint(height *version.Height) error
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
 	CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error
 	Name() string
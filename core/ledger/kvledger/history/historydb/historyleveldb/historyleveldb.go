@@ -7,8 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package historyleveldb
 
 import (
+	"bytes"
+	"math"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	cutil "github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
@@ -18,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 )
 
 var logger historydbLogger = flogging.MustGetLogger("historyleveldb")
@@ -25,6 +37,32 @@ var logger historydbLogger = flogging.MustGetLogger("historyleveldb")
 var savePointKey = []byte{0x00}
 var emptyValue = []byte{}
 
+// accessIndexKeyPrefix separates the entries of the access index from the history records
+// and the savepoint. A namespace never starts with this byte and hence the keys do not clash
+var accessIndexKeyPrefix = []byte{0x01}
+
+// timeIndexKeyPrefix separates the entries of the time index, which orders the history records of
+// a key by the timestamp of the transactions so that the queries restricted to a time range do
+// not scan the whole history of the key. The index adds an entry for every history record
+var timeIndexKeyPrefix = []byte{0x02}
+
+// accessIndexEnabledKey records whether the configuration of the channel, as of the last committed config
+// block, enables the access index. The access index is maintained only while the channel has the application
+// capability V1_4_KEY_ACCESS_INDEX, so that every peer of the channel indexes the same transactions
+var accessIndexEnabledKey = []byte{0x03}
+
+// timeIndexStartKey records the number of the first block covered by the time index. The history databases
+// written before the time index was introduced have no time index entries for the blocks committed until
+// then, and the queries restricted to a time range on these blocks scan the history records by height
+// instead, until the history database is rebuilt
+var timeIndexStartKey = []byte{0x04}
+
+// the types of the access to a key that are recorded in the access index
+const (
+	accessRead  = byte(0x01)
+	accessWrite = byte(0x02)
+)
+
 //go:generate counterfeiter -o fakes/historydb_logger.go -fake-name HistorydbLogger . historydbLogger
 
 // historydbLogger defines the interface for historyleveldb logging. The purpose is to allow unit tests to use a fake logger.
@@ -50,7 +88,22 @@ func NewHistoryDBProvider() *HistoryDBProvider {
 
 // GetDBHandle gets the handle to a named database
 func (provider *HistoryDBProvider) GetDBHandle(dbName string) (historydb.HistoryDB, error) {
-	return newHistoryDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	historyDB := newHistoryDB(provider.dbProvider.GetDBHandle(dbName), dbName)
+	accessIndexEnabled, err := historyDB.db.Get(accessIndexEnabledKey)
+	if err != nil {
+		return nil, err
+	}
+	historyDB.accessIndexEnabled = bytes.Equal(accessIndexEnabled, []byte{1})
+	if err := historyDB.initTimeIndexStart(); err != nil {
+		return nil, err
+	}
+	return historyDB, nil
+}
+
+// Drop removes the history and the savepoint of the named database.
+// This is expected to be invoked only when the named database is not in use
+func (provider *HistoryDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
 }
 
 // Close closes the underlying db
@@ -62,11 +115,41 @@ func (provider *HistoryDBProvider) Close() {
 type historyDB struct {
 	db     *leveldbhelper.DBHandle
 	dbName string
+	// accessIndexEnabled is updated when a config block is committed
+	accessIndexEnabled bool
+	// timeIndexStart is the number of the first block covered by the time index
+	timeIndexStart uint64
 }
 
 // newHistoryDB constructs an instance of HistoryDB
 func newHistoryDB(db *leveldbhelper.DBHandle, dbName string) *historyDB {
-	return &historyDB{db, dbName}
+	return &historyDB{db: db, dbName: dbName}
+}
+
+// initTimeIndexStart loads the number of the first block covered by the time index. A history database
+// without this record but with a savepoint was written before the time index was introduced, hence
+// the time index only covers the blocks that follow the savepoint
+func (historyDB *historyDB) initTimeIndexStart() error {
+	timeIndexStart, err := historyDB.db.Get(timeIndexStartKey)
+	if err != nil {
+		return err
+	}
+	if timeIndexStart != nil {
+		historyDB.timeIndexStart, _, err = cutil.DecodeOrderPreservingVarUint64(timeIndexStart)
+		return err
+	}
+
+	savepoint, err := historyDB.GetLastSavepoint()
+	if err != nil {
+		return err
+	}
+	if savepoint != nil {
+		historyDB.timeIndexStart = savepoint.BlockNum + 1
+		logger.Infof("Channel [%s]: The history database has no time index for the blocks up to [%d], the history queries "+
+			"restricted to a time range scan the whole history of the key on these blocks until the history database is rebuilt",
+			historyDB.dbName, savepoint.BlockNum)
+	}
+	return historyDB.db.Put(timeIndexStartKey, cutil.EncodeOrderPreservingVarUint64(historyDB.timeIndexStart), true)
 }
 
 // Open implements method in HistoryDB interface
@@ -94,67 +177,91 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 
 	// Get the invalidation byte array for the block
 	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	indexAccesses := historyDB.accessIndexEnabled
+
+	// a config block carries no endorser transaction but may enable or disable the access index
+	// for the blocks that follow. The setting is saved in the same batch as the savepoint
+	if putils.IsConfigBlock(block) {
+		var err error
+		if indexAccesses, err = isAccessIndexEnabledInConfig(block); err != nil {
+			return err
+		}
+		logger.Debugf("Channel [%s]: Access index enabled by config block [%d]: %t", historyDB.dbName, blockNo, indexAccesses)
+		if indexAccesses {
+			dbBatch.Put(accessIndexEnabledKey, []byte{1})
+		} else {
+			dbBatch.Put(accessIndexEnabledKey, []byte{0})
+		}
+	}
 
 	// write each tran's write set to history db
 	for _, envBytes := range block.Data.Data {
 
-		// If the tran is marked as invalid, skip it
-		if txsFilter.IsInvalid(int(tranNo)) {
+		// The invalid transactions are not added to the history of values but are added to the
+		// access index, if enabled, so that the attempts to access a key can be audited as well
+		valid := !txsFilter.IsInvalid(int(tranNo))
+		if !valid && !indexAccesses {
 			logger.Debugf("Channel [%s]: Skipping history write for invalid transaction number %d",
 				historyDB.dbName, tranNo)
 			tranNo++
 			continue
 		}
 
-		env, err := putils.GetEnvelopeFromBlock(envBytes)
+		txRWSet, txTimestamp, err := getEndorserTxRWSet(envBytes)
 		if err != nil {
-			return err
+			if valid {
+				return err
+			}
+			logger.Debugf("Channel [%s]: Skipping access index for invalid transaction number %d that cannot be parsed: %s",
+				historyDB.dbName, tranNo, err)
+			tranNo++
+			continue
 		}
-
-		payload, err := putils.GetPayload(env)
-		if err != nil {
-			return err
+		if txRWSet == nil {
+			logger.Debugf("Skipping transaction [%d] since it is not an endorsement transaction\n", tranNo)
+			tranNo++
+			continue
 		}
-
-		chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
-		if err != nil {
-			return err
+		if !valid {
+			logger.Debugf("Channel [%s]: Skipping history write for invalid transaction number %d",
+				historyDB.dbName, tranNo)
 		}
 
-		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-
-			// extract actions from the envelope message
-			respPayload, err := putils.GetActionFromEnvelope(envBytes)
-			if err != nil {
-				return err
-			}
-
-			//preparation for extracting RWSet from transaction
-			txRWSet := &rwsetutil.TxRwSet{}
+		// for each transaction, loop through the namespaces and writesets
+		// and add a history record for each write
+		accessValue := []byte{0, byte(txsFilter.Flag(int(tranNo)))}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			ns := nsRWSet.NameSpace
+			accesses := map[string]byte{}
 
-			// Get the Result from the Action and then Unmarshal
-			// it into a TxReadWriteSet using custom unmarshalling
-			if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
-				return err
+			if indexAccesses {
+				for _, kvRead := range nsRWSet.KvRwSet.Reads {
+					accesses[kvRead.Key] |= accessRead
+				}
 			}
-			// for each transaction, loop through the namespaces and writesets
-			// and add a history record for each write
-			for _, nsRWSet := range txRWSet.NsRwSets {
-				ns := nsRWSet.NameSpace
 
-				for _, kvWrite := range nsRWSet.KvRwSet.Writes {
-					writeKey := kvWrite.Key
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				writeKey := kvWrite.Key
+				if indexAccesses {
+					accesses[writeKey] |= accessWrite
+				}
+				if !valid {
+					continue
+				}
 
-					//composite key for history records is in the form ns~key~blockNo~tranNo
-					compositeHistoryKey := historydb.ConstructCompositeHistoryKey(ns, writeKey, blockNo, tranNo)
+				//composite key for history records is in the form ns~key~blockNo~tranNo
+				compositeHistoryKey := historydb.ConstructCompositeHistoryKey(ns, writeKey, blockNo, tranNo)
 
-					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
-					dbBatch.Put(compositeHistoryKey, emptyValue)
-				}
+				// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
+				dbBatch.Put(compositeHistoryKey, emptyValue)
+				dbBatch.Put(constructTimeIndexKey(ns, writeKey, txTimestamp, blockNo, tranNo), emptyValue)
 			}
 
-		} else {
-			logger.Debugf("Skipping transaction [%d] since it is not an endorsement transaction\n", tranNo)
+			// the access index records the type of the access and the validation code of the transaction
+			for key, access := range accesses {
+				accessValue[0] = access
+				dbBatch.Put(constructAccessIndexKey(ns, key, blockNo, tranNo), append([]byte{}, accessValue...))
+			}
 		}
 		tranNo++
 	}
@@ -169,10 +276,136 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 		return err
 	}
 
+	historyDB.accessIndexEnabled = indexAccesses
+
 	logger.Debugf("Channel [%s]: Updates committed to history database for blockNo [%v]", historyDB.dbName, blockNo)
 	return nil
 }
 
+// isAccessIndexEnabledInConfig returns whether the application capabilities set by a config block
+// enable the access index. The access index is disabled on the channels without an application group
+func isAccessIndexEnabledInConfig(block *common.Block) (bool, error) {
+	env, err := putils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return false, err
+	}
+
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return false, err
+	}
+
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return false, err
+	}
+
+	applicationGroup, ok := configEnvelope.GetConfig().GetChannelGroup().GetGroups()[channelconfig.ApplicationGroupKey]
+	if !ok {
+		return false, nil
+	}
+	capabilitiesValue, ok := applicationGroup.Values[channelconfig.CapabilitiesKey]
+	if !ok {
+		return false, nil
+	}
+	applicationCapabilities := &common.Capabilities{}
+	if err := proto.Unmarshal(capabilitiesValue.Value, applicationCapabilities); err != nil {
+		return false, errors.Wrap(err, "error unmarshaling the application capabilities")
+	}
+	return capabilities.NewApplicationProvider(applicationCapabilities.Capabilities).KeyAccessIndex(), nil
+}
+
+// getEndorserTxRWSet extracts the read-write set and the timestamp of an endorser transaction.
+// A nil read-write set is returned for the other types of transactions
+func getEndorserTxRWSet(envBytes []byte) (*rwsetutil.TxRwSet, *timestamp.Timestamp, error) {
+	env, err := putils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil, nil
+	}
+
+	// extract actions from the envelope message
+	respPayload, err := putils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	//preparation for extracting RWSet from transaction
+	txRWSet := &rwsetutil.TxRwSet{}
+
+	// Get the Result from the Action and then Unmarshal
+	// it into a TxReadWriteSet using custom unmarshalling
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return nil, nil, err
+	}
+	return txRWSet, chdr.Timestamp, nil
+}
+
+// constructAccessIndexKey builds the key of an access index entry in the form prefix~ns~key~blockNo~tranNo
+func constructAccessIndexKey(ns string, key string, blockNo uint64, tranNo uint64) []byte {
+	return append(append([]byte{}, accessIndexKeyPrefix...), historydb.ConstructCompositeHistoryKey(ns, key, blockNo, tranNo)...)
+}
+
+// constructPartialAccessIndexKey builds a partial key of the access index in the form prefix~ns~key~
+// for use in the range queries on the access index
+func constructPartialAccessIndexKey(ns string, key string, endkey bool) []byte {
+	return append(append([]byte{}, accessIndexKeyPrefix...), historydb.ConstructPartialCompositeHistoryKey(ns, key, endkey)...)
+}
+
+// constructTimeIndexKey builds the key of a time index entry in the form prefix~ns~key~time~blockNo~tranNo,
+// where time is the timestamp of the transaction in nanoseconds since the epoch
+func constructTimeIndexKey(ns string, key string, txTimestamp *timestamp.Timestamp, blockNo uint64, tranNo uint64) []byte {
+	timeIndexKey := constructPartialTimeIndexKey(ns, key, false)
+	timeIndexKey = append(timeIndexKey, cutil.EncodeOrderPreservingVarUint64(timeIndexNanos(txTimestamp))...)
+	timeIndexKey = append(timeIndexKey, cutil.EncodeOrderPreservingVarUint64(blockNo)...)
+	return append(timeIndexKey, cutil.EncodeOrderPreservingVarUint64(tranNo)...)
+}
+
+// constructPartialTimeIndexKey builds a partial key of the time index in the form prefix~ns~key~
+// for use in the range queries on the time index
+func constructPartialTimeIndexKey(ns string, key string, endkey bool) []byte {
+	return append(append([]byte{}, timeIndexKeyPrefix...), historydb.ConstructPartialCompositeHistoryKey(ns, key, endkey)...)
+}
+
+// timeIndexNanos converts a transaction timestamp to the nanoseconds since the epoch used in the time
+// index. Missing timestamps and timestamps before the epoch map to zero and timestamps too far in the
+// future to be represented map to the maximum value
+func timeIndexNanos(txTimestamp *timestamp.Timestamp) uint64 {
+	t, err := ptypes.Timestamp(txTimestamp)
+	if err != nil {
+		if txTimestamp != nil && txTimestamp.Seconds > 0 {
+			return math.MaxInt64
+		}
+		return 0
+	}
+	return timeNanos(t)
+}
+
+// timeNanos converts a time to the nanoseconds since the epoch, bounded in the same way as timeIndexNanos
+func timeNanos(t time.Time) uint64 {
+	switch {
+	case t.Unix() < 0:
+		return 0
+	case t.Unix() >= math.MaxInt64/int64(time.Second):
+		return math.MaxInt64
+	default:
+		return uint64(t.UnixNano())
+	}
+}
+
 // NewHistoryQueryExecutor implements method in HistoryDB interface
 func (historyDB *historyDB) NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error) {
 	return &LevelHistoryDBQueryExecutor{historyDB, blockStore}, nil
@@ -191,6 +424,12 @@ func (historyDB *historyDB) GetLastSavepoint() (*version.Height, error) {
 	return height, nil
 }
 
+// SetLastSavepoint implements method in HistoryDB interface. This is used when a ledger is bootstrapped
+// from a snapshot, as the history for the blocks covered by the snapshot is not available
+func (historyDB *historyDB) SetLastSavepoint(height *version.Height) error {
+	return historyDB.db.Put(savePointKey, height.ToBytes(), true)
+}
+
 // ShouldRecover implements method in interface kvledger.Recoverer
 func (historyDB *historyDB) ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error) {
 	if !ledgerconfig.IsHistoryDBEnabled() {
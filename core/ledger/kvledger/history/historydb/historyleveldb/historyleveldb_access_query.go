@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package historyleveldb
+
+import (
+	"encoding/hex"
+
+	"github.com/golang/protobuf/proto"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// GetAccessHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetAccessHistoryForKey(namespace string, key string,
+	options *ledger.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
+
+	options, err := validateHistoryQueryOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	partialKey := constructPartialAccessIndexKey(namespace, key, false)
+	startKey, endKey, err := scanRange(options, partialKey,
+		constructPartialAccessIndexKey(namespace, key, true),
+		func(blockNum, tranNum uint64) []byte {
+			return constructAccessIndexKey(namespace, key, blockNum, tranNum)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// range scan to find the access index entries starting with prefix~namespace~key
+	dbItr := q.historyDB.db.GetIterator(startKey, endKey)
+	return &accessScanner{newHistoryScanner(partialKey, namespace, key, dbItr, q.blockStore, options)}, nil
+}
+
+// accessScanner implements ResultsIterator for iterating through the access index. It shares the
+// ordering, the paging, and the time filtering with the historyScanner
+type accessScanner struct {
+	*historyScanner
+}
+
+// Next iterates to the next entry of the access index, loads the corresponding transaction from
+// the block storage and returns the details of the transaction along with the type of the access.
+// Like the history records, an entry of the access index may clash with the entry of another key
+// that contains nil bytes; such entries are detected and skipped in the same way as in historyScanner
+func (scanner *accessScanner) Next() (commonledger.QueryResult, error) {
+	if scanner.options.PageSize > 0 && scanner.returnedCount >= scanner.options.PageSize {
+		return nil, nil
+	}
+	for {
+		if !scanner.moveNext() {
+			return nil, nil
+		}
+		indexKey := scanner.dbItr.Key()
+		_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(indexKey, scanner.compositePartialKey)
+		blockNum, tranNum, err := decodeBlockNumTranNum(blockNumTranNumBytes)
+		if err != nil {
+			logger.Warnf("Some other key [%#v] found in the range while scanning access history for key [%#v]. Skipping (decoding error: %s)",
+				indexKey, scanner.key, err)
+			continue
+		}
+		accessValue := scanner.dbItr.Value()
+		if len(accessValue) != 2 {
+			return nil, errors.Errorf("unexpected value [%#v] of the access index entry [%#v]", accessValue, indexKey)
+		}
+
+		tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+		if err == blkstorage.ErrNotFoundInIndex {
+			logger.Warnf("Some other clashing key [%#v] found in the range while scanning access history for key [%#v]. Skipping (cannot find block:tx)",
+				indexKey, scanner.key)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keyAccess, err := getKeyAccessFromTran(tranEnvelope, scanner.namespace, scanner.key)
+		if err != nil {
+			return nil, err
+		}
+		if keyAccess == nil {
+			logger.Warnf("Some other key [%#v] found in the range while scanning access history for key [%#v]. Skipping (namespace or key not found)",
+				indexKey, scanner.key)
+			continue
+		}
+		if !scanner.inTimeRange(keyAccess.Timestamp) {
+			logger.Debugf("Skipping access record for namespace:%s key:%s from transaction %s outside the requested time range",
+				scanner.namespace, scanner.key, keyAccess.TxId)
+			continue
+		}
+		keyAccess.Read = accessValue[0]&accessRead != 0
+		keyAccess.Write = accessValue[0]&accessWrite != 0
+		keyAccess.ValidationCode = peer.TxValidationCode(accessValue[1])
+		keyAccess.BlockNum = blockNum
+		keyAccess.TxNum = tranNum
+
+		scanner.returnedCount++
+		scanner.bookmark = hex.EncodeToString(blockNumTranNumBytes)
+		return keyAccess, nil
+	}
+}
+
+// getKeyAccessFromTran returns the txid, the timestamp, and the MSP ID of the creator of a transaction
+// that read or wrote the given key. A nil result is returned if the transaction did not access the key
+func getKeyAccessFromTran(tranEnvelope *common.Envelope, namespace string, key string) (*queryresult.KeyAccess, error) {
+	payload, err := putils.GetPayload(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := putils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, respPayload, err := putils.GetPayloads(tx.Actions[0])
+	if err != nil {
+		return nil, err
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return nil, err
+	}
+
+	keyAccess := &queryresult.KeyAccess{TxId: chdr.TxId, Timestamp: chdr.Timestamp, CreatorMspId: getCreatorMSPID(payload.Header)}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != namespace {
+			continue
+		}
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			if kvRead.Key == key {
+				return keyAccess, nil
+			}
+		}
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			if kvWrite.Key == key {
+				return keyAccess, nil
+			}
+		}
+		logger.Debugf("key [%s] not found in namespace [%s]'s readset and writeset", key, namespace)
+		return nil, nil
+	}
+	logger.Debugf("namespace [%s] not found in transaction's ReadWriteSets", namespace)
+	return nil, nil
+}
+
+// getCreatorMSPID returns the MSP ID of the creator of a transaction. As an invalid transaction
+// may carry a malformed creator, an empty MSP ID is returned if the creator cannot be decoded
+func getCreatorMSPID(header *common.Header) string {
+	shdr, err := putils.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		logger.Debugf("Cannot decode the signature header of the transaction: %s", err)
+		return ""
+	}
+	creator := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, creator); err != nil {
+		logger.Debugf("Cannot decode the creator of the transaction: %s", err)
+		return ""
+	}
+	return creator.Mspid
+}
@@ -7,9 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package historyleveldb
 
 import (
+	"bytes"
+	"encoding/hex"
+	"math"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -28,19 +35,134 @@ type LevelHistoryDBQueryExecutor struct {
 
 // GetHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
 func (q *LevelHistoryDBQueryExecutor) GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error) {
+	return q.GetHistoryForKeyWithOptions(namespace, key, nil)
+}
 
-	if ledgerconfig.IsHistoryDBEnabled() == false {
-		return nil, errors.New("history database not enabled")
+// GetHistoryForKeyWithOptions implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string,
+	options *ledger.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
+
+	options, err := validateHistoryQueryOptions(options)
+	if err != nil {
+		return nil, err
 	}
 
-	var compositeStartKey []byte
-	var compositeEndKey []byte
-	compositeStartKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
-	compositeEndKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	// the time index narrows the scan to the records of the transactions in the time range, provided that it covers
+	// the blocks in the block range. Otherwise, the records are scanned by height and filtered by the timestamp
+	timeRange := !options.StartTime.IsZero() || !options.EndTime.IsZero()
+	if timeRange && options.StartBlock >= q.historyDB.timeIndexStart {
+		partialKey := constructPartialTimeIndexKey(namespace, key, false)
+		startKey, endKey, err := timeScanRange(options, partialKey, constructPartialTimeIndexKey(namespace, key, true))
+		if err != nil {
+			return nil, err
+		}
+		scanner := newHistoryScanner(partialKey, namespace, key, q.historyDB.db.GetIterator(startKey, endKey), q.blockStore, options)
+		scanner.timeOrdered = true
+		return scanner, nil
+	}
+
+	compositePartialKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
+	compositeStartKey, compositeEndKey, err := scanRange(options, compositePartialKey,
+		historydb.ConstructPartialCompositeHistoryKey(namespace, key, true),
+		func(blockNum, tranNum uint64) []byte {
+			return historydb.ConstructCompositeHistoryKey(namespace, key, blockNum, tranNum)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	// range scan to find any history records starting with namespace~key
 	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newHistoryScanner(compositeStartKey, namespace, key, dbItr, q.blockStore), nil
+	return newHistoryScanner(compositePartialKey, namespace, key, dbItr, q.blockStore, options), nil
+}
+
+// validateHistoryQueryOptions checks that the history database is enabled and that the options are
+// consistent. A nil options is replaced by the default options
+func validateHistoryQueryOptions(options *ledger.HistoryQueryOptions) (*ledger.HistoryQueryOptions, error) {
+	if ledgerconfig.IsHistoryDBEnabled() == false {
+		return nil, errors.New("history database not enabled")
+	}
+	if options == nil {
+		options = &ledger.HistoryQueryOptions{}
+	}
+	if options.EndBlock != 0 && options.EndBlock < options.StartBlock {
+		return nil, errors.Errorf("end block [%d] is lower than the start block [%d]", options.EndBlock, options.StartBlock)
+	}
+	if options.PageSize < 0 {
+		return nil, errors.Errorf("page size [%d] must not be negative", options.PageSize)
+	}
+	return options, nil
+}
+
+// scanRange narrows the range [startKey, endKey) that covers all the records of a key to the block range
+// and the bookmark in the options. The function constructKey builds the record key for a given blockNum:tranNum
+func scanRange(options *ledger.HistoryQueryOptions, startKey, endKey []byte,
+	constructKey func(blockNum, tranNum uint64) []byte) ([]byte, []byte, error) {
+
+	if options.StartBlock > 0 {
+		startKey = constructKey(options.StartBlock, 0)
+	}
+	if options.EndBlock > 0 && options.EndBlock < math.MaxUint64 {
+		endKey = constructKey(options.EndBlock+1, 0)
+	}
+
+	// the bookmark narrows the range so that the scan resumes right after the last result of the previous page
+	if options.Bookmark != "" {
+		blockNum, tranNum, err := decodeBookmark(options.Bookmark)
+		if err != nil {
+			return nil, nil, err
+		}
+		if options.NewestFirst {
+			bookmarkKey := constructKey(blockNum, tranNum)
+			if bytes.Compare(bookmarkKey, endKey) < 0 {
+				endKey = bookmarkKey
+			}
+		} else {
+			bookmarkKey := constructKey(blockNum, tranNum+1)
+			if bytes.Compare(bookmarkKey, startKey) > 0 {
+				startKey = bookmarkKey
+			}
+		}
+	}
+	return startKey, endKey, nil
+}
+
+// timeScanRange narrows the range [startKey, endKey) that covers all the time index entries of a key to the
+// time range and the bookmark in the options. The block range is applied as the entries are scanned
+func timeScanRange(options *ledger.HistoryQueryOptions, partialKey, endKey []byte) ([]byte, []byte, error) {
+	startKey := partialKey
+	if !options.StartTime.IsZero() {
+		startKey = append(append([]byte{}, partialKey...), util.EncodeOrderPreservingVarUint64(timeNanos(options.StartTime))...)
+	}
+	if !options.EndTime.IsZero() {
+		endKey = append(append([]byte{}, partialKey...), util.EncodeOrderPreservingVarUint64(timeNanos(options.EndTime))...)
+	}
+
+	if options.Bookmark != "" {
+		recordBytes, err := hex.DecodeString(options.Bookmark)
+		if err != nil {
+			return nil, nil, errors.Errorf("invalid bookmark [%s] for the history query", options.Bookmark)
+		}
+		txTime, blockNum, tranNum, err := decodeTimeIndexRecord(recordBytes)
+		if err != nil {
+			return nil, nil, errors.Errorf("invalid bookmark [%s] for the history query", options.Bookmark)
+		}
+		bookmarkKey := append(append([]byte{}, partialKey...), util.EncodeOrderPreservingVarUint64(txTime)...)
+		bookmarkKey = append(bookmarkKey, util.EncodeOrderPreservingVarUint64(blockNum)...)
+		if options.NewestFirst {
+			bookmarkKey = append(bookmarkKey, util.EncodeOrderPreservingVarUint64(tranNum)...)
+			if bytes.Compare(bookmarkKey, endKey) < 0 {
+				endKey = bookmarkKey
+			}
+		} else {
+			bookmarkKey = append(bookmarkKey, util.EncodeOrderPreservingVarUint64(tranNum+1)...)
+			if bytes.Compare(bookmarkKey, startKey) > 0 {
+				startKey = bookmarkKey
+			}
+		}
+	}
+	return startKey, endKey, nil
 }
 
 //historyScanner implements ResultsIterator for iterating through history results
@@ -50,11 +172,24 @@ type historyScanner struct {
 	key                 string
 	dbItr               iterator.Iterator
 	blockStore          blkstorage.BlockStore
+	options             *ledger.HistoryQueryOptions
+	timeOrdered         bool // the keys scanned are those of the time index
+	positioned          bool
+	returnedCount       int32
+	bookmark            string
 }
 
 func newHistoryScanner(compositePartialKey []byte, namespace string, key string,
-	dbItr iterator.Iterator, blockStore blkstorage.BlockStore) *historyScanner {
-	return &historyScanner{compositePartialKey, namespace, key, dbItr, blockStore}
+	dbItr iterator.Iterator, blockStore blkstorage.BlockStore, options *ledger.HistoryQueryOptions) *historyScanner {
+	return &historyScanner{
+		compositePartialKey: compositePartialKey,
+		namespace:           namespace,
+		key:                 key,
+		dbItr:               dbItr,
+		blockStore:          blockStore,
+		options:             options,
+		bookmark:            options.Bookmark,
+	}
 }
 
 // Next iterates to the next key from history scanner, decodes blockNumTranNumBytes to get blockNum and tranNum,
@@ -68,14 +203,19 @@ func newHistoryScanner(compositePartialKey []byte, namespace string, key string,
 // was actually added for some other <ns, key, blockNum, tranNum>. It would cause this iterator to
 // return a history query result out of the order.
 func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
+	if scanner.options.PageSize > 0 && scanner.returnedCount >= scanner.options.PageSize {
+		return nil, nil
+	}
 	for {
-		if !scanner.dbItr.Next() {
+		if !scanner.moveNext() {
 			return nil, nil
 		}
 		historyKey := scanner.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
 
 		// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
-		_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
+		// and, for the time index, SplitCompositeKey(prefix~namespace~key~time~blocknum~trannum, prefix~namespace~key~)
+		// will return time~blocknum~trannum
+		_, recordBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
 
 		//
 		// FAB-15450
@@ -94,12 +234,15 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 		//
 		// Note: in some scenarios, this can map to a block:tran in the block storage that contains the key
 		// but is out of order of iteration and hence the results are not guaranteed to be in order.
-		blockNum, tranNum, err := decodeBlockNumTranNum(blockNumTranNumBytes)
+		blockNum, tranNum, err := scanner.decodeRecord(recordBytes)
 		if err != nil {
 			logger.Warnf("Some other key [%#v] found in the range while scanning history for key [%#v]. Skipping (decoding error: %s)",
 				historyKey, scanner.key, err)
 			continue
 		}
+		if !scanner.inBlockRange(blockNum) {
+			continue
+		}
 
 		logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
 			scanner.namespace, scanner.key, blockNum, tranNum)
@@ -129,16 +272,78 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 				historyKey, scanner.key)
 			continue
 		}
+		keyModification := queryResult.(*queryresult.KeyModification)
+		if !scanner.inTimeRange(keyModification.Timestamp) {
+			logger.Debugf("Skipping history record for namespace:%s key:%s from transaction %s outside the requested time range",
+				scanner.namespace, scanner.key, keyModification.TxId)
+			continue
+		}
 		logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s",
-			scanner.namespace, scanner.key, queryResult.(*queryresult.KeyModification).TxId)
+			scanner.namespace, scanner.key, keyModification.TxId)
+		scanner.returnedCount++
+		scanner.bookmark = hex.EncodeToString(recordBytes)
 		return queryResult, nil
 	}
 }
 
+// moveNext positions the db iterator on the next history record in the requested order
+func (scanner *historyScanner) moveNext() bool {
+	if !scanner.options.NewestFirst {
+		return scanner.dbItr.Next()
+	}
+	if !scanner.positioned {
+		scanner.positioned = true
+		return scanner.dbItr.Last()
+	}
+	return scanner.dbItr.Prev()
+}
+
+// decodeRecord decodes the blockNum and tranNum from the part of the key of a history record or of a time
+// index entry that follows the partial key
+func (scanner *historyScanner) decodeRecord(recordBytes []byte) (uint64, uint64, error) {
+	if !scanner.timeOrdered {
+		return decodeBlockNumTranNum(recordBytes)
+	}
+	_, blockNum, tranNum, err := decodeTimeIndexRecord(recordBytes)
+	return blockNum, tranNum, err
+}
+
+// inBlockRange checks whether a block falls within the [StartBlock, EndBlock] range of the query options.
+// The range of the scanned keys only reflects the block range when the records are ordered by height
+func (scanner *historyScanner) inBlockRange(blockNum uint64) bool {
+	return blockNum >= scanner.options.StartBlock && (scanner.options.EndBlock == 0 || blockNum <= scanner.options.EndBlock)
+}
+
+// inTimeRange checks whether the timestamp of the transaction that modified the key
+// falls within the [StartTime, EndTime) range of the query options
+func (scanner *historyScanner) inTimeRange(txTimestamp *timestamp.Timestamp) bool {
+	if scanner.options.StartTime.IsZero() && scanner.options.EndTime.IsZero() {
+		return true
+	}
+	txTime, err := ptypes.Timestamp(txTimestamp)
+	if err != nil {
+		return false
+	}
+	if !scanner.options.StartTime.IsZero() && txTime.Before(scanner.options.StartTime) {
+		return false
+	}
+	if !scanner.options.EndTime.IsZero() && !txTime.Before(scanner.options.EndTime) {
+		return false
+	}
+	return true
+}
+
 func (scanner *historyScanner) Close() {
 	scanner.dbItr.Release()
 }
 
+// GetBookmarkAndClose returns the bookmark of the last returned result and releases the db iterator.
+// If no result has been returned, the bookmark passed in the query options is returned
+func (scanner *historyScanner) GetBookmarkAndClose() string {
+	scanner.Close()
+	return scanner.bookmark
+}
+
 // getTxIDandKeyWriteValueFromTran inspects a transaction for writes to a given key
 func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (commonledger.QueryResult, error) {
 	logger.Debugf("Entering getKeyModificationFromTran()\n", namespace, key)
@@ -193,6 +398,32 @@ func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string,
 	return nil, nil
 }
 
+// decodeBookmark decodes the blockNum and tranNum of the last result of a previous page
+func decodeBookmark(bookmark string) (uint64, uint64, error) {
+	blockNumTranNumBytes, err := hex.DecodeString(bookmark)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid bookmark [%s] for the history query", bookmark)
+	}
+	blockNum, tranNum, err := decodeBlockNumTranNum(blockNumTranNumBytes)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid bookmark [%s] for the history query", bookmark)
+	}
+	return blockNum, tranNum, nil
+}
+
+// decodeTimeIndexRecord decodes the time, the blockNum and the tranNum of a time index entry
+func decodeTimeIndexRecord(recordBytes []byte) (uint64, uint64, uint64, error) {
+	txTime, timeBytesConsumed, err := util.DecodeOrderPreservingVarUint64(recordBytes)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	blockNum, tranNum, err := decodeBlockNumTranNum(recordBytes[timeBytesConsumed:])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return txTime, blockNum, tranNum, nil
+}
+
 // decodeBlockNumTranNum decodes blockNumTranNumBytes to get blockNum and tranNum.
 func decodeBlockNumTranNum(blockNumTranNumBytes []byte) (uint64, uint64, error) {
 	blockNum, blockBytesConsumed, err := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes)
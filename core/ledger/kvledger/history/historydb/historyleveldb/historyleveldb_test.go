@@ -9,21 +9,29 @@ package historyleveldb
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/capabilities"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb/fakes"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +42,42 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+func TestSetLastSavepoint(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+
+	assert.NoError(t, env.testHistoryDB.SetLastSavepoint(version.NewHeight(10, 3)))
+	savepoint, err := env.testHistoryDB.GetLastSavepoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(10, 3), savepoint)
+
+	status, blockNum, err := env.testHistoryDB.ShouldRecover(10)
+	assert.NoError(t, err)
+	assert.False(t, status)
+	assert.Equal(t, uint64(11), blockNum)
+}
+
+func TestDrop(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testHistoryDBProvider.(*HistoryDBProvider)
+
+	otherHistoryDB, err := provider.GetDBHandle("OtherLedger")
+	assert.NoError(t, err)
+	for _, historyDB := range []historydb.HistoryDB{env.testHistoryDB, otherHistoryDB} {
+		_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+		assert.NoError(t, historyDB.Commit(gb))
+	}
+
+	assert.NoError(t, provider.Drop("TestHistoryDB"))
+	savepoint, err := env.testHistoryDB.GetLastSavepoint()
+	assert.NoError(t, err)
+	assert.Nil(t, savepoint)
+	savepoint, err = otherHistoryDB.GetLastSavepoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(0, 1), savepoint)
+}
+
 //TestSavepoint tests that save points get written after each block and get returned via GetBlockNumfromSavepoint
 func TestSavepoint(t *testing.T) {
 	env := newTestHistoryEnv(t)
@@ -520,6 +564,351 @@ func TestHistoryWithBlockNumber256(t *testing.T) {
 	assert.Equal(t, "value256", valueInBlock256)
 }
 
+func TestHistoryWithOptions(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// add 5 blocks, each block has 2 transactions setting state for "ns1" and "key", value is "value<blockNum>-<tranNum>"
+	for i := 1; i <= 5; i++ {
+		simulationResults := [][]byte{}
+		for j := 0; j < 2; j++ {
+			simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+			simulator.SetState("ns1", "key", []byte(fmt.Sprintf("value%d-%d", i, j)))
+			simulator.Done()
+			simRes, _ := simulator.GetTxSimulationResults()
+			pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+			simulationResults = append(simulationResults, pubSimResBytes)
+		}
+		block := bg.NextBlock(simulationResults)
+		assert.NoError(t, store1.AddBlock(block))
+		assert.NoError(t, env.testHistoryDB.Commit(block))
+	}
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	allValues := []string{"value1-0", "value1-1", "value2-0", "value2-1", "value3-0", "value3-1", "value4-0", "value4-1", "value5-0", "value5-1"}
+	vals, _ := testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", nil)
+	assert.Equal(t, allValues, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 3})
+	assert.Equal(t, []string{"value2-0", "value2-1", "value3-0", "value3-1"}, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 4})
+	assert.Equal(t, []string{"value4-0", "value4-1", "value5-0", "value5-1"}, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{EndBlock: 1, NewestFirst: true})
+	assert.Equal(t, []string{"value1-1", "value1-0"}, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartTime: time.Now().Add(time.Hour)})
+	assert.Empty(t, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartTime: time.Now().Add(-time.Hour), EndTime: time.Now().Add(time.Hour)})
+	assert.Equal(t, allValues, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{EndTime: time.Now().Add(-time.Hour)})
+	assert.Empty(t, vals)
+
+	// paginated queries in both orders
+	for _, newestFirst := range []bool{false, true} {
+		var pages [][]string
+		bookmark := ""
+		for {
+			vals, nextBookmark := testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+				&ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 4, NewestFirst: newestFirst, PageSize: 4, Bookmark: bookmark})
+			if len(vals) == 0 {
+				assert.Equal(t, bookmark, nextBookmark)
+				break
+			}
+			pages = append(pages, vals)
+			bookmark = nextBookmark
+		}
+		if newestFirst {
+			assert.Equal(t, [][]string{{"value4-1", "value4-0", "value3-1", "value3-0"}, {"value2-1", "value2-0"}}, pages)
+		} else {
+			assert.Equal(t, [][]string{{"value2-0", "value2-1", "value3-0", "value3-1"}, {"value4-0", "value4-1"}}, pages)
+		}
+	}
+
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 3, EndBlock: 2})
+	assert.EqualError(t, err, "end block [2] is lower than the start block [3]")
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{PageSize: -1})
+	assert.EqualError(t, err, "page size [-1] must not be negative")
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{Bookmark: "not-a-bookmark"})
+	assert.EqualError(t, err, "invalid bookmark [not-a-bookmark] for the history query")
+}
+
+func TestHistoryWithTimeRange(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// the timestamps of the transactions do not increase with the height: block 1 has the
+	// transactions at minutes 3 and 1, block 2 at minutes 2 and 5 and block 3 at minute 4
+	base := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	minutes := [][]int{{3, 1}, {2, 5}, {4}}
+	for i, blockMinutes := range minutes {
+		simulationResults := [][]byte{}
+		for _, minute := range blockMinutes {
+			simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+			simulator.SetState("ns1", "key", []byte(fmt.Sprintf("minute%d", minute)))
+			simulator.SetState("ns1", "key\x00", []byte(fmt.Sprintf("other%d", minute)))
+			simulator.Done()
+			simRes, _ := simulator.GetTxSimulationResults()
+			pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+			simulationResults = append(simulationResults, pubSimResBytes)
+		}
+		block := bg.NextBlock(simulationResults)
+		for j, minute := range blockMinutes {
+			setTxTimestamp(t, block, j, base.Add(time.Duration(minute)*time.Minute))
+		}
+		assert.NoError(t, store1.AddBlock(block), "Error upon adding block %d", i+1)
+		assert.NoError(t, env.testHistoryDB.Commit(block))
+	}
+
+	countingStore := &countingBlockStore{BlockStore: store1}
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(countingStore)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	// the results are ordered by timestamp and only the transactions in the time range are retrieved
+	vals, _ := testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+		&ledger.HistoryQueryOptions{StartTime: base.Add(2 * time.Minute), EndTime: base.Add(4 * time.Minute)})
+	assert.Equal(t, []string{"minute2", "minute3"}, vals)
+	assert.Equal(t, 2, countingStore.retrievedTxs)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+		&ledger.HistoryQueryOptions{StartTime: base, NewestFirst: true})
+	assert.Equal(t, []string{"minute5", "minute4", "minute3", "minute2", "minute1"}, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+		&ledger.HistoryQueryOptions{EndTime: base.Add(4 * time.Minute), StartBlock: 2})
+	assert.Equal(t, []string{"minute2"}, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key\x00",
+		&ledger.HistoryQueryOptions{StartTime: base.Add(5 * time.Minute)})
+	assert.Equal(t, []string{"other5"}, vals)
+
+	// paginated queries in both orders
+	for _, newestFirst := range []bool{false, true} {
+		var pages [][]string
+		bookmark := ""
+		for {
+			vals, nextBookmark := testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+				&ledger.HistoryQueryOptions{StartTime: base.Add(2 * time.Minute), NewestFirst: newestFirst, PageSize: 3, Bookmark: bookmark})
+			if len(vals) == 0 {
+				assert.Equal(t, bookmark, nextBookmark)
+				break
+			}
+			pages = append(pages, vals)
+			bookmark = nextBookmark
+		}
+		if newestFirst {
+			assert.Equal(t, [][]string{{"minute5", "minute4", "minute3"}, {"minute2"}}, pages)
+		} else {
+			assert.Equal(t, [][]string{{"minute2", "minute3", "minute4"}, {"minute5"}}, pages)
+		}
+	}
+
+	// a bookmark of a query ordered by height cannot resume a query with a time range
+	_, bookmark := testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{PageSize: 1})
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{StartTime: base, Bookmark: bookmark})
+	assert.EqualError(t, err, fmt.Sprintf("invalid bookmark [%s] for the history query", bookmark))
+}
+
+func TestHistoryWithTimeRangeWithoutTimeIndex(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	base := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	commitAtMinute := func(historyDB historydb.HistoryDB, minute int) {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		simulator.SetState("ns1", "key", []byte(fmt.Sprintf("minute%d", minute)))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		setTxTimestamp(t, block, 0, base.Add(time.Duration(minute)*time.Minute))
+		assert.NoError(t, store1.AddBlock(block))
+		assert.NoError(t, historyDB.Commit(block))
+	}
+	commitAtMinute(env.testHistoryDB, 3)
+	commitAtMinute(env.testHistoryDB, 1)
+
+	// remove the time index to get the history database of a peer that predates it
+	db := env.testHistoryDB.(*historyDB).db
+	itr := db.GetIterator(timeIndexKeyPrefix, []byte{timeIndexKeyPrefix[0] + 1})
+	for itr.Next() {
+		assert.NoError(t, db.Delete(append([]byte{}, itr.Key()...), true))
+	}
+	itr.Release()
+	assert.NoError(t, db.Delete(timeIndexStartKey, true))
+
+	reopenedDB, err := env.testHistoryDBProvider.GetDBHandle("TestHistoryDB")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), reopenedDB.(*historyDB).timeIndexStart)
+	commitAtMinute(reopenedDB, 2)
+
+	// the time index start is kept across the restarts
+	reopenedDB, err = env.testHistoryDBProvider.GetDBHandle("TestHistoryDB")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), reopenedDB.(*historyDB).timeIndexStart)
+
+	qhistory, err := reopenedDB.NewHistoryQueryExecutor(store1)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	// the records which are not in the time index are found by scanning the history by height
+	vals, _ := testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+		&ledger.HistoryQueryOptions{StartTime: base.Add(2 * time.Minute)})
+	assert.Equal(t, []string{"minute3", "minute2"}, vals)
+
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+		&ledger.HistoryQueryOptions{EndTime: base.Add(3 * time.Minute), NewestFirst: true})
+	assert.Equal(t, []string{"minute2", "minute1"}, vals)
+
+	// the time index is used for the blocks that it covers
+	vals, _ = testutilRetrieveValuesWithOptions(t, qhistory, "ns1", "key",
+		&ledger.HistoryQueryOptions{StartTime: base, StartBlock: 3})
+	assert.Equal(t, []string{"minute2"}, vals)
+
+	// a new history database covers all the blocks with the time index
+	otherHistoryDB, err := env.testHistoryDBProvider.GetDBHandle("OtherLedger")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), otherHistoryDB.(*historyDB).timeIndexStart)
+}
+
+func TestTimeIndexNanos(t *testing.T) {
+	ts, err := ptypes.TimestampProto(time.Unix(100, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100000000005), timeIndexNanos(ts))
+	assert.Equal(t, uint64(0), timeIndexNanos(nil))
+	assert.Equal(t, uint64(0), timeIndexNanos(&timestamp.Timestamp{Seconds: -100}))
+	assert.Equal(t, uint64(math.MaxInt64), timeIndexNanos(&timestamp.Timestamp{Seconds: math.MaxInt64}))
+	assert.Equal(t, uint64(math.MaxInt64), timeNanos(time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestAccessHistory(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	// the transactions are signed so that the MSP ID of the creator is available
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, true)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	simulate := func(readKey, writeKey string) []byte {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		if readKey != "" {
+			_, err := simulator.GetState("ns1", readKey)
+			assert.NoError(t, err)
+		}
+		if writeKey != "" {
+			assert.NoError(t, simulator.SetState("ns1", writeKey, []byte("value")))
+		}
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		return pubSimResBytes
+	}
+
+	// block1 is committed before the access index is enabled by the channel config
+	block1 := bg.NextBlock([][]byte{simulate("key1", "key1")})
+	assert.NoError(t, store1.AddBlock(block1))
+	assert.NoError(t, env.testHistoryDB.Commit(block1))
+
+	block2 := bg.NextConfigBlock(capabilities.ApplicationKeyAccessIndex)
+	assert.NoError(t, store1.AddBlock(block2))
+	assert.NoError(t, env.testHistoryDB.Commit(block2))
+
+	// the setting of the channel config is restored when the history db is reopened
+	historyDB, err := env.testHistoryDBProvider.GetDBHandle("TestHistoryDB")
+	assert.NoError(t, err)
+
+	// the third transaction of block3 that reads and writes key1 is invalid
+	// and is not added to the history of values
+	block3 := bg.NextBlock([][]byte{simulate("", "key1"), simulate("key1", "key2"), simulate("key1", "key1")})
+	txsFilter := util.TxValidationFlags(block3.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	txsFilter.SetFlag(2, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	block3.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	assert.NoError(t, store1.AddBlock(block3))
+	assert.NoError(t, historyDB.Commit(block3))
+
+	// the access index is disabled again by the channel config
+	block4 := bg.NextConfigBlock()
+	assert.NoError(t, store1.AddBlock(block4))
+	assert.NoError(t, historyDB.Commit(block4))
+	block5 := bg.NextBlock([][]byte{simulate("key1", "key2"), simulate("", "key1")})
+	txsFilter = util.TxValidationFlags(block5.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	txsFilter.SetFlag(0, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	block5.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	assert.NoError(t, store1.AddBlock(block5))
+	assert.NoError(t, historyDB.Commit(block5))
+
+	qhistory, err := historyDB.NewHistoryQueryExecutor(store1)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+	testutilVerifyResults(t, qhistory, "ns1", "key1", []string{"value", "value", "value"})
+	testutilVerifyResults(t, qhistory, "ns1", "key2", []string{"value"})
+
+	accesses, _ := testutilRetrieveAccessesWithOptions(t, qhistory, "ns1", "key1", nil)
+	assert.Equal(t, []string{"3:0:w:VALID", "3:1:r:VALID", "3:2:rw:MVCC_READ_CONFLICT"}, accesses)
+
+	accesses, _ = testutilRetrieveAccessesWithOptions(t, qhistory, "ns1", "key2", nil)
+	assert.Equal(t, []string{"3:1:w:VALID"}, accesses)
+
+	accesses, bookmark := testutilRetrieveAccessesWithOptions(t, qhistory, "ns1", "key1", &ledger.HistoryQueryOptions{NewestFirst: true, PageSize: 2})
+	assert.Equal(t, []string{"3:2:rw:MVCC_READ_CONFLICT", "3:1:r:VALID"}, accesses)
+	accesses, _ = testutilRetrieveAccessesWithOptions(t, qhistory, "ns1", "key1", &ledger.HistoryQueryOptions{NewestFirst: true, PageSize: 2, Bookmark: bookmark})
+	assert.Equal(t, []string{"3:0:w:VALID"}, accesses)
+
+	accesses, _ = testutilRetrieveAccessesWithOptions(t, qhistory, "ns1", "key1", &ledger.HistoryQueryOptions{StartBlock: 4})
+	assert.Empty(t, accesses)
+
+	itr, err := qhistory.GetAccessHistoryForKey("ns1", "key1", nil)
+	assert.NoError(t, err)
+	defer itr.Close()
+	result, err := itr.Next()
+	assert.NoError(t, err)
+	keyAccess := result.(*queryresult.KeyAccess)
+	txID, err := putils.GetOrComputeTxIDFromEnvelope(block3.Data.Data[0])
+	assert.NoError(t, err)
+	assert.Equal(t, txID, keyAccess.TxId)
+	assert.NotNil(t, keyAccess.Timestamp)
+	assert.Equal(t, "SampleOrg", keyAccess.CreatorMspId)
+
+	_, err = qhistory.GetAccessHistoryForKey("ns1", "key1", &ledger.HistoryQueryOptions{StartBlock: 3, EndBlock: 2})
+	assert.EqualError(t, err, "end block [2] is lower than the start block [3]")
+}
+
 func TestName(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
@@ -543,6 +932,73 @@ func testutilVerifyResults(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, ke
 	assert.Equal(t, expectedVals, retrievedVals)
 }
 
+func testutilRetrieveValuesWithOptions(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, key string, options *ledger.HistoryQueryOptions) ([]string, string) {
+	itr, err := hqe.GetHistoryForKeyWithOptions(ns, key, options)
+	assert.NoError(t, err, "Error upon GetHistoryForKeyWithOptions()")
+	retrievedVals := []string{}
+	for {
+		kmod, err := itr.Next()
+		assert.NoError(t, err)
+		if kmod == nil {
+			break
+		}
+		retrievedVals = append(retrievedVals, string(kmod.(*queryresult.KeyModification).Value))
+	}
+	return retrievedVals, itr.GetBookmarkAndClose()
+}
+
+// testutilRetrieveAccessesWithOptions retrieves the access history of a key, each access formatted
+// as <blockNum>:<tranNum>:<r|w|rw>:<validation code>
+func testutilRetrieveAccessesWithOptions(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, key string, options *ledger.HistoryQueryOptions) ([]string, string) {
+	itr, err := hqe.GetAccessHistoryForKey(ns, key, options)
+	assert.NoError(t, err, "Error upon GetAccessHistoryForKey()")
+	retrievedAccesses := []string{}
+	for {
+		result, err := itr.Next()
+		assert.NoError(t, err)
+		if result == nil {
+			break
+		}
+		keyAccess := result.(*queryresult.KeyAccess)
+		accessType := ""
+		if keyAccess.Read {
+			accessType += "r"
+		}
+		if keyAccess.Write {
+			accessType += "w"
+		}
+		retrievedAccesses = append(retrievedAccesses,
+			fmt.Sprintf("%d:%d:%s:%s", keyAccess.BlockNum, keyAccess.TxNum, accessType, keyAccess.ValidationCode))
+	}
+	return retrievedAccesses, itr.GetBookmarkAndClose()
+}
+
+// countingBlockStore counts the transactions retrieved from the block store
+type countingBlockStore struct {
+	blkstorage.BlockStore
+	retrievedTxs int
+}
+
+func (s *countingBlockStore) RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
+	s.retrievedTxs++
+	return s.BlockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+}
+
+// setTxTimestamp sets the timestamp in the channel header of a transaction of a block
+func setTxTimestamp(t *testing.T, block *common.Block, txNum int, txTime time.Time) {
+	envelope, err := putils.UnmarshalEnvelope(block.Data.Data[txNum])
+	assert.NoError(t, err)
+	payload, err := putils.GetPayload(envelope)
+	assert.NoError(t, err)
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err)
+	chdr.Timestamp, err = ptypes.TimestampProto(txTime)
+	assert.NoError(t, err)
+	payload.Header.ChannelHeader = putils.MarshalOrPanic(chdr)
+	envelope.Payload = putils.MarshalOrPanic(payload)
+	block.Data.Data[txNum] = putils.MarshalOrPanic(envelope)
+}
+
 // testutilCheckKeyInRange check if falseKey falls in range query when searching for desiredKey
 func testutilCheckKeyInRange(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, desiredKey, falseKey string, expectedMatchCount int) {
 	itr, err := hqe.GetHistoryForKey(ns, desiredKey)
@@ -46,6 +46,11 @@ type kvLedger struct {
 	blockAPIsRWLock        *sync.RWMutex
 	stats                  *ledgerStats
 	commitHash             []byte
+	versionedDB            privacyenabledstate.DB
+	configHistoryMgr       confighistory.Mgr
+	snapshotRequests       map[uint64]bool
+	snapshotRequestsLock   sync.Mutex
+	recoveryMonitor        *recoveryMonitor
 }
 
 // NewKVLedger constructs new `KVLedger`
@@ -59,11 +64,22 @@ func newKVLedger(
 	bookkeeperProvider bookkeeping.Provider,
 	ccInfoProvider ledger.DeployedChaincodeInfoProvider,
 	stats *ledgerStats,
+	recoveryMonitor *recoveryMonitor,
 ) (*kvLedger, error) {
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{}}
+	l := &kvLedger{
+		ledgerID:         ledgerID,
+		blockStore:       blockStore,
+		historyDB:        historyDB,
+		blockAPIsRWLock:  &sync.RWMutex{},
+		versionedDB:      versionedDB,
+		configHistoryMgr: configHistoryMgr,
+		snapshotRequests: map[uint64]bool{},
+		stats:            stats,
+		recoveryMonitor:  recoveryMonitor,
+	}
 
 	// Retrieves the current commit hash from the blockstore
 	var err error
@@ -90,8 +106,6 @@ func newKVLedger(
 		return nil, err
 	}
 	l.configHistoryRetriever = configHistoryMgr.GetRetriever(ledgerID, l)
-
-	l.stats = stats
 	return l, nil
 }
 
@@ -185,24 +199,29 @@ func (l *kvLedger) syncStateAndHistoryDBWithBlockstore() error {
 	if len(recoverers) == 0 {
 		return nil
 	}
+	if len(recoverers) == 2 && recoverers[0].firstBlockNum > recoverers[1].firstBlockNum {
+		// swap (put the lagger db at 0 index)
+		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
+	}
+	// The recovery commits the blocks one by one and each of the dbs moves its savepoint along with
+	// every block. Hence, a recovery that is interrupted resumes from the last committed block upon
+	// the next start instead of starting over
+	progress := l.recoveryMonitor.start(l.ledgerID, l.stats, recoverers[0].firstBlockNum, lastAvailableBlockNum+1)
+	defer l.recoveryMonitor.done(l.ledgerID)
 	if len(recoverers) == 1 {
-		return l.recommitLostBlocks(recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
+		return l.recommitLostBlocks(progress, recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
 	}
 
 	// both dbs need to be recovered
-	if recoverers[0].firstBlockNum > recoverers[1].firstBlockNum {
-		// swap (put the lagger db at 0 index)
-		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
-	}
 	if recoverers[0].firstBlockNum != recoverers[1].firstBlockNum {
 		// bring the lagger db equal to the other db
-		if err := l.recommitLostBlocks(recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
+		if err := l.recommitLostBlocks(progress, recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
 			recoverers[0].recoverable); err != nil {
 			return err
 		}
 	}
 	// get both the db upto block storage
-	return l.recommitLostBlocks(recoverers[1].firstBlockNum, lastAvailableBlockNum,
+	return l.recommitLostBlocks(progress, recoverers[1].firstBlockNum, lastAvailableBlockNum,
 		recoverers[0].recoverable, recoverers[1].recoverable)
 }
 
@@ -250,8 +269,8 @@ func (l *kvLedger) filterYetToCommitBlocks(blocksPvtData map[uint64][]*ledger.Tx
 }
 
 //recommitLostBlocks retrieves blocks in specified range and commit the write set to either
-//state DB or history DB or both
-func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
+//state DB or history DB or both. The progress is reported after each block
+func (l *kvLedger) recommitLostBlocks(progress *recoveryProgress, firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
 	logger.Infof("Recommitting lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
 	var err error
 	var blockAndPvtdata *ledger.BlockAndPvtData
@@ -264,6 +283,7 @@ func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64,
 				return err
 			}
 		}
+		progress.blockCommitted(blockNumber)
 	}
 	logger.Infof("Recommitted lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
 	return nil
@@ -443,6 +463,7 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData, co
 		elapsedCommitState,
 		txstatsInfo,
 	)
+	l.processSnapshotRequest(blockNo)
 	return nil
 }
 
@@ -7,10 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
-	"bytes"
 	"fmt"
+	"path/filepath"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/confighistory"
@@ -18,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
 	"github.com/hyperledger/fabric/protos/common"
@@ -36,6 +38,8 @@ var (
 
 	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
 	ledgerKeyPrefix            = []byte("l")
+	ledgerKeyStop              = []byte("m")
+	dropInProgressKeyPrefix    = []byte("d")
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -50,6 +54,7 @@ type Provider struct {
 	initializer         *ledger.Initializer
 	collElgNotifier     *collElgNotifier
 	stats               *stats
+	recoveryMonitor     *recoveryMonitor
 	fileLock            *leveldbhelper.FileLock
 }
 
@@ -70,7 +75,7 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 
 	logger.Info("ledger provider Initialized")
 	provider := &Provider{idStore, nil,
-		nil, historydbProvider, nil, nil, nil, nil, nil, nil, fileLock}
+		nil, historydbProvider, nil, nil, nil, nil, nil, nil, nil, fileLock}
 	return provider, nil
 }
 
@@ -98,6 +103,12 @@ func (provider *Provider) Initialize(initializer *ledger.Initializer) error {
 		return err
 	}
 	provider.stats = newStats(initializer.MetricsProvider)
+	provider.recoveryMonitor = newRecoveryMonitor()
+	if initializer.HealthCheckRegistry != nil {
+		if err := initializer.HealthCheckRegistry.RegisterChecker("ledger_recovery", provider.recoveryMonitor); err != nil {
+			return err
+		}
+	}
 	provider.recoverUnderConstructionLedger()
 	return nil
 }
@@ -137,6 +148,77 @@ func (provider *Provider) Create(genesisBlock *common.Block) (ledger.PeerLedger,
 	return lgr, nil
 }
 
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider
+// Similar to the function `Create`, this function sets the under construction flag before importing the
+// snapshot. The block store is bootstrapped last so that, upon a crash, the function
+// 'recoverUnderConstructionLedger' can determine whether the import was completed
+func (provider *Provider) CreateFromSnapshot(snapshotDir string) (ledger.PeerLedger, string, error) {
+	metadata, err := loadSnapshotMetadata(snapshotDir)
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "error while loading the snapshot metadata")
+	}
+	ledgerID := metadata.ChannelName
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if exists {
+		return nil, "", ErrLedgerIDExists
+	}
+	blkstoreSnapshotInfo, err := loadBoundaryBlocksFromSnapshot(snapshotDir, metadata)
+	if err != nil {
+		return nil, "", err
+	}
+	if err = provider.idStore.setUnderConstructionFlag(ledgerID); err != nil {
+		return nil, "", err
+	}
+	lgr, err := provider.importSnapshot(ledgerID, snapshotDir, blkstoreSnapshotInfo)
+	if err != nil {
+		logger.Errorf("Error creating the ledger [%s] from the snapshot. Unsetting under construction flag. Error: %+v", ledgerID, err)
+		panicOnErr(provider.runCleanup(ledgerID), "Error running cleanup for ledger id [%s]", ledgerID)
+		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
+		return nil, "", err
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, blkstoreSnapshotInfo.LastConfigBlock), "Error while marking ledger as created")
+	logger.Infof("Created ledger [%s] from the snapshot at block number [%d]", ledgerID, metadata.LastBlockNumber)
+	return lgr, ledgerID, nil
+}
+
+func (provider *Provider) importSnapshot(ledgerID, snapshotDir string, blkstoreSnapshotInfo *blkstorage.SnapshotInfo) (ledger.PeerLedger, error) {
+	lastBlock := blkstoreSnapshotInfo.LastBlock
+	savepoint := version.NewHeight(lastBlock.Header.Number, uint64(len(lastBlock.Data.Data)-1))
+
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := importStateFromSnapshot(snapshotDir, vDB, savepoint); err != nil {
+		return nil, errors.WithMessage(err, "error while importing the state from the snapshot")
+	}
+	historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := historyDB.SetLastSavepoint(savepoint); err != nil {
+		return nil, err
+	}
+	if err := importConfigHistoryFromSnapshot(snapshotDir, ledgerID, provider.configHistoryMgr.ImportConfigHistory); err != nil {
+		return nil, errors.WithMessage(err, "error while importing the config history from the snapshot")
+	}
+
+	txIDsReader, err := newSnapshotFileReader(filepath.Join(snapshotDir, snapshotTxIDsFileName))
+	if err != nil {
+		return nil, err
+	}
+	defer txIDsReader.close()
+	blockStore, err := provider.ledgerStoreProvider.BootstrapFromSnapshot(ledgerID, blkstoreSnapshotInfo, &snapshotTxIDsIterator{txIDsReader})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while bootstrapping the block store from the snapshot")
+	}
+	blockStore.Shutdown()
+	return provider.openInternal(ledgerID)
+}
+
 // Open implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	logger.Debugf("Open() opening kvledger: %s", ledgerID)
@@ -152,6 +234,15 @@ func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 }
 
 func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, error) {
+	dropInProgress, err := provider.idStore.isDropInProgress(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if dropInProgress {
+		return nil, errors.Errorf("the databases of the ledger [%s] are partially dropped as the command 'peer node rebuild-dbs'"+
+			" did not complete, rerun the command before starting the peer", ledgerID)
+	}
+
 	// Get the block store for a chain/ledger
 	blockStore, err := provider.ledgerStoreProvider.Open(ledgerID)
 	if err != nil {
@@ -178,6 +269,7 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 		provider.stateListeners, provider.bookkeepingProvider,
 		provider.initializer.DeployedChaincodeInfoProvider,
 		provider.stats.ledgerStats(ledgerID),
+		provider.recoveryMonitor,
 	)
 	if err != nil {
 		return nil, err
@@ -236,13 +328,33 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 		panicOnErr(err, "Error while retrieving genesis block from blockchain for ledger [%s]", ledgerID)
 		panicOnErr(provider.idStore.createLedgerID(ledgerID, genesisBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
 	default:
-		panic(errors.Errorf(
-			"data inconsistency: under construction flag is set for ledger [%s] while the height of the blockchain is [%d]",
-			ledgerID, bcInfo.Height))
+		bootstrapped, err := ledgerstorage.IsBootstrappedFromSnapshot(ledgerID)
+		panicOnErr(err, "Error while checking whether the ledger [%s] was bootstrapped from a snapshot", ledgerID)
+		if !bootstrapped {
+			panic(errors.Errorf(
+				"data inconsistency: under construction flag is set for ledger [%s] while the height of the blockchain is [%d]",
+				ledgerID, bcInfo.Height))
+		}
+		logger.Infof("Ledger was bootstrapped from a snapshot. Hence, marking the peer ledger as created")
+		configBlock, err := lastConfigBlock(ledger, bcInfo.Height-1)
+		panicOnErr(err, "Error while retrieving last config block from blockchain for ledger [%s]", ledgerID)
+		panicOnErr(provider.idStore.createLedgerID(ledgerID, configBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
 	}
 	return
 }
 
+func lastConfigBlock(l ledger.PeerLedger, lastBlockNum uint64) (*common.Block, error) {
+	lastBlock, err := l.GetBlockByNumber(lastBlockNum)
+	if err != nil {
+		return nil, err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	return l.GetBlockByNumber(lastConfigBlockNum)
+}
+
 // runCleanup cleans up blockstorage, statedb, and historydb for what
 // may have got created during in-complete ledger creation
 func (provider *Provider) runCleanup(ledgerID string) error {
@@ -262,9 +374,9 @@ func panicOnErr(err error, mgsFormat string, args ...interface{}) {
 	panic(fmt.Sprintf(mgsFormat+" Error: %s", args...))
 }
 
-//////////////////////////////////////////////////////////////////////
+// ////////////////////////////////////////////////////////////////////
 // Ledger id persistence related code
-///////////////////////////////////////////////////////////////////////
+// /////////////////////////////////////////////////////////////////////
 type idStore struct {
 	db *leveldbhelper.DB
 }
@@ -322,20 +434,37 @@ func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
 
 func (s *idStore) getAllLedgerIds() ([]string, error) {
 	var ids []string
-	itr := s.db.GetIterator(nil, nil)
+	// the iteration is limited to the ledger keys as the store contains the flags as well
+	itr := s.db.GetIterator(ledgerKeyPrefix, ledgerKeyStop)
 	defer itr.Release()
-	itr.First()
-	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
-			continue
-		}
+	for itr.Next() {
 		id := string(s.decodeLedgerID(itr.Key()))
 		ids = append(ids, id)
-		itr.Next()
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrap(err, "error while iterating over the ledger ids")
 	}
 	return ids, nil
 }
 
+// setDropInProgressFlag marks that the databases of the ledger are being dropped so that the ledger
+// is not opened with partially dropped databases
+func (s *idStore) setDropInProgressFlag(ledgerID string) error {
+	return s.db.Put(s.encodeDropInProgressKey(ledgerID), []byte{1}, true)
+}
+
+func (s *idStore) unsetDropInProgressFlag(ledgerID string) error {
+	return s.db.Delete(s.encodeDropInProgressKey(ledgerID), true)
+}
+
+func (s *idStore) isDropInProgress(ledgerID string) (bool, error) {
+	val, err := s.db.Get(s.encodeDropInProgressKey(ledgerID))
+	if err != nil {
+		return false, err
+	}
+	return val != nil, nil
+}
+
 func (s *idStore) close() {
 	s.db.Close()
 }
@@ -344,6 +473,10 @@ func (s *idStore) encodeLedgerKey(ledgerID string) []byte {
 	return append(ledgerKeyPrefix, []byte(ledgerID)...)
 }
 
+func (s *idStore) encodeDropInProgressKey(ledgerID string) []byte {
+	return append(append([]byte{}, dropInProgressKeyPrefix...), []byte(ledgerID)...)
+}
+
 func (s *idStore) decodeLedgerID(key []byte) string {
 	return string(key[len(ledgerKeyPrefix):])
 }
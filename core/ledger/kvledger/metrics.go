@@ -17,6 +17,8 @@ type stats struct {
 	blockAndPvtdataStoreCommitTime metrics.Histogram
 	statedbCommitTime              metrics.Histogram
 	transactionsCount              metrics.Counter
+	rebuildTargetHeight            metrics.Gauge
+	rebuildHeight                  metrics.Gauge
 }
 
 func newStats(metricsProvider metrics.Provider) *stats {
@@ -25,6 +27,8 @@ func newStats(metricsProvider metrics.Provider) *stats {
 	stats.blockAndPvtdataStoreCommitTime = metricsProvider.NewHistogram(blockAndPvtdataStoreCommitTimeOpts)
 	stats.statedbCommitTime = metricsProvider.NewHistogram(statedbCommitTimeOpts)
 	stats.transactionsCount = metricsProvider.NewCounter(transactionCountOpts)
+	stats.rebuildTargetHeight = metricsProvider.NewGauge(rebuildTargetHeightOpts)
+	stats.rebuildHeight = metricsProvider.NewGauge(rebuildHeightOpts)
 	return stats
 }
 
@@ -51,6 +55,14 @@ func (s *ledgerStats) updateStatedbCommitTime(timeTaken time.Duration) {
 	s.stats.statedbCommitTime.With("channel", s.ledgerid).Observe(timeTaken.Seconds())
 }
 
+func (s *ledgerStats) updateRebuildTargetHeight(height uint64) {
+	s.stats.rebuildTargetHeight.With("channel", s.ledgerid).Set(float64(height))
+}
+
+func (s *ledgerStats) updateRebuildHeight(height uint64) {
+	s.stats.rebuildHeight.With("channel", s.ledgerid).Set(float64(height))
+}
+
 func (s *ledgerStats) updateTransactionsStats(
 	txstatsInfo []*txmgr.TxStatInfo,
 ) {
@@ -113,4 +125,22 @@ var (
 		LabelNames:   []string{"channel", "transaction_type", "chaincode", "validation_code"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{transaction_type}.%{chaincode}.%{validation_code}",
 	}
+
+	rebuildTargetHeightOpts = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "dbs_rebuild_target_height",
+		Help:         "Height of the block store up to which the state and history databases are being rebuilt at the peer start.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	rebuildHeightOpts = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "dbs_rebuild_height",
+		Help:         "Height up to which the state and history databases have been rebuilt at the peer start.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
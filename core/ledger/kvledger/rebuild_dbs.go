@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/ledger/confighistory"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	"github.com/pkg/errors"
+)
+
+// RebuildDBs drops the state database, the history database, the config history, and the bookkeeping
+// of the given ledger. Unlike the reset and the rollback, the databases of the other ledgers are not
+// touched. The dropped databases are rebuilt from the block store when the peer starts next; the rebuild
+// commits the blocks one by one and records the progress in the savepoints, so a peer that stops midway
+// resumes the rebuild from the last committed block. If this function fails midway, the ledger is marked
+// so that the peer refuses to open it until the function is invoked again and completes
+func RebuildDBs(ledgerID string) error {
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer idStore.close()
+	exists, err := idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("ledger [%s] does not exist", ledgerID)
+	}
+	bootstrapped, err := ledgerstorage.IsBootstrappedFromSnapshot(ledgerID)
+	if err != nil {
+		return err
+	}
+	if bootstrapped {
+		return errors.Errorf("ledger [%s] is bootstrapped from a snapshot, hence its databases cannot be rebuilt from the block store", ledgerID)
+	}
+
+	vdbProvider, err := statedb.NewVersionedDBProvider(ledgerconfig.GetStateDatabase(), &disabled.Provider{})
+	if err != nil {
+		return err
+	}
+	defer vdbProvider.Close()
+	droppable, ok := vdbProvider.(statedb.Droppable)
+	if !ok {
+		return errors.Errorf("the state database [%s] does not support dropping the data of a single channel,"+
+			" use the command 'peer node reset' and drop the state database manually instead", ledgerconfig.GetStateDatabase())
+	}
+
+	if err := idStore.setDropInProgressFlag(ledgerID); err != nil {
+		return err
+	}
+	if err := dropLedgerDBs(ledgerID, droppable); err != nil {
+		return err
+	}
+	if err := idStore.unsetDropInProgressFlag(ledgerID); err != nil {
+		return err
+	}
+	logger.Infof("The databases of the channel [%s] have been dropped and are rebuilt upon the next peer start", ledgerID)
+	return nil
+}
+
+// dropLedgerDBs drops the databases of a single ledger in the same order as the function `dropDBs`,
+// i.e., the state database first followed by the config history, the bookkeeping, and the history database
+func dropLedgerDBs(ledgerID string, stateDB statedb.Droppable) error {
+	logger.Infof("Dropping the state database of the channel [%s]", ledgerID)
+	if err := stateDB.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error dropping the state database")
+	}
+
+	logger.Infof("Dropping the config history of the channel [%s]", ledgerID)
+	configHistoryMgr := confighistory.NewMgr(nil)
+	defer configHistoryMgr.Close()
+	if err := configHistoryMgr.DropConfigHistory(ledgerID); err != nil {
+		return errors.WithMessage(err, "error dropping the config history")
+	}
+
+	logger.Infof("Dropping the bookkeeping of the channel [%s]", ledgerID)
+	bookkeepingProvider := bookkeeping.NewProvider()
+	defer bookkeepingProvider.Close()
+	if err := bookkeepingProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error dropping the bookkeeping")
+	}
+
+	logger.Infof("Dropping the history database of the channel [%s]", ledgerID)
+	historydbProvider := historyleveldb.NewHistoryDBProvider()
+	defer historydbProvider.Close()
+	if err := historydbProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error dropping the history database")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildDBs(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProviderWithCollectionConfig(t, "ns", map[string]uint64{"coll": 0})
+	ccInfoProvider := provider.(*Provider).initializer.DeployedChaincodeInfoProvider
+
+	blocks := map[string]*lgr.BlockAndPvtData{}
+	for _, ledgerID := range []string{"ledger1", "ledger2"} {
+		bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+		l, err := provider.Create(gb)
+		require.NoError(t, err)
+		blocks[ledgerID] = prepareNextBlockForTest(t, l, bg, "SimulateForBlk1",
+			map[string]string{"key1": "value1.1", "key2": "value2.1"},
+			map[string]string{"key1": "pvtValue1.1", "key2": "pvtValue2.1"})
+		require.NoError(t, l.CommitWithPvtData(blocks[ledgerID], &lgr.CommitOptions{}))
+		l.Close()
+	}
+	provider.Close()
+
+	assert.EqualError(t, RebuildDBs("ledger3"), "ledger [ledger3] does not exist")
+	require.NoError(t, RebuildDBs("ledger1"))
+
+	// only the dropped ledger is rebuilt upon the next start
+	metricsProvider := testutilConstructMetricProvider()
+	rebuildHeightGauge := testutilConstructGauge()
+	metricsProvider.fakeProvider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
+		if opts.Name == rebuildHeightOpts.Name {
+			return rebuildHeightGauge
+		}
+		return testutilConstructGauge()
+	}
+	healthCheckRegistry := &mock.HealthCheckRegistry{}
+	provider, err := NewProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+	require.NoError(t, provider.Initialize(&lgr.Initializer{
+		DeployedChaincodeInfoProvider: ccInfoProvider,
+		MetricsProvider:               metricsProvider.fakeProvider,
+		HealthCheckRegistry:           healthCheckRegistry,
+	}))
+	require.Equal(t, 1, healthCheckRegistry.RegisterCheckerCallCount())
+	component, checker := healthCheckRegistry.RegisterCheckerArgsForCall(0)
+	assert.Equal(t, "ledger_recovery", component)
+	assert.Equal(t, provider.(*Provider).recoveryMonitor, checker)
+
+	for _, ledgerID := range []string{"ledger1", "ledger2"} {
+		l, err := provider.Open(ledgerID)
+		require.NoError(t, err)
+		defer l.Close()
+		checkBCSummaryForTest(t, l,
+			&bcSummary{
+				bcInfo: &common.BlockchainInfo{Height: 2,
+					CurrentBlockHash:  blocks[ledgerID].Block.Header.Hash(),
+					PreviousBlockHash: blocks[ledgerID].Block.Header.PreviousHash},
+				stateDBSavePoint:   uint64(1),
+				stateDBKVs:         map[string]string{"key1": "value1.1", "key2": "value2.1"},
+				stateDBPvtKVs:      map[string]string{"key1": "pvtValue1.1", "key2": "pvtValue2.1"},
+				historyDBSavePoint: uint64(1),
+				historyKey:         "key1",
+				historyVals:        []string{"value1.1"},
+			},
+		)
+	}
+	require.Equal(t, 3, rebuildHeightGauge.SetCallCount())
+	for i, expectedHeight := range []float64{0, 1, 2} {
+		assert.Equal(t, []string{"channel", "ledger1"}, rebuildHeightGauge.WithArgsForCall(i))
+		assert.Equal(t, expectedHeight, rebuildHeightGauge.SetArgsForCall(i))
+	}
+	assert.NoError(t, provider.(*Provider).recoveryMonitor.HealthCheck(context.Background()))
+}
+
+func TestOpenWithDropInProgress(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	_, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	l.Close()
+	provider.Close()
+
+	// simulate a crash of the command 'peer node rebuild-dbs' while dropping the databases
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	require.NoError(t, idStore.setDropInProgressFlag("ledger1"))
+	idStore.close()
+
+	provider = testutilNewProvider(t)
+	ledgerIDs, err := provider.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ledger1"}, ledgerIDs)
+	_, err = provider.Open("ledger1")
+	assert.EqualError(t, err, "the databases of the ledger [ledger1] are partially dropped as the command 'peer node rebuild-dbs'"+
+		" did not complete, rerun the command before starting the peer")
+	provider.Close()
+
+	require.NoError(t, RebuildDBs("ledger1"))
+	provider = testutilNewProvider(t)
+	defer provider.Close()
+	l, err = provider.Open("ledger1")
+	require.NoError(t, err)
+	defer l.Close()
+	checkBCSummaryForTest(t, l, &bcSummary{bcInfo: &common.BlockchainInfo{Height: 1, CurrentBlockHash: gb.Header.Hash()}})
+}
@@ -16,7 +16,20 @@ limitations under the License.
 
 package kvledger
 
-import "github.com/hyperledger/fabric/core/ledger"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// recoveryProgressLogInterval is the minimum interval between two log messages that report the progress of a recovery
+var recoveryProgressLogInterval = 30 * time.Second
 
 type recoverable interface {
 	// ShouldRecover return whether recovery is need.
@@ -33,3 +46,118 @@ type recoverer struct {
 	firstBlockNum uint64
 	recoverable   recoverable
 }
+
+// recoveryMonitor keeps track of the ledgers whose state and history databases are being recovered
+// from the block store, which includes the rebuild of the databases after these are dropped. The
+// monitor implements the interface healthz.HealthChecker and reports a failed check for as long as a
+// recovery is in progress, because the peer does not serve any channel before all the ledgers are opened
+type recoveryMonitor struct {
+	lock       sync.Mutex
+	recoveries map[string]*recoveryProgress
+}
+
+func newRecoveryMonitor() *recoveryMonitor {
+	return &recoveryMonitor{recoveries: map[string]*recoveryProgress{}}
+}
+
+// HealthCheck implements the function in the interface healthz.HealthChecker
+func (m *recoveryMonitor) HealthCheck(ctx context.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(m.recoveries) == 0 {
+		return nil
+	}
+	var ledgerIDs []string
+	for ledgerID := range m.recoveries {
+		ledgerIDs = append(ledgerIDs, ledgerID)
+	}
+	sort.Strings(ledgerIDs)
+	var statuses []string
+	for _, ledgerID := range ledgerIDs {
+		statuses = append(statuses, m.recoveries[ledgerID].String())
+	}
+	return errors.Errorf("the databases of the channels are being recovered from the block store: %s", strings.Join(statuses, ", "))
+}
+
+// start records the beginning of a recovery that commits the blocks from firstBlockNum up to the
+// block store height. The function `done` is expected to be invoked once the recovery ends
+func (m *recoveryMonitor) start(ledgerID string, stats *ledgerStats, firstBlockNum, targetHeight uint64) *recoveryProgress {
+	now := time.Now()
+	p := &recoveryProgress{
+		ledgerID:      ledgerID,
+		stats:         stats,
+		firstBlockNum: firstBlockNum,
+		targetHeight:  targetHeight,
+		height:        firstBlockNum,
+		startTime:     now,
+		lastLogTime:   now,
+	}
+	if firstBlockNum == 0 {
+		logger.Infof("Rebuilding the databases of the channel [%s] from the block store up to the height [%d]",
+			ledgerID, targetHeight)
+	} else {
+		logger.Infof("Recovering the databases of the channel [%s] from the block [%d] up to the height [%d]",
+			ledgerID, firstBlockNum, targetHeight)
+	}
+	stats.updateRebuildTargetHeight(targetHeight)
+	stats.updateRebuildHeight(firstBlockNum)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.recoveries[ledgerID] = p
+	return p
+}
+
+func (m *recoveryMonitor) done(ledgerID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.recoveries, ledgerID)
+}
+
+// recoveryProgress tracks the recovery of a single ledger. The progress is published as the metrics
+// `dbs_rebuild_target_height` and `dbs_rebuild_height` and is logged periodically along with an
+// estimate of the remaining time based on the rate at which the blocks have been committed so far
+type recoveryProgress struct {
+	ledgerID      string
+	stats         *ledgerStats
+	firstBlockNum uint64
+	targetHeight  uint64
+	startTime     time.Time
+
+	lock        sync.Mutex
+	height      uint64
+	lastLogTime time.Time
+}
+
+// blockCommitted records that the databases have been recovered up to the given block
+func (p *recoveryProgress) blockCommitted(blockNum uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.height = blockNum + 1
+	p.stats.updateRebuildHeight(p.height)
+	now := time.Now()
+	if now.Sub(p.lastLogTime) < recoveryProgressLogInterval && p.height < p.targetHeight {
+		return
+	}
+	p.lastLogTime = now
+	logger.Infof("Recovering the databases of the channel [%s]: %s", p.ledgerID, p.status(now))
+}
+
+// String returns the progress of the recovery in a human readable form
+func (p *recoveryProgress) String() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return fmt.Sprintf("[%s] %s", p.ledgerID, p.status(time.Now()))
+}
+
+func (p *recoveryProgress) status(now time.Time) string {
+	status := fmt.Sprintf("height [%d] of [%d]", p.height, p.targetHeight)
+	committed := p.height - p.firstBlockNum
+	elapsed := now.Sub(p.startTime)
+	if committed == 0 || elapsed <= 0 {
+		return status
+	}
+	rate := float64(committed) / elapsed.Seconds()
+	remaining := time.Duration(float64(p.targetHeight-p.height) / rate * float64(time.Second))
+	return fmt.Sprintf("%s, %.1f blocks/s, estimated time remaining %s", status, rate, remaining.Round(time.Second))
+}
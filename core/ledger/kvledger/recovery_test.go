@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryMonitor(t *testing.T) {
+	stats := newStats(&disabled.Provider{})
+	m := newRecoveryMonitor()
+	assert.NoError(t, m.HealthCheck(context.Background()))
+
+	p1 := m.start("ledger1", stats.ledgerStats("ledger1"), 0, 100)
+	p2 := m.start("ledger2", stats.ledgerStats("ledger2"), 40, 50)
+	assert.EqualError(t, m.HealthCheck(context.Background()),
+		"the databases of the channels are being recovered from the block store: "+
+			"[ledger1] height [0] of [100], [ledger2] height [40] of [50]")
+
+	// pretend that the recovery started ten seconds ago
+	p1.startTime = time.Now().Add(-10 * time.Second)
+	for blockNum := uint64(0); blockNum < 20; blockNum++ {
+		p1.blockCommitted(blockNum)
+	}
+	assert.Regexp(t, `^\[ledger1\] height \[20\] of \[100\], 2\.0 blocks/s, estimated time remaining 40s$`, p1.String())
+
+	p2.blockCommitted(49)
+	m.done("ledger2")
+	assert.Regexp(t, `^the databases of the channels are being recovered from the block store: \[ledger1\] height \[20\] of \[100\]`,
+		m.HealthCheck(context.Background()).Error())
+	m.done("ledger1")
+	assert.NoError(t, m.HealthCheck(context.Background()))
+}
@@ -0,0 +1,648 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	snapshotSignableMetadataFileName   = "_snapshot_signable_metadata.json"
+	snapshotAdditionalMetadataFileName = "_snapshot_additional_metadata.json"
+	snapshotPubStateFileName           = "public_state.data"
+	snapshotPvtStateHashesFileName     = "private_state_hashes.data"
+	snapshotTxIDsFileName              = "txids.data"
+	snapshotConfigHistoryFileName      = "confighistory.data"
+	snapshotBoundaryBlocksFileName     = "boundary_blocks.data"
+
+	snapshotsTempDirName      = "temp"
+	snapshotsCompletedDirName = "completed"
+
+	maxKeysInSnapshotImportBatch = 1000
+)
+
+// snapshotSignableMetadata is the part of the snapshot metadata that the operators of the peers are expected
+// to compare (or sign) across organizations before using a snapshot for bootstrapping a new ledger
+type snapshotSignableMetadata struct {
+	ChannelName            string            `json:"channel_name"`
+	LastBlockNumber        uint64            `json:"last_block_number"`
+	LastBlockHashInHex     string            `json:"last_block_hash"`
+	PreviousBlockHashInHex string            `json:"previous_block_hash"`
+	FilesAndHashes         map[string]string `json:"snapshot_files_raw_hashes"`
+}
+
+// snapshotAdditionalMetadata contains the hash of the signable metadata file, which covers the entire snapshot
+type snapshotAdditionalMetadata struct {
+	SnapshotHashInHex string `json:"snapshot_hash"`
+}
+
+// SnapshotsCompletedDir returns the directory under which the completed snapshots of the given ledger are placed
+func SnapshotsCompletedDir(ledgerID string) string {
+	return filepath.Join(ledgerconfig.GetSnapshotsRootDir(), snapshotsCompletedDirName, ledgerID)
+}
+
+// ExportSnapshot implements the corresponding function in interface ledger.PeerLedger
+func (l *kvLedger) ExportSnapshot() (*ledger.SnapshotInfo, error) {
+	l.blockAPIsRWLock.RLock()
+	defer l.blockAPIsRWLock.RUnlock()
+	return l.generateSnapshot()
+}
+
+// SubmitSnapshotRequest implements the corresponding function in interface ledger.PeerLedger
+func (l *kvLedger) SubmitSnapshotRequest(blockNum uint64) error {
+	bcInfo, err := l.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	l.snapshotRequestsLock.Lock()
+	defer l.snapshotRequestsLock.Unlock()
+	if bcInfo.Height > 0 && blockNum <= bcInfo.Height-1 {
+		return errors.Errorf("requested snapshot for block number [%d] cannot be less than or equal to the last committed block number [%d]",
+			blockNum, bcInfo.Height-1)
+	}
+	if l.snapshotRequests[blockNum] {
+		return errors.Errorf("a snapshot request for block number [%d] already exists", blockNum)
+	}
+	l.snapshotRequests[blockNum] = true
+	logger.Infof("[%s] Submitted snapshot request for block number [%d]", l.ledgerID, blockNum)
+	return nil
+}
+
+// PendingSnapshotRequests implements the corresponding function in interface ledger.PeerLedger
+func (l *kvLedger) PendingSnapshotRequests() ([]uint64, error) {
+	l.snapshotRequestsLock.Lock()
+	defer l.snapshotRequestsLock.Unlock()
+	blockNums := []uint64{}
+	for blockNum := range l.snapshotRequests {
+		blockNums = append(blockNums, blockNum)
+	}
+	sort.Slice(blockNums, func(i, j int) bool { return blockNums[i] < blockNums[j] })
+	return blockNums, nil
+}
+
+// processSnapshotRequest generates the snapshot if a request is pending for the given block number.
+// This is expected to be invoked after the block is committed and while the lock `blockAPIsRWLock` is held
+func (l *kvLedger) processSnapshotRequest(blockNum uint64) {
+	l.snapshotRequestsLock.Lock()
+	requested := l.snapshotRequests[blockNum]
+	delete(l.snapshotRequests, blockNum)
+	l.snapshotRequestsLock.Unlock()
+	if !requested {
+		return
+	}
+	if _, err := l.generateSnapshot(); err != nil {
+		logger.Errorf("[%s] Error while generating the snapshot for block number [%d]: %+v", l.ledgerID, blockNum, err)
+	}
+}
+
+// generateSnapshot writes the snapshot files in a temporary directory and, upon completion, moves the directory
+// to the location `<snapshotsRootDir>/completed/<ledgerID>/<lastBlockNumber>`. The caller is expected to hold
+// the lock `blockAPIsRWLock` so that no block gets committed while the snapshot is being generated
+func (l *kvLedger) generateSnapshot() (*ledger.SnapshotInfo, error) {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if bcInfo.Height == 0 {
+		return nil, errors.Errorf("cannot generate a snapshot of the ledger [%s] as it is empty", l.ledgerID)
+	}
+	lastBlockNum := bcInfo.Height - 1
+	completedDir := filepath.Join(SnapshotsCompletedDir(l.ledgerID), strconv.FormatUint(lastBlockNum, 10))
+	exists, _, err := util.FileExists(completedDir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while checking for an existing snapshot")
+	}
+	if exists {
+		return nil, errors.Errorf("snapshot for the ledger [%s] at block number [%d] already exists at [%s]",
+			l.ledgerID, lastBlockNum, completedDir)
+	}
+
+	tempRootDir := filepath.Join(ledgerconfig.GetSnapshotsRootDir(), snapshotsTempDirName)
+	if _, err := util.CreateDirIfMissing(tempRootDir); err != nil {
+		return nil, errors.Wrapf(err, "error while creating the snapshots temp dir [%s]", tempRootDir)
+	}
+	tempDir, err := ioutil.TempDir(tempRootDir, l.ledgerID+"-"+strconv.FormatUint(lastBlockNum, 10)+"-")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while creating a temp dir in [%s]", tempRootDir)
+	}
+	defer os.RemoveAll(tempDir)
+
+	logger.Infof("[%s] Generating snapshot at block number [%d]", l.ledgerID, lastBlockNum)
+	filesAndHashes := map[string]string{}
+	exporters := []func(dir string, filesAndHashes map[string]string) error{
+		l.exportPubStateAndPvtStateHashes,
+		l.exportTxIDs,
+		l.exportConfigHistory,
+		l.exportBoundaryBlocks,
+	}
+	for _, export := range exporters {
+		if err := export(tempDir, filesAndHashes); err != nil {
+			return nil, err
+		}
+	}
+
+	signableMetadata := &snapshotSignableMetadata{
+		ChannelName:            l.ledgerID,
+		LastBlockNumber:        lastBlockNum,
+		LastBlockHashInHex:     hex.EncodeToString(bcInfo.CurrentBlockHash),
+		PreviousBlockHashInHex: hex.EncodeToString(bcInfo.PreviousBlockHash),
+		FilesAndHashes:         filesAndHashes,
+	}
+	snapshotHash, err := writeSnapshotMetadata(tempDir, signableMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := util.CreateDirIfMissing(filepath.Dir(completedDir)); err != nil {
+		return nil, errors.Wrapf(err, "error while creating the dir [%s]", filepath.Dir(completedDir))
+	}
+	if err := os.Rename(tempDir, completedDir); err != nil {
+		return nil, errors.Wrapf(err, "error while renaming the snapshot dir [%s] to [%s]", tempDir, completedDir)
+	}
+	if err := syncDir(filepath.Dir(completedDir)); err != nil {
+		return nil, err
+	}
+	logger.Infof("[%s] Generated snapshot at block number [%d] in dir [%s]", l.ledgerID, lastBlockNum, completedDir)
+	return &ledger.SnapshotInfo{
+		LedgerID:          l.ledgerID,
+		LastBlockNum:      lastBlockNum,
+		LastBlockHash:     bcInfo.CurrentBlockHash,
+		PreviousBlockHash: bcInfo.PreviousBlockHash,
+		SnapshotHash:      snapshotHash,
+		Dir:               completedDir,
+	}, nil
+}
+
+func (l *kvLedger) exportPubStateAndPvtStateHashes(dir string, filesAndHashes map[string]string) error {
+	pubStateWriter, err := newSnapshotFileWriter(filepath.Join(dir, snapshotPubStateFileName))
+	if err != nil {
+		return err
+	}
+	defer pubStateWriter.close()
+	pvtStateHashesWriter, err := newSnapshotFileWriter(filepath.Join(dir, snapshotPvtStateHashesFileName))
+	if err != nil {
+		return err
+	}
+	defer pvtStateHashesWriter.close()
+
+	err = l.versionedDB.ExportPubStateAndPvtStateHashes(
+		func(kv *statedb.VersionedKV) error {
+			return pubStateWriter.addRecord(
+				[]byte(kv.Namespace), []byte(kv.Key), kv.Value, kv.Metadata, kv.Version.ToBytes(),
+			)
+		},
+		func(hashedKey *privacyenabledstate.HashedCompositeKey, vv *statedb.VersionedValue) error {
+			return pvtStateHashesWriter.addRecord(
+				[]byte(hashedKey.Namespace), []byte(hashedKey.CollectionName), []byte(hashedKey.KeyHash),
+				vv.Value, vv.Metadata, vv.Version.ToBytes(),
+			)
+		},
+	)
+	if err != nil {
+		return errors.WithMessage(err, "error while exporting the state")
+	}
+	if filesAndHashes[snapshotPubStateFileName], err = pubStateWriter.done(); err != nil {
+		return err
+	}
+	filesAndHashes[snapshotPvtStateHashesFileName], err = pvtStateHashesWriter.done()
+	return err
+}
+
+func (l *kvLedger) exportTxIDs(dir string, filesAndHashes map[string]string) error {
+	writer, err := newSnapshotFileWriter(filepath.Join(dir, snapshotTxIDsFileName))
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+	err = l.blockStore.ExportTxIDs(func(txIDInfo *blkstorage.TxIDInfo) error {
+		return writer.addRecord([]byte(txIDInfo.TxID), proto.EncodeVarint(uint64(txIDInfo.ValidationCode)))
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error while exporting the txids")
+	}
+	filesAndHashes[snapshotTxIDsFileName], err = writer.done()
+	return err
+}
+
+func (l *kvLedger) exportConfigHistory(dir string, filesAndHashes map[string]string) error {
+	writer, err := newSnapshotFileWriter(filepath.Join(dir, snapshotConfigHistoryFileName))
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+	err = l.configHistoryMgr.ExportConfigHistory(l.ledgerID,
+		func(chaincodeName string, configInfo *ledger.CollectionConfigInfo) error {
+			configBytes, err := proto.Marshal(configInfo.CollectionConfig)
+			if err != nil {
+				return errors.Wrapf(err, "error marshaling the collection config of the chaincode [%s]", chaincodeName)
+			}
+			return writer.addRecord([]byte(chaincodeName), proto.EncodeVarint(configInfo.CommittingBlockNum), configBytes)
+		},
+	)
+	if err != nil {
+		return errors.WithMessage(err, "error while exporting the config history")
+	}
+	filesAndHashes[snapshotConfigHistoryFileName], err = writer.done()
+	return err
+}
+
+// exportBoundaryBlocks exports the last block and the last config block. These are retained by the block
+// store of the ledger that is bootstrapped from this snapshot
+func (l *kvLedger) exportBoundaryBlocks(dir string, filesAndHashes map[string]string) error {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(bcInfo.Height - 1)
+	if err != nil {
+		return err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return err
+	}
+	lastConfigBlock, err := l.blockStore.RetrieveBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return err
+	}
+	writer, err := newSnapshotFileWriter(filepath.Join(dir, snapshotBoundaryBlocksFileName))
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+	for _, block := range []*common.Block{lastBlock, lastConfigBlock} {
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			return errors.Wrapf(err, "error marshaling the block [%d]", block.Header.Number)
+		}
+		if err := writer.addRecord(blockBytes); err != nil {
+			return err
+		}
+	}
+	filesAndHashes[snapshotBoundaryBlocksFileName], err = writer.done()
+	return err
+}
+
+// writeSnapshotMetadata writes the signable metadata file and the additional metadata file that contains
+// the hash of the signable metadata file. The hash is returned, as it represents the entire snapshot
+func writeSnapshotMetadata(dir string, signableMetadata *snapshotSignableMetadata) ([]byte, error) {
+	signableMetadataBytes, err := json.Marshal(signableMetadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling the snapshot signable metadata")
+	}
+	snapshotHash := sha256.Sum256(signableMetadataBytes)
+	additionalMetadataBytes, err := json.Marshal(&snapshotAdditionalMetadata{
+		SnapshotHashInHex: hex.EncodeToString(snapshotHash[:]),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling the snapshot additional metadata")
+	}
+	for fileName, content := range map[string][]byte{
+		snapshotSignableMetadataFileName:   signableMetadataBytes,
+		snapshotAdditionalMetadataFileName: additionalMetadataBytes,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, fileName), content, 0644); err != nil {
+			return nil, errors.Wrapf(err, "error writing the file [%s]", fileName)
+		}
+	}
+	return snapshotHash[:], nil
+}
+
+// loadSnapshotMetadata reads the metadata files from the snapshot dir and verifies the hash of the
+// signable metadata as well as the hash of each of the data files listed in the signable metadata
+func loadSnapshotMetadata(dir string) (*snapshotSignableMetadata, error) {
+	signableMetadataBytes, err := ioutil.ReadFile(filepath.Join(dir, snapshotSignableMetadataFileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading the snapshot signable metadata from dir [%s]", dir)
+	}
+	additionalMetadataBytes, err := ioutil.ReadFile(filepath.Join(dir, snapshotAdditionalMetadataFileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading the snapshot additional metadata from dir [%s]", dir)
+	}
+	signableMetadata := &snapshotSignableMetadata{}
+	if err := json.Unmarshal(signableMetadataBytes, signableMetadata); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the snapshot signable metadata")
+	}
+	additionalMetadata := &snapshotAdditionalMetadata{}
+	if err := json.Unmarshal(additionalMetadataBytes, additionalMetadata); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the snapshot additional metadata")
+	}
+	snapshotHash := sha256.Sum256(signableMetadataBytes)
+	if hex.EncodeToString(snapshotHash[:]) != additionalMetadata.SnapshotHashInHex {
+		return nil, errors.Errorf("snapshot hash mismatch: expected [%s], computed [%x]",
+			additionalMetadata.SnapshotHashInHex, snapshotHash)
+	}
+	if signableMetadata.ChannelName == "" {
+		return nil, errors.New("channel name is missing in the snapshot signable metadata")
+	}
+	for _, fileName := range []string{
+		snapshotPubStateFileName,
+		snapshotPvtStateHashesFileName,
+		snapshotTxIDsFileName,
+		snapshotConfigHistoryFileName,
+		snapshotBoundaryBlocksFileName,
+	} {
+		expectedHash, ok := signableMetadata.FilesAndHashes[fileName]
+		if !ok {
+			return nil, errors.Errorf("hash for the snapshot file [%s] is missing in the snapshot signable metadata", fileName)
+		}
+		computedHash, err := computeFileHash(filepath.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		if computedHash != expectedHash {
+			return nil, errors.Errorf("hash mismatch for the snapshot file [%s]: expected [%s], computed [%s]",
+				fileName, expectedHash, computedHash)
+		}
+	}
+	return signableMetadata, nil
+}
+
+// importStateFromSnapshot loads the public state and the hashes of the private state into the state database
+// and records the savepoint at the last block of the snapshot
+func importStateFromSnapshot(dir string, vdb privacyenabledstate.DB, savepoint *version.Height) error {
+	batch := privacyenabledstate.NewUpdateBatch()
+	numKeysInBatch := 0
+	flushIfFull := func() error {
+		numKeysInBatch++
+		if numKeysInBatch < maxKeysInSnapshotImportBatch {
+			return nil
+		}
+		if err := vdb.ApplyPrivacyAwareUpdates(batch, nil); err != nil {
+			return err
+		}
+		batch = privacyenabledstate.NewUpdateBatch()
+		numKeysInBatch = 0
+		return nil
+	}
+
+	err := readSnapshotFile(filepath.Join(dir, snapshotPubStateFileName), 5, func(fields [][]byte) error {
+		ver, _, err := version.NewHeightFromBytes(fields[4])
+		if err != nil {
+			return errors.Wrap(err, "error decoding the version of a public state key")
+		}
+		batch.PubUpdates.PutValAndMetadata(string(fields[0]), string(fields[1]), fields[2], fields[3], ver)
+		return flushIfFull()
+	})
+	if err != nil {
+		return err
+	}
+	err = readSnapshotFile(filepath.Join(dir, snapshotPvtStateHashesFileName), 6, func(fields [][]byte) error {
+		ver, _, err := version.NewHeightFromBytes(fields[5])
+		if err != nil {
+			return errors.Wrap(err, "error decoding the version of a private state key-hash")
+		}
+		batch.HashUpdates.PutValHashAndMetadata(string(fields[0]), string(fields[1]), fields[2], fields[3], fields[4], ver)
+		return flushIfFull()
+	})
+	if err != nil {
+		return err
+	}
+	return vdb.ApplyPrivacyAwareUpdates(batch, savepoint)
+}
+
+func importConfigHistoryFromSnapshot(dir, ledgerID string, importer func(ledgerID, chaincodeName string, configInfo *ledger.CollectionConfigInfo) error) error {
+	return readSnapshotFile(filepath.Join(dir, snapshotConfigHistoryFileName), 3, func(fields [][]byte) error {
+		committingBlockNum, n := proto.DecodeVarint(fields[1])
+		if n == 0 {
+			return errors.New("error decoding the committing block number of a collection config")
+		}
+		collConfig := &common.CollectionConfigPackage{}
+		if err := proto.Unmarshal(fields[2], collConfig); err != nil {
+			return errors.Wrap(err, "error unmarshaling a collection config")
+		}
+		return importer(ledgerID, string(fields[0]),
+			&ledger.CollectionConfigInfo{CollectionConfig: collConfig, CommittingBlockNum: committingBlockNum},
+		)
+	})
+}
+
+// loadBoundaryBlocksFromSnapshot returns the last block and the last config block from the snapshot after
+// verifying that the last block matches the snapshot metadata
+func loadBoundaryBlocksFromSnapshot(dir string, metadata *snapshotSignableMetadata) (*blkstorage.SnapshotInfo, error) {
+	var blocks []*common.Block
+	err := readSnapshotFile(filepath.Join(dir, snapshotBoundaryBlocksFileName), 1, func(fields [][]byte) error {
+		block := &common.Block{}
+		if err := proto.Unmarshal(fields[0], block); err != nil {
+			return errors.Wrap(err, "error unmarshaling a boundary block")
+		}
+		if block.Header == nil {
+			return errors.New("boundary block is missing the header")
+		}
+		blocks = append(blocks, block)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) != 2 {
+		return nil, errors.Errorf("expected 2 boundary blocks in the snapshot, found [%d]", len(blocks))
+	}
+	lastBlock, lastConfigBlock := blocks[0], blocks[1]
+	if lastBlock.Header.Number != metadata.LastBlockNumber ||
+		hex.EncodeToString(lastBlock.Header.Hash()) != metadata.LastBlockHashInHex ||
+		hex.EncodeToString(lastBlock.Header.PreviousHash) != metadata.PreviousBlockHashInHex {
+		return nil, errors.Errorf("last block in the snapshot does not match the snapshot metadata [block number=%d]",
+			metadata.LastBlockNumber)
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	if lastConfigBlock.Header.Number != lastConfigBlockNum {
+		return nil, errors.Errorf("last config block number in the snapshot is [%d], expected [%d]",
+			lastConfigBlock.Header.Number, lastConfigBlockNum)
+	}
+	chainID, err := utils.GetChainIDFromBlock(lastConfigBlock)
+	if err != nil {
+		return nil, err
+	}
+	if chainID != metadata.ChannelName {
+		return nil, errors.Errorf("channel name in the last config block [%s] does not match the snapshot metadata [%s]",
+			chainID, metadata.ChannelName)
+	}
+	return &blkstorage.SnapshotInfo{LastBlock: lastBlock, LastConfigBlock: lastConfigBlock}, nil
+}
+
+// snapshotTxIDsIterator implements the interface blkstorage.TxIDInfoIterator over the txids file of a snapshot
+type snapshotTxIDsIterator struct {
+	reader *snapshotFileReader
+}
+
+func (i *snapshotTxIDsIterator) Next() (*blkstorage.TxIDInfo, error) {
+	fields, err := i.reader.readRecord(2)
+	if err != nil || fields == nil {
+		return nil, err
+	}
+	validationCode, n := proto.DecodeVarint(fields[1])
+	if n == 0 {
+		return nil, errors.New("error decoding the validation code of a txid")
+	}
+	return &blkstorage.TxIDInfo{TxID: string(fields[0]), ValidationCode: peer.TxValidationCode(validationCode)}, nil
+}
+
+// snapshotFileWriter writes length-prefixed records to a snapshot file and computes the hash of the file content
+type snapshotFileWriter struct {
+	file      *os.File
+	bufWriter *bufio.Writer
+	multiW    io.Writer
+	hasher    hash.Hash
+}
+
+func newSnapshotFileWriter(filePath string) (*snapshotFileWriter, error) {
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while creating the snapshot file [%s]", filePath)
+	}
+	bufWriter := bufio.NewWriter(file)
+	hasher := sha256.New()
+	return &snapshotFileWriter{
+		file:      file,
+		bufWriter: bufWriter,
+		multiW:    io.MultiWriter(bufWriter, hasher),
+		hasher:    hasher,
+	}, nil
+}
+
+// addRecord encodes the fields as length-prefixed bytes and prefixes the record with its total length
+func (w *snapshotFileWriter) addRecord(fields ...[]byte) error {
+	buffer := proto.NewBuffer(nil)
+	for _, field := range fields {
+		if err := buffer.EncodeRawBytes(field); err != nil {
+			return errors.Wrap(err, "error encoding a snapshot record")
+		}
+	}
+	if _, err := w.multiW.Write(proto.EncodeVarint(uint64(len(buffer.Bytes())))); err != nil {
+		return errors.Wrapf(err, "error writing to the snapshot file [%s]", w.file.Name())
+	}
+	if _, err := w.multiW.Write(buffer.Bytes()); err != nil {
+		return errors.Wrapf(err, "error writing to the snapshot file [%s]", w.file.Name())
+	}
+	return nil
+}
+
+// done flushes and syncs the file and returns the hash of the file content in hex
+func (w *snapshotFileWriter) done() (string, error) {
+	if err := w.bufWriter.Flush(); err != nil {
+		return "", errors.Wrapf(err, "error flushing the snapshot file [%s]", w.file.Name())
+	}
+	if err := w.file.Sync(); err != nil {
+		return "", errors.Wrapf(err, "error syncing the snapshot file [%s]", w.file.Name())
+	}
+	return hex.EncodeToString(w.hasher.Sum(nil)), nil
+}
+
+func (w *snapshotFileWriter) close() {
+	w.file.Close()
+}
+
+// snapshotFileReader reads the records written by a snapshotFileWriter
+type snapshotFileReader struct {
+	file      *os.File
+	bufReader *bufio.Reader
+}
+
+func newSnapshotFileReader(filePath string) (*snapshotFileReader, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while opening the snapshot file [%s]", filePath)
+	}
+	return &snapshotFileReader{file: file, bufReader: bufio.NewReader(file)}, nil
+}
+
+// readRecord returns nil when the end of the file is reached
+func (r *snapshotFileReader) readRecord(numFields int) ([][]byte, error) {
+	recordLen, err := binary.ReadUvarint(r.bufReader)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading from the snapshot file [%s]", r.file.Name())
+	}
+	recordBytes := make([]byte, recordLen)
+	if _, err := io.ReadFull(r.bufReader, recordBytes); err != nil {
+		return nil, errors.Wrapf(err, "error reading from the snapshot file [%s]", r.file.Name())
+	}
+	buffer := proto.NewBuffer(recordBytes)
+	fields := make([][]byte, numFields)
+	for i := range fields {
+		if fields[i], err = buffer.DecodeRawBytes(false); err != nil {
+			return nil, errors.Wrapf(err, "error decoding a record from the snapshot file [%s]", r.file.Name())
+		}
+	}
+	return fields, nil
+}
+
+func (r *snapshotFileReader) close() {
+	r.file.Close()
+}
+
+func readSnapshotFile(filePath string, numFields int, handle func(fields [][]byte) error) error {
+	reader, err := newSnapshotFileReader(filePath)
+	if err != nil {
+		return err
+	}
+	defer reader.close()
+	for {
+		fields, err := reader.readRecord(numFields)
+		if err != nil {
+			return err
+		}
+		if fields == nil {
+			return nil
+		}
+		if err := handle(fields); err != nil {
+			return err
+		}
+	}
+}
+
+func computeFileHash(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error while opening the snapshot file [%s]", filePath)
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", errors.Wrapf(err, "error while computing the hash of the snapshot file [%s]", filePath)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func syncDir(dirPath string) error {
+	dir, err := os.Open(dirPath)
+	if err != nil {
+		return errors.Wrapf(err, "error while opening the dir [%s]", dirPath)
+	}
+	defer dir.Close()
+	return errors.Wrapf(dir.Sync(), "error while syncing the dir [%s]", dirPath)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotExportAndCreateLedgerFromSnapshot(t *testing.T) {
+	sourceEnv := newTestEnv(t)
+	defer sourceEnv.cleanup()
+	sourceProvider := testutilNewProvider(t)
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	sourceLedger, err := sourceProvider.Create(gb)
+	require.NoError(t, err)
+
+	simulateAndGenerateBlock := func(l lgr.PeerLedger, kvs map[string]string) *common.Block {
+		simulator, err := l.NewTxSimulator(util.GenerateUUID())
+		require.NoError(t, err)
+		for k, v := range kvs {
+			require.NoError(t, simulator.SetState("ns1", k, []byte(v)))
+		}
+		require.NoError(t, simulator.SetStateMetadata("ns1", "key1", map[string][]byte{"metadata-key": []byte("metadata-value")}))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		return bg.NextBlock([][]byte{pubSimBytes})
+	}
+
+	block1 := simulateAndGenerateBlock(sourceLedger, map[string]string{"key1": "value1", "key2": "value2"})
+	require.NoError(t, sourceLedger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block1}, &lgr.CommitOptions{}))
+	block2 := simulateAndGenerateBlock(sourceLedger, map[string]string{"key2": "value2-updated", "key3": "value3"})
+	require.NoError(t, sourceLedger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block2}, &lgr.CommitOptions{}))
+
+	snapshotInfo, err := sourceLedger.ExportSnapshot()
+	require.NoError(t, err)
+	sourceBCInfo, err := sourceLedger.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "testLedger", snapshotInfo.LedgerID)
+	assert.Equal(t, uint64(2), snapshotInfo.LastBlockNum)
+	assert.Equal(t, sourceBCInfo.CurrentBlockHash, snapshotInfo.LastBlockHash)
+	assert.Equal(t, sourceBCInfo.PreviousBlockHash, snapshotInfo.PreviousBlockHash)
+	assert.Equal(t, filepath.Join(SnapshotsCompletedDir("testLedger"), "2"), snapshotInfo.Dir)
+
+	_, err = sourceLedger.ExportSnapshot()
+	assert.Contains(t, err.Error(), "snapshot for the ledger [testLedger] at block number [2] already exists")
+
+	// copy the snapshot out of the source env, as the bootstrapped ledger is created in a different env
+	snapshotDir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(snapshotDir)
+	files, err := ioutil.ReadDir(snapshotInfo.Dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		content, err := ioutil.ReadFile(filepath.Join(snapshotInfo.Dir, f.Name()))
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(snapshotDir, f.Name()), content, 0644))
+	}
+
+	// a block committed after the snapshot is added to both the source and the bootstrapped ledger
+	block3 := simulateAndGenerateBlock(sourceLedger, map[string]string{"key4": "value4"})
+	block3Copy := proto.Clone(block3).(*common.Block)
+	require.NoError(t, sourceLedger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block3}, &lgr.CommitOptions{}))
+	sourceLedger.Close()
+	sourceProvider.Close()
+
+	targetEnv := newTestEnv(t)
+	defer targetEnv.cleanup()
+	targetProvider := testutilNewProvider(t)
+	defer targetProvider.Close()
+
+	targetLedger, ledgerID, err := targetProvider.CreateFromSnapshot(snapshotDir)
+	require.NoError(t, err)
+	assert.Equal(t, "testLedger", ledgerID)
+	exists, err := targetProvider.Exists("testLedger")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	verifyState := func(l lgr.PeerLedger, expected map[string]string) {
+		qe, err := l.NewQueryExecutor()
+		require.NoError(t, err)
+		defer qe.Done()
+		for k, v := range expected {
+			val, err := qe.GetState("ns1", k)
+			require.NoError(t, err)
+			assert.Equal(t, v, string(val))
+		}
+		metadata, err := qe.GetStateMetadata("ns1", "key1")
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"metadata-key": []byte("metadata-value")}, metadata)
+	}
+
+	bcInfo, err := targetLedger.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, sourceBCInfo, bcInfo)
+	verifyState(targetLedger, map[string]string{"key1": "value1", "key2": "value2-updated", "key3": "value3"})
+
+	txID, err := putils.GetOrComputeTxIDFromEnvelope(block1.Data.Data[0])
+	require.NoError(t, err)
+	validationCode, err := targetLedger.GetTxValidationCodeByTxID(txID)
+	require.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_VALID, validationCode)
+	_, err = targetLedger.GetTransactionByID(txID)
+	assert.Equal(t, blkstorage.ErrNotAvailableBeforeSnapshot, err)
+	_, err = targetLedger.GetBlockByNumber(1)
+	assert.Equal(t, blkstorage.ErrNotAvailableBeforeSnapshot, err)
+	lastBlock, err := targetLedger.GetBlockByNumber(2)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(block2, lastBlock))
+
+	require.NoError(t, targetLedger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block3Copy}, &lgr.CommitOptions{}))
+	verifyState(targetLedger, map[string]string{"key1": "value1", "key2": "value2-updated", "key3": "value3", "key4": "value4"})
+	// the commit hash continues from the last block in the snapshot
+	assert.Equal(t,
+		block3.Metadata.Metadata[common.BlockMetadataIndex_COMMIT_HASH],
+		block3Copy.Metadata.Metadata[common.BlockMetadataIndex_COMMIT_HASH],
+	)
+
+	_, _, err = targetProvider.CreateFromSnapshot(snapshotDir)
+	assert.Equal(t, ErrLedgerIDExists, err)
+	targetLedger.Close()
+}
+
+func TestSnapshotRequests(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer l.Close()
+
+	assert.EqualError(t, l.SubmitSnapshotRequest(0),
+		"requested snapshot for block number [0] cannot be less than or equal to the last committed block number [0]")
+	require.NoError(t, l.SubmitSnapshotRequest(2))
+	require.NoError(t, l.SubmitSnapshotRequest(1))
+	assert.EqualError(t, l.SubmitSnapshotRequest(2), "a snapshot request for block number [2] already exists")
+	pending, err := l.PendingSnapshotRequests()
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, pending)
+
+	for _, b := range bg.NextTestBlocks(2) {
+		require.NoError(t, l.CommitWithPvtData(&lgr.BlockAndPvtData{Block: b}, &lgr.CommitOptions{}))
+	}
+	pending, err = l.PendingSnapshotRequests()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	for _, blockNum := range []string{"1", "2"} {
+		_, err := loadSnapshotMetadata(filepath.Join(SnapshotsCompletedDir("testLedger"), blockNum))
+		assert.NoError(t, err)
+	}
+}
+
+func TestLoadSnapshotMetadataErrors(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer l.Close()
+	snapshotInfo, err := l.ExportSnapshot()
+	require.NoError(t, err)
+
+	_, err = loadSnapshotMetadata(snapshotInfo.Dir)
+	require.NoError(t, err)
+
+	txIDsFile := filepath.Join(snapshotInfo.Dir, snapshotTxIDsFileName)
+	require.NoError(t, ioutil.WriteFile(txIDsFile, []byte("tampered-content"), 0644))
+	_, err = loadSnapshotMetadata(snapshotInfo.Dir)
+	assert.Contains(t, err.Error(), "hash mismatch for the snapshot file [txids.data]")
+
+	_, _, err = provider.CreateFromSnapshot(snapshotInfo.Dir)
+	assert.Contains(t, err.Error(), "error while loading the snapshot metadata")
+
+	signableMetadataFile := filepath.Join(snapshotInfo.Dir, snapshotSignableMetadataFileName)
+	require.NoError(t, ioutil.WriteFile(signableMetadataFile, []byte(`{"channel_name":"anotherLedger"}`), 0644))
+	_, err = loadSnapshotMetadata(snapshotInfo.Dir)
+	assert.Contains(t, err.Error(), "snapshot hash mismatch")
+
+	_, err = loadSnapshotMetadata(filepath.Join(snapshotInfo.Dir, "non-existent-dir"))
+	assert.Contains(t, err.Error(), "error reading the snapshot signable metadata")
+}
@@ -18,8 +18,10 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	// the state database implementations register themselves with the statedb package
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statebadgerdb"
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/pkg/errors"
@@ -42,14 +44,9 @@ type CommonStorageDBProvider struct {
 
 // NewCommonStorageDBProvider constructs an instance of DBProvider
 func NewCommonStorageDBProvider(bookkeeperProvider bookkeeping.Provider, metricsProvider metrics.Provider, healthCheckRegistry ledger.HealthCheckRegistry) (DBProvider, error) {
-	var vdbProvider statedb.VersionedDBProvider
-	var err error
-	if ledgerconfig.IsCouchDBEnabled() {
-		if vdbProvider, err = statecouchdb.NewVersionedDBProvider(metricsProvider); err != nil {
-			return nil, err
-		}
-	} else {
-		vdbProvider = stateleveldb.NewVersionedDBProvider()
+	vdbProvider, err := statedb.NewVersionedDBProvider(ledgerconfig.GetStateDatabase(), metricsProvider)
+	if err != nil {
+		return nil, err
 	}
 
 	dbProvider := &CommonStorageDBProvider{vdbProvider, healthCheckRegistry, bookkeeperProvider}
@@ -312,6 +309,61 @@ func (s *CommonStorageDB) ChaincodeDeployDone(succeeded bool) {
 	// NOOP
 }
 
+// ExportPubStateAndPvtStateHashes implements corresponding function in interface DB. The function `handlePubState`
+// is invoked for each of the keys in the public state and the function `handlePvtStateHash` is invoked for each of the
+// key-hashes of the private data. The private data itself is not exported. The underlying VersionedDB is expected to
+// implement the interface statedb.FullScanIterable
+func (s *CommonStorageDB) ExportPubStateAndPvtStateHashes(handlePubState func(*statedb.VersionedKV) error,
+	handlePvtStateHash func(*HashedCompositeKey, *statedb.VersionedValue) error) error {
+	fullScanIterable, ok := s.VersionedDB.(statedb.FullScanIterable)
+	if !ok {
+		return errors.New("the state database does not support exporting of the state")
+	}
+	itr, err := fullScanIterable.GetFullScanIterator()
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			return nil
+		}
+		kv := res.(*statedb.VersionedKV)
+		split := strings.SplitN(kv.Namespace, nsJoiner, 2)
+		if len(split) == 1 {
+			if err := handlePubState(kv); err != nil {
+				return err
+			}
+			continue
+		}
+		ns, nsSuffix := split[0], split[1]
+		if !strings.HasPrefix(nsSuffix, hashDataPrefix) {
+			// private data is not exported
+			continue
+		}
+		keyHash := kv.Key
+		if !s.BytesKeySupported() {
+			decodedKeyHash, err := base64.StdEncoding.DecodeString(keyHash)
+			if err != nil {
+				return errors.Wrapf(err, "error decoding the key-hash [%s]", keyHash)
+			}
+			keyHash = string(decodedKeyHash)
+		}
+		hashedKey := &HashedCompositeKey{
+			Namespace:      ns,
+			CollectionName: strings.TrimPrefix(nsSuffix, hashDataPrefix),
+			KeyHash:        keyHash,
+		}
+		if err := handlePvtStateHash(hashedKey, &kv.VersionedValue); err != nil {
+			return err
+		}
+	}
+}
+
 func derivePvtDataNs(namespace, collection string) string {
 	return namespace + nsJoiner + pvtDataPrefix + collection
 }
@@ -39,6 +39,8 @@ type DB interface {
 	GetPrivateDataMetadataByHash(namespace, collection string, keyHash []byte) ([]byte, error)
 	ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error)
 	ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error
+	ExportPubStateAndPvtStateHashes(handlePubState func(*statedb.VersionedKV) error,
+		handlePvtStateHash func(*HashedCompositeKey, *statedb.VersionedValue) error) error
 }
 
 // PvtdataCompositeKey encloses Namespace, CollectionName and Key components
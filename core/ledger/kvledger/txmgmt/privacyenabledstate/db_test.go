@@ -586,3 +586,54 @@ func deletePvtUpdates(t *testing.T, updates *UpdateBatch, ns, coll, key string,
 	updates.PvtUpdates.Delete(ns, coll, key, ver)
 	updates.HashUpdates.Delete(ns, coll, util.ComputeStringHash(key), ver)
 }
+
+func TestExportPubStateAndPvtStateHashes(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle("test-ledger-id")
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.PutValAndMetadata("ns1", "key1", []byte("value1"), []byte("metadata1"), version.NewHeight(1, 1))
+	updates.PubUpdates.Put("ns2", "key2", []byte("value2"), version.NewHeight(1, 2))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 3)))
+
+	pubState := map[statedb.CompositeKey]*statedb.VersionedValue{}
+	pvtStateHashes := map[HashedCompositeKey]*statedb.VersionedValue{}
+	err := db.ExportPubStateAndPvtStateHashes(
+		func(kv *statedb.VersionedKV) error {
+			pubState[kv.CompositeKey] = &statedb.VersionedValue{Value: kv.Value, Metadata: kv.Metadata, Version: kv.Version}
+			return nil
+		},
+		func(hashedKey *HashedCompositeKey, vv *statedb.VersionedValue) error {
+			pvtStateHashes[*hashedKey] = vv
+			return nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		map[statedb.CompositeKey]*statedb.VersionedValue{
+			{Namespace: "ns1", Key: "key1"}: {Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)},
+			{Namespace: "ns2", Key: "key2"}: {Value: []byte("value2"), Version: version.NewHeight(1, 2)},
+		},
+		pubState,
+	)
+	assert.Equal(t,
+		map[HashedCompositeKey]*statedb.VersionedValue{
+			{Namespace: "ns1", CollectionName: "coll1", KeyHash: string(util.ComputeStringHash("key1"))}: {
+				Value: util.ComputeStringHash("pvt_value1"), Version: version.NewHeight(1, 3)},
+		},
+		pvtStateHashes,
+	)
+
+	err = db.ExportPubStateAndPvtStateHashes(
+		func(kv *statedb.VersionedKV) error {
+			return fmt.Errorf("pub-state-handler-error")
+		},
+		func(hashedKey *HashedCompositeKey, vv *statedb.VersionedValue) error {
+			return nil
+		},
+	)
+	assert.EqualError(t, err, "pub-state-handler-error")
+}
@@ -33,7 +33,7 @@ type TestEnv interface {
 // Tests will be run against each environment in this array
 // For example, to skip CouchDB tests, remove &couchDBLockBasedEnv{}
 //var testEnvs = []testEnv{&levelDBCommonStorageTestEnv{}, &couchDBCommonStorageTestEnv{}}
-var testEnvs = []TestEnv{&LevelDBCommonStorageTestEnv{}, &CouchDBCommonStorageTestEnv{}}
+var testEnvs = []TestEnv{&LevelDBCommonStorageTestEnv{}, &BadgerDBCommonStorageTestEnv{}, &CouchDBCommonStorageTestEnv{}}
 
 ///////////// LevelDB Environment //////////////
 
@@ -74,6 +74,46 @@ func (env *LevelDBCommonStorageTestEnv) Cleanup() {
 	removeDBPath(env.t)
 }
 
+///////////// BadgerDB Environment //////////////
+
+// BadgerDBCommonStorageTestEnv implements TestEnv interface for badgerdb based storage
+type BadgerDBCommonStorageTestEnv struct {
+	t                 testing.TB
+	provider          DBProvider
+	bookkeeperTestEnv *bookkeeping.TestEnv
+}
+
+// Init implements corresponding function from interface TestEnv
+func (env *BadgerDBCommonStorageTestEnv) Init(t testing.TB) {
+	viper.Set("ledger.state.stateDatabase", "badgerdb")
+	removeBadgerDBPath(t)
+	env.bookkeeperTestEnv = bookkeeping.NewTestEnv(t)
+	dbProvider, err := NewCommonStorageDBProvider(env.bookkeeperTestEnv.TestProvider, &disabled.Provider{}, &mock.HealthCheckRegistry{})
+	assert.NoError(t, err)
+	env.t = t
+	env.provider = dbProvider
+}
+
+// GetDBHandle implements corresponding function from interface TestEnv
+func (env *BadgerDBCommonStorageTestEnv) GetDBHandle(id string) DB {
+	db, err := env.provider.GetDBHandle(id)
+	assert.NoError(env.t, err)
+	return db
+}
+
+// GetName implements corresponding function from interface TestEnv
+func (env *BadgerDBCommonStorageTestEnv) GetName() string {
+	return "badgerDBCommonStorageTestEnv"
+}
+
+// Cleanup implements corresponding function from interface TestEnv
+func (env *BadgerDBCommonStorageTestEnv) Cleanup() {
+	env.provider.Close()
+	env.bookkeeperTestEnv.Cleanup()
+	removeBadgerDBPath(env.t)
+	viper.Set("ledger.state.stateDatabase", "")
+}
+
 ///////////// CouchDB Environment //////////////
 
 // CouchDBCommonStorageTestEnv implements TestEnv interface for couchdb based storage
@@ -149,3 +189,9 @@ func removeDBPath(t testing.TB) {
 		t.FailNow()
 	}
 }
+
+func removeBadgerDBPath(t testing.TB) {
+	if err := os.RemoveAll(ledgerconfig.GetStateBadgerDBPath()); err != nil {
+		t.Fatalf("Err: %s", err)
+	}
+}
@@ -966,3 +966,34 @@ func TestApplyUpdatesWithNilHeight(t *testing.T, dbProvider statedb.VersionedDBP
 	assert.Equal(t, savePoint, ht) // savepoint should still be what was set with batch1
 	// (because batch2 calls ApplyUpdates with savepoint as nil)
 }
+
+// TestDrop tests the statedb.Droppable implementation of a db provider
+func TestDrop(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db1, err := dbProvider.GetDBHandle("test-drop-db1")
+	assert.NoError(t, err)
+	db2, err := dbProvider.GetDBHandle("test-drop-db2")
+	assert.NoError(t, err)
+
+	for _, db := range []statedb.VersionedDB{db1, db2} {
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+		batch.Put("ns2", "key2", []byte("value2"), version.NewHeight(1, 2))
+		assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 2)))
+	}
+
+	assert.NoError(t, dbProvider.(statedb.Droppable).Drop("test-drop-db1"))
+	vv, err := db1.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+	sp, err := db1.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Nil(t, sp)
+
+	// the other dbs are not affected
+	vv, err = db2.GetState("ns2", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}, vv)
+	sp, err = db2.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1, 2), sp)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+// VersionedDBProviderFactory constructs the VersionedDBProvider of a state database implementation
+type VersionedDBProviderFactory func(metricsProvider metrics.Provider) (VersionedDBProvider, error)
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]VersionedDBProviderFactory
+}{factories: map[string]VersionedDBProviderFactory{}}
+
+// RegisterVersionedDBProvider makes a state database implementation available under the given name.
+// The implementations are expected to register themselves from an init function.
+// The names are case insensitive and registering the same name twice panics
+func RegisterVersionedDBProvider(name string, factory VersionedDBProviderFactory) {
+	registry.Lock()
+	defer registry.Unlock()
+	if name == "" || factory == nil {
+		panic("a state database must be registered with a name and a factory")
+	}
+	key := strings.ToLower(name)
+	if _, ok := registry.factories[key]; ok {
+		panic(errors.Errorf("state database [%s] is already registered", name))
+	}
+	registry.factories[key] = factory
+}
+
+// NewVersionedDBProvider constructs the VersionedDBProvider of the state database registered under the given name
+func NewVersionedDBProvider(name string, metricsProvider metrics.Provider) (VersionedDBProvider, error) {
+	registry.RLock()
+	factory, ok := registry.factories[strings.ToLower(name)]
+	registry.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("state database [%s] is not registered, the registered state databases are %s",
+			name, RegisteredVersionedDBProviders())
+	}
+	return factory(metricsProvider)
+}
+
+// RegisteredVersionedDBProviders returns the sorted names of the registered state databases
+func RegisteredVersionedDBProviders() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	var names []string
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
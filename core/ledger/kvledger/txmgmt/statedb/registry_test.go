@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testVersionedDBProvider struct {
+	metricsProvider metrics.Provider
+}
+
+func (p *testVersionedDBProvider) GetDBHandle(id string) (VersionedDB, error) { return nil, nil }
+func (p *testVersionedDBProvider) Close()                                     {}
+
+func TestRegistry(t *testing.T) {
+	RegisterVersionedDBProvider("TestDB", func(metricsProvider metrics.Provider) (VersionedDBProvider, error) {
+		return &testVersionedDBProvider{metricsProvider}, nil
+	})
+	assert.Contains(t, RegisteredVersionedDBProviders(), "testdb")
+
+	metricsProvider := &disabled.Provider{}
+	provider, err := NewVersionedDBProvider("testdb", metricsProvider)
+	require.NoError(t, err)
+	assert.Equal(t, &testVersionedDBProvider{metricsProvider}, provider)
+
+	_, err = NewVersionedDBProvider("unknowndb", metricsProvider)
+	assert.Contains(t, err.Error(), "state database [unknowndb] is not registered")
+
+	assert.PanicsWithValue(t, "a state database must be registered with a name and a factory", func() {
+		RegisterVersionedDBProvider("", nil)
+	})
+	assert.Panics(t, func() {
+		RegisterVersionedDBProvider("TESTDB", func(metrics.Provider) (VersionedDBProvider, error) { return nil, nil })
+	})
+}
@@ -0,0 +1,574 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebadgerdb
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb/msgs"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("statebadgerdb")
+
+var dbNameKeySep = []byte{0x00}
+var compositeKeySep = []byte{0x00}
+var lastKeyIndicator = byte(0x01)
+var savePointKey = []byte{0x00}
+
+// valueLogGCDiscardRatio is the fraction of the space of a value log file that must be
+// reclaimable for the file to be rewritten by the garbage collection of the value log
+const valueLogGCDiscardRatio = 0.5
+
+func init() {
+	statedb.RegisterVersionedDBProvider(ledgerconfig.StateDatabaseBadgerDB, func(metrics.Provider) (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider()
+	})
+}
+
+// VersionedDBProvider implements interface VersionedDBProvider on top of an embedded Badger instance.
+// The dbs of all the channels share the Badger instance and are separated by prefixing the keys with the db name
+type VersionedDBProvider struct {
+	db        *badger.DB
+	done      chan struct{}
+	gcDone    sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewVersionedDBProvider instantiates VersionedDBProvider
+func NewVersionedDBProvider() (*VersionedDBProvider, error) {
+	dbPath := ledgerconfig.GetStateBadgerDBPath()
+	logger.Debugf("constructing VersionedDBProvider dbPath=%s", dbPath)
+	if _, err := util.CreateDirIfMissing(dbPath); err != nil {
+		return nil, errors.Wrapf(err, "error creating the directory of the Badger state database at %s", dbPath)
+	}
+	// The writes are synced so that a savepoint is never persisted ahead of the state it covers.
+	// The value log is truncated on open after a crash because the blocks above the savepoint
+	// are committed again during the recovery of the ledger
+	opts := badger.DefaultOptions(dbPath).
+		WithSyncWrites(true).
+		WithTruncate(true).
+		WithLogger(badgerLogger{logger})
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening the Badger state database at %s", dbPath)
+	}
+	provider := &VersionedDBProvider{db: db, done: make(chan struct{})}
+	provider.gcDone.Add(1)
+	go provider.runValueLogGC(ledgerconfig.GetBadgerDBValueLogGCInterval())
+	return provider, nil
+}
+
+// runValueLogGC periodically reclaims the space of the value log taken by the overwritten and deleted values,
+// which Badger does not do on its own
+func (provider *VersionedDBProvider) runValueLogGC(interval time.Duration) {
+	defer provider.gcDone.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-provider.done:
+			return
+		case <-ticker.C:
+			// a value log file is rewritten per call; keep going until there is nothing left to reclaim
+			for {
+				err := provider.db.RunValueLogGC(valueLogGCDiscardRatio)
+				if err == nil {
+					continue
+				}
+				if err != badger.ErrNoRewrite && err != badger.ErrRejected {
+					logger.Warningf("Error while garbage collecting the value log: %s", err)
+				}
+				break
+			}
+		}
+	}
+}
+
+// GetDBHandle gets the handle to a named database. The returned VersionedDB implements
+// the interfaces statedb.BulkOptimizable and statedb.FullScanIterable
+func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.VersionedDB, error) {
+	return newVersionedDB(provider.db, dbName), nil
+}
+
+// Drop implements method in the interface statedb.Droppable
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	return errors.Wrapf(provider.db.DropPrefix(dbKeyPrefix(dbName)), "error dropping the db [%s]", dbName)
+}
+
+// Close closes the underlying db
+func (provider *VersionedDBProvider) Close() {
+	provider.closeOnce.Do(func() {
+		close(provider.done)
+		provider.gcDone.Wait()
+		if err := provider.db.Close(); err != nil {
+			logger.Warningf("Error while closing the Badger state database: %s", err)
+		}
+	})
+}
+
+// badgerLogger logs the informational messages of Badger, such as the progress of the compactions, at debug level
+type badgerLogger struct {
+	*flogging.FabricLogger
+}
+
+func (l badgerLogger) Infof(template string, args ...interface{}) {
+	l.Debugf(template, args...)
+}
+
+type nsVersions map[string]*version.Height
+
+// versionedDB implements VersionedDB interface
+type versionedDB struct {
+	db     *badger.DB
+	dbName string
+	prefix []byte
+
+	verCacheLock      sync.RWMutex
+	committedVersions map[string]nsVersions
+}
+
+// newVersionedDB constructs an instance of VersionedDB
+func newVersionedDB(db *badger.DB, dbName string) *versionedDB {
+	return &versionedDB{
+		db:                db,
+		dbName:            dbName,
+		prefix:            dbKeyPrefix(dbName),
+		committedVersions: map[string]nsVersions{},
+	}
+}
+
+// Open implements method in VersionedDB interface
+func (vdb *versionedDB) Open() error {
+	// do nothing because shared db is used
+	return nil
+}
+
+// Close implements method in VersionedDB interface
+func (vdb *versionedDB) Close() {
+	// do nothing because shared db is used
+}
+
+// ValidateKeyValue implements method in VersionedDB interface
+func (vdb *versionedDB) ValidateKeyValue(key string, value []byte) error {
+	return nil
+}
+
+// BytesKeySupported implements method in VersionedDB interface
+func (vdb *versionedDB) BytesKeySupported() bool {
+	return true
+}
+
+// GetState implements method in VersionedDB interface
+func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
+	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
+	dbVal, err := vdb.get(vdb.stateKey(namespace, key))
+	if err != nil || dbVal == nil {
+		return nil, err
+	}
+	return decodeValue(dbVal)
+}
+
+// GetVersion implements method in VersionedDB interface
+func (vdb *versionedDB) GetVersion(namespace string, key string) (*version.Height, error) {
+	if ver, ok := vdb.GetCachedVersion(namespace, key); ok {
+		return ver, nil
+	}
+	// This is reached only during simulation because during commit
+	// `LoadCommittedVersions` is always called before calling `GetVersion`
+	versionedValue, err := vdb.GetState(namespace, key)
+	if err != nil || versionedValue == nil {
+		return nil, err
+	}
+	return versionedValue.Version, nil
+}
+
+// GetStateMultipleKeys implements method in VersionedDB interface
+func (vdb *versionedDB) GetStateMultipleKeys(namespace string, keys []string) ([]*statedb.VersionedValue, error) {
+	vals := make([]*statedb.VersionedValue, len(keys))
+	for i, key := range keys {
+		val, err := vdb.GetState(namespace, key)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return vals, nil
+}
+
+// GetStateRangeScanIterator implements method in VersionedDB interface
+// startKey is inclusive
+// endKey is exclusive
+func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.GetStateRangeScanIteratorWithMetadata(namespace, startKey, endKey, nil)
+}
+
+const optionLimit = "limit"
+
+// GetStateRangeScanIteratorWithMetadata implements method in VersionedDB interface
+func (vdb *versionedDB) GetStateRangeScanIteratorWithMetadata(namespace string, startKey string, endKey string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
+	requestedLimit := int32(0)
+	// if metadata is provided, validate and apply options
+	if metadata != nil {
+		if err := statedb.ValidateRangeMetadata(metadata); err != nil {
+			return nil, err
+		}
+		if limitOption, ok := metadata[optionLimit]; ok {
+			requestedLimit = limitOption.(int32)
+		}
+	}
+
+	dbStartKey := vdb.stateKey(namespace, startKey)
+	dbEndKey := vdb.stateKey(namespace, endKey)
+	if endKey == "" {
+		dbEndKey[len(dbEndKey)-1] = lastKeyIndicator
+	}
+	nsPrefix := vdb.stateKey(namespace, "")
+	dbItr := newDBIterator(vdb.db, nsPrefix, dbStartKey, dbEndKey)
+	return &kvScanner{namespace: namespace, prefixLen: len(nsPrefix), dbItr: dbItr, requestedLimit: requestedLimit}, nil
+}
+
+// ExecuteQuery implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	return nil, errors.New("ExecuteQuery not supported for badgerdb")
+}
+
+// ExecuteQueryWithMetadata implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
+	return nil, errors.New("ExecuteQueryWithMetadata not supported for badgerdb")
+}
+
+// ApplyUpdates implements method in VersionedDB interface. The updates that do not fit in a single
+// Badger transaction are split across several transactions and the savepoint is written by the last
+// one. If the peer stops in between, the block is committed again from the previous savepoint upon
+// recovery, which rewrites the same values. The cached versions are dropped once the updates are applied
+func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	defer vdb.ClearCachedVersions()
+
+	txn := vdb.db.NewTransaction(true)
+	defer func() { txn.Discard() }()
+	// write retries the update in a new transaction if the current one is full
+	write := func(update func(txn *badger.Txn) error) error {
+		err := update(txn)
+		if err != badger.ErrTxnTooBig {
+			return err
+		}
+		if err := txn.Commit(); err != nil {
+			return err
+		}
+		txn = vdb.db.NewTransaction(true)
+		return update(txn)
+	}
+
+	for _, ns := range batch.GetUpdatedNamespaces() {
+		for k, vv := range batch.GetUpdates(ns) {
+			dbKey := vdb.stateKey(ns, k)
+			logger.Debugf("Channel [%s]: Applying key(string)=[%s] key(bytes)=[%#v]", vdb.dbName, string(dbKey), dbKey)
+
+			if vv.Value == nil {
+				if err := write(func(txn *badger.Txn) error { return txn.Delete(dbKey) }); err != nil {
+					return errors.Wrapf(err, "error deleting key [%s] of namespace [%s]", k, ns)
+				}
+				continue
+			}
+			encodedVal, err := encodeValue(vv)
+			if err != nil {
+				return err
+			}
+			if err := write(func(txn *badger.Txn) error { return txn.Set(dbKey, encodedVal) }); err != nil {
+				return errors.Wrapf(err, "error writing key [%s] of namespace [%s]", k, ns)
+			}
+		}
+	}
+	// Record a savepoint at a given height
+	// If a given height is nil, it denotes that we are committing pvt data of old blocks.
+	// In this case, we should not store a savepoint for recovery. The lastUpdatedOldBlockList
+	// in the pvtstore acts as a savepoint for pvt data.
+	if height != nil {
+		if err := write(func(txn *badger.Txn) error { return txn.Set(vdb.savePointKey(), height.ToBytes()) }); err != nil {
+			return errors.Wrap(err, "error writing the savepoint")
+		}
+	}
+	return errors.Wrap(txn.Commit(), "error committing the updates")
+}
+
+// GetLatestSavePoint implements method in VersionedDB interface
+func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
+	versionBytes, err := vdb.get(vdb.savePointKey())
+	if err != nil || versionBytes == nil {
+		return nil, err
+	}
+	version, _, err := version.NewHeightFromBytes(versionBytes)
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// LoadCommittedVersions implements method in BulkOptimizable interface. The versions of the given keys
+// are read in the key order from a single snapshot of the db and a nil version is cached for the keys
+// that do not exist so that these are not looked up again
+func (vdb *versionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) error {
+	sortedKeys := make([]*statedb.CompositeKey, len(keys))
+	copy(sortedKeys, keys)
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		if sortedKeys[i].Namespace != sortedKeys[j].Namespace {
+			return sortedKeys[i].Namespace < sortedKeys[j].Namespace
+		}
+		return sortedKeys[i].Key < sortedKeys[j].Key
+	})
+
+	committedVersions := map[string]nsVersions{}
+	err := vdb.db.View(func(txn *badger.Txn) error {
+		for _, compositeKey := range sortedKeys {
+			ns, key := compositeKey.Namespace, compositeKey.Key
+			if _, ok := committedVersions[ns][key]; ok {
+				continue
+			}
+			if committedVersions[ns] == nil {
+				committedVersions[ns] = nsVersions{}
+			}
+			dbVal, err := getFromTxn(txn, vdb.stateKey(ns, key))
+			if err != nil {
+				return err
+			}
+			if dbVal == nil {
+				committedVersions[ns][key] = nil
+				continue
+			}
+			vv, err := decodeValue(dbVal)
+			if err != nil {
+				return err
+			}
+			committedVersions[ns][key] = vv.Version
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logger.Debugf("Loaded the committed versions of %d keys into the cache", len(keys))
+
+	vdb.verCacheLock.Lock()
+	defer vdb.verCacheLock.Unlock()
+	vdb.committedVersions = committedVersions
+	return nil
+}
+
+// GetCachedVersion implements method in BulkOptimizable interface. `LoadCommittedVersions` populates the cache
+func (vdb *versionedDB) GetCachedVersion(namespace string, key string) (*version.Height, bool) {
+	vdb.verCacheLock.RLock()
+	defer vdb.verCacheLock.RUnlock()
+	ver, ok := vdb.committedVersions[namespace][key]
+	return ver, ok
+}
+
+// ClearCachedVersions implements method in BulkOptimizable interface
+func (vdb *versionedDB) ClearCachedVersions() {
+	vdb.verCacheLock.Lock()
+	defer vdb.verCacheLock.Unlock()
+	vdb.committedVersions = map[string]nsVersions{}
+}
+
+// GetFullScanIterator implements method in FullScanIterable interface
+func (vdb *versionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	return &fullScanner{
+		prefixLen:    len(vdb.prefix),
+		savePointKey: vdb.savePointKey(),
+		dbItr:        newDBIterator(vdb.db, vdb.prefix, vdb.prefix, nil),
+	}, nil
+}
+
+func (vdb *versionedDB) get(dbKey []byte) ([]byte, error) {
+	var dbVal []byte
+	err := vdb.db.View(func(txn *badger.Txn) error {
+		var err error
+		dbVal, err = getFromTxn(txn, dbKey)
+		return err
+	})
+	return dbVal, err
+}
+
+func getFromTxn(txn *badger.Txn, dbKey []byte) ([]byte, error) {
+	item, err := txn.Get(dbKey)
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading key [%#v]", dbKey)
+	}
+	return item.ValueCopy(nil)
+}
+
+func (vdb *versionedDB) stateKey(ns string, key string) []byte {
+	return append(append(append(append([]byte{}, vdb.prefix...), ns...), compositeKeySep...), key...)
+}
+
+func (vdb *versionedDB) savePointKey() []byte {
+	return append(append([]byte{}, vdb.prefix...), savePointKey...)
+}
+
+func dbKeyPrefix(dbName string) []byte {
+	return append([]byte(dbName), dbNameKeySep...)
+}
+
+func splitCompositeKey(compositeKey []byte) (string, string) {
+	split := bytes.SplitN(compositeKey, compositeKeySep, 2)
+	return string(split[0]), string(split[1])
+}
+
+// dbIterator iterates over the keys in [startKey, endKey) that have the given prefix, in a snapshot of
+// the db taken when the iterator is created. A nil endKey iterates up to the last key with the prefix
+type dbIterator struct {
+	txn     *badger.Txn
+	itr     *badger.Iterator
+	endKey  []byte
+	started bool
+}
+
+func newDBIterator(db *badger.DB, prefix, startKey, endKey []byte) *dbIterator {
+	txn := db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	itr := txn.NewIterator(opts)
+	itr.Seek(startKey)
+	return &dbIterator{txn: txn, itr: itr, endKey: endKey}
+}
+
+// next moves to the next key and returns it along with a copy of its value, or a nil key
+// once the iterator is exhausted
+func (dbItr *dbIterator) next() ([]byte, []byte, error) {
+	if dbItr.started {
+		dbItr.itr.Next()
+	}
+	dbItr.started = true
+	if !dbItr.itr.Valid() {
+		return nil, nil, nil
+	}
+	item := dbItr.itr.Item()
+	if dbItr.endKey != nil && bytes.Compare(item.Key(), dbItr.endKey) >= 0 {
+		return nil, nil, nil
+	}
+	dbVal, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error reading key [%#v]", item.Key())
+	}
+	return item.KeyCopy(nil), dbVal, nil
+}
+
+func (dbItr *dbIterator) close() {
+	dbItr.itr.Close()
+	dbItr.txn.Discard()
+}
+
+type kvScanner struct {
+	namespace            string
+	prefixLen            int
+	dbItr                *dbIterator
+	requestedLimit       int32
+	totalRecordsReturned int32
+}
+
+func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
+	dbKey, dbVal, err := scanner.dbItr.next()
+	if err != nil || dbKey == nil {
+		return nil, err
+	}
+	vv, err := decodeValue(dbVal)
+	if err != nil {
+		return nil, err
+	}
+	scanner.totalRecordsReturned++
+	return &statedb.VersionedKV{
+		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: string(dbKey[scanner.prefixLen:])},
+		VersionedValue: *vv,
+	}, nil
+}
+
+func (scanner *kvScanner) Close() {
+	scanner.dbItr.close()
+}
+
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	defer scanner.Close()
+	dbKey, _, err := scanner.dbItr.next()
+	if err != nil || dbKey == nil {
+		return ""
+	}
+	return string(dbKey[scanner.prefixLen:])
+}
+
+// fullScanner iterates over all the keys across all the namespaces of a db, skipping the savepoint
+type fullScanner struct {
+	prefixLen    int
+	savePointKey []byte
+	dbItr        *dbIterator
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for {
+		dbKey, dbVal, err := scanner.dbItr.next()
+		if err != nil || dbKey == nil {
+			return nil, err
+		}
+		if bytes.Equal(dbKey, scanner.savePointKey) {
+			continue
+		}
+		vv, err := decodeValue(dbVal)
+		if err != nil {
+			return nil, err
+		}
+		ns, key := splitCompositeKey(dbKey[scanner.prefixLen:])
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: ns, Key: key},
+			VersionedValue: *vv,
+		}, nil
+	}
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.close()
+}
+
+func encodeValue(v *statedb.VersionedValue) ([]byte, error) {
+	return proto.Marshal(&msgs.VersionedValueProto{
+		VersionBytes: v.Version.ToBytes(),
+		Value:        v.Value,
+		Metadata:     v.Metadata,
+	})
+}
+
+func decodeValue(encodedValue []byte) (*statedb.VersionedValue, error) {
+	msg := &msgs.VersionedValueProto{}
+	if err := proto.Unmarshal(encodedValue, msg); err != nil {
+		return nil, err
+	}
+	ver, _, err := version.NewHeightFromBytes(msg.VersionBytes)
+	if err != nil {
+		return nil, err
+	}
+	// protobuf always makes an empty byte array as nil
+	val := msg.Value
+	if val == nil {
+		val = []byte{}
+	}
+	return &statedb.VersionedValue{Version: ver, Value: val, Metadata: msg.Metadata}, nil
+}
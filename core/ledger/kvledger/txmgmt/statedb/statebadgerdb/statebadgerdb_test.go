@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebadgerdb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/ledgertests/kvledger/txmgmt/statedb/statebadgerdb")
+	os.Exit(m.Run())
+}
+
+func TestBasicRW(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestBasicRW(t, env.DBProvider)
+}
+
+func TestMultiDBBasicRW(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestMultiDBBasicRW(t, env.DBProvider)
+}
+
+func TestDeletes(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDeletes(t, env.DBProvider)
+}
+
+func TestIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestIterator(t, env.DBProvider)
+}
+
+func TestGetStateMultipleKeys(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestGetStateMultipleKeys(t, env.DBProvider)
+}
+
+func TestGetVersion(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestGetVersion(t, env.DBProvider)
+}
+
+func TestValueAndMetadataWrites(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+}
+
+func TestPaginatedRangeQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
+func TestApplyUpdatesWithNilHeight(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestApplyUpdatesWithNilHeight(t, env.DBProvider)
+}
+
+func TestDrop(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDrop(t, env.DBProvider)
+}
+
+func TestRegistration(t *testing.T) {
+	assert.Contains(t, statedb.RegisteredVersionedDBProviders(), "badgerdb")
+	provider, err := statedb.NewVersionedDBProvider("BadgerDB", &disabled.Provider{})
+	require.NoError(t, err)
+	defer removeDBPath(t, "TestRegistration")
+	defer provider.Close()
+	assert.IsType(t, &VersionedDBProvider{}, provider)
+}
+
+func TestRichQueryNotSupported(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testrichquerynotsupported")
+	require.NoError(t, err)
+	_, ok := db.(statedb.IndexCapable)
+	assert.False(t, ok)
+	_, err = db.ExecuteQuery("ns1", `{"selector":{"owner":"alice"}}`)
+	assert.EqualError(t, err, "ExecuteQuery not supported for badgerdb")
+	_, err = db.ExecuteQueryWithMetadata("ns1", `{"selector":{"owner":"alice"}}`, nil)
+	assert.EqualError(t, err, "ExecuteQueryWithMetadata not supported for badgerdb")
+}
+
+func TestReopen(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testreopen")
+	require.NoError(t, err)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+	env.DBProvider.Close()
+
+	env.DBProvider, err = NewVersionedDBProvider()
+	require.NoError(t, err)
+	db, err = env.DBProvider.GetDBHandle("testreopen")
+	require.NoError(t, err)
+	vv, err := db.GetState("ns1", "key1")
+	require.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, vv)
+	sp, err := db.GetLatestSavePoint()
+	require.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1, 1), sp)
+}
+
+func TestApplyUpdatesLargerThanATransaction(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testlargeupdates")
+	require.NoError(t, err)
+
+	// the default Badger options limit a transaction to about 10 MB
+	value := make([]byte, 1024*1024)
+	batch := statedb.NewUpdateBatch()
+	for i := 0; i < 16; i++ {
+		batch.Put("ns1", fmt.Sprintf("key%02d", i), value, version.NewHeight(1, uint64(i)))
+	}
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 15)))
+
+	for i := 0; i < 16; i++ {
+		vv, err := db.GetState("ns1", fmt.Sprintf("key%02d", i))
+		require.NoError(t, err)
+		require.NotNil(t, vv)
+		assert.Equal(t, version.NewHeight(1, uint64(i)), vv.Version)
+	}
+	sp, err := db.GetLatestSavePoint()
+	require.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1, 15), sp)
+}
+
+func TestVersionCache(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testversioncache")
+	require.NoError(t, err)
+	bulkOptimizable, ok := db.(statedb.BulkOptimizable)
+	require.True(t, ok)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns2", "key2", []byte("value2"), version.NewHeight(1, 2))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 2)))
+
+	require.NoError(t, bulkOptimizable.LoadCommittedVersions([]*statedb.CompositeKey{
+		{Namespace: "ns2", Key: "key2"},
+		{Namespace: "ns1", Key: "key1"},
+		{Namespace: "ns1", Key: "key3"},
+		{Namespace: "ns1", Key: "key1"},
+	}))
+	ver, ok := bulkOptimizable.GetCachedVersion("ns1", "key1")
+	assert.True(t, ok)
+	assert.Equal(t, version.NewHeight(1, 1), ver)
+	ver, ok = bulkOptimizable.GetCachedVersion("ns2", "key2")
+	assert.True(t, ok)
+	assert.Equal(t, version.NewHeight(1, 2), ver)
+	// a non-existing key is cached with a nil version
+	ver, ok = bulkOptimizable.GetCachedVersion("ns1", "key3")
+	assert.True(t, ok)
+	assert.Nil(t, ver)
+	_, ok = bulkOptimizable.GetCachedVersion("ns2", "key1")
+	assert.False(t, ok)
+
+	// the cache is dropped when the updates are applied
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key3", []byte("value3"), version.NewHeight(2, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+	_, ok = bulkOptimizable.GetCachedVersion("ns1", "key3")
+	assert.False(t, ok)
+	ver, err = db.GetVersion("ns1", "key3")
+	require.NoError(t, err)
+	assert.Equal(t, version.NewHeight(2, 1), ver)
+
+	require.NoError(t, bulkOptimizable.LoadCommittedVersions([]*statedb.CompositeKey{{Namespace: "ns1", Key: "key1"}}))
+	bulkOptimizable.ClearCachedVersions()
+	_, ok = bulkOptimizable.GetCachedVersion("ns1", "key1")
+	assert.False(t, ok)
+}
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testfullscaniterator")
+	assert.NoError(t, err)
+	// the keys of the other dbs sharing the Badger instance are not returned
+	otherDB, err := env.DBProvider.GetDBHandle("testfullscaniterator2")
+	assert.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)))
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key3", []byte("value4"), version.NewHeight(1, 1))
+	assert.NoError(t, otherDB.ApplyUpdates(batch, version.NewHeight(1, 1)))
+
+	itr, err := db.(statedb.FullScanIterable).GetFullScanIterator()
+	assert.NoError(t, err)
+	defer itr.Close()
+	var results []*statedb.VersionedKV
+	for {
+		res, err := itr.Next()
+		assert.NoError(t, err)
+		if res == nil {
+			break
+		}
+		results = append(results, res.(*statedb.VersionedKV))
+	}
+	assert.Equal(t,
+		[]*statedb.VersionedKV{
+			{
+				CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+				VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)},
+			},
+			{
+				CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key2"},
+				VersionedValue: statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)},
+			},
+			{
+				CompositeKey:   statedb.CompositeKey{Namespace: "ns2", Key: "key1"},
+				VersionedValue: statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(1, 3)},
+			},
+		},
+		results,
+	)
+}
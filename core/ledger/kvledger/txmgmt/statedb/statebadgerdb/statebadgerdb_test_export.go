@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebadgerdb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+)
+
+// TestVDBEnv provides a badger db backed versioned db for testing
+type TestVDBEnv struct {
+	t          testing.TB
+	DBProvider statedb.VersionedDBProvider
+}
+
+// NewTestVDBEnv instantiates and new badger db backed TestVDB
+func NewTestVDBEnv(t testing.TB) *TestVDBEnv {
+	t.Logf("Creating new TestVDBEnv")
+	removeDBPath(t, "NewTestVDBEnv")
+	dbProvider, err := NewVersionedDBProvider()
+	if err != nil {
+		t.Fatalf("Failed to create the badger db provider: %s", err)
+	}
+	return &TestVDBEnv{t, dbProvider}
+}
+
+// Cleanup closes the db and removes the db folder
+func (env *TestVDBEnv) Cleanup() {
+	env.t.Logf("Cleaningup TestVDBEnv")
+	env.DBProvider.Close()
+	removeDBPath(env.t, "Cleanup")
+}
+
+func removeDBPath(t testing.TB, caller string) {
+	dbPath := ledgerconfig.GetStateBadgerDBPath()
+	if err := os.RemoveAll(dbPath); err != nil {
+		t.Fatalf("Err: %s", err)
+	}
+	logger.Debugf("Removed folder [%s] for test environment for %s", dbPath, caller)
+}
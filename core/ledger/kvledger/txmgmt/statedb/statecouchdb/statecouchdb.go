@@ -39,12 +39,21 @@ type VersionedDBProvider struct {
 	openCounts    uint64
 }
 
+func init() {
+	statedb.RegisterVersionedDBProvider(ledgerconfig.StateDatabaseCouchDB, func(metricsProvider metrics.Provider) (statedb.VersionedDBProvider, error) {
+		provider, err := NewVersionedDBProvider(metricsProvider)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	})
+}
+
 // NewVersionedDBProvider instantiates VersionedDBProvider
 func NewVersionedDBProvider(metricsProvider metrics.Provider) (*VersionedDBProvider, error) {
 	logger.Debugf("constructing CouchDB VersionedDBProvider")
 	couchDBDef := couchdb.GetCouchDBDefinition()
-	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
-		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB, metricsProvider)
+	couchInstance, err := couchdb.CreateCouchInstanceFromDef(couchDBDef, metricsProvider)
 	if err != nil {
 		return nil, err
 	}
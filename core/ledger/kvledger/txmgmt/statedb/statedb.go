@@ -88,6 +88,22 @@ type IndexCapable interface {
 	ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error
 }
 
+//FullScanIterable interface provides additional function for
+//databases capable of iterating over all the keys across the namespaces
+type FullScanIterable interface {
+	// GetFullScanIterator returns an iterator over all the keys in the db, across all the namespaces.
+	// The returned ResultsIterator contains results of type *VersionedKV
+	GetFullScanIterator() (ResultsIterator, error)
+}
+
+//Droppable interface provides additional function for
+//providers capable of dropping the db of a single channel
+type Droppable interface {
+	// Drop removes all the data of the named db, including its savepoint.
+	// This is expected to be invoked only when the named db is not in use
+	Drop(dbName string) error
+}
+
 // CompositeKey encloses Namespace and Key components
 type CompositeKey struct {
 	Namespace string
@@ -0,0 +1,426 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+// The secondary indexes are maintained in the same leveldb handle as the state so that the index entries
+// are updated atomically with the state. The keys of the index definitions and of the index entries
+// begin with a byte that cannot appear as the first byte of a namespace
+var indexDefinitionKeyPrefix = []byte{0x01}
+var indexEntryKeyPrefix = []byte{0x02}
+
+// maxIndexEntriesInBatch is the maximum number of index entries that are written in a single batch while
+// building an index for the existing data of a namespace
+const maxIndexEntriesInBatch = 1000
+
+// Type tags for the order preserving encoding of the JSON values. The order of the tags follows the collation
+// of CouchDB, i.e., null < false < true < numbers < strings < arrays < objects
+const (
+	typeNull byte = iota + 1
+	typeFalse
+	typeTrue
+	typeNumber
+	typeString
+	typeArray
+	typeObject
+)
+
+// indexDefinition captures a secondary index on one or more fields of the JSON values in a namespace
+type indexDefinition struct {
+	Name   string   `json:"name"`
+	DDoc   string   `json:"ddoc,omitempty"`
+	Fields []string `json:"fields"`
+}
+
+// indexMgr maintains the secondary indexes of a state db
+type indexMgr struct {
+	db *leveldbhelper.DBHandle
+	// lock synchronizes the building of an index with the maintenance of the indexes during commit
+	lock    sync.RWMutex
+	indexes map[string]map[string]*indexDefinition
+}
+
+// newIndexMgr loads the index definitions that are present in the db
+func newIndexMgr(db *leveldbhelper.DBHandle) (*indexMgr, error) {
+	mgr := &indexMgr{db: db, indexes: make(map[string]map[string]*indexDefinition)}
+	itr := db.GetIterator(indexDefinitionKeyPrefix, indexEntryKeyPrefix)
+	defer itr.Release()
+	for itr.Next() {
+		ns, _ := splitCompositeKey(itr.Key()[len(indexDefinitionKeyPrefix):])
+		def := &indexDefinition{}
+		if err := json.Unmarshal(itr.Value(), def); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshalling index definition for namespace [%s]", ns)
+		}
+		mgr.addToCache(ns, def)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrap(err, "error while loading index definitions")
+	}
+	return mgr, nil
+}
+
+// getIndexes returns the index definitions of the namespace, sorted by the index name
+func (mgr *indexMgr) getIndexes(namespace string) []*indexDefinition {
+	mgr.lock.RLock()
+	defer mgr.lock.RUnlock()
+	var defs []*indexDefinition
+	for _, def := range mgr.indexes[namespace] {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// processIndexFiles creates or updates the indexes defined in the given files for the namespace.
+// The files are expected in the same format as the CouchDB index definitions
+func (mgr *indexMgr) processIndexFiles(namespace string, fileEntries []*ccprovider.TarFileEntry) error {
+	for _, fileEntry := range fileEntries {
+		def, err := parseIndexDefinition(fileEntry.FileContent)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf(
+				"error parsing index from file [%s] for namespace [%s]", fileEntry.FileHeader.Name, namespace))
+		}
+		if err := mgr.createIndex(namespace, def); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf(
+				"error creating index from file [%s] for namespace [%s]", fileEntry.FileHeader.Name, namespace))
+		}
+	}
+	return nil
+}
+
+// createIndex builds the index entries for the existing data of the namespace and persists the index definition.
+// If an index with the same name and fields already exists, this function is a noop
+func (mgr *indexMgr) createIndex(namespace string, def *indexDefinition) error {
+	mgr.lock.Lock()
+	defer mgr.lock.Unlock()
+
+	if existing, ok := mgr.indexes[namespace][def.Name]; ok && existing.sameAs(def) {
+		logger.Debugf("Index [%s] already exists for namespace [%s]", def.Name, namespace)
+		return nil
+	}
+	logger.Infof("Building index [%s] on fields %s for namespace [%s]", def.Name, def.Fields, namespace)
+
+	// remove the entries of an older version of the index, if any
+	entriesPrefix := indexEntriesPrefix(namespace, def.Name)
+	batch := leveldbhelper.NewUpdateBatch()
+	itr := mgr.db.GetIterator(entriesPrefix, prefixSuccessor(entriesPrefix))
+	for itr.Next() {
+		batch.Delete(copyBytes(itr.Key()))
+		if err := mgr.writeBatchIfFull(&batch); err != nil {
+			itr.Release()
+			return err
+		}
+	}
+	itr.Release()
+
+	itr = mgr.db.GetIterator(constructCompositeKey(namespace, ""), namespaceEndKey(namespace))
+	defer itr.Release()
+	for itr.Next() {
+		_, key := splitCompositeKey(itr.Key())
+		vv, err := decodeValue(copyBytes(itr.Value()))
+		if err != nil {
+			return err
+		}
+		if entry := def.entryKey(namespace, key, parseJSONObject(vv.Value)); entry != nil {
+			batch.Put(entry, []byte(key))
+		}
+		if err := mgr.writeBatchIfFull(&batch); err != nil {
+			return err
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrapf(err, "error while iterating over namespace [%s]", namespace)
+	}
+
+	defBytes, err := json.Marshal(def)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling index definition")
+	}
+	batch.Put(indexDefinitionKey(namespace, def.Name), defBytes)
+	if err := mgr.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	mgr.addToCache(namespace, def)
+	return nil
+}
+
+func (mgr *indexMgr) writeBatchIfFull(batch **leveldbhelper.UpdateBatch) error {
+	if (*batch).Len() < maxIndexEntriesInBatch {
+		return nil
+	}
+	if err := mgr.db.WriteBatch(*batch, true); err != nil {
+		return err
+	}
+	*batch = leveldbhelper.NewUpdateBatch()
+	return nil
+}
+
+// lockForCommit blocks the building of the indexes until the returned function is invoked
+func (mgr *indexMgr) lockForCommit() (unlock func()) {
+	mgr.lock.RLock()
+	return mgr.lock.RUnlock
+}
+
+// addIndexUpdates adds the changes in the index entries, which result from the given updates, to the dbBatch.
+// The caller is expected to hold the lock obtained via the function lockForCommit
+func (mgr *indexMgr) addIndexUpdates(dbBatch *leveldbhelper.UpdateBatch, updates *statedb.UpdateBatch) error {
+	for _, ns := range updates.GetUpdatedNamespaces() {
+		indexes := mgr.indexes[ns]
+		if len(indexes) == 0 {
+			continue
+		}
+		for key, vv := range updates.GetUpdates(ns) {
+			oldDoc, err := mgr.committedDoc(ns, key)
+			if err != nil {
+				return err
+			}
+			newDoc := parseJSONObject(vv.Value)
+			for _, def := range indexes {
+				if oldEntry := def.entryKey(ns, key, oldDoc); oldEntry != nil {
+					dbBatch.Delete(oldEntry)
+				}
+			}
+			for _, def := range indexes {
+				if newEntry := def.entryKey(ns, key, newDoc); newEntry != nil {
+					dbBatch.Put(newEntry, []byte(key))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (mgr *indexMgr) committedDoc(ns, key string) (map[string]interface{}, error) {
+	dbVal, err := mgr.db.Get(constructCompositeKey(ns, key))
+	if err != nil || dbVal == nil {
+		return nil, err
+	}
+	vv, err := decodeValue(dbVal)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONObject(vv.Value), nil
+}
+
+func (mgr *indexMgr) addToCache(namespace string, def *indexDefinition) {
+	nsIndexes, ok := mgr.indexes[namespace]
+	if !ok {
+		nsIndexes = make(map[string]*indexDefinition)
+		mgr.indexes[namespace] = nsIndexes
+	}
+	nsIndexes[def.Name] = def
+}
+
+// parseIndexDefinition parses an index definition in the CouchDB format, e.g.,
+// {"index":{"fields":["docType","owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}
+func parseIndexDefinition(indexBytes []byte) (*indexDefinition, error) {
+	indexJSON := &struct {
+		Index struct {
+			Fields []interface{} `json:"fields"`
+		} `json:"index"`
+		DDoc string `json:"ddoc"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(indexBytes, indexJSON); err != nil {
+		return nil, errors.Wrap(err, "index definition is not a valid JSON")
+	}
+	if indexJSON.Type != "" && indexJSON.Type != "json" {
+		return nil, errors.Errorf("index type [%s] is not supported", indexJSON.Type)
+	}
+	name := indexJSON.Name
+	if name == "" {
+		name = indexJSON.DDoc
+	}
+	if name == "" || strings.ContainsRune(name, 0) {
+		return nil, errors.New("index definition must include a valid name")
+	}
+	if len(indexJSON.Index.Fields) == 0 {
+		return nil, errors.New("index definition must include the fields")
+	}
+	def := &indexDefinition{Name: name, DDoc: indexJSON.DDoc}
+	for _, f := range indexJSON.Index.Fields {
+		switch field := f.(type) {
+		case string:
+			def.Fields = append(def.Fields, field)
+		case map[string]interface{}:
+			// the sort direction is ignored as an index can be scanned in both the directions
+			if len(field) != 1 {
+				return nil, errors.New("index field must be in the form {\"fieldname\":\"sort\"}")
+			}
+			for name := range field {
+				def.Fields = append(def.Fields, name)
+			}
+		default:
+			return nil, errors.Errorf("invalid index field [%v]", f)
+		}
+	}
+	return def, nil
+}
+
+func (def *indexDefinition) sameAs(other *indexDefinition) bool {
+	if def.DDoc != other.DDoc || len(def.Fields) != len(other.Fields) {
+		return false
+	}
+	for i := range def.Fields {
+		if def.Fields[i] != other.Fields[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// entryKey returns the key of the index entry for the given document. A document is included in an index only if
+// all the indexed fields are present in the document. A nil key is returned if the document is not to be indexed
+func (def *indexDefinition) entryKey(ns, key string, doc map[string]interface{}) []byte {
+	if doc == nil {
+		return nil
+	}
+	entryKey := indexEntriesPrefix(ns, def.Name)
+	for _, field := range def.Fields {
+		val, ok := lookupField(doc, field)
+		if !ok {
+			return nil
+		}
+		entryKey = append(entryKey, encodeIndexValue(val)...)
+	}
+	return append(entryKey, key...)
+}
+
+func indexDefinitionKey(ns, indexName string) []byte {
+	return append(append([]byte{}, indexDefinitionKeyPrefix...), constructCompositeKey(ns, indexName)...)
+}
+
+func indexEntriesPrefix(ns, indexName string) []byte {
+	k := append(append([]byte{}, indexEntryKeyPrefix...), constructCompositeKey(ns, indexName)...)
+	return append(k, compositeKeySep...)
+}
+
+func namespaceEndKey(ns string) []byte {
+	endKey := constructCompositeKey(ns, "")
+	endKey[len(endKey)-1] = lastKeyIndicator
+	return endKey
+}
+
+// isIndexKey returns true if the given db key belongs to an index definition or an index entry
+func isIndexKey(dbKey []byte) bool {
+	return bytes.HasPrefix(dbKey, indexDefinitionKeyPrefix) || bytes.HasPrefix(dbKey, indexEntryKeyPrefix)
+}
+
+// parseJSONObject returns the value as a JSON object. A nil map is returned if the value is not a JSON object
+func parseJSONObject(value []byte) map[string]interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(value, &doc); err != nil {
+		return nil
+	}
+	return doc
+}
+
+// lookupField returns the value of a field in the document. Nested fields are specified using the dot notation
+func lookupField(doc map[string]interface{}, field string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range strings.Split(field, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// encodeIndexValue encodes a JSON value such that the byte-wise order of the encoded values follows the
+// order of the values. The encoding is prefix-free so that the encoded values of multiple fields can be
+// concatenated. Strings are compared by their bytes and arrays and objects are compared by their JSON encoding
+func encodeIndexValue(val interface{}) []byte {
+	switch v := val.(type) {
+	case nil:
+		return []byte{typeNull}
+	case bool:
+		if v {
+			return []byte{typeTrue}
+		}
+		return []byte{typeFalse}
+	case float64:
+		bits := math.Float64bits(v)
+		if v >= 0 {
+			bits ^= 1 << 63
+		} else {
+			bits = ^bits
+		}
+		encoded := make([]byte, 9)
+		encoded[0] = typeNumber
+		binary.BigEndian.PutUint64(encoded[1:], bits)
+		return encoded
+	case string:
+		return encodeIndexBytes(typeString, []byte(v))
+	case []interface{}:
+		b, _ := json.Marshal(v)
+		return encodeIndexBytes(typeArray, b)
+	default:
+		b, _ := json.Marshal(v)
+		return encodeIndexBytes(typeObject, b)
+	}
+}
+
+// encodeIndexBytes escapes the 0x00 bytes as 0x00 0xFF and terminates the bytes with 0x00 0x01
+func encodeIndexBytes(typeTag byte, b []byte) []byte {
+	encoded := make([]byte, 0, len(b)+3)
+	encoded = append(encoded, typeTag)
+	for _, c := range b {
+		if c == 0x00 {
+			encoded = append(encoded, 0x00, 0xFF)
+			continue
+		}
+		encoded = append(encoded, c)
+	}
+	return append(encoded, 0x00, 0x01)
+}
+
+// compareValues compares two JSON values as per the order used by the indexes
+func compareValues(a, b interface{}) int {
+	return bytes.Compare(encodeIndexValue(a), encodeIndexValue(b))
+}
+
+// prefixSuccessor returns the smallest key that is larger than all the keys that begin with the given prefix.
+// A nil key is returned if no such key exists
+func prefixSuccessor(prefix []byte) []byte {
+	successor := copyBytes(prefix)
+	for i := len(successor) - 1; i >= 0; i-- {
+		if successor[i] != 0xFF {
+			successor[i]++
+			return successor[:i+1]
+		}
+	}
+	return nil
+}
+
+func copyBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
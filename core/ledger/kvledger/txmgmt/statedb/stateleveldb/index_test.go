@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"archive/tar"
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeIndexValueOrder(t *testing.T) {
+	orderedValues := []interface{}{
+		nil,
+		false,
+		true,
+		float64(-1000.5),
+		float64(-1),
+		float64(0),
+		float64(0.5),
+		float64(2),
+		float64(1000007),
+		"",
+		"a",
+		"a\x00",
+		"a\x00b",
+		"ab",
+		"b",
+		[]interface{}{"a"},
+		map[string]interface{}{"a": "b"},
+	}
+	encodedValues := make([][]byte, len(orderedValues))
+	for i, v := range orderedValues {
+		encodedValues[i] = encodeIndexValue(v)
+	}
+	assert.True(t, sort.SliceIsSorted(encodedValues, func(i, j int) bool {
+		return bytes.Compare(encodedValues[i], encodedValues[j]) < 0
+	}))
+	for i := 1; i < len(encodedValues); i++ {
+		assert.False(t, bytes.HasPrefix(encodedValues[i], encodedValues[i-1]),
+			"encoding of [%v] is a prefix of the encoding of [%v]", orderedValues[i-1], orderedValues[i])
+	}
+	assert.Equal(t, 0, compareValues(float64(1), float64(1.0)))
+}
+
+func TestPrefixSuccessor(t *testing.T) {
+	assert.Equal(t, []byte{0x01, 0x03}, prefixSuccessor([]byte{0x01, 0x02}))
+	assert.Equal(t, []byte{0x02}, prefixSuccessor([]byte{0x01, 0xFF}))
+	assert.Nil(t, prefixSuccessor([]byte{0xFF, 0xFF}))
+}
+
+func TestParseIndexDefinition(t *testing.T) {
+	def, err := parseIndexDefinition([]byte(`{"index":{"fields":["docType",{"owner":"desc"}]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &indexDefinition{Name: "indexOwner", DDoc: "indexOwnerDoc", Fields: []string{"docType", "owner"}}, def)
+
+	def, err = parseIndexDefinition([]byte(`{"index":{"fields":["size"]},"ddoc":"indexSizeDoc"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "indexSizeDoc", def.Name)
+
+	testCases := []struct {
+		index         string
+		expectedError string
+	}{
+		{`not json`, "index definition is not a valid JSON"},
+		{`{"index":{"fields":["size"]},"name":"indexSize","type":"text"}`, "index type [text] is not supported"},
+		{`{"index":{"fields":["size"]}}`, "index definition must include a valid name"},
+		{`{"index":{"fields":[]},"name":"indexSize"}`, "index definition must include the fields"},
+		{`{"index":{"fields":[1]},"name":"indexSize"}`, "invalid index field [1]"},
+	}
+	for _, testCase := range testCases {
+		_, err := parseIndexDefinition([]byte(testCase.index))
+		assert.Contains(t, err.Error(), testCase.expectedError)
+	}
+}
+
+func TestIndexMaintenance(t *testing.T) {
+	viper.Set("ledger.state.levelDBConfig.enableRichQuery", true)
+	defer viper.Set("ledger.state.levelDBConfig.enableRichQuery", false)
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testindexmaintenance")
+	require.NoError(t, err)
+	_, ok := db.(statedb.IndexCapable)
+	require.True(t, ok)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"owner":"tom","size":1}`), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte(`{"owner":"jerry","size":2}`), version.NewHeight(1, 2))
+	batch.Put("ns1", "key3", []byte(`{"size":3}`), version.NewHeight(1, 3))
+	batch.Put("ns1", "key4", []byte(`non-json-value`), version.NewHeight(1, 4))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)))
+
+	// the index is built for the existing data
+	require.NoError(t, db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1",
+		[]*ccprovider.TarFileEntry{indexFileEntry("indexOwner.json", `{"index":{"fields":["owner"]},"name":"indexOwner","type":"json"}`)}))
+	vdb := db.(*indexCapableVersionedDB).versionedDB
+	assert.Equal(t, []string{"key2", "key1"}, indexedKeys(t, vdb, "ns1", "indexOwner"))
+
+	// the index is maintained during commit
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"owner":"bob","size":1}`), version.NewHeight(2, 1))
+	batch.Delete("ns1", "key2", version.NewHeight(2, 2))
+	batch.Put("ns1", "key3", []byte(`{"owner":"alice","size":3}`), version.NewHeight(2, 3))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 3)))
+	assert.Equal(t, []string{"key3", "key1"}, indexedKeys(t, vdb, "ns1", "indexOwner"))
+
+	// the index keys are not exposed via the full scan
+	itr, err := vdb.GetFullScanIterator()
+	require.NoError(t, err)
+	defer itr.Close()
+	var keys []string
+	for {
+		res, err := itr.Next()
+		require.NoError(t, err)
+		if res == nil {
+			break
+		}
+		keys = append(keys, res.(*statedb.VersionedKV).Key)
+	}
+	assert.Equal(t, []string{"key1", "key3", "key4"}, keys)
+
+	// the index definitions are loaded when the db is opened again
+	env.DBProvider.Close()
+	env.DBProvider = NewVersionedDBProvider()
+	db, err = env.DBProvider.GetDBHandle("testindexmaintenance")
+	require.NoError(t, err)
+	vdb = db.(*indexCapableVersionedDB).versionedDB
+	assert.Equal(t, []*indexDefinition{{Name: "indexOwner", Fields: []string{"owner"}}}, vdb.indexMgr.getIndexes("ns1"))
+
+	// an index with changed fields replaces the older index
+	require.NoError(t, db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1",
+		[]*ccprovider.TarFileEntry{indexFileEntry("indexOwner.json", `{"index":{"fields":["size"]},"name":"indexOwner","type":"json"}`)}))
+	assert.Equal(t, []string{"key1", "key3"}, indexedKeys(t, vdb, "ns1", "indexOwner"))
+
+	err = db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1",
+		[]*ccprovider.TarFileEntry{indexFileEntry("badIndex.json", `{"index":{"fields":["size"]}}`)})
+	assert.Contains(t, err.Error(), "error parsing index from file [META-INF/statedb/leveldb/indexes/badIndex.json] for namespace [ns1]")
+}
+
+func TestRichQueryDisabled(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testrichquerydisabled")
+	require.NoError(t, err)
+	_, ok := db.(statedb.IndexCapable)
+	assert.False(t, ok)
+	_, err = db.ExecuteQueryWithMetadata("ns1", `{"selector":{"owner":"jerry"}}`, nil)
+	assert.EqualError(t, err, "ExecuteQueryWithMetadata not supported for leveldb")
+}
+
+func indexFileEntry(name, content string) *ccprovider.TarFileEntry {
+	return &ccprovider.TarFileEntry{
+		FileHeader:  &tar.Header{Name: "META-INF/statedb/leveldb/indexes/" + name},
+		FileContent: []byte(content),
+	}
+}
+
+func indexedKeys(t *testing.T, vdb *versionedDB, ns, indexName string) []string {
+	prefix := indexEntriesPrefix(ns, indexName)
+	itr := vdb.db.GetIterator(prefix, prefixSuccessor(prefix))
+	defer itr.Release()
+	var keys []string
+	for itr.Next() {
+		keys = append(keys, string(itr.Value()))
+	}
+	require.NoError(t, itr.Error())
+	return keys
+}
@@ -0,0 +1,587 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+)
+
+// query captures the supported subset of a CouchDB Mango query, e.g.,
+// {"selector":{"docType":"marble","size":{"$gt":10}},"sort":[{"size":"desc"}],"limit":10,"use_index":"indexSize"}
+type query struct {
+	selector  condition
+	sortBy    []string
+	sortDesc  bool
+	limit     int32
+	bookmark  string
+	useIndex  []string
+	fields    []string
+	namespace string
+}
+
+// condition is a node in the parsed selector
+type condition interface {
+	matches(doc map[string]interface{}) bool
+}
+
+type andCondition []condition
+
+type orCondition []condition
+
+type notCondition struct {
+	condition
+}
+
+// fieldCondition applies an operator on the value of a field
+type fieldCondition struct {
+	field    string
+	operator string
+	operand  interface{}
+}
+
+func (c andCondition) matches(doc map[string]interface{}) bool {
+	for _, sub := range c {
+		if !sub.matches(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c orCondition) matches(doc map[string]interface{}) bool {
+	for _, sub := range c {
+		if sub.matches(doc) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c notCondition) matches(doc map[string]interface{}) bool {
+	return !c.condition.matches(doc)
+}
+
+func (c *fieldCondition) matches(doc map[string]interface{}) bool {
+	val, present := lookupField(doc, c.field)
+	if c.operator == "$exists" {
+		return present == c.operand.(bool)
+	}
+	if !present {
+		return false
+	}
+	switch c.operator {
+	case "$eq":
+		return compareValues(val, c.operand) == 0
+	case "$ne":
+		return compareValues(val, c.operand) != 0
+	case "$gt":
+		return compareValues(val, c.operand) > 0
+	case "$gte":
+		return compareValues(val, c.operand) >= 0
+	case "$lt":
+		return compareValues(val, c.operand) < 0
+	case "$lte":
+		return compareValues(val, c.operand) <= 0
+	case "$in":
+		for _, candidate := range c.operand.([]interface{}) {
+			if compareValues(val, candidate) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// impliesExistence returns true if the condition can be satisfied only by the documents that contain the field
+func (c *fieldCondition) impliesExistence() bool {
+	return c.operator != "$exists" || c.operand.(bool)
+}
+
+// parseQuery parses the query string. Only the selector, sort, limit, bookmark, use_index and fields
+// attributes are supported
+func parseQuery(namespace, queryString string) (*query, error) {
+	queryJSON := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(queryString), &queryJSON); err != nil {
+		return nil, errors.Wrap(err, "query is not a valid JSON")
+	}
+	q := &query{namespace: namespace}
+	selectorFound := false
+	for attribute, val := range queryJSON {
+		var err error
+		switch attribute {
+		case "selector":
+			selectorJSON, ok := val.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("selector must be a JSON object")
+			}
+			q.selector, err = parseSelector(selectorJSON, "")
+			selectorFound = true
+		case "sort":
+			q.sortBy, q.sortDesc, err = parseSort(val)
+		case "limit":
+			limit, ok := val.(float64)
+			if !ok || limit < 0 || limit != float64(int32(limit)) {
+				return nil, errors.New("limit must be a non-negative integer")
+			}
+			q.limit = int32(limit)
+		case "bookmark":
+			bookmark, ok := val.(string)
+			if !ok {
+				return nil, errors.New("bookmark must be a string")
+			}
+			q.bookmark = bookmark
+		case "use_index":
+			q.useIndex, err = toStringSlice(val, "use_index")
+		case "fields":
+			q.fields, err = toStringSlice(val, "fields")
+		default:
+			return nil, errors.Errorf("query attribute [%s] is not supported", attribute)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !selectorFound {
+		return nil, errors.New("query must include a selector")
+	}
+	return q, nil
+}
+
+func parseSelector(selectorJSON map[string]interface{}, fieldPrefix string) (condition, error) {
+	var conditions andCondition
+	for key, val := range selectorJSON {
+		switch {
+		case key == "$and" || key == "$or":
+			subSelectors, ok := val.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("operator [%s] requires an array of selectors", key)
+			}
+			var subConditions []condition
+			for _, s := range subSelectors {
+				subSelectorJSON, ok := s.(map[string]interface{})
+				if !ok {
+					return nil, errors.Errorf("operator [%s] requires an array of selectors", key)
+				}
+				subCondition, err := parseSelector(subSelectorJSON, fieldPrefix)
+				if err != nil {
+					return nil, err
+				}
+				subConditions = append(subConditions, subCondition)
+			}
+			if key == "$and" {
+				conditions = append(conditions, andCondition(subConditions))
+			} else {
+				conditions = append(conditions, orCondition(subConditions))
+			}
+		case key == "$not":
+			subSelectorJSON, ok := val.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("operator [$not] requires a selector")
+			}
+			subCondition, err := parseSelector(subSelectorJSON, fieldPrefix)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, notCondition{subCondition})
+		case strings.HasPrefix(key, "$"):
+			return nil, errors.Errorf("operator [%s] is not supported", key)
+		default:
+			fieldConditions, err := parseFieldSelector(fieldPrefix+key, val)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, fieldConditions...)
+		}
+	}
+	return conditions, nil
+}
+
+// parseFieldSelector parses the selector for a field, e.g., "marble", {"$gt":10,"$lt":20}, or a nested selector
+// such as {"name":"tom"} for the field "owner" that is equivalent to the field "owner.name"
+func parseFieldSelector(field string, val interface{}) ([]condition, error) {
+	operators, ok := val.(map[string]interface{})
+	if !ok || len(operators) == 0 {
+		return []condition{&fieldCondition{field, "$eq", val}}, nil
+	}
+	var conditions []condition
+	for operator, operand := range operators {
+		if !strings.HasPrefix(operator, "$") {
+			nested, err := parseSelector(map[string]interface{}{operator: operand}, field+".")
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, nested.(andCondition)...)
+			continue
+		}
+		switch operator {
+		case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte":
+		case "$in":
+			if _, ok := operand.([]interface{}); !ok {
+				return nil, errors.New("operator [$in] requires an array")
+			}
+		case "$exists":
+			if _, ok := operand.(bool); !ok {
+				return nil, errors.New("operator [$exists] requires a boolean")
+			}
+		default:
+			return nil, errors.Errorf("operator [%s] is not supported", operator)
+		}
+		conditions = append(conditions, &fieldCondition{field, operator, operand})
+	}
+	return conditions, nil
+}
+
+// parseSort parses the sort attribute, e.g., ["owner","size"] or [{"owner":"desc"},{"size":"desc"}].
+// All the fields are required to be sorted in the same direction
+func parseSort(val interface{}) ([]string, bool, error) {
+	sortJSON, ok := val.([]interface{})
+	if !ok {
+		return nil, false, errors.New("sort must be an array")
+	}
+	var fields []string
+	var directions []bool
+	for _, s := range sortJSON {
+		switch sortField := s.(type) {
+		case string:
+			fields = append(fields, sortField)
+			directions = append(directions, false)
+		case map[string]interface{}:
+			if len(sortField) != 1 {
+				return nil, false, errors.New("sort field must be in the form {\"fieldname\":\"asc|desc\"}")
+			}
+			for field, direction := range sortField {
+				switch direction {
+				case "asc":
+					directions = append(directions, false)
+				case "desc":
+					directions = append(directions, true)
+				default:
+					return nil, false, errors.Errorf("sort direction [%v] must be either asc or desc", direction)
+				}
+				fields = append(fields, field)
+			}
+		default:
+			return nil, false, errors.New("sort field must be a string or an object")
+		}
+	}
+	for _, desc := range directions {
+		if desc != directions[0] {
+			return nil, false, errors.New("all the sort fields must use the same direction")
+		}
+	}
+	return fields, len(directions) > 0 && directions[0], nil
+}
+
+func toStringSlice(val interface{}, attribute string) ([]string, error) {
+	if s, ok := val.(string); ok {
+		return []string{s}, nil
+	}
+	arr, ok := val.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%s must be a string or an array of strings", attribute)
+	}
+	var strs []string
+	for _, v := range arr {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf("%s must be a string or an array of strings", attribute)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+// topLevelConditions returns the field conditions that are required to be satisfied by all the results
+func (q *query) topLevelConditions() map[string][]*fieldCondition {
+	conditions := map[string][]*fieldCondition{}
+	var collect func(c condition)
+	collect = func(c condition) {
+		switch cond := c.(type) {
+		case andCondition:
+			for _, sub := range cond {
+				collect(sub)
+			}
+		case *fieldCondition:
+			conditions[cond.field] = append(conditions[cond.field], cond)
+		}
+	}
+	collect(q.selector)
+	return conditions
+}
+
+// scanPlan captures the key range that is to be scanned for evaluating a query
+type scanPlan struct {
+	index    *indexDefinition
+	startKey []byte
+	endKey   []byte
+}
+
+// planScan selects the index for the query. A full scan of the namespace is planned if no index is usable,
+// unless the query requires sorting. An index is usable only if each of the indexed fields is either
+// constrained by the selector or is used for sorting, because the documents that do not contain
+// all the indexed fields are not included in the index
+func (q *query) planScan(indexes []*indexDefinition) (*scanPlan, error) {
+	conditions := q.topLevelConditions()
+	var bestPlan *scanPlan
+	bestScore := -1
+	for _, index := range indexes {
+		if len(q.useIndex) > 0 && !q.indexRequested(index) {
+			continue
+		}
+		plan, score, usable := q.planIndexScan(index, conditions)
+		if usable && score > bestScore {
+			bestPlan, bestScore = plan, score
+		}
+	}
+	switch {
+	case bestPlan != nil:
+		return bestPlan, nil
+	case len(q.useIndex) > 0:
+		return nil, errors.Errorf("index %s is not usable for the query", q.useIndex)
+	case len(q.sortBy) > 0:
+		return nil, errors.Errorf("no index exists for the sort fields %s", q.sortBy)
+	}
+	return &scanPlan{
+		startKey: constructCompositeKey(q.namespace, ""),
+		endKey:   namespaceEndKey(q.namespace),
+	}, nil
+}
+
+func (q *query) indexRequested(index *indexDefinition) bool {
+	switch len(q.useIndex) {
+	case 1:
+		return q.useIndex[0] == index.DDoc || q.useIndex[0] == index.Name
+	case 2:
+		return q.useIndex[0] == index.DDoc && q.useIndex[1] == index.Name
+	}
+	return false
+}
+
+// planIndexScan computes the range of the index entries to be scanned. The range is narrowed by the equality
+// conditions on the leading fields of the index, followed by the range conditions on the next field. The returned
+// score prefers the indexes that narrow the range on a larger number of fields
+func (q *query) planIndexScan(index *indexDefinition, conditions map[string][]*fieldCondition) (*scanPlan, int, bool) {
+	sortFields := map[string]bool{}
+	for _, f := range q.sortBy {
+		sortFields[f] = true
+	}
+	for _, f := range index.Fields {
+		if !sortFields[f] && !anyImpliesExistence(conditions[f]) {
+			return nil, 0, false
+		}
+	}
+
+	prefix := indexEntriesPrefix(q.namespace, index.Name)
+	numEqualityFields := 0
+	for _, f := range index.Fields {
+		eq := findCondition(conditions[f], "$eq")
+		if eq == nil {
+			break
+		}
+		prefix = append(prefix, encodeIndexValue(eq.operand)...)
+		numEqualityFields++
+	}
+	if len(q.sortBy) > 0 && !sortSatisfied(index.Fields, numEqualityFields, q.sortBy) {
+		return nil, 0, false
+	}
+
+	plan := &scanPlan{index: index, startKey: prefix, endKey: prefixSuccessor(prefix)}
+	score := 2 * numEqualityFields
+	if numEqualityFields < len(index.Fields) {
+		f := index.Fields[numEqualityFields]
+		if lower := findCondition(conditions[f], "$gt", "$gte"); lower != nil {
+			plan.startKey = append(copyBytes(prefix), encodeIndexValue(lower.operand)...)
+			if lower.operator == "$gt" {
+				plan.startKey = prefixSuccessor(plan.startKey)
+			}
+			score++
+		}
+		if upper := findCondition(conditions[f], "$lt", "$lte"); upper != nil {
+			plan.endKey = append(copyBytes(prefix), encodeIndexValue(upper.operand)...)
+			if upper.operator == "$lte" {
+				plan.endKey = prefixSuccessor(plan.endKey)
+			}
+			score++
+		}
+	}
+	return plan, score, true
+}
+
+// sortSatisfied returns true if the sort fields appear in the index fields contiguously, such that
+// all the index fields preceding the sort fields are constrained by equality conditions
+func sortSatisfied(indexFields []string, numEqualityFields int, sortBy []string) bool {
+	for start := 0; start <= numEqualityFields && start+len(sortBy) <= len(indexFields); start++ {
+		matched := true
+		for i, f := range sortBy {
+			if indexFields[start+i] != f {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func anyImpliesExistence(conditions []*fieldCondition) bool {
+	for _, c := range conditions {
+		if c.impliesExistence() {
+			return true
+		}
+	}
+	return false
+}
+
+func findCondition(conditions []*fieldCondition, operators ...string) *fieldCondition {
+	for _, c := range conditions {
+		for _, op := range operators {
+			if c.operator == op {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// applyBookmark narrows the scan range such that the scan resumes after the key recorded in the bookmark
+func (plan *scanPlan) applyBookmark(bookmark string, desc bool) error {
+	if bookmark == "" {
+		return nil
+	}
+	lastKey, err := hex.DecodeString(bookmark)
+	if err != nil || bytes.Compare(lastKey, plan.startKey) < 0 ||
+		(plan.endKey != nil && bytes.Compare(lastKey, plan.endKey) >= 0) {
+		return errors.Errorf("invalid bookmark [%s] for the query", bookmark)
+	}
+	if desc {
+		plan.endKey = lastKey
+	} else {
+		plan.startKey = append(lastKey, 0x00)
+	}
+	return nil
+}
+
+// queryScanner evaluates a query by scanning the range of the keys selected by the scan plan
+type queryScanner struct {
+	vdb                  *versionedDB
+	query                *query
+	plan                 *scanPlan
+	dbItr                iterator.Iterator
+	desc                 bool
+	started              bool
+	requestedLimit       int32
+	totalRecordsReturned int32
+	bookmark             string
+}
+
+func newQueryScanner(vdb *versionedDB, q *query, plan *scanPlan, requestedLimit int32, bookmark string) *queryScanner {
+	return &queryScanner{
+		vdb:            vdb,
+		query:          q,
+		plan:           plan,
+		dbItr:          vdb.db.GetIterator(plan.startKey, plan.endKey),
+		desc:           q.sortDesc,
+		requestedLimit: requestedLimit,
+		bookmark:       bookmark,
+	}
+}
+
+func (scanner *queryScanner) advance() bool {
+	if scanner.desc && !scanner.started {
+		scanner.started = true
+		return scanner.dbItr.Last()
+	}
+	if scanner.desc {
+		return scanner.dbItr.Prev()
+	}
+	return scanner.dbItr.Next()
+}
+
+// Next implements method in ResultsIterator interface
+func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
+	for scanner.advance() {
+		dbKey := scanner.dbItr.Key()
+		var key string
+		var vv *statedb.VersionedValue
+		var err error
+		if scanner.plan.index != nil {
+			key = string(scanner.dbItr.Value())
+			vv, err = scanner.vdb.GetState(scanner.query.namespace, key)
+		} else {
+			_, key = splitCompositeKey(dbKey)
+			vv, err = decodeValue(copyBytes(scanner.dbItr.Value()))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if vv == nil {
+			continue
+		}
+		doc := parseJSONObject(vv.Value)
+		if doc == nil || !scanner.query.selector.matches(doc) {
+			continue
+		}
+		if len(scanner.query.fields) > 0 {
+			if vv.Value, err = projectFields(doc, scanner.query.fields); err != nil {
+				return nil, err
+			}
+		}
+		scanner.totalRecordsReturned++
+		scanner.bookmark = hex.EncodeToString(dbKey)
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: scanner.query.namespace, Key: key},
+			VersionedValue: *vv}, nil
+	}
+	return nil, errors.Wrap(scanner.dbItr.Error(), "error while executing the query")
+}
+
+// Close implements method in ResultsIterator interface
+func (scanner *queryScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// GetBookmarkAndClose implements method in QueryResultsIterator interface. The bookmark records
+// the key of the last returned result
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	scanner.Close()
+	return scanner.bookmark
+}
+
+// projectFields returns the JSON encoding of the document that contains only the given fields
+func projectFields(doc map[string]interface{}, fields []string) ([]byte, error) {
+	projected := map[string]interface{}{}
+	for _, field := range fields {
+		val, ok := lookupField(doc, field)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(field, ".")
+		current := projected
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = val
+	}
+	return json.Marshal(projected)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichQueryOnLevelDB(t *testing.T) {
+	viper.Set("ledger.state.levelDBConfig.enableRichQuery", true)
+	defer viper.Set("ledger.state.levelDBConfig.enableRichQuery", false)
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestQuery(t, env.DBProvider)
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	testCases := []struct {
+		query         string
+		expectedError string
+	}{
+		{`not json`, "query is not a valid JSON"},
+		{`{"sort":["owner"]}`, "query must include a selector"},
+		{`{"selector":"owner"}`, "selector must be a JSON object"},
+		{`{"selector":{"owner":"tom"},"skip":10}`, "query attribute [skip] is not supported"},
+		{`{"selector":{"owner":"tom"},"limit":-1}`, "limit must be a non-negative integer"},
+		{`{"selector":{"owner":"tom"},"bookmark":1}`, "bookmark must be a string"},
+		{`{"selector":{"owner":{"$regex":"^t"}}}`, "operator [$regex] is not supported"},
+		{`{"selector":{"$nor":[{"owner":"tom"}]}}`, "operator [$nor] is not supported"},
+		{`{"selector":{"$or":{"owner":"tom"}}}`, "operator [$or] requires an array of selectors"},
+		{`{"selector":{"owner":{"$in":"tom"}}}`, "operator [$in] requires an array"},
+		{`{"selector":{"owner":{"$exists":"yes"}}}`, "operator [$exists] requires a boolean"},
+		{`{"selector":{"owner":"tom"},"sort":[{"owner":"asc"},{"size":"desc"}]}`, "all the sort fields must use the same direction"},
+		{`{"selector":{"owner":"tom"},"sort":[{"owner":"up"}]}`, "sort direction [up] must be either asc or desc"},
+		{`{"selector":{"owner":"tom"},"use_index":[1]}`, "use_index must be a string or an array of strings"},
+	}
+	for _, testCase := range testCases {
+		_, err := parseQuery("ns", testCase.query)
+		assert.Contains(t, err.Error(), testCase.expectedError, testCase.query)
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	doc := parseJSONObject([]byte(`{"docType":"marble","size":10,"owner":{"name":"tom","age":30},"tags":["a","b"]}`))
+	testCases := []struct {
+		selector string
+		matches  bool
+	}{
+		{`{"docType":"marble"}`, true},
+		{`{"docType":"car"}`, false},
+		{`{"size":{"$gt":5,"$lte":10}}`, true},
+		{`{"size":{"$gte":11}}`, false},
+		{`{"size":{"$ne":10}}`, false},
+		{`{"size":{"$in":[1,10]}}`, true},
+		{`{"owner.name":"tom"}`, true},
+		{`{"owner":{"name":"tom","age":{"$lt":20}}}`, false},
+		{`{"color":{"$exists":false}}`, true},
+		{`{"color":{"$ne":"red"}}`, false},
+		{`{"tags":["a","b"]}`, true},
+		{`{"$or":[{"size":1},{"owner.age":30}]}`, true},
+		{`{"$and":[{"size":10},{"$not":{"docType":"marble"}}]}`, false},
+	}
+	for _, testCase := range testCases {
+		q, err := parseQuery("ns", fmt.Sprintf(`{"selector":%s}`, testCase.selector))
+		require.NoError(t, err)
+		assert.Equal(t, testCase.matches, q.selector.matches(doc), testCase.selector)
+	}
+}
+
+func TestQueryPlanning(t *testing.T) {
+	indexes := []*indexDefinition{
+		{Name: "indexDocTypeOwner", DDoc: "indexDocTypeOwnerDoc", Fields: []string{"docType", "owner"}},
+		{Name: "indexSize", DDoc: "indexSizeDoc", Fields: []string{"size"}},
+	}
+	testCases := []struct {
+		query         string
+		expectedIndex string
+		expectedError string
+	}{
+		{`{"selector":{"docType":"marble","owner":"tom"}}`, "indexDocTypeOwner", ""},
+		{`{"selector":{"docType":"marble"}}`, "", ""},
+		{`{"selector":{"docType":"marble"},"sort":["owner"]}`, "indexDocTypeOwner", ""},
+		{`{"selector":{"size":{"$gt":5}},"sort":[{"size":"desc"}]}`, "indexSize", ""},
+		{`{"selector":{"docType":"marble","owner":"tom","size":{"$gt":5}}}`, "indexDocTypeOwner", ""},
+		{`{"selector":{"docType":"marble","owner":"tom","size":{"$gt":5}},"use_index":"indexSizeDoc"}`, "indexSize", ""},
+		{`{"selector":{"docType":"marble","owner":"tom","size":{"$gt":5}},"use_index":["indexSizeDoc","indexSize"]}`, "indexSize", ""},
+		{`{"selector":{"$or":[{"size":1},{"size":2}]}}`, "", ""},
+		{`{"selector":{"docType":"marble"},"sort":["color"]}`, "", "no index exists for the sort fields [color]"},
+		{`{"selector":{"owner":"tom"},"sort":["owner"]}`, "", "no index exists for the sort fields [owner]"},
+		{`{"selector":{"docType":"marble"},"use_index":"indexSizeDoc"}`, "", "index [indexSizeDoc] is not usable for the query"},
+	}
+	for _, testCase := range testCases {
+		q, err := parseQuery("ns", testCase.query)
+		require.NoError(t, err)
+		plan, err := q.planScan(indexes)
+		if testCase.expectedError != "" {
+			assert.EqualError(t, err, testCase.expectedError, testCase.query)
+			continue
+		}
+		require.NoError(t, err, testCase.query)
+		if testCase.expectedIndex == "" {
+			assert.Nil(t, plan.index, testCase.query)
+		} else {
+			assert.Equal(t, testCase.expectedIndex, plan.index.Name, testCase.query)
+		}
+	}
+}
+
+func TestQueryWithIndexes(t *testing.T) {
+	viper.Set("ledger.state.levelDBConfig.enableRichQuery", true)
+	defer viper.Set("ledger.state.levelDBConfig.enableRichQuery", false)
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testquerywithindexes")
+	require.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	owners := []string{"tom", "jerry", "fred", "martha", "fred", "elaine", "fred", "elaine", "fred", "mary"}
+	for i, owner := range owners {
+		value := fmt.Sprintf(`{"docType":"marble","owner":"%s","size":%d}`, owner, i+1)
+		batch.Put("ns1", fmt.Sprintf("key%02d", i+1), []byte(value), version.NewHeight(1, uint64(i+1)))
+	}
+	batch.Put("ns1", "car1", []byte(`{"docType":"car","owner":"fred","size":100}`), version.NewHeight(1, 11))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 11)))
+	require.NoError(t, db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1",
+		[]*ccprovider.TarFileEntry{
+			indexFileEntry("indexOwner.json", `{"index":{"fields":["docType","owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`),
+			indexFileEntry("indexSize.json", `{"index":{"fields":["size"]},"ddoc":"indexSizeDoc","name":"indexSize","type":"json"}`),
+		}))
+
+	keys, _ := executeQuery(t, db, `{"selector":{"docType":"marble","owner":"fred"}}`, nil)
+	assert.Equal(t, []string{"key03", "key05", "key07", "key09"}, keys)
+
+	keys, _ = executeQuery(t, db, `{"selector":{"docType":"marble","owner":{"$gte":"fred","$lt":"mary"}},"sort":["docType","owner"]}`, nil)
+	assert.Equal(t, []string{"key03", "key05", "key07", "key09", "key02", "key04"}, keys)
+
+	keys, _ = executeQuery(t, db, `{"selector":{"size":{"$gt":7}},"sort":[{"size":"desc"}],"limit":3}`, nil)
+	assert.Equal(t, []string{"car1", "key10", "key09"}, keys)
+
+	keys, _ = executeQuery(t, db, `{"selector":{"$or":[{"size":1},{"owner":"mary"}]}}`, nil)
+	assert.Equal(t, []string{"key01", "key10"}, keys)
+
+	keys, _ = executeQuery(t, db, `{"selector":{"owner":"fred","size":{"$gt":0}},"use_index":"indexSizeDoc"}`, nil)
+	assert.Equal(t, []string{"key03", "key05", "key07", "key09", "car1"}, keys)
+
+	itr, err := db.ExecuteQuery("ns1", `{"selector":{"docType":"car"},"fields":["owner"]}`)
+	require.NoError(t, err)
+	res, err := itr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, `{"owner":"fred"}`, string(res.(*statedb.VersionedKV).Value))
+	itr.Close()
+
+	// paginated query using the bookmark
+	query := `{"selector":{"size":{"$gte":1}},"sort":[{"size":"desc"}]}`
+	var pages [][]string
+	bookmark := ""
+	for {
+		keys, nextBookmark := executeQuery(t, db, query, map[string]interface{}{"limit": int32(4), "bookmark": bookmark})
+		if len(keys) == 0 {
+			break
+		}
+		pages = append(pages, keys)
+		bookmark = nextBookmark
+	}
+	assert.Equal(t, [][]string{
+		{"car1", "key10", "key09", "key08"},
+		{"key07", "key06", "key05", "key04"},
+		{"key03", "key02", "key01"},
+	}, pages)
+
+	// the index is used to serve the queries on the updated data
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key03", []byte(`{"docType":"marble","owner":"tom","size":3}`), version.NewHeight(2, 1))
+	batch.Delete("ns1", "key05", version.NewHeight(2, 2))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 2)))
+	keys, _ = executeQuery(t, db, `{"selector":{"docType":"marble","owner":"fred"}}`, nil)
+	assert.Equal(t, []string{"key07", "key09"}, keys)
+
+	_, err = db.ExecuteQueryWithMetadata("ns1", `{"selector":{"size":{"$gte":1}}}`, map[string]interface{}{"bookmark": "not-a-bookmark"})
+	assert.EqualError(t, err, "invalid bookmark [not-a-bookmark] for the query")
+	_, err = db.ExecuteQueryWithMetadata("ns1", `{"selector":{"size":{"$gte":1}}}`, map[string]interface{}{"limit": 10})
+	assert.EqualError(t, err, `Invalid entry, "limit" must be an int32`)
+	_, err = db.ExecuteQueryWithMetadata("ns1", `{"selector":{"size":{"$gte":1}}}`, map[string]interface{}{"skip": int32(10)})
+	assert.EqualError(t, err, "Invalid entry, option skip not recognized")
+}
+
+func executeQuery(t *testing.T, db statedb.VersionedDB, query string, metadata map[string]interface{}) ([]string, string) {
+	itr, err := db.ExecuteQueryWithMetadata("ns1", query, metadata)
+	require.NoError(t, err)
+	var keys []string
+	for {
+		res, err := itr.Next()
+		require.NoError(t, err)
+		if res == nil {
+			break
+		}
+		keys = append(keys, res.(*statedb.VersionedKV).Key)
+	}
+	return keys, itr.GetBookmarkAndClose()
+}
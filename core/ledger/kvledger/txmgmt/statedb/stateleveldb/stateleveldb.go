@@ -10,6 +10,8 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -28,6 +30,12 @@ type VersionedDBProvider struct {
 	dbProvider *leveldbhelper.Provider
 }
 
+func init() {
+	statedb.RegisterVersionedDBProvider(ledgerconfig.StateDatabaseGoLevelDB, func(metrics.Provider) (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider(), nil
+	})
+}
+
 // NewVersionedDBProvider instantiates VersionedDBProvider
 func NewVersionedDBProvider() *VersionedDBProvider {
 	dbPath := ledgerconfig.GetStateLevelDBPath()
@@ -36,9 +44,25 @@ func NewVersionedDBProvider() *VersionedDBProvider {
 	return &VersionedDBProvider{dbProvider}
 }
 
-// GetDBHandle gets the handle to a named database
+// GetDBHandle gets the handle to a named database. If the rich queries are enabled for leveldb,
+// the returned VersionedDB maintains the secondary indexes and implements the interface statedb.IndexCapable
 func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.VersionedDB, error) {
-	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	vdb := newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName)
+	if !ledgerconfig.IsLevelDBRichQueryEnabled() {
+		return vdb, nil
+	}
+	indexMgr, err := newIndexMgr(vdb.db)
+	if err != nil {
+		return nil, err
+	}
+	vdb.indexMgr = indexMgr
+	return &indexCapableVersionedDB{vdb}, nil
+}
+
+// Drop implements method in the interface statedb.Droppable. The secondary indexes
+// of the rich queries are stored in the same named db and are dropped along with the data
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
 }
 
 // Close closes the underlying db
@@ -50,11 +74,28 @@ func (provider *VersionedDBProvider) Close() {
 type versionedDB struct {
 	db     *leveldbhelper.DBHandle
 	dbName string
+	// indexMgr is nil if the rich queries are not enabled
+	indexMgr *indexMgr
 }
 
 // newVersionedDB constructs an instance of VersionedDB
 func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) *versionedDB {
-	return &versionedDB{db, dbName}
+	return &versionedDB{db: db, dbName: dbName}
+}
+
+// indexCapableVersionedDB extends versionedDB with the functions of the interface statedb.IndexCapable
+type indexCapableVersionedDB struct {
+	*versionedDB
+}
+
+// GetDBType returns the hosted stateDB
+func (vdb *indexCapableVersionedDB) GetDBType() string {
+	return "leveldb"
+}
+
+// ProcessIndexesForChaincodeDeploy creates indexes for a specified namespace
+func (vdb *indexCapableVersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error {
+	return vdb.indexMgr.processIndexFiles(namespace, fileEntries)
 }
 
 // Open implements method in VersionedDB interface
@@ -156,17 +197,82 @@ func (vdb *versionedDB) GetStateRangeScanIteratorWithMetadata(namespace string,
 
 // ExecuteQuery implements method in VersionedDB interface
 func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
-	return nil, errors.New("ExecuteQuery not supported for leveldb")
+	if vdb.indexMgr == nil {
+		return nil, errors.New("ExecuteQuery not supported for leveldb")
+	}
+	return vdb.ExecuteQueryWithMetadata(namespace, query, nil)
 }
 
+const optionBookmark = "bookmark"
+
 // ExecuteQueryWithMetadata implements method in VersionedDB interface
 func (vdb *versionedDB) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
-	return nil, errors.New("ExecuteQueryWithMetadata not supported for leveldb")
+	if vdb.indexMgr == nil {
+		return nil, errors.New("ExecuteQueryWithMetadata not supported for leveldb")
+	}
+	logger.Debugf("Entering ExecuteQueryWithMetadata  namespace: %s,  query: %s,  metadata: %v", namespace, query, metadata)
+	q, err := parseQuery(namespace, query)
+	if err != nil {
+		return nil, err
+	}
+	requestedLimit := q.limit
+	bookmark := q.bookmark
+	// if metadata is provided, validate and apply options
+	if metadata != nil {
+		if err := validateQueryMetadata(metadata); err != nil {
+			return nil, err
+		}
+		if limitOption, ok := metadata[optionLimit]; ok {
+			requestedLimit = limitOption.(int32)
+		}
+		if bookmarkOption, ok := metadata[optionBookmark]; ok {
+			bookmark = bookmarkOption.(string)
+		}
+	}
+	plan, err := q.planScan(vdb.indexMgr.getIndexes(namespace))
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.applyBookmark(bookmark, q.sortDesc); err != nil {
+		return nil, err
+	}
+	return newQueryScanner(vdb, q, plan, requestedLimit, bookmark), nil
+}
+
+func validateQueryMetadata(metadata map[string]interface{}) error {
+	for key, keyVal := range metadata {
+		switch key {
+		case optionBookmark:
+			//Verify the bookmark is a string
+			if _, ok := keyVal.(string); ok {
+				continue
+			}
+			return errors.New("Invalid entry, \"bookmark\" must be a string")
+
+		case optionLimit:
+			//Verify the limit is an integer
+			if _, ok := keyVal.(int32); ok {
+				continue
+			}
+			return errors.New("Invalid entry, \"limit\" must be an int32")
+
+		default:
+			return errors.Errorf("Invalid entry, option %s not recognized", key)
+		}
+	}
+	return nil
 }
 
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 	dbBatch := leveldbhelper.NewUpdateBatch()
+	if vdb.indexMgr != nil {
+		unlock := vdb.indexMgr.lockForCommit()
+		defer unlock()
+		if err := vdb.indexMgr.addIndexUpdates(dbBatch, batch); err != nil {
+			return err
+		}
+	}
 	namespaces := batch.GetUpdatedNamespaces()
 	for _, ns := range namespaces {
 		updates := batch.GetUpdates(ns)
@@ -215,6 +321,11 @@ func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
 	return version, nil
 }
 
+// GetFullScanIterator implements method in FullScanIterable interface
+func (vdb *versionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	return &fullScanner{vdb.db.GetIterator(nil, nil)}, nil
+}
+
 func constructCompositeKey(ns string, key string) []byte {
 	return append(append([]byte(ns), compositeKeySep...), []byte(key)...)
 }
@@ -278,3 +389,33 @@ func (scanner *kvScanner) GetBookmarkAndClose() string {
 	scanner.Close()
 	return retval
 }
+
+// fullScanner iterates over all the keys across all the namespaces, skipping the savepoint and the secondary indexes
+type fullScanner struct {
+	dbItr iterator.Iterator
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		if bytes.Equal(dbKey, savePointKey) || isIndexKey(dbKey) {
+			continue
+		}
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		ns, key := splitCompositeKey(dbKey)
+		vv, err := decodeValue(dbValCopy)
+		if err != nil {
+			return nil, err
+		}
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: ns, Key: key},
+			VersionedValue: *vv}, nil
+	}
+	return nil, errors.Wrap(scanner.dbItr.Error(), "error while iterating over the state db")
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.Release()
+}
@@ -136,3 +136,52 @@ func TestApplyUpdatesWithNilHeight(t *testing.T) {
 	defer env.Cleanup()
 	commontests.TestApplyUpdatesWithNilHeight(t, env.DBProvider)
 }
+
+func TestDrop(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDrop(t, env.DBProvider)
+}
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testfullscaniterator")
+	assert.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)))
+
+	itr, err := db.(statedb.FullScanIterable).GetFullScanIterator()
+	assert.NoError(t, err)
+	defer itr.Close()
+	var results []*statedb.VersionedKV
+	for {
+		res, err := itr.Next()
+		assert.NoError(t, err)
+		if res == nil {
+			break
+		}
+		results = append(results, res.(*statedb.VersionedKV))
+	}
+	assert.Equal(t,
+		[]*statedb.VersionedKV{
+			{
+				CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+				VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)},
+			},
+			{
+				CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key2"},
+				VersionedValue: statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)},
+			},
+			{
+				CompositeKey:   statedb.CompositeKey{Namespace: "ns2", Key: "key1"},
+				VersionedValue: statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(1, 3)},
+			},
+		},
+		results,
+	)
+}
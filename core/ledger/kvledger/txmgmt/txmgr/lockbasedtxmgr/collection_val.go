@@ -23,6 +23,23 @@ func newCollNameValidator(ccInfoProvider ledger.DeployedChaincodeInfoProvider, q
 }
 
 func (v *collNameValidator) validateCollName(ns, coll string) error {
+	err := v.validateCollNameInConfigPkg(ns, coll)
+	switch err.(type) {
+	case *ledger.CollConfigNotDefinedError, *ledger.InvalidCollNameError:
+		// collections which are not part of the collection config package of the
+		// namespace, such as implicit collections, are looked up by their name
+		collConfig, infoErr := v.ccInfoProvider.CollectionInfo(ns, coll, v.queryExecutor)
+		if infoErr != nil {
+			return infoErr
+		}
+		if collConfig != nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (v *collNameValidator) validateCollNameInConfigPkg(ns, coll string) error {
 	if !v.cache.isPopulatedFor(ns) {
 		conf, err := v.retrieveCollConfigFromStateDB(ns)
 		if err != nil {
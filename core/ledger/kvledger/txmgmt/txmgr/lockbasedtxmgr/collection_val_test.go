@@ -10,6 +10,9 @@ import (
 
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,6 +54,34 @@ func TestCollectionValidation(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCollectionValidationByName(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testLedger", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr().(*LockBasedTxMgr)
+	populateCollConfigForTest(t, txMgr, []collConfigkey{{"ns1", "coll1"}}, version.NewHeight(1, 1))
+	// collections which are not part of the collection config package are looked up by name
+	ccInfoProvider := txMgr.ccInfoProvider.(*mock.DeployedChaincodeInfoProvider)
+	ccInfoProvider.CollectionInfoStub = func(ns, coll string, qe ledger.SimpleQueryExecutor) (*common.StaticCollectionConfig, error) {
+		if coll == "implicit" {
+			return &common.StaticCollectionConfig{Name: coll}, nil
+		}
+		return nil, nil
+	}
+
+	sim, err := txMgr.NewTxSimulator("tx-id1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, sim.SetPrivateData("ns1", "implicit", "key1", []byte("val1")))
+	assert.NoError(t, sim.SetPrivateData("ns2", "implicit", "key1", []byte("val1")))
+	assert.IsType(t, &ledger.InvalidCollNameError{}, sim.SetPrivateData("ns1", "coll2", "key1", []byte("val1")))
+	assert.IsType(t, &ledger.CollConfigNotDefinedError{}, sim.SetPrivateData("ns2", "coll1", "key1", []byte("val1")))
+
+	ccInfoProvider.CollectionInfoReturns(nil, errors.New("collection info error"))
+	ccInfoProvider.CollectionInfoStub = nil
+	assert.EqualError(t, sim.SetPrivateData("ns1", "coll2", "key1", []byte("val1")), "collection info error")
+}
+
 func TestPvtGetNoCollection(t *testing.T) {
 	testEnv := testEnvs[0]
 	testEnv.init(t, "test-pvtdata-get-no-collection", nil)
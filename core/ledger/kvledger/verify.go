@@ -0,0 +1,325 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	componentStateDB      = "statedb"
+	componentHistoryDB    = "historydb"
+	componentPvtdataStore = "pvtdatastore"
+)
+
+// LedgerVerificationReport is the outcome of the offline verification of a ledger. A ledger is
+// reported as consistent if none of the inconsistencies is of the severity `error`, as the peer
+// resolves the ones of the severity `warning` on its own at the next start
+type LedgerVerificationReport struct {
+	LedgerID        string                      `json:"ledgerID"`
+	Consistent      bool                        `json:"consistent"`
+	BlockHeight     uint64                      `json:"blockHeight"`
+	FirstBlockNum   uint64                      `json:"firstBlockNum"`
+	BlocksVerified  uint64                      `json:"blocksVerified"`
+	Inconsistencies []*blkstorage.Inconsistency `json:"inconsistencies"`
+}
+
+// VerifyKVLedgers verifies the given ledgers offline, or all the ledgers on the peer if no ledger
+// is specified. The blocks are rechecked against the hash chain and the block index, the savepoints
+// of the state database and the history database are validated against the block height, and
+// the private data is rechecked against the hashes present in the blocks
+func VerifyKVLedgers(ledgerIDs ...string) ([]*LedgerVerificationReport, error) {
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return nil, errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer idStore.close()
+	if len(ledgerIDs) == 0 {
+		var err error
+		if ledgerIDs, err = idStore.getAllLedgerIds(); err != nil {
+			return nil, err
+		}
+	}
+	for _, ledgerID := range ledgerIDs {
+		exists, err := idStore.ledgerIDExists(ledgerID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.Errorf("ledger [%s] does not exist", ledgerID)
+		}
+	}
+
+	v, err := newLedgersVerifier()
+	if err != nil {
+		return nil, err
+	}
+	defer v.close()
+	var reports []*LedgerVerificationReport
+	for _, ledgerID := range ledgerIDs {
+		logger.Infof("Verifying ledger [%s]", ledgerID)
+		report, err := v.verify(ledgerID)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error verifying ledger [%s]", ledgerID))
+		}
+		logger.Infof("Verified [%d] blocks of ledger [%s], [%d] inconsistencies found",
+			report.BlocksVerified, ledgerID, len(report.Inconsistencies))
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+type ledgersVerifier struct {
+	vdbProvider       statedb.VersionedDBProvider
+	historydbProvider *historyleveldb.HistoryDBProvider
+	pvtdataProvider   pvtdatastorage.Provider
+}
+
+func newLedgersVerifier() (*ledgersVerifier, error) {
+	vdbProvider, err := statedb.NewVersionedDBProvider(ledgerconfig.GetStateDatabase(), &disabled.Provider{})
+	if err != nil {
+		return nil, err
+	}
+	v := &ledgersVerifier{
+		vdbProvider:     vdbProvider,
+		pvtdataProvider: pvtdatastorage.NewProvider(),
+	}
+	if ledgerconfig.IsHistoryDBEnabled() {
+		v.historydbProvider = historyleveldb.NewHistoryDBProvider()
+	}
+	return v, nil
+}
+
+func (v *ledgersVerifier) verify(ledgerID string) (*LedgerVerificationReport, error) {
+	pvtdataStore, err := v.pvtdataProvider.OpenStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer pvtdataStore.Shutdown()
+	// the private data is verified irrespective of its expiry, as the expired data that is not purged
+	// yet is expected to match the hashes present in the blocks as well
+	pvtdataStore.Init(&noExpiryBTLPolicy{})
+	pvtdataHeight, err := pvtdataStore.LastCommittedBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	pvtdataVerifier := &pvtdataVerifier{pvtdataStore, pvtdataHeight}
+	result, err := ledgerstorage.VerifyBlockStore(ledgerconfig.GetBlockStorePath(), ledgerID, pvtdataVerifier.verify)
+	if err != nil {
+		return nil, err
+	}
+	report := &LedgerVerificationReport{
+		LedgerID:        ledgerID,
+		BlockHeight:     result.BlockHeight,
+		FirstBlockNum:   result.FirstBlockNum,
+		BlocksVerified:  result.BlocksVerified,
+		Inconsistencies: result.Inconsistencies,
+	}
+
+	if err := v.verifySavepoints(ledgerID, report); err != nil {
+		return nil, err
+	}
+	pendingBatch, err := pvtdataStore.HasPendingBatch()
+	if err != nil {
+		return nil, err
+	}
+	isEmpty, err := pvtdataStore.IsEmpty()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case pendingBatch:
+		report.addInconsistency(componentPvtdataStore, blkstorage.SeverityWarning, pvtdataHeight,
+			"the private data store has a pending batch, which is committed or discarded at the peer start")
+	case isEmpty && report.BlockHeight > 0:
+		report.addInconsistency(componentPvtdataStore, blkstorage.SeverityWarning, 0,
+			"the private data store is empty, it is initialized from the block height at the peer start")
+	case pvtdataHeight == report.BlockHeight+1:
+		// the private data is committed before the block and hence, the private data store can be
+		// ahead by one block after a crash, which is rolled back at the peer start
+		report.addInconsistency(componentPvtdataStore, blkstorage.SeverityWarning, report.BlockHeight,
+			"the private data store is ahead of the block store by one block, which is recovered at the peer start")
+	case pvtdataHeight != report.BlockHeight:
+		report.addInconsistency(componentPvtdataStore, blkstorage.SeverityError, pvtdataHeight,
+			fmt.Sprintf("the height [%d] of the private data store does not match the block height [%d]",
+				pvtdataHeight, report.BlockHeight))
+	}
+
+	report.Consistent = true
+	for _, inconsistency := range report.Inconsistencies {
+		if inconsistency.Severity == blkstorage.SeverityError {
+			report.Consistent = false
+		}
+	}
+	if report.Inconsistencies == nil {
+		report.Inconsistencies = []*blkstorage.Inconsistency{}
+	}
+	return report, nil
+}
+
+// verifySavepoints validates the savepoints of the state database and the history database against the
+// block height. A database that lags behind is caught up by the peer at the next start, whereas a
+// database that is ahead of the block store indicates that the block store has lost blocks
+func (v *ledgersVerifier) verifySavepoints(ledgerID string, report *LedgerVerificationReport) error {
+	vdb, err := v.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	savepoint, err := vdb.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	report.verifySavepoint(componentStateDB, savepoint)
+
+	if v.historydbProvider == nil {
+		return nil
+	}
+	historyDB, err := v.historydbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	if savepoint, err = historyDB.GetLastSavepoint(); err != nil {
+		return err
+	}
+	report.verifySavepoint(componentHistoryDB, savepoint)
+	return nil
+}
+
+func (v *ledgersVerifier) close() {
+	v.vdbProvider.Close()
+	v.pvtdataProvider.Close()
+	if v.historydbProvider != nil {
+		v.historydbProvider.Close()
+	}
+}
+
+func (r *LedgerVerificationReport) verifySavepoint(component string, savepoint *version.Height) {
+	var height uint64
+	if savepoint != nil {
+		height = savepoint.BlockNum + 1
+	}
+	switch {
+	case height > r.BlockHeight:
+		r.addInconsistency(component, blkstorage.SeverityError, savepoint.BlockNum,
+			fmt.Sprintf("the savepoint is ahead of the block height [%d]", r.BlockHeight))
+	case height < r.BlockHeight:
+		r.addInconsistency(component, blkstorage.SeverityWarning, height,
+			fmt.Sprintf("the savepoint lags behind the block height [%d], the missing blocks are committed at the peer start",
+				r.BlockHeight))
+	}
+}
+
+func (r *LedgerVerificationReport) addInconsistency(component, severity string, blockNum uint64, description string) {
+	r.Inconsistencies = append(r.Inconsistencies, &blkstorage.Inconsistency{
+		Component:   component,
+		Severity:    severity,
+		BlockNum:    blockNum,
+		Description: description,
+	})
+}
+
+// pvtdataVerifier recomputes the hashes of the private data of a block and compares
+// these with the hashes present in the hashed rwsets of the transactions in the block
+type pvtdataVerifier struct {
+	store  pvtdatastorage.Store
+	height uint64
+}
+
+func (v *pvtdataVerifier) verify(block *common.Block) ([]*blkstorage.Inconsistency, error) {
+	blockNum := block.Header.Number
+	if blockNum >= v.height {
+		return nil, nil
+	}
+	blockPvtdata, err := v.store.GetPvtDataByBlockNum(blockNum, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var inconsistencies []*blkstorage.Inconsistency
+	addInconsistency := func(txNum uint64, description string) {
+		inconsistencies = append(inconsistencies, &blkstorage.Inconsistency{
+			Component:   componentPvtdataStore,
+			Severity:    blkstorage.SeverityError,
+			BlockNum:    blockNum,
+			TxNum:       &txNum,
+			Description: description,
+		})
+	}
+	for _, txPvtdata := range blockPvtdata {
+		txNum := txPvtdata.SeqInBlock
+		if txNum >= uint64(len(block.Data.Data)) {
+			addInconsistency(txNum, "the private data belongs to a transaction that is not present in the block")
+			continue
+		}
+		txRWSet, err := retrieveRwsetFromBlock(block, txNum)
+		if err != nil {
+			addInconsistency(txNum, fmt.Sprintf("error retrieving the rwset of the transaction: %s", err))
+			continue
+		}
+		for _, nsPvtRwset := range txPvtdata.WriteSet.NsPvtRwset {
+			ns := nsPvtRwset.Namespace
+			for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+				coll := collPvtRwset.CollectionName
+				expectedHash := txRWSet.GetPvtDataHash(ns, coll)
+				switch {
+				case expectedHash == nil:
+					addInconsistency(txNum, fmt.Sprintf("the private data of the collection [%s:%s] is not present in the hashed rwset",
+						ns, coll))
+				case !bytes.Equal(util.ComputeSHA256(collPvtRwset.Rwset), expectedHash):
+					addInconsistency(txNum, fmt.Sprintf("the hash of the private data of the collection [%s:%s] does not match the hash [%x] in the hashed rwset",
+						ns, coll, expectedHash))
+				}
+			}
+		}
+	}
+	return inconsistencies, nil
+}
+
+func retrieveRwsetFromBlock(block *common.Block, txNum uint64) (*rwsetutil.TxRwSet, error) {
+	action, err := utils.GetActionFromEnvelope(block.Data.Data[txNum])
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+		return nil, err
+	}
+	return txRWSet, nil
+}
+
+// noExpiryBTLPolicy treats the private data of all the collections as never expiring
+type noExpiryBTLPolicy struct{}
+
+func (p *noExpiryBTLPolicy) GetBTL(ns string, coll string) (uint64, error) {
+	return 0, nil
+}
+
+func (p *noExpiryBTLPolicy) GetExpiringBlock(ns string, coll string, committingBlock uint64) (uint64, error) {
+	return math.MaxUint64, nil
+}
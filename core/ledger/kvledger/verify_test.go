@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyKVLedgers(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+
+	_, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	lg, err := provider.Create(gb)
+	require.NoError(t, err)
+	lg.Close()
+
+	_, gb = testutil.NewBlockGenerator(t, "ledger2", false)
+	lg, err = provider.Create(gb)
+	require.NoError(t, err)
+	// commit a block with private data that does not match the hashes in the block directly to the block store
+	pvtdataTx0, pubSimResBytesTx0 := produceSamplePvtdata(t, 0, []string{"ns-1:coll-1"}, [][]byte{{0}})
+	wrongPvtdataTx1, _ := produceSamplePvtdata(t, 1, []string{"ns-1:coll-1"}, [][]byte{{2}})
+	_, pubSimResBytesTx1 := produceSamplePvtdata(t, 1, []string{"ns-1:coll-1"}, [][]byte{{1}})
+	blk1 := testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{pubSimResBytesTx0, pubSimResBytesTx1}, false)
+	require.NoError(t, lg.(*kvLedger).blockStore.CommitWithPvtData(&ledger.BlockAndPvtData{
+		Block:   blk1,
+		PvtData: ledger.TxPvtDataMap{0: pvtdataTx0, 1: wrongPvtdataTx1},
+	}))
+	lg.Close()
+	provider.Close()
+
+	reports, err := VerifyKVLedgers("ledger1")
+	require.NoError(t, err)
+	assert.Equal(t, []*LedgerVerificationReport{
+		{
+			LedgerID:        "ledger1",
+			Consistent:      true,
+			BlockHeight:     1,
+			BlocksVerified:  1,
+			Inconsistencies: []*blkstorage.Inconsistency{},
+		},
+	}, reports)
+
+	reports, err = VerifyKVLedgers()
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, reports[0].LedgerID, "ledger1")
+	txNum := uint64(1)
+	assert.Equal(t, &LedgerVerificationReport{
+		LedgerID:       "ledger2",
+		Consistent:     false,
+		BlockHeight:    2,
+		BlocksVerified: 2,
+		Inconsistencies: []*blkstorage.Inconsistency{
+			{
+				Component: "pvtdatastore",
+				Severity:  blkstorage.SeverityError,
+				BlockNum:  1,
+				TxNum:     &txNum,
+				Description: fmt.Sprintf("the hash of the private data of the collection [ns-1:coll-1] does not match the hash [%x] in the hashed rwset",
+					util.ComputeSHA256(pvtdataRwsetBytes(t, 1))),
+			},
+			{
+				Component:   "statedb",
+				Severity:    blkstorage.SeverityWarning,
+				BlockNum:    1,
+				Description: "the savepoint lags behind the block height [2], the missing blocks are committed at the peer start",
+			},
+			{
+				Component:   "historydb",
+				Severity:    blkstorage.SeverityWarning,
+				BlockNum:    1,
+				Description: "the savepoint lags behind the block height [2], the missing blocks are committed at the peer start",
+			},
+		},
+	}, reports[1])
+
+	_, err = VerifyKVLedgers("ledger3")
+	assert.EqualError(t, err, "ledger [ledger3] does not exist")
+}
+
+func pvtdataRwsetBytes(t *testing.T, value byte) []byte {
+	pvtdata, _ := produceSamplePvtdata(t, 0, []string{"ns-1:coll-1"}, [][]byte{{value}})
+	return pvtdata.WriteSet.NsPvtRwset[0].CollectionPvtRwset[0].Rwset
+}
@@ -8,6 +8,7 @@ package ledger
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-lib-go/healthz"
@@ -35,6 +36,11 @@ type PeerLedgerProvider interface {
 	// This function guarantees that the creation of ledger and committing the genesis block would an atomic action
 	// The chain id retrieved from the genesis block is treated as a ledger id
 	Create(genesisBlock *common.Block) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from a snapshot generated by the function `ExportSnapshot` on a
+	// ledger of another peer and returns the ledger along with its id. The ledger id is retrieved from the metadata
+	// of the snapshot. The created ledger does not contain the blocks covered by the snapshot (except for the last
+	// block and the last config block) and begins its blockchain after the last block in the snapshot
+	CreateFromSnapshot(snapshotDir string) (PeerLedger, string, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exists
@@ -102,6 +108,25 @@ type PeerLedger interface {
 	//     missing info is recorded in the ledger (or)
 	// (3) the block is committed and does not contain any pvtData.
 	DoesPvtDataInfoExist(blockNum uint64) (bool, error)
+	// ExportSnapshot generates a snapshot of the ledger at the last committed block. The snapshot contains
+	// the public state, the hashes of the private state, the txids, and the history of the collection configs.
+	// The block commits are paused while the snapshot is being generated
+	ExportSnapshot() (*SnapshotInfo, error)
+	// SubmitSnapshotRequest submits a request for generating a snapshot when the block with the given
+	// number gets committed. The pending requests are not persisted and hence are lost on a peer restart
+	SubmitSnapshotRequest(blockNum uint64) error
+	// PendingSnapshotRequests returns the block numbers for which the snapshot requests are yet to be processed
+	PendingSnapshotRequests() ([]uint64, error)
+}
+
+// SnapshotInfo captures the details of a generated snapshot of a ledger
+type SnapshotInfo struct {
+	LedgerID          string
+	LastBlockNum      uint64
+	LastBlockHash     []byte
+	PreviousBlockHash []byte
+	SnapshotHash      []byte
+	Dir               string
 }
 
 // SimpleQueryExecutor encapsulates basic functions
@@ -178,6 +203,37 @@ type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeyWithOptions retrieves the history of values for a key, restricted and ordered
+	// as specified by the options. A nil or empty options retrieves the same results as GetHistoryForKey.
+	// The returned QueryResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
+	// The bookmark returned by the iterator can be passed in the options to fetch the next page of results
+	GetHistoryForKeyWithOptions(namespace string, key string, options *HistoryQueryOptions) (QueryResultsIterator, error)
+	// GetAccessHistoryForKey retrieves the transactions that read or wrote a key. The accesses are indexed only for the
+	// blocks committed while the channel config sets the application capability V1_4_KEY_ACCESS_INDEX. Unlike the history
+	// of values, the invalid transactions are included so that the validation code of each access can be audited.
+	// The options restrict and order the results in the same way as for GetHistoryForKeyWithOptions.
+	// The returned QueryResultsIterator contains results of type *KeyAccess which is defined in protos/ledger/queryresult.
+	GetAccessHistoryForKey(namespace string, key string, options *HistoryQueryOptions) (QueryResultsIterator, error)
+}
+
+// HistoryQueryOptions restricts and orders the results of a history query.
+// The block range [StartBlock, EndBlock] is inclusive and an EndBlock of zero leaves the range open ended.
+// The time range [StartTime, EndTime) applies to the transaction timestamps and a zero time leaves
+// the corresponding end of the range open. By default, the results are returned oldest first.
+// The timestamps of the transactions are set by their clients and need not increase with the height,
+// hence a query with a time range scans an index of the history by timestamp and orders the results
+// by timestamp, then by height. Its cost depends on the number of records in the time range rather than
+// on the whole history of the key. The block range is then applied to the records in the time range.
+// A positive PageSize limits the number of results returned and the Bookmark, obtained from the
+// iterator of a previous page, resumes the query after the last result of that page
+type HistoryQueryOptions struct {
+	StartBlock  uint64
+	EndBlock    uint64
+	StartTime   time.Time
+	EndTime     time.Time
+	NewestFirst bool
+	PageSize    int32
+	Bookmark    string
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
@@ -8,36 +8,64 @@ package ledgerconfig
 
 import (
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
 )
 
-//IsCouchDBEnabled exposes the useCouchDB variable
+// The types of the state databases shipped with the peer, as returned by GetStateDatabase
+const (
+	StateDatabaseGoLevelDB = "goleveldb"
+	StateDatabaseCouchDB   = "couchdb"
+	StateDatabaseBadgerDB  = "badgerdb"
+)
+
+// IsCouchDBEnabled exposes the useCouchDB variable
 func IsCouchDBEnabled() bool {
-	stateDatabase := viper.GetString("ledger.state.stateDatabase")
-	if stateDatabase == "CouchDB" {
-		return true
+	return GetStateDatabase() == StateDatabaseCouchDB
+}
+
+// GetStateDatabase returns the type of the state database to be used by the peer. The value set in
+// core.yaml is normalized to lower case so that, for instance, "CouchDB" and "couchdb" select the same
+// state database. If not set in core.yaml, goleveldb is used
+func GetStateDatabase() string {
+	stateDatabase := strings.ToLower(strings.TrimSpace(viper.GetString(confStateDatabase)))
+	if stateDatabase == "" {
+		stateDatabase = StateDatabaseGoLevelDB
 	}
-	return false
+	return stateDatabase
 }
 
 const confPeerFileSystemPath = "peer.fileSystemPath"
 const confLedgersData = "ledgersData"
 const confLedgerProvider = "ledgerProvider"
 const confStateleveldb = "stateLeveldb"
+const confStateBadgerdb = "stateBadgerdb"
 const confHistoryLeveldb = "historyLeveldb"
 const confBookkeeper = "bookkeeper"
 const confConfigHistory = "configHistory"
 const confChains = "chains"
 const confPvtdataStore = "pvtdataStore"
 const fileLockPath = "fileLock"
+const confStateDatabase = "ledger.state.stateDatabase"
 const confTotalQueryLimit = "ledger.state.totalQueryLimit"
 const confInternalQueryLimit = "ledger.state.couchDBConfig.internalQueryLimit"
+const confEnableLevelDBRichQuery = "ledger.state.levelDBConfig.enableRichQuery"
+const confBadgerDBValueLogGCInterval = "ledger.state.badgerDBConfig.valueLogGCInterval"
 const confEnableHistoryDatabase = "ledger.history.enableHistoryDatabase"
 const confMaxBatchSize = "ledger.state.couchDBConfig.maxBatchUpdateSize"
 const confAutoWarmIndexes = "ledger.state.couchDBConfig.autoWarmIndexes"
 const confWarmIndexesAfterNBlocks = "ledger.state.couchDBConfig.warmIndexesAfterNBlocks"
+const confSnapshotsRootDir = "ledger.snapshots.rootDir"
+const confSnapshots = "snapshots"
+const confBlockArchiveEnabled = "ledger.blockchain.archive.enabled"
+const confBlockArchiveDir = "ledger.blockchain.archive.directory"
+const confBlockArchiveRetainBlocks = "ledger.blockchain.archive.retainBlocks"
+const confBlockArchiveMaxAge = "ledger.blockchain.archive.maxAge"
+const confBlockArchiveFetchArchived = "ledger.blockchain.archive.fetchArchivedBlocks"
+const confBlockArchive = "blockArchive"
 
 var confCollElgProcMaxDbBatchSize = &conf{"ledger.pvtdataStore.collElgProcMaxDbBatchSize", 5000}
 var confCollElgProcDbBatchesInterval = &conf{"ledger.pvtdataStore.collElgProcDbBatchesInterval", 1000}
@@ -59,6 +87,11 @@ func GetStateLevelDBPath() string {
 	return filepath.Join(GetRootPath(), confStateleveldb)
 }
 
+// GetStateBadgerDBPath returns the filesystem path that is used to maintain the state badger db
+func GetStateBadgerDBPath() string {
+	return filepath.Join(GetRootPath(), confStateBadgerdb)
+}
+
 // GetHistoryLevelDBPath returns the filesystem path that is used to maintain the history level db
 func GetHistoryLevelDBPath() string {
 	return filepath.Join(GetRootPath(), confHistoryLeveldb)
@@ -89,11 +122,59 @@ func GetConfigHistoryPath() string {
 	return filepath.Join(GetRootPath(), confConfigHistory)
 }
 
+// GetSnapshotsRootDir returns the filesystem path under which the ledger snapshots are generated.
+// If not set in core.yaml, the snapshots are generated under the ledgers data directory
+func GetSnapshotsRootDir() string {
+	if viper.GetString(confSnapshotsRootDir) != "" {
+		return config.GetPath(confSnapshotsRootDir)
+	}
+	return filepath.Join(GetRootPath(), confSnapshots)
+}
+
 // GetMaxBlockfileSize returns maximum size of the block file
 func GetMaxBlockfileSize() int {
 	return 64 * 1024 * 1024
 }
 
+// IsBlockArchiveEnabled returns true if the older block files are to be moved to the block archive
+func IsBlockArchiveEnabled() bool {
+	return viper.GetBool(confBlockArchiveEnabled)
+}
+
+// GetBlockArchiveDir returns the filesystem path to which the older block files are moved.
+// If not set in core.yaml, the block files are moved under the ledgers data directory
+func GetBlockArchiveDir() string {
+	if viper.GetString(confBlockArchiveDir) != "" {
+		return config.GetPath(confBlockArchiveDir)
+	}
+	return filepath.Join(GetRootPath(), confBlockArchive)
+}
+
+// GetBlockArchiveRetainBlocks returns the number of the latest blocks that are never archived.
+// A zero value disables the archiving based on the block height
+func GetBlockArchiveRetainBlocks() uint64 {
+	retainBlocks := viper.GetInt(confBlockArchiveRetainBlocks)
+	if retainBlocks < 0 {
+		return 0
+	}
+	return uint64(retainBlocks)
+}
+
+// GetBlockArchiveMaxAge returns the age after which a block file is archived.
+// A zero value disables the archiving based on the age of the block files
+func GetBlockArchiveMaxAge() time.Duration {
+	return viper.GetDuration(confBlockArchiveMaxAge)
+}
+
+// IsFetchArchivedBlocksEnabled returns true if the archived blocks are to be read from the archive.
+// If not set in core.yaml, the archived blocks are read from the archive
+func IsFetchArchivedBlocksEnabled() bool {
+	if viper.IsSet(confBlockArchiveFetchArchived) {
+		return viper.GetBool(confBlockArchiveFetchArchived)
+	}
+	return true
+}
+
 // GetTotalQueryLimit exposes the totalLimit variable
 func GetTotalQueryLimit() int {
 	totalQueryLimit := viper.GetInt(confTotalQueryLimit)
@@ -114,7 +195,7 @@ func GetInternalQueryLimit() int {
 	return internalQueryLimit
 }
 
-//GetMaxBatchUpdateSize exposes the maxBatchUpdateSize variable
+// GetMaxBatchUpdateSize exposes the maxBatchUpdateSize variable
 func GetMaxBatchUpdateSize() int {
 	maxBatchUpdateSize := viper.GetInt(confMaxBatchSize)
 	// if maxBatchUpdateSize was unset, default to 500
@@ -154,11 +235,26 @@ func GetPvtdataStoreCollElgProcDbBatchesInterval() int {
 	return collElgProcDbBatchesInterval
 }
 
-//IsHistoryDBEnabled exposes the historyDatabase variable
+// IsHistoryDBEnabled exposes the historyDatabase variable
 func IsHistoryDBEnabled() bool {
 	return viper.GetBool(confEnableHistoryDatabase)
 }
 
+// IsLevelDBRichQueryEnabled exposes the enableRichQuery variable of the goleveldb state database
+func IsLevelDBRichQueryEnabled() bool {
+	return viper.GetBool(confEnableLevelDBRichQuery)
+}
+
+// GetBadgerDBValueLogGCInterval returns the interval at which the value log of the Badger state database
+// is garbage collected. If not set in core.yaml, the value log is garbage collected every ten minutes
+func GetBadgerDBValueLogGCInterval() time.Duration {
+	interval := viper.GetDuration(confBadgerDBValueLogGCInterval)
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	return interval
+}
+
 // IsQueryReadsHashingEnabled enables or disables computing of hash
 // of range query results for phantom item validation
 func IsQueryReadsHashingEnabled() bool {
@@ -172,7 +268,7 @@ func GetMaxDegreeQueryReadsHashing() uint32 {
 	return 50
 }
 
-//IsAutoWarmIndexesEnabled exposes the autoWarmIndexes variable
+// IsAutoWarmIndexesEnabled exposes the autoWarmIndexes variable
 func IsAutoWarmIndexesEnabled() bool {
 	//Return the value set in core.yaml, if not set, the return true
 	if viper.IsSet(confAutoWarmIndexes) {
@@ -182,7 +278,7 @@ func IsAutoWarmIndexesEnabled() bool {
 
 }
 
-//GetWarmIndexesAfterNBlocks exposes the warmIndexesAfterNBlocks variable
+// GetWarmIndexesAfterNBlocks exposes the warmIndexesAfterNBlocks variable
 func GetWarmIndexesAfterNBlocks() int {
 	warmAfterNBlocks := viper.GetInt(confWarmIndexesAfterNBlocks)
 	// if warmIndexesAfterNBlocks was unset, default to 1
@@ -18,6 +18,7 @@ package ledgerconfig
 
 import (
 	"testing"
+	"time"
 
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/spf13/viper"
@@ -44,6 +45,24 @@ func TestIsCouchDBEnabled(t *testing.T) {
 	viper.Set("ledger.state.stateDatabase", "CouchDB")
 	updatedValue := IsCouchDBEnabled()
 	assert.True(t, updatedValue) //test config returns true
+	viper.Set("ledger.state.stateDatabase", " couchdb ")
+	assert.True(t, IsCouchDBEnabled())
+	viper.Set("ledger.state.stateDatabase", "goleveldb")
+	assert.False(t, IsCouchDBEnabled())
+}
+
+func TestGetStateDatabase(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, "goleveldb", GetStateDatabase())
+	viper.Set("ledger.state.stateDatabase", "")
+	assert.Equal(t, "goleveldb", GetStateDatabase())
+	viper.Set("ledger.state.stateDatabase", "GoLevelDB")
+	assert.Equal(t, StateDatabaseGoLevelDB, GetStateDatabase())
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	assert.Equal(t, StateDatabaseCouchDB, GetStateDatabase())
+	viper.Set("ledger.state.stateDatabase", "BadgerDB")
+	assert.Equal(t, StateDatabaseBadgerDB, GetStateDatabase())
 }
 
 func TestLedgerConfigPathDefault(t *testing.T) {
@@ -51,11 +70,13 @@ func TestLedgerConfigPathDefault(t *testing.T) {
 	assert.Equal(t, "/var/hyperledger/production/ledgersData", GetRootPath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/ledgerProvider", GetLedgerProviderPath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/stateLeveldb", GetStateLevelDBPath())
+	assert.Equal(t, "/var/hyperledger/production/ledgersData/stateBadgerdb", GetStateBadgerDBPath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/historyLeveldb", GetHistoryLevelDBPath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/chains", GetBlockStorePath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/pvtdataStore", GetPvtdataStorePath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/bookkeeper", GetInternalBookkeeperPath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/fileLock", GetFileLockPath())
+	assert.Equal(t, "/var/hyperledger/production/ledgersData/snapshots", GetSnapshotsRootDir())
 }
 
 func TestLedgerConfigPath(t *testing.T) {
@@ -65,11 +86,15 @@ func TestLedgerConfigPath(t *testing.T) {
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData", GetRootPath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/ledgerProvider", GetLedgerProviderPath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/stateLeveldb", GetStateLevelDBPath())
+	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/stateBadgerdb", GetStateBadgerDBPath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/historyLeveldb", GetHistoryLevelDBPath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/chains", GetBlockStorePath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/pvtdataStore", GetPvtdataStorePath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/bookkeeper", GetInternalBookkeeperPath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/fileLock", GetFileLockPath())
+	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/snapshots", GetSnapshotsRootDir())
+	viper.Set("ledger.snapshots.rootDir", "/tmp/snapshots")
+	assert.Equal(t, "/tmp/snapshots", GetSnapshotsRootDir())
 }
 
 func TestGetTotalLimitDefault(t *testing.T) {
@@ -176,6 +201,16 @@ func TestCollElgProcDbBatchesInterval(t *testing.T) {
 	assert.Equal(t, testVal, GetPvtdataStoreCollElgProcDbBatchesInterval())
 }
 
+func TestBadgerDBValueLogGCInterval(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, 10*time.Minute, GetBadgerDBValueLogGCInterval())
+	viper.Set("ledger.state.badgerDBConfig.valueLogGCInterval", "30s")
+	assert.Equal(t, 30*time.Second, GetBadgerDBValueLogGCInterval())
+	viper.Set("ledger.state.badgerDBConfig.valueLogGCInterval", "0s")
+	assert.Equal(t, 10*time.Minute, GetBadgerDBValueLogGCInterval())
+}
+
 func TestIsHistoryDBEnabledDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	defaultValue := IsHistoryDBEnabled()
@@ -198,6 +233,20 @@ func TestIsHistoryDBEnabledFalse(t *testing.T) {
 	assert.False(t, updatedValue) //test config returns false
 }
 
+func TestIsLevelDBRichQueryEnabledDefault(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defaultValue := IsLevelDBRichQueryEnabled()
+	assert.False(t, defaultValue) //test default config is false
+}
+
+func TestIsLevelDBRichQueryEnabledTrue(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.state.levelDBConfig.enableRichQuery", true)
+	updatedValue := IsLevelDBRichQueryEnabled()
+	assert.True(t, updatedValue) //test config returns true
+}
+
 func TestIsAutoWarmIndexesEnabledDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	defaultValue := IsAutoWarmIndexesEnabled()
@@ -250,6 +299,27 @@ func TestGetMaxBlockfileSize(t *testing.T) {
 	assert.Equal(t, 67108864, GetMaxBlockfileSize())
 }
 
+func TestBlockArchiveConfig(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.False(t, IsBlockArchiveEnabled())
+	assert.Equal(t, "/var/hyperledger/production/ledgersData/blockArchive", GetBlockArchiveDir())
+	assert.Equal(t, uint64(0), GetBlockArchiveRetainBlocks())
+	assert.Equal(t, time.Duration(0), GetBlockArchiveMaxAge())
+	assert.True(t, IsFetchArchivedBlocksEnabled())
+
+	viper.Set("ledger.blockchain.archive.enabled", true)
+	viper.Set("ledger.blockchain.archive.directory", "/tmp/blockArchive")
+	viper.Set("ledger.blockchain.archive.retainBlocks", 10000)
+	viper.Set("ledger.blockchain.archive.maxAge", "720h")
+	viper.Set("ledger.blockchain.archive.fetchArchivedBlocks", false)
+	assert.True(t, IsBlockArchiveEnabled())
+	assert.Equal(t, "/tmp/blockArchive", GetBlockArchiveDir())
+	assert.Equal(t, uint64(10000), GetBlockArchiveRetainBlocks())
+	assert.Equal(t, 720*time.Hour, GetBlockArchiveMaxAge())
+	assert.False(t, IsFetchArchivedBlocksEnabled())
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
@@ -51,8 +51,17 @@ var attrsToIndex = []blkstorage.IndexableAttr{
 func NewProvider(metricsProvider metrics.Provider) *Provider {
 	// Initialize the block storage
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	var archiveConf *fsblkstorage.ArchiveConf
+	if ledgerconfig.IsBlockArchiveEnabled() {
+		archiveConf = &fsblkstorage.ArchiveConf{
+			Archive:       fsblkstorage.NewFSArchive(ledgerconfig.GetBlockArchiveDir()),
+			RetainBlocks:  ledgerconfig.GetBlockArchiveRetainBlocks(),
+			MaxAge:        ledgerconfig.GetBlockArchiveMaxAge(),
+			FetchArchived: ledgerconfig.IsFetchArchivedBlocksEnabled(),
+		}
+	}
 	blockStoreProvider := fsblkstorage.NewProvider(
-		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
+		fsblkstorage.NewConfWithArchive(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize(), archiveConf),
 		indexConfig,
 		metricsProvider)
 
@@ -63,12 +72,29 @@ func NewProvider(metricsProvider metrics.Provider) *Provider {
 // Open opens the store
 func (p *Provider) Open(ledgerid string) (*Store, error) {
 	var blockStore blkstorage.BlockStore
-	var pvtdataStore pvtdatastorage.Store
 	var err error
 
 	if blockStore, err = p.blkStoreProvider.OpenBlockStore(ledgerid); err != nil {
 		return nil, err
 	}
+	return p.newStore(ledgerid, blockStore)
+}
+
+// BootstrapFromSnapshot creates the store for a ledger from a snapshot. The block store begins
+// after the last block covered by the snapshot and the pvt data store is initialized to the
+// same height when the store is opened
+func (p *Provider) BootstrapFromSnapshot(ledgerid string, snapshotInfo *blkstorage.SnapshotInfo,
+	txIDs blkstorage.TxIDInfoIterator) (*Store, error) {
+	blockStore, err := p.blkStoreProvider.BootstrapFromSnapshot(ledgerid, snapshotInfo, txIDs)
+	if err != nil {
+		return nil, err
+	}
+	return p.newStore(ledgerid, blockStore)
+}
+
+func (p *Provider) newStore(ledgerid string, blockStore blkstorage.BlockStore) (*Store, error) {
+	var pvtdataStore pvtdatastorage.Store
+	var err error
 	if pvtdataStore, err = p.pvtdataStoreProvider.OpenStore(ledgerid); err != nil {
 		return nil, err
 	}
@@ -104,6 +130,11 @@ func (p *Provider) Exists(ledgerID string) (bool, error) {
 	return p.blkStoreProvider.Exists(ledgerID)
 }
 
+// IsBootstrappedFromSnapshot returns true if the block store for the given ledger was bootstrapped from a snapshot
+func IsBootstrappedFromSnapshot(ledgerID string) (bool, error) {
+	return fsblkstorage.IsBootstrappedFromSnapshot(ledgerconfig.GetBlockStorePath(), ledgerID)
+}
+
 // Init initializes store with essential configurations
 func (s *Store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
 	s.pvtdataStore.Init(btlPolicy)
@@ -368,3 +399,10 @@ func Rollback(blockstorePath, ledgerID string, blockNum uint64) error {
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
 	return fsblkstorage.Rollback(blockstorePath, ledgerID, blockNum, indexConfig)
 }
+
+// VerifyBlockStore verifies the block files and the block index of a ledger. The block verifier
+// is invoked for each block so that the other stores can be cross-checked against the blocks
+func VerifyBlockStore(blockstorePath, ledgerID string, blockVerifier fsblkstorage.BlockVerifier) (*fsblkstorage.VerificationResult, error) {
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	return fsblkstorage.Verify(blockstorePath, ledgerID, indexConfig, blockVerifier)
+}
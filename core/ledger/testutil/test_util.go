@@ -78,9 +78,16 @@ func ResetConfigToDefaultValues() {
 	viper.Set("ledger.state.couchDBConfig.internalQueryLimit", 1000)
 	viper.Set("ledger.state.stateDatabase", "goleveldb")
 	viper.Set("ledger.history.enableHistoryDatabase", false)
+	viper.Set("ledger.state.levelDBConfig.enableRichQuery", false)
 	viper.Set("ledger.state.couchDBConfig.autoWarmIndexes", true)
 	viper.Set("ledger.state.couchDBConfig.warmIndexesAfterNBlocks", 1)
 	viper.Set("peer.fileSystemPath", "/var/hyperledger/production")
+	viper.Set("ledger.snapshots.rootDir", "")
+	viper.Set("ledger.blockchain.archive.enabled", false)
+	viper.Set("ledger.blockchain.archive.directory", "")
+	viper.Set("ledger.blockchain.archive.retainBlocks", 0)
+	viper.Set("ledger.blockchain.archive.maxAge", 0)
+	viper.Set("ledger.blockchain.archive.fetchArchivedBlocks", true)
 }
 
 // ParseTestParams parses tests params
@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package couchdb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// circuitBreaker tracks the outcome of the requests to CouchDB. Once the configured number
+// of consecutive requests fail, the breaker opens and the requests are rejected without
+// contacting CouchDB, so that the callers, e.g., the block commit, fail fast instead of
+// waiting on the timeouts and the retries of every request. After the open timeout, a single
+// probe request is let through; the breaker closes if the probe succeeds and opens again otherwise.
+// A nil circuitBreaker lets all the requests through
+type circuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	stateGauge       metrics.Gauge
+	now              func() time.Time
+
+	mutex               sync.Mutex
+	state               breakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	lastErr             error
+}
+
+// newCircuitBreaker constructs a circuitBreaker. A nil circuitBreaker is returned
+// if the failure threshold is not positive, which disables the circuit breaker
+func newCircuitBreaker(failureThreshold int, openTimeout time.Duration, stateGauge metrics.Gauge) *circuitBreaker {
+	if failureThreshold <= 0 {
+		return nil
+	}
+	cb := &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		stateGauge:       stateGauge,
+		now:              time.Now,
+	}
+	cb.setState(breakerClosed)
+	return cb
+}
+
+// allow returns an error if the request must not be sent to CouchDB
+func (cb *circuitBreaker) allow() error {
+	if cb == nil {
+		return nil
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if cb.now().Sub(cb.openedAt) < cb.openTimeout {
+			return cb.openError()
+		}
+		logger.Infof("CouchDB circuit breaker is half-open, sending a probe request")
+		cb.setState(breakerHalfOpen)
+		return nil
+	case breakerHalfOpen:
+		return errors.Errorf("CouchDB circuit breaker is half-open and a probe request is in progress, rejecting the request: last error: %s", cb.lastErr)
+	default:
+		return nil
+	}
+}
+
+// success records a request that reached CouchDB
+func (cb *circuitBreaker) success() {
+	if cb == nil {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state != breakerClosed {
+		logger.Infof("CouchDB is reachable again, closing the circuit breaker")
+	}
+	cb.consecutiveFailures = 0
+	cb.lastErr = nil
+	cb.setState(breakerClosed)
+}
+
+// record records the outcome of a request to CouchDB. An error without a status code, including an
+// error returned before the response of CouchDB could be decoded, means that CouchDB could not be
+// reached, whereas the status codes below 500 show that CouchDB is available. Every outcome is either
+// a success or a failure, so that a probe request always takes the breaker out of the half-open state
+func (cb *circuitBreaker) record(couchDBReturn *DBReturn, err error) {
+	if err != nil && (couchDBReturn == nil || couchDBReturn.StatusCode == 0 || couchDBReturn.StatusCode >= 500) {
+		cb.failure(err)
+		return
+	}
+	cb.success()
+}
+
+// failure records a request that could not reach CouchDB or failed with a server error
+func (cb *circuitBreaker) failure(err error) {
+	if cb == nil {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.consecutiveFailures++
+	cb.lastErr = err
+	if cb.state == breakerHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+		if cb.state != breakerOpen {
+			logger.Errorf("Opening the CouchDB circuit breaker for %s after %d consecutive failed requests, last error: %s",
+				cb.openTimeout, cb.consecutiveFailures, err)
+		}
+		cb.openedAt = cb.now()
+		cb.setState(breakerOpen)
+	}
+}
+
+func (cb *circuitBreaker) openError() error {
+	return errors.Errorf("CouchDB circuit breaker is open after %d consecutive failed requests, rejecting the requests until %s: last error: %s",
+		cb.consecutiveFailures, cb.openedAt.Add(cb.openTimeout).Format(time.RFC3339), cb.lastErr)
+}
+
+func (cb *circuitBreaker) setState(state breakerState) {
+	cb.state = state
+	cb.stateGauge.Set(float64(state))
+}
@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	fakeGauge := &metricsfakes.Gauge{}
+	cb := newCircuitBreaker(2, time.Minute, fakeGauge)
+	now := time.Unix(1000, 0)
+	cb.now = func() time.Time { return now }
+	assert.Equal(t, 1, fakeGauge.SetCallCount())
+	assert.Equal(t, float64(breakerClosed), fakeGauge.SetArgsForCall(0))
+
+	// the failures must be consecutive to open the breaker
+	assert.NoError(t, cb.allow())
+	cb.failure(errors.New("connection refused"))
+	cb.success()
+	cb.failure(errors.New("connection refused"))
+	assert.NoError(t, cb.allow())
+	assert.Equal(t, breakerClosed, cb.state)
+
+	cb.failure(errors.New("connection refused"))
+	err := cb.allow()
+	assert.EqualError(t, err, "CouchDB circuit breaker is open after 2 consecutive failed requests, rejecting the requests until "+
+		now.Add(time.Minute).Format(time.RFC3339)+": last error: connection refused")
+	assert.Equal(t, float64(breakerOpen), fakeGauge.SetArgsForCall(fakeGauge.SetCallCount()-1))
+
+	// a single probe request is let through once the open timeout elapses
+	now = now.Add(time.Minute)
+	assert.NoError(t, cb.allow())
+	assert.Equal(t, float64(breakerHalfOpen), fakeGauge.SetArgsForCall(fakeGauge.SetCallCount()-1))
+	assert.EqualError(t, cb.allow(), "CouchDB circuit breaker is half-open and a probe request is in progress, rejecting the request: last error: connection refused")
+	assert.Equal(t, breakerHalfOpen, cb.state)
+
+	// the breaker opens again if the probe fails
+	cb.failure(errors.New("connection reset"))
+	assert.Contains(t, cb.allow().Error(), "last error: connection reset")
+
+	// the breaker closes if the probe succeeds
+	now = now.Add(time.Minute)
+	assert.NoError(t, cb.allow())
+	cb.success()
+	assert.NoError(t, cb.allow())
+	assert.Equal(t, breakerClosed, cb.state)
+	assert.Equal(t, float64(breakerClosed), fakeGauge.SetArgsForCall(fakeGauge.SetCallCount()-1))
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	cb := newCircuitBreaker(0, time.Minute, &metricsfakes.Gauge{})
+	assert.Nil(t, cb)
+	for i := 0; i < 10; i++ {
+		cb.failure(errors.New("connection refused"))
+	}
+	assert.NoError(t, cb.allow())
+	cb.record(nil, errors.New("connection refused"))
+}
+
+func TestCircuitBreakerRecord(t *testing.T) {
+	cb := newCircuitBreaker(1, time.Minute, &metricsfakes.Gauge{})
+	now := time.Unix(1000, 0)
+	cb.now = func() time.Time { return now }
+
+	// the client errors show that CouchDB is available
+	cb.record(&DBReturn{StatusCode: 404}, errors.New("not found"))
+	assert.Equal(t, breakerClosed, cb.state)
+	cb.record(&DBReturn{StatusCode: 200}, nil)
+	assert.Equal(t, breakerClosed, cb.state)
+
+	cb.record(&DBReturn{StatusCode: 503}, errors.New("service unavailable"))
+	assert.Equal(t, breakerOpen, cb.state)
+
+	// a probe request that fails without a decoded response opens the breaker again
+	now = now.Add(time.Minute)
+	assert.NoError(t, cb.allow())
+	assert.Equal(t, breakerHalfOpen, cb.state)
+	cb.record(nil, errors.New("error reading response body"))
+	assert.Equal(t, breakerOpen, cb.state)
+	assert.Contains(t, cb.allow().Error(), "last error: error reading response body")
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, cb.allow())
+	cb.record(&DBReturn{}, nil)
+	assert.Equal(t, breakerClosed, cb.state)
+}
+
+func TestHandleRequestWithCircuitBreaker(t *testing.T) {
+	var requests int32
+	var status int32 = http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		w.Write([]byte(`{"error":"internal_server_error","reason":"unavailable"}`))
+	}))
+	defer server.Close()
+
+	couchInstance := newTestCouchInstance(t, server.URL, 2)
+	couchInstance.breaker.now = func() time.Time { return time.Unix(1000, 0) }
+	connectURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// the server errors are retried and count as a single failed request
+	_, _, err = couchInstance.handleRequest(context.Background(), http.MethodGet, "db", "test", connectURL, nil, "", "", 1, true, nil)
+	assert.EqualError(t, err, "error handling CouchDB request. Error:internal_server_error,  Status Code:500,  Reason:unavailable")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	_, _, err = couchInstance.handleRequest(context.Background(), http.MethodGet, "db", "test", connectURL, nil, "", "", 0, true, nil)
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// the requests fail fast while the breaker is open
+	_, _, err = couchInstance.handleRequest(context.Background(), http.MethodGet, "db", "test", connectURL, nil, "", "", 3, true, nil)
+	assert.Contains(t, err.Error(), "CouchDB circuit breaker is open after 2 consecutive failed requests")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// the health check reaches CouchDB while the breaker is open
+	err = couchInstance.HealthCheck(context.Background())
+	assert.Contains(t, err.Error(), "Status Code:500")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+	assert.Equal(t, breakerOpen, couchInstance.breaker.state)
+
+	// the probe request closes the breaker once CouchDB recovers
+	couchInstance.breaker.now = func() time.Time { return time.Unix(2000, 0) }
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	_, couchDBReturn, err := couchInstance.handleRequest(context.Background(), http.MethodGet, "db", "test", connectURL, nil, "", "", 0, true, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 404, couchDBReturn.StatusCode)
+	assert.Equal(t, breakerClosed, couchInstance.breaker.state)
+
+	// a successful health check closes the breaker without waiting for the open timeout
+	couchInstance.breaker.failure(errors.New("connection refused"))
+	couchInstance.breaker.failure(errors.New("connection refused"))
+	assert.Equal(t, breakerOpen, couchInstance.breaker.state)
+	atomic.StoreInt32(&status, http.StatusOK)
+	assert.NoError(t, couchInstance.HealthCheck(context.Background()))
+	assert.Equal(t, breakerClosed, couchInstance.breaker.state)
+}
+
+func newTestCouchInstance(t *testing.T, serverURL string, failureThreshold int) *CouchInstance {
+	conf := CouchConnectionDef{URL: serverURL, MaxRetries: 1, MaxRetriesOnStartup: 1, RequestTimeout: 10 * time.Second}
+	stats := newStats(&disabled.Provider{})
+	return &CouchInstance{
+		conf:    conf,
+		client:  &http.Client{Timeout: conf.RequestTimeout, Transport: newTransport(&conf, stats.openConnections)},
+		stats:   stats,
+		breaker: newCircuitBreaker(failureThreshold, time.Minute, stats.circuitBreakerState),
+	}
+}
@@ -31,6 +31,20 @@ type CouchDBDef struct {
 	MaxRetriesOnStartup   int
 	RequestTimeout        time.Duration
 	CreateGlobalChangesDB bool
+	// MaxConnections limits the number of connections to CouchDB, zero means no limit
+	MaxConnections int
+	// MaxIdleConnections is the number of idle connections kept open for reuse
+	MaxIdleConnections int
+	// IdleConnectionTimeout is the duration after which an idle connection is closed
+	IdleConnectionTimeout time.Duration
+	// RequestDeadline limits the duration of a request to a database including its retries, zero means no limit
+	RequestDeadline time.Duration
+	// CircuitBreakerFailureThreshold is the number of consecutive failed requests that opens
+	// the circuit breaker, zero disables the circuit breaker
+	CircuitBreakerFailureThreshold int
+	// CircuitBreakerOpenTimeout is the duration for which an open circuit breaker rejects the
+	// requests before a probe request is let through
+	CircuitBreakerOpenTimeout time.Duration
 }
 
 //GetCouchDBDefinition exposes the useCouchDB variable
@@ -44,5 +58,19 @@ func GetCouchDBDefinition() *CouchDBDef {
 	requestTimeout := viper.GetDuration("ledger.state.couchDBConfig.requestTimeout")
 	createGlobalChangesDB := viper.GetBool("ledger.state.couchDBConfig.createGlobalChangesDB")
 
-	return &CouchDBDef{couchDBAddress, username, password, maxRetries, maxRetriesOnStartup, requestTimeout, createGlobalChangesDB}
+	return &CouchDBDef{
+		URL:                            couchDBAddress,
+		Username:                       username,
+		Password:                       password,
+		MaxRetries:                     maxRetries,
+		MaxRetriesOnStartup:            maxRetriesOnStartup,
+		RequestTimeout:                 requestTimeout,
+		CreateGlobalChangesDB:          createGlobalChangesDB,
+		MaxConnections:                 viper.GetInt("ledger.state.couchDBConfig.maxConnections"),
+		MaxIdleConnections:             viper.GetInt("ledger.state.couchDBConfig.maxIdleConnections"),
+		IdleConnectionTimeout:          viper.GetDuration("ledger.state.couchDBConfig.idleConnectionTimeout"),
+		RequestDeadline:                viper.GetDuration("ledger.state.couchDBConfig.requestDeadline"),
+		CircuitBreakerFailureThreshold: viper.GetInt("ledger.state.couchDBConfig.circuitBreaker.failureThreshold"),
+		CircuitBreakerOpenTimeout:      viper.GetDuration("ledger.state.couchDBConfig.circuitBreaker.openTimeout"),
+	}
 }
@@ -24,4 +24,10 @@ func TestGetCouchDBDefinition(t *testing.T) {
 	assert.Equal(t, 3, couchDBDef.MaxRetries)
 	assert.Equal(t, 20, couchDBDef.MaxRetriesOnStartup)
 	assert.Equal(t, time.Second*35, couchDBDef.RequestTimeout)
+	assert.Equal(t, time.Second*90, couchDBDef.RequestDeadline)
+	assert.Equal(t, 0, couchDBDef.MaxConnections)
+	assert.Equal(t, 100, couchDBDef.MaxIdleConnections)
+	assert.Equal(t, time.Second*90, couchDBDef.IdleConnectionTimeout)
+	assert.Equal(t, 5, couchDBDef.CircuitBreakerFailureThreshold)
+	assert.Equal(t, time.Second*30, couchDBDef.CircuitBreakerOpenTimeout)
 }
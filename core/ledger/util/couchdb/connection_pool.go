@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package couchdb
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+// connectionPool manages the connections to CouchDB. The connections are pooled by the
+// http transport, which is bounded by the configured limits; the pool counts the connections
+// that are open, including the idle ones, and reports the count through a gauge
+type connectionPool struct {
+	dialer          *net.Dialer
+	openConnections int64
+	gauge           metrics.Gauge
+}
+
+// newTransport constructs the http transport for the connections to CouchDB
+func newTransport(conf *CouchConnectionDef, openConnectionsGauge metrics.Gauge) *http.Transport {
+	pool := &connectionPool{
+		dialer: &net.Dialer{
+			Timeout:   conf.RequestTimeout,
+			KeepAlive: 30 * time.Second,
+		},
+		gauge: openConnectionsGauge,
+	}
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         pool.dialContext,
+		MaxConnsPerHost:     conf.MaxConnections,
+		MaxIdleConnsPerHost: conf.MaxIdleConnections,
+		IdleConnTimeout:     conf.IdleConnectionTimeout,
+	}
+}
+
+func (p *connectionPool) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := p.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	p.gauge.Set(float64(atomic.AddInt64(&p.openConnections, 1)))
+	return &pooledConn{Conn: conn, pool: p}, nil
+}
+
+// pooledConn decrements the count of the open connections when it is closed
+type pooledConn struct {
+	net.Conn
+	pool *connectionPool
+	once sync.Once
+}
+
+func (c *pooledConn) Close() error {
+	c.once.Do(func() {
+		c.pool.gauge.Set(float64(atomic.AddInt64(&c.pool.openConnections, -1)))
+	})
+	return c.Conn.Close()
+}
+
+// cancelOnCloseBody releases the context of a request once the caller closes the response body
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package couchdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDeadline(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"unavailable","reason":"unavailable"}`))
+	}))
+	defer server.Close()
+
+	couchInstance := newTestCouchInstance(t, server.URL, 0)
+	couchInstance.conf.RequestDeadline = 200 * time.Millisecond
+	db := &CouchDatabase{CouchInstance: couchInstance, DBName: "db"}
+	connectURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// without the deadline, 10 retries would take more than two minutes
+	start := time.Now()
+	_, _, err = db.handleRequest(http.MethodGet, "test", connectURL, nil, "", "", 10, true, nil)
+	assert.Contains(t, err.Error(), "CouchDB request test for database [db] did not complete after")
+	assert.Contains(t, err.Error(), "context deadline exceeded")
+	assert.True(t, time.Since(start) < 10*time.Second)
+	assert.True(t, atomic.LoadInt32(&requests) < 10)
+}
+
+func TestOpenConnectionsMetric(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	fakeGauge := &metricsfakes.Gauge{}
+	transport := newTransport(&CouchConnectionDef{RequestTimeout: 10 * time.Second, MaxIdleConnections: 10}, fakeGauge)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	closeResponseBody(resp)
+	require.Equal(t, 1, fakeGauge.SetCallCount())
+	assert.Equal(t, float64(1), fakeGauge.SetArgsForCall(0))
+
+	// the idle connection is reused
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	closeResponseBody(resp)
+	assert.Equal(t, 1, fakeGauge.SetCallCount())
+
+	transport.CloseIdleConnections()
+	require.Equal(t, 2, fakeGauge.SetCallCount())
+	assert.Equal(t, float64(0), fakeGauge.SetArgsForCall(1))
+}
@@ -125,13 +125,18 @@ type CouchConnectionDef struct {
 	MaxRetriesOnStartup   int
 	RequestTimeout        time.Duration
 	CreateGlobalChangesDB bool
+	MaxConnections        int
+	MaxIdleConnections    int
+	IdleConnectionTimeout time.Duration
+	RequestDeadline       time.Duration
 }
 
 //CouchInstance represents a CouchDB instance
 type CouchInstance struct {
-	conf   CouchConnectionDef //connection configuration
-	client *http.Client       // a client to connect to this instance
-	stats  *stats
+	conf    CouchConnectionDef //connection configuration
+	client  *http.Client       // a client to connect to this instance
+	stats   *stats
+	breaker *circuitBreaker // fails the requests fast while CouchDB is unavailable
 }
 
 //CouchDatabase represents a database within a CouchDB instance
@@ -253,8 +258,15 @@ func CreateConnectionDefinition(couchDBAddress, username, password string, maxRe
 	logger.Debugf("Exiting CreateConnectionDefinition()")
 
 	//return an object containing the connection information
-	return &CouchConnectionDef{finalURL.String(), username, password, maxRetries,
-		maxRetriesOnStartup, requestTimeout, createGlobalChangesDB}, nil
+	return &CouchConnectionDef{
+		URL:                   finalURL.String(),
+		Username:              username,
+		Password:              password,
+		MaxRetries:            maxRetries,
+		MaxRetriesOnStartup:   maxRetriesOnStartup,
+		RequestTimeout:        requestTimeout,
+		CreateGlobalChangesDB: createGlobalChangesDB,
+	}, nil
 
 }
 
@@ -444,7 +456,10 @@ func (couchInstance *CouchInstance) HealthCheck(ctx context.Context) error {
 		logger.Errorf("URL parse error: %s", err)
 		return errors.Wrapf(err, "error parsing CouchDB URL: %s", couchInstance.conf.URL)
 	}
-	_, _, err = couchInstance.handleRequest(ctx, http.MethodHead, "", "HealthCheck", connectURL, nil, "", "", 0, true, nil)
+	// the health check bypasses the circuit breaker so that it reports whether CouchDB is reachable
+	// while the requests are rejected. Its outcome is recorded like the outcome of any other request
+	_, couchDBReturn, err := couchInstance.handleRequestWithRetries(ctx, http.MethodHead, "", "HealthCheck", connectURL, nil, "", "", 0, true, nil)
+	couchInstance.breaker.record(couchDBReturn, err)
 	if err != nil {
 		return fmt.Errorf("failed to connect to couch db [%s]", err)
 	}
@@ -1647,19 +1662,46 @@ func (dbclient *CouchDatabase) handleRequestWithRevisionRetry(id, method, dbName
 func (dbclient *CouchDatabase) handleRequest(method, functionName string, connectURL *url.URL, data []byte, rev, multipartBoundary string,
 	maxRetries int, keepConnectionOpen bool, queryParms *url.Values, pathElements ...string) (*http.Response, *DBReturn, error) {
 
-	return dbclient.CouchInstance.handleRequest(context.Background(),
+	// the deadline bounds the request including its retries, so that a slow CouchDB
+	// fails the request instead of stalling the caller
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if deadline := dbclient.CouchInstance.conf.RequestDeadline; deadline > 0 {
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+	}
+
+	resp, couchDBReturn, err := dbclient.CouchInstance.handleRequest(ctx,
 		method, dbclient.DBName, functionName, connectURL, data, rev, multipartBoundary,
 		maxRetries, keepConnectionOpen, queryParms, pathElements...,
 	)
+	if err != nil || resp == nil {
+		cancel()
+		return resp, couchDBReturn, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, couchDBReturn, nil
 }
 
-//handleRequest method is a generic http request handler.
+//handleRequest method is a generic http request handler guarded by the circuit breaker.
 // If it returns an error, it ensures that the response body is closed, else it is the
 // callee's responsibility to close response correctly.
 // Any http error or CouchDB error (4XX or 500) will result in a golang error getting returned
 func (couchInstance *CouchInstance) handleRequest(ctx context.Context, method, dbName, functionName string, connectURL *url.URL, data []byte, rev string,
 	multipartBoundary string, maxRetries int, keepConnectionOpen bool, queryParms *url.Values, pathElements ...string) (*http.Response, *DBReturn, error) {
 
+	if err := couchInstance.breaker.allow(); err != nil {
+		logger.Debugf("Rejecting CouchDB request %s for database [%s]: %s", functionName, dbName, err)
+		return nil, nil, err
+	}
+
+	resp, couchDBReturn, err := couchInstance.handleRequestWithRetries(ctx, method, dbName, functionName, connectURL, data, rev,
+		multipartBoundary, maxRetries, keepConnectionOpen, queryParms, pathElements...)
+	couchInstance.breaker.record(couchDBReturn, err)
+	return resp, couchDBReturn, err
+}
+
+func (couchInstance *CouchInstance) handleRequestWithRetries(ctx context.Context, method, dbName, functionName string, connectURL *url.URL, data []byte, rev string,
+	multipartBoundary string, maxRetries int, keepConnectionOpen bool, queryParms *url.Values, pathElements ...string) (*http.Response, *DBReturn, error) {
+
 	logger.Debugf("Entering handleRequest()  method=%s  url=%v  dbName=%s", method, connectURL, dbName)
 
 	//create the return objects for couchDB
@@ -1700,7 +1742,7 @@ func (couchInstance *CouchInstance) handleRequest(ctx context.Context, method, d
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "error creating http request")
 		}
-		req.WithContext(ctx)
+		req = req.WithContext(ctx)
 
 		//set the request to close on completion if shared connections are not allowSharedConnection
 		//Current CouchDB has a problem with zero length attachments, do not allow the connection to be reused.
@@ -1751,6 +1793,13 @@ func (couchInstance *CouchInstance) handleRequest(ctx context.Context, method, d
 		//Execute http request
 		resp, errResp = couchInstance.client.Do(req)
 
+		//do not retry once the deadline of the request has passed or the request is canceled
+		if errResp != nil && ctx.Err() != nil {
+			errResp = errors.WithMessage(ctx.Err(), fmt.Sprintf("CouchDB request %s for database [%s] did not complete after %d attempt(s)",
+				functionName, dbName, attempts+1))
+			break
+		}
+
 		//check to see if the return from CouchDB is valid
 		if invalidCouchDBReturn(resp, errResp) {
 			continue
@@ -1809,8 +1858,11 @@ func (couchInstance *CouchInstance) handleRequest(ctx context.Context, method, d
 					waitDuration.String(), attempts+1, couchDBReturn.Error, resp.Status, couchDBReturn.Reason)
 
 			}
-			//sleep for specified sleep time, then retry
-			time.Sleep(waitDuration)
+			//sleep for specified sleep time, then retry, unless the request is done meanwhile
+			select {
+			case <-time.After(waitDuration):
+			case <-ctx.Done():
+			}
 
 			//backoff, doubling the retry time for next attempt
 			waitDuration *= 2
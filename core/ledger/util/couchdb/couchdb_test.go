@@ -151,7 +151,7 @@ func TestHealthCheck(t *testing.T) {
 	badConnectDef := CouchConnectionDef{URL: badURL, Username: "", Password: "",
 		MaxRetries: 1, MaxRetriesOnStartup: 1, RequestTimeout: time.Second * 30}
 
-	badCouchDBInstance := CouchInstance{badConnectDef, client, newStats(&disabled.Provider{})}
+	badCouchDBInstance := CouchInstance{conf: badConnectDef, client: client, stats: newStats(&disabled.Provider{})}
 	err := badCouchDBInstance.HealthCheck(context.Background())
 	assert.Error(t, err, "Health check should result in an error if unable to connect to couch db")
 	assert.Contains(t, err.Error(), "failed to connect to couch db")
@@ -161,7 +161,7 @@ func TestHealthCheck(t *testing.T) {
 	goodConnectDef := CouchConnectionDef{URL: goodURL, Username: "", Password: "",
 		MaxRetries: 1, MaxRetriesOnStartup: 1, RequestTimeout: time.Second * 30}
 
-	goodCouchDBInstance := CouchInstance{goodConnectDef, client, newStats(&disabled.Provider{})}
+	goodCouchDBInstance := CouchInstance{conf: goodConnectDef, client: client, stats: newStats(&disabled.Provider{})}
 	err = goodCouchDBInstance.HealthCheck(context.Background())
 	assert.NoError(t, err)
 }
@@ -175,7 +175,7 @@ func TestBadCouchDBInstance(t *testing.T) {
 	client := &http.Client{}
 
 	//Create a bad couchdb instance
-	badCouchDBInstance := CouchInstance{badConnectDef, client, newStats(&disabled.Provider{})}
+	badCouchDBInstance := CouchInstance{conf: badConnectDef, client: client, stats: newStats(&disabled.Provider{})}
 
 	//Create a bad CouchDatabase
 	badDB := CouchDatabase{&badCouchDBInstance, "baddb", 1}
@@ -36,25 +36,48 @@ var collectionNameAllowedLength = 50
 func CreateCouchInstance(couchDBConnectURL, id, pw string, maxRetries,
 	maxRetriesOnStartup int, connectionTimeout time.Duration, createGlobalChangesDB bool, metricsProvider metrics.Provider) (*CouchInstance, error) {
 
-	couchConf, err := CreateConnectionDefinition(couchDBConnectURL,
-		id, pw, maxRetries, maxRetriesOnStartup, connectionTimeout, createGlobalChangesDB)
+	return CreateCouchInstanceFromDef(&CouchDBDef{
+		URL:                   couchDBConnectURL,
+		Username:              id,
+		Password:              pw,
+		MaxRetries:            maxRetries,
+		MaxRetriesOnStartup:   maxRetriesOnStartup,
+		RequestTimeout:        connectionTimeout,
+		CreateGlobalChangesDB: createGlobalChangesDB,
+	}, metricsProvider)
+}
+
+//CreateCouchInstanceFromDef creates a CouchDB instance with the connection pool,
+//the request deadline, and the circuit breaker configured in the CouchDBDef
+func CreateCouchInstanceFromDef(couchDBDef *CouchDBDef, metricsProvider metrics.Provider) (*CouchInstance, error) {
+
+	couchConf, err := CreateConnectionDefinition(couchDBDef.URL,
+		couchDBDef.Username, couchDBDef.Password, couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup,
+		couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB)
 	if err != nil {
 		logger.Errorf("Error calling CouchDB CreateConnectionDefinition(): %s", err)
 		return nil, err
 	}
+	couchConf.MaxConnections = couchDBDef.MaxConnections
+	couchConf.MaxIdleConnections = couchDBDef.MaxIdleConnections
+	couchConf.IdleConnectionTimeout = couchDBDef.IdleConnectionTimeout
+	couchConf.RequestDeadline = couchDBDef.RequestDeadline
+
+	stats := newStats(metricsProvider)
 
 	// Create the http client once
 	// Clients and Transports are safe for concurrent use by multiple goroutines
 	// and for efficiency should only be created once and re-used.
 	client := &http.Client{Timeout: couchConf.RequestTimeout}
-
-	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
-	transport.DisableCompression = false
-	client.Transport = transport
+	client.Transport = newTransport(couchConf, stats.openConnections)
 
 	//Create the CouchDB instance
-	couchInstance := &CouchInstance{conf: *couchConf, client: client}
-	couchInstance.stats = newStats(metricsProvider)
+	couchInstance := &CouchInstance{
+		conf:    *couchConf,
+		client:  client,
+		stats:   stats,
+		breaker: newCircuitBreaker(couchDBDef.CircuitBreakerFailureThreshold, couchDBDef.CircuitBreakerOpenTimeout, stats.circuitBreakerState),
+	}
 	connectInfo, retVal, verifyErr := couchInstance.VerifyCouchConfig()
 	if verifyErr != nil {
 		return nil, verifyErr
@@ -20,15 +20,35 @@ var (
 		LabelNames:   []string{"database", "function_name", "result"},
 		StatsdFormat: "%{#fqname}.%{database}.%{function_name}.%{result}",
 	}
+
+	openConnectionsOpts = metrics.GaugeOpts{
+		Namespace:    "couchdb",
+		Subsystem:    "",
+		Name:         "open_connections",
+		Help:         "Number of open connections to CouchDB, including the idle connections in the pool.",
+		StatsdFormat: "%{#fqname}",
+	}
+
+	circuitBreakerStateOpts = metrics.GaugeOpts{
+		Namespace:    "couchdb",
+		Subsystem:    "",
+		Name:         "circuit_breaker_state",
+		Help:         "State of the circuit breaker of the requests to CouchDB: 0 is closed, 1 is half-open, and 2 is open.",
+		StatsdFormat: "%{#fqname}",
+	}
 )
 
 type stats struct {
-	apiProcessingTime metrics.Histogram
+	apiProcessingTime   metrics.Histogram
+	openConnections     metrics.Gauge
+	circuitBreakerState metrics.Gauge
 }
 
 func newStats(metricsProvider metrics.Provider) *stats {
 	return &stats{
-		apiProcessingTime: metricsProvider.NewHistogram(apiProcessingTimeOpts),
+		apiProcessingTime:   metricsProvider.NewHistogram(apiProcessingTimeOpts),
+		openConnections:     metricsProvider.NewGauge(openConnectionsOpts),
+		circuitBreakerState: metricsProvider.NewGauge(circuitBreakerStateOpts),
 	}
 }
 
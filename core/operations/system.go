@@ -144,6 +144,12 @@ func (s *System) RegisterChecker(component string, checker healthz.HealthChecker
 	return s.healthHandler.RegisterChecker(component, checker)
 }
 
+// RegisterHandler registers the given handler for the given pattern. The handler
+// requires a client certificate when TLS is enabled.
+func (s *System) RegisterHandler(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, s.handlerChain(handler, s.options.TLS.Enabled))
+}
+
 func (s *System) initializeServer() {
 	s.mux = http.NewServeMux()
 	s.httpServer = &http.Server{
@@ -104,6 +104,24 @@ var _ = Describe("System", func() {
 		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 	})
 
+	It("hosts a secure endpoint for a registered handler", func() {
+		system.RegisterHandler("/admin/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}))
+		err := system.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		adminURL := fmt.Sprintf("https://%s/admin/things", system.Addr())
+		resp, err := client.Get(adminURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusTeapot))
+		resp.Body.Close()
+
+		resp, err = unauthClient.Get(adminURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
 	Context("when TLS is disabled", func() {
 		BeforeEach(func() {
 			options.TLS.Enabled = false
@@ -523,6 +523,20 @@ func CreateChainFromBlock(cb *common.Block, ccp ccprovider.ChaincodeProvider, sc
 	return createChain(cid, l, cb, ccp, sccp, pluginMapper)
 }
 
+// CreateChainFromSnapshot creates a new chain from the ledger snapshot present in the given dir
+func CreateChainFromSnapshot(snapshotDir string, ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider) (string, error) {
+	l, cid, err := ledgermgmt.CreateLedgerFromSnapshot(snapshotDir)
+	if err != nil {
+		return "", errors.WithMessage(err, "cannot create ledger from snapshot")
+	}
+
+	cb, err := getCurrConfigBlockFromLedger(l)
+	if err != nil {
+		return "", errors.WithMessage(err, "cannot retrieve the config block from the ledger created from snapshot")
+	}
+	return cid, createChain(cid, l, cb, ccp, sccp, pluginMapper)
+}
+
 // GetLedger returns the ledger of the chain with chain ID. Note that this
 // call returns nil if chain cid has not been created.
 func GetLedger(cid string) ledger.PeerLedger {
@@ -13,6 +13,7 @@ package cscc
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
@@ -77,6 +78,9 @@ const (
 	GetChannels              string = "GetChannels"
 	GetConfigTree            string = "GetConfigTree"
 	SimulateConfigTreeUpdate string = "SimulateConfigTreeUpdate"
+	JoinChainBySnapshot      string = "JoinChainBySnapshot"
+	ExportSnapshot           string = "ExportSnapshot"
+	SubmitSnapshotRequest    string = "SubmitSnapshotRequest"
 )
 
 // Init is mostly useless from an SCC perspective
@@ -93,7 +97,9 @@ func (e *PeerConfiger) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # args[0] is the function name, which must be JoinChain, GetConfigBlock or
 // UpdateConfigBlock
 // # args[1] is a configuration Block if args[0] is JoinChain or
-// UpdateConfigBlock; otherwise it is the chain id
+// UpdateConfigBlock, the snapshot directory if args[0] is JoinChainBySnapshot;
+// otherwise it is the chain id
+// # args[2] is the block number if args[0] is SubmitSnapshotRequest
 // TODO: Improve the scc interface to avoid marshal/unmarshal args
 func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
@@ -190,7 +196,30 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 		}
 
 		return getChannels()
-
+	case JoinChainBySnapshot:
+		if len(args[1]) == 0 {
+			return shim.Error("Cannot join the channel, no snapshot directory provided")
+		}
+		// check local MSP Admins policy
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s]: [%s]", fname, err))
+		}
+		return joinChainBySnapshot(string(args[1]), e.ccp, e.sccp)
+	case ExportSnapshot:
+		// check local MSP Admins policy
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, args[1], err))
+		}
+		return exportSnapshot(string(args[1]))
+	case SubmitSnapshotRequest:
+		if len(args) < 3 {
+			return shim.Error(fmt.Sprintf("Incorrect number of arguments, %d", len(args)))
+		}
+		// check local MSP Admins policy
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, args[1], err))
+		}
+		return submitSnapshotRequest(string(args[1]), string(args[2]))
 	}
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
 }
@@ -242,6 +271,50 @@ func joinChain(chainID string, block *common.Block, ccp ccprovider.ChaincodeProv
 	return shim.Success(nil)
 }
 
+// joinChainBySnapshot will join the chain by creating the ledger from the snapshot present in the given
+// directory on the filesystem of the peer
+func joinChainBySnapshot(snapshotDir string, ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider) pb.Response {
+	chainID, err := peer.CreateChainFromSnapshot(snapshotDir, ccp, sccp)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	peer.InitChain(chainID)
+
+	return shim.Success(nil)
+}
+
+// exportSnapshot generates a snapshot of the ledger for the specified chainID at the last committed
+// block and returns the directory in which the snapshot is generated
+func exportSnapshot(chainID string) pb.Response {
+	l := peer.GetLedger(chainID)
+	if l == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", chainID))
+	}
+	snapshotInfo, err := l.ExportSnapshot()
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success([]byte(snapshotInfo.Dir))
+}
+
+// submitSnapshotRequest submits a request for generating a snapshot of the ledger for the specified
+// chainID when the block with the given number is committed
+func submitSnapshotRequest(chainID, blockNumber string) pb.Response {
+	l := peer.GetLedger(chainID)
+	if l == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", chainID))
+	}
+	blockNum, err := strconv.ParseUint(blockNumber, 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid block number [%s]: %s", blockNumber, err))
+	}
+	if err := l.SubmitSnapshotRequest(blockNum); err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(nil)
+}
+
 // Return the current configuration block for the specified chainID. If the
 // peer doesn't belong to the chain, return error
 func getConfigBlock(chainID []byte) pb.Response {
@@ -369,6 +369,37 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	if len(cqr.GetChannels()) != 1 {
 		t.FailNow()
 	}
+
+	// export a snapshot of the joined channel
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(ExportSnapshot), []byte("unknownchainid")}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Unknown chain ID, unknownchainid", res.Message)
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(ExportSnapshot), []byte(chainID)}, sProp)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	snapshotDir := string(res.Payload)
+	assert.DirExists(t, snapshotDir)
+
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(SubmitSnapshotRequest), []byte(chainID)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Incorrect number of arguments, 2", res.Message)
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(SubmitSnapshotRequest), []byte(chainID), []byte("ten")}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Invalid block number [ten]")
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(SubmitSnapshotRequest), []byte(chainID), []byte("10")}, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	// joining the same channel from the snapshot must fail
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(JoinChainBySnapshot), []byte(snapshotDir)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "LedgerID already exists")
+
+	sProp.Signature = nil
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(JoinChainBySnapshot), []byte(snapshotDir)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "access denied for [JoinChainBySnapshot]")
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(ExportSnapshot), []byte(chainID)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "access denied for [ExportSnapshot][mytestchainid]")
 }
 
 func TestGetConfigTree(t *testing.T) {
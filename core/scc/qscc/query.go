@@ -14,7 +14,9 @@ import (
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -40,6 +42,7 @@ func (e *LedgerQuerier) Enabled() bool             { return true }
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetKeyAccessHistory returns the transactions that read or wrote a key
 type LedgerQuerier struct {
 	aclProvider aclmgmt.ACLProvider
 }
@@ -48,11 +51,12 @@ var qscclogger = flogging.MustGetLogger("qscc")
 
 // These are function names from Invoke first parameter
 const (
-	GetChainInfo       string = "GetChainInfo"
-	GetBlockByNumber   string = "GetBlockByNumber"
-	GetBlockByHash     string = "GetBlockByHash"
-	GetTransactionByID string = "GetTransactionByID"
-	GetBlockByTxID     string = "GetBlockByTxID"
+	GetChainInfo        string = "GetChainInfo"
+	GetBlockByNumber    string = "GetBlockByNumber"
+	GetBlockByHash      string = "GetBlockByHash"
+	GetTransactionByID  string = "GetTransactionByID"
+	GetBlockByTxID      string = "GetBlockByTxID"
+	GetKeyAccessHistory string = "GetKeyAccessHistory"
 )
 
 // Init is called once per chain when the chain is created.
@@ -71,6 +75,8 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetKeyAccessHistory: Return the transactions that accessed the key in args[3] of the namespace in args[2],
+// with the optional page size in args[4] and bookmark in args[5]
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -115,6 +121,8 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
 		return getBlockByTxID(targetLedger, args[2])
+	case GetKeyAccessHistory:
+		return getKeyAccessHistory(targetLedger, args[2:])
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
@@ -214,6 +222,56 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+// getKeyAccessHistory returns a page of the transactions that read or wrote a key. If the page size is
+// not specified, the page is limited to the total query limit configured for the peer
+func getKeyAccessHistory(vledger ledger.PeerLedger, args [][]byte) pb.Response {
+	if len(args) < 2 {
+		return shim.Error("Namespace and key must not be nil.")
+	}
+	namespace, key := string(args[0]), string(args[1])
+	options := &ledger.HistoryQueryOptions{PageSize: int32(ledgerconfig.GetTotalQueryLimit())}
+	if len(args) > 2 && len(args[2]) > 0 {
+		pageSize, err := strconv.ParseInt(string(args[2]), 10, 32)
+		if err != nil || pageSize <= 0 {
+			return shim.Error(fmt.Sprintf("Invalid page size %s", string(args[2])))
+		}
+		options.PageSize = int32(pageSize)
+	}
+	if len(args) > 3 {
+		options.Bookmark = string(args[3])
+	}
+
+	hqe, err := vledger.NewHistoryQueryExecutor()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history query executor with error %s", err))
+	}
+	itr, err := hqe.GetAccessHistoryForKey(namespace, key, options)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get access history for key %s in namespace %s, error %s", key, namespace, err))
+	}
+
+	accessHistory := &queryresult.KeyAccessHistory{}
+	for {
+		result, err := itr.Next()
+		if err != nil {
+			itr.Close()
+			return shim.Error(fmt.Sprintf("Failed to get access history for key %s in namespace %s, error %s", key, namespace, err))
+		}
+		if result == nil {
+			break
+		}
+		accessHistory.Accesses = append(accessHistory.Accesses, result.(*queryresult.KeyAccess))
+	}
+	accessHistory.Bookmark = itr.GetBookmarkAndClose()
+
+	bytes, err := utils.Marshal(accessHistory)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
 func getACLResource(fname string) string {
 	return "qscc/" + fname
 }
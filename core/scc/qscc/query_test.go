@@ -21,6 +21,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
@@ -29,6 +31,7 @@ import (
 	ledger2 "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	peer2 "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -328,6 +331,56 @@ func TestQueryGeneratedBlock(t *testing.T) {
 	}
 }
 
+func TestQueryGetKeyAccessHistory(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := tempDir(t, "test9")
+	defer os.RemoveAll(path)
+
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	defer viper.Set("ledger.history.enableHistoryDatabase", false)
+	stub, err := setupTestLedger(chainid, path)
+	require.NoError(t, err)
+	// the accesses are indexed once the channel config enables the access index
+	addConfigBlockForTesting(t, chainid, capabilities.ApplicationKeyAccessIndex)
+	block2 := addBlockForTesting(t, chainid)
+
+	args := [][]byte{[]byte(GetKeyAccessHistory), []byte(chainid), []byte("ns1"), []byte("key1")}
+	prop := resetProvider(resources.Qscc_GetKeyAccessHistory, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	accessHistory := &queryresult.KeyAccessHistory{}
+	require.NoError(t, proto.Unmarshal(res.Payload, accessHistory))
+	require.Len(t, accessHistory.Accesses, 1)
+	txID, err := utils.GetOrComputeTxIDFromEnvelope(block2.Data.Data[0])
+	require.NoError(t, err)
+	assert.Equal(t, txID, accessHistory.Accesses[0].TxId)
+	assert.True(t, accessHistory.Accesses[0].Write)
+	assert.False(t, accessHistory.Accesses[0].Read)
+	assert.Equal(t, peer2.TxValidationCode_VALID, accessHistory.Accesses[0].ValidationCode)
+	assert.Equal(t, uint64(2), accessHistory.Accesses[0].BlockNum)
+	assert.NotEmpty(t, accessHistory.Bookmark)
+
+	// the next page starting after the bookmark is empty
+	args = [][]byte{[]byte(GetKeyAccessHistory), []byte(chainid), []byte("ns1"), []byte("key1"), []byte("10"), []byte(accessHistory.Bookmark)}
+	prop = resetProvider(resources.Qscc_GetKeyAccessHistory, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	accessHistory = &queryresult.KeyAccessHistory{}
+	require.NoError(t, proto.Unmarshal(res.Payload, accessHistory))
+	assert.Empty(t, accessHistory.Accesses)
+
+	args = [][]byte{[]byte(GetKeyAccessHistory), []byte(chainid), []byte("ns1"), []byte("key1"), []byte("-1")}
+	prop = resetProvider(resources.Qscc_GetKeyAccessHistory, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Invalid page size -1", res.Message)
+
+	args = [][]byte{[]byte(GetKeyAccessHistory), []byte(chainid), []byte("ns1")}
+	prop = resetProvider(resources.Qscc_GetKeyAccessHistory, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetKeyAccessHistory should have failed without a key")
+}
+
 func addBlockForTesting(t *testing.T, chainid string) *common.Block {
 	ledger := peer.GetLedger(chainid)
 	defer ledger.Close()
@@ -352,11 +405,19 @@ func addBlockForTesting(t *testing.T, chainid string) *common.Block {
 
 	bcInfo, err := ledger.GetBlockchainInfo()
 	assert.NoError(t, err)
-	block1 := testutil.ConstructBlock(t, 1, bcInfo.CurrentBlockHash, [][]byte{pubSimResBytes1, pubSimResBytes2}, false)
+	block1 := testutil.ConstructBlock(t, bcInfo.Height, bcInfo.CurrentBlockHash, [][]byte{pubSimResBytes1, pubSimResBytes2}, false)
 	ledger.CommitWithPvtData(&ledger2.BlockAndPvtData{Block: block1}, &ledger2.CommitOptions{})
 	return block1
 }
 
+func addConfigBlockForTesting(t *testing.T, chainid string, applicationCapabilities ...string) {
+	ledger := peer.GetLedger(chainid)
+	bcInfo, err := ledger.GetBlockchainInfo()
+	require.NoError(t, err)
+	block := testutil.ConstructConfigBlock(t, bcInfo.Height, bcInfo.CurrentBlockHash, chainid, applicationCapabilities...)
+	require.NoError(t, ledger.CommitWithPvtData(&ledger2.BlockAndPvtData{Block: block}, &ledger2.CommitOptions{}))
+}
+
 var mockAclProvider *mocks.MockACLProvider
 
 func TestMain(m *testing.M) {
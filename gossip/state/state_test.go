@@ -331,6 +331,18 @@ func (mock *ramLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	return false, nil
 }
 
+func (mock *ramLedger) ExportSnapshot() (*ledger.SnapshotInfo, error) {
+	panic("implement me")
+}
+
+func (mock *ramLedger) SubmitSnapshotRequest(blockNum uint64) error {
+	panic("implement me")
+}
+
+func (mock *ramLedger) PendingSnapshotRequests() ([]uint64, error) {
+	panic("implement me")
+}
+
 func (mock *ramLedger) GetBlockByNumber(blockNumber uint64) (*pcomm.Block, error) {
 	mock.RLock()
 	defer mock.RUnlock()
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"math"
+	"time"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+)
+
+// arrivalWeight is the weight of the latest interval between two messages
+// in the moving average of the intervals.
+const arrivalWeight = 0.2
+
+// batchTimeoutTuner is implemented by receivers which tune the batch
+// timeout from the load of the channel.
+type batchTimeoutTuner interface {
+	// BatchTimeout returns the time to wait before cutting the pending batch.
+	BatchTimeout() time.Duration
+}
+
+// BatchTimeout returns the time to wait before cutting the pending batch of
+// the receiver, which is the batch timeout of the channel unless the receiver
+// tunes it.
+func BatchTimeout(r Receiver, ordererConfig channelconfig.Orderer) time.Duration {
+	if tuner, ok := r.(batchTimeoutTuner); ok {
+		return tuner.BatchTimeout()
+	}
+	return ordererConfig.BatchTimeout()
+}
+
+// arrivalEstimator estimates the arrival rate of messages from an exponentially
+// weighted moving average of the intervals between them.
+type arrivalEstimator struct {
+	last     time.Time
+	samples  int
+	interval float64
+}
+
+// observe records the arrival of a message.
+func (a *arrivalEstimator) observe(now time.Time) {
+	if !a.last.IsZero() {
+		sample := now.Sub(a.last).Seconds()
+		if sample < 0 {
+			sample = 0
+		}
+		if a.samples == 0 {
+			a.interval = sample
+		} else {
+			a.interval = arrivalWeight*sample + (1-arrivalWeight)*a.interval
+		}
+		a.samples++
+	}
+	a.last = now
+}
+
+// perSecond returns the estimated number of messages per second.
+func (a *arrivalEstimator) perSecond() float64 {
+	switch {
+	case a.samples == 0:
+		return 0
+	case a.interval == 0:
+		return math.Inf(1)
+	default:
+		return 1 / a.interval
+	}
+}
+
+// tune returns the max message count and the batch timeout of a batch whose
+// first message is ordered within the target latency at the estimated arrival
+// rate. They are bounded by the configured max message count and batch timeout.
+//
+// At low load, when no other message is expected within the target latency,
+// every message is cut into its own batch right away. At higher load, batches
+// grow with the number of messages expected within the target latency, and the
+// batch timeout shrinks to twice the time it takes them to arrive, so that a
+// batch is not held for long once the load drops.
+func (a *arrivalEstimator) tune(targetLatency, batchTimeout time.Duration, maxMessageCount uint32) (uint32, time.Duration) {
+	timeout := batchTimeout
+	if targetLatency < timeout {
+		timeout = targetLatency
+	}
+
+	rate := a.perSecond()
+	if rate == 0 {
+		return 1, timeout
+	}
+
+	count := maxMessageCount
+	if expected := math.Floor(targetLatency.Seconds()*rate) + 1; expected < float64(maxMessageCount) {
+		count = uint32(expected)
+	}
+
+	if fill := time.Duration(2 * float64(count) / rate * float64(time.Second)); fill > 0 && fill < timeout {
+		timeout = fill
+	}
+	return count, timeout
+}
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrivalEstimator(t *testing.T) {
+	start := time.Now()
+	arrive := func(a *arrivalEstimator, interval time.Duration, count int) {
+		for i := 0; i < count; i++ {
+			a.observe(start)
+			start = start.Add(interval)
+		}
+	}
+
+	t.Run("no arrivals", func(t *testing.T) {
+		a := &arrivalEstimator{}
+		assert.Equal(t, float64(0), a.perSecond())
+
+		count, timeout := a.tune(time.Second, 2*time.Second, 100)
+		assert.Equal(t, uint32(1), count)
+		assert.Equal(t, time.Second, timeout)
+	})
+
+	t.Run("low load", func(t *testing.T) {
+		a := &arrivalEstimator{}
+		arrive(a, 5*time.Second, 10)
+		assert.InDelta(t, 0.2, a.perSecond(), 0.001)
+
+		count, timeout := a.tune(time.Second, 2*time.Second, 100)
+		assert.Equal(t, uint32(1), count)
+		assert.Equal(t, time.Second, timeout)
+	})
+
+	t.Run("moderate load", func(t *testing.T) {
+		a := &arrivalEstimator{}
+		arrive(a, 125*time.Millisecond, 10)
+		assert.InDelta(t, 8, a.perSecond(), 0.001)
+
+		count, timeout := a.tune(time.Second, 2*time.Second, 100)
+		assert.Equal(t, uint32(9), count)
+		assert.Equal(t, time.Second, timeout)
+	})
+
+	t.Run("high load", func(t *testing.T) {
+		a := &arrivalEstimator{}
+		arrive(a, time.Millisecond, 10)
+		assert.InDelta(t, 1000, a.perSecond(), 0.001)
+
+		count, timeout := a.tune(time.Second, 2*time.Second, 100)
+		assert.Equal(t, uint32(100), count)
+		assert.Equal(t, 200*time.Millisecond, timeout)
+	})
+
+	t.Run("burst", func(t *testing.T) {
+		a := &arrivalEstimator{}
+		arrive(a, 0, 10)
+
+		count, timeout := a.tune(time.Second, 2*time.Second, 100)
+		assert.Equal(t, uint32(100), count)
+		assert.Equal(t, time.Second, timeout)
+	})
+
+	t.Run("load drops", func(t *testing.T) {
+		a := &arrivalEstimator{}
+		arrive(a, time.Millisecond, 10)
+		arrive(a, 10*time.Second, 2)
+		assert.True(t, a.perSecond() < 1)
+
+		count, _ := a.tune(time.Second, 2*time.Second, 100)
+		assert.Equal(t, uint32(1), count)
+	})
+}
@@ -7,11 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package blockcutter
 
 import (
+	"math"
 	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 )
 
 var logger = flogging.MustGetLogger("orderer.common.blockcutter")
@@ -36,6 +38,10 @@ type receiver struct {
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
 
+	// arrivals and batchTimeout are only used by adaptive block cutting
+	arrivals     arrivalEstimator
+	batchTimeout time.Duration
+
 	PendingBatchStartTime time.Time
 	ChannelID             string
 	Metrics               *Metrics
@@ -57,7 +63,8 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 // messageBatches length: 0, pending: true
 //   - no batch is cut and there are messages pending
 // messageBatches length: 1, pending: false
-//   - the message count reaches BatchSize.MaxMessageCount
+//   - the message count reaches BatchSize.MaxMessageCount, or the max message count
+//     chosen by adaptive block cutting
 // messageBatches length: 1, pending: true
 //   - the current message will cause the pending batch size in bytes to exceed BatchSize.PreferredMaxBytes.
 // messageBatches length: 2, pending: false
@@ -78,6 +85,10 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	}
 
 	batchSize := ordererConfig.BatchSize()
+	maxMessageCount := batchSize.MaxMessageCount
+	if ordererConfig.BatchCuttingMode() == ab.BatchCutting_ADAPTIVE {
+		maxMessageCount = r.tune(ordererConfig)
+	}
 
 	messageSizeBytes := messageSizeBytes(msg)
 	if messageSizeBytes > batchSize.PreferredMaxBytes {
@@ -113,7 +124,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	r.pendingBatchSizeBytes += messageSizeBytes
 	pending = true
 
-	if uint32(len(r.pendingBatch)) >= batchSize.MaxMessageCount {
+	if uint32(len(r.pendingBatch)) >= maxMessageCount {
 		logger.Debugf("Batch size met, cutting batch")
 		messageBatch := r.Cut()
 		messageBatches = append(messageBatches, messageBatch)
@@ -133,6 +144,38 @@ func (r *receiver) Cut() []*cb.Envelope {
 	return batch
 }
 
+// BatchTimeout returns the time to wait before cutting the pending batch, which
+// is the batch timeout of the channel unless blocks are cut adaptively.
+func (r *receiver) BatchTimeout() time.Duration {
+	ordererConfig, ok := r.sharedConfigFetcher.OrdererConfig()
+	if !ok {
+		logger.Panicf("Could not retrieve orderer config to query batch parameters, block cutting is not possible")
+	}
+
+	if ordererConfig.BatchCuttingMode() != ab.BatchCutting_ADAPTIVE || r.batchTimeout == 0 {
+		return ordererConfig.BatchTimeout()
+	}
+	return r.batchTimeout
+}
+
+// tune records the arrival of a message, and returns the max message count
+// of the pending batch at the estimated arrival rate of messages.
+func (r *receiver) tune(ordererConfig channelconfig.Orderer) uint32 {
+	r.arrivals.observe(time.Now())
+
+	var maxMessageCount uint32
+	maxMessageCount, r.batchTimeout = r.arrivals.tune(ordererConfig.TargetLatency(), ordererConfig.BatchTimeout(), ordererConfig.BatchSize().MaxMessageCount)
+	logger.Debugf("Tuned the max message count to %d and the batch timeout to %s", maxMessageCount, r.batchTimeout)
+
+	if rate := r.arrivals.perSecond(); !math.IsInf(rate, 1) {
+		r.Metrics.ArrivalRate.With("channel", r.ChannelID).Set(rate)
+	}
+	r.Metrics.AdaptiveMaxMessageCount.With("channel", r.ChannelID).Set(float64(maxMessageCount))
+	r.Metrics.AdaptiveBatchTimeout.With("channel", r.ChannelID).Set(r.batchTimeout.Seconds())
+
+	return maxMessageCount
+}
+
 func messageSizeBytes(message *cb.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
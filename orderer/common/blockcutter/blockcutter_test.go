@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package blockcutter_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -22,8 +24,11 @@ var _ = Describe("Blockcutter", func() {
 		fakeConfig        *mock.OrdererConfig
 		fakeConfigFetcher *mock.OrdererConfigFetcher
 
-		metrics               *blockcutter.Metrics
-		fakeBlockFillDuration *mock.MetricsHistogram
+		metrics                     *blockcutter.Metrics
+		fakeBlockFillDuration       *mock.MetricsHistogram
+		fakeArrivalRate             *mock.MetricsGauge
+		fakeAdaptiveMaxMessageCount *mock.MetricsGauge
+		fakeAdaptiveBatchTimeout    *mock.MetricsGauge
 	)
 
 	BeforeEach(func() {
@@ -33,8 +38,17 @@ var _ = Describe("Blockcutter", func() {
 
 		fakeBlockFillDuration = &mock.MetricsHistogram{}
 		fakeBlockFillDuration.WithReturns(fakeBlockFillDuration)
+		fakeArrivalRate = &mock.MetricsGauge{}
+		fakeArrivalRate.WithReturns(fakeArrivalRate)
+		fakeAdaptiveMaxMessageCount = &mock.MetricsGauge{}
+		fakeAdaptiveMaxMessageCount.WithReturns(fakeAdaptiveMaxMessageCount)
+		fakeAdaptiveBatchTimeout = &mock.MetricsGauge{}
+		fakeAdaptiveBatchTimeout.WithReturns(fakeAdaptiveBatchTimeout)
 		metrics = &blockcutter.Metrics{
-			BlockFillDuration: fakeBlockFillDuration,
+			BlockFillDuration:       fakeBlockFillDuration,
+			ArrivalRate:             fakeArrivalRate,
+			AdaptiveMaxMessageCount: fakeAdaptiveMaxMessageCount,
+			AdaptiveBatchTimeout:    fakeAdaptiveBatchTimeout,
 		}
 
 		bc = blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, metrics)
@@ -173,6 +187,51 @@ var _ = Describe("Blockcutter", func() {
 			})
 		})
 
+		Context("when blocks are cut adaptively", func() {
+			BeforeEach(func() {
+				fakeConfig.BatchCuttingModeReturns(ab.BatchCutting_ADAPTIVE)
+				fakeConfig.TargetLatencyReturns(time.Second)
+				fakeConfig.BatchTimeoutReturns(2 * time.Second)
+			})
+
+			It("cuts the first message right away", func() {
+				batches, pending := bc.Ordered(message)
+				Expect(len(batches)).To(Equal(1))
+				Expect(len(batches[0])).To(Equal(1))
+				Expect(pending).To(BeFalse())
+
+				Expect(fakeArrivalRate.SetCallCount()).To(Equal(1))
+				Expect(fakeArrivalRate.SetArgsForCall(0)).To(Equal(float64(0)))
+				Expect(fakeAdaptiveMaxMessageCount.SetCallCount()).To(Equal(1))
+				Expect(fakeAdaptiveMaxMessageCount.SetArgsForCall(0)).To(Equal(float64(1)))
+				Expect(fakeAdaptiveMaxMessageCount.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel"}))
+				Expect(fakeAdaptiveBatchTimeout.SetCallCount()).To(Equal(1))
+				Expect(fakeAdaptiveBatchTimeout.SetArgsForCall(0)).To(Equal(float64(1)))
+				Expect(fakeAdaptiveBatchTimeout.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel"}))
+			})
+
+			It("batches messages which arrive within the target latency", func() {
+				batches, pending := bc.Ordered(message)
+				Expect(len(batches)).To(Equal(1))
+				Expect(pending).To(BeFalse())
+
+				batches, pending = bc.Ordered(message)
+				Expect(batches).To(BeEmpty())
+				Expect(pending).To(BeTrue())
+				Expect(fakeAdaptiveMaxMessageCount.SetArgsForCall(1)).To(Equal(float64(2)))
+
+				batchTimeout := blockcutter.BatchTimeout(bc, fakeConfig)
+				Expect(batchTimeout).To(BeNumerically(">", 0))
+				Expect(batchTimeout).To(BeNumerically("<", time.Second))
+				Expect(fakeAdaptiveBatchTimeout.SetArgsForCall(1)).To(Equal(batchTimeout.Seconds()))
+
+				batches, pending = bc.Ordered(message)
+				Expect(len(batches)).To(Equal(1))
+				Expect(len(batches[0])).To(Equal(2))
+				Expect(pending).To(BeFalse())
+			})
+		})
+
 		Context("when the orderer config cannot be retrieved", func() {
 			BeforeEach(func() {
 				fakeConfigFetcher.OrdererConfigReturns(nil, false)
@@ -183,4 +242,21 @@ var _ = Describe("Blockcutter", func() {
 			})
 		})
 	})
+
+	Describe("BatchTimeout", func() {
+		BeforeEach(func() {
+			fakeConfig.BatchTimeoutReturns(2 * time.Second)
+		})
+
+		It("returns the batch timeout of the channel", func() {
+			Expect(blockcutter.BatchTimeout(bc, fakeConfig)).To(Equal(2 * time.Second))
+			Expect(fakeAdaptiveBatchTimeout.SetCallCount()).To(Equal(0))
+		})
+
+		Context("when the receiver does not tune the batch timeout", func() {
+			It("returns the batch timeout of the channel", func() {
+				Expect(blockcutter.BatchTimeout(struct{ blockcutter.Receiver }{bc}, fakeConfig)).To(Equal(2 * time.Second))
+			})
+		})
+	})
 })
@@ -16,14 +16,41 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+	arrivalRate = metrics.GaugeOpts{
+		Namespace:    "blockcutter",
+		Name:         "arrival_rate",
+		Help:         "The estimated number of transactions per second used by adaptive block cutting.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	adaptiveMaxMessageCount = metrics.GaugeOpts{
+		Namespace:    "blockcutter",
+		Name:         "adaptive_max_message_count",
+		Help:         "The max message count chosen by adaptive block cutting.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	adaptiveBatchTimeout = metrics.GaugeOpts{
+		Namespace:    "blockcutter",
+		Name:         "adaptive_batch_timeout",
+		Help:         "The batch timeout in seconds chosen by adaptive block cutting.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
 
 type Metrics struct {
-	BlockFillDuration metrics.Histogram
+	BlockFillDuration       metrics.Histogram
+	ArrivalRate             metrics.Gauge
+	AdaptiveMaxMessageCount metrics.Gauge
+	AdaptiveBatchTimeout    metrics.Gauge
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
 	return &Metrics{
-		BlockFillDuration: p.NewHistogram(blockFillDuration),
+		BlockFillDuration:       p.NewHistogram(blockFillDuration),
+		ArrivalRate:             p.NewGauge(arrivalRate),
+		AdaptiveMaxMessageCount: p.NewGauge(adaptiveMaxMessageCount),
+		AdaptiveBatchTimeout:    p.NewGauge(adaptiveBatchTimeout),
 	}
 }
@@ -10,6 +10,16 @@ import (
 )
 
 type OrdererConfig struct {
+	BatchCuttingModeStub        func() orderer.BatchCutting_Mode
+	batchCuttingModeMutex       sync.RWMutex
+	batchCuttingModeArgsForCall []struct {
+	}
+	batchCuttingModeReturns struct {
+		result1 orderer.BatchCutting_Mode
+	}
+	batchCuttingModeReturnsOnCall map[int]struct {
+		result1 orderer.BatchCutting_Mode
+	}
 	BatchSizeStub        func() *orderer.BatchSize
 	batchSizeMutex       sync.RWMutex
 	batchSizeArgsForCall []struct {
@@ -100,10 +110,72 @@ type OrdererConfig struct {
 	organizationsReturnsOnCall map[int]struct {
 		result1 map[string]channelconfig.OrdererOrg
 	}
+	TargetLatencyStub        func() time.Duration
+	targetLatencyMutex       sync.RWMutex
+	targetLatencyArgsForCall []struct {
+	}
+	targetLatencyReturns struct {
+		result1 time.Duration
+	}
+	targetLatencyReturnsOnCall map[int]struct {
+		result1 time.Duration
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *OrdererConfig) BatchCuttingMode() orderer.BatchCutting_Mode {
+	fake.batchCuttingModeMutex.Lock()
+	ret, specificReturn := fake.batchCuttingModeReturnsOnCall[len(fake.batchCuttingModeArgsForCall)]
+	fake.batchCuttingModeArgsForCall = append(fake.batchCuttingModeArgsForCall, struct {
+	}{})
+	fake.recordInvocation("BatchCuttingMode", []interface{}{})
+	fake.batchCuttingModeMutex.Unlock()
+	if fake.BatchCuttingModeStub != nil {
+		return fake.BatchCuttingModeStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.batchCuttingModeReturns
+	return fakeReturns.result1
+}
+
+func (fake *OrdererConfig) BatchCuttingModeCallCount() int {
+	fake.batchCuttingModeMutex.RLock()
+	defer fake.batchCuttingModeMutex.RUnlock()
+	return len(fake.batchCuttingModeArgsForCall)
+}
+
+func (fake *OrdererConfig) BatchCuttingModeCalls(stub func() orderer.BatchCutting_Mode) {
+	fake.batchCuttingModeMutex.Lock()
+	defer fake.batchCuttingModeMutex.Unlock()
+	fake.BatchCuttingModeStub = stub
+}
+
+func (fake *OrdererConfig) BatchCuttingModeReturns(result1 orderer.BatchCutting_Mode) {
+	fake.batchCuttingModeMutex.Lock()
+	defer fake.batchCuttingModeMutex.Unlock()
+	fake.BatchCuttingModeStub = nil
+	fake.batchCuttingModeReturns = struct {
+		result1 orderer.BatchCutting_Mode
+	}{result1}
+}
+
+func (fake *OrdererConfig) BatchCuttingModeReturnsOnCall(i int, result1 orderer.BatchCutting_Mode) {
+	fake.batchCuttingModeMutex.Lock()
+	defer fake.batchCuttingModeMutex.Unlock()
+	fake.BatchCuttingModeStub = nil
+	if fake.batchCuttingModeReturnsOnCall == nil {
+		fake.batchCuttingModeReturnsOnCall = make(map[int]struct {
+			result1 orderer.BatchCutting_Mode
+		})
+	}
+	fake.batchCuttingModeReturnsOnCall[i] = struct {
+		result1 orderer.BatchCutting_Mode
+	}{result1}
+}
+
 func (fake *OrdererConfig) BatchSize() *orderer.BatchSize {
 	fake.batchSizeMutex.Lock()
 	ret, specificReturn := fake.batchSizeReturnsOnCall[len(fake.batchSizeArgsForCall)]
@@ -572,9 +644,63 @@ func (fake *OrdererConfig) OrganizationsReturnsOnCall(i int, result1 map[string]
 	}{result1}
 }
 
+func (fake *OrdererConfig) TargetLatency() time.Duration {
+	fake.targetLatencyMutex.Lock()
+	ret, specificReturn := fake.targetLatencyReturnsOnCall[len(fake.targetLatencyArgsForCall)]
+	fake.targetLatencyArgsForCall = append(fake.targetLatencyArgsForCall, struct {
+	}{})
+	fake.recordInvocation("TargetLatency", []interface{}{})
+	fake.targetLatencyMutex.Unlock()
+	if fake.TargetLatencyStub != nil {
+		return fake.TargetLatencyStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.targetLatencyReturns
+	return fakeReturns.result1
+}
+
+func (fake *OrdererConfig) TargetLatencyCallCount() int {
+	fake.targetLatencyMutex.RLock()
+	defer fake.targetLatencyMutex.RUnlock()
+	return len(fake.targetLatencyArgsForCall)
+}
+
+func (fake *OrdererConfig) TargetLatencyCalls(stub func() time.Duration) {
+	fake.targetLatencyMutex.Lock()
+	defer fake.targetLatencyMutex.Unlock()
+	fake.TargetLatencyStub = stub
+}
+
+func (fake *OrdererConfig) TargetLatencyReturns(result1 time.Duration) {
+	fake.targetLatencyMutex.Lock()
+	defer fake.targetLatencyMutex.Unlock()
+	fake.TargetLatencyStub = nil
+	fake.targetLatencyReturns = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *OrdererConfig) TargetLatencyReturnsOnCall(i int, result1 time.Duration) {
+	fake.targetLatencyMutex.Lock()
+	defer fake.targetLatencyMutex.Unlock()
+	fake.TargetLatencyStub = nil
+	if fake.targetLatencyReturnsOnCall == nil {
+		fake.targetLatencyReturnsOnCall = make(map[int]struct {
+			result1 time.Duration
+		})
+	}
+	fake.targetLatencyReturnsOnCall[i] = struct {
+		result1 time.Duration
+	}{result1}
+}
+
 func (fake *OrdererConfig) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.batchCuttingModeMutex.RLock()
+	defer fake.batchCuttingModeMutex.RUnlock()
 	fake.batchSizeMutex.RLock()
 	defer fake.batchSizeMutex.RUnlock()
 	fake.batchTimeoutMutex.RLock()
@@ -593,6 +719,8 @@ func (fake *OrdererConfig) Invocations() map[string][][]interface{} {
 	defer fake.maxChannelsCountMutex.RUnlock()
 	fake.organizationsMutex.RLock()
 	defer fake.organizationsMutex.RUnlock()
+	fake.targetLatencyMutex.RLock()
+	defer fake.targetLatencyMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -7,14 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package broadcast
 
 import (
+	"context"
 	"io"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 )
 
@@ -60,6 +64,9 @@ type Consenter interface {
 type Handler struct {
 	SupportRegistrar ChannelSupportRegistrar
 	Metrics          *Metrics
+	// FairQueue, if set, queues the messages of each channel
+	// before they are passed to the consenter.
+	FairQueue *FairQueue
 }
 
 // Handle reads requests from a Broadcast stream, processes them, and returns the responses to the stream
@@ -77,7 +84,7 @@ func (bh *Handler) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			return err
 		}
 
-		resp := bh.ProcessMessage(msg, addr)
+		resp := bh.processMessage(srv.Context(), msg, addr)
 		err = srv.Send(resp)
 		if resp.Status != cb.Status_SUCCESS {
 			return err
@@ -134,6 +141,12 @@ func (mt *MetricsTracker) BeginEnqueue() {
 
 // ProcessMessage validates and enqueues a single message
 func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.BroadcastResponse) {
+	return bh.processMessage(context.Background(), msg, addr)
+}
+
+// processMessage validates and enqueues a single message, and gives up waiting
+// for its turn in the fair queue, if any, when the context is done.
+func (bh *Handler) processMessage(ctx context.Context, msg *cb.Envelope, addr string) (resp *ab.BroadcastResponse) {
 	tracker := &MetricsTracker{
 		ChannelID: "unknown",
 		TxType:    "unknown",
@@ -173,7 +186,15 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
 		}
 
-		err = processor.Order(msg, configSeq)
+		err = bh.submit(ctx, chdr.ChannelId, msg, false, func() error { return processor.Order(msg, configSeq) })
+		if errors.Cause(err) == ErrQuotaExceeded {
+			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with TOO_MANY_REQUESTS: %s", chdr.ChannelId, addr, err)
+			return &ab.BroadcastResponse{Status: cb.Status_TOO_MANY_REQUESTS, Info: err.Error()}
+		}
+		if errors.Cause(err) == msgprocessor.ErrDuplicateTxID {
+			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with BAD_REQUEST: %s", chdr.ChannelId, addr, err)
+			return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+		}
 		if err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with SERVICE_UNAVAILABLE: rejected by Order: %s", chdr.ChannelId, addr, err)
 			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
@@ -194,7 +215,7 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
 		}
 
-		err = processor.Configure(config, configSeq)
+		err = bh.submit(ctx, chdr.ChannelId, msg, true, func() error { return processor.Configure(config, configSeq) })
 		if err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s with SERVICE_UNAVAILABLE: rejected by Configure: %s", chdr.ChannelId, addr, err)
 			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
@@ -206,6 +227,32 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 	return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
 }
 
+// submit passes a message to the consenter through the fair queue, if any.
+func (bh *Handler) submit(ctx context.Context, channelID string, msg *cb.Envelope, isConfig bool, submit func() error) error {
+	if bh.FairQueue == nil {
+		return submit()
+	}
+	return bh.FairQueue.Submit(ctx, channelID, creatorMSPID(msg), isConfig, submit)
+}
+
+// creatorMSPID returns the MSP ID of the creator of a message, or an empty
+// string if the message does not carry one.
+func creatorMSPID(msg *cb.Envelope) string {
+	payload, err := utils.UnmarshalPayload(msg.Payload)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return ""
+	}
+	sid := &mspproto.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, sid); err != nil {
+		return ""
+	}
+	return sid.Mspid
+}
+
 // ClassifyError converts an error type into a status code.
 func ClassifyError(err error) cb.Status {
 	switch errors.Cause(err) {
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	. "github.com/onsi/ginkgo"
@@ -19,7 +20,10 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/broadcast/mock"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 )
 
 var _ = Describe("Broadcast", func() {
@@ -225,6 +229,69 @@ var _ = Describe("Broadcast", func() {
 			})
 		})
 
+		Context("when the transaction is already being ordered", func() {
+			BeforeEach(func() {
+				fakeSupport.OrderReturns(errors.WithMessage(msgprocessor.ErrDuplicateTxID, "transaction tx1 is being ordered"))
+			})
+
+			It("rejects the message with BAD_REQUEST", func() {
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeABServer.SendCallCount()).To(Equal(1))
+				Expect(proto.Equal(
+					fakeABServer.SendArgsForCall(0),
+					&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: "transaction tx1 is being ordered: duplicate transaction ID"}),
+				).To(BeTrue())
+			})
+		})
+
+		Context("when the messages are fair queued", func() {
+			BeforeEach(func() {
+				fakeMsg = &cb.Envelope{
+					Payload: utils.MarshalOrPanic(&cb.Payload{
+						Header: &cb.Header{
+							SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{
+								Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP"}),
+							}),
+						},
+					}),
+				}
+				fakeABServer.RecvReturnsOnCall(0, fakeMsg, nil)
+				fakeABServer.RecvReturnsOnCall(1, fakeMsg, nil)
+				fakeABServer.RecvReturnsOnCall(2, nil, io.EOF)
+
+				now := time.Now()
+				handler.FairQueue = &broadcast.FairQueue{
+					Default:       broadcast.Quota{Rate: 1},
+					Organizations: map[string]broadcast.Quota{"Org2MSP": {Rate: 10}},
+					Now:           func() time.Time { return now },
+				}
+			})
+
+			It("rejects the messages which exceed the quota of the organization", func() {
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.OrderCallCount()).To(Equal(1))
+				Expect(fakeABServer.SendCallCount()).To(Equal(2))
+				Expect(proto.Equal(fakeABServer.SendArgsForCall(0), &ab.BroadcastResponse{Status: cb.Status_SUCCESS})).To(BeTrue())
+				Expect(proto.Equal(
+					fakeABServer.SendArgsForCall(1),
+					&ab.BroadcastResponse{
+						Status: cb.Status_TOO_MANY_REQUESTS,
+						Info:   "organization Org1MSP exceeds its rate of 1 requests per second: quota exceeded",
+					}),
+				).To(BeTrue())
+
+				Expect(fakeProcessedCounter.WithArgsForCall(1)).To(Equal([]string{
+					"status", "TOO_MANY_REQUESTS",
+					"channel", "fake-channel",
+					"type", "ENDORSER_TRANSACTION",
+				}))
+			})
+		})
+
 		Context("when the message processor returns an error", func() {
 			BeforeEach(func() {
 				fakeSupport.ProcessNormalMsgReturns(0, fmt.Errorf("normal-messsage-processing-error"))
@@ -0,0 +1,281 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrQuotaExceeded is returned when an organization exceeds the quota of
+// requests it may submit to a channel.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// pruneInterval is the minimal interval between two removals of the queues of
+// the organizations and of the channels which have gone idle.
+const pruneInterval = time.Minute
+
+// Quota limits the requests an organization may submit to a channel.
+type Quota struct {
+	// QueueSize is the number of requests of the organization which may wait
+	// to be submitted to the consenter of a channel.
+	QueueSize int
+	// Rate is the number of requests per second the organization may submit
+	// to a channel, or 0 if it is not limited.
+	Rate float64
+	// Burst is the number of requests the organization may submit to a channel
+	// at once on top of its rate.
+	Burst int
+}
+
+// FairQueue submits the requests of a channel to its consenter one at a time,
+// in a round robin over the organizations of the creators of the requests, so
+// that an organization which floods a channel does not starve the others.
+// Config updates are submitted before any other request, and are not subject
+// to the quotas of the organizations.
+type FairQueue struct {
+	// Default is the quota of the organizations which have no quota of their own.
+	Default Quota
+	// Organizations are the quotas of the organizations, by MSP ID.
+	Organizations map[string]Quota
+
+	// Now returns the current time, and defaults to time.Now.
+	Now func() time.Time
+
+	mutex    sync.Mutex
+	channels map[string]*channelQueue
+	pruned   time.Time
+}
+
+type channelQueue struct {
+	busy     bool
+	config   []chan struct{}
+	orgs     map[string]*orgQueue
+	schedule []string // organizations with pending requests, in round robin order
+}
+
+type orgQueue struct {
+	pending []chan struct{}
+	tokens  tokenBucket
+}
+
+// Submit waits for the turn of the request in the queue of the channel and
+// then calls submit. It returns an error wrapping ErrQuotaExceeded without
+// calling submit if the organization exceeds its quota, and an error without
+// calling submit if the context is done before the turn of the request.
+func (fq *FairQueue) Submit(ctx context.Context, channelID, org string, isConfig bool, submit func() error) error {
+	turn := make(chan struct{})
+
+	fq.mutex.Lock()
+	now := fq.now()
+	fq.prune(now)
+	cq := fq.channel(channelID)
+	if isConfig {
+		cq.config = append(cq.config, turn)
+	} else if err := fq.enqueue(cq, org, turn, now); err != nil {
+		fq.mutex.Unlock()
+		return err
+	}
+	if !cq.busy {
+		cq.next()
+	}
+	fq.mutex.Unlock()
+
+	select {
+	case <-turn:
+	case <-ctx.Done():
+		fq.mutex.Lock()
+		defer fq.mutex.Unlock()
+		select {
+		case <-turn:
+			// The turn was handed to the request as it was canceled.
+			cq.busy = false
+			cq.next()
+		default:
+			cq.remove(org, isConfig, turn)
+		}
+		return errors.Wrap(ctx.Err(), "request canceled while waiting for its turn")
+	}
+	defer fq.done(cq)
+
+	return submit()
+}
+
+func (fq *FairQueue) now() time.Time {
+	if fq.Now != nil {
+		return fq.Now()
+	}
+	return time.Now()
+}
+
+func (fq *FairQueue) quota(org string) Quota {
+	quota, exists := fq.Organizations[org]
+	if !exists {
+		return fq.Default
+	}
+	return quota
+}
+
+// prune removes the queues of the organizations which have no pending requests
+// and whose rate is not limited anymore, and the queues of the channels which
+// are left idle, at most once per pruneInterval.
+func (fq *FairQueue) prune(now time.Time) {
+	if now.Sub(fq.pruned) < pruneInterval {
+		return
+	}
+	fq.pruned = now
+
+	for channelID, cq := range fq.channels {
+		for org, oq := range cq.orgs {
+			quota := fq.quota(org)
+			if len(oq.pending) == 0 && oq.tokens.full(now, quota.Rate, quota.Burst) {
+				delete(cq.orgs, org)
+			}
+		}
+		if !cq.busy && len(cq.config) == 0 && len(cq.orgs) == 0 {
+			delete(fq.channels, channelID)
+		}
+	}
+}
+
+func (fq *FairQueue) channel(channelID string) *channelQueue {
+	if fq.channels == nil {
+		fq.channels = map[string]*channelQueue{}
+	}
+	cq, exists := fq.channels[channelID]
+	if !exists {
+		cq = &channelQueue{orgs: map[string]*orgQueue{}}
+		fq.channels[channelID] = cq
+	}
+	return cq
+}
+
+func (fq *FairQueue) enqueue(cq *channelQueue, org string, turn chan struct{}, now time.Time) error {
+	quota := fq.quota(org)
+
+	oq, exists := cq.orgs[org]
+	if !exists {
+		oq = &orgQueue{}
+		cq.orgs[org] = oq
+	}
+
+	if quota.QueueSize > 0 && len(oq.pending) >= quota.QueueSize {
+		return errors.WithMessage(ErrQuotaExceeded, fmt.Sprintf("organization %s has %d pending requests", org, len(oq.pending)))
+	}
+
+	if !oq.tokens.take(now, quota.Rate, quota.Burst) {
+		return errors.WithMessage(ErrQuotaExceeded, fmt.Sprintf("organization %s exceeds its rate of %g requests per second", org, quota.Rate))
+	}
+
+	if len(oq.pending) == 0 {
+		cq.schedule = append(cq.schedule, org)
+	}
+	oq.pending = append(oq.pending, turn)
+	return nil
+}
+
+func (fq *FairQueue) done(cq *channelQueue) {
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+	cq.busy = false
+	cq.next()
+}
+
+// next hands the turn to the next pending request of the channel, if any.
+func (cq *channelQueue) next() {
+	var turn chan struct{}
+	switch {
+	case len(cq.config) > 0:
+		turn, cq.config = cq.config[0], cq.config[1:]
+	case len(cq.schedule) > 0:
+		org := cq.schedule[0]
+		cq.schedule = cq.schedule[1:]
+		oq := cq.orgs[org]
+		turn, oq.pending = oq.pending[0], oq.pending[1:]
+		if len(oq.pending) > 0 {
+			cq.schedule = append(cq.schedule, org)
+		}
+	default:
+		return
+	}
+	cq.busy = true
+	close(turn)
+}
+
+// remove removes a pending request of the channel which
+// was canceled before its turn.
+func (cq *channelQueue) remove(org string, isConfig bool, turn chan struct{}) {
+	if isConfig {
+		cq.config = removeTurn(cq.config, turn)
+		return
+	}
+
+	oq := cq.orgs[org]
+	oq.pending = removeTurn(oq.pending, turn)
+	if len(oq.pending) == 0 {
+		for i, scheduled := range cq.schedule {
+			if scheduled == org {
+				cq.schedule = append(cq.schedule[:i], cq.schedule[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func removeTurn(turns []chan struct{}, turn chan struct{}) []chan struct{} {
+	for i, t := range turns {
+		if t == turn {
+			return append(turns[:i], turns[i+1:]...)
+		}
+	}
+	return turns
+}
+
+// tokenBucket limits the rate of requests, allowing bursts of requests
+// up to the size of the bucket.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take takes a token from the bucket, which is refilled at the given rate up
+// to burst+1 tokens, and returns false if the bucket is empty.
+func (tb *tokenBucket) take(now time.Time, rate float64, burst int) bool {
+	if rate <= 0 {
+		return true
+	}
+
+	size := float64(burst + 1)
+	if tb.last.IsZero() {
+		tb.tokens = size
+	} else if elapsed := now.Sub(tb.last).Seconds(); elapsed > 0 {
+		tb.tokens += elapsed * rate
+		if tb.tokens > size {
+			tb.tokens = size
+		}
+	}
+	tb.last = now
+
+	if tb.tokens < 1 {
+		return false
+	}
+	tb.tokens--
+	return true
+}
+
+// full returns true if the bucket has been refilled up to burst+1 tokens, in
+// which case it is equivalent to a new bucket.
+func (tb *tokenBucket) full(now time.Time, rate float64, burst int) bool {
+	if rate <= 0 || tb.last.IsZero() {
+		return true
+	}
+	return tb.tokens+now.Sub(tb.last).Seconds()*rate >= float64(burst+1)
+}
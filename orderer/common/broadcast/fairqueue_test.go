@@ -0,0 +1,250 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func (fq *FairQueue) pending(channelID string) int {
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+	cq, exists := fq.channels[channelID]
+	if !exists {
+		return 0
+	}
+	n := len(cq.config)
+	for _, oq := range cq.orgs {
+		n += len(oq.pending)
+	}
+	return n
+}
+
+func TestFairQueueOrder(t *testing.T) {
+	fq := &FairQueue{}
+
+	var (
+		lock   sync.Mutex
+		served []string
+		wg     sync.WaitGroup
+	)
+	submitted := func(name string) func() error {
+		return func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			served = append(served, name)
+			return nil
+		}
+	}
+	submit := func(org string, isConfig bool, name string) {
+		wg.Add(1)
+		pending := fq.pending("mychannel")
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, fq.Submit(context.Background(), "mychannel", org, isConfig, submitted(name)))
+		}()
+		for fq.pending("mychannel") == pending {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Hold the turn of the channel until the other requests are queued.
+	release := make(chan struct{})
+	holding := make(chan struct{})
+	go fq.Submit(context.Background(), "mychannel", "org1", false, func() error {
+		close(holding)
+		<-release
+		return nil
+	})
+	<-holding
+
+	submit("org1", false, "org1-a")
+	submit("org1", false, "org1-b")
+	submit("org1", false, "org1-c")
+	submit("org2", false, "org2-a")
+	submit("org3", false, "org3-a")
+	submit("org2", false, "org2-b")
+	submit("org1", true, "config")
+
+	// Other channels are not held by the channel.
+	assert.NoError(t, fq.Submit(context.Background(), "otherchannel", "org1", false, func() error { return nil }))
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"config", "org1-a", "org2-a", "org3-a", "org1-b", "org2-b", "org1-c"}, served)
+}
+
+func TestFairQueueSize(t *testing.T) {
+	fq := &FairQueue{
+		Default:       Quota{QueueSize: 1},
+		Organizations: map[string]Quota{"org2": {QueueSize: 2}},
+	}
+
+	release := make(chan struct{})
+	holding := make(chan struct{})
+	go fq.Submit(context.Background(), "mychannel", "org1", false, func() error {
+		close(holding)
+		<-release
+		return nil
+	})
+	<-holding
+
+	var wg sync.WaitGroup
+	for _, org := range []string{"org1", "org2", "org2"} {
+		wg.Add(1)
+		pending := fq.pending("mychannel")
+		go func(org string) {
+			defer wg.Done()
+			assert.NoError(t, fq.Submit(context.Background(), "mychannel", org, false, func() error { return nil }))
+		}(org)
+		for fq.pending("mychannel") == pending {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	err := fq.Submit(context.Background(), "mychannel", "org1", false, func() error { return nil })
+	assert.EqualError(t, err, "organization org1 has 1 pending requests: quota exceeded")
+	assert.Equal(t, ErrQuotaExceeded, errors.Cause(err))
+	err = fq.Submit(context.Background(), "mychannel", "org2", false, func() error { return nil })
+	assert.EqualError(t, err, "organization org2 has 2 pending requests: quota exceeded")
+
+	// Config updates are not subject to the quotas.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", true, func() error { return nil }))
+	}()
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 0, fq.pending("mychannel"))
+}
+
+func TestFairQueueRate(t *testing.T) {
+	now := time.Now()
+	fq := &FairQueue{
+		Default:       Quota{Rate: 2, Burst: 1},
+		Organizations: map[string]Quota{"org2": {}},
+		Now:           func() time.Time { return now },
+	}
+	ok := func() error { return nil }
+
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+	err := fq.Submit(context.Background(), "mychannel", "org1", false, ok)
+	assert.EqualError(t, err, "organization org1 exceeds its rate of 2 requests per second: quota exceeded")
+
+	// The quotas are per channel and per organization.
+	assert.NoError(t, fq.Submit(context.Background(), "otherchannel", "org1", false, ok))
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org3", false, ok))
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org2", false, ok))
+	}
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", true, ok))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+	assert.Error(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+
+	now = now.Add(10 * time.Second)
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+	assert.Error(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+}
+
+func TestFairQueueSubmitError(t *testing.T) {
+	fq := &FairQueue{}
+	err := fq.Submit(context.Background(), "mychannel", "org1", false, func() error { return errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, func() error { return nil }))
+}
+
+func TestFairQueueCancel(t *testing.T) {
+	fq := &FairQueue{}
+
+	release := make(chan struct{})
+	holding := make(chan struct{})
+	go fq.Submit(context.Background(), "mychannel", "org1", false, func() error {
+		close(holding)
+		<-release
+		return nil
+	})
+	<-holding
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		canceled <- fq.Submit(ctx, "mychannel", "org2", false, func() error {
+			t.Error("a canceled request should not be submitted")
+			return nil
+		})
+	}()
+	for fq.pending("mychannel") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	served := make(chan struct{})
+	go func() {
+		assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org3", false, func() error {
+			close(served)
+			return nil
+		}))
+	}()
+	for fq.pending("mychannel") == 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	err := <-canceled
+	assert.EqualError(t, err, "request canceled while waiting for its turn: context canceled")
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+	assert.Equal(t, 1, fq.pending("mychannel"))
+
+	close(release)
+	<-served
+	fq.mutex.Lock()
+	assert.Empty(t, fq.channels["mychannel"].schedule)
+	fq.mutex.Unlock()
+}
+
+func TestFairQueuePrune(t *testing.T) {
+	now := time.Now()
+	fq := &FairQueue{
+		Default:       Quota{Rate: 1},
+		Organizations: map[string]Quota{"org2": {Rate: 0.001}},
+		Now:           func() time.Time { return now },
+	}
+	ok := func() error { return nil }
+
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org2", false, ok))
+	assert.NoError(t, fq.Submit(context.Background(), "otherchannel", "org1", false, ok))
+	assert.Len(t, fq.channels, 2)
+	assert.Len(t, fq.channels["mychannel"].orgs, 2)
+
+	// The queues of the organizations whose rate is no longer limited,
+	// and of the channels which are left without any, are removed.
+	now = now.Add(2 * pruneInterval)
+	assert.NoError(t, fq.Submit(context.Background(), "thirdchannel", "org1", false, ok))
+	assert.Len(t, fq.channels, 2)
+	assert.Contains(t, fq.channels, "thirdchannel")
+	assert.Len(t, fq.channels["mychannel"].orgs, 1)
+	assert.Contains(t, fq.channels["mychannel"].orgs, "org2")
+
+	// The queues are pruned at most once per interval.
+	now = now.Add(pruneInterval / 2)
+	assert.NoError(t, fq.Submit(context.Background(), "mychannel", "org1", false, ok))
+	assert.Len(t, fq.channels, 2)
+	assert.Len(t, fq.channels["mychannel"].orgs, 2)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// URLBaseV1 is the base path of the channel participation API.
+	URLBaseV1 = "/participation/v1/"
+	// URLBaseV1Channels is the path of the channels resource of the channel participation API.
+	URLBaseV1Channels = URLBaseV1 + "channels"
+	// FormDataConfigBlockKey is the key of the multipart form field that carries the config block
+	// in a request to join a channel.
+	FormDataConfigBlockKey = "config-block"
+
+	channelIDKey        = "channelID"
+	urlWithChannelIDKey = URLBaseV1Channels + "/{" + channelIDKey + "}"
+)
+
+//go:generate counterfeiter -o mocks/channel_management.go -fake-name ChannelManagement . ChannelManagement
+
+// ChannelManagement is the registrar of the channels of the orderer.
+type ChannelManagement interface {
+	// ChannelList returns the channels of the orderer.
+	ChannelList() types.ChannelList
+	// ChannelInfo returns the status and the height of the given channel.
+	ChannelInfo(channelID string) (types.ChannelInfo, error)
+	// JoinChannel creates the given channel from the given config block and starts it.
+	JoinChannel(channelID string, configBlock *cb.Block) (types.ChannelInfo, error)
+	// RemoveChannel halts the given channel and removes its ledger.
+	RemoveChannel(channelID string) error
+}
+
+// ErrorResponse carries the error of a failed request.
+// This is marshaled into the body of the HTTP response.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// HTTPHandler handles the requests of the channel participation API, which lists the channels
+// of the orderer, joins the orderer to a channel given a config block of the channel, and removes
+// a channel from the orderer.
+type HTTPHandler struct {
+	logger    *flogging.FabricLogger
+	config    localconfig.ChannelParticipation
+	registrar ChannelManagement
+	router    *mux.Router
+}
+
+// NewHTTPHandler constructs an HTTPHandler.
+func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelManagement) *HTTPHandler {
+	handler := &HTTPHandler{
+		logger:    flogging.MustGetLogger("orderer.common.channelparticipation"),
+		config:    config,
+		registrar: registrar,
+		router:    mux.NewRouter(),
+	}
+
+	handler.router.HandleFunc(URLBaseV1Channels, handler.serveListAll).Methods(http.MethodGet)
+	handler.router.HandleFunc(URLBaseV1Channels, handler.serveJoin).Methods(http.MethodPost)
+	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveListOne).Methods(http.MethodGet)
+	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveRemove).Methods(http.MethodDelete)
+	handler.router.MethodNotAllowedHandler = http.HandlerFunc(handler.serveNotAllowed)
+
+	return handler
+}
+
+func (h *HTTPHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if !h.config.Enabled {
+		h.sendResponseJSON(resp, http.StatusServiceUnavailable, errors.New("channel participation API is disabled"))
+		return
+	}
+	h.router.ServeHTTP(resp, req)
+}
+
+func (h *HTTPHandler) serveListAll(resp http.ResponseWriter, req *http.Request) {
+	list := h.registrar.ChannelList()
+	if list.SystemChannel != nil {
+		list.SystemChannel.URL = channelURL(list.SystemChannel.Name)
+	}
+	for i := range list.Channels {
+		list.Channels[i].URL = channelURL(list.Channels[i].Name)
+	}
+	h.sendResponseJSON(resp, http.StatusOK, list)
+}
+
+func (h *HTTPHandler) serveListOne(resp http.ResponseWriter, req *http.Request) {
+	channelID, err := channelIDFromRequest(req)
+	if err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, err)
+		return
+	}
+
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err != nil {
+		h.sendError(resp, err)
+		return
+	}
+	info.URL = channelURL(channelID)
+	h.sendResponseJSON(resp, http.StatusOK, info)
+}
+
+func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		h.sendResponseJSON(resp, http.StatusBadRequest, errors.New("unsupported Content-Type, expected multipart/form-data"))
+		return
+	}
+
+	req.Body = http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize))
+	if err := req.ParseMultipartForm(int64(h.config.MaxRequestBodySize)); err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, errors.Wrap(err, "cannot read the multipart form"))
+		return
+	}
+	file, _, err := req.FormFile(FormDataConfigBlockKey)
+	if err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, errors.Wrapf(err, "form field %s is missing", FormDataConfigBlockKey))
+		return
+	}
+	defer file.Close()
+
+	blockBytes, err := ioutil.ReadAll(file)
+	if err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot read form field %s", FormDataConfigBlockKey))
+		return
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, errors.Wrap(err, "cannot unmarshal the config block"))
+		return
+	}
+	channelID, err := utils.GetChainIDFromBlock(block)
+	if err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, errors.WithMessage(err, "cannot extract the channel ID from the config block"))
+		return
+	}
+	if err := configtx.ValidateChannelID(channelID); err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, err)
+		return
+	}
+
+	info, err := h.registrar.JoinChannel(channelID, block)
+	if err != nil {
+		h.logger.Warningf("Failed to join channel %s: %s", channelID, err)
+		h.sendError(resp, err)
+		return
+	}
+	info.URL = channelURL(channelID)
+	resp.Header().Set("Location", info.URL)
+	h.sendResponseJSON(resp, http.StatusCreated, info)
+}
+
+func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request) {
+	channelID, err := channelIDFromRequest(req)
+	if err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.registrar.RemoveChannel(channelID); err != nil {
+		h.logger.Warningf("Failed to remove channel %s: %s", channelID, err)
+		h.sendError(resp, err)
+		return
+	}
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) serveNotAllowed(resp http.ResponseWriter, req *http.Request) {
+	allowed := http.MethodGet + ", " + http.MethodDelete
+	if strings.TrimSuffix(req.URL.Path, "/") == URLBaseV1Channels {
+		allowed = http.MethodGet + ", " + http.MethodPost
+	}
+	resp.Header().Set("Allow", allowed)
+	h.sendResponseJSON(resp, http.StatusMethodNotAllowed, errors.Errorf("invalid request method: %s", req.Method))
+}
+
+// sendError maps the errors of the registrar to the status codes of the response.
+func (h *HTTPHandler) sendError(resp http.ResponseWriter, err error) {
+	switch errors.Cause(err) {
+	case types.ErrChannelNotExist:
+		h.sendResponseJSON(resp, http.StatusNotFound, err)
+	case types.ErrChannelAlreadyExists:
+		h.sendResponseJSON(resp, http.StatusConflict, err)
+	case types.ErrSystemChannelExists:
+		h.sendResponseJSON(resp, http.StatusMethodNotAllowed, err)
+	default:
+		h.sendResponseJSON(resp, http.StatusBadRequest, err)
+	}
+}
+
+func (h *HTTPHandler) sendResponseJSON(resp http.ResponseWriter, code int, payload interface{}) {
+	if err, ok := payload.(error); ok {
+		payload = &ErrorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		h.logger.Errorw("failed to encode payload", "error", err)
+	}
+}
+
+func channelIDFromRequest(req *http.Request) (string, error) {
+	channelID := mux.Vars(req)[channelIDKey]
+	if err := configtx.ValidateChannelID(channelID); err != nil {
+		return "", errors.WithMessage(err, "invalid channel ID")
+	}
+	return channelID, nil
+}
+
+func channelURL(channelID string) string {
+	return path.Join(URLBaseV1Channels, channelID)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var enabledConfig = localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}
+
+func TestHTTPHandlerDisabled(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{}, fakeManager)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.JSONEq(t, `{"error":"channel participation API is disabled"}`, resp.Body.String())
+	assert.Equal(t, 0, fakeManager.ChannelListCallCount())
+}
+
+func TestHTTPHandlerList(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	fakeManager.ChannelListReturns(types.ChannelList{
+		Channels: []types.ChannelInfoShort{{Name: "app1"}, {Name: "app2"}},
+	})
+	h := channelparticipation.NewHTTPHandler(enabledConfig, fakeManager)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"systemChannel":null,"channels":[`+
+		`{"name":"app1","url":"/participation/v1/channels/app1"},`+
+		`{"name":"app2","url":"/participation/v1/channels/app2"}]}`, resp.Body.String())
+
+	fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "app1", Status: types.StatusInactive, Height: 5}, nil)
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app1", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"name":"app1","url":"/participation/v1/channels/app1","status":"inactive","height":5}`, resp.Body.String())
+	assert.Equal(t, "app1", fakeManager.ChannelInfoArgsForCall(0))
+
+	fakeManager.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app3", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"error":"channel does not exist"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/App_1", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid channel ID: channel ID 'App_1' contains illegal characters"}`, resp.Body.String())
+	assert.Equal(t, 2, fakeManager.ChannelInfoCallCount())
+}
+
+func TestHTTPHandlerJoin(t *testing.T) {
+	conf := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+	conf.Consortiums = nil
+	blockBytes := utils.MarshalOrPanic(encoder.New(conf).GenesisBlockForChannel("app1"))
+
+	t.Run("Joined", func(t *testing.T) {
+		fakeManager := &mocks.ChannelManagement{}
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "app1", Status: types.StatusActive, Height: 1}, nil)
+		h := channelparticipation.NewHTTPHandler(enabledConfig, fakeManager)
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, newJoinRequest(t, channelparticipation.FormDataConfigBlockKey, blockBytes))
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, "/participation/v1/channels/app1", resp.Header().Get("Location"))
+		assert.JSONEq(t, `{"name":"app1","url":"/participation/v1/channels/app1","status":"active","height":1}`, resp.Body.String())
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+		channelID, block := fakeManager.JoinChannelArgsForCall(0)
+		assert.Equal(t, "app1", channelID)
+		assert.Equal(t, blockBytes, utils.MarshalOrPanic(block))
+	})
+
+	for _, testCase := range []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "channel exists", err: types.ErrChannelAlreadyExists, expectedCode: http.StatusConflict},
+		{name: "system channel exists", err: types.ErrSystemChannelExists, expectedCode: http.StatusMethodNotAllowed},
+		{name: "invalid block", err: errors.New("invalid config block: block is not a config block"), expectedCode: http.StatusBadRequest},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeManager := &mocks.ChannelManagement{}
+			fakeManager.JoinChannelReturns(types.ChannelInfo{}, testCase.err)
+			h := channelparticipation.NewHTTPHandler(enabledConfig, fakeManager)
+
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, newJoinRequest(t, channelparticipation.FormDataConfigBlockKey, blockBytes))
+			assert.Equal(t, testCase.expectedCode, resp.Code)
+			assert.JSONEq(t, `{"error":"`+testCase.err.Error()+`"}`, resp.Body.String())
+		})
+	}
+
+	t.Run("Bad requests", func(t *testing.T) {
+		fakeManager := &mocks.ChannelManagement{}
+		h := channelparticipation.NewHTTPHandler(enabledConfig, fakeManager)
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, bytes.NewReader(blockBytes)))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.JSONEq(t, `{"error":"unsupported Content-Type, expected multipart/form-data"}`, resp.Body.String())
+
+		resp = httptest.NewRecorder()
+		h.ServeHTTP(resp, newJoinRequest(t, "block", blockBytes))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "form field config-block is missing")
+
+		resp = httptest.NewRecorder()
+		h.ServeHTTP(resp, newJoinRequest(t, channelparticipation.FormDataConfigBlockKey, []byte("not-a-block")))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "cannot unmarshal the config block")
+
+		small := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 100}, fakeManager)
+		resp = httptest.NewRecorder()
+		small.ServeHTTP(resp, newJoinRequest(t, channelparticipation.FormDataConfigBlockKey, blockBytes))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "cannot read the multipart form")
+
+		assert.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+}
+
+func TestHTTPHandlerRemove(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	h := channelparticipation.NewHTTPHandler(enabledConfig, fakeManager)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app1", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+	assert.Equal(t, "app1", fakeManager.RemoveChannelArgsForCall(0))
+
+	fakeManager.RemoveChannelReturns(types.ErrChannelNotExist)
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app1", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"error":"channel does not exist"}`, resp.Body.String())
+}
+
+func TestHTTPHandlerMethodNotAllowed(t *testing.T) {
+	h := channelparticipation.NewHTTPHandler(enabledConfig, &mocks.ChannelManagement{})
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, channelparticipation.URLBaseV1Channels, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET, POST", resp.Header().Get("Allow"))
+	assert.JSONEq(t, `{"error":"invalid request method: PUT"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels+"/app1", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET, DELETE", resp.Header().Get("Allow"))
+}
+
+func newJoinRequest(t *testing.T, fieldName string, blockBytes []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(fieldName, "block.pb")
+	require.NoError(t, err)
+	_, err = part.Write(blockBytes)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
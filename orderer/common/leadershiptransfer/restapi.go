@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leadershiptransfer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// URLBaseV1 is the base path of the leadership transfer API.
+	URLBaseV1 = "/leadership/v1/"
+	// URLBaseV1Transfer is the path of the transfer resource of the leadership transfer API.
+	URLBaseV1Transfer = URLBaseV1 + "transfer"
+
+	channelIDKey        = "channelID"
+	urlWithChannelIDKey = URLBaseV1Transfer + "/{" + channelIDKey + "}"
+)
+
+//go:generate counterfeiter -o mocks/leadership_manager.go -fake-name LeadershipManager . LeadershipManager
+
+// LeadershipManager transfers the leadership of the channels of the orderer.
+type LeadershipManager interface {
+	// TransferLeadership transfers the leadership of the given channel away from the orderer.
+	TransferLeadership(channelID string) (types.LeadershipTransfer, error)
+	// TransferAllLeaderships transfers the leadership of all the channels away from the orderer.
+	TransferAllLeaderships() []types.LeadershipTransfer
+}
+
+// ErrorResponse carries the error of a failed request.
+// This is marshaled into the body of the HTTP response.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// HTTPHandler handles the requests of the leadership transfer API, which moves the leadership
+// of the channels away from the orderer, e.g. before the orderer is restarted for maintenance.
+type HTTPHandler struct {
+	logger  *flogging.FabricLogger
+	manager LeadershipManager
+	router  *mux.Router
+}
+
+// NewHTTPHandler constructs an HTTPHandler.
+func NewHTTPHandler(manager LeadershipManager) *HTTPHandler {
+	handler := &HTTPHandler{
+		logger:  flogging.MustGetLogger("orderer.common.leadershiptransfer"),
+		manager: manager,
+		router:  mux.NewRouter(),
+	}
+
+	handler.router.HandleFunc(URLBaseV1Transfer, handler.serveTransferAll).Methods(http.MethodPost)
+	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveTransferOne).Methods(http.MethodPost)
+	handler.router.MethodNotAllowedHandler = http.HandlerFunc(handler.serveNotAllowed)
+
+	return handler
+}
+
+func (h *HTTPHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	h.router.ServeHTTP(resp, req)
+}
+
+func (h *HTTPHandler) serveTransferAll(resp http.ResponseWriter, req *http.Request) {
+	list := types.LeadershipTransferList{Channels: h.manager.TransferAllLeaderships()}
+	for _, transfer := range list.Channels {
+		if transfer.Error != "" {
+			h.sendResponseJSON(resp, http.StatusInternalServerError, list)
+			return
+		}
+	}
+	h.sendResponseJSON(resp, http.StatusOK, list)
+}
+
+func (h *HTTPHandler) serveTransferOne(resp http.ResponseWriter, req *http.Request) {
+	channelID := mux.Vars(req)[channelIDKey]
+	if err := configtx.ValidateChannelID(channelID); err != nil {
+		h.sendResponseJSON(resp, http.StatusBadRequest, errors.WithMessage(err, "invalid channel ID"))
+		return
+	}
+
+	transfer, err := h.manager.TransferLeadership(channelID)
+	if err != nil {
+		h.logger.Warningf("Failed to transfer leadership of channel %s: %s", channelID, err)
+		h.sendError(resp, err)
+		return
+	}
+	h.sendResponseJSON(resp, http.StatusOK, transfer)
+}
+
+func (h *HTTPHandler) serveNotAllowed(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Allow", http.MethodPost)
+	h.sendResponseJSON(resp, http.StatusMethodNotAllowed, errors.Errorf("invalid request method: %s", req.Method))
+}
+
+// sendError maps the errors of the manager to the status codes of the response.
+func (h *HTTPHandler) sendError(resp http.ResponseWriter, err error) {
+	switch errors.Cause(err) {
+	case types.ErrChannelNotExist:
+		h.sendResponseJSON(resp, http.StatusNotFound, err)
+	case types.ErrLeadershipTransferNotSupported:
+		h.sendResponseJSON(resp, http.StatusBadRequest, err)
+	default:
+		h.sendResponseJSON(resp, http.StatusInternalServerError, err)
+	}
+}
+
+func (h *HTTPHandler) sendResponseJSON(resp http.ResponseWriter, code int, payload interface{}) {
+	if err, ok := payload.(error); ok {
+		payload = &ErrorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		h.logger.Errorw("failed to encode payload", "error", err)
+	}
+}
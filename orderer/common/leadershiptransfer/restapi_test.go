@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leadershiptransfer_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/common/leadershiptransfer"
+	"github.com/hyperledger/fabric/orderer/common/leadershiptransfer/mocks"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHandlerTransferAll(t *testing.T) {
+	fakeManager := &mocks.LeadershipManager{}
+	fakeManager.TransferAllLeadershipsReturns([]types.LeadershipTransfer{
+		{Channel: "app1", Leader: 2},
+		{Channel: "app2", Leader: 3},
+	})
+	h := leadershiptransfer.NewHTTPHandler(fakeManager)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, leadershiptransfer.URLBaseV1Transfer, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"channels":[{"channel":"app1","leader":2},{"channel":"app2","leader":3}]}`, resp.Body.String())
+	assert.Equal(t, 1, fakeManager.TransferAllLeadershipsCallCount())
+
+	fakeManager.TransferAllLeadershipsReturns([]types.LeadershipTransfer{
+		{Channel: "app1", Leader: 2},
+		{Channel: "app2", Error: "leader transfer timeout"},
+	})
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, leadershiptransfer.URLBaseV1Transfer, nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"channels":[{"channel":"app1","leader":2},{"channel":"app2","leader":0,"error":"leader transfer timeout"}]}`, resp.Body.String())
+}
+
+func TestHTTPHandlerTransferOne(t *testing.T) {
+	fakeManager := &mocks.LeadershipManager{}
+	fakeManager.TransferLeadershipReturns(types.LeadershipTransfer{Channel: "app1", Leader: 2}, nil)
+	h := leadershiptransfer.NewHTTPHandler(fakeManager)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, leadershiptransfer.URLBaseV1Transfer+"/app1", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"channel":"app1","leader":2}`, resp.Body.String())
+	require.Equal(t, 1, fakeManager.TransferLeadershipCallCount())
+	assert.Equal(t, "app1", fakeManager.TransferLeadershipArgsForCall(0))
+
+	for _, testCase := range []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "channel does not exist", err: types.ErrChannelNotExist, expectedCode: http.StatusNotFound},
+		{name: "not supported", err: types.ErrLeadershipTransferNotSupported, expectedCode: http.StatusBadRequest},
+		{name: "transfer failed", err: errors.New("failed to transfer leadership of channel app1: leader transfer timeout"), expectedCode: http.StatusInternalServerError},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeManager.TransferLeadershipReturns(types.LeadershipTransfer{}, testCase.err)
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, leadershiptransfer.URLBaseV1Transfer+"/app1", nil))
+			assert.Equal(t, testCase.expectedCode, resp.Code)
+			assert.JSONEq(t, `{"error":"`+testCase.err.Error()+`"}`, resp.Body.String())
+		})
+	}
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, leadershiptransfer.URLBaseV1Transfer+"/App_1", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid channel ID: channel ID 'App_1' contains illegal characters"}`, resp.Body.String())
+	assert.Equal(t, 4, fakeManager.TransferLeadershipCallCount())
+}
+
+func TestHTTPHandlerMethodNotAllowed(t *testing.T) {
+	h := leadershiptransfer.NewHTTPHandler(&mocks.LeadershipManager{})
+
+	for _, path := range []string{leadershiptransfer.URLBaseV1Transfer, leadershiptransfer.URLBaseV1Transfer + "/app1"} {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+		assert.Equal(t, "POST", resp.Header().Get("Allow"))
+		assert.JSONEq(t, `{"error":"invalid request method: GET"}`, resp.Body.String())
+	}
+}
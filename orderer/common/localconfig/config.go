@@ -28,14 +28,16 @@ var logger = flogging.MustGetLogger("localconfig")
 // modify the default mapping, see the "Unmarshal"
 // section of https://github.com/spf13/viper for more info.
 type TopLevel struct {
-	General    General
-	FileLedger FileLedger
-	RAMLedger  RAMLedger
-	Kafka      Kafka
-	Debug      Debug
-	Consensus  interface{}
-	Operations Operations
-	Metrics    Metrics
+	General              General
+	FileLedger           FileLedger
+	RAMLedger            RAMLedger
+	CompactedLedger      CompactedLedger
+	Kafka                Kafka
+	Debug                Debug
+	Consensus            interface{}
+	Operations           Operations
+	Metrics              Metrics
+	ChannelParticipation ChannelParticipation
 }
 
 // General contains config which should be common among all orderer types.
@@ -56,6 +58,8 @@ type General struct {
 	LocalMSPID        string
 	BCCSP             *bccsp.FactoryOpts
 	Authentication    Authentication
+	BroadcastQuota    BroadcastQuota
+	Deduplication     Deduplication
 }
 
 type Cluster struct {
@@ -109,6 +113,32 @@ type Authentication struct {
 	NoExpirationChecks bool
 }
 
+// Deduplication contains configuration parameters related to the rejection of
+// transactions whose ID was recently ordered, or is being ordered.
+type Deduplication struct {
+	Enabled         bool
+	WindowSize      int
+	InFlightTimeout time.Duration
+}
+
+// BroadcastQuota contains configuration parameters related to the fair queuing
+// and rate limiting of the messages broadcast by the organizations of a channel.
+type BroadcastQuota struct {
+	Enabled       bool
+	QueueSize     int
+	Rate          float64
+	Burst         int
+	Organizations []OrganizationQuota
+}
+
+// OrganizationQuota overrides the broadcast quota of an organization.
+type OrganizationQuota struct {
+	MSPID     string
+	QueueSize int
+	Rate      float64
+	Burst     int
+}
+
 // Profile contains configuration for Go pprof profiling.
 type Profile struct {
 	Enabled bool
@@ -126,6 +156,12 @@ type RAMLedger struct {
 	HistorySize uint
 }
 
+// CompactedLedger contains configuration for the compacted ledger.
+type CompactedLedger struct {
+	Retention       uint64
+	ArchiveLocation string
+}
+
 // Kafka contains configuration for the Kafka-based orderer.
 type Kafka struct {
 	Retry     Retry
@@ -196,6 +232,13 @@ type Operations struct {
 	TLS           TLS
 }
 
+// ChannelParticipation provides the channel participation API configuration for the orderer.
+// The API is served on the operations endpoint.
+type ChannelParticipation struct {
+	Enabled            bool
+	MaxRequestBodySize uint32
+}
+
 // Operations confiures the metrics provider for the orderer.
 type Metrics struct {
 	Provider string
@@ -241,10 +284,20 @@ var Defaults = TopLevel{
 		Authentication: Authentication{
 			TimeWindow: time.Duration(15 * time.Minute),
 		},
+		BroadcastQuota: BroadcastQuota{
+			QueueSize: 100,
+		},
+		Deduplication: Deduplication{
+			WindowSize:      100000,
+			InFlightTimeout: time.Minute,
+		},
 	},
 	RAMLedger: RAMLedger{
 		HistorySize: 10000,
 	},
+	CompactedLedger: CompactedLedger{
+		Retention: 1000,
+	},
 	FileLedger: FileLedger{
 		Location: "/var/hyperledger/production/orderer",
 		Prefix:   "hyperledger-fabric-ordererledger",
@@ -291,6 +344,10 @@ var Defaults = TopLevel{
 	Metrics: Metrics{
 		Provider: "disabled",
 	},
+	ChannelParticipation: ChannelParticipation{
+		Enabled:            false,
+		MaxRequestBodySize: 1024 * 1024,
+	},
 }
 
 // Load parses the orderer YAML file and environment, producing
@@ -350,6 +407,13 @@ func (c *TopLevel) completeInitialization(configDir string) {
 
 		case c.General.GenesisMethod == "":
 			c.General.GenesisMethod = Defaults.General.GenesisMethod
+		case c.General.GenesisMethod == "none" && !c.ChannelParticipation.Enabled:
+			logger.Panicf("General.GenesisMethod can be set to none only if ChannelParticipation.Enabled is set to true.")
+		case c.ChannelParticipation.Enabled && (!c.Operations.TLS.Enabled || !c.Operations.TLS.ClientAuthRequired):
+			logger.Panicf("Operations.TLS.Enabled and Operations.TLS.ClientAuthRequired must be set to true if ChannelParticipation.Enabled is set to true.")
+		case c.ChannelParticipation.MaxRequestBodySize == 0:
+			logger.Infof("ChannelParticipation.MaxRequestBodySize unset, setting to %v", Defaults.ChannelParticipation.MaxRequestBodySize)
+			c.ChannelParticipation.MaxRequestBodySize = Defaults.ChannelParticipation.MaxRequestBodySize
 		case c.General.GenesisFile == "":
 			c.General.GenesisFile = Defaults.General.GenesisFile
 		case c.General.GenesisProfile == "":
@@ -401,10 +465,26 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", Defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = Defaults.General.Authentication.TimeWindow
 
+		case c.General.BroadcastQuota.Enabled && c.General.BroadcastQuota.QueueSize == 0:
+			logger.Infof("General.BroadcastQuota.QueueSize unset, setting to %d", Defaults.General.BroadcastQuota.QueueSize)
+			c.General.BroadcastQuota.QueueSize = Defaults.General.BroadcastQuota.QueueSize
+
+		case c.General.Deduplication.Enabled && c.General.Deduplication.WindowSize == 0:
+			logger.Infof("General.Deduplication.WindowSize unset, setting to %d", Defaults.General.Deduplication.WindowSize)
+			c.General.Deduplication.WindowSize = Defaults.General.Deduplication.WindowSize
+
+		case c.General.Deduplication.Enabled && c.General.Deduplication.InFlightTimeout == 0:
+			logger.Infof("General.Deduplication.InFlightTimeout unset, setting to %s", Defaults.General.Deduplication.InFlightTimeout)
+			c.General.Deduplication.InFlightTimeout = Defaults.General.Deduplication.InFlightTimeout
+
 		case c.FileLedger.Prefix == "":
 			logger.Infof("FileLedger.Prefix unset, setting to %s", Defaults.FileLedger.Prefix)
 			c.FileLedger.Prefix = Defaults.FileLedger.Prefix
 
+		case c.CompactedLedger.Retention == 0:
+			logger.Infof("CompactedLedger.Retention unset, setting to %d", Defaults.CompactedLedger.Retention)
+			c.CompactedLedger.Retention = Defaults.CompactedLedger.Retention
+
 		case c.Kafka.Retry.ShortInterval == 0:
 			logger.Infof("Kafka.Retry.ShortInterval unset, setting to %v", Defaults.Kafka.Retry.ShortInterval)
 			c.Kafka.Retry.ShortInterval = Defaults.Kafka.Retry.ShortInterval
@@ -153,6 +153,39 @@ func TestKafkaSASLPlain(t *testing.T) {
 	}
 }
 
+func TestChannelParticipationConfig(t *testing.T) {
+	mutualTLS := Operations{TLS: TLS{Enabled: true, ClientAuthRequired: true}}
+	testCases := []struct {
+		name                 string
+		genesisMethod        string
+		operations           Operations
+		channelParticipation ChannelParticipation
+		shouldPanic          bool
+	}{
+		{"Disabled", "file", Operations{}, ChannelParticipation{}, false},
+		{"Enabled", "file", mutualTLS, ChannelParticipation{Enabled: true}, false},
+		{"EnabledWithoutSystemChannel", "none", mutualTLS, ChannelParticipation{Enabled: true}, false},
+		{"DisabledWithoutSystemChannel", "none", mutualTLS, ChannelParticipation{}, true},
+		{"EnabledNoTLS", "file", Operations{}, ChannelParticipation{Enabled: true}, true},
+		{"EnabledNoClientAuth", "file", Operations{TLS: TLS{Enabled: true}}, ChannelParticipation{Enabled: true}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uconf := &TopLevel{
+				General:              General{GenesisMethod: tc.genesisMethod},
+				Operations:           tc.operations,
+				ChannelParticipation: tc.channelParticipation,
+			}
+			if tc.shouldPanic {
+				assert.Panics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should panic")
+			} else {
+				assert.NotPanics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should not panic")
+				assert.Equal(t, Defaults.ChannelParticipation.MaxRequestBodySize, uconf.ChannelParticipation.MaxRequestBodySize)
+			}
+		})
+	}
+}
+
 func TestClusterDefaults(t *testing.T) {
 	cleanup := configtest.SetDevFabricConfigPath(t)
 	defer cleanup()
@@ -235,3 +268,13 @@ func TestConnectionTimeout(t *testing.T) {
 		assert.Equal(t, cfg.General.ConnectionTimeout, 10*time.Second)
 	})
 }
+
+func TestBroadcastQuotaDefaults(t *testing.T) {
+	uconf := &TopLevel{General: General{BroadcastQuota: BroadcastQuota{Enabled: true}}}
+	uconf.completeInitialization("/dummy/path")
+	assert.Equal(t, Defaults.General.BroadcastQuota.QueueSize, uconf.General.BroadcastQuota.QueueSize)
+
+	uconf = &TopLevel{General: General{BroadcastQuota: BroadcastQuota{Enabled: true, QueueSize: 5}}}
+	uconf.completeInitialization("/dummy/path")
+	assert.Equal(t, 5, uconf.General.BroadcastQuota.QueueSize)
+}
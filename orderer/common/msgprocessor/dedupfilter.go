@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// ErrDuplicateTxID is returned by the dedup filter for transactions whose ID
+// was recently ordered on the channel.
+var ErrDuplicateTxID = errors.New("duplicate transaction ID")
+
+// DedupFilterSupport provides the blocks of the channel to the dedup filter.
+type DedupFilterSupport interface {
+	// Height returns the number of blocks of the channel
+	Height() uint64
+
+	// Block returns the block with the given number, or nil if it does not exist
+	Block(number uint64) *cb.Block
+}
+
+// DedupFilter rejects the transactions whose ID is among the IDs of the most
+// recent transactions of the ledger of the channel, or among the IDs of the
+// transactions which were passed to the consenter by this orderer and are not
+// yet written to the ledger.
+//
+// The IDs of the transactions which are being ordered are only known to the
+// orderer which received them, so the consenters must not reject a transaction
+// because of them when they revalidate it: the rule returned by Committed only
+// considers the ledger, from which every orderer of the channel derives the
+// same window, regardless of which one is the leader.
+type DedupFilter struct {
+	support         DedupFilterSupport
+	size            int
+	inFlightTimeout time.Duration
+
+	mutex    sync.Mutex
+	synced   bool
+	height   uint64
+	txIDs    map[string]struct{}
+	window   []string
+	next     int
+	inFlight map[string]time.Time
+	now      func() time.Time
+}
+
+// NewDedupFilter creates a dedup filter which remembers the given number of
+// most recent transaction IDs of the channel. The transactions which are being
+// ordered are forgotten if they are not written to the ledger within the given
+// timeout, e.g. because the consenter dropped them.
+func NewDedupFilter(support DedupFilterSupport, size int, inFlightTimeout time.Duration) *DedupFilter {
+	return &DedupFilter{
+		support:         support,
+		size:            size,
+		inFlightTimeout: inFlightTimeout,
+		txIDs:           map[string]struct{}{},
+		inFlight:        map[string]time.Time{},
+	}
+}
+
+// Apply returns an error if the ID of the transaction is among the IDs of the
+// most recent transactions of the channel, or of the transactions which are
+// being ordered.
+func (df *DedupFilter) Apply(message *cb.Envelope) error {
+	txID := transactionID(message)
+	if txID == "" {
+		return nil
+	}
+
+	df.mutex.Lock()
+	defer df.mutex.Unlock()
+
+	df.sync()
+	return df.check(txID)
+}
+
+// Committed returns a rule which only rejects the transactions whose ID is
+// among the IDs of the most recent transactions of the ledger of the channel.
+// It is meant for the consenters, which must reach the same decision on every
+// orderer of the channel.
+func (df *DedupFilter) Committed() Rule {
+	return committedDedupFilter{df: df}
+}
+
+// Track marks the transaction as being ordered, so that its duplicates are
+// rejected until the transaction is written to the ledger. It returns an error
+// if the transaction is a duplicate, as another one with the same ID may have
+// been passed to the consenter since the transaction was filtered.
+func (df *DedupFilter) Track(message *cb.Envelope) error {
+	txID := transactionID(message)
+	if txID == "" {
+		return nil
+	}
+
+	df.mutex.Lock()
+	defer df.mutex.Unlock()
+
+	df.sync()
+	if err := df.check(txID); err != nil {
+		return err
+	}
+	df.inFlight[txID] = df.clock()
+	return nil
+}
+
+// Untrack forgets a transaction which was marked as being ordered,
+// for instance because the consenter did not accept it.
+func (df *DedupFilter) Untrack(message *cb.Envelope) {
+	txID := transactionID(message)
+	if txID == "" {
+		return
+	}
+
+	df.mutex.Lock()
+	defer df.mutex.Unlock()
+
+	delete(df.inFlight, txID)
+}
+
+// check returns an error if the transaction ID is in the window, or if it
+// belongs to a transaction which is being ordered and did not time out.
+func (df *DedupFilter) check(txID string) error {
+	if _, exists := df.txIDs[txID]; exists {
+		return errors.WithMessage(ErrDuplicateTxID, fmt.Sprintf("transaction %s has already been ordered", txID))
+	}
+	if since, exists := df.inFlight[txID]; exists {
+		if df.clock().Sub(since) < df.inFlightTimeout {
+			return errors.WithMessage(ErrDuplicateTxID, fmt.Sprintf("transaction %s is being ordered", txID))
+		}
+		delete(df.inFlight, txID)
+	}
+	return nil
+}
+
+func (df *DedupFilter) clock() time.Time {
+	if df.now != nil {
+		return df.now()
+	}
+	return time.Now()
+}
+
+// sync adds the IDs of the transactions of the blocks which were appended to
+// the ledger since the last call to the window, and evicts them from the
+// transactions which are being ordered, along with the ones which timed out.
+func (df *DedupFilter) sync() {
+	height := df.support.Height()
+	if !df.synced {
+		df.synced = true
+		df.height = df.start(height)
+	}
+	if df.height >= height {
+		return
+	}
+
+	for ; df.height < height; df.height++ {
+		block := df.support.Block(df.height)
+		if block == nil || block.Data == nil {
+			continue
+		}
+		for _, data := range block.Data.Data {
+			env, err := utils.UnmarshalEnvelope(data)
+			if err != nil {
+				continue
+			}
+			txID := transactionID(env)
+			df.add(txID)
+			delete(df.inFlight, txID)
+		}
+	}
+
+	now := df.clock()
+	for txID, since := range df.inFlight {
+		if now.Sub(since) >= df.inFlightTimeout {
+			delete(df.inFlight, txID)
+		}
+	}
+}
+
+// start returns the number of the oldest block whose transactions are in the
+// window, when the window is first filled from a ledger of the given height.
+func (df *DedupFilter) start(height uint64) uint64 {
+	count := 0
+	for start := height; start > 0; start-- {
+		if count >= df.size {
+			return start
+		}
+		if block := df.support.Block(start - 1); block != nil && block.Data != nil {
+			count += len(block.Data.Data)
+		}
+	}
+	return 0
+}
+
+// add adds a transaction ID to the window, and evicts
+// the oldest one if the window is full.
+func (df *DedupFilter) add(txID string) {
+	if _, exists := df.txIDs[txID]; exists || txID == "" || df.size <= 0 {
+		return
+	}
+
+	if len(df.window) < df.size {
+		df.window = append(df.window, txID)
+	} else {
+		delete(df.txIDs, df.window[df.next])
+		df.window[df.next] = txID
+		df.next = (df.next + 1) % df.size
+	}
+	df.txIDs[txID] = struct{}{}
+}
+
+// DedupFilter returns the dedup filter of the rule set, or nil if it has none.
+func (rs *RuleSet) DedupFilter() *DedupFilter {
+	for _, rule := range rs.rules {
+		if df, ok := rule.(*DedupFilter); ok {
+			return df
+		}
+	}
+	return nil
+}
+
+// Committed returns a copy of the rule set in which the dedup
+// filter, if any, is replaced by the rule returned by its Committed.
+func (rs *RuleSet) Committed() *RuleSet {
+	rules := make([]Rule, len(rs.rules))
+	for i, rule := range rs.rules {
+		if df, ok := rule.(*DedupFilter); ok {
+			rule = df.Committed()
+		}
+		rules[i] = rule
+	}
+	return NewRuleSet(rules)
+}
+
+// committedDedupFilter is the view of a dedup filter
+// which ignores the transactions being ordered.
+type committedDedupFilter struct {
+	df *DedupFilter
+}
+
+// Apply returns an error if the ID of the transaction is among the
+// IDs of the most recent transactions of the ledger of the channel.
+func (cdf committedDedupFilter) Apply(message *cb.Envelope) error {
+	txID := transactionID(message)
+	if txID == "" {
+		return nil
+	}
+
+	cdf.df.mutex.Lock()
+	defer cdf.df.mutex.Unlock()
+
+	cdf.df.sync()
+	if _, exists := cdf.df.txIDs[txID]; exists {
+		return errors.WithMessage(ErrDuplicateTxID, fmt.Sprintf("transaction %s has already been ordered", txID))
+	}
+	return nil
+}
+
+// transactionID returns the transaction ID of an envelope, or an
+// empty string if the envelope does not carry one.
+func transactionID(env *cb.Envelope) string {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return ""
+	}
+	return chdr.TxId
+}
@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDedupFilterSupport struct {
+	mockconfig.Resources
+	blocks []*cb.Block
+	reads  int
+}
+
+func (ms *mockDedupFilterSupport) Height() uint64 {
+	return uint64(len(ms.blocks))
+}
+
+func (ms *mockDedupFilterSupport) Block(number uint64) *cb.Block {
+	ms.reads++
+	return ms.blocks[number]
+}
+
+func (ms *mockDedupFilterSupport) appendBlock(txIDs ...string) {
+	block := cb.NewBlock(uint64(len(ms.blocks)), nil)
+	for _, txID := range txIDs {
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(transaction(txID)))
+	}
+	ms.blocks = append(ms.blocks, block)
+}
+
+func transaction(txID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: testChannelID,
+					TxId:      txID,
+				}),
+			},
+		}),
+	}
+}
+
+func TestDedupFilter(t *testing.T) {
+	ms := &mockDedupFilterSupport{}
+	ms.appendBlock("")
+	ms.appendBlock("tx1", "tx2")
+	ms.appendBlock("tx3")
+	ms.appendBlock("tx4", "tx5")
+
+	df := NewDedupFilter(ms, 3, time.Minute)
+
+	// The window starts with the most recent transactions of the ledger.
+	for _, txID := range []string{"tx1", "tx2", "tx6"} {
+		assert.NoError(t, df.Apply(transaction(txID)))
+	}
+	for _, txID := range []string{"tx3", "tx4", "tx5"} {
+		err := df.Apply(transaction(txID))
+		assert.EqualError(t, err, fmt.Sprintf("transaction %s has already been ordered: duplicate transaction ID", txID))
+		assert.Equal(t, ErrDuplicateTxID, errors.Cause(err))
+	}
+
+	// New blocks are added to the window, and evict the oldest transactions.
+	reads := ms.reads
+	ms.appendBlock("tx6", "tx3")
+	ms.appendBlock("tx7")
+	assert.EqualError(t, df.Apply(transaction("tx6")), "transaction tx6 has already been ordered: duplicate transaction ID")
+	assert.Equal(t, reads+2, ms.reads)
+	assert.Error(t, df.Apply(transaction("tx3")))
+	assert.Error(t, df.Apply(transaction("tx7")))
+	assert.NoError(t, df.Apply(transaction("tx4")))
+	assert.NoError(t, df.Apply(transaction("tx5")))
+	assert.Equal(t, reads+2, ms.reads)
+
+	// Messages without a transaction ID are not filtered.
+	assert.NoError(t, df.Apply(&cb.Envelope{Payload: []byte("garbage")}))
+	assert.NoError(t, df.Apply(transaction("")))
+}
+
+func TestDedupFilterEmptyLedger(t *testing.T) {
+	ms := &mockDedupFilterSupport{}
+	df := NewDedupFilter(ms, 10, time.Minute)
+	assert.NoError(t, df.Apply(transaction("tx1")))
+	assert.NoError(t, df.Track(transaction("tx1")))
+
+	// A duplicate of a transaction which is not yet written to the ledger is rejected.
+	err := df.Apply(transaction("tx1"))
+	assert.EqualError(t, err, "transaction tx1 is being ordered: duplicate transaction ID")
+	assert.Equal(t, ErrDuplicateTxID, errors.Cause(err))
+	assert.Error(t, df.Track(transaction("tx1")))
+
+	ms.appendBlock("tx1")
+	assert.EqualError(t, df.Apply(transaction("tx1")), "transaction tx1 has already been ordered: duplicate transaction ID")
+	assert.Empty(t, df.inFlight)
+}
+
+func TestDedupFilterInFlight(t *testing.T) {
+	ms := &mockDedupFilterSupport{}
+	ms.appendBlock("tx1")
+	df := NewDedupFilter(ms, 10, time.Minute)
+	now := time.Now()
+	df.now = func() time.Time { return now }
+
+	// The transactions which are being ordered are ignored by the committed rule.
+	committed := df.Committed()
+	assert.Error(t, df.Track(transaction("tx1")))
+	assert.NoError(t, df.Track(transaction("tx2")))
+	assert.NoError(t, df.Track(transaction("tx3")))
+	assert.Error(t, df.Apply(transaction("tx2")))
+	assert.NoError(t, committed.Apply(transaction("tx2")))
+	assert.Error(t, committed.Apply(transaction("tx1")))
+
+	// A transaction which the consenter did not accept is forgotten.
+	df.Untrack(transaction("tx2"))
+	assert.NoError(t, df.Apply(transaction("tx2")))
+
+	// So is a transaction which is not written to the ledger in time.
+	now = now.Add(time.Minute)
+	assert.NoError(t, df.Apply(transaction("tx3")))
+	assert.NoError(t, df.Track(transaction("tx3")))
+	assert.NoError(t, df.Track(transaction("tx4")))
+	now = now.Add(time.Minute)
+	ms.appendBlock("tx3")
+	assert.Error(t, df.Apply(transaction("tx3")))
+	assert.Empty(t, df.inFlight)
+
+	// Messages without a transaction ID are not tracked.
+	assert.NoError(t, df.Track(transaction("")))
+	assert.Empty(t, df.inFlight)
+}
+
+func TestCreateStandardChannelFiltersDedup(t *testing.T) {
+	ms := &mockDedupFilterSupport{}
+	ms.appendBlock("tx1")
+
+	config := localconfig.TopLevel{}
+	config.General.Authentication.NoExpirationChecks = true
+	assert.Len(t, CreateStandardChannelFilters(ms, config).rules, 3)
+
+	config.General.Deduplication = localconfig.Deduplication{Enabled: true, WindowSize: 10, InFlightTimeout: time.Minute}
+	filters := CreateStandardChannelFilters(ms, config)
+	assert.Len(t, filters.rules, 4)
+	assert.Equal(t, NewDedupFilter(ms, 10, time.Minute), filters.rules[3])
+	assert.Equal(t, filters.rules[3], filters.DedupFilter())
+
+	committed := filters.Committed()
+	assert.Equal(t, filters.rules[:3], committed.rules[:3])
+	assert.Equal(t, filters.DedupFilter().Committed(), committed.rules[3])
+	assert.Nil(t, committed.DedupFilter())
+
+	filters = CreateStandardChannelFilters(&mockconfig.Resources{}, config)
+	assert.Len(t, filters.rules, 3)
+	assert.Nil(t, filters.DedupFilter())
+}
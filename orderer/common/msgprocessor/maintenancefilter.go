@@ -195,3 +195,35 @@ func (mf *MaintenanceFilter) ensureConsensusTypeChangeOnly(configEnvelope *cb.Co
 
 	return nil
 }
+
+// consensusTypeMigrationSupport provides the current config of a
+// channel to the maintenance filter, outside of the orderer.
+type consensusTypeMigrationSupport struct {
+	*channelconfig.Bundle
+	chainID string
+}
+
+func (s *consensusTypeMigrationSupport) ChainID() string {
+	return s.chainID
+}
+
+// ValidateConsensusTypeMigrationStep checks that a config transaction, which takes the channel from the current
+// config to the config of the envelope, is a valid step of a consensus-type migration, i.e. that it changes
+// only the ConsensusType value, and that it respects the transition rules of the maintenance filter.
+// It allows the tools which build such transactions to validate them before they are submitted.
+func ValidateConsensusTypeMigrationStep(chainID string, current *cb.Config, configEnvelope *cb.ConfigEnvelope) error {
+	bundle, err := channelconfig.NewBundle(chainID, current)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse current config")
+	}
+	ordererConfig, ok := bundle.OrdererConfig()
+	if !ok {
+		return errors.New("current config is missing orderer group")
+	}
+
+	mf := NewMaintenanceFilter(&consensusTypeMigrationSupport{Bundle: bundle, chainID: chainID})
+	if err := mf.ensureConsensusTypeChangeOnly(configEnvelope); err != nil {
+		return err
+	}
+	return mf.inspect(configEnvelope, ordererConfig)
+}
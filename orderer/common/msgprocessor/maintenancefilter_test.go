@@ -470,3 +470,71 @@ func makeBaseConfig(t *testing.T) *common.Config {
 	}
 	return original
 }
+
+func TestValidateConsensusTypeMigrationStep(t *testing.T) {
+	setConsensusType := func(config *common.Config, info consensusTypeInfo) {
+		config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey] = &common.ConfigValue{
+			Value: utils.MarshalOrPanic(
+				&orderer.ConsensusType{
+					Type:     info.ordererType,
+					Metadata: info.metadata,
+					State:    info.state,
+				}),
+			ModPolicy: channelconfig.AdminsPolicyKey,
+		}
+	}
+	configEnvelope := func(original, updated *common.Config) *common.ConfigEnvelope {
+		configEnv := &common.ConfigEnvelope{}
+		_, err := utils.UnmarshalEnvelopeOfType(makeConfigTx(original, updated, t), common.HeaderType_CONFIG, configEnv)
+		require.NoError(t, err)
+		return configEnv
+	}
+
+	normal := consensusTypeInfo{ordererType: "kafka", metadata: []byte{}, state: orderer.ConsensusType_STATE_NORMAL}
+	maintenance := consensusTypeInfo{ordererType: "kafka", metadata: []byte{}, state: orderer.ConsensusType_STATE_MAINTENANCE}
+	migrated := consensusTypeInfo{ordererType: "etcdraft", metadata: utils.MarshalOrPanic(&etcdraft.ConfigMetadata{}), state: orderer.ConsensusType_STATE_MAINTENANCE}
+
+	for _, tc := range []struct {
+		name          string
+		current, next consensusTypeInfo
+		extra         bool
+		expectedErr   string
+	}{
+		{name: "entry", current: normal, next: maintenance},
+		{name: "type change", current: maintenance, next: migrated},
+		{name: "type change back", current: migrated, next: maintenance},
+		{name: "exit", current: maintenance, next: normal},
+		{
+			name:        "type change out of maintenance",
+			current:     normal,
+			next:        consensusTypeInfo{ordererType: "etcdraft", metadata: migrated.metadata, state: orderer.ConsensusType_STATE_NORMAL},
+			expectedErr: "attempted to change consensus type from kafka to etcdraft, but current config ConsensusType.State is not in maintenance mode",
+		},
+		{
+			name:        "other changes",
+			current:     maintenance,
+			next:        migrated,
+			extra:       true,
+			expectedErr: "config update contain more then just the ConsensusType value in the Orderer group",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			original, updated := makeBaseConfig(t), makeBaseConfig(t)
+			setConsensusType(original, tc.current)
+			setConsensusType(updated, tc.next)
+			if tc.extra {
+				updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchTimeoutKey] = &common.ConfigValue{
+					Value:     utils.MarshalOrPanic(&orderer.BatchTimeout{Timeout: "1s"}),
+					ModPolicy: channelconfig.AdminsPolicyKey,
+				}
+			}
+
+			err := ValidateConsensusTypeMigrationStep(testChannelID, original, configEnvelope(original, updated))
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
@@ -57,6 +57,9 @@ func NewStandardChannel(support StandardChannelSupport, filters *RuleSet) *Stand
 //
 // In maintenance mode, require the signature of /Channel/Orderer/Writer. This will filter out configuration
 // changes that are not related to consensus-type migration (e.g on /Channel/Application).
+//
+// If deduplication is enabled and the filter support provides the blocks of the channel, transactions
+// whose ID was recently ordered, or which are being ordered, are rejected.
 func CreateStandardChannelFilters(filterSupport channelconfig.Resources, config localconfig.TopLevel) *RuleSet {
 	rules := []Rule{
 		EmptyRejectRule,
@@ -69,6 +72,14 @@ func CreateStandardChannelFilters(filterSupport channelconfig.Resources, config
 		rules = append(rules[:2], append([]Rule{expirationRule}, rules[2:]...)...)
 	}
 
+	if config.General.Deduplication.Enabled {
+		if dedupSupport, ok := filterSupport.(DedupFilterSupport); ok {
+			rules = append(rules, NewDedupFilter(dedupSupport, config.General.Deduplication.WindowSize, config.General.Deduplication.InFlightTimeout))
+		} else {
+			logger.Warningf("Deduplication is enabled, but the channel does not provide its blocks")
+		}
+	}
+
 	return NewRuleSet(rules)
 }
 
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package multichannel
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -194,15 +195,26 @@ func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte) {
 }
 
 func (bw *BlockWriter) addBlockSignature(block *cb.Block) {
-	blockSignature := &cb.MetadataSignature{
-		SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(bw.support)),
-	}
-
 	blockSignatureValue := utils.MarshalOrPanic(&cb.OrdererBlockMetadata{
 		LastConfig:        &cb.LastConfig{Index: bw.lastConfigBlockNum},
 		ConsenterMetadata: bw.lastBlock.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER],
 	})
 
+	// Consenters which collect the signatures of several orderers on the block
+	// set them beforehand, in which case they are kept as long as they sign
+	// the same value.
+	if presetSignatures, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES); err == nil && len(presetSignatures.Signatures) > 0 {
+		if bytes.Equal(presetSignatures.Value, blockSignatureValue) {
+			logger.Debugf("[channel: %s] Block [%d] already carries %d signatures", bw.support.ChainID(), block.Header.Number, len(presetSignatures.Signatures))
+			return
+		}
+		logger.Warningf("[channel: %s] Discarding the signatures of block [%d] as they were made over a different value", bw.support.ChainID(), block.Header.Number)
+	}
+
+	blockSignature := &cb.MetadataSignature{
+		SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(bw.support)),
+	}
+
 	blockSignature.Signature = utils.SignOrPanic(bw.support, util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, block.Header.Bytes()))
 
 	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
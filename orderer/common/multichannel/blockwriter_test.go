@@ -9,6 +9,7 @@ package multichannel
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	newchannelconfig "github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -23,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mockBlockWriterSupport struct {
@@ -94,6 +96,64 @@ func TestBlockSignature(t *testing.T) {
 	assert.NotNil(t, md.Signatures, "Should have signature")
 }
 
+func TestBlockPresetSignatures(t *testing.T) {
+	rlf := ramledger.New(3)
+	l, err := rlf.GetOrCreate("mychannel")
+	assert.NoError(t, err)
+	lastBlock := cb.NewBlock(0, nil)
+	l.Append(lastBlock)
+
+	bw := &BlockWriter{
+		lastConfigBlockNum: 42,
+		support: &mockBlockWriterSupport{
+			LocalSigner: mockCrypto(),
+			Validator:   &mockconfigtx.Validator{},
+			ReadWriter:  l,
+		},
+	}
+
+	consensusMetadata := []byte("bar")
+	presetSignatures := []*cb.MetadataSignature{
+		{SignatureHeader: []byte("header-1"), Signature: []byte("signature-1")},
+		{SignatureHeader: []byte("header-2"), Signature: []byte("signature-2")},
+	}
+	newBlockWithSignatures := func(number uint64, previousHash []byte, value []byte) *cb.Block {
+		block := cb.NewBlock(number, previousHash)
+		block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
+			Value:      value,
+			Signatures: presetSignatures,
+		})
+		return block
+	}
+
+	expectedMetadataValue := utils.MarshalOrPanic(&cb.OrdererBlockMetadata{
+		LastConfig:        &cb.LastConfig{Index: 42},
+		ConsenterMetadata: utils.MarshalOrPanic(&cb.Metadata{Value: consensusMetadata}),
+	})
+
+	t.Run("same value", func(t *testing.T) {
+		bw.lastBlock = newBlockWithSignatures(1, lastBlock.Header.Hash(), expectedMetadataValue)
+		bw.commitBlock(consensusMetadata)
+
+		md := utils.GetMetadataFromBlockOrPanic(blockledger.GetBlock(l, 1), cb.BlockMetadataIndex_SIGNATURES)
+		assert.Equal(t, expectedMetadataValue, md.Value)
+		require.Len(t, md.Signatures, len(presetSignatures), "Preset signatures are kept")
+		for i := range presetSignatures {
+			assert.True(t, proto.Equal(presetSignatures[i], md.Signatures[i]))
+		}
+	})
+
+	t.Run("different value", func(t *testing.T) {
+		bw.lastBlock = newBlockWithSignatures(2, bw.lastBlock.Header.Hash(), []byte("other value"))
+		bw.commitBlock(consensusMetadata)
+
+		md := utils.GetMetadataFromBlockOrPanic(blockledger.GetBlock(l, 2), cb.BlockMetadataIndex_SIGNATURES)
+		assert.Equal(t, expectedMetadataValue, md.Value)
+		assert.Len(t, md.Signatures, 1, "Preset signatures are replaced by the signature of the orderer")
+		assert.False(t, proto.Equal(presetSignatures[0], md.Signatures[0]))
+	})
+}
+
 func TestBlockLastConfig(t *testing.T) {
 	lastConfigSeq := uint64(6)
 	newConfigSeq := lastConfigSeq + 1
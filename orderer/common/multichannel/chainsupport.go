@@ -27,6 +27,12 @@ type ChainSupport struct {
 	consensus.Chain
 	cutter blockcutter.Receiver
 	crypto.LocalSigner
+
+	// dedupFilter is the dedup filter of the channel, if enabled, and
+	// committedProcessor the processor which the consenter revalidates
+	// the messages with, whose dedup filter only considers the ledger.
+	dedupFilter        *msgprocessor.DedupFilter
+	committedProcessor msgprocessor.Processor
 }
 
 func newChainSupport(
@@ -57,7 +63,11 @@ func newChainSupport(
 	}
 
 	// Set up the msgprocessor
-	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config))
+	filters := msgprocessor.CreateStandardChannelFilters(cs, registrar.config)
+	cs.Processor = msgprocessor.NewStandardChannel(cs, filters)
+	if cs.dedupFilter = filters.DedupFilter(); cs.dedupFilter != nil {
+		cs.committedProcessor = msgprocessor.NewStandardChannel(cs, filters.Committed())
+	}
 
 	// Set up the block writer
 	cs.BlockWriter = newBlockWriter(lastBlock, registrar, cs)
@@ -69,7 +79,11 @@ func newChainSupport(
 		logger.Panicf("Error retrieving consenter of type: %s", consenterType)
 	}
 
-	cs.Chain, err = consenter.HandleChain(cs, metadata)
+	var support consensus.ConsenterSupport = cs
+	if cs.dedupFilter != nil {
+		support = &consenterSupport{ChainSupport: cs}
+	}
+	cs.Chain, err = consenter.HandleChain(support, metadata)
 	if err != nil {
 		logger.Panicf("[channel: %s] Error creating consenter: %s", cs.ChainID(), err)
 	}
@@ -97,6 +111,23 @@ func (cs *ChainSupport) Signer() crypto.LocalSigner {
 	return cs
 }
 
+// Order marks the transaction as being ordered in the dedup filter of the
+// channel, if any, and passes it to the consenter.
+func (cs *ChainSupport) Order(env *cb.Envelope, configSeq uint64) error {
+	if cs.dedupFilter == nil {
+		return cs.Chain.Order(env, configSeq)
+	}
+
+	if err := cs.dedupFilter.Track(env); err != nil {
+		return err
+	}
+	if err := cs.Chain.Order(env, configSeq); err != nil {
+		cs.dedupFilter.Untrack(env)
+		return err
+	}
+	return nil
+}
+
 func (cs *ChainSupport) start() {
 	cs.Chain.Start()
 }
@@ -177,3 +208,21 @@ func (cs *ChainSupport) VerifyBlockSignature(sd []*cb.SignedData, envelope *cb.C
 	}
 	return nil
 }
+
+// consenterSupport is the support handed to the consenter of a channel with a
+// dedup filter. The transactions which are being ordered are only known to the
+// orderer which received them, hence the consenter revalidates the messages
+// against the transactions of the ledger only, so that every orderer of the
+// channel reaches the same decision.
+type consenterSupport struct {
+	*ChainSupport
+}
+
+// ProcessNormalMsg revalidates a normal message, ignoring the
+// transactions which this orderer is ordering.
+func (cs *consenterSupport) ProcessNormalMsg(env *cb.Envelope) (configSeq uint64, err error) {
+	if cs.committedProcessor == nil {
+		return cs.Processor.ProcessNormalMsg(env)
+	}
+	return cs.committedProcessor.ProcessNormalMsg(env)
+}
@@ -8,6 +8,7 @@ package multichannel
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/deliver/mock"
@@ -19,8 +20,11 @@ import (
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -106,3 +110,83 @@ func testConfigEnvelope(t *testing.T) *common.ConfigEnvelope {
 		},
 	}
 }
+
+type emptyDedupSupport struct{}
+
+func (emptyDedupSupport) Height() uint64                    { return 0 }
+func (emptyDedupSupport) Block(number uint64) *common.Block { return nil }
+
+type orderRecorder struct {
+	consensus.Chain
+	err     error
+	ordered []*common.Envelope
+}
+
+func (or *orderRecorder) Order(env *common.Envelope, configSeq uint64) error {
+	if or.err != nil {
+		return or.err
+	}
+	or.ordered = append(or.ordered, env)
+	return nil
+}
+
+type sequenceProcessor struct {
+	msgprocessor.Processor
+	seq uint64
+}
+
+func (sp sequenceProcessor) ProcessNormalMsg(env *common.Envelope) (uint64, error) {
+	return sp.seq, nil
+}
+
+func transactionEnvelope(txID string) *common.Envelope {
+	return &common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					Type: int32(common.HeaderType_ENDORSER_TRANSACTION),
+					TxId: txID,
+				}),
+			},
+		}),
+	}
+}
+
+func TestChainSupportOrderDedup(t *testing.T) {
+	chain := &orderRecorder{}
+	df := msgprocessor.NewDedupFilter(emptyDedupSupport{}, 10, time.Minute)
+	cs := &ChainSupport{Chain: chain, dedupFilter: df}
+
+	assert.NoError(t, cs.Order(transactionEnvelope("tx1"), 0))
+	assert.Len(t, chain.ordered, 1)
+	assert.Error(t, df.Apply(transactionEnvelope("tx1")))
+
+	// A duplicate which passed the filter concurrently is not ordered.
+	err := cs.Order(transactionEnvelope("tx1"), 0)
+	assert.Equal(t, msgprocessor.ErrDuplicateTxID, errors.Cause(err))
+	assert.Len(t, chain.ordered, 1)
+
+	// A transaction which the consenter does not accept is not tracked.
+	chain.err = errors.New("not ready")
+	assert.EqualError(t, cs.Order(transactionEnvelope("tx2"), 0), "not ready")
+	assert.NoError(t, df.Apply(transactionEnvelope("tx2")))
+
+	// Without a dedup filter, the message is passed to the consenter.
+	cs = &ChainSupport{Chain: chain}
+	assert.EqualError(t, cs.Order(transactionEnvelope("tx1"), 0), "not ready")
+}
+
+func TestConsenterSupportProcessNormalMsg(t *testing.T) {
+	cs := &consenterSupport{ChainSupport: &ChainSupport{
+		Processor:          sequenceProcessor{seq: 1},
+		committedProcessor: sequenceProcessor{seq: 2},
+	}}
+	seq, err := cs.ProcessNormalMsg(transactionEnvelope("tx1"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), seq)
+
+	cs.committedProcessor = nil
+	seq, err = cs.ProcessNormalMsg(transactionEnvelope("tx1"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), seq)
+}
@@ -11,6 +11,7 @@ package multichannel
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
@@ -22,7 +23,9 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/inactive"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -90,9 +93,18 @@ type ledgerResources struct {
 	blockledger.ReadWriter
 }
 
+// ChannelReplicator pulls the blocks of a channel that precede the config block it joins
+// the channel with, when the orderer joins a channel that has already progressed.
+type ChannelReplicator interface {
+	// ReplicateUntil appends to the given ledger the blocks of the channel that precede
+	// the given join block, and verifies that they lead to the join block.
+	ReplicateUntil(joinBlock *cb.Block, ledger blockledger.ReadWriter) error
+}
+
 // Registrar serves as a point of access and control for the individual channel resources.
 type Registrar struct {
 	lock               sync.RWMutex
+	participationLock  sync.Mutex
 	chains             map[string]*ChainSupport
 	config             localconfig.TopLevel
 	consenters         map[string]consensus.Consenter
@@ -103,6 +115,7 @@ type Registrar struct {
 	systemChannel      *ChainSupport
 	templator          msgprocessor.ChannelConfigTemplator
 	callbacks          []channelconfig.BundleActor
+	replicator         ChannelReplicator
 }
 
 // ConfigBlock retrieves the last configuration block from the given ledger.
@@ -174,6 +187,7 @@ func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 			)
 			r.templator = msgprocessor.NewDefaultTemplator(chain)
 			chain.Processor = msgprocessor.NewSystemChannel(chain, r.templator, msgprocessor.CreateSystemChannelFilters(r, chain, r.config))
+			chain.dedupFilter, chain.committedProcessor = nil, nil
 
 			// Retrieve genesis block to log its hash. See FAB-5450 for the purpose
 			iter, pos := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})
@@ -209,10 +223,23 @@ func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 	}
 
 	if r.systemChannelID == "" {
+		if r.config.ChannelParticipation.Enabled {
+			logger.Infof("No system channel found, the channels are managed through the channel participation API")
+			return
+		}
 		logger.Panicf("No system chain found.  If bootstrapping, does your system channel contain a consortiums group definition?")
 	}
 }
 
+// SetChannelReplicator sets the ChannelReplicator used to join channels with a config
+// block other than the genesis block.
+func (r *Registrar) SetChannelReplicator(replicator ChannelReplicator) {
+	r.participationLock.Lock()
+	defer r.participationLock.Unlock()
+
+	r.replicator = replicator
+}
+
 // SystemChannelID returns the ChannelID for the system channel.
 func (r *Registrar) SystemChannelID() string {
 	return r.systemChannelID
@@ -230,6 +257,9 @@ func (r *Registrar) BroadcastChannelSupport(msg *cb.Envelope) (*cb.ChannelHeader
 	cs := r.GetChain(chdr.ChannelId)
 	// New channel creation
 	if cs == nil {
+		if r.systemChannel == nil {
+			return nil, false, nil, errors.Errorf("channel creation request not allowed because the orderer system channel is not defined")
+		}
 		cs = r.systemChannel
 	}
 
@@ -335,6 +365,246 @@ func (r *Registrar) newChain(configtx *cb.Envelope) {
 	r.chains = newChains
 }
 
+// ChannelList returns the names of the channels of the orderer.
+func (r *Registrar) ChannelList() types.ChannelList {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	list := types.ChannelList{}
+	for chainID := range r.chains {
+		if chainID == r.systemChannelID {
+			list.SystemChannel = &types.ChannelInfoShort{Name: chainID}
+			continue
+		}
+		list.Channels = append(list.Channels, types.ChannelInfoShort{Name: chainID})
+	}
+	sort.Slice(list.Channels, func(i, j int) bool { return list.Channels[i].Name < list.Channels[j].Name })
+
+	return list
+}
+
+// ChannelInfo returns the status and the height of the given channel.
+func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
+	cs := r.GetChain(channelID)
+	if cs == nil {
+		return types.ChannelInfo{}, types.ErrChannelNotExist
+	}
+	return channelInfo(cs), nil
+}
+
+func channelInfo(cs *ChainSupport) types.ChannelInfo {
+	info := types.ChannelInfo{
+		Name:   cs.ChainID(),
+		Status: types.StatusActive,
+		Height: cs.Height(),
+	}
+	if _, ok := cs.Chain.(*inactive.Chain); ok {
+		info.Status = types.StatusInactive
+	}
+	return info
+}
+
+// JoinChannel creates the given channel from the given config block and starts it.
+// If the config block is not the genesis block of the channel, the blocks that precede it are pulled
+// from the orderers of the channel. Channels can be joined only if the orderer has no system channel.
+func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block) (types.ChannelInfo, error) {
+	r.participationLock.Lock()
+	defer r.participationLock.Unlock()
+
+	if r.systemChannelID != "" {
+		return types.ChannelInfo{}, types.ErrSystemChannelExists
+	}
+	if r.GetChain(channelID) != nil {
+		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+	}
+	if err := r.validateJoinBlock(channelID, configBlock); err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "invalid config block")
+	}
+
+	ledger, err := r.ledgerFactory.GetOrCreate(channelID)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, fmt.Sprintf("failed to create the ledger of channel %s", channelID))
+	}
+	if ledger.Height() != 0 {
+		return types.ChannelInfo{}, errors.Errorf("the ledger of channel %s is not empty", channelID)
+	}
+	if err := r.appendJoinBlock(channelID, configBlock, ledger); err != nil {
+		if removeErr := r.ledgerFactory.Remove(channelID); removeErr != nil {
+			logger.Errorf("Failed to remove the ledger of channel %s: %s", channelID, removeErr)
+		}
+		return types.ChannelInfo{}, err
+	}
+
+	logger.Infof("Joining channel %s with config block number %d", channelID, configBlock.Header.Number)
+	r.newChain(configTx(ledger))
+
+	return channelInfo(r.GetChain(channelID)), nil
+}
+
+func (r *Registrar) validateJoinBlock(channelID string, configBlock *cb.Block) error {
+	if configBlock == nil || configBlock.Header == nil || configBlock.Data == nil {
+		return errors.New("block is empty")
+	}
+	if !utils.IsConfigBlock(configBlock) {
+		return errors.New("block is not a config block")
+	}
+	blockChannelID, err := utils.GetChainIDFromBlock(configBlock)
+	if err != nil {
+		return err
+	}
+	if blockChannelID != channelID {
+		return errors.Errorf("block is of channel %s, not %s", blockChannelID, channelID)
+	}
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(configBlock)
+	if err != nil {
+		return err
+	}
+	if lastConfig != configBlock.Header.Number {
+		return errors.Errorf("block number is %d but its last config index is %d", configBlock.Header.Number, lastConfig)
+	}
+
+	env, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return err
+	}
+	bundle, err := channelconfig.NewBundle(channelID, configEnv.Config)
+	if err != nil {
+		return err
+	}
+	if err := checkResources(bundle); err != nil {
+		return err
+	}
+	if _, ok := bundle.ConsortiumsConfig(); ok {
+		return errors.New("block contains a consortiums group, joining a system channel is not supported")
+	}
+	oc, _ := bundle.OrdererConfig()
+	if _, ok := r.consenters[oc.ConsensusType()]; !ok {
+		return errors.Errorf("consensus type %s is not supported", oc.ConsensusType())
+	}
+	return nil
+}
+
+func (r *Registrar) appendJoinBlock(channelID string, configBlock *cb.Block, ledger blockledger.ReadWriter) error {
+	if configBlock.Header.Number > 0 {
+		if r.replicator == nil {
+			return errors.Errorf("joining channel %s with config block number %d requires block replication, which is not available",
+				channelID, configBlock.Header.Number)
+		}
+		if err := r.replicator.ReplicateUntil(configBlock, ledger); err != nil {
+			return errors.WithMessage(err, "failed to replicate the blocks preceding the config block")
+		}
+	}
+	return ledger.Append(configBlock)
+}
+
+// RemoveChannel halts the given channel and removes its ledger, along with the
+// state the consenter keeps for it. Channels can be removed only if the orderer
+// has no system channel.
+func (r *Registrar) RemoveChannel(channelID string) error {
+	r.participationLock.Lock()
+	defer r.participationLock.Unlock()
+
+	if r.systemChannelID != "" {
+		return types.ErrSystemChannelExists
+	}
+	cs := r.GetChain(channelID)
+	if cs == nil {
+		return types.ErrChannelNotExist
+	}
+
+	consensusType := cs.SharedConfig().ConsensusType()
+	cs.Halt()
+	r.lock.Lock()
+	newChains := make(map[string]*ChainSupport)
+	for key, value := range r.chains {
+		if key != channelID {
+			newChains[key] = value
+		}
+	}
+	r.chains = newChains
+	r.lock.Unlock()
+
+	if remover, ok := r.consenters[consensusType].(consensus.ChainRemover); ok {
+		if err := remover.RemoveChain(channelID); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to remove the consenter state of channel %s", channelID))
+		}
+	}
+	if err := r.ledgerFactory.Remove(channelID); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to remove the ledger of channel %s", channelID))
+	}
+
+	logger.Infof("Removed channel %s", channelID)
+	return nil
+}
+
+// TransferLeadership transfers the leadership of the given channel away from this orderer, and waits
+// for the transfer to complete. The consensus of the channel must elect a leader.
+func (r *Registrar) TransferLeadership(channelID string) (types.LeadershipTransfer, error) {
+	cs := r.GetChain(channelID)
+	if cs == nil {
+		return types.LeadershipTransfer{}, types.ErrChannelNotExist
+	}
+	transferer, ok := cs.Chain.(consensus.LeadershipTransferer)
+	if !ok {
+		return types.LeadershipTransfer{}, types.ErrLeadershipTransferNotSupported
+	}
+
+	leader, err := transferer.TransferLeadership()
+	if err != nil {
+		return types.LeadershipTransfer{}, errors.WithMessage(err, fmt.Sprintf("failed to transfer leadership of channel %s", channelID))
+	}
+	return types.LeadershipTransfer{Channel: channelID, Leader: leader}, nil
+}
+
+// TransferAllLeaderships transfers the leadership of all the channels whose consensus elects a leader
+// away from this orderer, concurrently, and waits for the transfers to complete. The outcomes are
+// sorted by channel name.
+func (r *Registrar) TransferAllLeaderships() []types.LeadershipTransfer {
+	r.lock.RLock()
+	transferers := make(map[string]consensus.LeadershipTransferer)
+	for chainID, cs := range r.chains {
+		if transferer, ok := cs.Chain.(consensus.LeadershipTransferer); ok {
+			transferers[chainID] = transferer
+		}
+	}
+	r.lock.RUnlock()
+
+	var wg sync.WaitGroup
+	results := make(chan types.LeadershipTransfer, len(transferers))
+	for chainID, transferer := range transferers {
+		wg.Add(1)
+		go func(chainID string, transferer consensus.LeadershipTransferer) {
+			defer wg.Done()
+			result := types.LeadershipTransfer{Channel: chainID}
+			leader, err := transferer.TransferLeadership()
+			if err != nil {
+				logger.Warningf("Failed to transfer leadership of channel %s: %s", chainID, err)
+				result.Error = err.Error()
+			}
+			result.Leader = leader
+			results <- result
+		}(chainID, transferer)
+	}
+	wg.Wait()
+	close(results)
+
+	var transfers []types.LeadershipTransfer
+	for result := range results {
+		transfers = append(transfers, result)
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].Channel < transfers[j].Channel })
+
+	return transfers
+}
+
 // ChannelsCount returns the count of the current total number of channels.
 func (r *Registrar) ChannelsCount() int {
 	r.lock.RLock()
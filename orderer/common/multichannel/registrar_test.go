@@ -22,6 +22,7 @@ import (
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -390,3 +391,164 @@ func TestBroadcastChannelSupportRejection(t *testing.T) {
 		assert.Error(t, err, "Messages of type HeaderType_CONFIG should return an error.")
 	})
 }
+
+type mockChainRemover struct {
+	mockConsenter
+	removed []string
+}
+
+func (mcr *mockChainRemover) RemoveChain(chainID string) error {
+	mcr.removed = append(mcr.removed, chainID)
+	return nil
+}
+
+type mockChannelReplicator struct {
+	source blockledger.Reader
+	err    error
+}
+
+func (mcr *mockChannelReplicator) ReplicateUntil(joinBlock *cb.Block, ledger blockledger.ReadWriter) error {
+	for i := uint64(0); i < joinBlock.Header.Number; i++ {
+		if err := ledger.Append(blockledger.GetBlock(mcr.source, i)); err != nil {
+			return err
+		}
+	}
+	return mcr.err
+}
+
+func TestChannelParticipation(t *testing.T) {
+	conf := localconfig.TopLevel{ChannelParticipation: localconfig.ChannelParticipation{Enabled: true}}
+	confSys := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+	genesisBlockSys := encoder.New(confSys).GenesisBlock()
+	confApp := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+	confApp.Consortiums = nil
+	genesisBlockApp := encoder.New(confApp).GenesisBlockForChannel("mychannel")
+
+	newRegistrar := func(consenter consensus.Consenter) (*Registrar, blockledger.Factory) {
+		lf := ramledger.New(10)
+		registrar := NewRegistrar(conf, lf, mockCrypto(), &disabled.Provider{})
+		registrar.Initialize(map[string]consensus.Consenter{confSys.Orderer.OrdererType: consenter})
+		return registrar, lf
+	}
+
+	t.Run("Without system channel", func(t *testing.T) {
+		consenter := &mockChainRemover{}
+		registrar, lf := newRegistrar(consenter)
+		assert.Equal(t, types.ChannelList{}, registrar.ChannelList())
+		_, err := registrar.ChannelInfo("mychannel")
+		assert.Equal(t, types.ErrChannelNotExist, err)
+		_, _, _, err = registrar.BroadcastChannelSupport(makeConfigTx("mychannel", 1))
+		assert.EqualError(t, err, "channel creation request not allowed because the orderer system channel is not defined")
+
+		info, err := registrar.JoinChannel("mychannel", genesisBlockApp)
+		assert.NoError(t, err)
+		assert.Equal(t, types.ChannelInfo{Name: "mychannel", Status: types.StatusActive, Height: 1}, info)
+		assert.Equal(t, types.ChannelList{Channels: []types.ChannelInfoShort{{Name: "mychannel"}}}, registrar.ChannelList())
+		info, err = registrar.ChannelInfo("mychannel")
+		assert.NoError(t, err)
+		assert.Equal(t, "mychannel", info.Name)
+		chain := registrar.GetChain("mychannel")
+		assert.NotNil(t, chain)
+
+		_, err = registrar.JoinChannel("mychannel", genesisBlockApp)
+		assert.Equal(t, types.ErrChannelAlreadyExists, err)
+
+		assert.NoError(t, registrar.RemoveChannel("mychannel"))
+		assert.Nil(t, registrar.GetChain("mychannel"))
+		assert.Empty(t, lf.ChainIDs())
+		assert.Equal(t, []string{"mychannel"}, consenter.removed)
+		// the chain is halted
+		_, ok := <-chain.Chain.(*mockChain).queue
+		assert.False(t, ok)
+		assert.Equal(t, types.ErrChannelNotExist, registrar.RemoveChannel("mychannel"))
+	})
+
+	t.Run("Invalid config block", func(t *testing.T) {
+		registrar, lf := newRegistrar(&mockConsenter{})
+
+		_, err := registrar.JoinChannel("mychannel", &cb.Block{})
+		assert.EqualError(t, err, "invalid config block: block is empty")
+		_, err = registrar.JoinChannel("yourchannel", genesisBlockApp)
+		assert.EqualError(t, err, "invalid config block: block is of channel mychannel, not yourchannel")
+		_, err = registrar.JoinChannel(genesisconfig.TestChainID, genesisBlockSys)
+		assert.EqualError(t, err, "invalid config block: block contains a consortiums group, joining a system channel is not supported")
+		assert.Empty(t, lf.ChainIDs())
+	})
+
+	t.Run("Join with a config block other than the genesis block", func(t *testing.T) {
+		source := ramledger.New(10)
+		sourceLedger, err := source.GetOrCreate("mychannel")
+		assert.NoError(t, err)
+		assert.NoError(t, sourceLedger.Append(genesisBlockApp))
+		assert.NoError(t, sourceLedger.Append(blockledger.CreateNextBlock(sourceLedger, []*cb.Envelope{makeNormalTx("mychannel", 1)})))
+		joinBlock := blockledger.CreateNextBlock(sourceLedger, []*cb.Envelope{utils.ExtractEnvelopeOrPanic(genesisBlockApp, 0)})
+		joinBlock.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+			Value: utils.MarshalOrPanic(&cb.LastConfig{Index: 2}),
+		})
+
+		registrar, lf := newRegistrar(&mockConsenter{})
+		_, err = registrar.JoinChannel("mychannel", joinBlock)
+		assert.EqualError(t, err, "joining channel mychannel with config block number 2 requires block replication, which is not available")
+
+		registrar.SetChannelReplicator(&mockChannelReplicator{source: sourceLedger, err: errors.New("unreachable")})
+		_, err = registrar.JoinChannel("mychannel", joinBlock)
+		assert.EqualError(t, err, "failed to replicate the blocks preceding the config block: unreachable")
+		assert.Empty(t, lf.ChainIDs())
+
+		registrar.SetChannelReplicator(&mockChannelReplicator{source: sourceLedger})
+		info, err := registrar.JoinChannel("mychannel", joinBlock)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), info.Height)
+	})
+
+	t.Run("With system channel", func(t *testing.T) {
+		lf, _ := newRAMLedgerAndFactory(10, genesisconfig.TestChainID, genesisBlockSys)
+		registrar := NewRegistrar(conf, lf, mockCrypto(), &disabled.Provider{})
+		registrar.Initialize(map[string]consensus.Consenter{confSys.Orderer.OrdererType: &mockConsenter{}})
+
+		assert.Equal(t, types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: genesisconfig.TestChainID}}, registrar.ChannelList())
+		_, err := registrar.JoinChannel("mychannel", genesisBlockApp)
+		assert.Equal(t, types.ErrSystemChannelExists, err)
+		assert.Equal(t, types.ErrSystemChannelExists, registrar.RemoveChannel(genesisconfig.TestChainID))
+	})
+}
+
+func TestTransferLeadership(t *testing.T) {
+	confSys := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+	genesisBlockSys := encoder.New(confSys).GenesisBlock()
+	ledgerFactory, _ := newRAMLedgerAndFactory(10, genesisconfig.TestChainID, genesisBlockSys)
+	registrar := NewRegistrar(localconfig.TopLevel{}, ledgerFactory, mockCrypto(), &disabled.Provider{})
+	registrar.Initialize(map[string]consensus.Consenter{confSys.Orderer.OrdererType: &mockConsenter{}})
+
+	_, err := registrar.TransferLeadership("mychannel")
+	assert.Equal(t, types.ErrChannelNotExist, err)
+	_, err = registrar.TransferLeadership(genesisconfig.TestChainID)
+	assert.Equal(t, types.ErrLeadershipTransferNotSupported, err)
+	assert.Empty(t, registrar.TransferAllLeaderships())
+
+	cs := registrar.GetChain(genesisconfig.TestChainID)
+	chain := &mockLeadershipTransferer{Chain: cs.Chain, leader: 2}
+	cs.Chain = chain
+
+	transfer, err := registrar.TransferLeadership(genesisconfig.TestChainID)
+	assert.NoError(t, err)
+	assert.Equal(t, types.LeadershipTransfer{Channel: genesisconfig.TestChainID, Leader: 2}, transfer)
+	assert.Equal(t, []types.LeadershipTransfer{{Channel: genesisconfig.TestChainID, Leader: 2}}, registrar.TransferAllLeaderships())
+
+	chain.leader, chain.err = 0, errors.New("no follower is qualified as transferee")
+	_, err = registrar.TransferLeadership(genesisconfig.TestChainID)
+	assert.EqualError(t, err, "failed to transfer leadership of channel testchainid: no follower is qualified as transferee")
+	assert.Equal(t, []types.LeadershipTransfer{
+		{Channel: genesisconfig.TestChainID, Error: "no follower is qualified as transferee"},
+	}, registrar.TransferAllLeaderships())
+}
+
+type mockLeadershipTransferer struct {
+	consensus.Chain
+	leader uint64
+	err    error
+}
+
+func (mlt *mockLeadershipTransferer) TransferLeadership() (uint64, error) {
+	return mlt.leader, mlt.err
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -40,11 +41,15 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/leadershiptransfer"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/bft"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/kafka"
 	"github.com/hyperledger/fabric/orderer/consensus/solo"
@@ -65,8 +70,9 @@ var (
 	start     = app.Command("start", "Start the orderer node").Default()
 	version   = app.Command("version", "Show version information")
 	benchmark = app.Command("benchmark", "Run orderer in benchmark mode")
+	keygen    = app.Command("keygen", "Generate a key of the local BCCSP to encrypt the Raft WAL and snapshots with")
 
-	clusterTypes = map[string]struct{}{"etcdraft": {}}
+	clusterTypes = map[string]struct{}{"etcdraft": {}, "bft": {}}
 )
 
 // Main is the entry point of orderer process
@@ -87,6 +93,16 @@ func Main() {
 	initializeLogging()
 	initializeLocalMsp(conf)
 
+	// "keygen" command
+	if fullCmd == keygen.FullCommand() {
+		keyID, err := etcdraft.GenerateEncryptionKey(factory.GetDefault())
+		if err != nil {
+			logger.Fatal("Failed to generate encryption key:", err)
+		}
+		fmt.Println(keyID)
+		return
+	}
+
 	prettyPrintStruct(conf)
 	Start(fullCmd, conf)
 }
@@ -94,8 +110,10 @@ func Main() {
 // Start provides a layer of abstraction for benchmark test
 func Start(cmd string, conf *localconfig.TopLevel) {
 	bootstrapBlock := extractBootstrapBlock(conf)
-	if err := ValidateBootstrapBlock(bootstrapBlock); err != nil {
-		logger.Panicf("Failed validating bootstrap block: %v", err)
+	if bootstrapBlock != nil {
+		if err := ValidateBootstrapBlock(bootstrapBlock); err != nil {
+			logger.Panicf("Failed validating bootstrap block: %v", err)
+		}
 	}
 
 	opsSystem := newOperationsSystem(conf.Operations, conf.Metrics)
@@ -106,11 +124,24 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 	defer opsSystem.Stop()
 	metricsProvider := opsSystem.Provider
 
-	lf, _ := createLedgerFactory(conf, metricsProvider)
-	sysChanLastConfigBlock := extractSysChanLastConfig(lf, bootstrapBlock)
-	clusterBootBlock := selectClusterBootBlock(bootstrapBlock, sysChanLastConfigBlock)
-
-	clusterType := isClusterType(clusterBootBlock)
+	lf, _, err := createLedgerFactory(conf, metricsProvider)
+	if err != nil {
+		logger.Panicf("Failed creating the block ledger: %s", err)
+	}
+	var clusterBootBlock *cb.Block
+	var clusterType bool
+	if bootstrapBlock != nil {
+		sysChanLastConfigBlock := extractSysChanLastConfig(lf, bootstrapBlock)
+		clusterBootBlock = selectClusterBootBlock(bootstrapBlock, sysChanLastConfigBlock)
+		clusterType = isClusterType(clusterBootBlock)
+	} else {
+		// Without a system channel, the channels are joined through the channel participation API,
+		// and the node takes part in a cluster whenever it is able to, i.e. when TLS is enabled.
+		clusterType = conf.General.TLS.Enabled
+		if !clusterType {
+			logger.Warningf("TLS is disabled, channels of type etcdraft cannot be joined")
+		}
+	}
 	signer := localmsp.NewSigner()
 
 	clusterClientConfig := initializeClusterClientConfig(conf, clusterType, bootstrapBlock)
@@ -177,11 +208,26 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 	manager := initializeMultichannelRegistrar(clusterBootBlock, r, clusterDialer, clusterServerConfig, clusterGRPCServer, conf, signer, metricsProvider, opsSystem, lf, tlsCallback)
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
 	expiration := conf.General.Authentication.NoExpirationChecks
-	server := NewServer(manager, metricsProvider, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS, expiration)
+	server := NewServer(manager, metricsProvider, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS, expiration, newFairQueue(conf.General.BroadcastQuota))
+
+	if conf.ChannelParticipation.Enabled {
+		if clusterType {
+			manager.SetChannelReplicator(r)
+		}
+		opsSystem.RegisterHandler(channelparticipation.URLBaseV1, channelparticipation.NewHTTPHandler(conf.ChannelParticipation, manager))
+		logger.Infof("Channel participation API is enabled at %s", channelparticipation.URLBaseV1)
+	}
+
+	if clusterType {
+		opsSystem.RegisterHandler(leadershiptransfer.URLBaseV1, leadershiptransfer.NewHTTPHandler(manager))
+	}
 
 	logger.Infof("Starting %s", metadata.GetVersionInfo())
 	go handleSignals(addPlatformSignals(map[os.Signal]func(){
 		syscall.SIGTERM: func() {
+			if clusterType {
+				transferLeaderships(manager)
+			}
 			grpcServer.Stop()
 			if clusterGRPCServer != grpcServer {
 				clusterGRPCServer.Stop()
@@ -261,15 +307,16 @@ func createReplicator(
 		logger:        logger,
 	}
 
-	systemChannelName, err := utils.GetChainIDFromBlock(bootstrapBlock)
-	if err != nil {
-		logger.Panicf("Failed extracting system channel name from bootstrap block: %v", err)
-	}
-
 	// System channel is not verified because we trust the bootstrap block
 	// and use backward hash chain verification.
 	verifiersByChannel := vl.loadVerifiers()
-	verifiersByChannel[systemChannelName] = &cluster.NoopBlockVerifier{}
+	if bootstrapBlock != nil {
+		systemChannelName, err := utils.GetChainIDFromBlock(bootstrapBlock)
+		if err != nil {
+			logger.Panicf("Failed extracting system channel name from bootstrap block: %v", err)
+		}
+		verifiersByChannel[systemChannelName] = &cluster.NoopBlockVerifier{}
+	}
 
 	vr := &cluster.VerificationRegistry{
 		LoadVerifier:       vl.loadVerifier,
@@ -314,6 +361,19 @@ func initializeProfilingService(conf *localconfig.TopLevel) {
 	}
 }
 
+// transferLeaderships moves the leadership of the channels away from this orderer
+// before it stops, so that the channels keep ordering without an election gap.
+func transferLeaderships(manager *multichannel.Registrar) {
+	logger.Info("Transferring leadership of the channels before stopping")
+	for _, transfer := range manager.TransferAllLeaderships() {
+		if transfer.Error != "" {
+			logger.Warningf("Failed to transfer leadership of channel %s: %s", transfer.Channel, transfer.Error)
+			continue
+		}
+		logger.Infof("Leader of channel %s is %d", transfer.Channel, transfer.Leader)
+	}
+}
+
 func handleSignals(handlers map[os.Signal]func()) {
 	var signals []os.Signal
 	for sig := range handlers {
@@ -546,6 +606,8 @@ func extractBootstrapBlock(conf *localconfig.TopLevel) *cb.Block {
 		bootstrapBlock = encoder.New(genesisconfig.Load(conf.General.GenesisProfile)).GenesisBlockForChannel(conf.General.SystemChannel)
 	case "file":
 		bootstrapBlock = file.New(conf.General.GenesisFile).GenesisBlock()
+	case "none":
+		logger.Info("Starting without a system channel")
 	default:
 		logger.Panic("Unknown genesis method:", conf.General.GenesisMethod)
 	}
@@ -592,6 +654,35 @@ func consensusType(genesisBlock *cb.Block) string {
 	return ordConf.ConsensusType()
 }
 
+func newFairQueue(quota localconfig.BroadcastQuota) *broadcast.FairQueue {
+	if !quota.Enabled {
+		return nil
+	}
+
+	fq := &broadcast.FairQueue{
+		Default: broadcast.Quota{
+			QueueSize: quota.QueueSize,
+			Rate:      quota.Rate,
+			Burst:     quota.Burst,
+		},
+		Organizations: map[string]broadcast.Quota{},
+	}
+	for _, org := range quota.Organizations {
+		orgQuota := fq.Default
+		if org.QueueSize != 0 {
+			orgQuota.QueueSize = org.QueueSize
+		}
+		if org.Rate != 0 {
+			orgQuota.Rate = org.Rate
+		}
+		if org.Burst != 0 {
+			orgQuota.Burst = org.Burst
+		}
+		fq.Organizations[org.MSPID] = orgQuota
+	}
+	return fq
+}
+
 func initializeGrpcServer(conf *localconfig.TopLevel, serverConfig comm.ServerConfig) *comm.GRPCServer {
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort))
 	if err != nil {
@@ -637,7 +728,9 @@ func initializeMultichannelRegistrar(
 ) *multichannel.Registrar {
 	genesisBlock := extractBootstrapBlock(conf)
 	// Are we bootstrapping?
-	if len(lf.ChainIDs()) == 0 {
+	if genesisBlock == nil {
+		logger.Info("Not bootstrapping because there is no system channel")
+	} else if len(lf.ChainIDs()) == 0 {
 		initializeBootstrapChannel(genesisBlock, lf)
 	} else {
 		logger.Info("Not bootstrapping because of existing channels")
@@ -653,8 +746,16 @@ func initializeMultichannelRegistrar(
 	// Note, we pass a 'nil' channel here, we could pass a channel that
 	// closes if we wished to cleanup this routine on exit.
 	go kafkaMetrics.PollGoMetricsUntilStop(time.Minute, nil)
-	if isClusterType(bootstrapBlock) {
+	// Without a system channel, etcdraft channels can be joined as long as TLS is enabled
+	clusterType := conf.General.TLS.Enabled
+	if bootstrapBlock != nil {
+		clusterType = isClusterType(bootstrapBlock)
+	}
+	if clusterType {
 		initializeEtcdraftConsenter(consenters, conf, lf, clusterDialer, bootstrapBlock, ri, srvConf, srv, registrar, metricsProvider)
+		// BFT chains communicate through the cluster service of the etcdraft consenter
+		raftConsenter := consenters["etcdraft"].(*etcdraft.Consenter)
+		consenters["bft"] = bft.New(clusterDialer, raftConsenter.Communication, conf, srvConf)
 	}
 	registrar.Initialize(consenters)
 	return registrar
@@ -677,6 +778,13 @@ func initializeEtcdraftConsenter(
 		replicationRefreshInterval = defaultReplicationBackgroundRefreshInterval
 	}
 
+	// Without a system channel there is no way to learn about the channels this node
+	// is added to, and the inactive chains are not tracked.
+	if bootstrapBlock == nil {
+		consenters["etcdraft"] = etcdraft.New(clusterDialer, conf, srvConf, srv, registrar, nil, metricsProvider)
+		return
+	}
+
 	systemChannelName, err := utils.GetChainIDFromBlock(bootstrapBlock)
 	if err != nil {
 		ri.logger.Panicf("Failed extracting system channel name from bootstrap block: %v", err)
@@ -33,6 +33,7 @@ import (
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
@@ -87,6 +88,25 @@ func TestInitializeProfilingService(t *testing.T) {
 	}
 }
 
+func TestNewFairQueue(t *testing.T) {
+	assert.Nil(t, newFairQueue(localconfig.BroadcastQuota{QueueSize: 10}))
+
+	fq := newFairQueue(localconfig.BroadcastQuota{
+		Enabled:   true,
+		QueueSize: 10,
+		Rate:      100,
+		Organizations: []localconfig.OrganizationQuota{
+			{MSPID: "Org1MSP", Rate: 500, Burst: 50},
+			{MSPID: "Org2MSP", QueueSize: 1},
+		},
+	})
+	assert.Equal(t, broadcast.Quota{QueueSize: 10, Rate: 100}, fq.Default)
+	assert.Equal(t, map[string]broadcast.Quota{
+		"Org1MSP": {QueueSize: 10, Rate: 500, Burst: 50},
+		"Org2MSP": {QueueSize: 1, Rate: 100},
+	}, fq.Organizations)
+}
+
 func TestInitializeServerConfig(t *testing.T) {
 	conf := &localconfig.TopLevel{
 		General: localconfig.General{
@@ -204,7 +224,7 @@ func TestInitializeBootstrapChannel(t *testing.T) {
 		t.Run(tc.genesisMethod+"/"+tc.ledgerType, func(t *testing.T) {
 
 			fileLedgerLocation, _ := ioutil.TempDir("", "test-ledger")
-			ledgerFactory, _ := createLedgerFactory(
+			ledgerFactory, _, err := createLedgerFactory(
 				&localconfig.TopLevel{
 					General: localconfig.General{LedgerType: tc.ledgerType},
 					FileLedger: localconfig.FileLedger{
@@ -213,6 +233,7 @@ func TestInitializeBootstrapChannel(t *testing.T) {
 				},
 				&disabled.Provider{},
 			)
+			assert.NoError(t, err)
 
 			bootstrapConfig := &localconfig.TopLevel{
 				General: localconfig.General{
@@ -346,12 +367,30 @@ func TestInitializeMultiChainManager(t *testing.T) {
 	conf := genesisConfig(t)
 	assert.NotPanics(t, func() {
 		initializeLocalMsp(conf)
-		lf, _ := createLedgerFactory(conf, &disabled.Provider{})
+		lf, _, err := createLedgerFactory(conf, &disabled.Provider{})
+		assert.NoError(t, err)
 		bootBlock := encoder.New(genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel("system")
 		initializeMultichannelRegistrar(bootBlock, &replicationInitiator{}, &cluster.PredicateDialer{}, comm.ServerConfig{}, nil, conf, localmsp.NewSigner(), &disabled.Provider{}, &mocks.HealthChecker{}, lf)
 	})
 }
 
+func TestInitializeMultiChainManagerWithoutSystemChannel(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	conf := genesisConfig(t)
+	conf.General.GenesisMethod = "none"
+	conf.ChannelParticipation.Enabled = true
+	initializeLocalMsp(conf)
+	lf, _, err := createLedgerFactory(conf, &disabled.Provider{})
+	assert.NoError(t, err)
+
+	assert.Nil(t, extractBootstrapBlock(conf))
+	registrar := initializeMultichannelRegistrar(nil, &replicationInitiator{}, &cluster.PredicateDialer{}, comm.ServerConfig{}, nil, conf, localmsp.NewSigner(), &disabled.Provider{}, &mocks.HealthChecker{}, lf)
+	assert.Empty(t, lf.ChainIDs())
+	assert.Equal(t, "", registrar.SystemChannelID())
+	assert.Empty(t, registrar.ChannelList().Channels)
+}
+
 func TestInitializeGrpcServer(t *testing.T) {
 	// get a free random port
 	listenAddr := func() string {
@@ -409,7 +448,8 @@ func TestUpdateTrustedRoots(t *testing.T) {
 			updateTrustedRoots(caSupport, bundle, grpcServer)
 		}
 	}
-	lf, _ := createLedgerFactory(conf, &disabled.Provider{})
+	lf, _, err := createLedgerFactory(conf, &disabled.Provider{})
+	assert.NoError(t, err)
 	bootBlock := encoder.New(genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel("system")
 	initializeMultichannelRegistrar(bootBlock, &replicationInitiator{}, &cluster.PredicateDialer{}, comm.ServerConfig{}, nil, genesisConfig(t), localmsp.NewSigner(), &disabled.Provider{}, &mocks.HealthChecker{}, lf, callback)
 	t.Logf("# app CAs: %d", len(caSupport.AppRootCAsByChain[genesisconfig.TestChainID]))
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package server
 
 import (
+	"bytes"
 	"sync"
 	"time"
 
@@ -120,6 +121,53 @@ func (ri *replicationInitiator) ReplicateChains(lastConfigBlock *common.Block, c
 	return replicator.ReplicateChains()
 }
 
+// ReplicateUntil pulls the blocks of the channel of the given join block that precede it, and appends them
+// to the given ledger. The signatures of the blocks are not verified, as the join block is trusted and the
+// blocks that precede it are verified through the hash chain that leads to it.
+func (ri *replicationInitiator) ReplicateUntil(joinBlock *common.Block, ledger blockledger.ReadWriter) error {
+	channel, err := utils.GetChainIDFromBlock(joinBlock)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting the channel name from the join block")
+	}
+	pullerConfig := cluster.PullerConfigFromTopLevelConfig(channel, ri.conf, ri.secOpts.Key, ri.secOpts.Certificate, ri.signer)
+	puller, err := cluster.BlockPullerFromConfigBlock(pullerConfig, joinBlock, &noopVerifierRetriever{})
+	if err != nil {
+		return errors.WithMessage(err, "failed creating a block puller from the join block")
+	}
+	puller.MaxPullBlockRetries = uint64(ri.conf.General.Cluster.ReplicationMaxRetries)
+	puller.RetryTimeout = ri.conf.General.Cluster.ReplicationRetryTimeout
+	defer puller.Close()
+
+	ri.logger.Infof("Pulling blocks [0, %d) of channel %s", joinBlock.Header.Number, channel)
+	var actualPrevHash []byte
+	for seq := ledger.Height(); seq < joinBlock.Header.Number; seq++ {
+		block := puller.PullBlock(seq)
+		if block == nil {
+			return errors.Wrapf(cluster.ErrRetryCountExhausted, "failed pulling block [%d] of channel %s", seq, channel)
+		}
+		if actualPrevHash != nil && !bytes.Equal(block.Header.PreviousHash, actualPrevHash) {
+			return errors.Errorf("block header mismatch on sequence %d, expected %x, got %x", seq, actualPrevHash, block.Header.PreviousHash)
+		}
+		actualPrevHash = block.Header.Hash()
+		if err := ledger.Append(block); err != nil {
+			return errors.Wrapf(err, "failed appending block [%d] of channel %s", seq, channel)
+		}
+	}
+
+	if !bytes.Equal(joinBlock.Header.PreviousHash, actualPrevHash) {
+		return errors.Errorf("block header mismatch on the join block of channel %s, expected previous hash %x, got %x",
+			channel, actualPrevHash, joinBlock.Header.PreviousHash)
+	}
+	return nil
+}
+
+// noopVerifierRetriever retrieves verifiers that accept all block signatures
+type noopVerifierRetriever struct{}
+
+func (*noopVerifierRetriever) RetrieveVerifier(channel string) cluster.BlockVerifier {
+	return &cluster.NoopBlockVerifier{}
+}
+
 type ledgerFactory struct {
 	blockledger.Factory
 	onBlockCommit cluster.BlockCommitFunc
@@ -255,6 +303,9 @@ type Factory interface {
 	// ChainIDs returns the chain IDs the Factory is aware of
 	ChainIDs() []string
 
+	// Remove removes the ledger of the given chain
+	Remove(chainID string) error
+
 	// Close releases all resources acquired by the factory
 	Close()
 }
@@ -864,6 +864,107 @@ func TestLedgerFactory(t *testing.T) {
 	assert.Equal(t, uint64(0), lw.Height())
 }
 
+func TestReplicateUntil(t *testing.T) {
+	t.Parallel()
+
+	caCert := loadPEM("ca.crt", t)
+	key := loadPEM("server.key", t)
+	cert := loadPEM("server.crt", t)
+
+	deliverServer := newServerNode(t, key, cert)
+	defer deliverServer.srv.Stop()
+
+	genesisBlockBytes, err := ioutil.ReadFile(filepath.Join("testdata", "genesis.block"))
+	assert.NoError(t, err)
+	genesisBlock := &common.Block{}
+	assert.NoError(t, proto.Unmarshal(genesisBlockBytes, genesisBlock))
+	genesisBlock.Header.Number = 0
+	injectOrdererEndpoint(t, genesisBlock, deliverServer.srv.Address())
+
+	block1 := &common.Block{
+		Header: &common.BlockHeader{
+			Number:       1,
+			PreviousHash: genesisBlock.Header.Hash(),
+		},
+		Metadata: &common.BlockMetadata{
+			Metadata: [][]byte{{}, {}, {}, {}},
+		},
+		Data: &common.BlockData{
+			Data: [][]byte{utils.MarshalOrPanic(&common.Envelope{
+				Payload: utils.MarshalOrPanic(&common.Payload{
+					Header: &common.Header{
+						ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+							ChannelId: "testchainid",
+							Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+						}),
+					},
+				}),
+			})},
+		},
+	}
+	block1.Header.DataHash = block1.Data.Hash()
+
+	joinBlock := proto.Clone(genesisBlock).(*common.Block)
+	joinBlock.Header.Number = 2
+	joinBlock.Header.PreviousHash = block1.Header.Hash()
+
+	ri := &replicationInitiator{
+		logger: flogging.MustGetLogger("testReplicateUntil"),
+		conf: &localconfig.TopLevel{
+			General: localconfig.General{
+				Cluster: localconfig.Cluster{
+					ReplicationPullTimeout:  time.Hour,
+					DialTimeout:             time.Hour,
+					RPCTimeout:              time.Hour,
+					ReplicationRetryTimeout: time.Millisecond,
+					ReplicationBufferSize:   1,
+					ReplicationMaxRetries:   5,
+				},
+			},
+		},
+		secOpts: &comm.SecureOptions{
+			Certificate:   cert,
+			Key:           key,
+			UseTLS:        true,
+			ServerRootCAs: [][]byte{caCert},
+		},
+	}
+
+	sendBlocks := func(blocks ...*common.Block) {
+		// The height of the channel is probed first, and then the blocks are pulled
+		deliverServer.blockResponses <- &orderer.DeliverResponse{
+			Type: &orderer.DeliverResponse_Block{Block: blocks[len(blocks)-1]},
+		}
+		for _, block := range blocks {
+			deliverServer.blockResponses <- &orderer.DeliverResponse{
+				Type: &orderer.DeliverResponse_Block{Block: block},
+			}
+		}
+	}
+
+	t.Run("Replicated", func(t *testing.T) {
+		ledger, err := ramledger.New(10).GetOrCreate("testchainid")
+		assert.NoError(t, err)
+		sendBlocks(genesisBlock, block1)
+
+		assert.NoError(t, ri.ReplicateUntil(joinBlock, ledger))
+		assert.Equal(t, uint64(2), ledger.Height())
+		deliverServer.blockResponses <- nil
+	})
+
+	t.Run("Hash chain mismatch", func(t *testing.T) {
+		ledger, err := ramledger.New(10).GetOrCreate("testchainid")
+		assert.NoError(t, err)
+		sendBlocks(genesisBlock, block1)
+
+		forgedJoinBlock := proto.Clone(joinBlock).(*common.Block)
+		forgedJoinBlock.Header.PreviousHash = genesisBlock.Header.Hash()
+		err = ri.ReplicateUntil(forgedJoinBlock, ledger)
+		assert.Contains(t, err.Error(), "block header mismatch on the join block of channel testchainid")
+		deliverServer.blockResponses <- nil
+	})
+}
+
 func injectConsenterCertificate(t *testing.T, block *common.Block, tlsCert []byte) {
 	env, err := utils.ExtractEnvelope(block, 0)
 	assert.NoError(t, err)
@@ -79,6 +79,7 @@ func NewServer(
 	timeWindow time.Duration,
 	mutualTLS bool,
 	expirationCheckDisabled bool,
+	fairQueue *broadcast.FairQueue,
 ) ab.AtomicBroadcastServer {
 	s := &server{
 		dh: deliver.NewHandler(
@@ -91,6 +92,7 @@ func NewServer(
 		bh: &broadcast.Handler{
 			SupportRegistrar: broadcastSupport{Registrar: r},
 			Metrics:          broadcast.NewMetrics(metricsProvider),
+			FairQueue:        fairQueue,
 		},
 		debug:     debug,
 		Registrar: r,
@@ -10,47 +10,106 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	compactedledger "github.com/hyperledger/fabric/common/ledger/blockledger/compacted"
 	fileledger "github.com/hyperledger/fabric/common/ledger/blockledger/file"
 	jsonledger "github.com/hyperledger/fabric/common/ledger/blockledger/json"
 	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
 	"github.com/hyperledger/fabric/common/metrics"
 	config "github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/pkg/errors"
 )
 
-func createLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string) {
-	var lf blockledger.Factory
-	var ld string
-	switch conf.General.LedgerType {
-	case "file":
-		ld = conf.FileLedger.Location
-		if ld == "" {
-			ld = createTempDir(conf.FileLedger.Prefix)
-		}
-		logger.Debug("Ledger dir:", ld)
-		lf = fileledger.New(ld, metricsProvider)
-		// The file-based ledger stores the blocks for each channel
-		// in a fsblkstorage.ChainsDir sub-directory that we have
-		// to create separately. Otherwise the call to the ledger
-		// Factory's ChainIDs below will fail (dir won't exist).
-		createSubDir(ld, fsblkstorage.ChainsDir)
-	case "json":
-		ld = conf.FileLedger.Location
-		if ld == "" {
-			ld = createTempDir(conf.FileLedger.Prefix)
+// LedgerFactoryConstructor creates a block ledger factory from the
+// configuration of the orderer, and returns the directory of the ledger,
+// if any.
+type LedgerFactoryConstructor func(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string)
+
+var ledgerFactories = map[string]LedgerFactoryConstructor{
+	"file":      newFileLedgerFactory,
+	"json":      newJSONLedgerFactory,
+	"ram":       newRAMLedgerFactory,
+	"compacted": newCompactedLedgerFactory,
+}
+
+// RegisterLedgerFactory registers a block ledger factory, which is used when
+// the General.LedgerType of the orderer is the given ledger type. It must be
+// called before the orderer is started.
+func RegisterLedgerFactory(ledgerType string, constructor LedgerFactoryConstructor) {
+	ledgerFactories[ledgerType] = constructor
+}
+
+// createLedgerFactory creates the block ledger factory of the type set in General.LedgerType.
+// An error listing the accepted ledger types is returned if the type is unknown
+func createLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string, error) {
+	ledgerType := conf.General.LedgerType
+	if ledgerType == "" {
+		ledgerType = "ram"
+	}
+	constructor, ok := ledgerFactories[ledgerType]
+	if !ok {
+		var accepted []string
+		for name := range ledgerFactories {
+			accepted = append(accepted, name)
 		}
-		logger.Debug("Ledger dir:", ld)
-		lf = jsonledger.New(ld)
-	case "ram":
-		fallthrough
-	default:
-		lf = ramledger.New(int(conf.RAMLedger.HistorySize))
+		sort.Strings(accepted)
+		return nil, "", errors.Errorf("unknown ledger type [%s] set in General.LedgerType, the accepted ledger types are: %s",
+			ledgerType, strings.Join(accepted, ", "))
 	}
+	lf, ld := constructor(conf, metricsProvider)
+	return lf, ld, nil
+}
+
+func newFileLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string) {
+	ld := ledgerDir(conf)
+	lf := fileledger.New(ld, metricsProvider)
+	// The file-based ledger stores the blocks for each channel
+	// in a fsblkstorage.ChainsDir sub-directory that we have
+	// to create separately. Otherwise the call to the ledger
+	// Factory's ChainIDs below will fail (dir won't exist).
+	createSubDir(ld, fsblkstorage.ChainsDir)
 	return lf, ld
 }
 
+func newJSONLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string) {
+	ld := ledgerDir(conf)
+	return jsonledger.New(ld), ld
+}
+
+func newRAMLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string) {
+	return ramledger.New(int(conf.RAMLedger.HistorySize)), ""
+}
+
+func newCompactedLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string) {
+	ld := ledgerDir(conf)
+	var archive compactedledger.Archive
+	if conf.CompactedLedger.ArchiveLocation != "" {
+		dirArchive, err := compactedledger.NewDirArchive(conf.CompactedLedger.ArchiveLocation)
+		if err != nil {
+			logger.Panicf("Failed creating ledger archive: %s", err)
+		}
+		archive = dirArchive
+	} else {
+		logger.Warningf("CompactedLedger.ArchiveLocation unset, blocks older than the last %d blocks will be discarded", conf.CompactedLedger.Retention)
+	}
+	return compactedledger.New(ld, conf.CompactedLedger.Retention, archive), ld
+}
+
+// ledgerDir returns the directory of the ledger, which is a new
+// temporary directory if the location of the ledger is unset.
+func ledgerDir(conf *config.TopLevel) string {
+	ld := conf.FileLedger.Location
+	if ld == "" {
+		ld = createTempDir(conf.FileLedger.Prefix)
+	}
+	logger.Debug("Ledger dir:", ld)
+	return ld
+}
+
 func createTempDir(dirPrefix string) string {
 	dirPath, err := ioutil.TempDir("", dirPrefix)
 	if err != nil {
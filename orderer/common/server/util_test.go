@@ -11,6 +11,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	config "github.com/hyperledger/fabric/orderer/common/localconfig"
@@ -25,13 +28,16 @@ func TestCreateLedgerFactory(t *testing.T) {
 		ledgerType      string
 		ledgerDir       string
 		ledgerDirPrefix string
-		expectPanic     bool
+		expectErr       bool
 	}{
 		{"RAM", "ram", "", "", false},
 		{"JSONwithPathSet", "json", "test-dir", "", false},
 		{"JSONwithPathUnset", "json", "", "test-prefix", false},
 		{"FilewithPathSet", "file", filepath.Join(os.TempDir(), "test-dir"), "", false},
 		{"FilewithPathUnset", "file", "", "test-prefix", false},
+		{"CompactedwithPathSet", "compacted", filepath.Join(os.TempDir(), "test-compacted-dir"), "", false},
+		{"CompactedwithPathUnset", "compacted", "", "test-prefix", false},
+		{"Unknown", "unknown", "", "", true},
 	}
 
 	conf, err := config.Load()
@@ -41,20 +47,15 @@ func TestCreateLedgerFactory(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			defer func() {
-				r := recover()
-				if tc.expectPanic && r == nil {
-					t.Fatal("Should have panicked")
-				}
-				if !tc.expectPanic && r != nil {
-					t.Fatal("Should not have panicked")
-				}
-			}()
-
 			conf.General.LedgerType = tc.ledgerType
 			conf.FileLedger.Location = tc.ledgerDir
 			conf.FileLedger.Prefix = tc.ledgerDirPrefix
-			lf, ld := createLedgerFactory(conf, &disabled.Provider{})
+			lf, ld, err := createLedgerFactory(conf, &disabled.Provider{})
+			if tc.expectErr {
+				assert.EqualError(t, err, "unknown ledger type [unknown] set in General.LedgerType, the accepted ledger types are: compacted, file, json, ram")
+				return
+			}
+			assert.NoError(t, err)
 
 			defer func() {
 				if ld != "" {
@@ -67,6 +68,25 @@ func TestCreateLedgerFactory(t *testing.T) {
 	}
 }
 
+func TestRegisterLedgerFactory(t *testing.T) {
+	defer delete(ledgerFactories, "custom")
+
+	lf := ramledger.New(10)
+	RegisterLedgerFactory("custom", func(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string) {
+		return lf, "custom-dir"
+	})
+
+	conf := &config.TopLevel{General: config.General{LedgerType: "custom"}}
+	createdFactory, ld, err := createLedgerFactory(conf, &disabled.Provider{})
+	assert.NoError(t, err)
+	assert.Equal(t, lf, createdFactory)
+	assert.Equal(t, "custom-dir", ld)
+
+	conf.General.LedgerType = "unknown"
+	_, _, err = createLedgerFactory(conf, &disabled.Provider{})
+	assert.EqualError(t, err, "unknown ledger type [unknown] set in General.LedgerType, the accepted ledger types are: compacted, custom, file, json, ram")
+}
+
 func TestCreateSubDir(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package types
+
+// ChannelList carries the response to an HTTP request to list all the channels.
+// This is marshaled into the body of the HTTP response.
+type ChannelList struct {
+	// The system channel info, nil if it doesn't exist.
+	SystemChannel *ChannelInfoShort `json:"systemChannel"`
+	// Application channels only, nil or empty if no channels defined.
+	Channels []ChannelInfoShort `json:"channels"`
+}
+
+// ChannelInfoShort carries a short info of a single channel.
+type ChannelInfoShort struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The channel relative URL (no Host:Port, only path), e.g.: "/participation/v1/channels/my-channel".
+	URL string `json:"url"`
+}
+
+// ChannelStatus is the status of a channel of the orderer.
+type ChannelStatus string
+
+const (
+	// StatusActive means that the orderer is a member of the channel and orders its transactions.
+	StatusActive ChannelStatus = "active"
+	// StatusInactive means that the orderer holds the ledger of the channel but it is not
+	// a member of the channel, e.g., it is not in the consenter set of an etcdraft channel.
+	StatusInactive ChannelStatus = "inactive"
+)
+
+// ChannelInfo carries the response to an HTTP request to get a single channel.
+// This is marshaled into the body of the HTTP response.
+type ChannelInfo struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The channel relative URL (no Host:Port, only path), e.g.: "/participation/v1/channels/my-channel".
+	URL string `json:"url"`
+	// The status of the orderer in the channel.
+	Status ChannelStatus `json:"status"`
+	// Current block height.
+	Height uint64 `json:"height"`
+}
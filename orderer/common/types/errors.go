@@ -0,0 +1,22 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package types
+
+import "github.com/pkg/errors"
+
+// ErrSystemChannelExists is returned by the channel participation API when the orderer has a
+// system channel, as the channels are then created through the system channel.
+var ErrSystemChannelExists = errors.New("system channel exists")
+
+// ErrChannelAlreadyExists is returned when trying to join a channel that already exists.
+var ErrChannelAlreadyExists = errors.New("channel already exists")
+
+// ErrChannelNotExist is returned when the channel does not exist.
+var ErrChannelNotExist = errors.New("channel does not exist")
+
+// ErrLeadershipTransferNotSupported is returned when the consensus of the channel does not elect a leader.
+var ErrLeadershipTransferNotSupported = errors.New("leadership transfer is not supported by the consensus type of the channel")
@@ -0,0 +1,24 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package types
+
+// LeadershipTransfer carries the outcome of a leadership transfer of a single channel.
+// This is marshaled into the body of the HTTP response.
+type LeadershipTransfer struct {
+	// The channel name.
+	Channel string `json:"channel"`
+	// The consenter ID of the leader after the transfer, 0 if no leader is known.
+	Leader uint64 `json:"leader"`
+	// The reason the transfer failed, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// LeadershipTransferList carries the response to an HTTP request to transfer the leadership of all the channels.
+// This is marshaled into the body of the HTTP response.
+type LeadershipTransferList struct {
+	Channels []LeadershipTransfer `json:"channels"`
+}
@@ -0,0 +1,1494 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// maxBufferedMessages is the number of messages of future views and
+// sequences a node keeps, per consenter of the channel.
+const maxBufferedMessages = 100
+
+// maxViewChangeBackoff bounds the exponential growth of the view change timeout.
+const maxViewChangeBackoff = 6
+
+// Configurator is used to configure the communication layer
+// when the chain starts.
+type Configurator interface {
+	Configure(channel string, newNodes []cluster.RemoteNode)
+}
+
+// RPC is used to mock the transport layer in tests.
+type RPC interface {
+	SendConsensus(dest uint64, msg *orderer.ConsensusRequest) error
+	SendSubmit(dest uint64, request *orderer.SubmitRequest) error
+}
+
+// BlockPuller is used to pull blocks from other OSN
+type BlockPuller interface {
+	PullBlock(seq uint64) *common.Block
+	Close()
+}
+
+// CreateBlockPuller is a function to create BlockPuller on demand.
+type CreateBlockPuller func() (BlockPuller, error)
+
+// Options contains all the configurations relevant to the chain.
+type Options struct {
+	SelfID     uint64
+	Consenters map[uint64]*bft.Consenter
+	// View is the view in which the last block of the chain was committed.
+	View uint64
+
+	RequestTimeout    time.Duration
+	ViewChangeTimeout time.Duration
+
+	Clock    clock.Clock
+	Logger   *flogging.FabricLogger
+	Verifier Verifier
+}
+
+type submit struct {
+	req    *orderer.SubmitRequest
+	sender uint64
+	leader chan uint64
+}
+
+type message struct {
+	sender  uint64
+	content *bft.Message
+}
+
+// pendingRequest is a request that was forwarded to the leader
+// and is not yet ordered.
+type pendingRequest struct {
+	req   *orderer.SubmitRequest
+	since time.Time
+	// relayed is set once the request was sent to all the nodes,
+	// which suspect the leader if it still does not order it.
+	relayed bool
+}
+
+// slot holds the messages exchanged to order the next block in the current view.
+type slot struct {
+	view       uint64
+	sequence   uint64
+	prePrepare *bft.PrePrepare
+	digest     []byte
+	prepared   bool
+	prepares   map[uint64]*bft.Prepare
+	commits    map[uint64]*bft.Commit
+	// commits received before the proposal, which can
+	// only be verified once the block is known.
+	unverifiedCommits map[uint64]*bft.Commit
+}
+
+// Chain implements consensus.Chain interface with a PBFT-style protocol.
+//
+// The leader of a view proposes the next block to the other nodes, which
+// prepare it once they validate it, and commit it once a quorum of them
+// prepared it. The nodes sign the block along with their commit message,
+// and a block is written once a quorum of the nodes committed it, along with
+// their signatures. A node that suspects the leader, either because it proposed
+// an invalid block or because it does not order the requests forwarded to it,
+// moves to the next view, whose leader re-proposes the block that may have been
+// committed in the previous views.
+type Chain struct {
+	configurator Configurator
+	rpc          RPC
+	support      consensus.ConsenterSupport
+	createPuller CreateBlockPuller
+
+	channelID  string
+	selfID     uint64
+	opts       Options
+	logger     *flogging.FabricLogger
+	consenters *consenterSet
+
+	submitC chan *submit
+	msgC    chan *message
+	haltC   chan struct{} // Signals to goroutines that the chain is halting
+	doneC   chan struct{} // Closes when the chain halts
+	startC  chan struct{} // Closes when the node is started
+
+	// The fields below are only accessed by the serving goroutine.
+	view               uint64
+	viewChanging       bool
+	viewChangeAttempts uint
+	lastBlock          *common.Block
+	lastConfigIndex    uint64
+	slot               *slot
+	locked             *bft.PreparedProof
+	requiredDigest     []byte
+	viewChanges        map[uint64]map[uint64]*bft.ViewChange
+	lastNewView        *bft.NewView
+	buffered           []*message
+	progressed         bool
+	ahead              map[uint64]uint64
+	pending            map[string]*pendingRequest
+	ordering           map[string]struct{}
+	batches            [][]*common.Envelope
+	configQueued       bool
+	held               []*orderer.SubmitRequest
+
+	batchTimer      clock.Timer
+	requestTimer    clock.Timer
+	viewChangeTimer clock.Timer
+}
+
+// NewChain constructs a chain object.
+func NewChain(
+	support consensus.ConsenterSupport,
+	opts Options,
+	conf Configurator,
+	rpc RPC,
+	f CreateBlockPuller,
+) (*Chain, error) {
+	lg := opts.Logger.With("channel", support.ChainID(), "node", opts.SelfID)
+
+	consenters := newConsenterSet(opts.Consenters, opts.Verifier)
+	if !consenters.contains(opts.SelfID) {
+		return nil, errors.Errorf("node %d is not a consenter of channel %s", opts.SelfID, support.ChainID())
+	}
+
+	sigHdr, err := support.NewSignatureHeader()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed creating signature header")
+	}
+	if id := consenters.idOf(sigHdr.Creator); id != opts.SelfID {
+		return nil, errors.Errorf("signing identity of this node does not match the identity of consenter %d", opts.SelfID)
+	}
+
+	lastBlock := support.Block(support.Height() - 1)
+	if lastBlock == nil {
+		return nil, errors.Errorf("failed to retrieve block [%d]", support.Height()-1)
+	}
+	lastConfigIndex, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		if !isChannelConfigBlock(lastBlock) {
+			return nil, errors.WithMessage(err, "failed to read the last config index")
+		}
+		lastConfigIndex = lastBlock.Header.Number
+	}
+
+	lg.Infof("Starting at block %d in view %d with %d consenters", lastBlock.Header.Number, opts.View, consenters.size())
+
+	return &Chain{
+		configurator:    conf,
+		rpc:             rpc,
+		support:         support,
+		createPuller:    f,
+		channelID:       support.ChainID(),
+		selfID:          opts.SelfID,
+		opts:            opts,
+		logger:          lg,
+		consenters:      consenters,
+		submitC:         make(chan *submit),
+		msgC:            make(chan *message),
+		haltC:           make(chan struct{}),
+		doneC:           make(chan struct{}),
+		startC:          make(chan struct{}),
+		view:            opts.View,
+		lastBlock:       lastBlock,
+		lastConfigIndex: lastConfigIndex,
+		viewChanges:     make(map[uint64]map[uint64]*bft.ViewChange),
+		ahead:           make(map[uint64]uint64),
+		pending:         make(map[string]*pendingRequest),
+		ordering:        make(map[string]struct{}),
+	}, nil
+}
+
+// Start instructs the orderer to begin serving the chain and keep it current.
+func (c *Chain) Start() {
+	c.logger.Infof("Starting BFT node")
+
+	if err := c.configureComm(); err != nil {
+		c.logger.Errorf("Failed to start chain, aborting: +%v", err)
+		close(c.doneC)
+		return
+	}
+
+	close(c.startC)
+	go c.serveRequest()
+}
+
+// Order submits normal type transactions for ordering.
+func (c *Chain) Order(env *common.Envelope, configSeq uint64) error {
+	return c.Submit(&orderer.SubmitRequest{LastValidationSeq: configSeq, Payload: env, Channel: c.channelID}, 0)
+}
+
+// Configure submits config type transactions for ordering.
+func (c *Chain) Configure(env *common.Envelope, configSeq uint64) error {
+	if err := c.checkConfigUpdateValidity(env); err != nil {
+		c.logger.Warnf("Rejected config: %s", err)
+		return err
+	}
+	return c.Submit(&orderer.SubmitRequest{LastValidationSeq: configSeq, Payload: env, Channel: c.channelID}, 0)
+}
+
+// WaitReady returns right away unless the chain is not running.
+func (c *Chain) WaitReady() error {
+	return c.isRunning()
+}
+
+// Errored returns a channel that closes when the chain stops.
+func (c *Chain) Errored() <-chan struct{} {
+	return c.doneC
+}
+
+// Halt stops the chain.
+func (c *Chain) Halt() {
+	select {
+	case <-c.startC:
+	default:
+		c.logger.Warnf("Attempted to halt a chain that has not started")
+		return
+	}
+
+	select {
+	case c.haltC <- struct{}{}:
+	case <-c.doneC:
+		return
+	}
+	<-c.doneC
+}
+
+func (c *Chain) isRunning() error {
+	select {
+	case <-c.startC:
+	default:
+		return errors.Errorf("chain is not started")
+	}
+
+	select {
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	default:
+	}
+
+	return nil
+}
+
+// Consensus passes the given ConsensusRequest message to the serving goroutine.
+func (c *Chain) Consensus(req *orderer.ConsensusRequest, sender uint64) error {
+	if err := c.isRunning(); err != nil {
+		return err
+	}
+
+	content := &bft.Message{}
+	if err := proto.Unmarshal(req.Payload, content); err != nil {
+		return errors.Wrap(err, "failed to unmarshal ConsensusRequest payload to BFT message")
+	}
+
+	select {
+	case c.msgC <- &message{sender: sender, content: content}:
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	}
+
+	return nil
+}
+
+// Submit forwards the incoming request to:
+// - the local serveRequest goroutine if this is leader
+// - the actual leader via the transport mechanism
+// Requests submitted to this node while a view change is in progress
+// are forwarded to the leader of the next view once it is installed.
+// Requests submitted by other nodes are either forwarded to this node
+// as the leader, or relayed by nodes which suspect the leader.
+func (c *Chain) Submit(req *orderer.SubmitRequest, sender uint64) error {
+	if err := c.isRunning(); err != nil {
+		return err
+	}
+
+	leaderC := make(chan uint64, 1)
+	select {
+	case c.submitC <- &submit{req: req, sender: sender, leader: leaderC}:
+		if leader := <-leaderC; leader != 0 && leader != c.selfID {
+			if err := c.rpc.SendSubmit(leader, req); err != nil {
+				return err
+			}
+		}
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	}
+
+	return nil
+}
+
+func (c *Chain) serveRequest() {
+	defer func() {
+		c.stopTimer(&c.batchTimer)
+		c.stopTimer(&c.requestTimer)
+		c.stopTimer(&c.viewChangeTimer)
+		close(c.doneC)
+		c.logger.Infof("Stopped serving requests")
+	}()
+
+	for {
+		select {
+		case s := <-c.submitC:
+			s.leader <- c.submitted(s)
+
+		case m := <-c.msgC:
+			c.handleMessage(m)
+
+		case <-timerC(c.batchTimer):
+			c.batchTimer = nil
+			if batch := c.support.BlockCutter().Cut(); len(batch) != 0 {
+				c.logger.Debugf("Batch timer expired, cut a batch of %d transactions", len(batch))
+				c.batches = append(c.batches, batch)
+				c.propose()
+			}
+
+		case <-timerC(c.requestTimer):
+			c.requestTimer = nil
+			c.checkPendingRequests()
+
+		case <-timerC(c.viewChangeTimer):
+			c.viewChangeTimer = nil
+			c.logger.Warnf("View change to view %d did not complete in time", c.view)
+			c.startViewChange(c.view + 1)
+
+		case <-c.haltC:
+			c.logger.Infof("Received halt signal")
+			return
+		}
+
+		// Messages of the next sequence or view may have arrived early.
+		for c.progressed {
+			c.progressed = false
+			c.replayBuffered()
+		}
+	}
+}
+
+// submitted handles a request submitted to this node, and returns the
+// node the request should be forwarded to, if any.
+func (c *Chain) submitted(s *submit) uint64 {
+	// Requests of other nodes were not validated by this node,
+	// and a faulty node could otherwise have valid leaders suspected.
+	if s.sender != 0 {
+		if err := c.validateRequest(s.req); err != nil {
+			c.logger.Warnf("Dropping invalid request submitted by node %d: %s", s.sender, err)
+			return 0
+		}
+	}
+
+	leader := c.consenters.leader(c.view)
+	if leader == c.selfID && !c.viewChanging {
+		c.order(s.req)
+		return leader
+	}
+
+	c.addPending(s.req, s.sender != 0)
+	if c.viewChanging {
+		return 0
+	}
+	return leader
+}
+
+// validateRequest validates a request against the current config of the channel.
+func (c *Chain) validateRequest(req *orderer.SubmitRequest) error {
+	if req.Payload == nil {
+		return errors.New("request has no payload")
+	}
+	chdr, err := utils.ChannelHeader(req.Payload)
+	if err != nil {
+		return err
+	}
+
+	seq := c.support.Sequence()
+	if chdr.Type == int32(common.HeaderType_CONFIG) || chdr.Type == int32(common.HeaderType_ORDERER_TRANSACTION) {
+		err = c.validateConfig(req.Payload)
+	} else {
+		_, err = c.support.ProcessNormalMsg(req.Payload)
+	}
+	if err != nil {
+		return err
+	}
+
+	req.LastValidationSeq = seq
+	return nil
+}
+
+// order orders the request at the leader, and proposes the batches it cuts.
+func (c *Chain) order(req *orderer.SubmitRequest) {
+	if c.configQueued {
+		// Requests are validated against the config that is being ordered
+		// once it is committed.
+		c.held = append(c.held, req)
+		return
+	}
+
+	// The same request may be forwarded by several nodes after a view change.
+	key := requestKey(req.Payload)
+	if _, exists := c.ordering[key]; exists {
+		c.logger.Debugf("Request is already being ordered")
+		return
+	}
+
+	batches, pending, err := c.ordered(req)
+	if err != nil {
+		c.logger.Errorf("Failed to order message: %s", err)
+		return
+	}
+	c.ordering[key] = struct{}{}
+
+	for _, batch := range batches {
+		if len(batch) == 1 && c.isConfig(batch[0]) {
+			c.configQueued = true
+		}
+		c.batches = append(c.batches, batch)
+	}
+
+	if pending {
+		if c.batchTimer == nil {
+			c.startTimer(&c.batchTimer, blockcutter.BatchTimeout(c.support.BlockCutter(), c.support.SharedConfig()))
+		}
+	} else {
+		c.stopTimer(&c.batchTimer)
+	}
+
+	c.propose()
+}
+
+// Orders the envelope in the `msg` content. SubmitRequest.
+// Returns
+//   -- batches [][]*common.Envelope; the batches cut,
+//   -- pending bool; if there are envelopes pending to be ordered,
+//   -- err error; the error encountered, if any.
+// It takes care of config messages as well as the revalidation of messages if the config sequence has advanced.
+func (c *Chain) ordered(msg *orderer.SubmitRequest) (batches [][]*common.Envelope, pending bool, err error) {
+	seq := c.support.Sequence()
+
+	if c.isConfig(msg.Payload) {
+		if msg.LastValidationSeq < seq {
+			c.logger.Warnf("Config message was validated against %d, although current config seq has advanced (%d)", msg.LastValidationSeq, seq)
+			msg.Payload, _, err = c.support.ProcessConfigMsg(msg.Payload)
+			if err != nil {
+				return nil, true, errors.Errorf("bad config message: %s", err)
+			}
+
+			if err = c.checkConfigUpdateValidity(msg.Payload); err != nil {
+				return nil, true, errors.Errorf("bad config message: %s", err)
+			}
+		}
+		batch := c.support.BlockCutter().Cut()
+		batches = [][]*common.Envelope{}
+		if len(batch) != 0 {
+			batches = append(batches, batch)
+		}
+		batches = append(batches, []*common.Envelope{msg.Payload})
+		return batches, false, nil
+	}
+
+	if msg.LastValidationSeq < seq {
+		c.logger.Warnf("Normal message was validated against %d, although current config seq has advanced (%d)", msg.LastValidationSeq, seq)
+		if _, err := c.support.ProcessNormalMsg(msg.Payload); err != nil {
+			return nil, true, errors.Errorf("bad normal message: %s", err)
+		}
+	}
+	batches, pending = c.support.BlockCutter().Ordered(msg.Payload)
+	return batches, pending, nil
+}
+
+// propose proposes the next batch if this node is the leader
+// and no block is being ordered. The block prepared in the previous
+// views, if any, is proposed along with the new view instead.
+func (c *Chain) propose() {
+	if c.viewChanging || c.consenters.leader(c.view) != c.selfID || len(c.batches) == 0 || c.requiredDigest != nil {
+		return
+	}
+	if c.slot != nil && c.slot.prePrepare != nil {
+		return
+	}
+
+	batch := c.batches[0]
+	c.batches = c.batches[1:]
+
+	block := c.newBlock(batch)
+	c.logger.Infof("Proposing block [%d] with %d transactions in view %d", block.Header.Number, len(batch), c.view)
+
+	prePrepare := &bft.PrePrepare{View: c.view, Sequence: block.Header.Number, Block: block}
+	c.broadcast(&bft.Message{Content: &bft.Message_PrePrepare{PrePrepare: prePrepare}})
+	c.handlePrePrepare(c.selfID, prePrepare)
+}
+
+func (c *Chain) newBlock(batch []*common.Envelope) *common.Block {
+	data := &common.BlockData{Data: make([][]byte, len(batch))}
+	for i, env := range batch {
+		data.Data[i] = utils.MarshalOrPanic(env)
+	}
+
+	block := common.NewBlock(c.lastBlock.Header.Number+1, c.lastBlock.Header.Hash())
+	block.Header.DataHash = data.Hash()
+	block.Data = data
+	return block
+}
+
+func (c *Chain) sequence() uint64 {
+	return c.lastBlock.Header.Number + 1
+}
+
+func (c *Chain) handleMessage(m *message) {
+	if !c.consenters.contains(m.sender) {
+		c.logger.Warnf("Dropping message of node %d which is not a consenter", m.sender)
+		return
+	}
+
+	switch content := m.content.Content.(type) {
+	case *bft.Message_PrePrepare:
+		c.handlePrePrepare(m.sender, content.PrePrepare)
+	case *bft.Message_Prepare:
+		c.handlePrepare(m.sender, content.Prepare)
+	case *bft.Message_Commit:
+		c.handleCommit(m.sender, content.Commit)
+	case *bft.Message_ViewChange:
+		c.handleViewChange(m.sender, content.ViewChange)
+	case *bft.Message_NewView:
+		c.handleNewView(m.sender, content.NewView)
+	default:
+		c.logger.Warnf("Dropping message of unknown type %T from node %d", content, m.sender)
+	}
+}
+
+// currentSlot returns the slot of the given view and sequence if they are
+// the current ones. Messages of future views and sequences are buffered,
+// and the others are dropped.
+func (c *Chain) currentSlot(sender uint64, view, sequence uint64, content *bft.Message) *slot {
+	if sequence > c.sequence() {
+		c.noteAhead(sender, sequence)
+	}
+
+	if view < c.view || sequence < c.sequence() {
+		return nil
+	}
+
+	if view > c.view || sequence > c.sequence() || c.viewChanging {
+		c.buffer(&message{sender: sender, content: content})
+		return nil
+	}
+
+	if c.slot == nil {
+		c.slot = &slot{
+			view:              view,
+			sequence:          sequence,
+			prepares:          make(map[uint64]*bft.Prepare),
+			commits:           make(map[uint64]*bft.Commit),
+			unverifiedCommits: make(map[uint64]*bft.Commit),
+		}
+	}
+	return c.slot
+}
+
+func (c *Chain) handlePrePrepare(sender uint64, prePrepare *bft.PrePrepare) {
+	if leader := c.consenters.leader(prePrepare.View); sender != leader {
+		c.logger.Warnf("Dropping proposal of node %d for view %d whose leader is node %d", sender, prePrepare.View, leader)
+		return
+	}
+
+	s := c.currentSlot(sender, prePrepare.View, prePrepare.Sequence, &bft.Message{Content: &bft.Message_PrePrepare{PrePrepare: prePrepare}})
+	if s == nil {
+		return
+	}
+
+	if prePrepare.Block == nil || prePrepare.Block.Header == nil {
+		c.logger.Warnf("Leader %d proposed an empty block in view %d", sender, c.view)
+		c.startViewChange(c.view + 1)
+		return
+	}
+	digest := prePrepare.Block.Header.Hash()
+
+	if s.prePrepare != nil {
+		if !bytes.Equal(s.digest, digest) {
+			c.logger.Warnf("Leader %d proposed two different blocks at sequence %d in view %d", sender, s.sequence, s.view)
+			c.startViewChange(c.view + 1)
+		}
+		return
+	}
+
+	if c.requiredDigest != nil && !bytes.Equal(c.requiredDigest, digest) {
+		c.logger.Warnf("Leader %d did not propose the block prepared in the previous views at sequence %d", sender, s.sequence)
+		c.startViewChange(c.view + 1)
+		return
+	}
+
+	if sender != c.selfID {
+		if err := c.validateProposal(prePrepare.Block); err != nil {
+			c.logger.Warnf("Leader %d proposed an invalid block at sequence %d in view %d: %s", sender, s.sequence, s.view, err)
+			c.startViewChange(c.view + 1)
+			return
+		}
+	}
+
+	s.prePrepare = prePrepare
+	s.digest = digest
+
+	prepare := &bft.Prepare{
+		View:     s.view,
+		Sequence: s.sequence,
+		Digest:   digest,
+		Signer:   c.selfID,
+	}
+	prepare.Signature = utils.SignOrPanic(c.support, prepareSigningBytes(prepare.View, prepare.Sequence, prepare.Digest))
+	c.broadcast(&bft.Message{Content: &bft.Message_Prepare{Prepare: prepare}})
+	s.prepares[c.selfID] = prepare
+
+	c.checkSlot()
+}
+
+func (c *Chain) handlePrepare(sender uint64, prepare *bft.Prepare) {
+	if prepare.Signer != sender {
+		c.logger.Warnf("Dropping prepare of node %d sent by node %d", prepare.Signer, sender)
+		return
+	}
+
+	s := c.currentSlot(sender, prepare.View, prepare.Sequence, &bft.Message{Content: &bft.Message_Prepare{Prepare: prepare}})
+	if s == nil {
+		return
+	}
+	if _, exists := s.prepares[sender]; exists {
+		return
+	}
+
+	if err := c.consenters.verifyPrepare(prepare); err != nil {
+		c.logger.Warnf("Dropping prepare of node %d: %s", sender, err)
+		return
+	}
+	s.prepares[sender] = prepare
+
+	c.checkSlot()
+}
+
+func (c *Chain) handleCommit(sender uint64, commit *bft.Commit) {
+	s := c.currentSlot(sender, commit.View, commit.Sequence, &bft.Message{Content: &bft.Message_Commit{Commit: commit}})
+	if s == nil {
+		return
+	}
+	if _, exists := s.commits[sender]; exists {
+		return
+	}
+
+	s.unverifiedCommits[sender] = commit
+	c.checkSlot()
+}
+
+// checkSlot commits the proposed block once it is prepared by a quorum,
+// and writes it once it is committed by a quorum.
+func (c *Chain) checkSlot() {
+	s := c.slot
+	if s == nil || s.prePrepare == nil {
+		return
+	}
+
+	if !s.prepared {
+		var prepares []*bft.Prepare
+		for _, prepare := range s.prepares {
+			if bytes.Equal(prepare.Digest, s.digest) {
+				prepares = append(prepares, prepare)
+			}
+		}
+		if len(prepares) >= c.consenters.quorum() {
+			sort.Slice(prepares, func(i, j int) bool { return prepares[i].Signer < prepares[j].Signer })
+			s.prepared = true
+			c.locked = &bft.PreparedProof{
+				View:     s.view,
+				Sequence: s.sequence,
+				Block:    s.prePrepare.Block,
+				Prepares: prepares,
+			}
+			c.logger.Debugf("Block [%d] is prepared in view %d", s.sequence, s.view)
+
+			commit := &bft.Commit{
+				View:      s.view,
+				Sequence:  s.sequence,
+				Digest:    s.digest,
+				Signature: c.signBlock(s.prePrepare.Block, s.view),
+			}
+			c.broadcast(&bft.Message{Content: &bft.Message_Commit{Commit: commit}})
+			s.commits[c.selfID] = commit
+		}
+	}
+
+	value := c.blockSignatureValue(s.prePrepare.Block, s.view)
+	for sender, commit := range s.unverifiedCommits {
+		delete(s.unverifiedCommits, sender)
+		if !bytes.Equal(commit.Digest, s.digest) {
+			c.logger.Warnf("Node %d committed a different block at sequence %d in view %d", sender, s.sequence, s.view)
+			continue
+		}
+		signer, err := c.consenters.verifyBlockSignature(s.prePrepare.Block.Header, value, commit.Signature)
+		if err != nil || signer != sender {
+			c.logger.Warnf("Dropping commit of node %d with an invalid block signature: %v", sender, err)
+			continue
+		}
+		s.commits[sender] = commit
+	}
+
+	if len(s.commits) < c.consenters.quorum() {
+		return
+	}
+
+	c.decide(s, value)
+}
+
+// decide writes the block of the slot along with the signatures of the
+// nodes that committed it.
+func (c *Chain) decide(s *slot, value []byte) {
+	signers := make([]uint64, 0, len(s.commits))
+	for signer := range s.commits {
+		signers = append(signers, signer)
+	}
+	sort.Slice(signers, func(i, j int) bool { return signers[i] < signers[j] })
+
+	signatures := make([]*common.MetadataSignature, len(signers))
+	for i, signer := range signers {
+		signatures[i] = s.commits[signer].Signature
+	}
+
+	block := s.prePrepare.Block
+	metadata := utils.MarshalOrPanic(&bft.BlockMetadata{View: s.view})
+	if block.Metadata == nil || len(block.Metadata.Metadata) < len(common.BlockMetadataIndex_name) {
+		block.Metadata = &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))}
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&common.Metadata{Value: metadata})
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&common.Metadata{
+		Value:      value,
+		Signatures: signatures,
+	})
+
+	c.logger.Infof("Writing block [%d] committed in view %d by nodes %v", block.Header.Number, s.view, signers)
+	c.writeBlock(block, metadata)
+}
+
+func (c *Chain) writeBlock(block *common.Block, metadata []byte) {
+	if utils.IsConfigBlock(block) {
+		c.support.WriteConfigBlock(block, metadata)
+	} else {
+		c.support.WriteBlock(block, metadata)
+	}
+
+	c.lastBlock = block
+	if isChannelConfigBlock(block) {
+		c.lastConfigIndex = block.Header.Number
+	}
+
+	c.slot = nil
+	c.requiredDigest = nil
+	if c.locked != nil && c.locked.Sequence < c.sequence() {
+		c.locked = nil
+	}
+	for sender, sequence := range c.ahead {
+		if sequence <= c.sequence() {
+			delete(c.ahead, sender)
+		}
+	}
+	c.progressed = true
+
+	c.removePending(block)
+
+	if utils.IsConfigBlock(block) && c.configQueued {
+		c.configQueued = false
+		held := c.held
+		c.held = nil
+		for _, req := range held {
+			c.order(req)
+		}
+	}
+
+	c.propose()
+}
+
+// blockSignatureValue returns the value the nodes sign along with the
+// header of the block, which is the value the block writer computes
+// when it writes the block.
+func (c *Chain) blockSignatureValue(block *common.Block, view uint64) []byte {
+	lastConfigIndex := c.lastConfigIndex
+	if isChannelConfigBlock(block) {
+		lastConfigIndex = block.Header.Number
+	}
+	return utils.MarshalOrPanic(&common.OrdererBlockMetadata{
+		LastConfig: &common.LastConfig{Index: lastConfigIndex},
+		ConsenterMetadata: utils.MarshalOrPanic(&common.Metadata{
+			Value: utils.MarshalOrPanic(&bft.BlockMetadata{View: view}),
+		}),
+	})
+}
+
+func (c *Chain) signBlock(block *common.Block, view uint64) *common.MetadataSignature {
+	sigHdr := utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(c.support))
+	value := c.blockSignatureValue(block, view)
+	return &common.MetadataSignature{
+		SignatureHeader: sigHdr,
+		Signature:       utils.SignOrPanic(c.support, util.ConcatenateBytes(value, sigHdr, block.Header.Bytes())),
+	}
+}
+
+// validateProposal validates the block proposed by the leader
+// against the ledger and the config of the channel.
+func (c *Chain) validateProposal(block *common.Block) error {
+	if err := verifyBlockStructure(block); err != nil {
+		return err
+	}
+	if block.Header.Number != c.sequence() {
+		return errors.Errorf("block number is %d instead of %d", block.Header.Number, c.sequence())
+	}
+	if !bytes.Equal(block.Header.PreviousHash, c.lastBlock.Header.Hash()) {
+		return errors.Errorf("block does not extend block %d", c.lastBlock.Header.Number)
+	}
+
+	count := len(block.Data.Data)
+	if count == 0 {
+		return errors.New("block is empty")
+	}
+	if max := c.support.SharedConfig().BatchSize().MaxMessageCount; uint32(count) > max {
+		return errors.Errorf("block has %d transactions, more than the maximum of %d", count, max)
+	}
+
+	for i, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid transaction %d", i))
+		}
+		chdr, err := utils.ChannelHeader(env)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid transaction %d", i))
+		}
+		if chdr.Type == int32(common.HeaderType_CONFIG) || chdr.Type == int32(common.HeaderType_ORDERER_TRANSACTION) {
+			if count != 1 {
+				return errors.New("config transaction is not alone in its block")
+			}
+			if err := c.validateConfig(env); err != nil {
+				return errors.WithMessage(err, "invalid config transaction")
+			}
+			continue
+		}
+		if _, err := c.support.ProcessNormalMsg(env); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid transaction %d", i))
+		}
+	}
+	return nil
+}
+
+// validateConfig checks that the config carried by the transaction
+// is the one that results from its config update.
+func (c *Chain) validateConfig(env *common.Envelope) error {
+	proposed, err := configFromEnvelope(env)
+	if err != nil {
+		return err
+	}
+	processed, _, err := c.support.ProcessConfigMsg(env)
+	if err != nil {
+		return err
+	}
+	expected, err := configFromEnvelope(processed)
+	if err != nil {
+		return err
+	}
+	if !proto.Equal(proposed, expected) {
+		return errors.New("config does not match the config update it carries")
+	}
+	return c.checkConfigUpdateValidity(env)
+}
+
+// checkConfigUpdateValidity rejects config updates which change the consensus
+// type or the consenters, since the consenters of a BFT channel are fixed.
+func (c *Chain) checkConfigUpdateValidity(env *common.Envelope) error {
+	config, err := configFromEnvelope(env)
+	if err != nil {
+		return err
+	}
+	if config.ChannelGroup == nil || config.ChannelGroup.Groups[channelconfig.OrdererGroupKey] == nil {
+		return errors.New("config has no orderer group")
+	}
+	value, exists := config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey]
+	if !exists {
+		return errors.New("config has no consensus type")
+	}
+
+	consensusType := &orderer.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	if consensusType.Type != bft.TypeKey {
+		return errors.Errorf("consensus type cannot be changed from %s to %s", bft.TypeKey, consensusType.Type)
+	}
+
+	metadata := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+		return errors.Wrap(err, "failed to unmarshal bft metadata")
+	}
+	if len(metadata.Consenters) != c.consenters.size() {
+		return errors.New("consenters of a bft channel cannot be changed")
+	}
+	for i, consenter := range metadata.Consenters {
+		if !proto.Equal(consenter, c.opts.Consenters[uint64(i+1)]) {
+			return errors.New("consenters of a bft channel cannot be changed")
+		}
+	}
+	return nil
+}
+
+func (c *Chain) isConfig(env *common.Envelope) bool {
+	h, err := utils.ChannelHeader(env)
+	if err != nil {
+		c.logger.Panicf("failed to extract channel header from envelope")
+	}
+
+	return h.Type == int32(common.HeaderType_CONFIG) || h.Type == int32(common.HeaderType_ORDERER_TRANSACTION)
+}
+
+// startViewChange moves this node to the given view, and sends a
+// view change message to the other nodes.
+func (c *Chain) startViewChange(view uint64) {
+	if view <= c.view {
+		return
+	}
+
+	c.logger.Warnf("Changing view from %d to %d, the leader of view %d is node %d", c.view, view, view, c.consenters.leader(view))
+
+	c.view = view
+	c.viewChanging = true
+	c.slot = nil
+	c.requiredDigest = nil
+	c.stopTimer(&c.batchTimer)
+	c.stopTimer(&c.requestTimer)
+
+	backoff := c.viewChangeAttempts
+	if backoff > maxViewChangeBackoff {
+		backoff = maxViewChangeBackoff
+	}
+	c.startTimer(&c.viewChangeTimer, c.opts.ViewChangeTimeout<<backoff)
+	c.viewChangeAttempts++
+
+	vc := &bft.ViewChange{
+		View:     view,
+		Sequence: c.sequence(),
+		Signer:   c.selfID,
+	}
+	if c.locked != nil && c.locked.Sequence == vc.Sequence {
+		vc.Prepared = c.locked
+	}
+	if vc.Sequence > 1 {
+		signatures, err := utils.GetMetadataFromBlock(c.lastBlock, common.BlockMetadataIndex_SIGNATURES)
+		if err != nil {
+			c.logger.Panicf("Failed reading signatures of block [%d]: %s", c.lastBlock.Header.Number, err)
+		}
+		vc.CheckpointHeader = c.lastBlock.Header
+		vc.CheckpointSignatures = signatures
+	}
+	vc.Signature = utils.SignOrPanic(c.support, viewChangeSigningBytes(vc))
+
+	c.broadcast(&bft.Message{Content: &bft.Message_ViewChange{ViewChange: vc}})
+	c.handleViewChange(c.selfID, vc)
+}
+
+func (c *Chain) handleViewChange(sender uint64, vc *bft.ViewChange) {
+	if vc.Signer != sender {
+		c.logger.Warnf("Dropping view change of node %d sent by node %d", vc.Signer, sender)
+		return
+	}
+
+	if vc.View < c.view || (vc.View == c.view && !c.viewChanging) {
+		// The sender missed the new view, send it again.
+		if vc.View == c.view && c.lastNewView != nil && c.lastNewView.View == vc.View {
+			c.send(sender, &bft.Message{Content: &bft.Message_NewView{NewView: c.lastNewView}})
+		}
+		return
+	}
+
+	if _, exists := c.viewChanges[vc.View][sender]; exists {
+		return
+	}
+	if sender != c.selfID {
+		if err := c.consenters.verifyViewChange(vc); err != nil {
+			c.logger.Warnf("Dropping view change of node %d: %s", sender, err)
+			return
+		}
+	}
+	// Only the latest view change of every node is kept.
+	for view, vcs := range c.viewChanges {
+		if prev, exists := vcs[sender]; exists {
+			if prev.View > vc.View {
+				return
+			}
+			delete(vcs, sender)
+			if len(vcs) == 0 {
+				delete(c.viewChanges, view)
+			}
+		}
+	}
+	if c.viewChanges[vc.View] == nil {
+		c.viewChanges[vc.View] = make(map[uint64]*bft.ViewChange)
+	}
+	c.viewChanges[vc.View][sender] = vc
+
+	// Join the view change once enough nodes moved to higher views,
+	// as at least one of them is correct.
+	if view, joined := c.viewToJoin(); joined {
+		c.startViewChange(view)
+	}
+
+	if c.viewChanging && c.consenters.leader(c.view) == c.selfID && len(c.viewChanges[c.view]) >= c.consenters.quorum() {
+		c.sendNewView()
+	}
+}
+
+// viewToJoin returns the lowest view out of the highest views
+// that f+1 other nodes are moving to, if all are higher than
+// the view of this node.
+func (c *Chain) viewToJoin() (uint64, bool) {
+	highest := make(map[uint64]uint64)
+	for view, vcs := range c.viewChanges {
+		if view <= c.view {
+			continue
+		}
+		for sender := range vcs {
+			if sender != c.selfID && view > highest[sender] {
+				highest[sender] = view
+			}
+		}
+	}
+	if len(highest) < c.consenters.faults()+1 {
+		return 0, false
+	}
+
+	var lowest uint64
+	for _, view := range highest {
+		if lowest == 0 || view < lowest {
+			lowest = view
+		}
+	}
+	return lowest, true
+}
+
+// sendNewView installs the view this node is the leader of,
+// once a quorum of nodes moved to it.
+func (c *Chain) sendNewView() {
+	vcs := make([]*bft.ViewChange, 0, len(c.viewChanges[c.view]))
+	for _, vc := range c.viewChanges[c.view] {
+		vcs = append(vcs, vc)
+	}
+	sort.Slice(vcs, func(i, j int) bool { return vcs[i].Signer < vcs[j].Signer })
+
+	sequence, proof := selectProof(vcs)
+	if sequence > c.sequence() && !c.sync(sequence) {
+		c.logger.Warnf("Failed catching up to sequence %d, cannot install view %d", sequence, c.view)
+		return
+	}
+
+	nv := &bft.NewView{View: c.view, ViewChanges: vcs}
+	if proof != nil {
+		nv.PrePrepare = &bft.PrePrepare{View: c.view, Sequence: proof.Sequence, Block: proof.Block}
+	}
+	c.lastNewView = nv
+
+	c.logger.Infof("Installing view %d at sequence %d", c.view, sequence)
+	c.broadcast(&bft.Message{Content: &bft.Message_NewView{NewView: nv}})
+	c.enterView(nv.View, proof)
+	if nv.PrePrepare != nil {
+		c.handlePrePrepare(c.selfID, nv.PrePrepare)
+	}
+}
+
+func (c *Chain) handleNewView(sender uint64, nv *bft.NewView) {
+	if leader := c.consenters.leader(nv.View); sender != leader {
+		c.logger.Warnf("Dropping new view %d of node %d whose leader is node %d", nv.View, sender, leader)
+		return
+	}
+	if nv.View < c.view || (nv.View == c.view && !c.viewChanging) {
+		return
+	}
+
+	sequence, proof, err := c.consenters.verifyNewView(nv)
+	if err != nil {
+		c.logger.Warnf("Dropping invalid new view %d of node %d: %s", nv.View, sender, err)
+		return
+	}
+
+	if nv.PrePrepare != nil {
+		if nv.PrePrepare.View != nv.View || proof == nil || nv.PrePrepare.Block == nil ||
+			!bytes.Equal(nv.PrePrepare.Block.Header.Hash(), proof.Block.Header.Hash()) {
+			c.logger.Warnf("Dropping new view %d of node %d which does not re-propose the prepared block", nv.View, sender)
+			return
+		}
+	} else if proof != nil {
+		c.logger.Warnf("Dropping new view %d of node %d which does not re-propose the prepared block", nv.View, sender)
+		return
+	}
+
+	if sequence > c.sequence() && !c.sync(sequence) {
+		c.logger.Warnf("Failed catching up to sequence %d, cannot enter view %d", sequence, nv.View)
+		return
+	}
+
+	c.logger.Infof("Entering view %d installed by node %d at sequence %d", nv.View, sender, sequence)
+	c.enterView(nv.View, proof)
+	if nv.PrePrepare != nil {
+		c.handlePrePrepare(sender, nv.PrePrepare)
+	}
+}
+
+// enterView resumes the normal operation in the given view. The first block
+// proposed in the view has to be the given prepared one, if any.
+func (c *Chain) enterView(view uint64, proof *bft.PreparedProof) {
+	c.view = view
+	c.viewChanging = false
+	c.viewChangeAttempts = 0
+	c.stopTimer(&c.viewChangeTimer)
+	for v := range c.viewChanges {
+		if v <= view {
+			delete(c.viewChanges, v)
+		}
+	}
+
+	c.slot = nil
+	c.requiredDigest = nil
+	if proof != nil && proof.Sequence == c.sequence() {
+		c.requiredDigest = proof.Block.Header.Hash()
+	}
+	c.progressed = true
+
+	leader := c.consenters.leader(view)
+	if leader != c.selfID {
+		c.lastNewView = nil
+		c.abdicate()
+	}
+
+	for key, p := range c.pending {
+		if leader == c.selfID {
+			delete(c.pending, key)
+			c.order(p.req)
+			continue
+		}
+		p.since = c.opts.Clock.Now()
+		if err := c.rpc.SendSubmit(leader, p.req); err != nil {
+			c.logger.Warnf("Failed forwarding request to leader %d: %s", leader, err)
+		}
+	}
+	c.armRequestTimer()
+
+	c.propose()
+}
+
+// abdicate hands the requests ordered by this node while it was the leader
+// over to the pending requests, which are forwarded to the new leader.
+func (c *Chain) abdicate() {
+	var reqs []*orderer.SubmitRequest
+	for _, batch := range c.batches {
+		for _, env := range batch {
+			reqs = append(reqs, &orderer.SubmitRequest{Channel: c.channelID, LastValidationSeq: c.support.Sequence(), Payload: env})
+		}
+	}
+	for _, env := range c.support.BlockCutter().Cut() {
+		reqs = append(reqs, &orderer.SubmitRequest{Channel: c.channelID, LastValidationSeq: c.support.Sequence(), Payload: env})
+	}
+	reqs = append(reqs, c.held...)
+
+	c.batches = nil
+	c.held = nil
+	c.ordering = make(map[string]struct{})
+	c.configQueued = false
+	c.stopTimer(&c.batchTimer)
+
+	for _, req := range reqs {
+		c.addPending(req, false)
+	}
+}
+
+func (c *Chain) addPending(req *orderer.SubmitRequest, relayed bool) {
+	key := requestKey(req.Payload)
+	if p, exists := c.pending[key]; exists {
+		p.relayed = p.relayed || relayed
+		return
+	}
+
+	c.pending[key] = &pendingRequest{req: req, since: c.opts.Clock.Now(), relayed: relayed}
+	if c.requestTimer == nil {
+		c.armRequestTimer()
+	}
+}
+
+// removePending removes the requests ordered in the given block. Once a config
+// block is written, pending config requests are dropped as they are either ordered
+// or no longer valid, and the other requests are dropped if they are no longer valid.
+func (c *Chain) removePending(block *common.Block) {
+	for _, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			continue
+		}
+		key := requestKey(env)
+		delete(c.pending, key)
+		delete(c.ordering, key)
+	}
+	if utils.IsConfigBlock(block) {
+		for key, p := range c.pending {
+			if c.isConfig(p.req.Payload) {
+				delete(c.pending, key)
+				continue
+			}
+			if _, err := c.support.ProcessNormalMsg(p.req.Payload); err != nil {
+				c.logger.Debugf("Dropping pending request which is no longer valid: %s", err)
+				delete(c.pending, key)
+			}
+		}
+	}
+	c.armRequestTimer()
+}
+
+func requestKey(env *common.Envelope) string {
+	digest := sha256.Sum256(env.Payload)
+	return string(digest[:])
+}
+
+// armRequestTimer sets the request timer to expire once the
+// oldest pending request should have been ordered.
+func (c *Chain) armRequestTimer() {
+	c.stopTimer(&c.requestTimer)
+	if c.viewChanging || len(c.pending) == 0 {
+		return
+	}
+
+	var oldest time.Time
+	for _, p := range c.pending {
+		if oldest.IsZero() || p.since.Before(oldest) {
+			oldest = p.since
+		}
+	}
+	timeout := oldest.Add(c.opts.RequestTimeout).Sub(c.opts.Clock.Now())
+	if timeout < 0 {
+		timeout = 0
+	}
+	c.startTimer(&c.requestTimer, timeout)
+}
+
+// checkPendingRequests relays the requests the leader did not order in time
+// to the other nodes, so that they can suspect the leader as well, and
+// suspects the leader if it still does not order them.
+func (c *Chain) checkPendingRequests() {
+	if c.viewChanging {
+		return
+	}
+	now := c.opts.Clock.Now()
+	for _, p := range c.pending {
+		if now.Sub(p.since) < c.opts.RequestTimeout {
+			continue
+		}
+		if p.relayed {
+			c.logger.Warnf("Leader %d did not order a request within %s", c.consenters.leader(c.view), c.opts.RequestTimeout)
+			c.startViewChange(c.view + 1)
+			return
+		}
+
+		c.logger.Infof("Leader %d did not order a request within %s, relaying it to the other nodes", c.consenters.leader(c.view), c.opts.RequestTimeout)
+		p.relayed = true
+		p.since = now
+		for _, id := range c.consenters.ids {
+			if id == c.selfID {
+				continue
+			}
+			if err := c.rpc.SendSubmit(id, p.req); err != nil {
+				c.logger.Debugf("Failed relaying request to node %d: %s", id, err)
+			}
+		}
+	}
+	c.armRequestTimer()
+}
+
+// noteAhead tracks the nodes that are ahead of this node,
+// and catches up with them once f+1 of them are at least
+// two blocks ahead, as at least one of them is correct.
+// This also keeps a node which is alone in suspecting the
+// leader up to date until the other nodes change views.
+func (c *Chain) noteAhead(sender, sequence uint64) {
+	if sequence > c.ahead[sender] {
+		c.ahead[sender] = sequence
+	}
+
+	var sequences []uint64
+	for _, seq := range c.ahead {
+		if seq > c.sequence()+1 {
+			sequences = append(sequences, seq)
+		}
+	}
+	f := c.consenters.faults()
+	if len(sequences) < f+1 {
+		return
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] > sequences[j] })
+	target := sequences[f]
+
+	c.logger.Infof("Nodes are ahead of this node, catching up from sequence %d to %d", c.sequence(), target)
+	c.sync(target)
+}
+
+// sync pulls the blocks up to the given sequence from the other nodes.
+// It returns whether all blocks were pulled.
+func (c *Chain) sync(sequence uint64) bool {
+	puller, err := c.createPuller()
+	if err != nil {
+		c.logger.Errorf("Failed creating block puller: %s", err)
+		return false
+	}
+	defer puller.Close()
+
+	for seq := c.sequence(); seq < sequence; seq = c.sequence() {
+		block := puller.PullBlock(seq)
+		if block == nil {
+			c.logger.Warnf("Failed pulling block [%d]", seq)
+			return false
+		}
+		if err := c.verifyPulledBlock(block); err != nil {
+			c.logger.Warnf("Pulled an invalid block [%d]: %s", seq, err)
+			return false
+		}
+
+		view, err := blockView(block)
+		if err != nil {
+			c.logger.Warnf("Pulled block [%d] with invalid metadata: %s", seq, err)
+			return false
+		}
+
+		c.logger.Infof("Writing block [%d] pulled from the other nodes", seq)
+		c.writeBlock(block, nil)
+
+		// A quorum committed the block in a later view, which
+		// means this node missed a view change.
+		if view > c.view {
+			c.logger.Infof("Block [%d] was committed in view %d, moving from view %d", seq, view, c.view)
+			c.enterView(view, nil)
+		}
+	}
+	return true
+}
+
+func (c *Chain) verifyPulledBlock(block *common.Block) error {
+	if block.Header == nil || block.Header.Number != c.sequence() {
+		return errors.New("unexpected block number")
+	}
+	if !bytes.Equal(block.Header.PreviousHash, c.lastBlock.Header.Hash()) {
+		return errors.Errorf("block does not extend block %d", c.lastBlock.Header.Number)
+	}
+	if err := verifyBlockStructure(block); err != nil {
+		return err
+	}
+	signatures, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return err
+	}
+	return c.consenters.verifyBlockSignatures(block.Header, signatures)
+}
+
+func (c *Chain) buffer(m *message) {
+	if len(c.buffered) >= maxBufferedMessages*c.consenters.size() {
+		c.buffered = c.buffered[1:]
+	}
+	c.buffered = append(c.buffered, m)
+}
+
+func (c *Chain) replayBuffered() {
+	buffered := c.buffered
+	c.buffered = nil
+	for _, m := range buffered {
+		c.handleMessage(m)
+	}
+}
+
+func (c *Chain) broadcast(content *bft.Message) {
+	for _, id := range c.consenters.ids {
+		if id != c.selfID {
+			c.send(id, content)
+		}
+	}
+}
+
+func (c *Chain) send(dest uint64, content *bft.Message) {
+	req := &orderer.ConsensusRequest{
+		Channel: c.channelID,
+		Payload: utils.MarshalOrPanic(content),
+	}
+	if err := c.rpc.SendConsensus(dest, req); err != nil {
+		c.logger.Debugf("Failed sending message to node %d: %s", dest, err)
+	}
+}
+
+func (c *Chain) startTimer(timer *clock.Timer, d time.Duration) {
+	c.stopTimer(timer)
+	*timer = c.opts.Clock.NewTimer(d)
+}
+
+func (c *Chain) stopTimer(timer *clock.Timer) {
+	if *timer != nil {
+		(*timer).Stop()
+		*timer = nil
+	}
+}
+
+func timerC(timer clock.Timer) <-chan time.Time {
+	if timer == nil {
+		return nil
+	}
+	return timer.C()
+}
+
+func (c *Chain) configureComm() error {
+	var nodes []cluster.RemoteNode
+	for id, consenter := range c.opts.Consenters {
+		// No need to know yourself
+		if id == c.selfID {
+			continue
+		}
+		serverCertAsDER, err := pemToDER(consenter.ServerTlsCert, id, "server", c.logger)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		clientCertAsDER, err := pemToDER(consenter.ClientTlsCert, id, "client", c.logger)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		nodes = append(nodes, cluster.RemoteNode{
+			ID:            id,
+			Endpoint:      fmt.Sprintf("%s:%d", consenter.Host, consenter.Port),
+			ServerTLSCert: serverCertAsDER,
+			ClientTLSCert: clientCertAsDER,
+		})
+	}
+
+	c.configurator.Configure(c.channelID, nodes)
+	return nil
+}
+
+func pemToDER(pemBytes []byte, id uint64, certType string, logger *flogging.FabricLogger) ([]byte, error) {
+	bl, _ := pem.Decode(pemBytes)
+	if bl == nil {
+		logger.Errorf("Rejecting PEM block of %s TLS cert for node %d, offending PEM is: %s", certType, id, string(pemBytes))
+		return nil, errors.Errorf("invalid PEM block")
+	}
+	return bl.Bytes, nil
+}
@@ -0,0 +1,518 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/flogging"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/consensus/mocks"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelID = "mychannel"
+
+// ecdsaVerifier verifies the signatures of the consenters
+// without the MSP of the channel.
+type ecdsaVerifier struct{}
+
+func (ecdsaVerifier) Verify(identity, message, signature []byte) error {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(identity, sID); err != nil {
+		return err
+	}
+	bl, _ := pem.Decode(sID.IdBytes)
+	if bl == nil {
+		return errors.New("identity is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(bl.Bytes)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(message)
+	if !ecdsa.VerifyASN1(cert.PublicKey.(*ecdsa.PublicKey), digest[:], signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// cutter cuts a batch once it holds the given number of envelopes.
+type cutter struct {
+	max     int
+	pending []*common.Envelope
+}
+
+func (bc *cutter) Ordered(env *common.Envelope) ([][]*common.Envelope, bool) {
+	bc.pending = append(bc.pending, env)
+	if len(bc.pending) < bc.max {
+		return nil, true
+	}
+	return [][]*common.Envelope{bc.Cut()}, false
+}
+
+func (bc *cutter) Cut() []*common.Envelope {
+	batch := bc.pending
+	bc.pending = nil
+	return batch
+}
+
+type node struct {
+	id      uint64
+	chain   *Chain
+	support *mocks.FakeConsenterSupport
+	signer  *tlsgen.CertKeyPair
+
+	lock   sync.Mutex
+	ledger []*common.Block
+}
+
+func (n *node) height() uint64 {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return uint64(len(n.ledger))
+}
+
+func (n *node) block(number uint64) *common.Block {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if number >= uint64(len(n.ledger)) {
+		return nil
+	}
+	return n.ledger[number]
+}
+
+// writeBlock mimics the block writer of the orderer, which keeps the
+// signatures the chain collected on the block.
+func (n *node) writeBlock(block *common.Block, encodedMetadataValue []byte) {
+	if encodedMetadataValue != nil {
+		block.Metadata.Metadata[common.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&common.Metadata{Value: encodedMetadataValue})
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&common.Metadata{
+		Value: utils.MarshalOrPanic(&common.LastConfig{Index: 0}),
+	})
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.ledger = append(n.ledger, block)
+}
+
+// network delivers the messages of the nodes in the order they are sent.
+type network struct {
+	clock      *fakeclock.FakeClock
+	consenters map[uint64]*bft.Consenter
+	nodes      map[uint64]*node
+	inboxes    map[uint64]chan func()
+
+	lock         sync.Mutex
+	disconnected map[uint64]bool
+}
+
+func newNetwork(t *testing.T, size int, maxMessageCount uint32) *network {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	genesis := common.NewBlock(0, nil)
+	genesis.Data.Data = [][]byte{utils.MarshalOrPanic(envelope("genesis"))}
+	genesis.Header.DataHash = genesis.Data.Hash()
+	genesis.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&common.Metadata{
+		Value: utils.MarshalOrPanic(&common.LastConfig{Index: 0}),
+	})
+
+	n := &network{
+		clock:        fakeclock.NewFakeClock(time.Now()),
+		consenters:   make(map[uint64]*bft.Consenter),
+		nodes:        make(map[uint64]*node),
+		inboxes:      make(map[uint64]chan func()),
+		disconnected: make(map[uint64]bool),
+	}
+
+	for id := uint64(1); id <= uint64(size); id++ {
+		tlsCert, err := ca.NewServerCertKeyPair("127.0.0.1")
+		require.NoError(t, err)
+		signer, err := ca.NewClientCertKeyPair()
+		require.NoError(t, err)
+
+		n.consenters[id] = &bft.Consenter{
+			Host:          "127.0.0.1",
+			Port:          uint32(7050 + id),
+			ClientTlsCert: tlsCert.Cert,
+			ServerTlsCert: tlsCert.Cert,
+			MspId:         "OrdererOrg",
+			Identity:      signer.Cert,
+		}
+		n.nodes[id] = &node{
+			id:     id,
+			signer: signer,
+			ledger: []*common.Block{proto.Clone(genesis).(*common.Block)},
+		}
+	}
+
+	for id, nd := range n.nodes {
+		nd := nd
+		support := &mocks.FakeConsenterSupport{}
+		support.ChainIDReturns(channelID)
+		support.HeightStub = nd.height
+		support.BlockStub = nd.block
+		support.WriteBlockStub = nd.writeBlock
+		support.WriteConfigBlockStub = nd.writeBlock
+		support.BlockCutterReturns(&cutter{max: int(maxMessageCount)})
+		support.SharedConfigReturns(&mockconfig.Orderer{
+			BatchTimeoutVal: time.Second,
+			BatchSizeVal:    &orderer.BatchSize{MaxMessageCount: maxMessageCount},
+		})
+		support.NewSignatureHeaderStub = func() (*common.SignatureHeader, error) {
+			return &common.SignatureHeader{Creator: serializedIdentity(n.consenters[nd.id])}, nil
+		}
+		support.SignStub = func(message []byte) ([]byte, error) {
+			digest := sha256.Sum256(message)
+			return nd.signer.Signer.Sign(rand.Reader, digest[:], nil)
+		}
+		nd.support = support
+
+		chain, err := NewChain(
+			support,
+			Options{
+				SelfID:            id,
+				Consenters:        n.consenters,
+				RequestTimeout:    10 * time.Second,
+				ViewChangeTimeout: 20 * time.Second,
+				Clock:             n.clock,
+				Logger:            flogging.MustGetLogger("orderer.consensus.bft"),
+				Verifier:          ecdsaVerifier{},
+			},
+			&configurator{},
+			&rpc{network: n, from: id},
+			func() (BlockPuller, error) { return &puller{network: n, self: nd.id}, nil },
+		)
+		require.NoError(t, err)
+		nd.chain = chain
+
+		inbox := make(chan func(), 100000)
+		n.inboxes[id] = inbox
+		go func() {
+			for deliver := range inbox {
+				deliver()
+			}
+		}()
+	}
+
+	return n
+}
+
+func (n *network) start() {
+	for _, nd := range n.nodes {
+		nd.chain.Start()
+	}
+}
+
+func (n *network) stop() {
+	for id, nd := range n.nodes {
+		nd.chain.Halt()
+		close(n.inboxes[id])
+	}
+}
+
+func (n *network) disconnect(id uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.disconnected[id] = true
+}
+
+func (n *network) connect(id uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.disconnected, id)
+}
+
+func (n *network) reachable(from, to uint64) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return !n.disconnected[from] && !n.disconnected[to]
+}
+
+// send delivers the message unless either node is disconnected,
+// in which case the message is lost.
+func (n *network) send(from, to uint64, deliver func()) {
+	if n.reachable(from, to) {
+		n.inboxes[to] <- deliver
+	}
+}
+
+// waitFor waits until the condition holds, and advances the clock
+// of the nodes while waiting if tick is set.
+func (n *network) waitFor(t *testing.T, tick time.Duration, condition func() bool) {
+	for i := 0; i < 1000; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		if tick > 0 {
+			n.clock.Increment(tick)
+		}
+	}
+	t.Fatalf("Condition did not hold in time")
+}
+
+func (n *network) heightsAre(height uint64, ids ...uint64) func() bool {
+	return func() bool {
+		for _, id := range ids {
+			if n.nodes[id].height() != height {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// assertLedgers asserts that the ledgers of the given nodes hold the same
+// blocks, signed by a quorum of the nodes, and ordered in the given views.
+func (n *network) assertLedgers(t *testing.T, views []uint64, ids ...uint64) {
+	for number := uint64(1); number <= uint64(len(views)); number++ {
+		expected := n.nodes[ids[0]].block(number)
+		require.NotNil(t, expected)
+		for _, id := range ids {
+			block := n.nodes[id].block(number)
+			require.NotNil(t, block)
+			assert.Equal(t, expected.Header.Hash(), block.Header.Hash(), "node %d has a different block [%d]", id, number)
+			assert.NoError(t, VerifyBlock(block, n.consenters, ecdsaVerifier{}))
+
+			view, err := blockView(block)
+			require.NoError(t, err)
+			assert.Equal(t, views[number-1], view, "block [%d] of node %d was committed in another view", number, id)
+		}
+	}
+}
+
+type configurator struct{}
+
+func (*configurator) Configure(channel string, newNodes []cluster.RemoteNode) {}
+
+type rpc struct {
+	network *network
+	from    uint64
+}
+
+func (r *rpc) SendConsensus(dest uint64, msg *orderer.ConsensusRequest) error {
+	msg = proto.Clone(msg).(*orderer.ConsensusRequest)
+	r.network.send(r.from, dest, func() {
+		r.network.nodes[dest].chain.Consensus(msg, r.from)
+	})
+	return nil
+}
+
+func (r *rpc) SendSubmit(dest uint64, request *orderer.SubmitRequest) error {
+	request = proto.Clone(request).(*orderer.SubmitRequest)
+	r.network.send(r.from, dest, func() {
+		r.network.nodes[dest].chain.Submit(request, r.from)
+	})
+	return nil
+}
+
+// puller pulls blocks from the ledgers of the other connected nodes.
+type puller struct {
+	network *network
+	self    uint64
+}
+
+func (p *puller) PullBlock(seq uint64) *common.Block {
+	for id, nd := range p.network.nodes {
+		if id == p.self || !p.network.reachable(p.self, id) {
+			continue
+		}
+		if block := nd.block(seq); block != nil {
+			return proto.Clone(block).(*common.Block)
+		}
+	}
+	return nil
+}
+
+func (p *puller) Close() {}
+
+func envelope(data string) *common.Envelope {
+	return &common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					Type:      int32(common.HeaderType_MESSAGE),
+					ChannelId: channelID,
+				}),
+			},
+			Data: []byte(data),
+		}),
+	}
+}
+
+func TestOrderBlocks(t *testing.T) {
+	n := newNetwork(t, 4, 2)
+	n.start()
+	defer n.stop()
+
+	// Requests submitted to the leader
+	require.NoError(t, n.nodes[1].chain.Order(envelope("tx1"), 0))
+	require.NoError(t, n.nodes[1].chain.Order(envelope("tx2"), 0))
+	n.waitFor(t, 0, n.heightsAre(2, 1, 2, 3, 4))
+
+	// A request forwarded to the leader, and cut once the batch timer expires
+	require.NoError(t, n.nodes[3].chain.Order(envelope("tx3"), 0))
+	n.waitFor(t, 100*time.Millisecond, n.heightsAre(3, 1, 2, 3, 4))
+
+	n.assertLedgers(t, []uint64{0, 0}, 1, 2, 3, 4)
+	for _, nd := range n.nodes {
+		signatures, err := utils.GetMetadataFromBlock(nd.block(1), common.BlockMetadataIndex_SIGNATURES)
+		require.NoError(t, err)
+		assert.True(t, len(signatures.Signatures) >= 3)
+		assert.Len(t, nd.block(1).Data.Data, 2)
+		assert.Len(t, nd.block(2).Data.Data, 1)
+	}
+}
+
+func TestLeaderCrash(t *testing.T) {
+	n := newNetwork(t, 4, 1)
+	n.start()
+	defer n.stop()
+
+	n.disconnect(1)
+
+	// The request is forwarded to the crashed leader, relayed to
+	// the other nodes once it times out, and the nodes then change
+	// the view once it times out again.
+	require.NoError(t, n.nodes[2].chain.Order(envelope("tx1"), 0))
+	n.waitFor(t, time.Second, n.heightsAre(2, 2, 3, 4))
+
+	require.NoError(t, n.nodes[4].chain.Order(envelope("tx2"), 0))
+	n.waitFor(t, 0, n.heightsAre(3, 2, 3, 4))
+
+	n.assertLedgers(t, []uint64{1, 1}, 2, 3, 4)
+	assert.Equal(t, uint64(1), n.nodes[1].height())
+}
+
+func TestInvalidProposal(t *testing.T) {
+	n := newNetwork(t, 4, 1)
+	for _, id := range []uint64{2, 3, 4} {
+		n.nodes[id].support.ProcessNormalMsgStub = func(env *common.Envelope) (uint64, error) {
+			payload, err := utils.UnmarshalPayload(env.Payload)
+			if err != nil || string(payload.Data) == "bad" {
+				return 0, errors.New("bad transaction")
+			}
+			return 0, nil
+		}
+	}
+	n.start()
+	defer n.stop()
+
+	// The leader proposes a transaction the other nodes reject,
+	// which makes them move to the next view.
+	require.NoError(t, n.nodes[1].chain.Order(envelope("bad"), 0))
+	require.NoError(t, n.nodes[3].chain.Order(envelope("tx1"), 0))
+	n.waitFor(t, time.Second, n.heightsAre(2, 2, 3, 4))
+
+	n.assertLedgers(t, []uint64{1}, 2, 3, 4)
+	block := n.nodes[2].block(1)
+	require.Len(t, block.Data.Data, 1)
+	env, err := utils.UnmarshalEnvelope(block.Data.Data[0])
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(envelope("tx1"), env))
+}
+
+func TestLaggingNodeCatchesUp(t *testing.T) {
+	n := newNetwork(t, 4, 1)
+	n.start()
+	defer n.stop()
+
+	n.disconnect(4)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, n.nodes[1].chain.Order(envelope(fmt.Sprintf("tx%d", i)), 0))
+	}
+	n.waitFor(t, 0, n.heightsAre(4, 1, 2, 3))
+	assert.Equal(t, uint64(1), n.nodes[4].height())
+
+	// Once the node receives the messages of the next blocks,
+	// it pulls the blocks it missed from the other nodes.
+	n.connect(4)
+	require.NoError(t, n.nodes[1].chain.Order(envelope("tx4"), 0))
+	n.waitFor(t, 0, n.heightsAre(5, 1, 2, 3))
+	require.NoError(t, n.nodes[1].chain.Order(envelope("tx5"), 0))
+	n.waitFor(t, 0, n.heightsAre(6, 1, 2, 3, 4))
+
+	n.assertLedgers(t, []uint64{0, 0, 0, 0, 0}, 1, 2, 3, 4)
+}
+
+func TestNewChain(t *testing.T) {
+	n := newNetwork(t, 4, 1)
+	support := n.nodes[1].support
+
+	t.Run("not a consenter", func(t *testing.T) {
+		_, err := NewChain(support, Options{SelfID: 5, Consenters: n.consenters, Logger: flogging.MustGetLogger("test")}, nil, nil, nil)
+		assert.EqualError(t, err, "node 5 is not a consenter of channel mychannel")
+	})
+
+	t.Run("signing identity of another consenter", func(t *testing.T) {
+		_, err := NewChain(support, Options{SelfID: 2, Consenters: n.consenters, Logger: flogging.MustGetLogger("test")}, nil, nil, nil)
+		assert.EqualError(t, err, "signing identity of this node does not match the identity of consenter 2")
+	})
+}
+
+func TestConfigUpdateValidity(t *testing.T) {
+	n := newNetwork(t, 4, 1)
+	chain := n.nodes[1].chain
+
+	configEnv := func(consenters []*bft.Consenter, consensusType string) *common.Envelope {
+		metadata := utils.MarshalOrPanic(&bft.ConfigMetadata{Consenters: consenters, Options: &bft.Options{}})
+		config := &common.Config{
+			ChannelGroup: &common.ConfigGroup{
+				Groups: map[string]*common.ConfigGroup{
+					"Orderer": {
+						Values: map[string]*common.ConfigValue{
+							"ConsensusType": {
+								Value: utils.MarshalOrPanic(&orderer.ConsensusType{Type: consensusType, Metadata: metadata}),
+							},
+						},
+					},
+				},
+			},
+		}
+		return &common.Envelope{
+			Payload: utils.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{
+					ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+						Type:      int32(common.HeaderType_CONFIG),
+						ChannelId: channelID,
+					}),
+				},
+				Data: utils.MarshalOrPanic(&common.ConfigEnvelope{Config: config}),
+			}),
+		}
+	}
+
+	consenters := []*bft.Consenter{n.consenters[1], n.consenters[2], n.consenters[3], n.consenters[4]}
+	assert.NoError(t, chain.checkConfigUpdateValidity(configEnv(consenters, "bft")))
+	assert.EqualError(t, chain.checkConfigUpdateValidity(configEnv(consenters, "etcdraft")), "consensus type cannot be changed from bft to etcdraft")
+	assert.EqualError(t, chain.checkConfigUpdateValidity(configEnv(consenters[:3], "bft")), "consenters of a bft channel cannot be changed")
+	assert.EqualError(t, chain.checkConfigUpdateValidity(configEnv([]*bft.Consenter{
+		n.consenters[2], n.consenters[1], n.consenters[3], n.consenters[4],
+	}, "bft")), "consenters of a bft channel cannot be changed")
+}
@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/inactive"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/pkg/errors"
+)
+
+// Consenter implements the BFT consenter. It shares the cluster
+// communication of the etcdraft consenter, which dispatches the
+// messages of BFT channels to their chains.
+type Consenter struct {
+	Dialer        *cluster.PredicateDialer
+	Communication cluster.Communicator
+	Logger        *flogging.FabricLogger
+	OrdererConfig localconfig.TopLevel
+	Cert          []byte
+}
+
+// mspManagerSupport is implemented by the consenter support of the
+// orderer, and provides the identities of the channel members.
+type mspManagerSupport interface {
+	MSPManager() msp.MSPManager
+}
+
+func (c *Consenter) detectSelfID(consenters map[uint64]*bft.Consenter) (uint64, error) {
+	thisNodeCertAsDER, err := pemToDER(c.Cert, 0, "server", c.Logger)
+	if err != nil {
+		return 0, err
+	}
+
+	for nodeID, cst := range consenters {
+		certAsDER, err := pemToDER(cst.ServerTlsCert, nodeID, "server", c.Logger)
+		if err != nil {
+			return 0, err
+		}
+
+		if bytes.Equal(thisNodeCertAsDER, certAsDER) {
+			return nodeID, nil
+		}
+	}
+
+	c.Logger.Warning("Could not find", string(c.Cert), "among the consenters")
+	return 0, cluster.ErrNotInChannel
+}
+
+// HandleChain returns a new Chain instance or an error upon failure
+func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *common.Metadata) (consensus.Chain, error) {
+	m := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(support.SharedConfig().ConsensusMetadata(), m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus metadata")
+	}
+
+	if m.Options == nil {
+		return nil, errors.New("bft options have not been provided")
+	}
+
+	mspSupport, ok := support.(mspManagerSupport)
+	if !ok {
+		return nil, errors.New("consenter support does not provide the MSP manager of the channel")
+	}
+
+	requestTimeout, err := time.ParseDuration(m.Options.RequestTimeout)
+	if err != nil {
+		return nil, errors.Errorf("failed to parse RequestTimeout (%s) to time duration", m.Options.RequestTimeout)
+	}
+	viewChangeTimeout, err := time.ParseDuration(m.Options.ViewChangeTimeout)
+	if err != nil {
+		return nil, errors.Errorf("failed to parse ViewChangeTimeout (%s) to time duration", m.Options.ViewChangeTimeout)
+	}
+
+	blockMetadata, err := ReadBlockMetadata(metadata)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read BFT metadata")
+	}
+
+	// The consenters of a BFT channel never change,
+	// so their IDs are their positions in the config.
+	consenters := map[uint64]*bft.Consenter{}
+	for i, consenter := range m.Consenters {
+		consenters[uint64(i+1)] = consenter
+	}
+
+	id, err := c.detectSelfID(consenters)
+	if err != nil {
+		return &inactive.Chain{Err: errors.Errorf("channel %s is not serviced by me", support.ChainID())}, nil
+	}
+
+	opts := Options{
+		SelfID:            id,
+		Consenters:        consenters,
+		View:              blockMetadata.View,
+		RequestTimeout:    requestTimeout,
+		ViewChangeTimeout: viewChangeTimeout,
+		Clock:             clock.NewClock(),
+		Logger:            c.Logger,
+		Verifier:          &MSPVerifier{MSPManager: mspSupport.MSPManager},
+	}
+
+	rpc := &cluster.RPC{
+		Timeout:       c.OrdererConfig.General.Cluster.RPCTimeout,
+		Logger:        c.Logger,
+		Channel:       support.ChainID(),
+		Comm:          c.Communication,
+		StreamsByType: cluster.NewStreamsByType(),
+	}
+	return NewChain(
+		support,
+		opts,
+		c.Communication,
+		rpc,
+		func() (BlockPuller, error) { return newBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster) },
+	)
+}
+
+// New creates a BFT Consenter which communicates through the given cluster communication.
+func New(
+	clusterDialer *cluster.PredicateDialer,
+	communication cluster.Communicator,
+	conf *localconfig.TopLevel,
+	srvConf comm.ServerConfig,
+) *Consenter {
+	return &Consenter{
+		Dialer:        clusterDialer,
+		Communication: communication,
+		Logger:        flogging.MustGetLogger("orderer.consensus.bft"),
+		OrdererConfig: *conf,
+		Cert:          srvConf.SecOpts.Certificate,
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"encoding/pem"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// newBlockPuller creates a new block puller, which verifies the
+// pulled blocks against the block validation policy of the channel.
+func newBlockPuller(support consensus.ConsenterSupport,
+	baseDialer *cluster.PredicateDialer,
+	clusterConfig localconfig.Cluster) (BlockPuller, error) {
+
+	verifyBlockSequence := func(blocks []*common.Block, _ string) error {
+		return cluster.VerifyBlocks(blocks, support)
+	}
+
+	stdDialer := &cluster.StandardDialer{
+		ClientConfig: baseDialer.ClientConfig.Clone(),
+	}
+	stdDialer.ClientConfig.AsyncConnect = false
+	stdDialer.ClientConfig.SecOpts.VerifyCertificate = nil
+
+	endpoints, err := etcdraft.EndpointconfigFromFromSupport(support)
+	if err != nil {
+		return nil, err
+	}
+
+	der, _ := pem.Decode(stdDialer.ClientConfig.SecOpts.Certificate)
+	if der == nil {
+		return nil, errors.Errorf("client certificate isn't in PEM format: %v",
+			string(stdDialer.ClientConfig.SecOpts.Certificate))
+	}
+
+	bp := &cluster.BlockPuller{
+		VerifyBlockSequence: verifyBlockSequence,
+		Logger:              flogging.MustGetLogger("orderer.common.cluster.puller"),
+		RetryTimeout:        clusterConfig.ReplicationRetryTimeout,
+		MaxTotalBufferBytes: clusterConfig.ReplicationBufferSize,
+		FetchTimeout:        clusterConfig.ReplicationPullTimeout,
+		Endpoints:           endpoints,
+		Signer:              support,
+		TLSCert:             der.Bytes,
+		Channel:             support.ChainID(),
+		Dialer:              stdDialer,
+	}
+
+	return &etcdraft.LedgerBlockPuller{
+		Height:         support.Height,
+		BlockRetriever: support,
+		BlockPuller:    bp,
+	}, nil
+}
+
+// configFromEnvelope returns the config carried by a config transaction,
+// or by the config transaction wrapped in an orderer transaction.
+func configFromEnvelope(env *common.Envelope) (*common.Config, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing payload header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	switch chdr.Type {
+	case int32(common.HeaderType_ORDERER_TRANSACTION):
+		inner, err := utils.UnmarshalEnvelope(payload.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal config envelope for orderer type transaction")
+		}
+		return configFromEnvelope(inner)
+	case int32(common.HeaderType_CONFIG):
+		configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+		if err != nil {
+			return nil, err
+		}
+		if configEnvelope.Config == nil {
+			return nil, errors.New("missing config")
+		}
+		return configEnvelope.Config, nil
+	default:
+		return nil, errors.Errorf("unexpected header type: %v", chdr.Type)
+	}
+}
+
+// isChannelConfigBlock returns whether the block updates the config of
+// the channel, as opposed to a block that creates another channel.
+func isChannelConfigBlock(block *common.Block) bool {
+	if !utils.IsConfigBlock(block) {
+		return false
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return false
+	}
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return false
+	}
+	return chdr.Type == int32(common.HeaderType_CONFIG)
+}
+
+// ReadBlockMetadata returns the BFT metadata of the block, which is empty
+// for blocks that were not ordered by the BFT nodes.
+func ReadBlockMetadata(blockMetadata *common.Metadata) (*bft.BlockMetadata, error) {
+	m := &bft.BlockMetadata{}
+	if blockMetadata == nil || len(blockMetadata.Value) == 0 {
+		return m, nil
+	}
+	if err := proto.Unmarshal(blockMetadata.Value, m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal block's metadata")
+	}
+	return m, nil
+}
+
+// blockView returns the view in which the given block was committed.
+func blockView(block *common.Block) (uint64, error) {
+	metadata, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_ORDERER)
+	if err != nil {
+		return 0, err
+	}
+	m, err := ReadBlockMetadata(metadata)
+	if err != nil {
+		return 0, err
+	}
+	return m.View, nil
+}
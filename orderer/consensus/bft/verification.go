@@ -0,0 +1,328 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// Verifier verifies the signatures of the consenters.
+type Verifier interface {
+	// Verify verifies that the given signature over the given message
+	// was created by the given serialized identity.
+	Verify(identity, message, signature []byte) error
+}
+
+// MSPVerifier verifies signatures with the identities
+// deserialized by the MSP manager of the channel.
+type MSPVerifier struct {
+	MSPManager func() msp.MSPManager
+}
+
+// Verify verifies the signature with the identity deserialized by the MSP manager.
+func (v *MSPVerifier) Verify(identity, message, signature []byte) error {
+	id, err := v.MSPManager().DeserializeIdentity(identity)
+	if err != nil {
+		return errors.WithMessage(err, "failed deserializing identity")
+	}
+	return id.Verify(message, signature)
+}
+
+// consenterSet holds the consenters of a channel,
+// and verifies the messages and signatures they produce.
+type consenterSet struct {
+	ids        []uint64
+	identities map[uint64][]byte
+	verifier   Verifier
+}
+
+func newConsenterSet(consenters map[uint64]*bft.Consenter, verifier Verifier) *consenterSet {
+	cs := &consenterSet{
+		identities: make(map[uint64][]byte, len(consenters)),
+		verifier:   verifier,
+	}
+	for id, consenter := range consenters {
+		cs.ids = append(cs.ids, id)
+		cs.identities[id] = serializedIdentity(consenter)
+	}
+	sort.Slice(cs.ids, func(i, j int) bool { return cs.ids[i] < cs.ids[j] })
+	return cs
+}
+
+// serializedIdentity returns the serialized signing identity of the consenter.
+func serializedIdentity(consenter *bft.Consenter) []byte {
+	return utils.MarshalOrPanic(&mspproto.SerializedIdentity{
+		Mspid:   consenter.MspId,
+		IdBytes: consenter.Identity,
+	})
+}
+
+// sameIdentity returns whether the two serialized identities carry
+// the same MSP ID and certificate, regardless of their PEM encoding.
+func sameIdentity(a, b []byte) bool {
+	idA, idB := &mspproto.SerializedIdentity{}, &mspproto.SerializedIdentity{}
+	if proto.Unmarshal(a, idA) != nil || proto.Unmarshal(b, idB) != nil {
+		return false
+	}
+	if idA.Mspid != idB.Mspid {
+		return false
+	}
+	blockA, _ := pem.Decode(idA.IdBytes)
+	blockB, _ := pem.Decode(idB.IdBytes)
+	if blockA == nil || blockB == nil {
+		return bytes.Equal(idA.IdBytes, idB.IdBytes)
+	}
+	return bytes.Equal(blockA.Bytes, blockB.Bytes)
+}
+
+func (cs *consenterSet) size() int {
+	return len(cs.ids)
+}
+
+func (cs *consenterSet) quorum() int {
+	return bft.QuorumSize(len(cs.ids))
+}
+
+// faults returns the number of byzantine consenters tolerated.
+func (cs *consenterSet) faults() int {
+	return (len(cs.ids) - 1) / 3
+}
+
+func (cs *consenterSet) leader(view uint64) uint64 {
+	return cs.ids[view%uint64(len(cs.ids))]
+}
+
+func (cs *consenterSet) contains(id uint64) bool {
+	_, exists := cs.identities[id]
+	return exists
+}
+
+// idOf returns the ID of the consenter with the given serialized identity, or 0.
+func (cs *consenterSet) idOf(identity []byte) uint64 {
+	for _, id := range cs.ids {
+		if sameIdentity(cs.identities[id], identity) {
+			return id
+		}
+	}
+	return 0
+}
+
+func (cs *consenterSet) verify(signer uint64, message, signature []byte) error {
+	identity, exists := cs.identities[signer]
+	if !exists {
+		return errors.Errorf("node %d is not a consenter", signer)
+	}
+	if err := cs.verifier.Verify(identity, message, signature); err != nil {
+		return errors.WithMessage(err, "invalid signature")
+	}
+	return nil
+}
+
+func uint64Bytes(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
+
+func prepareSigningBytes(view, sequence uint64, digest []byte) []byte {
+	return util.ConcatenateBytes([]byte("prepare"), uint64Bytes(view), uint64Bytes(sequence), digest)
+}
+
+func viewChangeSigningBytes(vc *bft.ViewChange) []byte {
+	var preparedView uint64
+	var preparedDigest, checkpointDigest []byte
+	if vc.Prepared != nil && vc.Prepared.Block != nil {
+		preparedView = vc.Prepared.View
+		preparedDigest = vc.Prepared.Block.Header.Hash()
+	}
+	if vc.CheckpointHeader != nil {
+		checkpointDigest = vc.CheckpointHeader.Hash()
+	}
+	return util.ConcatenateBytes(
+		[]byte("viewchange"),
+		uint64Bytes(vc.View),
+		uint64Bytes(vc.Sequence),
+		uint64Bytes(vc.Signer),
+		uint64Bytes(preparedView),
+		preparedDigest,
+		checkpointDigest,
+	)
+}
+
+func (cs *consenterSet) verifyPrepare(prepare *bft.Prepare) error {
+	return cs.verify(prepare.Signer, prepareSigningBytes(prepare.View, prepare.Sequence, prepare.Digest), prepare.Signature)
+}
+
+// verifyBlockSignature verifies a signature over the block header
+// and returns the ID of the consenter that created it.
+func (cs *consenterSet) verifyBlockSignature(header *common.BlockHeader, value []byte, signature *common.MetadataSignature) (uint64, error) {
+	if signature == nil {
+		return 0, errors.New("missing signature")
+	}
+	sigHdr, err := utils.GetSignatureHeader(signature.SignatureHeader)
+	if err != nil {
+		return 0, err
+	}
+	signer := cs.idOf(sigHdr.Creator)
+	if signer == 0 {
+		return 0, errors.New("signature was not created by a consenter")
+	}
+	message := util.ConcatenateBytes(value, signature.SignatureHeader, header.Bytes())
+	if err := cs.verify(signer, message, signature.Signature); err != nil {
+		return 0, errors.WithMessage(err, "failed verifying block signature")
+	}
+	return signer, nil
+}
+
+// verifyBlockSignatures verifies that the given block signatures were
+// created over the block header by a quorum of consenters.
+func (cs *consenterSet) verifyBlockSignatures(header *common.BlockHeader, signatures *common.Metadata) error {
+	if header == nil || signatures == nil {
+		return errors.New("missing block header or signatures")
+	}
+	signers := make(map[uint64]struct{})
+	for _, signature := range signatures.Signatures {
+		signer, err := cs.verifyBlockSignature(header, signatures.Value, signature)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid signature of block %d", header.Number))
+		}
+		signers[signer] = struct{}{}
+	}
+	if len(signers) < cs.quorum() {
+		return errors.Errorf("block %d is signed by %d consenters, but %d are required", header.Number, len(signers), cs.quorum())
+	}
+	return nil
+}
+
+// VerifyBlock verifies that the block is consistent with its header,
+// and is signed by a quorum of the given consenters.
+func VerifyBlock(block *common.Block, consenters map[uint64]*bft.Consenter, verifier Verifier) error {
+	if err := verifyBlockStructure(block); err != nil {
+		return err
+	}
+	signatures, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed reading signatures of block %d", block.Header.Number))
+	}
+	return newConsenterSet(consenters, verifier).verifyBlockSignatures(block.Header, signatures)
+}
+
+func verifyBlockStructure(block *common.Block) error {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return errors.New("empty block")
+	}
+	if !bytes.Equal(block.Header.DataHash, block.Data.Hash()) {
+		return errors.Errorf("data hash of block %d does not match its data", block.Header.Number)
+	}
+	return nil
+}
+
+// verifyPreparedProof verifies that the block of the proof was
+// prepared by a quorum of consenters in the view of the proof.
+func (cs *consenterSet) verifyPreparedProof(proof *bft.PreparedProof) error {
+	if err := verifyBlockStructure(proof.Block); err != nil {
+		return errors.WithMessage(err, "invalid prepared block")
+	}
+	if proof.Block.Header.Number != proof.Sequence {
+		return errors.Errorf("prepared block number %d does not match sequence %d", proof.Block.Header.Number, proof.Sequence)
+	}
+	digest := proof.Block.Header.Hash()
+	signers := make(map[uint64]struct{})
+	for _, prepare := range proof.Prepares {
+		if prepare.View != proof.View || prepare.Sequence != proof.Sequence || !bytes.Equal(prepare.Digest, digest) {
+			return errors.Errorf("prepare of node %d does not match the prepared block", prepare.Signer)
+		}
+		if err := cs.verifyPrepare(prepare); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid prepare of node %d", prepare.Signer))
+		}
+		signers[prepare.Signer] = struct{}{}
+	}
+	if len(signers) < cs.quorum() {
+		return errors.Errorf("block is prepared by %d consenters, but %d are required", len(signers), cs.quorum())
+	}
+	return nil
+}
+
+// verifyViewChange verifies the signature of the view change,
+// its prepared proof and its checkpoint.
+func (cs *consenterSet) verifyViewChange(vc *bft.ViewChange) error {
+	if err := cs.verify(vc.Signer, viewChangeSigningBytes(vc), vc.Signature); err != nil {
+		return err
+	}
+	if vc.Prepared != nil {
+		if vc.Prepared.Sequence != vc.Sequence || vc.Prepared.View >= vc.View {
+			return errors.Errorf("prepared proof of view %d and sequence %d does not fit view change to view %d at sequence %d",
+				vc.Prepared.View, vc.Prepared.Sequence, vc.View, vc.Sequence)
+		}
+		if err := cs.verifyPreparedProof(vc.Prepared); err != nil {
+			return errors.WithMessage(err, "invalid prepared proof")
+		}
+	}
+	// The genesis block is not signed by the consenters.
+	if vc.Sequence <= 1 {
+		return nil
+	}
+	if vc.CheckpointHeader == nil || vc.CheckpointHeader.Number != vc.Sequence-1 {
+		return errors.Errorf("missing checkpoint of block %d", vc.Sequence-1)
+	}
+	return errors.WithMessage(cs.verifyBlockSignatures(vc.CheckpointHeader, vc.CheckpointSignatures), "invalid checkpoint")
+}
+
+// verifyNewView verifies the view changes carried by the new view, and returns
+// the sequence the new view starts at along with the block that has to be
+// proposed at that sequence, if any.
+func (cs *consenterSet) verifyNewView(nv *bft.NewView) (uint64, *bft.PreparedProof, error) {
+	signers := make(map[uint64]struct{})
+	for _, vc := range nv.ViewChanges {
+		if vc.View != nv.View {
+			return 0, nil, errors.Errorf("view change of node %d is to view %d instead of %d", vc.Signer, vc.View, nv.View)
+		}
+		if err := cs.verifyViewChange(vc); err != nil {
+			return 0, nil, errors.WithMessage(err, fmt.Sprintf("invalid view change of node %d", vc.Signer))
+		}
+		signers[vc.Signer] = struct{}{}
+	}
+	if len(signers) < cs.quorum() {
+		return 0, nil, errors.Errorf("new view carries view changes of %d consenters, but %d are required", len(signers), cs.quorum())
+	}
+	sequence, proof := selectProof(nv.ViewChanges)
+	return sequence, proof, nil
+}
+
+// selectProof returns the highest sequence among the view changes, and the
+// prepared proof with the highest view at that sequence, if any.
+func selectProof(viewChanges []*bft.ViewChange) (uint64, *bft.PreparedProof) {
+	var sequence uint64
+	for _, vc := range viewChanges {
+		if vc.Sequence > sequence {
+			sequence = vc.Sequence
+		}
+	}
+	var proof *bft.PreparedProof
+	for _, vc := range viewChanges {
+		if vc.Sequence != sequence || vc.Prepared == nil {
+			continue
+		}
+		if proof == nil || vc.Prepared.View > proof.View {
+			proof = vc.Prepared
+		}
+	}
+	return sequence, proof
+}
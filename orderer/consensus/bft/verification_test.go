@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyBlock(t *testing.T) {
+	n := newNetwork(t, 4, 1)
+
+	newBlock := func(signers ...uint64) *common.Block {
+		block := n.nodes[1].chain.newBlock([]*common.Envelope{envelope("tx")})
+		metadata := &common.Metadata{Value: n.nodes[1].chain.blockSignatureValue(block, 0)}
+		for _, signer := range signers {
+			metadata.Signatures = append(metadata.Signatures, n.nodes[signer].chain.signBlock(block, 0))
+		}
+		block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(metadata)
+		return block
+	}
+
+	assert.NoError(t, VerifyBlock(newBlock(1, 2, 3), n.consenters, ecdsaVerifier{}))
+	assert.NoError(t, VerifyBlock(newBlock(4, 3, 2, 1), n.consenters, ecdsaVerifier{}))
+	assert.EqualError(t, VerifyBlock(newBlock(1, 2), n.consenters, ecdsaVerifier{}), "block 1 is signed by 2 consenters, but 3 are required")
+	assert.EqualError(t, VerifyBlock(newBlock(1, 2, 2), n.consenters, ecdsaVerifier{}), "block 1 is signed by 2 consenters, but 3 are required")
+
+	block := newBlock(1, 2, 3)
+	block.Header.Number = 2
+	assert.EqualError(t, VerifyBlock(block, n.consenters, ecdsaVerifier{}), "invalid signature of block 2: failed verifying block signature: invalid signature: invalid signature")
+
+	block = newBlock(1, 2, 3)
+	block.Data.Data = append(block.Data.Data, []byte("tx"))
+	assert.EqualError(t, VerifyBlock(block, n.consenters, ecdsaVerifier{}), "data hash of block 1 does not match its data")
+
+	otherConsenters := map[uint64]*bft.Consenter{
+		1: n.consenters[1],
+		2: n.consenters[2],
+		3: n.consenters[3],
+		4: {MspId: "OtherOrg", Identity: n.consenters[4].Identity},
+	}
+	assert.EqualError(t, VerifyBlock(newBlock(1, 2, 4), otherConsenters, ecdsaVerifier{}), "invalid signature of block 1: signature was not created by a consenter")
+}
+
+func TestConsenterSet(t *testing.T) {
+	consenters := map[uint64]*bft.Consenter{}
+	for _, id := range []uint64{7, 3, 5, 1} {
+		consenters[id] = &bft.Consenter{MspId: "OrdererOrg", Identity: []byte{byte(id)}}
+	}
+	cs := newConsenterSet(consenters, ecdsaVerifier{})
+
+	assert.Equal(t, 4, cs.size())
+	assert.Equal(t, 3, cs.quorum())
+	assert.Equal(t, 1, cs.faults())
+	assert.Equal(t, []uint64{1, 3, 5, 7}, cs.ids)
+	assert.Equal(t, uint64(1), cs.leader(0))
+	assert.Equal(t, uint64(7), cs.leader(3))
+	assert.Equal(t, uint64(3), cs.leader(5))
+	assert.Equal(t, uint64(5), cs.idOf(serializedIdentity(consenters[5])))
+	assert.Equal(t, uint64(0), cs.idOf(serializedIdentity(&bft.Consenter{MspId: "OtherOrg", Identity: []byte{5}})))
+}
+
+func TestSelectProof(t *testing.T) {
+	proof := func(view uint64) *bft.PreparedProof {
+		return &bft.PreparedProof{View: view, Sequence: 5}
+	}
+
+	for _, testCase := range []struct {
+		name             string
+		viewChanges      []*bft.ViewChange
+		expectedSequence uint64
+		expectedProof    *bft.PreparedProof
+	}{
+		{
+			name: "nothing prepared",
+			viewChanges: []*bft.ViewChange{
+				{Sequence: 4},
+				{Sequence: 5},
+				{Sequence: 5},
+			},
+			expectedSequence: 5,
+		},
+		{
+			name: "highest view",
+			viewChanges: []*bft.ViewChange{
+				{Sequence: 5, Prepared: proof(1)},
+				{Sequence: 5, Prepared: proof(3)},
+				{Sequence: 5},
+			},
+			expectedSequence: 5,
+			expectedProof:    proof(3),
+		},
+		{
+			name: "committed",
+			viewChanges: []*bft.ViewChange{
+				{Sequence: 5, Prepared: proof(3)},
+				{Sequence: 6},
+				{Sequence: 5},
+			},
+			expectedSequence: 6,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			sequence, proof := selectProof(testCase.viewChanges)
+			assert.Equal(t, testCase.expectedSequence, sequence)
+			assert.Equal(t, testCase.expectedProof, proof)
+		})
+	}
+}
@@ -114,3 +114,20 @@ type ConsenterSupport interface {
 	// unlike WriteBlock that also mutates its metadata.
 	Append(block *cb.Block) error
 }
+
+// ChainRemover is implemented by the consenters which keep state of their own for a chain,
+// outside of the ledger, that must be removed when the chain is removed from the orderer.
+type ChainRemover interface {
+	// RemoveChain removes the state of the consenter for the given chain.
+	// The chain must have been halted.
+	RemoveChain(chainID string) error
+}
+
+// LeadershipTransferer is implemented by the chains whose consensus elects a leader, which can be
+// moved away from the orderer before the orderer is stopped.
+type LeadershipTransferer interface {
+	// TransferLeadership transfers the leadership of the chain to another consenter, if this orderer
+	// is the leader, and waits for the transfer to complete. It returns the ID of the leader
+	// after the transfer.
+	TransferLeadership() (uint64, error)
+}
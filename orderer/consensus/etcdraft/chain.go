@@ -18,6 +18,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/protos/common"
@@ -27,7 +28,6 @@ import (
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/raft"
 	"go.etcd.io/etcd/raft/raftpb"
-	"go.etcd.io/etcd/wal"
 )
 
 const (
@@ -105,6 +105,10 @@ type Options struct {
 	MemoryStorage MemoryStorage
 	Logger        *flogging.FabricLogger
 
+	// Encryption encrypts the WAL and snapshots of the chain at rest,
+	// if it is configured with a key.
+	Encryption *Encryption
+
 	TickInterval      time.Duration
 	ElectionTick      int
 	HeartbeatTick     int
@@ -147,8 +151,10 @@ type Chain struct {
 	channelID string
 
 	lastKnownLeader uint64
+	draining        uint32 // set while leadership is being transferred, accessed atomically
 
 	submitC  chan *submit
+	drainC   chan chan struct{} // Signals serveRequest to stop accepting requests as leader, nil to resume
 	applyC   chan apply
 	observeC chan<- raft.SoftState // Notifies external observer on leader change (passed in optionally as an argument for tests)
 	haltC    chan struct{}         // Signals to goroutines that the chain is halting
@@ -207,8 +213,7 @@ func NewChain(
 
 	lg := opts.Logger.With("channel", support.ChainID(), "node", opts.RaftID)
 
-	fresh := !wal.Exist(opts.WALDir)
-	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage)
+	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage, opts.Encryption)
 	if err != nil {
 		return nil, errors.Errorf("failed to restore persisted raft data: %s", err)
 	}
@@ -244,6 +249,7 @@ func NewChain(
 		channelID:        support.ChainID(),
 		raftID:           opts.RaftID,
 		submitC:          make(chan *submit),
+		drainC:           make(chan chan struct{}),
 		applyC:           make(chan apply),
 		haltC:            make(chan struct{}),
 		doneC:            make(chan struct{}),
@@ -253,7 +259,7 @@ func NewChain(
 		gcC:              make(chan *gc),
 		observeC:         observeC,
 		support:          support,
-		fresh:            fresh,
+		fresh:            storage.fresh,
 		appliedIndex:     opts.BlockMetadata.RaftIndex,
 		lastBlock:        b,
 		sizeLimit:        sizeLimit,
@@ -457,6 +463,10 @@ func (c *Chain) WaitReady() error {
 		return err
 	}
 
+	if atomic.LoadUint32(&c.draining) == 1 {
+		return errors.Errorf("leadership transfer is in progress")
+	}
+
 	select {
 	case c.submitC <- nil:
 	case <-c.doneC:
@@ -528,6 +538,51 @@ func (c *Chain) Consensus(req *orderer.ConsensusRequest, sender uint64) error {
 	return nil
 }
 
+// TransferLeadership transfers the Raft leadership of the chain to the most
+// up-to-date follower, if this node is the leader, and waits for the transfer
+// to complete. Meanwhile, new requests submitted to this node are rejected,
+// and the blocks in flight are committed before the transfer starts. The requests
+// forwarded by the followers are held, and forwarded to the new leader once it
+// is elected. It returns the ID of the leader after the transfer.
+func (c *Chain) TransferLeadership() (uint64, error) {
+	if err := c.isRunning(); err != nil {
+		return raft.None, err
+	}
+
+	if !atomic.CompareAndSwapUint32(&c.draining, 0, 1) {
+		return raft.None, errors.Errorf("leadership transfer is already in progress")
+	}
+	defer func() {
+		atomic.StoreUint32(&c.draining, 0)
+		select {
+		case c.drainC <- nil:
+		case <-c.doneC:
+		}
+	}()
+
+	timeout := time.Duration(c.opts.ElectionTick) * c.opts.TickInterval
+
+	drained := make(chan struct{})
+	select {
+	case c.drainC <- drained:
+	case <-c.doneC:
+		return raft.None, errors.Errorf("chain is stopped")
+	}
+
+	select {
+	case <-drained:
+	case <-time.After(timeout):
+		return raft.None, errors.Errorf("timed out waiting for in-flight blocks to be committed")
+	case <-c.doneC:
+		return raft.None, errors.Errorf("chain is stopped")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return c.Node.transferLeadership(ctx)
+}
+
 // Submit forwards the incoming request to:
 // - the local serveRequest goroutine if this is leader
 // - the actual leader via the transport mechanism
@@ -538,6 +593,11 @@ func (c *Chain) Submit(req *orderer.SubmitRequest, sender uint64) error {
 		return err
 	}
 
+	if sender == 0 && atomic.LoadUint32(&c.draining) == 1 {
+		c.Metrics.ProposalFailures.Add(1)
+		return errors.Errorf("leadership transfer is in progress")
+	}
+
 	leadC := make(chan uint64, 1)
 	select {
 	case c.submitC <- &submit{req, leadC}:
@@ -584,7 +644,7 @@ func (c *Chain) serveRequest() {
 	startTimer := func() {
 		if !ticking {
 			ticking = true
-			timer.Reset(c.support.SharedConfig().BatchTimeout())
+			timer.Reset(blockcutter.BatchTimeout(c.support.BlockCutter(), c.support.SharedConfig()))
 		}
 	}
 
@@ -600,6 +660,24 @@ func (c *Chain) serveRequest() {
 	submitC := c.submitC
 	var bc *blockCreator
 
+	// set while leadership is being transferred, during which the leader
+	// stops accepting requests, and closes drained once blocks in flight
+	// are committed.
+	var draining bool
+	var drained chan struct{}
+
+	checkDrained := func() {
+		if !draining || soft.Lead != c.raftID {
+			return
+		}
+
+		submitC = nil
+		if drained != nil && !c.justElected && !c.configInflight && c.blockInflight == 0 {
+			close(drained)
+			drained = nil
+		}
+	}
+
 	var propC chan<- *common.Block
 	var cancelProp context.CancelFunc
 	cancelProp = func() {} // no-op as initial value
@@ -659,6 +737,11 @@ func (c *Chain) serveRequest() {
 		submitC = c.submitC
 		bc = nil
 		c.Metrics.IsLeader.Set(0)
+
+		if drained != nil {
+			close(drained)
+			drained = nil
+		}
 	}
 
 	for {
@@ -782,6 +865,31 @@ func (c *Chain) serveRequest() {
 				submitC = c.submitC
 			}
 
+			checkDrained()
+
+		case done := <-c.drainC:
+			if done == nil {
+				draining = false
+				drained = nil
+				if soft.Lead == c.raftID && !c.justElected && !c.configInflight && c.blockInflight < c.opts.MaxInflightBlocks {
+					submitC = c.submitC
+				}
+				continue
+			}
+
+			if soft.Lead != c.raftID {
+				close(done)
+				continue
+			}
+
+			c.logger.Infof("Leadership transfer requested, stop accepting requests and cut pending batch")
+			draining, drained = true, done
+			if batch := c.support.BlockCutter().Cut(); len(batch) != 0 && bc != nil {
+				c.propose(propC, bc, batch)
+			}
+			stopTimer()
+			checkDrained()
+
 		case <-timer.C():
 			ticking = false
 
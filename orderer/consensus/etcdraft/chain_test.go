@@ -2702,6 +2702,79 @@ var _ = Describe("Chain", func() {
 					})
 			})
 
+			When("leadership is transferred", func() {
+				It("returns the current leader right away on follower", func() {
+					lead, err := c2.TransferLeadership()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lead).To(Equal(uint64(1)))
+					Consistently(c2.observe).ShouldNot(Receive())
+				})
+
+				It("commits pending requests and transfers leadership to the most up-to-date follower", func() {
+					// 3 falls behind 2 once the pending batch is committed
+					network.disconnect(3)
+
+					c1.cutter.CutNext = false
+					Expect(c1.Order(env, 0)).To(Succeed())
+					Eventually(c1.cutter.CurBatch, LongEventualTimeout).Should(HaveLen(1))
+
+					type result struct {
+						lead uint64
+						err  error
+					}
+					resultC := make(chan result, 1)
+					go func() {
+						lead, err := c1.TransferLeadership()
+						resultC <- result{lead, err}
+					}()
+
+					By("cutting the pending batch and proposing it to followers")
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+					}, 1, 2)
+
+					Eventually(resultC, LongEventualTimeout).Should(Receive(Equal(result{lead: 2})))
+					Eventually(c1.observe, LongEventualTimeout).Should(Receive(StateEqual(2, raft.StateFollower)))
+					network.Lock()
+					network.leader = 2
+					network.Unlock()
+
+					By("forwarding requests to the new leader")
+					c2.cutter.CutNext = true
+					Expect(c1.Order(env, 0)).To(Succeed())
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(2))
+					}, 1, 2)
+				})
+
+				It("rejects new requests until in flight blocks are committed", func() {
+					c1.cutter.CutNext = true
+					network.disconnect(1)
+					Expect(c1.Order(env, 0)).To(Succeed())
+
+					errorC := make(chan error, 1)
+					go func() {
+						_, err := c1.TransferLeadership()
+						errorC <- err
+					}()
+
+					Eventually(c1.WaitReady, LongEventualTimeout).Should(MatchError("leadership transfer is in progress"))
+					Expect(c1.Order(env, 0)).To(MatchError("leadership transfer is in progress"))
+					_, err := c1.TransferLeadership()
+					Expect(err).To(MatchError("leadership transfer is already in progress"))
+
+					network.connect(1)
+					c1.clock.Increment(interval)
+
+					Eventually(errorC, LongEventualTimeout).Should(Receive(BeNil()))
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+					})
+					Eventually(c1.observe, LongEventualTimeout).Should(Receive(BeFollower()))
+					Expect(c1.WaitReady()).To(Succeed())
+				})
+			})
+
 			When("MaxInflightBlocks is reached", func() {
 				BeforeEach(func() {
 					network.exec(func(c *chain) { c.opts.MaxInflightBlocks = 1 })
@@ -8,12 +8,14 @@ package etcdraft
 
 import (
 	"bytes"
+	"os"
 	"path"
 	"reflect"
 	"time"
 
 	"code.cloudfoundry.org/clock"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/viperutil"
@@ -57,6 +59,7 @@ type Config struct {
 	WALDir            string // WAL data of <my-channel> is stored in WALDir/<my-channel>
 	SnapDir           string // Snapshots of <my-channel> are stored in SnapDir/<my-channel>
 	EvictionSuspicion string // Duration threshold that the node samples in order to suspect its eviction from the channel.
+	EncryptionKey     string // Hex encoded SKI of the BCCSP key which encrypts the WAL data and snapshots, if any.
 }
 
 // Consenter implements etcdraft consenter
@@ -72,6 +75,7 @@ type Consenter struct {
 	OrdererConfig  localconfig.TopLevel
 	Cert           []byte
 	Metrics        *Metrics
+	Encryption     *Encryption
 }
 
 // TargetChannel extracts the channel from the given proto.Message.
@@ -97,10 +101,12 @@ func (c *Consenter) ReceiverByChain(channelID string) MessageReceiver {
 	if cs.Chain == nil {
 		c.Logger.Panicf("Programming error - Chain %s is nil although it exists in the mapping", channelID)
 	}
-	if etcdRaftChain, isEtcdRaftChain := cs.Chain.(*Chain); isEtcdRaftChain {
-		return etcdRaftChain
+	// Chains of other cluster types, such as BFT chains,
+	// share the cluster communication of etcdraft chains.
+	if receiver, isReceiver := cs.Chain.(MessageReceiver); isReceiver {
+		return receiver
 	}
-	c.Logger.Warningf("Chain %s is of type %v and not etcdraft.Chain", channelID, reflect.TypeOf(cs.Chain))
+	c.Logger.Warningf("Chain %s is of type %v and does not receive cluster messages", channelID, reflect.TypeOf(cs.Chain))
 	return nil
 }
 
@@ -162,9 +168,7 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 
 	id, err := c.detectSelfID(consenters)
 	if err != nil {
-		c.InactiveChainRegistry.TrackChain(support.ChainID(), support.Block(0), func() {
-			c.CreateChain(support.ChainID())
-		})
+		c.trackInactiveChain(support.ChainID(), support.Block(0))
 		return &inactive.Chain{Err: errors.Errorf("channel %s is not serviced by me", support.ChainID())}, nil
 	}
 
@@ -207,6 +211,7 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		EvictionSuspicion: evictionSuspicion,
 		Cert:              c.Cert,
 		Metrics:           c.Metrics,
+		Encryption:        c.Encryption,
 	}
 
 	rpc := &cluster.RPC{
@@ -222,13 +227,36 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		c.Communication,
 		rpc,
 		func() (BlockPuller, error) { return newBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster) },
-		func() {
-			c.InactiveChainRegistry.TrackChain(support.ChainID(), nil, func() { c.CreateChain(support.ChainID()) })
-		},
+		func() { c.trackInactiveChain(support.ChainID(), nil) },
 		nil,
 	)
 }
 
+// trackInactiveChain hands the chain over to the InactiveChainRegistry, which
+// re-creates it once this node is added to the chain. There is no such registry
+// when the orderer runs without a system channel, and the chain then stays inactive
+// until it is removed and joined again.
+func (c *Consenter) trackInactiveChain(chainID string, genesisBlock *common.Block) {
+	if c.InactiveChainRegistry == nil {
+		c.Logger.Warningf("Channel %s is not serviced by me and there is no system channel to track it, it remains inactive", chainID)
+		return
+	}
+	c.InactiveChainRegistry.TrackChain(chainID, genesisBlock, func() { c.CreateChain(chainID) })
+}
+
+// RemoveChain removes the WAL and the snapshots of the given chain.
+func (c *Consenter) RemoveChain(chainID string) error {
+	for _, dir := range []string{
+		path.Join(c.EtcdRaftConfig.WALDir, chainID),
+		path.Join(c.EtcdRaftConfig.SnapDir, chainID),
+	} {
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.Wrapf(err, "failed to remove %s of channel %s", dir, chainID)
+		}
+	}
+	return nil
+}
+
 // ReadBlockMetadata attempts to read raft metadata from block metadata, if available.
 // otherwise, it reads raft metadata from config metadata supplied.
 func ReadBlockMetadata(blockMetadata *common.Metadata, configMetadata *etcdraft.ConfigMetadata) (*etcdraft.BlockMetadata, error) {
@@ -270,6 +298,11 @@ func New(
 		logger.Panicf("Failed to decode etcdraft configuration: %s", err)
 	}
 
+	encryption, err := NewEncryption(factory.GetDefault(), cfg.EncryptionKey)
+	if err != nil {
+		logger.Panicf("Failed to initialize encryption of the WAL and snapshots: %s", err)
+	}
+
 	consenter := &Consenter{
 		CreateChain:           r.CreateChain,
 		Cert:                  srvConf.SecOpts.Certificate,
@@ -280,6 +313,7 @@ func New(
 		Dialer:                clusterDialer,
 		Metrics:               NewMetrics(metricsProvider),
 		InactiveChainRegistry: icr,
+		Encryption:            encryption,
 	}
 	consenter.Dispatcher = &Dispatcher{
 		Logger:        logger,
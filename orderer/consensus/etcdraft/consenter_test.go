@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	clustermocks "github.com/hyperledger/fabric/orderer/common/cluster/mocks"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
+	"github.com/hyperledger/fabric/orderer/consensus/bft"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft/mocks"
 	consensusmocks "github.com/hyperledger/fabric/orderer/consensus/mocks"
@@ -100,6 +101,7 @@ var _ = Describe("Consenter", func() {
 
 	When("the consenter is asked for a chain", func() {
 		chainInstance := &etcdraft.Chain{}
+		bftChainInstance := &bft.Chain{}
 		cs := &multichannel.ChainSupport{
 			Chain: chainInstance,
 		}
@@ -110,6 +112,9 @@ var _ = Describe("Consenter", func() {
 			chainGetter.On("GetChain", "notraftchain").Return(&multichannel.ChainSupport{
 				Chain: &multichannel.ChainSupport{},
 			})
+			chainGetter.On("GetChain", "bftchain").Return(&multichannel.ChainSupport{
+				Chain: bftChainInstance,
+			})
 		})
 		It("calls the chain getter and returns the reference when it is found", func() {
 			consenter := newConsenter(chainGetter)
@@ -133,6 +138,13 @@ var _ = Describe("Consenter", func() {
 			chain := consenter.ReceiverByChain("notraftchain")
 			Expect(chain).To(BeNil())
 		})
+		It("calls the chain getter and returns the reference of a chain of another cluster type", func() {
+			consenter := newConsenter(chainGetter)
+			Expect(consenter).NotTo(BeNil())
+
+			chain := consenter.ReceiverByChain("bftchain")
+			Expect(chain).To(BeIdenticalTo(bftChainInstance))
+		})
 		It("calls the chain getter and panics when the chain has a bad internal state", func() {
 			consenter := newConsenter(chainGetter)
 			Expect(consenter).NotTo(BeNil())
@@ -214,6 +226,49 @@ var _ = Describe("Consenter", func() {
 		consenter.icr.AssertNumberOfCalls(testingInstance, "TrackChain", 1)
 	})
 
+	It("leaves the chain inactive if no matching cert found and there is no inactive chain registry", func() {
+		m := &etcdraftproto.ConfigMetadata{
+			Consenters: []*etcdraftproto.Consenter{
+				{ServerTlsCert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("foo")})},
+			},
+			Options: &etcdraftproto.Options{
+				TickInterval:      "500ms",
+				ElectionTick:      10,
+				HeartbeatTick:     1,
+				MaxInflightBlocks: 5,
+			},
+		}
+		metadata := utils.MarshalOrPanic(m)
+		support := &consensusmocks.FakeConsenterSupport{}
+		support.SharedConfigReturns(&mockconfig.Orderer{
+			ConsensusMetadataVal: metadata,
+			BatchSizeVal:         &orderer.BatchSize{PreferredMaxBytes: 2 * 1024 * 1024},
+		})
+		support.ChainIDReturns("foo")
+
+		consenter := newConsenter(chainGetter)
+		consenter.InactiveChainRegistry = nil
+
+		chain, err := consenter.HandleChain(support, &common.Metadata{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chain.Order(nil, 0).Error()).To(Equal("channel foo is not serviced by me"))
+		consenter.icr.AssertNotCalled(testingInstance, "TrackChain", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("removes the WAL and the snapshots of a chain", func() {
+		consenter := newConsenter(chainGetter)
+		consenter.EtcdRaftConfig.WALDir = walDir
+		consenter.EtcdRaftConfig.SnapDir = snapDir
+		for _, dir := range []string{path.Join(walDir, "foo"), path.Join(snapDir, "foo"), path.Join(walDir, "bar")} {
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		}
+
+		Expect(consenter.RemoveChain("foo")).To(Succeed())
+		Expect(path.Join(walDir, "foo")).NotTo(BeADirectory())
+		Expect(path.Join(snapDir, "foo")).NotTo(BeADirectory())
+		Expect(path.Join(walDir, "bar")).To(BeADirectory())
+	})
+
 	It("fails to handle chain if etcdraft options have not been provided", func() {
 		m := &etcdraftproto.ConfigMetadata{
 			Consenters: []*etcdraftproto.Consenter{
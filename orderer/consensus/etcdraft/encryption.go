@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+	"go.etcd.io/etcd/raft/raftpb"
+)
+
+// encryptedDataPrefix starts the data of encrypted WAL records and snapshots.
+// Its first byte is not a valid protobuf tag, so it never starts plaintext data.
+var encryptedDataPrefix = []byte{0x00, 'E', 'N', 'C', 0x01}
+
+// Encryption encrypts the data of the WAL records and snapshots of a chain with
+// AES-GCM under an AES key of a BCCSP, and decrypts them with the key they were
+// encrypted with, which is looked up in the BCCSP by its subject key identifier.
+// The key never leaves the BCCSP. The header of the encrypted data is
+// authenticated along with the ciphertext, so data which was modified fails to
+// be decrypted.
+type Encryption struct {
+	csp   bccsp.BCCSP
+	keyID []byte
+	key   bccsp.Key
+
+	mutex sync.Mutex
+	keys  map[string]bccsp.Key
+}
+
+// NewEncryption creates an Encryption which encrypts data with the key of the
+// BCCSP with the given hex encoded subject key identifier, or which does not
+// encrypt data if the identifier is empty.
+func NewEncryption(csp bccsp.BCCSP, keyID string) (*Encryption, error) {
+	e := &Encryption{
+		csp:  csp,
+		keys: map[string]bccsp.Key{},
+	}
+	if keyID == "" {
+		return e, nil
+	}
+
+	ski, err := hex.DecodeString(keyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid encryption key identifier %s", keyID)
+	}
+	key, err := e.getKey(ski)
+	if err != nil {
+		return nil, err
+	}
+	e.keyID, e.key = ski, key
+	return e, nil
+}
+
+// GenerateEncryptionKey generates an AES key which is stored by the BCCSP,
+// and returns its hex encoded subject key identifier.
+func GenerateEncryptionKey(csp bccsp.BCCSP) (string, error) {
+	key, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate encryption key")
+	}
+	return hex.EncodeToString(key.SKI()), nil
+}
+
+func (e *Encryption) getKey(ski []byte) (bccsp.Key, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if key, exists := e.keys[string(ski)]; exists {
+		return key, nil
+	}
+	key, err := e.csp.GetKey(ski)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get encryption key %x", ski)
+	}
+	if !key.Symmetric() {
+		return nil, errors.Errorf("encryption key %x is not a symmetric key", ski)
+	}
+	e.keys[string(ski)] = key
+	return key, nil
+}
+
+// encrypt encrypts data with the current key, if any. The encrypted data consists
+// of the prefix, the length and value of the subject key identifier of the key, and
+// the ciphertext, which authenticates the preceding header.
+func (e *Encryption) encrypt(data []byte) ([]byte, error) {
+	if e == nil || e.key == nil || len(data) == 0 {
+		return data, nil
+	}
+
+	header := make([]byte, 0, len(encryptedDataPrefix)+1+len(e.keyID))
+	header = append(header, encryptedDataPrefix...)
+	header = append(header, byte(len(e.keyID)))
+	header = append(header, e.keyID...)
+	ciphertext, err := e.csp.Encrypt(e.key, data, &bccsp.AESGCMModeOpts{AdditionalData: header})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to encrypt data with key %x", e.keyID)
+	}
+	return append(header, ciphertext...), nil
+}
+
+// decrypt decrypts data, and returns whether the data was encrypted as
+// it would be by encrypt, that is with the current key or not at all.
+func (e *Encryption) decrypt(data []byte) ([]byte, bool, error) {
+	if !bytes.HasPrefix(data, encryptedDataPrefix) {
+		return data, e == nil || e.key == nil || len(data) == 0, nil
+	}
+	if e == nil {
+		return nil, false, errors.New("data is encrypted, but no BCCSP is available to decrypt it")
+	}
+
+	body := data[len(encryptedDataPrefix):]
+	if len(body) == 0 || len(body) < 1+int(body[0]) {
+		return nil, false, errors.New("encrypted data is truncated")
+	}
+	headerLen := len(encryptedDataPrefix) + 1 + int(body[0])
+	ski, ciphertext := body[1:1+int(body[0])], data[headerLen:]
+
+	key, err := e.getKey(ski)
+	if err != nil {
+		return nil, false, err
+	}
+	plaintext, err := e.csp.Decrypt(key, ciphertext, &bccsp.AESGCMModeOpts{AdditionalData: data[:headerLen]})
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to decrypt data with key %x", ski)
+	}
+	return plaintext, bytes.Equal(ski, e.keyID), nil
+}
+
+// encryptEntries returns a copy of the entries with their data encrypted.
+func (e *Encryption) encryptEntries(entries []raftpb.Entry) ([]raftpb.Entry, error) {
+	if e == nil || e.key == nil {
+		return entries, nil
+	}
+
+	encrypted := make([]raftpb.Entry, len(entries))
+	for i, entry := range entries {
+		data, err := e.encrypt(entry.Data)
+		if err != nil {
+			return nil, err
+		}
+		encrypted[i] = entry
+		encrypted[i].Data = data
+	}
+	return encrypted, nil
+}
+
+// decryptEntries decrypts the data of the entries in place, and returns
+// whether they were all encrypted as they would be by encryptEntries.
+func (e *Encryption) decryptEntries(entries []raftpb.Entry) (bool, error) {
+	current := true
+	for i := range entries {
+		data, ok, err := e.decrypt(entries[i].Data)
+		if err != nil {
+			return false, errors.WithMessage(err, "failed to decrypt WAL entry")
+		}
+		entries[i].Data = data
+		current = current && ok
+	}
+	return current, nil
+}
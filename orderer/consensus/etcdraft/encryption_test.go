@@ -0,0 +1,236 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft"
+	"go.etcd.io/etcd/raft/raftpb"
+	"go.uber.org/zap"
+)
+
+func newTestCSP(t *testing.T, keystore string) bccsp.BCCSP {
+	csp, err := sw.NewDefaultSecurityLevel(keystore)
+	require.NoError(t, err)
+	return csp
+}
+
+func newTestEncryption(t *testing.T, csp bccsp.BCCSP, keyID string) *Encryption {
+	enc, err := NewEncryption(csp, keyID)
+	require.NoError(t, err)
+	return enc
+}
+
+// dirContains returns whether any file in the directory contains the data.
+func dirContains(t *testing.T, dir string, data []byte) bool {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, info := range infos {
+		content, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		require.NoError(t, err)
+		if bytes.Contains(content, data) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEncryption(t *testing.T) {
+	keystore, err := ioutil.TempDir("", "etcdraft-keystore-")
+	require.NoError(t, err)
+	defer os.RemoveAll(keystore)
+	csp := newTestCSP(t, keystore)
+
+	key1, err := GenerateEncryptionKey(csp)
+	require.NoError(t, err)
+	key2, err := GenerateEncryptionKey(csp)
+	require.NoError(t, err)
+	assert.NotEqual(t, key1, key2)
+
+	enc1 := newTestEncryption(t, csp, key1)
+	enc2 := newTestEncryption(t, csp, key2)
+	plain := newTestEncryption(t, csp, "")
+
+	encrypted, err := enc1.encrypt([]byte("data"))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(encrypted, encryptedDataPrefix))
+	assert.False(t, bytes.Contains(encrypted, []byte("data")))
+
+	for _, tc := range []struct {
+		name    string
+		enc     *Encryption
+		data    []byte
+		current bool
+	}{
+		{name: "same key", enc: enc1, data: encrypted, current: true},
+		{name: "other key", enc: enc2, data: encrypted, current: false},
+		{name: "no key", enc: plain, data: encrypted, current: false},
+		{name: "plaintext with key", enc: enc1, data: []byte("data"), current: false},
+		{name: "plaintext without key", enc: plain, data: []byte("data"), current: true},
+		{name: "nil encryption", enc: nil, data: []byte("data"), current: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, current, err := tc.enc.decrypt(tc.data)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("data"), data)
+			assert.Equal(t, tc.current, current)
+		})
+	}
+
+	_, _, err = (*Encryption)(nil).decrypt(encrypted)
+	assert.EqualError(t, err, "data is encrypted, but no BCCSP is available to decrypt it")
+	_, _, err = enc1.decrypt(encrypted[:len(encryptedDataPrefix)+2])
+	assert.EqualError(t, err, "encrypted data is truncated")
+	_, _, err = enc1.decrypt(encrypted[:len(encryptedDataPrefix)+1+len(skiOf(t, key1))+4])
+	assert.Contains(t, err.Error(), "failed to decrypt data with key "+key1)
+	assert.Contains(t, err.Error(), "Invalid ciphertext. It must be longer than the nonce")
+
+	// the ciphertext and the header are authenticated
+	for _, i := range []int{len(encrypted) - 1, len(encrypted) - 20, len(encryptedDataPrefix) + 1 + len(skiOf(t, key1))} {
+		tampered := append([]byte(nil), encrypted...)
+		tampered[i] ^= 0x01
+		_, _, err = enc1.decrypt(tampered)
+		assert.Contains(t, err.Error(), "failed to decrypt data with key "+key1)
+		assert.Contains(t, err.Error(), "cipher: message authentication failed")
+	}
+	encrypted2, err := enc1.encrypt([]byte("data"))
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, encrypted2)
+
+	entries := []raftpb.Entry{{Index: 1, Data: []byte("entry")}, {Index: 2}}
+	encryptedEntries, err := enc2.encryptEntries(entries)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("entry"), entries[0].Data)
+	assert.NotEqual(t, []byte("entry"), encryptedEntries[0].Data)
+	assert.Nil(t, encryptedEntries[1].Data)
+
+	current, err := enc1.decryptEntries(encryptedEntries)
+	require.NoError(t, err)
+	assert.False(t, current)
+	assert.Equal(t, entries, encryptedEntries)
+
+	_, err = NewEncryption(csp, "not hex")
+	assert.Contains(t, err.Error(), "invalid encryption key identifier not hex")
+	_, err = NewEncryption(csp, hex.EncodeToString([]byte("unknown")))
+	assert.Contains(t, err.Error(), "failed to get encryption key 756e6b6e6f776e")
+}
+
+func TestStorageEncryption(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "etcdraft-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+	keystore := path.Join(dataDir, "keystore")
+	walDir, snapDir := path.Join(dataDir, "wal"), path.Join(dataDir, "snapshot")
+	lg := flogging.NewFabricLogger(zap.NewExample())
+
+	csp := newTestCSP(t, keystore)
+	key1, err := GenerateEncryptionKey(csp)
+	require.NoError(t, err)
+	key2, err := GenerateEncryptionKey(csp)
+	require.NoError(t, err)
+
+	// reopen closes the storage and opens it again, and asserts that
+	// it contains the entries with the given data after the snapshot.
+	reopen := func(store *RaftStorage, enc *Encryption, data ...string) *RaftStorage {
+		require.NoError(t, store.Close())
+		ram := raft.NewMemoryStorage()
+		store, err := CreateStorage(lg, walDir, snapDir, ram, enc)
+		require.NoError(t, err)
+
+		sn, err := ram.Snapshot()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("snapshot"), sn.Data)
+		lasti, err := ram.LastIndex()
+		require.NoError(t, err)
+		entries, err := ram.Entries(sn.Metadata.Index+1, lasti+1, 1<<20)
+		require.NoError(t, err)
+		var actual []string
+		for _, entry := range entries {
+			actual = append(actual, string(entry.Data))
+		}
+		assert.Equal(t, data, actual)
+		return store
+	}
+
+	store, err := CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), nil)
+	require.NoError(t, err)
+	assert.True(t, store.fresh)
+	var entries []raftpb.Entry
+	for i, data := range []string{"plain1", "plain2", "plain3"} {
+		entries = append(entries, raftpb.Entry{Index: uint64(i + 1), Term: 1, Data: []byte(data)})
+	}
+	require.NoError(t, store.Store(entries, raftpb.HardState{Term: 1, Commit: 3}, raftpb.Snapshot{}))
+	require.NoError(t, store.TakeSnapshot(1, raftpb.ConfState{Nodes: []uint64{1}}, []byte("snapshot")))
+	store = reopen(store, nil, "plain2", "plain3")
+	assert.False(t, store.fresh)
+
+	t.Run("migrating to an encrypted WAL", func(t *testing.T) {
+		store = reopen(store, newTestEncryption(t, csp, key1), "plain2", "plain3")
+		assert.False(t, dirContains(t, walDir, []byte("plain")))
+		assert.False(t, dirContains(t, snapDir, []byte("snapshot")))
+		assert.True(t, dirContains(t, walDir, skiOf(t, key1)))
+
+		entry := raftpb.Entry{Index: 4, Term: 1, Data: []byte("secret4")}
+		require.NoError(t, store.Store([]raftpb.Entry{entry}, raftpb.HardState{Term: 1, Commit: 4}, raftpb.Snapshot{}))
+		assert.False(t, dirContains(t, walDir, []byte("secret")))
+		store = reopen(store, newTestEncryption(t, csp, key1), "plain2", "plain3", "secret4")
+	})
+
+	t.Run("rotating the key", func(t *testing.T) {
+		store = reopen(store, newTestEncryption(t, csp, key2), "plain2", "plain3", "secret4")
+		assert.False(t, dirContains(t, walDir, skiOf(t, key1)))
+		assert.False(t, dirContains(t, snapDir, skiOf(t, key1)))
+
+		// The replaced key is no longer needed.
+		require.NoError(t, os.Remove(filepath.Join(keystore, key1+"_key")))
+		csp := newTestCSP(t, keystore)
+		store = reopen(store, newTestEncryption(t, csp, key2), "plain2", "plain3", "secret4")
+		_, err := NewEncryption(csp, key1)
+		assert.Error(t, err)
+	})
+
+	t.Run("recovering an interrupted rewrite", func(t *testing.T) {
+		// the node stopped after the WAL was moved aside, but before the rewritten WAL replaced it
+		require.NoError(t, store.Close())
+		require.NoError(t, os.Rename(walDir, walDir+".old"))
+		require.NoError(t, os.MkdirAll(walDir+".rewrite", 0700))
+		store, err = CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), newTestEncryption(t, csp, key2))
+		require.NoError(t, err)
+		assert.False(t, store.fresh, "the restored WAL must not be taken for a fresh node")
+		store = reopen(store, newTestEncryption(t, csp, key2), "plain2", "plain3", "secret4")
+		_, err = os.Stat(walDir + ".old")
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(walDir + ".rewrite")
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("disabling encryption", func(t *testing.T) {
+		store = reopen(store, newTestEncryption(t, newTestCSP(t, keystore), ""), "plain2", "plain3", "secret4")
+		assert.True(t, dirContains(t, walDir, []byte("secret4")))
+		store = reopen(store, nil, "plain2", "plain3", "secret4")
+	})
+
+	require.NoError(t, store.Close())
+}
+
+func skiOf(t *testing.T, keyID string) []byte {
+	ski, err := hex.DecodeString(keyID)
+	require.NoError(t, err)
+	return ski
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 	"go.etcd.io/etcd/raft"
 	"go.etcd.io/etcd/raft/raftpb"
 )
@@ -210,20 +211,7 @@ func (n *node) abdicateLeader(currentLead uint64) {
 
 	// Leader initiates leader transfer
 	if status.RaftState == raft.StateLeader {
-		var transferee uint64
-		for id, pr := range status.Progress {
-			if id == status.ID {
-				continue // skip self
-			}
-
-			if pr.RecentActive && !pr.Paused {
-				transferee = id
-				break
-			}
-
-			n.logger.Debugf("Node %d is not qualified as transferee because it's either paused or not active", id)
-		}
-
+		transferee := n.transferee(status)
 		if transferee == raft.None {
 			n.logger.Errorf("No follower is qualified as transferee, abort leader transfer")
 			return
@@ -233,20 +221,79 @@ func (n *node) abdicateLeader(currentLead uint64) {
 		n.TransferLeadership(ctx, status.ID, transferee)
 	}
 
-	// Periodically check leader has changed till ElectionTimeout elapsed
+	newLeader, err := n.waitForLeaderChange(ctx, status.Lead)
+	if err != nil {
+		n.logger.Warnf("Leader transfer aborted: %s", err)
+		return
+	}
+
+	n.logger.Infof("Leader has been transferred from %d to %d", currentLead, newLeader)
+}
+
+// transferLeadership transfers the leadership to the most up-to-date follower
+// if this node is the leader, and waits for the new leader to be elected.
+// It returns the current leader right away if this node is not the leader.
+func (n *node) transferLeadership(ctx context.Context) (uint64, error) {
+	status := n.Status()
+	if status.RaftState != raft.StateLeader {
+		return status.Lead, nil
+	}
+
+	transferee := n.transferee(status)
+	if transferee == raft.None {
+		return raft.None, errors.New("no follower is qualified as transferee")
+	}
+
+	n.logger.Infof("Transferring leadership to %d", transferee)
+	n.TransferLeadership(ctx, status.ID, transferee)
+
+	newLeader, err := n.waitForLeaderChange(ctx, status.ID)
+	if err != nil {
+		return raft.None, err
+	}
+
+	n.logger.Infof("Leader has been transferred from %d to %d", status.ID, newLeader)
+	return newLeader, nil
+}
+
+// transferee picks the follower that has replicated the most entries,
+// among the followers that are recently active and not paused, so that
+// it catches up with the leader as soon as possible.
+func (n *node) transferee(status raft.Status) uint64 {
+	var transferee, match uint64
+	for id, pr := range status.Progress {
+		if id == status.ID {
+			continue // skip self
+		}
+
+		if !pr.RecentActive || pr.Paused {
+			n.logger.Debugf("Node %d is not qualified as transferee because it's either paused or not active", id)
+			continue
+		}
+
+		if transferee == raft.None || pr.Match > match || (pr.Match == match && id < transferee) {
+			transferee, match = id, pr.Match
+		}
+	}
+
+	return transferee
+}
+
+// waitForLeaderChange periodically checks the leader till it is different
+// from the given one, or ctx is done.
+func (n *node) waitForLeaderChange(ctx context.Context, lead uint64) (uint64, error) {
 	var newLeader uint64
-	for newLeader = n.Status().Lead; newLeader == status.Lead || newLeader == raft.None; newLeader = n.Status().Lead {
+	for newLeader = n.Status().Lead; newLeader == lead || newLeader == raft.None; newLeader = n.Status().Lead {
 		select {
 		case <-ctx.Done():
-			n.logger.Warn("Leader transfer timeout")
-			return
+			return raft.None, errors.New("leader transfer timeout")
 		case <-time.After(n.tickInterval):
 		case <-n.chain.doneC:
-			return
+			return raft.None, errors.New("chain is stopped")
 		}
 	}
 
-	n.logger.Infof("Leader has been transferred from %d to %d", currentLead, newLeader)
+	return newLeader, nil
 }
 
 func (n *node) logSendFailure(dest uint64, err error) {
@@ -56,6 +56,11 @@ type RaftStorage struct {
 	ram  MemoryStorage
 	wal  *wal.WAL
 	snap *snap.Snapshotter
+	enc  *Encryption
+
+	// fresh indicates that no WAL data was found, once an interrupted
+	// rewrite of the WAL was recovered, when the storage was created
+	fresh bool
 
 	// a queue that keeps track of indices of snapshots on disk
 	snapshotIndex []uint64
@@ -63,11 +68,16 @@ type RaftStorage struct {
 
 // CreateStorage attempts to create a storage to persist etcd/raft data.
 // If data presents in specified disk, they are loaded to reconstruct storage state.
+// The data of WAL records and snapshots is encrypted with the current key of enc,
+// if any, and the WAL and snapshots on disk are rewritten if they are not encrypted
+// with that key, so that enabling encryption or rotating the key takes effect on
+// the existing data as well.
 func CreateStorage(
 	lg *flogging.FabricLogger,
 	walDir string,
 	snapDir string,
 	ram MemoryStorage,
+	enc *Encryption,
 ) (*RaftStorage, error) {
 
 	sn, err := createSnapshotter(lg, snapDir)
@@ -75,6 +85,11 @@ func CreateStorage(
 		return nil, err
 	}
 
+	if err := recoverWAL(lg, walDir); err != nil {
+		return nil, err
+	}
+	fresh := !wal.Exist(walDir)
+
 	snapshot, err := sn.Load()
 	if err != nil {
 		if err == snap.ErrNoSnapshot {
@@ -86,6 +101,10 @@ func CreateStorage(
 		// snapshot found
 		lg.Debugf("Loaded snapshot at Term %d and Index %d, Nodes: %+v",
 			snapshot.Metadata.Term, snapshot.Metadata.Index, snapshot.Metadata.ConfState.Nodes)
+
+		if snapshot.Data, _, err = enc.decrypt(snapshot.Data); err != nil {
+			return nil, errors.Errorf("failed to decrypt snapshot: %s", err)
+		}
 	}
 
 	w, st, ents, err := createOrReadWAL(lg, walDir, snapshot)
@@ -93,6 +112,30 @@ func CreateStorage(
 		return nil, errors.Errorf("failed to create or read WAL: %s", err)
 	}
 
+	current, err := enc.decryptEntries(ents)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	if !current {
+		lg.Infof("Rewriting WAL at path '%s' with the current encryption key", walDir)
+		if err := w.Close(); err != nil {
+			return nil, errors.Errorf("failed to close WAL before rewriting it: %s", err)
+		}
+		if err := rewriteWAL(lg, walDir, snapshot, st, ents, enc); err != nil {
+			return nil, err
+		}
+		if w, _, _, err = createOrReadWAL(lg, walDir, snapshot); err != nil {
+			return nil, errors.Errorf("failed to read rewritten WAL: %s", err)
+		}
+	}
+
+	if err := rewriteSnapshots(lg, snapDir, enc); err != nil {
+		w.Close()
+		return nil, err
+	}
+
 	if snapshot != nil {
 		lg.Debugf("Applying snapshot to raft MemoryStorage")
 		if err := ram.ApplySnapshot(*snapshot); err != nil {
@@ -111,12 +154,145 @@ func CreateStorage(
 		ram:           ram,
 		wal:           w,
 		snap:          sn,
+		enc:           enc,
+		fresh:         fresh,
 		walDir:        walDir,
 		snapDir:       snapDir,
 		snapshotIndex: ListSnapshots(lg, snapDir),
 	}, nil
 }
 
+// recoverWAL restores the WAL directory if the node
+// stopped while the WAL was being rewritten.
+func recoverWAL(lg *flogging.FabricLogger, walDir string) error {
+	rewriteDir, oldDir := walDir+".rewrite", walDir+".old"
+
+	if !wal.Exist(walDir) && wal.Exist(oldDir) {
+		lg.Warnf("Restoring WAL at path '%s' whose rewrite was interrupted", walDir)
+		if err := os.Rename(oldDir, walDir); err != nil {
+			return errors.Errorf("failed to restore WAL: %s", err)
+		}
+		if err := syncDir(filepath.Dir(walDir)); err != nil {
+			return errors.Errorf("failed to restore WAL: %s", err)
+		}
+	}
+
+	for _, dir := range []string{rewriteDir, oldDir} {
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.Errorf("failed to remove '%s': %s", dir, err)
+		}
+	}
+	return nil
+}
+
+// rewriteWAL replaces the WAL with a WAL which contains the given
+// snapshot, hard state and entries, encrypted with the current key.
+func rewriteWAL(lg *flogging.FabricLogger, walDir string, snapshot *raftpb.Snapshot, st raftpb.HardState, ents []raftpb.Entry, enc *Encryption) error {
+	rewriteDir, oldDir := walDir+".rewrite", walDir+".old"
+
+	w, err := wal.Create(lg.Zap(), rewriteDir, nil)
+	if err != nil {
+		return errors.Errorf("failed to create WAL: %s", err)
+	}
+
+	encrypted, err := enc.encryptEntries(ents)
+	if err != nil {
+		w.Close()
+		return err
+	}
+
+	if snapshot != nil {
+		walsnap := walpb.Snapshot{Index: snapshot.Metadata.Index, Term: snapshot.Metadata.Term}
+		if err := w.SaveSnapshot(walsnap); err != nil {
+			w.Close()
+			return errors.Errorf("failed to save snapshot to WAL: %s", err)
+		}
+	}
+
+	if err := w.Save(st, encrypted); err != nil {
+		w.Close()
+		return errors.Errorf("failed to save entries to WAL: %s", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return errors.Errorf("failed to close WAL: %s", err)
+	}
+
+	if err := os.Rename(walDir, oldDir); err != nil {
+		return errors.Errorf("failed to replace WAL: %s", err)
+	}
+	if err := os.Rename(rewriteDir, walDir); err != nil {
+		return errors.Errorf("failed to replace WAL: %s", err)
+	}
+	// the renames must be durable before the previous WAL is removed
+	if err := syncDir(filepath.Dir(walDir)); err != nil {
+		return errors.Errorf("failed to replace WAL: %s", err)
+	}
+	return os.RemoveAll(oldDir)
+}
+
+// syncDir flushes the entries of a directory, such as the
+// files and directories renamed into it, to the disk.
+func syncDir(dir string) error {
+	d, err := fileutil.OpenDir(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return fileutil.Fsync(d)
+}
+
+// rewriteSnapshots rewrites the snapshots on disk
+// which are not encrypted with the current key.
+func rewriteSnapshots(lg *flogging.FabricLogger, snapDir string, enc *Encryption) error {
+	snapFiles, err := fileutil.ReadDir(snapDir)
+	if err != nil {
+		return errors.Errorf("failed to read snapshot directory %s: %s", snapDir, err)
+	}
+
+	rewriteDir := snapDir + ".rewrite"
+	defer os.RemoveAll(rewriteDir)
+
+	for _, f := range snapFiles {
+		if !strings.HasSuffix(f, ".snap") {
+			continue
+		}
+
+		fpath := filepath.Join(snapDir, f)
+		s, err := snap.Read(lg.Zap(), fpath)
+		if err != nil {
+			// Corrupted snapshots are renamed by ListSnapshots
+			continue
+		}
+
+		data, current, err := enc.decrypt(s.Data)
+		if err != nil {
+			return errors.Errorf("failed to decrypt snapshot %s: %s", fpath, err)
+		}
+		if current {
+			continue
+		}
+
+		lg.Infof("Rewriting snapshot %s with the current encryption key", fpath)
+		if s.Data, err = enc.encrypt(data); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(rewriteDir, os.ModePerm); err != nil {
+			return errors.Errorf("failed to mkdir '%s' for snapshot: %s", rewriteDir, err)
+		}
+		if err := snap.New(lg.Zap(), rewriteDir).SaveSnap(*s); err != nil {
+			return errors.Errorf("failed to rewrite snapshot %s: %s", fpath, err)
+		}
+		if err := os.Rename(filepath.Join(rewriteDir, f), fpath); err != nil {
+			return errors.Errorf("failed to replace snapshot %s: %s", fpath, err)
+		}
+		if err := syncDir(snapDir); err != nil {
+			return errors.Errorf("failed to replace snapshot %s: %s", fpath, err)
+		}
+	}
+	return nil
+}
+
 // ListSnapshots returns a list of RaftIndex of snapshots stored on disk.
 // If a file is corrupted, rename the file.
 func ListSnapshots(logger *flogging.FabricLogger, snapDir string) []uint64 {
@@ -242,7 +418,12 @@ func (rs *RaftStorage) Snapshot() raftpb.Snapshot {
 
 // Store persists etcd/raft data
 func (rs *RaftStorage) Store(entries []raftpb.Entry, hardstate raftpb.HardState, snapshot raftpb.Snapshot) error {
-	if err := rs.wal.Save(hardstate, entries); err != nil {
+	encrypted, err := rs.enc.encryptEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.wal.Save(hardstate, encrypted); err != nil {
 		return err
 	}
 
@@ -283,7 +464,13 @@ func (rs *RaftStorage) saveSnap(snap raftpb.Snapshot) error {
 		return errors.Errorf("failed to save snapshot to WAL: %s", err)
 	}
 
-	if err := rs.snap.SaveSnap(snap); err != nil {
+	data, err := rs.enc.encrypt(snap.Data)
+	if err != nil {
+		return err
+	}
+	encrypted := snap
+	encrypted.Data = data
+	if err := rs.snap.SaveSnap(encrypted); err != nil {
 		return errors.Errorf("failed to save snapshot to disk: %s", err)
 	}
 
@@ -41,7 +41,7 @@ func setup(t *testing.T) {
 	dataDir, err = ioutil.TempDir("", "etcdraft-")
 	assert.NoError(t, err)
 	walDir, snapDir = path.Join(dataDir, "wal"), path.Join(dataDir, "snapshot")
-	store, err = CreateStorage(logger, walDir, snapDir, ram)
+	store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 	assert.NoError(t, err)
 }
 
@@ -108,7 +108,7 @@ func TestOpenWAL(t *testing.T) {
 
 		// create new storage
 		ram = raft.NewMemoryStorage()
-		store, err = CreateStorage(logger, walDir, snapDir, ram)
+		store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 		require.NoError(t, err)
 		lastI, _ := store.ram.LastIndex()
 		assert.True(t, lastI > 0)     // we are still able to read some entries
@@ -177,7 +177,7 @@ func TestTakeSnapshot(t *testing.T) {
 			err = store.Close()
 			assert.NoError(t, err)
 			ram := raft.NewMemoryStorage()
-			store, err = CreateStorage(logger, walDir, snapDir, ram)
+			store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 			assert.NoError(t, err)
 
 			store.TakeSnapshot(uint64(7), raftpb.ConfState{Nodes: []uint64{1}}, make([]byte, 10))
@@ -233,7 +233,7 @@ func TestTakeSnapshot(t *testing.T) {
 			err = store.Close()
 			assert.NoError(t, err)
 			ram := raft.NewMemoryStorage()
-			store, err = CreateStorage(logger, walDir, snapDir, ram)
+			store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 			assert.NoError(t, err)
 
 			// Two snapshots at index 5, 7. And we keep one extra wal file prior to oldest snapshot.
@@ -309,7 +309,7 @@ func TestTakeSnapshot(t *testing.T) {
 			err = store.Close()
 			assert.NoError(t, err)
 			ram := raft.NewMemoryStorage()
-			store, err = CreateStorage(logger, walDir, snapDir, ram)
+			store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 			assert.NoError(t, err)
 
 			// Corrupted snapshot file should've been renamed
@@ -327,3 +327,12 @@ func TestTakeSnapshot(t *testing.T) {
 		})
 	})
 }
+
+func TestSyncDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcdraft-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, syncDir(dir))
+	assert.Error(t, syncDir(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/spf13/cobra"
+)
+
+var chaincodeApproveForMyOrgCmd *cobra.Command
+
+const approveForMyOrgCmdName = "approveformyorg"
+
+// approveForMyOrgCmd returns the cobra command for Chaincode ApproveForMyOrg
+func approveForMyOrgCmd(cf *ChaincodeCmdFactory) *cobra.Command {
+	chaincodeApproveForMyOrgCmd = &cobra.Command{
+		Use:   approveForMyOrgCmdName,
+		Short: fmt.Sprintf("Approve the definition of a %s for my organization.", chainFuncName),
+		Long: fmt.Sprintf("Approve the definition of a %s for the organization of the peer. The approval is recorded "+
+			"in the private state of the organization on the channel once the transaction is committed.", chainFuncName),
+		ValidArgs: []string{"1"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return chaincodeApproveForMyOrg(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"name",
+		"channelID",
+		"version",
+		"sequence",
+		"policy",
+		"escc",
+		"vscc",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+		"collections-config",
+		"waitForEvent",
+		"waitForEventTimeout",
+	}
+	attachFlags(chaincodeApproveForMyOrgCmd, flagList)
+
+	return chaincodeApproveForMyOrgCmd
+}
+
+func chaincodeApproveForMyOrg(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	if channelID == "" {
+		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	cd, err := getChaincodeDefinition(cmd)
+	if err != nil {
+		return err
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, true)
+		if err != nil {
+			return err
+		}
+	}
+	defer cf.BroadcastClient.Close()
+
+	args := &lb.ApproveChaincodeDefinitionForMyOrgArgs{
+		Name:       chaincodeName,
+		Definition: cd,
+	}
+
+	return invokeLifecycle(lifecycle.ApproveChaincodeDefinitionForMyOrgFuncName, args, cf)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// capturingEndorserClient records the signed proposals it endorses
+type capturingEndorserClient struct {
+	response  *pb.ProposalResponse
+	proposals []*pb.SignedProposal
+}
+
+func (c *capturingEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	c.proposals = append(c.proposals, in)
+	return c.response, nil
+}
+
+// lifecycleInvocation returns the function name and the
+// arguments of the invocation of the lifecycle system chaincode
+func lifecycleInvocation(t *testing.T, signedProp *pb.SignedProposal) (string, []byte) {
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	require.NoError(t, err)
+	cis, err := utils.GetChaincodeInvocationSpec(prop)
+	require.NoError(t, err)
+	assert.Equal(t, "+lifecycle", cis.ChaincodeSpec.ChaincodeId.Name)
+	require.Len(t, cis.ChaincodeSpec.Input.Args, 2)
+	return string(cis.ChaincodeSpec.Input.Args[0]), cis.ChaincodeSpec.Input.Args[1]
+}
+
+func TestApproveForMyOrgCmd(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactory()
+	assert.NoError(t, err, "Error getting mock chaincode command factory")
+
+	var tests = []struct {
+		name          string
+		args          []string
+		errorExpected bool
+		errMsg        string
+	}{
+		{
+			name:          "successful",
+			args:          []string{"-n", "example02", "-v", "anotherversion", "--sequence", "1", "-C", "mychannel"},
+			errorExpected: false,
+			errMsg:        "Run chaincode approveformyorg cmd error",
+		},
+		{
+			name:          "successful with policy",
+			args:          []string{"-P", "OR('Org1MSP.member', 'Org2MSP.member')", "-n", "example02", "-v", "anotherversion", "--sequence", "1", "-C", "mychannel"},
+			errorExpected: false,
+			errMsg:        "Run chaincode approveformyorg cmd error",
+		},
+		{
+			name:          "no option",
+			args:          []string{},
+			errorExpected: true,
+			errMsg:        "Expected error executing approveformyorg command without required options",
+		},
+		{
+			name:          "missing version",
+			args:          []string{"-n", "example02", "--sequence", "1", "-C", "mychannel"},
+			errorExpected: true,
+			errMsg:        "Expected error executing approveformyorg command without the -v option",
+		},
+		{
+			name:          "missing sequence",
+			args:          []string{"-n", "example02", "-v", "anotherversion", "-C", "mychannel"},
+			errorExpected: true,
+			errMsg:        "Expected error executing approveformyorg command without the --sequence option",
+		},
+		{
+			name:          "missing channelID",
+			args:          []string{"-n", "example02", "-v", "anotherversion", "--sequence", "1"},
+			errorExpected: true,
+			errMsg:        "Expected error executing approveformyorg command without the -C option",
+		},
+		{
+			name:          "invalid policy",
+			args:          []string{"-P", "notapolicy", "-n", "example02", "-v", "anotherversion", "--sequence", "1", "-C", "mychannel"},
+			errorExpected: true,
+			errMsg:        "Expected error executing approveformyorg command with an invalid policy",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetFlags()
+			cmd := approveForMyOrgCmd(mockCF)
+			addFlags(cmd)
+			cmd.SetArgs(test.args)
+			err = cmd.Execute()
+			checkError(t, err, test.errorExpected, test.errMsg)
+		})
+	}
+}
+
+func TestApproveForMyOrgCmdProposal(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactory()
+	require.NoError(t, err)
+	endorserClient := &capturingEndorserClient{
+		response: &pb.ProposalResponse{
+			Response:    &pb.Response{Status: 200},
+			Endorsement: &pb.Endorsement{},
+		},
+	}
+	mockCF.EndorserClients = []pb.EndorserClient{endorserClient}
+
+	resetFlags()
+	cmd := approveForMyOrgCmd(mockCF)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-v", "1.0", "--sequence", "2", "-E", "myescc", "-V", "myvscc", "-C", "mychannel"})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	require.Len(t, endorserClient.proposals, 1)
+	funcName, argsBytes := lifecycleInvocation(t, endorserClient.proposals[0])
+	assert.Equal(t, "ApproveChaincodeDefinitionForMyOrg", funcName)
+
+	args := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+	err = proto.Unmarshal(argsBytes, args)
+	require.NoError(t, err)
+	assert.Equal(t, "example02", args.Name)
+	assert.Equal(t, int64(2), args.Definition.Sequence)
+	assert.Equal(t, "1.0", args.Definition.Version)
+	assert.Equal(t, "myescc", args.Definition.EndorsementPlugin)
+	assert.Equal(t, "myvscc", args.Definition.ValidationPlugin)
+	assert.Nil(t, args.Definition.Collections)
+}
+
+func TestApproveForMyOrgCmdEndorsementFailure(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactoryEndorsementFailure(500, []byte("access denied"))
+	require.NoError(t, err)
+
+	resetFlags()
+	cmd := approveForMyOrgCmd(mockCF)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-v", "1.0", "--sequence", "1", "-C", "mychannel"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ApproveChaincodeDefinitionForMyOrg failed")
+}
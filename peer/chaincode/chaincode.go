@@ -23,7 +23,7 @@ import (
 
 const (
 	chainFuncName = "chaincode"
-	chainCmdDes   = "Operate a chaincode: install|instantiate|invoke|package|query|signpackage|upgrade|list."
+	chainCmdDes   = "Operate a chaincode: install|instantiate|invoke|package|query|signpackage|upgrade|list|approveformyorg|commit|uninstall."
 )
 
 var logger = flogging.MustGetLogger("chaincodeCmd")
@@ -57,6 +57,9 @@ func Cmd(cf *ChaincodeCmdFactory) *cobra.Command {
 	chaincodeCmd.AddCommand(signpackageCmd(cf))
 	chaincodeCmd.AddCommand(upgradeCmd(cf))
 	chaincodeCmd.AddCommand(listCmd(cf))
+	chaincodeCmd.AddCommand(approveForMyOrgCmd(cf))
+	chaincodeCmd.AddCommand(commitCmd(cf))
+	chaincodeCmd.AddCommand(uninstallCmd(cf))
 
 	return chaincodeCmd
 }
@@ -72,6 +75,7 @@ var (
 	chaincodeQueryHex     bool
 	channelID             string
 	chaincodeVersion      string
+	chaincodeSequence     int64
 	policy                string
 	escc                  string
 	vscc                  string
@@ -116,6 +120,8 @@ func resetFlags() {
 		fmt.Sprint("Name of the chaincode"))
 	flags.StringVarP(&chaincodeVersion, "version", "v", common.UndefinedParamValue,
 		fmt.Sprint("Version of the chaincode specified in install/instantiate/upgrade commands"))
+	flags.Int64VarP(&chaincodeSequence, "sequence", "", 0,
+		fmt.Sprint("The sequence number of the chaincode definition for the channel, specified in approveformyorg/commit commands"))
 	flags.StringVarP(&chaincodeUsr, "username", "u", common.UndefinedParamValue,
 		fmt.Sprint("Username for chaincode operations when security is enabled"))
 	flags.StringVarP(&channelID, "channelID", "C", "",
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/spf13/cobra"
+)
+
+var chaincodeCommitCmd *cobra.Command
+
+const commitCmdName = "commit"
+
+// commitCmd returns the cobra command for Chaincode Commit
+func commitCmd(cf *ChaincodeCmdFactory) *cobra.Command {
+	chaincodeCommitCmd = &cobra.Command{
+		Use:   commitCmdName,
+		Short: fmt.Sprintf("Commit the definition of a %s on the channel.", chainFuncName),
+		Long: fmt.Sprintf("Commit the definition of a %s on the channel. The definition is recorded once the transaction "+
+			"is committed, if the endorsing organizations which approved it satisfy the lifecycle endorsement policy of the channel.", chainFuncName),
+		ValidArgs: []string{"1"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return chaincodeCommit(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"name",
+		"channelID",
+		"version",
+		"sequence",
+		"policy",
+		"escc",
+		"vscc",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+		"collections-config",
+		"waitForEvent",
+		"waitForEventTimeout",
+	}
+	attachFlags(chaincodeCommitCmd, flagList)
+
+	return chaincodeCommitCmd
+}
+
+func chaincodeCommit(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	if channelID == "" {
+		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	cd, err := getChaincodeDefinition(cmd)
+	if err != nil {
+		return err
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, true)
+		if err != nil {
+			return err
+		}
+	}
+	defer cf.BroadcastClient.Close()
+
+	args := &lb.CommitChaincodeDefinitionArgs{
+		Name:       chaincodeName,
+		Definition: cd,
+	}
+
+	return invokeLifecycle(lifecycle.CommitChaincodeDefinitionFuncName, args, cf)
+}
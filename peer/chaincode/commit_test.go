@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitCmd(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactory()
+	assert.NoError(t, err, "Error getting mock chaincode command factory")
+
+	var tests = []struct {
+		name          string
+		args          []string
+		errorExpected bool
+		errMsg        string
+	}{
+		{
+			name:          "successful",
+			args:          []string{"-n", "example02", "-v", "anotherversion", "--sequence", "1", "-C", "mychannel"},
+			errorExpected: false,
+			errMsg:        "Run chaincode commit cmd error",
+		},
+		{
+			name:          "no option",
+			args:          []string{},
+			errorExpected: true,
+			errMsg:        "Expected error executing commit command without required options",
+		},
+		{
+			name:          "missing version",
+			args:          []string{"-n", "example02", "--sequence", "1", "-C", "mychannel"},
+			errorExpected: true,
+			errMsg:        "Expected error executing commit command without the -v option",
+		},
+		{
+			name:          "missing sequence",
+			args:          []string{"-n", "example02", "-v", "anotherversion", "-C", "mychannel"},
+			errorExpected: true,
+			errMsg:        "Expected error executing commit command without the --sequence option",
+		},
+		{
+			name:          "missing channelID",
+			args:          []string{"-n", "example02", "-v", "anotherversion", "--sequence", "1"},
+			errorExpected: true,
+			errMsg:        "Expected error executing commit command without the -C option",
+		},
+		{
+			name:          "missing collections config file",
+			args:          []string{"-n", "example02", "-v", "anotherversion", "--sequence", "1", "-C", "mychannel", "--collections-config", "/does/not/exist.json"},
+			errorExpected: true,
+			errMsg:        "Expected error executing commit command with a missing collections config file",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetFlags()
+			cmd := commitCmd(mockCF)
+			addFlags(cmd)
+			cmd.SetArgs(test.args)
+			err = cmd.Execute()
+			checkError(t, err, test.errorExpected, test.errMsg)
+		})
+	}
+}
+
+func TestCommitCmdProposal(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "commit-cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	collectionsConfig := filepath.Join(tempDir, "collections.json")
+	err = ioutil.WriteFile(collectionsConfig, []byte(sampleCollectionConfigGood), 0644)
+	require.NoError(t, err)
+
+	mockCF, err := getMockChaincodeCmdFactory()
+	require.NoError(t, err)
+	response := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Endorsement: &pb.Endorsement{},
+	}
+	endorserClient1 := &capturingEndorserClient{response: response}
+	endorserClient2 := &capturingEndorserClient{response: response}
+	mockCF.EndorserClients = []pb.EndorserClient{endorserClient1, endorserClient2}
+
+	resetFlags()
+	cmd := commitCmd(mockCF)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-v", "1.0", "--sequence", "1", "-C", "mychannel", "--collections-config", collectionsConfig})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	// the same proposal is endorsed by every peer
+	require.Len(t, endorserClient1.proposals, 1)
+	require.Len(t, endorserClient2.proposals, 1)
+	assert.Equal(t, endorserClient1.proposals[0], endorserClient2.proposals[0])
+
+	funcName, argsBytes := lifecycleInvocation(t, endorserClient1.proposals[0])
+	assert.Equal(t, "CommitChaincodeDefinition", funcName)
+
+	args := &lb.CommitChaincodeDefinitionArgs{}
+	err = proto.Unmarshal(argsBytes, args)
+	require.NoError(t, err)
+	assert.Equal(t, "example02", args.Name)
+	assert.Equal(t, int64(1), args.Definition.Sequence)
+	assert.Equal(t, "1.0", args.Definition.Version)
+	assert.Equal(t, "escc", args.Definition.EndorsementPlugin)
+	assert.Equal(t, "vscc", args.Definition.ValidationPlugin)
+	require.NotNil(t, args.Definition.Collections)
+	assert.Len(t, args.Definition.Collections.Config, 1)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/msp"
 	ccapi "github.com/hyperledger/fabric/peer/chaincode/api"
@@ -30,6 +31,7 @@ import (
 	pcommon "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -152,6 +154,74 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, invoke bool, cf *ChaincodeCmdFac
 	return nil
 }
 
+// getChaincodeDefinition gets the chaincode definition from the cli cmd parameters
+func getChaincodeDefinition(cmd *cobra.Command) (*lb.ChaincodeDefinition, error) {
+	if err := checkChaincodeCmdParams(cmd); err != nil {
+		// unset usage silence because it's a command line usage error
+		cmd.SilenceUsage = false
+		return nil, err
+	}
+
+	var collections *pcommon.CollectionConfigPackage
+	if collectionsConfigFile != common.UndefinedParamValue {
+		collections = &pcommon.CollectionConfigPackage{}
+		if err := proto.Unmarshal(collectionConfigBytes, collections); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal collection configuration")
+		}
+	}
+
+	return &lb.ChaincodeDefinition{
+		Sequence:            chaincodeSequence,
+		Version:             chaincodeVersion,
+		EndorsementPlugin:   escc,
+		ValidationPlugin:    vscc,
+		ValidationParameter: policyMarshalled,
+		Collections:         collections,
+	}, nil
+}
+
+// invokeLifecycle invokes the function of the lifecycle system chaincode
+// with the given arguments on the peers and sends the endorsed transaction
+// for ordering
+func invokeLifecycle(funcName string, args proto.Message, cf *ChaincodeCmdFactory) error {
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling lifecycle arguments")
+	}
+
+	spec := &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_GOLANG,
+		ChaincodeId: &pb.ChaincodeID{Name: privdata.LifecycleNamespace},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(funcName), argsBytes}},
+	}
+
+	proposalResp, err := ChaincodeInvokeOrQuery(
+		spec,
+		channelID,
+		"",
+		true,
+		cf.Signer,
+		cf.Certificate,
+		cf.EndorserClients,
+		cf.DeliverClients,
+		cf.BroadcastClient)
+	if err != nil {
+		return errors.Errorf("%s - proposal response: %v", err, proposalResp)
+	}
+	if proposalResp == nil {
+		return errors.Errorf("received nil proposal response for %s", funcName)
+	}
+	if proposalResp.Response == nil || proposalResp.Response.Status >= shim.ERRORTHRESHOLD {
+		return errors.Errorf("%s failed: %v", funcName, proposalResp.Response)
+	}
+	if proposalResp.Endorsement == nil {
+		return errors.Errorf("endorsement failure during %s. response: %v", funcName, proposalResp.Response)
+	}
+
+	logger.Infof("%s successful for chaincode '%s' on channel '%s'", funcName, chaincodeName, channelID)
+	return nil
+}
+
 type collectionConfigJson struct {
 	Name           string `json:"name"`
 	Policy         string `json:"policy"`
@@ -223,11 +293,16 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 	}
 
 	if cmd.Name() == instantiateCmdName || cmd.Name() == installCmdName ||
-		cmd.Name() == upgradeCmdName || cmd.Name() == packageCmdName {
+		cmd.Name() == upgradeCmdName || cmd.Name() == packageCmdName ||
+		cmd.Name() == approveForMyOrgCmdName || cmd.Name() == commitCmdName {
 		if chaincodeVersion == common.UndefinedParamValue {
 			return errors.Errorf("chaincode version is not provided for %s", cmd.Name())
 		}
 
+		if (cmd.Name() == approveForMyOrgCmdName || cmd.Name() == commitCmdName) && chaincodeSequence <= 0 {
+			return errors.Errorf("chaincode sequence must be greater than 0 for %s", cmd.Name())
+		}
+
 		if escc != common.UndefinedParamValue {
 			logger.Infof("Using escc %s", escc)
 		} else {
@@ -281,7 +356,8 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 			return errors.New("non-empty JSON chaincode parameters must contain the following keys: 'Args' or 'Function' and 'Args'")
 		}
 	} else {
-		if cmd == nil || (cmd != chaincodeInstallCmd && cmd != chaincodePackageCmd) {
+		if cmd == nil || (cmd != chaincodeInstallCmd && cmd != chaincodePackageCmd &&
+			cmd != chaincodeApproveForMyOrgCmd && cmd != chaincodeCommitCmd) {
 			return errors.New("empty JSON chaincode parameters must contain the following keys: 'Args' or 'Function' and 'Args'")
 		}
 	}
@@ -311,8 +387,8 @@ func validatePeerConnectionParameters(cmdName string) error {
 		}
 	}
 
-	// currently only support multiple peer addresses for invoke
-	if cmdName != "invoke" && len(peerAddresses) > 1 {
+	// currently only support multiple peer addresses for invoke and commit
+	if cmdName != "invoke" && cmdName != commitCmdName && len(peerAddresses) > 1 {
 		return errors.Errorf("'%s' command can only be executed against one peer. received %d", cmdName, len(peerAddresses))
 	}
 
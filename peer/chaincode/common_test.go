@@ -239,6 +239,20 @@ func TestValidatePeerConnectionParams(t *testing.T) {
 	assert.Error(err)
 	assert.Contains(err.Error(), "command can only be executed against one peer")
 
+	// failure - more than one peer - approveformyorg
+	resetFlags()
+	peerAddresses = []string{"peer0", "peer1"}
+	err = validatePeerConnectionParameters("approveformyorg")
+	assert.Error(err)
+	assert.Contains(err.Error(), "command can only be executed against one peer")
+
+	// success - more than one peer - commit
+	// TLS disabled
+	resetFlags()
+	peerAddresses = []string{"peer0", "peer1"}
+	err = validatePeerConnectionParameters("commit")
+	assert.NoError(err)
+
 	// success - peer provided and no TLS root certs
 	// TLS disabled
 	resetFlags()
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var chaincodeUninstallCmd *cobra.Command
+
+const uninstallCmdName = "uninstall"
+
+// uninstallCmd returns the cobra command for Chaincode Uninstall
+func uninstallCmd(cf *ChaincodeCmdFactory) *cobra.Command {
+	chaincodeUninstallCmd = &cobra.Command{
+		Use:   uninstallCmdName,
+		Short: fmt.Sprintf("Remove an installed %s from the peer.", chainFuncName),
+		Long: fmt.Sprintf("Remove an installed %s and the image built for it from the peer. The %s is not "+
+			"removed while it is defined at that version on any channel the peer has joined.", chainFuncName, chainFuncName),
+		ValidArgs: []string{"1"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return chaincodeUninstall(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"name",
+		"version",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(chaincodeUninstallCmd, flagList)
+
+	return chaincodeUninstallCmd
+}
+
+func chaincodeUninstall(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	if chaincodeName == common.UndefinedParamValue {
+		return errors.Errorf("must supply value for %s name parameter", chainFuncName)
+	}
+	if chaincodeVersion == common.UndefinedParamValue {
+		return errors.Errorf("chaincode version is not provided for %s", cmd.Name())
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	hash, err := uninstall(chaincodeName, chaincodeVersion, cf)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Uninstalled chaincode %s:%s with hash %s", chaincodeName, chaincodeVersion, hex.EncodeToString(hash))
+	return nil
+}
+
+// uninstall invokes the uninstall function of the lifecycle system chaincode
+// on the peer and returns the hash of the removed chaincode
+func uninstall(name, version string, cf *ChaincodeCmdFactory) ([]byte, error) {
+	argsBytes, err := proto.Marshal(&lb.UninstallChaincodeArgs{
+		Name:    name,
+		Version: version,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling lifecycle arguments")
+	}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error serializing identity for %s", cf.Signer.GetIdentifier()))
+	}
+
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: privdata.LifecycleNamespace},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(lifecycle.UninstallChaincodeFuncName), argsBytes}},
+		},
+	}
+	prop, _, err := utils.CreateProposalFromCIS(pcommon.HeaderType_ENDORSER_TRANSACTION, "", invocation, creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating proposal")
+	}
+
+	signedProp, err := utils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating signed proposal")
+	}
+
+	// uninstall is currently only supported for one peer
+	proposalResponse, err := cf.EndorserClients[0].ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error endorsing uninstall")
+	}
+	if proposalResponse == nil || proposalResponse.Response == nil {
+		return nil, errors.New("received nil proposal response for uninstall")
+	}
+	if proposalResponse.Response.Status != int32(pcommon.Status_SUCCESS) {
+		return nil, errors.Errorf("uninstall failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+	}
+
+	result := &lb.UninstallChaincodeResult{}
+	if err := proto.Unmarshal(proposalResponse.Response.Payload, result); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling uninstall result")
+	}
+
+	return result.Hash, nil
+}
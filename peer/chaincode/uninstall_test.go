@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUninstallCmd(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactory()
+	assert.NoError(t, err, "Error getting mock chaincode command factory")
+
+	var tests = []struct {
+		name          string
+		args          []string
+		errorExpected bool
+		errMsg        string
+	}{
+		{
+			name:          "successful",
+			args:          []string{"-n", "example02", "-v", "1.0"},
+			errorExpected: false,
+			errMsg:        "Run chaincode uninstall cmd error",
+		},
+		{
+			name:          "no option",
+			args:          []string{},
+			errorExpected: true,
+			errMsg:        "Expected error executing uninstall command without required options",
+		},
+		{
+			name:          "missing version",
+			args:          []string{"-n", "example02"},
+			errorExpected: true,
+			errMsg:        "Expected error executing uninstall command without the -v option",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetFlags()
+			cmd := uninstallCmd(mockCF)
+			addFlags(cmd)
+			cmd.SetArgs(test.args)
+			err = cmd.Execute()
+			checkError(t, err, test.errorExpected, test.errMsg)
+		})
+	}
+}
+
+func TestUninstallProposal(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactory()
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&lb.UninstallChaincodeResult{Hash: []byte("hash")})
+	require.NoError(t, err)
+	endorserClient := &capturingEndorserClient{
+		response: &pb.ProposalResponse{
+			Response: &pb.Response{Status: 200, Payload: payload},
+		},
+	}
+	mockCF.EndorserClients = []pb.EndorserClient{endorserClient}
+
+	hash, err := uninstall("example02", "1.0", mockCF)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hash"), hash)
+
+	require.Len(t, endorserClient.proposals, 1)
+	funcName, argsBytes := lifecycleInvocation(t, endorserClient.proposals[0])
+	assert.Equal(t, "UninstallChaincode", funcName)
+
+	args := &lb.UninstallChaincodeArgs{}
+	err = proto.Unmarshal(argsBytes, args)
+	require.NoError(t, err)
+	assert.Equal(t, "example02", args.Name)
+	assert.Equal(t, "1.0", args.Version)
+}
+
+func TestUninstallFailures(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactory()
+	require.NoError(t, err)
+
+	mockCF.EndorserClients = []pb.EndorserClient{common.GetMockEndorserClient(nil, errors.New("connection refused"))}
+	_, err = uninstall("example02", "1.0", mockCF)
+	assert.EqualError(t, err, "error endorsing uninstall: connection refused")
+
+	mockCF.EndorserClients = []pb.EndorserClient{common.GetMockEndorserClient(nil, nil)}
+	_, err = uninstall("example02", "1.0", mockCF)
+	assert.EqualError(t, err, "received nil proposal response for uninstall")
+
+	mockCF.EndorserClients = []pb.EndorserClient{common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response: &pb.Response{Status: 500, Message: "chaincode 'example02:1.0' is in use"},
+	}, nil)}
+	_, err = uninstall("example02", "1.0", mockCF)
+	assert.EqualError(t, err, "uninstall failed with status: 500 - chaincode 'example02:1.0' is in use")
+
+	mockCF.EndorserClients = []pb.EndorserClient{common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response: &pb.Response{Status: 200, Payload: []byte("garbage")},
+	}, nil)}
+	_, err = uninstall("example02", "1.0", mockCF)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error unmarshaling uninstall result")
+}
@@ -43,6 +43,10 @@ var (
 
 	// fetch related variables
 	bestEffort bool
+
+	// migrate related variables
+	migrationChannels []string
+	metadataFile      string
 )
 
 // Cmd returns the cobra command for Node
@@ -56,6 +60,7 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 	channelCmd.AddCommand(updateCmd(cf))
 	channelCmd.AddCommand(signconfigtxCmd(cf))
 	channelCmd.AddCommand(getinfoCmd(cf))
+	channelCmd.AddCommand(migrateCmd(cf))
 
 	return channelCmd
 }
@@ -81,6 +86,8 @@ func resetFlags() {
 	flags.StringVarP(&outputBlock, "outputBlock", "", common.UndefinedParamValue, `The path to write the genesis block for the channel. (default ./<channelID>.block)`)
 	flags.DurationVarP(&timeout, "timeout", "t", 10*time.Second, "Channel creation timeout")
 	flags.BoolVarP(&bestEffort, "bestEffort", "", false, "Whether fetch requests should ignore errors and return blocks on a best effort basis")
+	flags.StringSliceVarP(&migrationChannels, "channels", "", nil, "Comma separated list of the channels to migrate, starting with the system channel")
+	flags.StringVarP(&metadataFile, "metadata", "", "", "File containing the JSON document of the etcdraft ConfigMetadata the channels are migrated to")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
@@ -96,8 +103,8 @@ func attachFlags(cmd *cobra.Command, names []string) {
 
 var channelCmd = &cobra.Command{
 	Use:   "channel",
-	Short: "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
-	Long:  "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
+	Short: "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo|migrate.",
+	Long:  "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo|migrate.",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		common.InitCmd(cmd, args)
 		common.SetOrdererEnv(cmd, args)
@@ -106,6 +113,8 @@ var channelCmd = &cobra.Command{
 
 type BroadcastClientFactory func() (common.BroadcastClient, error)
 
+type deliverClientFactory func(channelID string) (deliverClientIntf, error)
+
 type deliverClientIntf interface {
 	GetSpecifiedBlock(num uint64) (*cb.Block, error)
 	GetOldestBlock() (*cb.Block, error)
@@ -120,6 +129,7 @@ type ChannelCmdFactory struct {
 	BroadcastClient  common.BroadcastClient
 	DeliverClient    deliverClientIntf
 	BroadcastFactory BroadcastClientFactory
+	DeliverFactory   deliverClientFactory
 }
 
 // InitCmdFactory init the ChannelCmdFactory with clients to endorser and orderer according to params
@@ -141,6 +151,15 @@ func InitCmdFactory(isEndorserRequired, isPeerDeliverRequired, isOrdererRequired
 		return common.GetBroadcastClientFnc()
 	}
 
+	// for commands which fetch blocks of several channels from the orderer
+	cf.DeliverFactory = func(channelID string) (deliverClientIntf, error) {
+		dc, err := common.NewDeliverClientForOrderer(channelID, bestEffort)
+		if err != nil {
+			return nil, err
+		}
+		return dc, nil
+	}
+
 	// for join and list, we need the endorser as well
 	if isEndorserRequired {
 		// creating an EndorserClient with these empty parameters will create a
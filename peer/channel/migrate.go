@@ -0,0 +1,379 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	localsigner "github.com/hyperledger/fabric/common/localmsp"
+	configtxupdate "github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// The steps of the migration from kafka to etcdraft, in order.
+const (
+	migrationStepMaintenance = "maintenance"
+	migrationStepConsensus   = "consensus"
+	migrationStepNormal      = "normal"
+)
+
+// configPollInterval is the interval at which the orderer is polled
+// for the config block which commits a config update.
+var configPollInterval = 500 * time.Millisecond
+
+func migrateCmd(cf *ChannelCmdFactory) *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate <maintenance|consensus|normal>",
+		Short: "Migrate the channels from kafka to etcdraft.",
+		Long: "Takes a step of the migration of the channels from kafka to etcdraft, by generating, signing and submitting " +
+			"the config update of the step for each channel, in order, and waiting until it is committed. The steps are " +
+			"'maintenance', which puts the channels in maintenance mode, 'consensus', which changes their consensus type " +
+			"to etcdraft with the metadata of '--metadata', and, once the ordering nodes were restarted, 'normal', which " +
+			"puts the channels back in normal mode. If the step fails on a channel, it is reverted on the channels which " +
+			"already took it. Requires '-o' and '--channels'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrate(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channels",
+		"metadata",
+		"timeout",
+	}
+	attachFlags(migrateCmd, flagList)
+
+	return migrateCmd
+}
+
+// consensusTypeTransition returns the ConsensusType of a channel after a
+// migration step, or nil if the channel already took the step.
+type consensusTypeTransition func(current *ab.ConsensusType) (*ab.ConsensusType, error)
+
+// migratedChannel is a channel which took the migration step.
+type migratedChannel struct {
+	channelID string
+	previous  *ab.ConsensusType
+}
+
+func migrate(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if len(args) != 1 {
+		return errors.Errorf("migration step required, %s, %s or %s", migrationStepMaintenance, migrationStepConsensus, migrationStepNormal)
+	}
+	if len(migrationChannels) == 0 {
+		return errors.New("Must supply the channels to migrate")
+	}
+
+	transition, err := migrationTransition(args[0])
+	if err != nil {
+		return err
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		if len(strings.Split(common.OrderingEndpoint, ":")) != 2 {
+			return errors.Errorf("ordering service endpoint %s is not valid or missing", common.OrderingEndpoint)
+		}
+		cf, err = InitCmdFactory(EndorserNotRequired, PeerDeliverNotRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	var migrated []migratedChannel
+	for _, channelID := range migrationChannels {
+		previous, changed, err := migrateChannel(cf, channelID, transition)
+		if err == nil {
+			if changed {
+				migrated = append(migrated, migratedChannel{channelID: channelID, previous: previous})
+			}
+			continue
+		}
+
+		err = errors.WithMessage(err, fmt.Sprintf("migration step %s failed on channel %s", args[0], channelID))
+		logger.Errorf("%s, reverting it on %d channels", err, len(migrated))
+		if rollbackErr := rollback(cf, migrated); rollbackErr != nil {
+			return errors.Errorf("%s, and reverting it failed: %s", err, rollbackErr)
+		}
+		return err
+	}
+
+	logger.Infof("Successfully took migration step %s on %d channels", args[0], len(migrationChannels))
+	return nil
+}
+
+// migrationTransition returns the transition of the ConsensusType of a channel of the migration step.
+func migrationTransition(step string) (consensusTypeTransition, error) {
+	switch step {
+	case migrationStepMaintenance:
+		return func(current *ab.ConsensusType) (*ab.ConsensusType, error) {
+			if current.State == ab.ConsensusType_STATE_MAINTENANCE {
+				return nil, nil
+			}
+			if current.Type != "kafka" {
+				return nil, errors.Errorf("consensus type is %s, not kafka", current.Type)
+			}
+			return &ab.ConsensusType{Type: current.Type, Metadata: current.Metadata, State: ab.ConsensusType_STATE_MAINTENANCE}, nil
+		}, nil
+
+	case migrationStepConsensus:
+		metadata, err := readConsensusMetadata(metadataFile)
+		if err != nil {
+			return nil, err
+		}
+		return func(current *ab.ConsensusType) (*ab.ConsensusType, error) {
+			if current.State != ab.ConsensusType_STATE_MAINTENANCE {
+				return nil, errors.New("channel is not in maintenance mode")
+			}
+			if current.Type == "etcdraft" && bytes.Equal(current.Metadata, metadata) {
+				return nil, nil
+			}
+			if current.Type != "kafka" && current.Type != "etcdraft" {
+				return nil, errors.Errorf("consensus type is %s, not kafka", current.Type)
+			}
+			return &ab.ConsensusType{Type: "etcdraft", Metadata: metadata, State: current.State}, nil
+		}, nil
+
+	case migrationStepNormal:
+		return func(current *ab.ConsensusType) (*ab.ConsensusType, error) {
+			if current.State == ab.ConsensusType_STATE_NORMAL {
+				return nil, nil
+			}
+			return &ab.ConsensusType{Type: current.Type, Metadata: current.Metadata, State: ab.ConsensusType_STATE_NORMAL}, nil
+		}, nil
+
+	default:
+		return nil, errors.Errorf("unknown migration step %s, must be %s, %s or %s", step, migrationStepMaintenance, migrationStepConsensus, migrationStepNormal)
+	}
+}
+
+// readConsensusMetadata reads the JSON document of an etcdraft ConfigMetadata,
+// and returns it marshaled.
+func readConsensusMetadata(file string) ([]byte, error) {
+	if file == "" {
+		return nil, errors.New("Must supply the etcdraft metadata file")
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open etcdraft metadata file")
+	}
+	defer f.Close()
+
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := protolator.DeepUnmarshalJSON(f, metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to decode etcdraft metadata")
+	}
+	if len(metadata.Consenters) == 0 {
+		return nil, errors.New("etcdraft metadata has no consenters")
+	}
+	return proto.Marshal(metadata)
+}
+
+// migrateChannel takes the migration step on the channel, and returns its
+// previous ConsensusType and whether the step changed it.
+func migrateChannel(cf *ChannelCmdFactory, channelID string, transition consensusTypeTransition) (*ab.ConsensusType, bool, error) {
+	dc, err := cf.DeliverFactory(channelID)
+	if err != nil {
+		return nil, false, errors.WithMessage(err, "error getting deliver client")
+	}
+	defer dc.Close()
+
+	config, configNumber, err := fetchConfig(dc)
+	if err != nil {
+		return nil, false, err
+	}
+	current, err := consensusType(config)
+	if err != nil {
+		return nil, false, err
+	}
+	next, err := transition(current)
+	if err != nil {
+		return nil, false, err
+	}
+	if next == nil {
+		logger.Infof("Channel %s already took the migration step, its consensus type is %s in %s", channelID, current.Type, current.State)
+		return current, false, nil
+	}
+
+	if err := updateConsensusType(cf, dc, channelID, config, configNumber, next); err != nil {
+		return nil, false, err
+	}
+	return current, true, nil
+}
+
+// rollback restores the previous ConsensusType of the channels, in reverse order.
+func rollback(cf *ChannelCmdFactory, migrated []migratedChannel) error {
+	for i := len(migrated) - 1; i >= 0; i-- {
+		channelID, previous := migrated[i].channelID, migrated[i].previous
+		_, _, err := migrateChannel(cf, channelID, func(current *ab.ConsensusType) (*ab.ConsensusType, error) {
+			if sameConsensusType(current, previous) {
+				return nil, nil
+			}
+			return previous, nil
+		})
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to revert channel %s", channelID))
+		}
+		logger.Infof("Reverted channel %s to consensus type %s in %s", channelID, previous.Type, previous.State)
+	}
+	return nil
+}
+
+// updateConsensusType submits the config update which changes the ConsensusType of the
+// channel, once validated against the rules of consensus-type migration, and waits until
+// the orderer commits it.
+func updateConsensusType(cf *ChannelCmdFactory, dc deliverClientIntf, channelID string, config *cb.Config, configNumber uint64, next *ab.ConsensusType) error {
+	updated := proto.Clone(config).(*cb.Config)
+	value := updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey]
+	value.Value = utils.MarshalOrPanic(next)
+
+	configUpdate, err := configtxupdate.Compute(config, updated)
+	if err != nil {
+		return errors.WithMessage(err, "error computing config update")
+	}
+	configUpdate.ChannelId = channelID
+
+	env, err := signConfigUpdate(channelID, configUpdate)
+	if err != nil {
+		return err
+	}
+
+	err = msgprocessor.ValidateConsensusTypeMigrationStep(channelID, config, &cb.ConfigEnvelope{Config: updated, LastUpdate: env})
+	if err != nil {
+		return errors.WithMessage(err, "config update is not a valid consensus-type migration step")
+	}
+
+	broadcastClient, err := cf.BroadcastFactory()
+	if err != nil {
+		return errors.WithMessage(err, "error getting broadcast client")
+	}
+	defer broadcastClient.Close()
+	if err := broadcastClient.Send(env); err != nil {
+		return err
+	}
+
+	logger.Infof("Submitted config update of channel %s to consensus type %s in %s", channelID, next.Type, next.State)
+	return waitForConsensusType(dc, channelID, configNumber, next)
+}
+
+// signConfigUpdate signs the config update with the local signer, and wraps it in an envelope.
+func signConfigUpdate(channelID string, configUpdate *cb.ConfigUpdate) (*cb.Envelope, error) {
+	signer := localsigner.NewSigner()
+	sigHeader, err := signer.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: utils.MarshalOrPanic(configUpdate),
+	}
+	configSig := &cb.ConfigSignature{
+		SignatureHeader: utils.MarshalOrPanic(sigHeader),
+	}
+	configSig.Signature, err = signer.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdateEnv.ConfigUpdate))
+	if err != nil {
+		return nil, err
+	}
+	configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, signer, configUpdateEnv, 0, 0)
+}
+
+// waitForConsensusType waits until a config block newer than the given
+// one is committed, and checks that it carries the ConsensusType.
+func waitForConsensusType(dc deliverClientIntf, channelID string, configNumber uint64, expected *ab.ConsensusType) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		config, number, err := fetchConfig(dc)
+		if err != nil {
+			return err
+		}
+		if number > configNumber {
+			actual, err := consensusType(config)
+			if err != nil {
+				return err
+			}
+			if !sameConsensusType(actual, expected) {
+				return errors.Errorf("config of channel %s was updated to consensus type %s in %s instead", channelID, actual.Type, actual.State)
+			}
+			logger.Infof("Config update of channel %s was committed in block %d", channelID, number)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for the config update of channel %s to be committed", channelID)
+		}
+		time.Sleep(configPollInterval)
+	}
+}
+
+// fetchConfig returns the config of the channel, and the number of its config block.
+func fetchConfig(dc deliverClientIntf) (*cb.Config, uint64, error) {
+	block, err := dc.GetNewestBlock()
+	if err != nil {
+		return nil, 0, err
+	}
+	lc, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return nil, 0, err
+	}
+	if block.Header.Number != lc {
+		if block, err = dc.GetSpecifiedBlock(lc); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "error extracting config envelope")
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "error extracting config envelope")
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return configEnv.Config, lc, nil
+}
+
+// consensusType returns the ConsensusType of the channel config.
+func consensusType(config *cb.Config) (*ab.ConsensusType, error) {
+	ordererGroup, ok := config.GetChannelGroup().GetGroups()[channelconfig.OrdererGroupKey]
+	if !ok {
+		return nil, errors.New("config has no orderer group")
+	}
+	value, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return nil, errors.Errorf("config has no %s value", channelconfig.ConsensusTypeKey)
+	}
+	ct := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, ct); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling ConsensusType")
+	}
+	return ct, nil
+}
+
+func sameConsensusType(ct1, ct2 *ab.ConsensusType) bool {
+	return ct1.Type == ct2.Type && ct1.State == ct2.State && bytes.Equal(ct1.Metadata, ct2.Metadata)
+}
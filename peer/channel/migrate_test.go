@@ -0,0 +1,278 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrationOrderer commits the ConsensusType of the config updates
+// it receives in a new config block of the channel.
+type migrationOrderer struct {
+	blocks map[string][]*cb.Block
+	sent   []string
+	// reject returns whether the config update of the channel is rejected
+	reject func(channelID string) bool
+}
+
+func newMigrationOrderer(t *testing.T, channelIDs ...string) *migrationOrderer {
+	conf := configtxgentest.Load(genesisconfig.SampleDevModeKafkaProfile)
+	conf.Orderer.Capabilities = map[string]bool{capabilities.OrdererV1_4_2: true}
+	channelGroup, err := encoder.NewChannelGroup(conf)
+	require.NoError(t, err)
+
+	mo := &migrationOrderer{blocks: map[string][]*cb.Block{}}
+	for _, channelID := range channelIDs {
+		mo.appendConfig(channelID, &cb.Config{ChannelGroup: channelGroup})
+	}
+	return mo
+}
+
+func (mo *migrationOrderer) appendConfig(channelID string, config *cb.Config) {
+	block := cb.NewBlock(uint64(len(mo.blocks[channelID])), nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(&cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG), ChannelId: channelID}),
+			},
+			Data: utils.MarshalOrPanic(&cb.ConfigEnvelope{Config: config}),
+		}),
+	})}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&cb.LastConfig{Index: block.Header.Number}),
+	})
+	mo.blocks[channelID] = append(mo.blocks[channelID], block)
+}
+
+func (mo *migrationOrderer) consensusType(t *testing.T, channelID string) *ab.ConsensusType {
+	config, _, err := fetchConfig(&migrationDeliverClient{orderer: mo, channelID: channelID})
+	require.NoError(t, err)
+	ct, err := consensusType(config)
+	require.NoError(t, err)
+	return ct
+}
+
+func (mo *migrationOrderer) Send(env *cb.Envelope) error {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	configUpdate, err := utils.EnvelopeToConfigUpdate(env)
+	if err != nil {
+		return err
+	}
+	if mo.reject != nil && mo.reject(chdr.ChannelId) {
+		return errors.New("rejected")
+	}
+	mo.sent = append(mo.sent, chdr.ChannelId)
+
+	update := &cb.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate.ConfigUpdate, update); err != nil {
+		return err
+	}
+	config, _, err := fetchConfig(&migrationDeliverClient{orderer: mo, channelID: chdr.ChannelId})
+	if err != nil {
+		return err
+	}
+	config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey] =
+		update.WriteSet.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey]
+	mo.appendConfig(chdr.ChannelId, config)
+	return nil
+}
+
+func (mo *migrationOrderer) Close() error {
+	return nil
+}
+
+type migrationDeliverClient struct {
+	orderer   *migrationOrderer
+	channelID string
+}
+
+func (m *migrationDeliverClient) GetSpecifiedBlock(num uint64) (*cb.Block, error) {
+	return m.orderer.blocks[m.channelID][num], nil
+}
+
+func (m *migrationDeliverClient) GetOldestBlock() (*cb.Block, error) {
+	return m.GetSpecifiedBlock(0)
+}
+
+func (m *migrationDeliverClient) GetNewestBlock() (*cb.Block, error) {
+	blocks := m.orderer.blocks[m.channelID]
+	if len(blocks) == 0 {
+		return nil, errors.Errorf("channel %s does not exist", m.channelID)
+	}
+	return blocks[len(blocks)-1], nil
+}
+
+func (m *migrationDeliverClient) Close() error {
+	return nil
+}
+
+func migrationCmdFactory(mo *migrationOrderer) *ChannelCmdFactory {
+	return &ChannelCmdFactory{
+		BroadcastFactory: func() (common.BroadcastClient, error) {
+			return mo, nil
+		},
+		DeliverFactory: func(channelID string) (deliverClientIntf, error) {
+			return &migrationDeliverClient{orderer: mo, channelID: channelID}, nil
+		},
+	}
+}
+
+func executeMigrate(mo *migrationOrderer, args ...string) error {
+	resetFlags()
+	cmd := migrateCmd(migrationCmdFactory(mo))
+	AddFlags(cmd)
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func writeMetadataFile(t *testing.T, dir string) string {
+	file := filepath.Join(dir, "metadata.json")
+	metadata := `{"consenters": [{"host": "orderer0", "port": 7050, "client_tls_cert": "Y2VydA==", "server_tls_cert": "Y2VydA=="}]}`
+	require.NoError(t, ioutil.WriteFile(file, []byte(metadata), 0600))
+	return file
+}
+
+func TestMigrate(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	dir, err := ioutil.TempDir("", "migrate-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	metadataFile := writeMetadataFile(t, dir)
+
+	mo := newMigrationOrderer(t, "syschannel", "mychannel")
+
+	assert.NoError(t, executeMigrate(mo, "maintenance", "--channels", "syschannel,mychannel"))
+	assert.Equal(t, []string{"syschannel", "mychannel"}, mo.sent)
+	for _, channelID := range []string{"syschannel", "mychannel"} {
+		ct := mo.consensusType(t, channelID)
+		assert.Equal(t, "kafka", ct.Type)
+		assert.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, ct.State)
+	}
+
+	// Channels which already took the step are skipped.
+	assert.NoError(t, executeMigrate(mo, "maintenance", "--channels", "syschannel,mychannel"))
+	assert.Len(t, mo.sent, 2)
+
+	assert.NoError(t, executeMigrate(mo, "consensus", "--channels", "syschannel,mychannel", "--metadata", metadataFile))
+	assert.Len(t, mo.sent, 4)
+	for _, channelID := range []string{"syschannel", "mychannel"} {
+		ct := mo.consensusType(t, channelID)
+		assert.Equal(t, "etcdraft", ct.Type)
+		assert.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, ct.State)
+		assert.NotEmpty(t, ct.Metadata)
+	}
+
+	assert.NoError(t, executeMigrate(mo, "normal", "--channels", "syschannel,mychannel"))
+	assert.Len(t, mo.sent, 6)
+	for _, channelID := range []string{"syschannel", "mychannel"} {
+		ct := mo.consensusType(t, channelID)
+		assert.Equal(t, "etcdraft", ct.Type)
+		assert.Equal(t, ab.ConsensusType_STATE_NORMAL, ct.State)
+	}
+}
+
+func TestMigrateRollback(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	t.Run("reverted", func(t *testing.T) {
+		mo := newMigrationOrderer(t, "syschannel", "mychannel1", "mychannel2")
+		mo.reject = func(channelID string) bool { return channelID == "mychannel2" }
+
+		err := executeMigrate(mo, "maintenance", "--channels", "syschannel,mychannel1,mychannel2")
+		assert.EqualError(t, err, "migration step maintenance failed on channel mychannel2: rejected")
+		assert.Equal(t, []string{"syschannel", "mychannel1", "mychannel1", "syschannel"}, mo.sent)
+		for _, channelID := range []string{"syschannel", "mychannel1", "mychannel2"} {
+			assert.Equal(t, ab.ConsensusType_STATE_NORMAL, mo.consensusType(t, channelID).State)
+		}
+	})
+
+	t.Run("reverting fails", func(t *testing.T) {
+		mo := newMigrationOrderer(t, "syschannel", "mychannel")
+		mo.reject = func(string) bool { return len(mo.sent) > 0 }
+
+		err := executeMigrate(mo, "maintenance", "--channels", "syschannel,mychannel")
+		assert.EqualError(t, err, "migration step maintenance failed on channel mychannel: rejected, and reverting it failed: failed to revert channel syschannel: rejected")
+		assert.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, mo.consensusType(t, "syschannel").State)
+	})
+}
+
+func TestMigrateBadInput(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	mo := newMigrationOrderer(t, "mychannel")
+
+	for _, tc := range []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "no step",
+			args:        []string{"--channels", "mychannel"},
+			expectedErr: "migration step required, maintenance, consensus or normal",
+		},
+		{
+			name:        "unknown step",
+			args:        []string{"rollback", "--channels", "mychannel"},
+			expectedErr: "unknown migration step rollback, must be maintenance, consensus or normal",
+		},
+		{
+			name:        "no channels",
+			args:        []string{"maintenance"},
+			expectedErr: "Must supply the channels to migrate",
+		},
+		{
+			name:        "no metadata",
+			args:        []string{"consensus", "--channels", "mychannel"},
+			expectedErr: "Must supply the etcdraft metadata file",
+		},
+		{
+			name:        "unknown channel",
+			args:        []string{"maintenance", "--channels", "yourchannel"},
+			expectedErr: "migration step maintenance failed on channel yourchannel: channel yourchannel does not exist",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, executeMigrate(mo, tc.args...), tc.expectedErr)
+		})
+	}
+
+	dir, err := ioutil.TempDir("", "migrate-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	err = executeMigrate(mo, "consensus", "--channels", "mychannel", "--metadata", writeMetadataFile(t, dir))
+	assert.EqualError(t, err, "migration step consensus failed on channel mychannel: channel is not in maintenance mode")
+	assert.Empty(t, mo.sent)
+}
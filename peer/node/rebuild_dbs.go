@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func rebuildDBsCmd() *cobra.Command {
+	nodeRebuildDBsCmd.ResetFlags()
+	flags := nodeRebuildDBsCmd.Flags()
+	flags.StringVarP(&channelID, "channel", "c", common.UndefinedParamValue, "Channel whose databases need to be rebuilt.")
+	return nodeRebuildDBsCmd
+}
+
+var nodeRebuildDBsCmd = &cobra.Command{
+	Use:   "rebuild-dbs",
+	Short: "Rebuilds the databases of a channel.",
+	Long:  `Drops the state database, the history database, and the config history of a channel. The databases of the other channels are not touched. When the command is executed, the peer must be offline. When the peer starts after the command, the dropped databases are rebuilt from the block store. The progress of the rebuild is logged periodically and is published through the metrics and the health check endpoint of the operations service. If the peer stops during the rebuild, the rebuild resumes from the last committed block upon the next start.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		cmd.SilenceUsage = true
+		return kvledger.RebuildDBs(channelID)
+	},
+}
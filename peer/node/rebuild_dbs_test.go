@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildDBsCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rebuilddbs")
+	require.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Set("peer.fileSystemPath", "")
+
+	t.Run("when the channel is not supplied", func(t *testing.T) {
+		cmd := rebuildDBsCmd()
+		cmd.SetArgs([]string{})
+		err := cmd.Execute()
+		assert.EqualError(t, err, "Must supply channel ID")
+	})
+
+	t.Run("when the specified channel does not exist", func(t *testing.T) {
+		cmd := rebuildDBsCmd()
+		cmd.SetArgs([]string{"--channel", "ch1"})
+		err := cmd.Execute()
+		assert.EqualError(t, err, "ledger [ch1] does not exist")
+	})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var snapshotPath string
+
+func snapshotCmd() *cobra.Command {
+	nodeSnapshotCmd.ResetCommands()
+	nodeSnapshotCmd.AddCommand(snapshotExportCmd())
+	nodeSnapshotCmd.AddCommand(snapshotSubmitRequestCmd())
+	nodeSnapshotCmd.AddCommand(snapshotJoinCmd())
+	return nodeSnapshotCmd
+}
+
+var nodeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Manages ledger snapshots.",
+	Long:  `Exports snapshots of a channel ledger and joins the peer to a channel from a snapshot. The peer must be running.`,
+}
+
+func snapshotExportCmd() *cobra.Command {
+	nodeSnapshotExportCmd.ResetFlags()
+	flags := nodeSnapshotExportCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel for which the snapshot is exported.")
+	return nodeSnapshotExportCmd
+}
+
+var nodeSnapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports a snapshot of a channel.",
+	Long:  `Exports a snapshot of a channel at the last committed block. The snapshot is written to the snapshots directory of the peer.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		cmd.SilenceUsage = true
+		resp, err := invokeSnapshotFunction(cscc.ExportSnapshot, []byte(channelID))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot exported to %s\n", string(resp.Payload))
+		return nil
+	},
+}
+
+func snapshotSubmitRequestCmd() *cobra.Command {
+	nodeSnapshotSubmitRequestCmd.ResetFlags()
+	flags := nodeSnapshotSubmitRequestCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel for which the snapshot is requested.")
+	flags.Uint64VarP(&blockNumber, "blockNumber", "b", 0, "Block number at which the snapshot is to be exported.")
+	return nodeSnapshotSubmitRequestCmd
+}
+
+var nodeSnapshotSubmitRequestCmd = &cobra.Command{
+	Use:   "submitrequest",
+	Short: "Requests a snapshot of a channel at a future block.",
+	Long:  `Requests a snapshot of a channel to be exported when the block with the specified block number is committed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		cmd.SilenceUsage = true
+		_, err := invokeSnapshotFunction(cscc.SubmitSnapshotRequest,
+			[]byte(channelID), []byte(strconv.FormatUint(blockNumber, 10)))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot request submitted for block number %d\n", blockNumber)
+		return nil
+	},
+}
+
+func snapshotJoinCmd() *cobra.Command {
+	nodeSnapshotJoinCmd.ResetFlags()
+	flags := nodeSnapshotJoinCmd.Flags()
+	flags.StringVarP(&snapshotPath, "snapshotpath", "s", common.UndefinedParamValue, "Path to the snapshot directory on the peer.")
+	return nodeSnapshotJoinCmd
+}
+
+var nodeSnapshotJoinCmd = &cobra.Command{
+	Use:   "join",
+	Short: "Joins the peer to a channel from a snapshot.",
+	Long:  `Joins the peer to a channel by bootstrapping the channel ledger from a snapshot. The snapshot directory must be accessible to the peer.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if snapshotPath == common.UndefinedParamValue {
+			return errors.New("Must supply snapshot path")
+		}
+		cmd.SilenceUsage = true
+		_, err := invokeSnapshotFunction(cscc.JoinChainBySnapshot, []byte(snapshotPath))
+		if err != nil {
+			return err
+		}
+		fmt.Println("Successfully joined the channel from the snapshot")
+		return nil
+	},
+}
+
+// invokeSnapshotFunction sends a proposal for the given function to the
+// configuration system chaincode of the peer and returns the response
+func invokeSnapshotFunction(function string, args ...[]byte) (*pb.Response, error) {
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting the default signer")
+	}
+	endorserClient, err := common.GetEndorserClientFnc(common.UndefinedParamValue, common.UndefinedParamValue)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting the endorser client")
+	}
+
+	input := &pb.ChaincodeInput{Args: append([][]byte{[]byte(function)}, args...)}
+	spec := &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_GOLANG,
+		ChaincodeId: &pb.ChaincodeID{Name: "cscc"},
+		Input:       input,
+	}
+	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
+
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error serializing the signer identity")
+	}
+	prop, _, err := putils.CreateProposalFromCIS(pcommon.HeaderType_CONFIG, "", invocation, creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating the proposal")
+	}
+	signedProp, err := putils.GetSignedProposal(prop, signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error signing the proposal")
+	}
+	proposalResp, err := endorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error processing the proposal")
+	}
+	if proposalResp == nil || proposalResp.Response == nil {
+		return nil, errors.New("nil proposal response")
+	}
+	if proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
+		return nil, errors.Errorf("bad proposal response %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+	return proposalResp.Response, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/msp"
+	common2 "github.com/hyperledger/fabric/peer/common"
+	"github.com/hyperledger/fabric/peer/mocks"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCmd(t *testing.T) {
+	defer func() {
+		common2.GetDefaultSignerFnc = common2.GetDefaultSigner
+		common2.GetEndorserClientFnc = common2.GetEndorserClient
+	}()
+	common2.GetDefaultSignerFnc = func() (msp.SigningIdentity, error) {
+		return &mocks.Signer{}, nil
+	}
+	setEndorserResponse := func(resp *pb.ProposalResponse, err error) {
+		common2.GetEndorserClientFnc = func(string, string) (pb.EndorserClient, error) {
+			return common2.GetMockEndorserClient(resp, err), nil
+		}
+	}
+	okResponse := &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: []byte("/snapshots/completed/mychannel/5")}}
+
+	var tests = []struct {
+		name          string
+		args          []string
+		response      *pb.ProposalResponse
+		responseErr   error
+		expectedError string
+	}{
+		{
+			name:          "export without channel ID",
+			args:          []string{"export"},
+			expectedError: "Must supply channel ID",
+		},
+		{
+			name:     "export",
+			args:     []string{"export", "-c", "mychannel"},
+			response: okResponse,
+		},
+		{
+			name:          "export with a bad proposal response",
+			args:          []string{"export", "-c", "mychannel"},
+			response:      &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "Unknown chain ID, mychannel"}},
+			expectedError: "bad proposal response 500: Unknown chain ID, mychannel",
+		},
+		{
+			name:          "submitrequest without channel ID",
+			args:          []string{"submitrequest", "-b", "10"},
+			expectedError: "Must supply channel ID",
+		},
+		{
+			name:     "submitrequest",
+			args:     []string{"submitrequest", "-c", "mychannel", "-b", "10"},
+			response: okResponse,
+		},
+		{
+			name:          "submitrequest with an endorser error",
+			args:          []string{"submitrequest", "-c", "mychannel", "-b", "10"},
+			responseErr:   errors.New("connection refused"),
+			expectedError: "error processing the proposal: connection refused",
+		},
+		{
+			name:          "join without snapshot path",
+			args:          []string{"join"},
+			expectedError: "Must supply snapshot path",
+		},
+		{
+			name:     "join",
+			args:     []string{"join", "-s", "/snapshots/completed/mychannel/5"},
+			response: okResponse,
+		},
+		{
+			name:          "join with a nil proposal response",
+			args:          []string{"join", "-s", "/snapshots/completed/mychannel/5"},
+			expectedError: "nil proposal response",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setEndorserResponse(test.response, test.responseErr)
+			cmd := snapshotCmd()
+			cmd.SetArgs(test.args)
+			err := cmd.Execute()
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
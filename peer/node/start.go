@@ -9,10 +9,12 @@ package node
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"sync"
 	"syscall"
 	"time"
@@ -46,11 +48,14 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/platforms/java"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/node"
 	"github.com/hyperledger/fabric/core/comm"
+	coreconfig "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	"github.com/hyperledger/fabric/core/container/inproccontroller"
 	"github.com/hyperledger/fabric/core/endorser"
 	authHandler "github.com/hyperledger/fabric/core/handlers/auth"
@@ -64,6 +69,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/policyprovider"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/core/scc/lscc"
@@ -677,24 +683,71 @@ func registerChaincodeSupport(
 	sccp := scc.NewProvider(peer.Default, peer.DefaultSupport, ipRegistry)
 	lsccInst := lscc.New(sccp, aclProvider, pr)
 
-	dockerProvider := dockercontroller.NewProvider(
-		viper.GetString("peer.id"),
-		viper.GetString("peer.networkId"),
-		ops.Provider,
-	)
-	dockerVM := dockercontroller.NewDockerVM(
-		dockerProvider.PeerID,
-		dockerProvider.NetworkID,
-		dockerProvider.BuildMetrics,
-	)
+	ccConfig := chaincode.GlobalConfig()
+
+	var externalBuilders []*externalbuilder.Builder
+	if err := viperutil.EnhancedExactUnmarshalKey("chaincode.externalBuilders", &externalBuilders); err != nil {
+		logger.Panicf("could not load external builders config: %s", err)
+	}
+
+	// without a docker endpoint, user chaincode is only run by the external builders
+	var userCCProvider container.VMProvider
+	if len(externalBuilders) == 0 || viper.GetString("vm.endpoint") != "" {
+		dockerProvider := dockercontroller.NewProvider(
+			viper.GetString("peer.id"),
+			viper.GetString("peer.networkId"),
+			ops.Provider,
+		)
+		dockerVM := dockercontroller.NewDockerVM(
+			dockerProvider.PeerID,
+			dockerProvider.NetworkID,
+			dockerProvider.BuildMetrics,
+		)
+
+		err := ops.RegisterChecker("docker", dockerVM)
+		if err != nil {
+			logger.Panicf("failed to register docker health check: %s", err)
+		}
+		userCCProvider = dockerProvider
 
-	err := ops.RegisterChecker("docker", dockerVM)
+		if l, ok := lifecycleSCC.Functions.(*lifecycle.Lifecycle); ok {
+			l.ImageRemover = dockerVM
+		}
+		if viper.GetBool("vm.docker.imageGC.enabled") {
+			interval := viper.GetDuration("vm.docker.imageGC.interval")
+			if interval <= 0 {
+				logger.Panicf("invalid chaincode image collection interval: %s", interval)
+			}
+			collector := &dockercontroller.ImageCollector{
+				VM:        dockerVM,
+				Installed: chaincodeInstalled(packageProvider.Store),
+				Interval:  interval,
+			}
+			go collector.Run()
+		}
+	}
+
+	if len(externalBuilders) != 0 {
+		for _, b := range externalBuilders {
+			logger.Infof("Using external builder %s at %s", b.Name, b.Path)
+		}
+		workDir := filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "externalbuilds")
+		userCCProvider = externalbuilder.NewProvider(workDir, externalBuilders, ccConfig.StartupTimeout, userCCProvider)
+	}
+
+	servers, err := chaincodeServers(tlsEnabled)
+	if err != nil {
+		logger.Panicf("could not load chaincode servers config: %s", err)
+	}
+	ccConfig.ChaincodeServers = servers
+	resourceLimits, err := chaincode.ResourceLimitsFromViper()
 	if err != nil {
-		logger.Panicf("failed to register docker health check: %s", err)
+		logger.Panicf("could not load chaincode resource limits config: %s", err)
 	}
+	ccConfig.ResourceLimits = resourceLimits
 
 	chaincodeSupport := chaincode.NewChaincodeSupport(
-		chaincode.GlobalConfig(),
+		ccConfig,
 		ccEndpoint,
 		userRunsCC,
 		ca.CertBytes(),
@@ -704,7 +757,7 @@ func registerChaincodeSupport(
 		aclProvider,
 		container.NewVMController(
 			map[string]container.VMProvider{
-				dockercontroller.ContainerType: dockerProvider,
+				dockercontroller.ContainerType: userCCProvider,
 				inproccontroller.ContainerType: ipRegistry,
 			},
 		),
@@ -716,6 +769,16 @@ func registerChaincodeSupport(
 	ipRegistry.ChaincodeSupport = chaincodeSupport
 	ccp := chaincode.NewProvider(chaincodeSupport)
 
+	if l, ok := lifecycleSCC.Functions.(*lifecycle.Lifecycle); ok {
+		l.ChaincodeStopper = chaincodeSupport
+	}
+
+	if launcher, ok := chaincodeSupport.Launcher.(*chaincode.RuntimeLauncher); ok && launcher.Servers != nil {
+		if err := ops.RegisterChecker("chaincode_servers", launcher); err != nil {
+			logger.Panicf("failed to register chaincode servers health check: %s", err)
+		}
+	}
+
 	ccSrv := pb.ChaincodeSupportServer(chaincodeSupport)
 	if tlsEnabled {
 		ccSrv = authenticator.Wrap(ccSrv)
@@ -734,6 +797,113 @@ func registerChaincodeSupport(
 	return chaincodeSupport, ccp, sccp
 }
 
+// peerLedgers gives the lifecycle access to the ledgers
+// of the channels the peer has joined
+type peerLedgers struct{}
+
+func (peerLedgers) GetChannelsInfo() []*pb.ChannelInfo {
+	return peer.GetChannelsInfo()
+}
+
+func (peerLedgers) NewQueryExecutor(channelID string) (lifecycle.StateQueryExecutor, error) {
+	l := peer.GetLedger(channelID)
+	if l == nil {
+		return nil, errors.Errorf("ledger of channel '%s' not found", channelID)
+	}
+	return l.NewQueryExecutor()
+}
+
+// chaincodeInstalled returns whether a chaincode is installed on the peer,
+// either as an install package or as a legacy deployment spec
+func chaincodeInstalled(store persistence.StorePackageProvider) func(ccid ccintf.CCID) (bool, error) {
+	return func(ccid ccintf.CCID) (bool, error) {
+		_, err := store.RetrieveHash(ccid.Name, ccid.Version)
+		if err == nil {
+			return true, nil
+		}
+		if _, ok := err.(*persistence.CodePackageNotFoundErr); !ok {
+			return false, err
+		}
+
+		exists, err := ccprovider.ChaincodePackageExists(ccid.Name, ccid.Version)
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return exists, err
+	}
+}
+
+// chaincodeServerDialTimeout is how long the peer waits for a connection
+// to a chaincode server to be established
+const chaincodeServerDialTimeout = 10 * time.Second
+
+// chaincodeServerConfig is the configuration of chaincode run as a server
+type chaincodeServerConfig struct {
+	Name     string `mapstructure:"name" yaml:"name"`
+	Address  string `mapstructure:"address" yaml:"address"`
+	RootCert string `mapstructure:"rootCert" yaml:"rootCert"`
+}
+
+// chaincodeServers loads the chaincodes run as servers. If TLS is enabled,
+// the peer connects to them with its TLS client certificate, and verifies
+// their TLS server certificate with the configured root certificate.
+func chaincodeServers(tlsEnabled bool) (map[string]*chaincode.ChaincodeServerConfig, error) {
+	var configs []*chaincodeServerConfig
+	if err := viperutil.EnhancedExactUnmarshalKey("chaincode.servers", &configs); err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	secOpts := &comm.SecureOptions{UseTLS: tlsEnabled}
+	if tlsEnabled {
+		keyFile, certFile := "peer.tls.clientKey.file", "peer.tls.clientCert.file"
+		if viper.GetString(keyFile) == "" {
+			keyFile, certFile = "peer.tls.key.file", "peer.tls.cert.file"
+		}
+		key, err := ioutil.ReadFile(coreconfig.GetPath(keyFile))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client TLS key")
+		}
+		cert, err := ioutil.ReadFile(coreconfig.GetPath(certFile))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client TLS certificate")
+		}
+		secOpts.Key = key
+		secOpts.Certificate = cert
+		secOpts.RequireClientCert = true
+	}
+
+	servers := map[string]*chaincode.ChaincodeServerConfig{}
+	for _, c := range configs {
+		if c.Name == "" || c.Address == "" {
+			return nil, errors.New("chaincode servers must have a name and an address")
+		}
+		clientConfig := comm.ClientConfig{
+			SecOpts: secOpts,
+			KaOpts:  comm.DefaultKeepaliveOptions,
+			Timeout: chaincodeServerDialTimeout,
+		}
+		if tlsEnabled {
+			if c.RootCert == "" {
+				return nil, errors.Errorf("chaincode server %s must have a root certificate when TLS is enabled", c.Name)
+			}
+			rootCert, err := ioutil.ReadFile(coreconfig.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), c.RootCert))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to load root certificate of chaincode server %s", c.Name)
+			}
+			chaincodeSecOpts := *secOpts
+			chaincodeSecOpts.ServerRootCAs = [][]byte{rootCert}
+			clientConfig.SecOpts = &chaincodeSecOpts
+		}
+		logger.Infof("Chaincode %s runs as a server at %s", c.Name, c.Address)
+		servers[c.Name] = &chaincode.ChaincodeServerConfig{Address: c.Address, ClientConfig: clientConfig}
+	}
+
+	return servers, nil
+}
+
 // startChaincodeServer will finish chaincode related initialization, including:
 // 1) setup local chaincode install path
 // 2) create chaincode specific tls CA
@@ -760,10 +930,15 @@ func startChaincodeServer(
 	}
 
 	lifecycleSCC := &lifecycle.SCC{
-		Protobuf: &lifecycle.ProtobufImpl{},
+		OrgMSPID:            viper.GetString("peer.localMspId"),
+		ChannelConfigSource: peer.Default,
+		AppConfig:           peer.DefaultSupport,
+		PolicyChecker:       policyprovider.GetPolicyChecker(),
+		Protobuf:            &lifecycle.ProtobufImpl{},
 		Functions: &lifecycle.Lifecycle{
 			PackageParser:  ccPackageParser,
 			ChaincodeStore: ccStore,
+			ChannelStates:  &lifecycle.PeerLedgerShim{Peer: peerLedgers{}},
 		},
 	}
 
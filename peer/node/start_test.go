@@ -10,10 +10,14 @@ import (
 	"bytes"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric/common/viperutil"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/handlers/library"
 	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/peer/node/mock"
@@ -21,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 )
 
@@ -224,3 +229,41 @@ func TestResetLoop(t *testing.T) {
 	assert.False(t, resetFilter.reject)
 	assert.Equal(t, 4, peerLedger.GetBlockchainInfoCallCount())
 }
+
+func TestChaincodeInstalled(t *testing.T) {
+	installPath, err := ioutil.TempDir("", "chaincodeinstalled")
+	require.NoError(t, err)
+	defer os.RemoveAll(installPath)
+	ccprovider.SetChaincodesPath(installPath)
+
+	store := &persistence.Store{Path: installPath, ReadWriter: &persistence.FilesystemIO{}}
+	_, err = store.Save("mycc", "1.0", []byte("package"))
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(installPath, "legacycc.1.0"), []byte("cds"), 0600)
+	require.NoError(t, err)
+
+	installed := chaincodeInstalled(store)
+	tests := []struct {
+		ccid     ccintf.CCID
+		expected bool
+	}{
+		{ccintf.CCID{Name: "mycc", Version: "1.0"}, true},
+		{ccintf.CCID{Name: "legacycc", Version: "1.0"}, true},
+		{ccintf.CCID{Name: "mycc", Version: "0.9"}, false},
+	}
+	for _, tt := range tests {
+		ok, err := installed(tt.ccid)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, ok, "installation of %s", tt.ccid.GetName())
+	}
+
+	// the chaincode may be installed if the store cannot be read
+	store.Path = filepath.Join(installPath, "missing")
+	_, err = installed(ccintf.CCID{Name: "mycc", Version: "0.9"})
+	assert.Error(t, err)
+}
+
+func TestPeerLedgers(t *testing.T) {
+	_, err := peerLedgers{}.NewQueryExecutor("nonexistent-channel")
+	assert.EqualError(t, err, "ledger of channel 'nonexistent-channel' not found")
+}
@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func verifyLedgerCmd() *cobra.Command {
+	nodeVerifyLedgerCmd.ResetFlags()
+	flags := nodeVerifyLedgerCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel to verify. All the channels are verified if not specified.")
+	return nodeVerifyLedgerCmd
+}
+
+var nodeVerifyLedgerCmd = &cobra.Command{
+	Use:   "verify-ledger",
+	Short: "Verifies the integrity of the channel ledgers.",
+	Long:  `Verifies the integrity of the ledger of a channel, or of all the channels if no channel is specified. The hash chain of the blocks, the block index, the savepoints of the state and history databases, and the hashes of the private data are checked. When the command is executed, the peer must be offline. The report of the inconsistencies is printed in JSON format and the command fails if any of the ledgers is inconsistent.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var ledgerIDs []string
+		if channelID != common.UndefinedParamValue {
+			ledgerIDs = append(ledgerIDs, channelID)
+		}
+		cmd.SilenceUsage = true
+		reports, err := kvledger.VerifyKVLedgers(ledgerIDs...)
+		if err != nil {
+			return err
+		}
+		if reports == nil {
+			reports = []*kvledger.LedgerVerificationReport{}
+		}
+		reportBytes, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "error marshaling the verification report")
+		}
+		fmt.Println(string(reportBytes))
+		for _, report := range reports {
+			if !report.Consistent {
+				return errors.Errorf("the ledger of the channel [%s] is inconsistent", report.LedgerID)
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyLedgerCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "verifyledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Set("peer.fileSystemPath", "")
+
+	t.Run("when no channel exists", func(t *testing.T) {
+		cmd := verifyLedgerCmd()
+		cmd.SetArgs([]string{})
+		assert.NoError(t, cmd.Execute())
+	})
+
+	t.Run("when the specified channelID does not exist", func(t *testing.T) {
+		cmd := verifyLedgerCmd()
+		args := []string{"-c", "ch1"}
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		assert.EqualError(t, err, "ledger [ch1] does not exist")
+	})
+}
@@ -29,6 +29,7 @@ const (
 	Status_FORBIDDEN                Status = 403
 	Status_NOT_FOUND                Status = 404
 	Status_REQUEST_ENTITY_TOO_LARGE Status = 413
+	Status_TOO_MANY_REQUESTS        Status = 429
 	Status_INTERNAL_SERVER_ERROR    Status = 500
 	Status_NOT_IMPLEMENTED          Status = 501
 	Status_SERVICE_UNAVAILABLE      Status = 503
@@ -41,6 +42,7 @@ var Status_name = map[int32]string{
 	403: "FORBIDDEN",
 	404: "NOT_FOUND",
 	413: "REQUEST_ENTITY_TOO_LARGE",
+	429: "TOO_MANY_REQUESTS",
 	500: "INTERNAL_SERVER_ERROR",
 	501: "NOT_IMPLEMENTED",
 	503: "SERVICE_UNAVAILABLE",
@@ -52,6 +54,7 @@ var Status_value = map[string]int32{
 	"FORBIDDEN":                403,
 	"NOT_FOUND":                404,
 	"REQUEST_ENTITY_TOO_LARGE": 413,
+	"TOO_MANY_REQUESTS":        429,
 	"INTERNAL_SERVER_ERROR":    500,
 	"NOT_IMPLEMENTED":          501,
 	"SERVICE_UNAVAILABLE":      503,
@@ -61,7 +64,7 @@ func (x Status) String() string {
 	return proto.EnumName(Status_name, int32(x))
 }
 func (Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{0}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{0}
 }
 
 type HeaderType int32
@@ -105,7 +108,7 @@ func (x HeaderType) String() string {
 	return proto.EnumName(HeaderType_name, int32(x))
 }
 func (HeaderType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{1}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{1}
 }
 
 // This enum enlists indexes of the block metadata array
@@ -138,7 +141,7 @@ func (x BlockMetadataIndex) String() string {
 	return proto.EnumName(BlockMetadataIndex_name, int32(x))
 }
 func (BlockMetadataIndex) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{2}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{2}
 }
 
 // LastConfig is the encoded value for the Metadata message which is encoded in the LAST_CONFIGURATION block metadata index
@@ -153,7 +156,7 @@ func (m *LastConfig) Reset()         { *m = LastConfig{} }
 func (m *LastConfig) String() string { return proto.CompactTextString(m) }
 func (*LastConfig) ProtoMessage()    {}
 func (*LastConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{0}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{0}
 }
 func (m *LastConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LastConfig.Unmarshal(m, b)
@@ -193,7 +196,7 @@ func (m *Metadata) Reset()         { *m = Metadata{} }
 func (m *Metadata) String() string { return proto.CompactTextString(m) }
 func (*Metadata) ProtoMessage()    {}
 func (*Metadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{1}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{1}
 }
 func (m *Metadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Metadata.Unmarshal(m, b)
@@ -239,7 +242,7 @@ func (m *MetadataSignature) Reset()         { *m = MetadataSignature{} }
 func (m *MetadataSignature) String() string { return proto.CompactTextString(m) }
 func (*MetadataSignature) ProtoMessage()    {}
 func (*MetadataSignature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{2}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{2}
 }
 func (m *MetadataSignature) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MetadataSignature.Unmarshal(m, b)
@@ -285,7 +288,7 @@ func (m *Header) Reset()         { *m = Header{} }
 func (m *Header) String() string { return proto.CompactTextString(m) }
 func (*Header) ProtoMessage()    {}
 func (*Header) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{3}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{3}
 }
 func (m *Header) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Header.Unmarshal(m, b)
@@ -358,7 +361,7 @@ func (m *ChannelHeader) Reset()         { *m = ChannelHeader{} }
 func (m *ChannelHeader) String() string { return proto.CompactTextString(m) }
 func (*ChannelHeader) ProtoMessage()    {}
 func (*ChannelHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{4}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{4}
 }
 func (m *ChannelHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelHeader.Unmarshal(m, b)
@@ -448,7 +451,7 @@ func (m *SignatureHeader) Reset()         { *m = SignatureHeader{} }
 func (m *SignatureHeader) String() string { return proto.CompactTextString(m) }
 func (*SignatureHeader) ProtoMessage()    {}
 func (*SignatureHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{5}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{5}
 }
 func (m *SignatureHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignatureHeader.Unmarshal(m, b)
@@ -497,7 +500,7 @@ func (m *Payload) Reset()         { *m = Payload{} }
 func (m *Payload) String() string { return proto.CompactTextString(m) }
 func (*Payload) ProtoMessage()    {}
 func (*Payload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{6}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{6}
 }
 func (m *Payload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Payload.Unmarshal(m, b)
@@ -546,7 +549,7 @@ func (m *Envelope) Reset()         { *m = Envelope{} }
 func (m *Envelope) String() string { return proto.CompactTextString(m) }
 func (*Envelope) ProtoMessage()    {}
 func (*Envelope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{7}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{7}
 }
 func (m *Envelope) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Envelope.Unmarshal(m, b)
@@ -597,7 +600,7 @@ func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{8}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{8}
 }
 func (m *Block) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Block.Unmarshal(m, b)
@@ -654,7 +657,7 @@ func (m *BlockHeader) Reset()         { *m = BlockHeader{} }
 func (m *BlockHeader) String() string { return proto.CompactTextString(m) }
 func (*BlockHeader) ProtoMessage()    {}
 func (*BlockHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{9}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{9}
 }
 func (m *BlockHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockHeader.Unmarshal(m, b)
@@ -706,7 +709,7 @@ func (m *BlockData) Reset()         { *m = BlockData{} }
 func (m *BlockData) String() string { return proto.CompactTextString(m) }
 func (*BlockData) ProtoMessage()    {}
 func (*BlockData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{10}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{10}
 }
 func (m *BlockData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockData.Unmarshal(m, b)
@@ -744,7 +747,7 @@ func (m *BlockMetadata) Reset()         { *m = BlockMetadata{} }
 func (m *BlockMetadata) String() string { return proto.CompactTextString(m) }
 func (*BlockMetadata) ProtoMessage()    {}
 func (*BlockMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{11}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{11}
 }
 func (m *BlockMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockMetadata.Unmarshal(m, b)
@@ -784,7 +787,7 @@ func (m *OrdererBlockMetadata) Reset()         { *m = OrdererBlockMetadata{} }
 func (m *OrdererBlockMetadata) String() string { return proto.CompactTextString(m) }
 func (*OrdererBlockMetadata) ProtoMessage()    {}
 func (*OrdererBlockMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_72f685cee4d0b877, []int{12}
+	return fileDescriptor_common_26d8a3ebd92217bd, []int{12}
 }
 func (m *OrdererBlockMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_OrdererBlockMetadata.Unmarshal(m, b)
@@ -837,72 +840,73 @@ func init() {
 	proto.RegisterEnum("common.BlockMetadataIndex", BlockMetadataIndex_name, BlockMetadataIndex_value)
 }
 
-func init() { proto.RegisterFile("common/common.proto", fileDescriptor_common_72f685cee4d0b877) }
-
-var fileDescriptor_common_72f685cee4d0b877 = []byte{
-	// 1023 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xcf, 0x6f, 0xe3, 0x44,
-	0x14, 0xde, 0xc4, 0xf9, 0xf9, 0xb2, 0x69, 0xdd, 0x49, 0x97, 0x35, 0x85, 0xd5, 0x56, 0x81, 0x45,
-	0xa5, 0x15, 0xa9, 0xe8, 0x5e, 0xe0, 0xe8, 0xd8, 0xd3, 0xd6, 0x6a, 0x62, 0x87, 0xb1, 0xb3, 0x88,
-	0x05, 0x69, 0xe4, 0x26, 0xd3, 0x24, 0xc2, 0xb1, 0x23, 0x7b, 0x52, 0xb5, 0x5c, 0xb9, 0x23, 0x24,
-	0xb8, 0xf2, 0xbf, 0x70, 0x44, 0xfc, 0x3d, 0x20, 0xae, 0x68, 0x3c, 0xb6, 0x9b, 0x94, 0x95, 0x38,
-	0xc5, 0xdf, 0x9b, 0x6f, 0xde, 0xfb, 0xe6, 0x7d, 0x2f, 0x33, 0xd0, 0x99, 0x44, 0xcb, 0x65, 0x14,
-	0x9e, 0xca, 0x9f, 0xde, 0x2a, 0x8e, 0x78, 0x84, 0x6a, 0x12, 0x1d, 0xbc, 0x9c, 0x45, 0xd1, 0x2c,
-	0x60, 0xa7, 0x69, 0xf4, 0x7a, 0x7d, 0x73, 0xca, 0x17, 0x4b, 0x96, 0x70, 0x7f, 0xb9, 0x92, 0xc4,
-	0x6e, 0x17, 0x60, 0xe0, 0x27, 0xdc, 0x88, 0xc2, 0x9b, 0xc5, 0x0c, 0xed, 0x43, 0x75, 0x11, 0x4e,
-	0xd9, 0x9d, 0x56, 0x3a, 0x2c, 0x1d, 0x55, 0x88, 0x04, 0xdd, 0x6f, 0xa1, 0x31, 0x64, 0xdc, 0x9f,
-	0xfa, 0xdc, 0x17, 0x8c, 0x5b, 0x3f, 0x58, 0xb3, 0x94, 0xf1, 0x94, 0x48, 0x80, 0xbe, 0x04, 0x48,
-	0x16, 0xb3, 0xd0, 0xe7, 0xeb, 0x98, 0x25, 0x5a, 0xf9, 0x50, 0x39, 0x6a, 0x9d, 0xbd, 0xdf, 0xcb,
-	0x14, 0xe5, 0x7b, 0xdd, 0x9c, 0x41, 0x36, 0xc8, 0xdd, 0xef, 0x60, 0xef, 0x3f, 0x04, 0xf4, 0x29,
-	0xa8, 0x05, 0x85, 0xce, 0x99, 0x3f, 0x65, 0x71, 0x56, 0x70, 0xb7, 0x88, 0x5f, 0xa6, 0x61, 0xf4,
-	0x21, 0x34, 0x8b, 0x90, 0x56, 0x4e, 0x39, 0x0f, 0x81, 0xee, 0x5b, 0xa8, 0x65, 0xbc, 0x57, 0xb0,
-	0x33, 0x99, 0xfb, 0x61, 0xc8, 0x82, 0xed, 0x84, 0xed, 0x2c, 0x9a, 0xd1, 0xde, 0x55, 0xb9, 0xfc,
-	0xce, 0xca, 0xdd, 0x1f, 0xcb, 0xd0, 0x36, 0xb6, 0x36, 0x23, 0xa8, 0xf0, 0xfb, 0x95, 0xec, 0x4d,
-	0x95, 0xa4, 0xdf, 0x48, 0x83, 0xfa, 0x2d, 0x8b, 0x93, 0x45, 0x14, 0xa6, 0x79, 0xaa, 0x24, 0x87,
-	0xe8, 0x0b, 0x68, 0x16, 0x6e, 0x68, 0xca, 0x61, 0xe9, 0xa8, 0x75, 0x76, 0xd0, 0x93, 0x7e, 0xf5,
-	0x72, 0xbf, 0x7a, 0x5e, 0xce, 0x20, 0x0f, 0x64, 0xf4, 0x02, 0x20, 0x3f, 0xcb, 0x62, 0xaa, 0x55,
-	0x0e, 0x4b, 0x47, 0x4d, 0xd2, 0xcc, 0x22, 0xd6, 0x14, 0x75, 0xa0, 0xca, 0xef, 0xc4, 0x4a, 0x35,
-	0x5d, 0xa9, 0xf0, 0x3b, 0x6b, 0x2a, 0x8c, 0x63, 0xab, 0x68, 0x32, 0xd7, 0x6a, 0xd2, 0xda, 0x14,
-	0x88, 0xee, 0xb1, 0x3b, 0xce, 0xc2, 0x54, 0x5f, 0x5d, 0x76, 0xaf, 0x08, 0xa0, 0x2e, 0xb4, 0x79,
-	0x90, 0xd0, 0x09, 0x8b, 0x39, 0x9d, 0xfb, 0xc9, 0x5c, 0x6b, 0xa4, 0x8c, 0x16, 0x0f, 0x12, 0x83,
-	0xc5, 0xfc, 0xd2, 0x4f, 0xe6, 0x5d, 0x1d, 0x76, 0xdd, 0x47, 0x96, 0x68, 0x50, 0x9f, 0xc4, 0xcc,
-	0xe7, 0x51, 0xde, 0xe3, 0x1c, 0x0a, 0x11, 0x61, 0x14, 0x4e, 0x72, 0xa3, 0x24, 0xe8, 0x62, 0xa8,
-	0x8f, 0xfc, 0xfb, 0x20, 0xf2, 0xa7, 0xe8, 0x13, 0xa8, 0x6d, 0xb8, 0xd3, 0x3a, 0xdb, 0xc9, 0x87,
-	0x48, 0xa6, 0x26, 0xd9, 0xaa, 0xe8, 0xb4, 0x98, 0x98, 0x2c, 0x4f, 0xfa, 0xdd, 0xed, 0x43, 0x03,
-	0x87, 0xb7, 0x2c, 0x88, 0x64, 0xd7, 0x57, 0x32, 0x65, 0x2e, 0x21, 0x83, 0xff, 0x33, 0x2f, 0x3f,
-	0x95, 0xa0, 0xda, 0x0f, 0xa2, 0xc9, 0xf7, 0xe8, 0xe4, 0x91, 0x92, 0x4e, 0xae, 0x24, 0x5d, 0x7e,
-	0x24, 0xe7, 0xd5, 0x86, 0x9c, 0xd6, 0xd9, 0xde, 0x16, 0xd5, 0xf4, 0xb9, 0x2f, 0x15, 0xa2, 0xcf,
-	0xa1, 0xb1, 0xcc, 0x66, 0x3d, 0x33, 0xfc, 0xd9, 0x16, 0x35, 0xff, 0x23, 0x90, 0x82, 0xd6, 0x9d,
-	0x41, 0x6b, 0xa3, 0x20, 0x7a, 0x0f, 0x6a, 0xe1, 0x7a, 0x79, 0x9d, 0xa9, 0xaa, 0x90, 0x0c, 0xa1,
-	0x8f, 0xa0, 0xbd, 0x8a, 0xd9, 0xed, 0x22, 0x5a, 0x27, 0xd2, 0x29, 0x79, 0xb2, 0xa7, 0x79, 0x50,
-	0x58, 0x85, 0x3e, 0x80, 0xa6, 0xc8, 0x29, 0x09, 0x4a, 0x4a, 0x68, 0x88, 0x40, 0xea, 0xe3, 0x4b,
-	0x68, 0x16, 0x72, 0x8b, 0xf6, 0x96, 0x0e, 0x95, 0xa2, 0xbd, 0x27, 0xd0, 0xde, 0x12, 0x89, 0x0e,
-	0x36, 0x4e, 0x23, 0x89, 0x0f, 0xb2, 0x7f, 0x80, 0x7d, 0x27, 0x9e, 0xb2, 0x98, 0xc5, 0xdb, 0x7b,
-	0x5e, 0x43, 0x2b, 0xf0, 0x13, 0x4e, 0x27, 0xe9, 0x7d, 0x93, 0xb5, 0x16, 0xe5, 0x4d, 0x78, 0xb8,
-	0x89, 0x08, 0x04, 0x0f, 0xb7, 0xd2, 0x67, 0x80, 0x26, 0x51, 0x98, 0xb0, 0x90, 0xb3, 0x98, 0x16,
-	0x25, 0xe5, 0x09, 0xf7, 0x8a, 0x95, 0xbc, 0xc6, 0xf1, 0xef, 0x25, 0xa8, 0xb9, 0xdc, 0xe7, 0xeb,
-	0x04, 0xb5, 0xa0, 0x3e, 0xb6, 0xaf, 0x6c, 0xe7, 0x6b, 0x5b, 0x7d, 0x82, 0x9e, 0x42, 0xdd, 0x1d,
-	0x1b, 0x06, 0x76, 0x5d, 0xf5, 0x8f, 0x12, 0x52, 0xa1, 0xd5, 0xd7, 0x4d, 0x4a, 0xf0, 0x57, 0x63,
-	0xec, 0x7a, 0xea, 0xcf, 0x0a, 0xda, 0x81, 0xe6, 0xb9, 0x43, 0xfa, 0x96, 0x69, 0x62, 0x5b, 0xfd,
-	0x25, 0xc5, 0xb6, 0xe3, 0xd1, 0x73, 0x67, 0x6c, 0x9b, 0xea, 0xaf, 0x0a, 0x7a, 0x01, 0x5a, 0xc6,
-	0xa6, 0xd8, 0xf6, 0x2c, 0xef, 0x1b, 0xea, 0x39, 0x0e, 0x1d, 0xe8, 0xe4, 0x02, 0xab, 0xbf, 0x29,
-	0xe8, 0x00, 0x9e, 0x59, 0xb6, 0x87, 0x89, 0xad, 0x0f, 0xa8, 0x8b, 0xc9, 0x1b, 0x4c, 0x28, 0x26,
-	0xc4, 0x21, 0xea, 0x5f, 0x0a, 0xda, 0x87, 0x5d, 0x91, 0xca, 0x1a, 0x8e, 0x06, 0x78, 0x88, 0x6d,
-	0x0f, 0x9b, 0xea, 0xdf, 0x0a, 0xd2, 0xa0, 0x23, 0x88, 0x96, 0x81, 0xe9, 0xd8, 0xd6, 0xdf, 0xe8,
-	0xd6, 0x40, 0xef, 0x0f, 0xb0, 0xfa, 0x8f, 0x72, 0xfc, 0x67, 0x09, 0x40, 0x3a, 0xee, 0x89, 0x3b,
-	0xa4, 0x05, 0xf5, 0x21, 0x76, 0x5d, 0xfd, 0x02, 0xab, 0x4f, 0x10, 0x40, 0xcd, 0x70, 0xec, 0x73,
-	0xeb, 0x42, 0x2d, 0xa1, 0x3d, 0x68, 0xcb, 0x6f, 0x3a, 0x1e, 0x99, 0xba, 0x87, 0xd5, 0x32, 0xd2,
-	0x60, 0x1f, 0xdb, 0xa6, 0x43, 0x5c, 0x4c, 0xa8, 0x47, 0x74, 0xdb, 0xd5, 0x0d, 0xcf, 0x72, 0x6c,
-	0x55, 0x41, 0xcf, 0xa1, 0xe3, 0x10, 0x13, 0x93, 0x47, 0x0b, 0x15, 0xf4, 0x0c, 0xf6, 0x4c, 0x3c,
-	0xb0, 0x84, 0x62, 0x17, 0xe3, 0x2b, 0x6a, 0xd9, 0xe7, 0x8e, 0x5a, 0x15, 0x61, 0xe3, 0x52, 0xb7,
-	0x6c, 0xc3, 0x31, 0x31, 0x1d, 0xe9, 0xc6, 0x95, 0xa8, 0x5f, 0x13, 0x05, 0x46, 0x18, 0x13, 0xaa,
-	0x9b, 0x43, 0xcb, 0xa6, 0xce, 0x08, 0x13, 0x3d, 0xcd, 0xd3, 0x10, 0x1b, 0x3c, 0xe7, 0x0a, 0xdb,
-	0x5b, 0xe9, 0x9b, 0xc7, 0x01, 0xa0, 0xad, 0x21, 0xb0, 0xc4, 0xa3, 0x82, 0x76, 0x00, 0x5c, 0xeb,
-	0xc2, 0xd6, 0xbd, 0x31, 0xc1, 0xae, 0xfa, 0x04, 0xed, 0x42, 0x6b, 0xa0, 0xbb, 0x1e, 0x2d, 0xce,
-	0xf6, 0x1c, 0x3a, 0x1b, 0x79, 0x5c, 0x7a, 0x6e, 0x0d, 0x3c, 0x4c, 0xd4, 0xb2, 0xe8, 0x46, 0x76,
-	0x0e, 0x55, 0x11, 0xdb, 0x0c, 0x67, 0x38, 0xb4, 0x3c, 0x7a, 0xa9, 0xbb, 0x97, 0x6a, 0xa5, 0xef,
-	0xc2, 0xc7, 0x51, 0x3c, 0xeb, 0xcd, 0xef, 0x57, 0x2c, 0x0e, 0xd8, 0x74, 0xc6, 0xe2, 0xde, 0x8d,
-	0x7f, 0x1d, 0x2f, 0x26, 0xf2, 0x4e, 0x4d, 0xb2, 0x59, 0x7b, 0x7b, 0x32, 0x5b, 0xf0, 0xf9, 0xfa,
-	0x5a, 0xc0, 0xd3, 0x0d, 0xf2, 0xa9, 0x24, 0xcb, 0x07, 0x33, 0xc9, 0x1e, 0xd5, 0xeb, 0x5a, 0x0a,
-	0x5f, 0xff, 0x1b, 0x00, 0x00, 0xff, 0xff, 0xad, 0x85, 0xf1, 0xab, 0x6c, 0x07, 0x00, 0x00,
+func init() { proto.RegisterFile("common/common.proto", fileDescriptor_common_26d8a3ebd92217bd) }
+
+var fileDescriptor_common_26d8a3ebd92217bd = []byte{
+	// 1038 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xdf, 0x6e, 0xe3, 0xc4,
+	0x17, 0xde, 0xc4, 0xf9, 0x7b, 0xb2, 0x69, 0x9d, 0x49, 0xbb, 0xeb, 0x5f, 0x7f, 0xac, 0xb6, 0x32,
+	0x2c, 0x2a, 0xad, 0x48, 0x45, 0xf7, 0x06, 0x2e, 0x1d, 0x7b, 0xda, 0x5a, 0x4d, 0xec, 0x30, 0x76,
+	0x16, 0xed, 0x82, 0x64, 0xb9, 0xc9, 0x34, 0x89, 0x70, 0xec, 0xc8, 0x9e, 0x54, 0x2d, 0xb7, 0xdc,
+	0x23, 0x24, 0xb8, 0xe5, 0x11, 0x78, 0x0f, 0xc4, 0x13, 0xf0, 0x20, 0x20, 0x6e, 0xd1, 0x78, 0x6c,
+	0x37, 0x29, 0x2b, 0x71, 0x95, 0xf9, 0xce, 0x7c, 0x73, 0xce, 0x37, 0xe7, 0x3b, 0x19, 0x43, 0x77,
+	0x12, 0x2d, 0x97, 0x51, 0x78, 0x2a, 0x7e, 0x7a, 0xab, 0x38, 0x62, 0x11, 0xaa, 0x09, 0x74, 0xf0,
+	0x72, 0x16, 0x45, 0xb3, 0x80, 0x9e, 0xa6, 0xd1, 0xeb, 0xf5, 0xcd, 0x29, 0x5b, 0x2c, 0x69, 0xc2,
+	0xfc, 0xe5, 0x4a, 0x10, 0x55, 0x15, 0x60, 0xe0, 0x27, 0x4c, 0x8f, 0xc2, 0x9b, 0xc5, 0x0c, 0xed,
+	0x41, 0x75, 0x11, 0x4e, 0xe9, 0x9d, 0x52, 0x3a, 0x2c, 0x1d, 0x55, 0x88, 0x00, 0xea, 0xd7, 0xd0,
+	0x18, 0x52, 0xe6, 0x4f, 0x7d, 0xe6, 0x73, 0xc6, 0xad, 0x1f, 0xac, 0x69, 0xca, 0x78, 0x4a, 0x04,
+	0x40, 0x5f, 0x00, 0x24, 0x8b, 0x59, 0xe8, 0xb3, 0x75, 0x4c, 0x13, 0xa5, 0x7c, 0x28, 0x1d, 0xb5,
+	0xce, 0xfe, 0xd7, 0xcb, 0x14, 0xe5, 0x67, 0x9d, 0x9c, 0x41, 0x36, 0xc8, 0xea, 0x37, 0xd0, 0xf9,
+	0x17, 0x01, 0x7d, 0x02, 0x72, 0x41, 0xf1, 0xe6, 0xd4, 0x9f, 0xd2, 0x38, 0x2b, 0xb8, 0x5b, 0xc4,
+	0x2f, 0xd3, 0x30, 0xfa, 0x00, 0x9a, 0x45, 0x48, 0x29, 0xa7, 0x9c, 0x87, 0x80, 0xfa, 0x0e, 0x6a,
+	0x19, 0xef, 0x15, 0xec, 0x4c, 0xe6, 0x7e, 0x18, 0xd2, 0x60, 0x3b, 0x61, 0x3b, 0x8b, 0x66, 0xb4,
+	0xf7, 0x55, 0x2e, 0xbf, 0xb7, 0xb2, 0xfa, 0x7d, 0x19, 0xda, 0xfa, 0xd6, 0x61, 0x04, 0x15, 0x76,
+	0xbf, 0x12, 0xbd, 0xa9, 0x92, 0x74, 0x8d, 0x14, 0xa8, 0xdf, 0xd2, 0x38, 0x59, 0x44, 0x61, 0x9a,
+	0xa7, 0x4a, 0x72, 0x88, 0x3e, 0x87, 0x66, 0xe1, 0x86, 0x22, 0x1d, 0x96, 0x8e, 0x5a, 0x67, 0x07,
+	0x3d, 0xe1, 0x57, 0x2f, 0xf7, 0xab, 0xe7, 0xe6, 0x0c, 0xf2, 0x40, 0x46, 0x2f, 0x00, 0xf2, 0xbb,
+	0x2c, 0xa6, 0x4a, 0xe5, 0xb0, 0x74, 0xd4, 0x24, 0xcd, 0x2c, 0x62, 0x4e, 0x51, 0x17, 0xaa, 0xec,
+	0x8e, 0xef, 0x54, 0xd3, 0x9d, 0x0a, 0xbb, 0x33, 0xa7, 0xdc, 0x38, 0xba, 0x8a, 0x26, 0x73, 0xa5,
+	0x26, 0xac, 0x4d, 0x01, 0xef, 0x1e, 0xbd, 0x63, 0x34, 0x4c, 0xf5, 0xd5, 0x45, 0xf7, 0x8a, 0x00,
+	0x52, 0xa1, 0xcd, 0x82, 0xc4, 0x9b, 0xd0, 0x98, 0x79, 0x73, 0x3f, 0x99, 0x2b, 0x8d, 0x94, 0xd1,
+	0x62, 0x41, 0xa2, 0xd3, 0x98, 0x5d, 0xfa, 0xc9, 0x5c, 0xd5, 0x60, 0xd7, 0x79, 0x64, 0x89, 0x02,
+	0xf5, 0x49, 0x4c, 0x7d, 0x16, 0xe5, 0x3d, 0xce, 0x21, 0x17, 0x11, 0x46, 0xe1, 0x24, 0x37, 0x4a,
+	0x00, 0x15, 0x43, 0x7d, 0xe4, 0xdf, 0x07, 0x91, 0x3f, 0x45, 0x1f, 0x43, 0x6d, 0xc3, 0x9d, 0xd6,
+	0xd9, 0x4e, 0x3e, 0x44, 0x22, 0x35, 0xa9, 0xcd, 0x8b, 0x4e, 0xf3, 0x89, 0xc9, 0xf2, 0xa4, 0x6b,
+	0xb5, 0x0f, 0x0d, 0x1c, 0xde, 0xd2, 0x20, 0x12, 0x5d, 0x5f, 0x89, 0x94, 0xb9, 0x84, 0x0c, 0xfe,
+	0xc7, 0xbc, 0xfc, 0x50, 0x82, 0x6a, 0x3f, 0x88, 0x26, 0xdf, 0xa2, 0x93, 0x47, 0x4a, 0xba, 0xb9,
+	0x92, 0x74, 0xfb, 0x91, 0x9c, 0x57, 0x1b, 0x72, 0x5a, 0x67, 0x9d, 0x2d, 0xaa, 0xe1, 0x33, 0x5f,
+	0x28, 0x44, 0x9f, 0x41, 0x63, 0x99, 0xcd, 0x7a, 0x66, 0xf8, 0xfe, 0x16, 0x35, 0xff, 0x23, 0x90,
+	0x82, 0xa6, 0xce, 0xa0, 0xb5, 0x51, 0x10, 0x3d, 0x83, 0x5a, 0xb8, 0x5e, 0x5e, 0x67, 0xaa, 0x2a,
+	0x24, 0x43, 0xe8, 0x43, 0x68, 0xaf, 0x62, 0x7a, 0xbb, 0x88, 0xd6, 0x89, 0x70, 0x4a, 0xdc, 0xec,
+	0x69, 0x1e, 0xe4, 0x56, 0xa1, 0xff, 0x43, 0x93, 0xe7, 0x14, 0x04, 0x29, 0x25, 0x34, 0x78, 0x20,
+	0xf5, 0xf1, 0x25, 0x34, 0x0b, 0xb9, 0x45, 0x7b, 0x4b, 0x87, 0x52, 0xd1, 0xde, 0x13, 0x68, 0x6f,
+	0x89, 0x44, 0x07, 0x1b, 0xb7, 0x11, 0xc4, 0x07, 0xd9, 0xdf, 0xc1, 0x9e, 0x1d, 0x4f, 0x69, 0x4c,
+	0xe3, 0xed, 0x33, 0xaf, 0xa1, 0x15, 0xf8, 0x09, 0xf3, 0x26, 0xe9, 0x7b, 0x93, 0xb5, 0x16, 0xe5,
+	0x4d, 0x78, 0x78, 0x89, 0x08, 0x04, 0xc5, 0x1a, 0x7d, 0x0a, 0x68, 0x12, 0x85, 0x09, 0x0d, 0x19,
+	0x8d, 0xbd, 0xa2, 0xa4, 0xb8, 0x61, 0xa7, 0xd8, 0xc9, 0x6b, 0x1c, 0xff, 0x51, 0x82, 0x9a, 0xc3,
+	0x7c, 0xb6, 0x4e, 0x50, 0x0b, 0xea, 0x63, 0xeb, 0xca, 0xb2, 0xbf, 0xb2, 0xe4, 0x27, 0xe8, 0x29,
+	0xd4, 0x9d, 0xb1, 0xae, 0x63, 0xc7, 0x91, 0x7f, 0x2b, 0x21, 0x19, 0x5a, 0x7d, 0xcd, 0xf0, 0x08,
+	0xfe, 0x72, 0x8c, 0x1d, 0x57, 0xfe, 0x51, 0x42, 0x3b, 0xd0, 0x3c, 0xb7, 0x49, 0xdf, 0x34, 0x0c,
+	0x6c, 0xc9, 0x3f, 0xa5, 0xd8, 0xb2, 0x5d, 0xef, 0xdc, 0x1e, 0x5b, 0x86, 0xfc, 0xb3, 0x84, 0x5e,
+	0x80, 0x92, 0xb1, 0x3d, 0x6c, 0xb9, 0xa6, 0xfb, 0xd6, 0x73, 0x6d, 0xdb, 0x1b, 0x68, 0xe4, 0x02,
+	0xcb, 0xbf, 0x48, 0xe8, 0x19, 0x74, 0x38, 0x1e, 0x6a, 0xd6, 0xdb, 0x3c, 0xab, 0x23, 0xff, 0x2a,
+	0xa1, 0x03, 0xd8, 0x37, 0x2d, 0x17, 0x13, 0x4b, 0x1b, 0x78, 0x0e, 0x26, 0x6f, 0x30, 0xf1, 0x30,
+	0x21, 0x36, 0x91, 0xff, 0x94, 0xd0, 0x1e, 0xec, 0xf2, 0x12, 0xe6, 0x70, 0x34, 0xc0, 0x43, 0x6c,
+	0xb9, 0xd8, 0x90, 0xff, 0x92, 0x90, 0x02, 0x5d, 0x4e, 0x34, 0x75, 0xec, 0x8d, 0x2d, 0xed, 0x8d,
+	0x66, 0x0e, 0xb4, 0xfe, 0x00, 0xcb, 0x7f, 0x4b, 0xc7, 0xbf, 0x97, 0x00, 0xc4, 0x24, 0xb8, 0xfc,
+	0x6d, 0x69, 0x41, 0x7d, 0x88, 0x1d, 0x47, 0xbb, 0xc0, 0xf2, 0x13, 0x04, 0x50, 0xd3, 0x6d, 0xeb,
+	0xdc, 0xbc, 0x90, 0x4b, 0xa8, 0x03, 0x6d, 0xb1, 0xf6, 0xc6, 0x23, 0x43, 0x73, 0xb1, 0x5c, 0x46,
+	0x0a, 0xec, 0x61, 0xcb, 0xb0, 0x89, 0x83, 0x89, 0xe7, 0x12, 0xcd, 0x72, 0x34, 0xdd, 0x35, 0x6d,
+	0x4b, 0x96, 0xd0, 0x73, 0xe8, 0xda, 0xc4, 0xc0, 0xe4, 0xd1, 0x46, 0x05, 0xed, 0x43, 0xc7, 0xc0,
+	0x03, 0x93, 0x2b, 0x76, 0x30, 0xbe, 0xf2, 0x4c, 0xeb, 0xdc, 0x96, 0xab, 0x3c, 0xac, 0x5f, 0x6a,
+	0xa6, 0xa5, 0xdb, 0x06, 0xf6, 0x46, 0x9a, 0x7e, 0xc5, 0xeb, 0xd7, 0x78, 0x81, 0x11, 0xc6, 0xc4,
+	0xd3, 0x8c, 0xa1, 0x69, 0x79, 0xf6, 0x08, 0x13, 0x2d, 0xcd, 0xd3, 0xe0, 0x07, 0x5c, 0xfb, 0x0a,
+	0x5b, 0x5b, 0xe9, 0x9b, 0xc7, 0x01, 0xa0, 0xad, 0xe1, 0x30, 0xf9, 0xc7, 0x06, 0xed, 0x00, 0x38,
+	0xe6, 0x85, 0xa5, 0xb9, 0x63, 0x82, 0x1d, 0xf9, 0x09, 0xda, 0x85, 0xd6, 0x40, 0x73, 0x5c, 0xaf,
+	0xb8, 0xdb, 0x73, 0xe8, 0x6e, 0xe4, 0x71, 0xbc, 0x73, 0x73, 0xe0, 0x62, 0x22, 0x97, 0x79, 0x37,
+	0xb2, 0x7b, 0xc8, 0x12, 0x3f, 0xa6, 0xdb, 0xc3, 0xa1, 0xe9, 0x7a, 0x97, 0x9a, 0x73, 0x29, 0x57,
+	0xfa, 0x0e, 0x7c, 0x14, 0xc5, 0xb3, 0xde, 0xfc, 0x7e, 0x45, 0xe3, 0x80, 0x4e, 0x67, 0x34, 0xee,
+	0xdd, 0xf8, 0xd7, 0xf1, 0x62, 0x22, 0xde, 0xda, 0x24, 0x9b, 0xc1, 0x77, 0x27, 0xb3, 0x05, 0x9b,
+	0xaf, 0xaf, 0x39, 0x3c, 0xdd, 0x20, 0x9f, 0x0a, 0xb2, 0xf8, 0x90, 0x26, 0xd9, 0xc7, 0xf6, 0xba,
+	0x96, 0xc2, 0xd7, 0xff, 0x0c, 0x00, 0x1d, 0x43, 0xad, 0xca, 0x84, 0x07, 0x00, 0x00,
 }
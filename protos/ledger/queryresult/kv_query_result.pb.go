@@ -7,6 +7,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import timestamp "github.com/golang/protobuf/ptypes/timestamp"
+import peer "github.com/hyperledger/fabric/protos/peer"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -33,7 +34,7 @@ func (m *KV) Reset()         { *m = KV{} }
 func (m *KV) String() string { return proto.CompactTextString(m) }
 func (*KV) ProtoMessage()    {}
 func (*KV) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_query_result_8f59f813f4fe5e5a, []int{0}
+	return fileDescriptor_kv_query_result_202a1b44114845db, []int{0}
 }
 func (m *KV) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KV.Unmarshal(m, b)
@@ -90,7 +91,7 @@ func (m *KeyModification) Reset()         { *m = KeyModification{} }
 func (m *KeyModification) String() string { return proto.CompactTextString(m) }
 func (*KeyModification) ProtoMessage()    {}
 func (*KeyModification) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_query_result_8f59f813f4fe5e5a, []int{1}
+	return fileDescriptor_kv_query_result_202a1b44114845db, []int{1}
 }
 func (m *KeyModification) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KeyModification.Unmarshal(m, b)
@@ -138,33 +139,187 @@ func (m *KeyModification) GetIsDelete() bool {
 	return false
 }
 
+type KeyAccess struct {
+	TxId                 string                `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Timestamp            *timestamp.Timestamp  `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	CreatorMspId         string                `protobuf:"bytes,3,opt,name=creator_msp_id,json=creatorMspId,proto3" json:"creator_msp_id,omitempty"`
+	ValidationCode       peer.TxValidationCode `protobuf:"varint,4,opt,name=validation_code,json=validationCode,proto3,enum=protos.TxValidationCode" json:"validation_code,omitempty"`
+	Read                 bool                  `protobuf:"varint,5,opt,name=read,proto3" json:"read,omitempty"`
+	Write                bool                  `protobuf:"varint,6,opt,name=write,proto3" json:"write,omitempty"`
+	BlockNum             uint64                `protobuf:"varint,7,opt,name=block_num,json=blockNum,proto3" json:"block_num,omitempty"`
+	TxNum                uint64                `protobuf:"varint,8,opt,name=tx_num,json=txNum,proto3" json:"tx_num,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *KeyAccess) Reset()         { *m = KeyAccess{} }
+func (m *KeyAccess) String() string { return proto.CompactTextString(m) }
+func (*KeyAccess) ProtoMessage()    {}
+func (*KeyAccess) Descriptor() ([]byte, []int) {
+	return fileDescriptor_kv_query_result_202a1b44114845db, []int{2}
+}
+func (m *KeyAccess) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyAccess.Unmarshal(m, b)
+}
+func (m *KeyAccess) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyAccess.Marshal(b, m, deterministic)
+}
+func (dst *KeyAccess) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyAccess.Merge(dst, src)
+}
+func (m *KeyAccess) XXX_Size() int {
+	return xxx_messageInfo_KeyAccess.Size(m)
+}
+func (m *KeyAccess) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyAccess.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyAccess proto.InternalMessageInfo
+
+func (m *KeyAccess) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *KeyAccess) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *KeyAccess) GetCreatorMspId() string {
+	if m != nil {
+		return m.CreatorMspId
+	}
+	return ""
+}
+
+func (m *KeyAccess) GetValidationCode() peer.TxValidationCode {
+	if m != nil {
+		return m.ValidationCode
+	}
+	return peer.TxValidationCode_VALID
+}
+
+func (m *KeyAccess) GetRead() bool {
+	if m != nil {
+		return m.Read
+	}
+	return false
+}
+
+func (m *KeyAccess) GetWrite() bool {
+	if m != nil {
+		return m.Write
+	}
+	return false
+}
+
+func (m *KeyAccess) GetBlockNum() uint64 {
+	if m != nil {
+		return m.BlockNum
+	}
+	return 0
+}
+
+func (m *KeyAccess) GetTxNum() uint64 {
+	if m != nil {
+		return m.TxNum
+	}
+	return 0
+}
+
+type KeyAccessHistory struct {
+	Accesses             []*KeyAccess `protobuf:"bytes,1,rep,name=accesses,proto3" json:"accesses,omitempty"`
+	Bookmark             string       `protobuf:"bytes,2,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *KeyAccessHistory) Reset()         { *m = KeyAccessHistory{} }
+func (m *KeyAccessHistory) String() string { return proto.CompactTextString(m) }
+func (*KeyAccessHistory) ProtoMessage()    {}
+func (*KeyAccessHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_kv_query_result_202a1b44114845db, []int{3}
+}
+func (m *KeyAccessHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyAccessHistory.Unmarshal(m, b)
+}
+func (m *KeyAccessHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyAccessHistory.Marshal(b, m, deterministic)
+}
+func (dst *KeyAccessHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyAccessHistory.Merge(dst, src)
+}
+func (m *KeyAccessHistory) XXX_Size() int {
+	return xxx_messageInfo_KeyAccessHistory.Size(m)
+}
+func (m *KeyAccessHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyAccessHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyAccessHistory proto.InternalMessageInfo
+
+func (m *KeyAccessHistory) GetAccesses() []*KeyAccess {
+	if m != nil {
+		return m.Accesses
+	}
+	return nil
+}
+
+func (m *KeyAccessHistory) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*KV)(nil), "queryresult.KV")
 	proto.RegisterType((*KeyModification)(nil), "queryresult.KeyModification")
+	proto.RegisterType((*KeyAccess)(nil), "queryresult.KeyAccess")
+	proto.RegisterType((*KeyAccessHistory)(nil), "queryresult.KeyAccessHistory")
 }
 
 func init() {
-	proto.RegisterFile("ledger/queryresult/kv_query_result.proto", fileDescriptor_kv_query_result_8f59f813f4fe5e5a)
-}
-
-var fileDescriptor_kv_query_result_8f59f813f4fe5e5a = []byte{
-	// 286 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x51, 0x4f, 0x4b, 0xc3, 0x30,
-	0x1c, 0xa5, 0xdd, 0x26, 0x6b, 0x26, 0x28, 0xd1, 0x43, 0x99, 0x82, 0x65, 0xa7, 0x9e, 0x12, 0xd1,
-	0x83, 0x9e, 0xc5, 0x8b, 0x0e, 0x2f, 0x45, 0x3c, 0x78, 0x29, 0x69, 0xfb, 0x6b, 0x17, 0xda, 0x2e,
-	0x35, 0x7f, 0xc6, 0xfa, 0x39, 0xfc, 0xc2, 0x62, 0xb2, 0xd9, 0x82, 0xb7, 0xbc, 0xf7, 0x7b, 0xef,
-	0xf1, 0x78, 0x41, 0x71, 0x03, 0x45, 0x05, 0x92, 0x7e, 0x19, 0x90, 0xbd, 0x04, 0x65, 0x1a, 0x4d,
-	0xeb, 0x5d, 0x6a, 0x61, 0xea, 0x30, 0xe9, 0xa4, 0xd0, 0x02, 0x2f, 0x46, 0x92, 0xe5, 0x4d, 0x25,
-	0x44, 0xd5, 0x00, 0xb5, 0xa7, 0xcc, 0x94, 0x54, 0xf3, 0x16, 0x94, 0x66, 0x6d, 0xe7, 0xd4, 0xab,
-	0x57, 0xe4, 0xaf, 0x3f, 0xf0, 0x35, 0x0a, 0xb6, 0xac, 0x05, 0xd5, 0xb1, 0x1c, 0x42, 0x2f, 0xf2,
-	0xe2, 0x20, 0x19, 0x08, 0x7c, 0x8e, 0x26, 0x35, 0xf4, 0xa1, 0x6f, 0xf9, 0xdf, 0x27, 0xbe, 0x44,
-	0xb3, 0x1d, 0x6b, 0x0c, 0x84, 0x93, 0xc8, 0x8b, 0x4f, 0x13, 0x07, 0x56, 0xdf, 0x1e, 0x3a, 0x5b,
-	0x43, 0xff, 0x26, 0x0a, 0x5e, 0xf2, 0x9c, 0x69, 0x2e, 0xb6, 0xf8, 0x02, 0xcd, 0xf4, 0x3e, 0xe5,
-	0xc5, 0x21, 0x75, 0xaa, 0xf7, 0x2f, 0xc5, 0x60, 0xf7, 0x47, 0x76, 0xfc, 0x88, 0x82, 0xbf, 0x76,
-	0x36, 0x78, 0x71, 0xb7, 0x24, 0xae, 0x3f, 0x39, 0xf6, 0x27, 0xef, 0x47, 0x45, 0x32, 0x88, 0xf1,
-	0x15, 0x0a, 0xb8, 0x4a, 0x0b, 0x68, 0x40, 0x43, 0x38, 0x8d, 0xbc, 0x78, 0x9e, 0xcc, 0xb9, 0x7a,
-	0xb6, 0xf8, 0xa9, 0x46, 0xb7, 0x42, 0x56, 0x64, 0xd3, 0x77, 0x20, 0xdd, 0x88, 0xa4, 0x64, 0x99,
-	0xe4, 0xb9, 0x0b, 0x55, 0xe4, 0x40, 0x8e, 0x66, 0xfb, 0x7c, 0xa8, 0xb8, 0xde, 0x98, 0x8c, 0xe4,
-	0xa2, 0xa5, 0x23, 0x23, 0x75, 0x46, 0xb7, 0xa6, 0xa2, 0xff, 0xbf, 0x24, 0x3b, 0xb1, 0xa7, 0xfb,
-	0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa2, 0xb7, 0x3e, 0x86, 0xaf, 0x01, 0x00, 0x00,
+	proto.RegisterFile("ledger/queryresult/kv_query_result.proto", fileDescriptor_kv_query_result_202a1b44114845db)
+}
+
+var fileDescriptor_kv_query_result_202a1b44114845db = []byte{
+	// 475 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x4d, 0x6f, 0xd3, 0x30,
+	0x18, 0x56, 0xfa, 0x31, 0x52, 0x77, 0xea, 0x26, 0x03, 0x53, 0x54, 0x90, 0xa8, 0x2a, 0x0e, 0x39,
+	0x39, 0xa8, 0x1c, 0xe0, 0x3a, 0xe0, 0xc0, 0xa8, 0xb6, 0x43, 0x34, 0xed, 0xc0, 0x25, 0x72, 0xec,
+	0xb7, 0x9d, 0x95, 0xa4, 0x0e, 0xb6, 0x53, 0x92, 0xdf, 0xc1, 0x2f, 0xe0, 0x9f, 0xa2, 0xd8, 0x6d,
+	0x9a, 0x09, 0x2e, 0xdc, 0xfc, 0x3c, 0xef, 0xf3, 0x7e, 0x3d, 0x7a, 0x8d, 0xc2, 0x1c, 0xf8, 0x16,
+	0x54, 0xf4, 0xa3, 0x02, 0xd5, 0x28, 0xd0, 0x55, 0x6e, 0xa2, 0x6c, 0x9f, 0x58, 0x98, 0x38, 0x4c,
+	0x4a, 0x25, 0x8d, 0xc4, 0xd3, 0x9e, 0x64, 0xfe, 0x66, 0x2b, 0xe5, 0x36, 0x87, 0xc8, 0x86, 0xd2,
+	0x6a, 0x13, 0x19, 0x51, 0x80, 0x36, 0xb4, 0x28, 0x9d, 0x7a, 0x7e, 0x55, 0x02, 0xa8, 0xc8, 0x28,
+	0xba, 0xd3, 0x94, 0x19, 0x21, 0x77, 0x8e, 0x5f, 0x7e, 0x43, 0x83, 0xf5, 0x03, 0x7e, 0x8d, 0x26,
+	0x3b, 0x5a, 0x80, 0x2e, 0x29, 0x83, 0xc0, 0x5b, 0x78, 0xe1, 0x24, 0x3e, 0x11, 0xf8, 0x12, 0x0d,
+	0x33, 0x68, 0x82, 0x81, 0xe5, 0xdb, 0x27, 0x7e, 0x81, 0xc6, 0x7b, 0x9a, 0x57, 0x10, 0x0c, 0x17,
+	0x5e, 0x78, 0x1e, 0x3b, 0xb0, 0xfc, 0xe5, 0xa1, 0x8b, 0x35, 0x34, 0xb7, 0x92, 0x8b, 0x8d, 0x60,
+	0xb4, 0xed, 0x82, 0x9f, 0xa3, 0xb1, 0xa9, 0x13, 0xc1, 0x0f, 0x55, 0x47, 0xa6, 0xbe, 0xe1, 0xa7,
+	0xf4, 0x41, 0x2f, 0x1d, 0x7f, 0x44, 0x93, 0x6e, 0x6a, 0x5b, 0x78, 0xba, 0x9a, 0x13, 0xb7, 0x17,
+	0x39, 0xee, 0x45, 0xee, 0x8f, 0x8a, 0xf8, 0x24, 0xc6, 0xaf, 0xd0, 0x44, 0xe8, 0x84, 0x43, 0x0e,
+	0x06, 0x82, 0xd1, 0xc2, 0x0b, 0xfd, 0xd8, 0x17, 0xfa, 0x8b, 0xc5, 0xcb, 0xdf, 0x03, 0x34, 0x59,
+	0x43, 0x73, 0xcd, 0x18, 0x68, 0xfd, 0xef, 0x79, 0x9e, 0x74, 0x1e, 0xfc, 0x4f, 0xe7, 0xb7, 0x68,
+	0xc6, 0x14, 0x50, 0x23, 0x55, 0x52, 0xe8, 0xb2, 0xad, 0x3b, 0xb4, 0x75, 0xcf, 0x0f, 0xec, 0xad,
+	0x2e, 0x6f, 0x38, 0xbe, 0x46, 0x17, 0x7b, 0x9a, 0x0b, 0x6e, 0x2d, 0x49, 0x98, 0xe4, 0x6e, 0xca,
+	0xd9, 0x2a, 0x70, 0xe5, 0x35, 0xb9, 0xaf, 0x1f, 0x3a, 0xc1, 0x67, 0xc9, 0x21, 0x9e, 0xed, 0x9f,
+	0x60, 0x8c, 0xd1, 0x48, 0x01, 0xe5, 0xc1, 0xd8, 0x6e, 0x67, 0xdf, 0xad, 0x8d, 0x3f, 0x95, 0x30,
+	0x10, 0x9c, 0x59, 0xd2, 0x81, 0xd6, 0x8c, 0x34, 0x97, 0x2c, 0x4b, 0x76, 0x55, 0x11, 0x3c, 0x5b,
+	0x78, 0xe1, 0x28, 0xf6, 0x2d, 0x71, 0x57, 0x15, 0xf8, 0x25, 0x3a, 0x33, 0xb5, 0x8d, 0xf8, 0x36,
+	0x32, 0x36, 0xf5, 0x5d, 0x55, 0x2c, 0x53, 0x74, 0xd9, 0x59, 0xf4, 0x55, 0x68, 0x23, 0x55, 0x83,
+	0x57, 0xc8, 0xa7, 0x96, 0x00, 0x1d, 0x78, 0x8b, 0x61, 0x38, 0x5d, 0x5d, 0x91, 0xde, 0xc9, 0x91,
+	0x2e, 0x21, 0xee, 0x74, 0x78, 0x8e, 0xfc, 0x54, 0xca, 0xac, 0xa0, 0x2a, 0x3b, 0x9c, 0x4b, 0x87,
+	0x3f, 0x65, 0xe8, 0x9d, 0x54, 0x5b, 0xf2, 0xd8, 0x94, 0xa0, 0xdc, 0x91, 0x93, 0x0d, 0x4d, 0x95,
+	0x60, 0x47, 0x0f, 0x0e, 0x64, 0xaf, 0xc7, 0xf7, 0x0f, 0x5b, 0x61, 0x1e, 0xab, 0x94, 0x30, 0x59,
+	0x44, 0xbd, 0xc4, 0xc8, 0x25, 0xba, 0x6b, 0xd7, 0xd1, 0xdf, 0x5f, 0x26, 0x3d, 0xb3, 0xa1, 0xf7,
+	0x7f, 0x06, 0x00, 0xa0, 0xac, 0x7c, 0x18, 0x4f, 0x03, 0x00, 0x00,
 }
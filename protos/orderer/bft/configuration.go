@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/orderer"
+)
+
+// TypeKey is the string with which this consensus implementation is identified across Fabric.
+const TypeKey = "bft"
+
+func init() {
+	orderer.ConsensusTypeMetadataMap[TypeKey] = ConsensusTypeMetadataFactory{}
+}
+
+// ConsensusTypeMetadataFactory allows this implementation's proto messages to register
+// their type with the orderer's proto messages. This is needed for protolator to work.
+type ConsensusTypeMetadataFactory struct{}
+
+// NewMessage implements the Orderer.ConsensusTypeMetadataFactory interface.
+func (dogf ConsensusTypeMetadataFactory) NewMessage() proto.Message {
+	return &ConfigMetadata{}
+}
+
+// Marshal serializes this implementation's proto messages. It is called by the encoder package
+// during the creation of the Orderer ConfigGroup.
+func Marshal(md *ConfigMetadata) ([]byte, error) {
+	copyMd := proto.Clone(md).(*ConfigMetadata)
+	for _, c := range copyMd.Consenters {
+		// Expect the user to set the config value for client/server certs and the
+		// signing identity to the path where they are persisted locally, then load
+		// these files to memory.
+		clientCert, err := ioutil.ReadFile(string(c.GetClientTlsCert()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client cert for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.ClientTlsCert = clientCert
+
+		serverCert, err := ioutil.ReadFile(string(c.GetServerTlsCert()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load server cert for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.ServerTlsCert = serverCert
+
+		identity, err := ioutil.ReadFile(string(c.GetIdentity()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load identity for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.Identity = identity
+	}
+	return proto.Marshal(copyMd)
+}
+
+// QuorumSize returns the number of consenters out of n that have to agree
+// on a block, which tolerates (n-1)/3 byzantine consenters.
+func QuorumSize(n int) int {
+	f := (n - 1) / 3
+	return (n+f)/2 + 1
+}
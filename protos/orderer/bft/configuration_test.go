@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bft-marshal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	md := &bft.ConfigMetadata{Options: &bft.Options{RequestTimeout: "10s", ViewChangeTimeout: "20s"}}
+	for i := 1; i <= 4; i++ {
+		c := &bft.Consenter{Host: fmt.Sprintf("node-%d.example.com", i), Port: 7050, MspId: "OrdererMSP"}
+		for _, file := range []struct {
+			name  string
+			field *[]byte
+		}{
+			{name: "tls-client", field: &c.ClientTlsCert},
+			{name: "tls-server", field: &c.ServerTlsCert},
+			{name: "identity", field: &c.Identity},
+		} {
+			path := filepath.Join(dir, fmt.Sprintf("%s-%d.pem", file.name, i))
+			require.NoError(t, ioutil.WriteFile(path, []byte(path+" contents"), 0644))
+			*file.field = []byte(path)
+		}
+		md.Consenters = append(md.Consenters, c)
+	}
+
+	packed, err := bft.Marshal(md)
+	require.NoError(t, err, "marshalling should succeed")
+
+	packed, err = bft.Marshal(md)
+	require.NoError(t, err, "marshalling should succeed a second time because we did not mutate ourselves")
+
+	unpacked := &bft.ConfigMetadata{}
+	require.NoError(t, proto.Unmarshal(packed, unpacked), "unmarshalling should succeed")
+	require.Len(t, unpacked.Consenters, 4)
+	for i, c := range unpacked.Consenters {
+		assert.Equal(t, string(md.Consenters[i].ClientTlsCert)+" contents", string(c.ClientTlsCert))
+		assert.Equal(t, string(md.Consenters[i].ServerTlsCert)+" contents", string(c.ServerTlsCert))
+		assert.Equal(t, string(md.Consenters[i].Identity)+" contents", string(c.Identity))
+		assert.Equal(t, "OrdererMSP", c.MspId)
+	}
+	assert.True(t, proto.Equal(md.Options, unpacked.Options))
+
+	md.Consenters[0].Identity = []byte(filepath.Join(dir, "missing.pem"))
+	_, err = bft.Marshal(md)
+	assert.Contains(t, fmt.Sprint(err), "cannot load identity for consenter node-1.example.com:7050")
+}
+
+func TestQuorumSize(t *testing.T) {
+	for n, q := range map[int]int{1: 1, 2: 2, 3: 2, 4: 3, 5: 4, 6: 4, 7: 5, 10: 7} {
+		assert.Equal(t, q, bft.QuorumSize(n), "quorum of %d consenters", n)
+	}
+}
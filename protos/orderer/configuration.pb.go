@@ -41,7 +41,30 @@ func (x ConsensusType_State) String() string {
 	return proto.EnumName(ConsensusType_State_name, int32(x))
 }
 func (ConsensusType_State) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{0, 0}
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{0, 0}
+}
+
+type BatchCutting_Mode int32
+
+const (
+	BatchCutting_STATIC   BatchCutting_Mode = 0
+	BatchCutting_ADAPTIVE BatchCutting_Mode = 1
+)
+
+var BatchCutting_Mode_name = map[int32]string{
+	0: "STATIC",
+	1: "ADAPTIVE",
+}
+var BatchCutting_Mode_value = map[string]int32{
+	"STATIC":   0,
+	"ADAPTIVE": 1,
+}
+
+func (x BatchCutting_Mode) String() string {
+	return proto.EnumName(BatchCutting_Mode_name, int32(x))
+}
+func (BatchCutting_Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{3, 0}
 }
 
 type ConsensusType struct {
@@ -60,7 +83,7 @@ func (m *ConsensusType) Reset()         { *m = ConsensusType{} }
 func (m *ConsensusType) String() string { return proto.CompactTextString(m) }
 func (*ConsensusType) ProtoMessage()    {}
 func (*ConsensusType) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{0}
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{0}
 }
 func (m *ConsensusType) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConsensusType.Unmarshal(m, b)
@@ -120,7 +143,7 @@ func (m *BatchSize) Reset()         { *m = BatchSize{} }
 func (m *BatchSize) String() string { return proto.CompactTextString(m) }
 func (*BatchSize) ProtoMessage()    {}
 func (*BatchSize) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{1}
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{1}
 }
 func (m *BatchSize) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchSize.Unmarshal(m, b)
@@ -174,7 +197,7 @@ func (m *BatchTimeout) Reset()         { *m = BatchTimeout{} }
 func (m *BatchTimeout) String() string { return proto.CompactTextString(m) }
 func (*BatchTimeout) ProtoMessage()    {}
 func (*BatchTimeout) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{2}
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{2}
 }
 func (m *BatchTimeout) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchTimeout.Unmarshal(m, b)
@@ -201,6 +224,60 @@ func (m *BatchTimeout) GetTimeout() string {
 	return ""
 }
 
+// BatchCutting selects how the ordering service cuts blocks.  In the STATIC
+// mode, which is the default, blocks are cut as specified by BatchSize and
+// BatchTimeout.  In the ADAPTIVE mode, the batch timeout and the maximum
+// message count of a block are tuned from the observed arrival rate of
+// messages, so that messages are ordered within the target latency, and
+// BatchSize and BatchTimeout only bound them.
+type BatchCutting struct {
+	Mode BatchCutting_Mode `protobuf:"varint,1,opt,name=mode,proto3,enum=orderer.BatchCutting_Mode" json:"mode,omitempty"`
+	// Any duration string parseable by ParseDuration():
+	// https://golang.org/pkg/time/#ParseDuration
+	TargetLatency        string   `protobuf:"bytes,2,opt,name=target_latency,json=targetLatency,proto3" json:"target_latency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchCutting) Reset()         { *m = BatchCutting{} }
+func (m *BatchCutting) String() string { return proto.CompactTextString(m) }
+func (*BatchCutting) ProtoMessage()    {}
+func (*BatchCutting) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{3}
+}
+func (m *BatchCutting) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCutting.Unmarshal(m, b)
+}
+func (m *BatchCutting) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCutting.Marshal(b, m, deterministic)
+}
+func (dst *BatchCutting) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCutting.Merge(dst, src)
+}
+func (m *BatchCutting) XXX_Size() int {
+	return xxx_messageInfo_BatchCutting.Size(m)
+}
+func (m *BatchCutting) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCutting.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCutting proto.InternalMessageInfo
+
+func (m *BatchCutting) GetMode() BatchCutting_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return BatchCutting_STATIC
+}
+
+func (m *BatchCutting) GetTargetLatency() string {
+	if m != nil {
+		return m.TargetLatency
+	}
+	return ""
+}
+
 // Carries a list of bootstrap brokers, i.e. this is not the exclusive set of
 // brokers an ordering service
 type KafkaBrokers struct {
@@ -216,7 +293,7 @@ func (m *KafkaBrokers) Reset()         { *m = KafkaBrokers{} }
 func (m *KafkaBrokers) String() string { return proto.CompactTextString(m) }
 func (*KafkaBrokers) ProtoMessage()    {}
 func (*KafkaBrokers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{3}
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{4}
 }
 func (m *KafkaBrokers) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KafkaBrokers.Unmarshal(m, b)
@@ -255,7 +332,7 @@ func (m *ChannelRestrictions) Reset()         { *m = ChannelRestrictions{} }
 func (m *ChannelRestrictions) String() string { return proto.CompactTextString(m) }
 func (*ChannelRestrictions) ProtoMessage()    {}
 func (*ChannelRestrictions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{4}
+	return fileDescriptor_configuration_b29a8c6d05b886a1, []int{5}
 }
 func (m *ChannelRestrictions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelRestrictions.Unmarshal(m, b)
@@ -286,41 +363,47 @@ func init() {
 	proto.RegisterType((*ConsensusType)(nil), "orderer.ConsensusType")
 	proto.RegisterType((*BatchSize)(nil), "orderer.BatchSize")
 	proto.RegisterType((*BatchTimeout)(nil), "orderer.BatchTimeout")
+	proto.RegisterType((*BatchCutting)(nil), "orderer.BatchCutting")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
 	proto.RegisterType((*ChannelRestrictions)(nil), "orderer.ChannelRestrictions")
 	proto.RegisterEnum("orderer.ConsensusType_State", ConsensusType_State_name, ConsensusType_State_value)
+	proto.RegisterEnum("orderer.BatchCutting_Mode", BatchCutting_Mode_name, BatchCutting_Mode_value)
 }
 
 func init() {
-	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_3a2420d24de6d468)
-}
-
-var fileDescriptor_configuration_3a2420d24de6d468 = []byte{
-	// 403 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xc1, 0x8a, 0xdb, 0x30,
-	0x10, 0x86, 0xeb, 0x66, 0xb7, 0xbb, 0x19, 0x92, 0x36, 0xd1, 0x52, 0x30, 0xdd, 0x1e, 0x82, 0xa1,
-	0x10, 0xca, 0x22, 0x97, 0xf4, 0x09, 0x92, 0x90, 0x43, 0x69, 0x93, 0x82, 0xe2, 0x5e, 0x7a, 0x09,
-	0x63, 0x67, 0xe2, 0x98, 0x8d, 0x2d, 0x23, 0xc9, 0x90, 0xf4, 0x3d, 0xfa, 0x08, 0x7d, 0xcf, 0x22,
-	0xc9, 0xde, 0x6e, 0x6f, 0xf3, 0xff, 0xf3, 0x69, 0x98, 0xd1, 0x0f, 0xf7, 0x52, 0xed, 0x49, 0x91,
-	0x8a, 0x33, 0x59, 0x1d, 0x8a, 0xbc, 0x51, 0x68, 0x0a, 0x59, 0xf1, 0x5a, 0x49, 0x23, 0xd9, 0x4d,
-	0xdb, 0x8c, 0xfe, 0x04, 0x30, 0x5c, 0xca, 0x4a, 0x53, 0xa5, 0x1b, 0x9d, 0x5c, 0x6a, 0x62, 0x0c,
-	0xae, 0xcc, 0xa5, 0xa6, 0x30, 0x98, 0x04, 0xd3, 0xbe, 0x70, 0x35, 0x7b, 0x07, 0xb7, 0x25, 0x19,
-	0xdc, 0xa3, 0xc1, 0xf0, 0xe5, 0x24, 0x98, 0x0e, 0xc4, 0x93, 0x66, 0x33, 0xb8, 0xd6, 0x06, 0x0d,
-	0x85, 0xbd, 0x49, 0x30, 0x7d, 0x3d, 0x7b, 0xcf, 0xdb, 0xd1, 0xfc, 0xbf, 0xb1, 0x7c, 0x6b, 0x19,
-	0xe1, 0xd1, 0xe8, 0x13, 0x5c, 0x3b, 0xcd, 0x46, 0x30, 0xd8, 0x26, 0xf3, 0x64, 0xb5, 0xdb, 0x7c,
-	0x17, 0xeb, 0xf9, 0xb7, 0xd1, 0x0b, 0xf6, 0x16, 0xc6, 0xde, 0x59, 0xcf, 0xbf, 0x6c, 0x92, 0xd5,
-	0x66, 0xbe, 0x59, 0xae, 0x46, 0x41, 0xf4, 0x3b, 0x80, 0xfe, 0x02, 0x4d, 0x76, 0xdc, 0x16, 0xbf,
-	0x88, 0x7d, 0x84, 0x71, 0x89, 0xe7, 0x5d, 0x49, 0x5a, 0x63, 0x4e, 0xbb, 0x4c, 0x36, 0x95, 0x71,
-	0x0b, 0x0f, 0xc5, 0x9b, 0x12, 0xcf, 0x6b, 0xef, 0x2f, 0xad, 0xcd, 0x1e, 0x80, 0x61, 0xaa, 0xe5,
-	0xa9, 0x31, 0xb4, 0xb3, 0x8f, 0xd2, 0x8b, 0x21, 0xed, 0xae, 0x18, 0x8a, 0x51, 0xd7, 0x59, 0xe3,
-	0x79, 0x61, 0x7d, 0xc6, 0xe1, 0xae, 0x56, 0x74, 0x20, 0xa5, 0x68, 0xff, 0x0c, 0xef, 0x39, 0x7c,
-	0xfc, 0xd4, 0xea, 0xf8, 0x68, 0x0a, 0x03, 0xb7, 0x56, 0x52, 0x94, 0x24, 0x1b, 0xc3, 0x42, 0xb8,
-	0x31, 0xbe, 0x6c, 0x3f, 0xb0, 0x93, 0x96, 0xfc, 0x8a, 0x87, 0x47, 0x5c, 0x28, 0xf9, 0x48, 0x4a,
-	0x5b, 0x32, 0xf5, 0x65, 0x18, 0x4c, 0x7a, 0x96, 0x6c, 0x65, 0x34, 0x83, 0xbb, 0xe5, 0x11, 0xab,
-	0x8a, 0x4e, 0x82, 0xb4, 0x51, 0x45, 0x66, 0x83, 0xd3, 0xec, 0x1e, 0xfa, 0x76, 0xa1, 0x7f, 0xc7,
-	0x5e, 0x89, 0xdb, 0x12, 0xcf, 0xee, 0xca, 0xc5, 0x0f, 0xf8, 0x20, 0x55, 0xce, 0x8f, 0x97, 0x9a,
-	0xd4, 0x89, 0xf6, 0x39, 0x29, 0x7e, 0xc0, 0x54, 0x15, 0x99, 0x0f, 0x5c, 0x77, 0xa9, 0xfc, 0x7c,
-	0xc8, 0x0b, 0x73, 0x6c, 0x52, 0x9e, 0xc9, 0x32, 0x7e, 0x46, 0xc7, 0x9e, 0x8e, 0x3d, 0x1d, 0xb7,
-	0x74, 0xfa, 0xca, 0xe9, 0xcf, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x90, 0x7c, 0x05, 0xd3, 0x4d,
-	0x02, 0x00, 0x00,
+	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_b29a8c6d05b886a1)
+}
+
+var fileDescriptor_configuration_b29a8c6d05b886a1 = []byte{
+	// 478 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x92, 0x51, 0x6e, 0xda, 0x40,
+	0x10, 0x86, 0xe3, 0x42, 0x12, 0x18, 0x01, 0x85, 0x8d, 0x2a, 0xa1, 0xa4, 0x0f, 0xc8, 0x52, 0x24,
+	0x54, 0x45, 0xa6, 0xa2, 0x27, 0x30, 0x2e, 0x0f, 0xa8, 0x81, 0x56, 0xc6, 0xed, 0x43, 0x5f, 0xd0,
+	0xda, 0x1e, 0x8c, 0x15, 0xec, 0x45, 0xbb, 0x63, 0x09, 0xf7, 0x02, 0x3d, 0x41, 0x8f, 0xd0, 0x7b,
+	0x56, 0xeb, 0x35, 0x94, 0xbc, 0xcd, 0xff, 0xcf, 0xb7, 0xab, 0x99, 0x5f, 0x03, 0x0f, 0x42, 0xc6,
+	0x28, 0x51, 0x4e, 0x22, 0x91, 0x6f, 0xd3, 0xa4, 0x90, 0x9c, 0x52, 0x91, 0x3b, 0x07, 0x29, 0x48,
+	0xb0, 0xdb, 0xba, 0x69, 0xff, 0xb5, 0xa0, 0xeb, 0x89, 0x5c, 0x61, 0xae, 0x0a, 0x15, 0x94, 0x07,
+	0x64, 0x0c, 0x9a, 0x54, 0x1e, 0x70, 0x68, 0x8d, 0xac, 0x71, 0xdb, 0xaf, 0x6a, 0x76, 0x0f, 0xad,
+	0x0c, 0x89, 0xc7, 0x9c, 0xf8, 0xf0, 0xcd, 0xc8, 0x1a, 0x77, 0xfc, 0xb3, 0x66, 0x53, 0xb8, 0x56,
+	0xc4, 0x09, 0x87, 0x8d, 0x91, 0x35, 0xee, 0x4d, 0xdf, 0x3b, 0xf5, 0xd7, 0xce, 0xab, 0x6f, 0x9d,
+	0xb5, 0x66, 0x7c, 0x83, 0xda, 0x1f, 0xe1, 0xba, 0xd2, 0xac, 0x0f, 0x9d, 0x75, 0xe0, 0x06, 0xf3,
+	0xcd, 0xea, 0xab, 0xbf, 0x74, 0x9f, 0xfb, 0x57, 0xec, 0x1d, 0x0c, 0x8c, 0xb3, 0x74, 0x17, 0xab,
+	0x60, 0xbe, 0x72, 0x57, 0xde, 0xbc, 0x6f, 0xd9, 0x7f, 0x2c, 0x68, 0xcf, 0x38, 0x45, 0xbb, 0x75,
+	0xfa, 0x0b, 0xd9, 0x07, 0x18, 0x64, 0xfc, 0xb8, 0xc9, 0x50, 0x29, 0x9e, 0xe0, 0x26, 0x12, 0x45,
+	0x4e, 0xd5, 0xc0, 0x5d, 0xff, 0x6d, 0xc6, 0x8f, 0x4b, 0xe3, 0x7b, 0xda, 0x66, 0x4f, 0xc0, 0x78,
+	0xa8, 0xc4, 0xbe, 0x20, 0xdc, 0xe8, 0x47, 0x61, 0x49, 0xa8, 0xaa, 0x2d, 0xba, 0x7e, 0xff, 0xd4,
+	0x59, 0xf2, 0xe3, 0x4c, 0xfb, 0xcc, 0x81, 0xbb, 0x83, 0xc4, 0x2d, 0x4a, 0x89, 0xf1, 0x05, 0xde,
+	0xa8, 0xf0, 0xc1, 0xb9, 0x75, 0xe2, 0xed, 0x31, 0x74, 0xaa, 0xb1, 0x82, 0x34, 0x43, 0x51, 0x10,
+	0x1b, 0xc2, 0x2d, 0x99, 0xb2, 0x0e, 0xf0, 0x24, 0xed, 0xdf, 0x56, 0x8d, 0x7a, 0x05, 0x51, 0x9a,
+	0x27, 0xcc, 0x81, 0x66, 0x26, 0x62, 0x13, 0x74, 0x6f, 0x7a, 0x7f, 0xce, 0xed, 0x12, 0x72, 0x96,
+	0x22, 0x46, 0xbf, 0xe2, 0xd8, 0x23, 0xf4, 0x88, 0xcb, 0x04, 0x69, 0xb3, 0xe7, 0x84, 0x79, 0x54,
+	0x56, 0x4b, 0xb4, 0xfd, 0xae, 0x71, 0x9f, 0x8d, 0x69, 0x8f, 0xa0, 0xa9, 0x1f, 0x31, 0x80, 0x1b,
+	0x1d, 0xe4, 0xc2, 0xeb, 0x5f, 0xb1, 0x0e, 0xb4, 0xdc, 0xcf, 0xee, 0xb7, 0x60, 0xf1, 0x43, 0x67,
+	0x39, 0x86, 0xce, 0x17, 0xbe, 0x7d, 0xe1, 0x33, 0x29, 0x5e, 0x50, 0x2a, 0x3d, 0x73, 0x68, 0xca,
+	0xa1, 0x35, 0x6a, 0xe8, 0x99, 0x6b, 0x69, 0x4f, 0xe1, 0xce, 0xdb, 0xf1, 0x3c, 0xc7, 0xbd, 0x8f,
+	0x8a, 0x64, 0x1a, 0xe9, 0x13, 0x52, 0xec, 0x01, 0xda, 0x3a, 0x9a, 0xff, 0xb1, 0x37, 0xfd, 0x56,
+	0xc6, 0x8f, 0x55, 0xde, 0xb3, 0xef, 0xf0, 0x28, 0x64, 0xe2, 0xec, 0xca, 0x03, 0xca, 0x3d, 0xc6,
+	0x09, 0x4a, 0x67, 0xcb, 0x43, 0x99, 0x46, 0xe6, 0xf4, 0xd4, 0x69, 0xcf, 0x9f, 0x4f, 0x49, 0x4a,
+	0xbb, 0x22, 0x74, 0x22, 0x91, 0x4d, 0x2e, 0xe8, 0x89, 0xa1, 0x27, 0x86, 0x9e, 0xd4, 0x74, 0x78,
+	0x53, 0xe9, 0x4f, 0xff, 0x06, 0x00, 0x20, 0x2a, 0x5c, 0x50, 0xd7, 0x02, 0x00, 0x00,
 }
@@ -104,7 +104,7 @@ func (x ChaincodeMessage_Type) String() string {
 	return proto.EnumName(ChaincodeMessage_Type_name, int32(x))
 }
 func (ChaincodeMessage_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{0, 0}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{0, 0}
 }
 
 type ChaincodeMessage struct {
@@ -128,7 +128,7 @@ func (m *ChaincodeMessage) Reset()         { *m = ChaincodeMessage{} }
 func (m *ChaincodeMessage) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeMessage) ProtoMessage()    {}
 func (*ChaincodeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{0}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{0}
 }
 func (m *ChaincodeMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeMessage.Unmarshal(m, b)
@@ -212,7 +212,7 @@ func (m *GetState) Reset()         { *m = GetState{} }
 func (m *GetState) String() string { return proto.CompactTextString(m) }
 func (*GetState) ProtoMessage()    {}
 func (*GetState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{1}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{1}
 }
 func (m *GetState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetState.Unmarshal(m, b)
@@ -258,7 +258,7 @@ func (m *GetStateMetadata) Reset()         { *m = GetStateMetadata{} }
 func (m *GetStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*GetStateMetadata) ProtoMessage()    {}
 func (*GetStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{2}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{2}
 }
 func (m *GetStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateMetadata.Unmarshal(m, b)
@@ -309,7 +309,7 @@ func (m *PutState) Reset()         { *m = PutState{} }
 func (m *PutState) String() string { return proto.CompactTextString(m) }
 func (*PutState) ProtoMessage()    {}
 func (*PutState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{3}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{3}
 }
 func (m *PutState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutState.Unmarshal(m, b)
@@ -363,7 +363,7 @@ func (m *PutStateMetadata) Reset()         { *m = PutStateMetadata{} }
 func (m *PutStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*PutStateMetadata) ProtoMessage()    {}
 func (*PutStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{4}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{4}
 }
 func (m *PutStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutStateMetadata.Unmarshal(m, b)
@@ -420,7 +420,7 @@ func (m *DelState) Reset()         { *m = DelState{} }
 func (m *DelState) String() string { return proto.CompactTextString(m) }
 func (*DelState) ProtoMessage()    {}
 func (*DelState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{5}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{5}
 }
 func (m *DelState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DelState.Unmarshal(m, b)
@@ -472,7 +472,7 @@ func (m *GetStateByRange) Reset()         { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()    {}
 func (*GetStateByRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{6}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{6}
 }
 func (m *GetStateByRange) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateByRange.Unmarshal(m, b)
@@ -537,7 +537,7 @@ func (m *GetQueryResult) Reset()         { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()    {}
 func (*GetQueryResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{7}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{7}
 }
 func (m *GetQueryResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetQueryResult.Unmarshal(m, b)
@@ -593,7 +593,7 @@ func (m *QueryMetadata) Reset()         { *m = QueryMetadata{} }
 func (m *QueryMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryMetadata) ProtoMessage()    {}
 func (*QueryMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{8}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{8}
 }
 func (m *QueryMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryMetadata.Unmarshal(m, b)
@@ -628,9 +628,11 @@ func (m *QueryMetadata) GetBookmark() string {
 }
 
 // GetHistoryForKey is the payload of a ChaincodeMessage. It contains a key
-// for which the historical values need to be retrieved.
+// for which the historical values need to be retrieved. The metadata hold
+// the byte representation of HistoryQueryMetadata.
 type GetHistoryForKey struct {
 	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -640,7 +642,7 @@ func (m *GetHistoryForKey) Reset()         { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()    {}
 func (*GetHistoryForKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{9}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{9}
 }
 func (m *GetHistoryForKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryForKey.Unmarshal(m, b)
@@ -667,6 +669,103 @@ func (m *GetHistoryForKey) GetKey() string {
 	return ""
 }
 
+func (m *GetHistoryForKey) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// HistoryQueryMetadata is the metadata of a GetHistoryForKey. It bounds the
+// history to a range of block numbers and a range of transaction timestamps,
+// selects the newest-first ordering and carries a pageSize and a bookmark
+// for pagination. An endBlock of zero and unset timestamps denote open ranges.
+type HistoryQueryMetadata struct {
+	StartBlock           uint64               `protobuf:"varint,1,opt,name=startBlock,proto3" json:"startBlock,omitempty"`
+	EndBlock             uint64               `protobuf:"varint,2,opt,name=endBlock,proto3" json:"endBlock,omitempty"`
+	StartTime            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	NewestFirst          bool                 `protobuf:"varint,5,opt,name=newestFirst,proto3" json:"newestFirst,omitempty"`
+	PageSize             int32                `protobuf:"varint,6,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	Bookmark             string               `protobuf:"bytes,7,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HistoryQueryMetadata) Reset()         { *m = HistoryQueryMetadata{} }
+func (m *HistoryQueryMetadata) String() string { return proto.CompactTextString(m) }
+func (*HistoryQueryMetadata) ProtoMessage()    {}
+func (*HistoryQueryMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{10}
+}
+func (m *HistoryQueryMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryQueryMetadata.Unmarshal(m, b)
+}
+func (m *HistoryQueryMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryQueryMetadata.Marshal(b, m, deterministic)
+}
+func (dst *HistoryQueryMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryQueryMetadata.Merge(dst, src)
+}
+func (m *HistoryQueryMetadata) XXX_Size() int {
+	return xxx_messageInfo_HistoryQueryMetadata.Size(m)
+}
+func (m *HistoryQueryMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryQueryMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryQueryMetadata proto.InternalMessageInfo
+
+func (m *HistoryQueryMetadata) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetNewestFirst() bool {
+	if m != nil {
+		return m.NewestFirst
+	}
+	return false
+}
+
+func (m *HistoryQueryMetadata) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 type QueryStateNext struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -678,7 +777,7 @@ func (m *QueryStateNext) Reset()         { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()    {}
 func (*QueryStateNext) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{10}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{11}
 }
 func (m *QueryStateNext) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateNext.Unmarshal(m, b)
@@ -716,7 +815,7 @@ func (m *QueryStateClose) Reset()         { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()    {}
 func (*QueryStateClose) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{11}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{12}
 }
 func (m *QueryStateClose) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateClose.Unmarshal(m, b)
@@ -755,7 +854,7 @@ func (m *QueryResultBytes) Reset()         { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()    {}
 func (*QueryResultBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{12}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{13}
 }
 func (m *QueryResultBytes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResultBytes.Unmarshal(m, b)
@@ -801,7 +900,7 @@ func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{13}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{14}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
@@ -863,7 +962,7 @@ func (m *QueryResponseMetadata) Reset()         { *m = QueryResponseMetadata{} }
 func (m *QueryResponseMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryResponseMetadata) ProtoMessage()    {}
 func (*QueryResponseMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{14}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{15}
 }
 func (m *QueryResponseMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponseMetadata.Unmarshal(m, b)
@@ -909,7 +1008,7 @@ func (m *StateMetadata) Reset()         { *m = StateMetadata{} }
 func (m *StateMetadata) String() string { return proto.CompactTextString(m) }
 func (*StateMetadata) ProtoMessage()    {}
 func (*StateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{15}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{16}
 }
 func (m *StateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadata.Unmarshal(m, b)
@@ -954,7 +1053,7 @@ func (m *StateMetadataResult) Reset()         { *m = StateMetadataResult{} }
 func (m *StateMetadataResult) String() string { return proto.CompactTextString(m) }
 func (*StateMetadataResult) ProtoMessage()    {}
 func (*StateMetadataResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{16}
+	return fileDescriptor_chaincode_shim_029f42bb32b01ab9, []int{17}
 }
 func (m *StateMetadataResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadataResult.Unmarshal(m, b)
@@ -992,6 +1091,7 @@ func init() {
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
 	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
+	proto.RegisterType((*HistoryQueryMetadata)(nil), "protos.HistoryQueryMetadata")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
@@ -1106,75 +1206,177 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 	Metadata: "peer/chaincode_shim.proto",
 }
 
+// ChaincodeClient is the client API for Chaincode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChaincodeClient interface {
+	Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error)
+}
+
+type chaincodeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChaincodeClient(cc *grpc.ClientConn) ChaincodeClient {
+	return &chaincodeClient{cc}
+}
+
+func (c *chaincodeClient) Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Chaincode_serviceDesc.Streams[0], "/protos.Chaincode/Connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chaincodeConnectClient{stream}
+	return x, nil
+}
+
+type Chaincode_ConnectClient interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ClientStream
+}
+
+type chaincodeConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *chaincodeConnectClient) Send(m *ChaincodeMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectClient) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ChaincodeServer is the server API for Chaincode service.
+type ChaincodeServer interface {
+	Connect(Chaincode_ConnectServer) error
+}
+
+func RegisterChaincodeServer(s *grpc.Server, srv ChaincodeServer) {
+	s.RegisterService(&_Chaincode_serviceDesc, srv)
+}
+
+func _Chaincode_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChaincodeServer).Connect(&chaincodeConnectServer{stream})
+}
+
+type Chaincode_ConnectServer interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ServerStream
+}
+
+type chaincodeConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *chaincodeConnectServer) Send(m *ChaincodeMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectServer) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Chaincode_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Chaincode",
+	HandlerType: (*ChaincodeServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _Chaincode_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "peer/chaincode_shim.proto",
+}
+
 func init() {
-	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_b04d3028f86b65a2)
+	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_029f42bb32b01ab9)
 }
 
-var fileDescriptor_chaincode_shim_b04d3028f86b65a2 = []byte{
-	// 1027 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_chaincode_shim_029f42bb32b01ab9 = []byte{
+	// 1128 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcf, 0x73, 0xda, 0x46,
-	0x14, 0x0e, 0x06, 0x8c, 0x78, 0xd8, 0x78, 0xb3, 0x0e, 0x2e, 0x66, 0x26, 0x2d, 0x65, 0x7a, 0xa0,
-	0x17, 0x68, 0x68, 0x0f, 0x3d, 0x74, 0x26, 0x83, 0x61, 0x8d, 0x19, 0xdb, 0x40, 0x56, 0xb2, 0x27,
-	0xee, 0x45, 0x23, 0xa4, 0xb5, 0xd0, 0x58, 0x68, 0x55, 0x69, 0x49, 0x43, 0x6f, 0xbd, 0xf6, 0xd8,
-	0x3f, 0xae, 0x7f, 0x4f, 0x67, 0xf5, 0xcb, 0x80, 0xeb, 0xa4, 0x93, 0x13, 0xfa, 0xde, 0xfb, 0xf6,
-	0x7b, 0xbf, 0xf6, 0x21, 0xc1, 0xa9, 0xcf, 0x58, 0xd0, 0x35, 0x17, 0x86, 0xe3, 0x99, 0xdc, 0x62,
-	0x7a, 0xb8, 0x70, 0x96, 0x1d, 0x3f, 0xe0, 0x82, 0xe3, 0xfd, 0xe8, 0x27, 0x6c, 0x34, 0x76, 0x28,
-	0xec, 0x03, 0xf3, 0x44, 0xcc, 0x69, 0x1c, 0x47, 0x3e, 0x3f, 0xe0, 0x3e, 0x0f, 0x0d, 0x37, 0x31,
-	0x7e, 0x63, 0x73, 0x6e, 0xbb, 0xac, 0x1b, 0xa1, 0xf9, 0xea, 0xbe, 0x2b, 0x9c, 0x25, 0x0b, 0x85,
-	0xb1, 0xf4, 0x63, 0x42, 0xeb, 0x9f, 0x22, 0xa0, 0x41, 0xaa, 0x77, 0xcd, 0xc2, 0xd0, 0xb0, 0x19,
-	0x7e, 0x03, 0x05, 0xb1, 0xf6, 0x59, 0x3d, 0xd7, 0xcc, 0xb5, 0xab, 0xbd, 0xd7, 0x31, 0x35, 0xec,
-	0xec, 0xf2, 0x3a, 0xda, 0xda, 0x67, 0x34, 0xa2, 0xe2, 0x9f, 0xa1, 0x9c, 0x49, 0xd7, 0xf7, 0x9a,
-	0xb9, 0x76, 0xa5, 0xd7, 0xe8, 0xc4, 0xc1, 0x3b, 0x69, 0xf0, 0x8e, 0x96, 0x32, 0xe8, 0x23, 0x19,
-	0xd7, 0xa1, 0xe4, 0x1b, 0x6b, 0x97, 0x1b, 0x56, 0x3d, 0xdf, 0xcc, 0xb5, 0x0f, 0x68, 0x0a, 0x31,
-	0x86, 0x82, 0xf8, 0xe8, 0x58, 0xf5, 0x42, 0x33, 0xd7, 0x2e, 0xd3, 0xe8, 0x19, 0xf7, 0x40, 0x49,
-	0x4b, 0xac, 0x17, 0xa3, 0x30, 0x27, 0x69, 0x7a, 0xaa, 0x63, 0x7b, 0xcc, 0x9a, 0x25, 0x5e, 0x9a,
-	0xf1, 0xf0, 0x5b, 0x38, 0xda, 0x69, 0x59, 0x7d, 0x7f, 0xfb, 0x68, 0x56, 0x19, 0x91, 0x5e, 0x5a,
-	0x35, 0xb7, 0x30, 0x7e, 0x0d, 0x60, 0x2e, 0x0c, 0xcf, 0x63, 0xae, 0xee, 0x58, 0xf5, 0x52, 0x94,
-	0x4e, 0x39, 0xb1, 0x8c, 0xad, 0xd6, 0xdf, 0x79, 0x28, 0xc8, 0x56, 0xe0, 0x43, 0x28, 0xdf, 0x4c,
-	0x86, 0xe4, 0x7c, 0x3c, 0x21, 0x43, 0xf4, 0x02, 0x1f, 0x80, 0x42, 0xc9, 0x68, 0xac, 0x6a, 0x84,
-	0xa2, 0x1c, 0xae, 0x02, 0xa4, 0x88, 0x0c, 0xd1, 0x1e, 0x56, 0xa0, 0x30, 0x9e, 0x8c, 0x35, 0x94,
-	0xc7, 0x65, 0x28, 0x52, 0xd2, 0x1f, 0xde, 0xa1, 0x02, 0x3e, 0x82, 0x8a, 0x46, 0xfb, 0x13, 0xb5,
-	0x3f, 0xd0, 0xc6, 0xd3, 0x09, 0x2a, 0x4a, 0xc9, 0xc1, 0xf4, 0x7a, 0x76, 0x45, 0x34, 0x32, 0x44,
-	0xfb, 0x92, 0x4a, 0x28, 0x9d, 0x52, 0x54, 0x92, 0x9e, 0x11, 0xd1, 0x74, 0x55, 0xeb, 0x6b, 0x04,
-	0x29, 0x12, 0xce, 0x6e, 0x52, 0x58, 0x96, 0x70, 0x48, 0xae, 0x12, 0x08, 0xf8, 0x15, 0xa0, 0xf1,
-	0xe4, 0x76, 0x7a, 0x49, 0xf4, 0xc1, 0x45, 0x7f, 0x3c, 0x19, 0x4c, 0x87, 0x04, 0x55, 0xe2, 0x04,
-	0xd5, 0xd9, 0x74, 0xa2, 0x12, 0x74, 0x88, 0x4f, 0x00, 0x67, 0x82, 0xfa, 0xd9, 0x9d, 0x4e, 0xfb,
-	0x93, 0x11, 0x41, 0x55, 0x79, 0x56, 0xda, 0xdf, 0xdd, 0x10, 0x7a, 0xa7, 0x53, 0xa2, 0xde, 0x5c,
-	0x69, 0xe8, 0x48, 0x5a, 0x63, 0x4b, 0xcc, 0x9f, 0x90, 0xf7, 0x1a, 0x42, 0xb8, 0x06, 0x2f, 0x37,
-	0xad, 0x83, 0xab, 0xa9, 0x4a, 0xd0, 0x4b, 0x99, 0xcd, 0x25, 0x21, 0xb3, 0xfe, 0xd5, 0xf8, 0x96,
-	0x20, 0x8c, 0xbf, 0x82, 0x63, 0xa9, 0x78, 0x31, 0x56, 0xb5, 0x29, 0xbd, 0xd3, 0xcf, 0xa7, 0x54,
-	0xbf, 0x24, 0x77, 0xe8, 0x78, 0x3b, 0x85, 0x6b, 0xa2, 0xf5, 0x87, 0x7d, 0xad, 0x8f, 0x5e, 0x49,
-	0x7b, 0x56, 0xdc, 0xa3, 0xbd, 0x86, 0x4f, 0xa1, 0x26, 0xf9, 0x33, 0x3a, 0xbe, 0x95, 0x1e, 0x69,
-	0xd5, 0x2f, 0xfa, 0xea, 0x05, 0x3a, 0x69, 0xfd, 0x02, 0xca, 0x88, 0x09, 0x55, 0x18, 0x82, 0x61,
-	0x04, 0xf9, 0x07, 0xb6, 0x8e, 0xae, 0x73, 0x99, 0xca, 0x47, 0xfc, 0x35, 0x80, 0xc9, 0x5d, 0x97,
-	0x99, 0xc2, 0xe1, 0x5e, 0x74, 0x5f, 0xcb, 0x74, 0xc3, 0xd2, 0x1a, 0x02, 0x4a, 0x4f, 0x5f, 0x33,
-	0x61, 0x58, 0x86, 0x30, 0xbe, 0x40, 0x85, 0x82, 0x32, 0x5b, 0x3d, 0x9b, 0xc3, 0x2b, 0x28, 0x7e,
-	0x30, 0xdc, 0x15, 0x8b, 0x0e, 0x1e, 0xd0, 0x18, 0xec, 0x68, 0xe6, 0x9f, 0x68, 0xfe, 0x0e, 0x28,
-	0xd5, 0xfc, 0xdf, 0x99, 0x3d, 0x51, 0xc1, 0x6f, 0x40, 0x59, 0x26, 0xa7, 0xa3, 0xf5, 0xaa, 0xf4,
-	0x6a, 0xd9, 0x1a, 0x6d, 0x4a, 0xd3, 0x8c, 0x26, 0x1b, 0x3a, 0x64, 0xee, 0x97, 0x36, 0xf4, 0xcf,
-	0x1c, 0x1c, 0xa5, 0x1d, 0x3d, 0x5b, 0x53, 0xc3, 0xb3, 0x19, 0x6e, 0x80, 0x12, 0x0a, 0x23, 0x10,
-	0x97, 0x99, 0x54, 0x86, 0xf1, 0x09, 0xec, 0x33, 0xcf, 0x92, 0x9e, 0x58, 0x2b, 0x41, 0x9f, 0x2d,
-	0xac, 0xb1, 0x53, 0xd8, 0xc1, 0x46, 0x05, 0x73, 0xa8, 0x8e, 0x98, 0x78, 0xb7, 0x62, 0xc1, 0x9a,
-	0xb2, 0x70, 0xe5, 0x0a, 0x39, 0x82, 0xdf, 0x24, 0x4c, 0xc2, 0xc7, 0xe0, 0x73, 0xb5, 0x6c, 0xc5,
-	0xc8, 0xef, 0xc4, 0x18, 0xc1, 0x61, 0x14, 0x20, 0x9b, 0x4d, 0x03, 0x14, 0xdf, 0xb0, 0x99, 0xea,
-	0xfc, 0x11, 0xff, 0x9f, 0x16, 0x69, 0x86, 0xa5, 0x6f, 0xce, 0xf9, 0xc3, 0xd2, 0x08, 0x1e, 0x92,
-	0x30, 0x19, 0x6e, 0x7d, 0x17, 0xdd, 0xc0, 0x0b, 0x27, 0x14, 0x3c, 0x58, 0x9f, 0xf3, 0x40, 0x16,
-	0xff, 0xa4, 0xed, 0xad, 0x26, 0x54, 0xa3, 0x70, 0x51, 0x5f, 0x27, 0xec, 0xa3, 0xc0, 0x55, 0xd8,
-	0x73, 0xac, 0x84, 0xb2, 0xe7, 0x58, 0xad, 0x6f, 0xe1, 0xe8, 0x91, 0x31, 0x70, 0x79, 0xc8, 0x9e,
-	0x50, 0x7e, 0x02, 0xb4, 0xd1, 0x94, 0xb3, 0xb5, 0x60, 0x21, 0x6e, 0x42, 0x25, 0x78, 0x84, 0x11,
-	0xf9, 0x80, 0x6e, 0x9a, 0x5a, 0x7f, 0xe5, 0x92, 0x52, 0x29, 0x0b, 0x7d, 0xee, 0x85, 0x0c, 0xf7,
-	0xa0, 0x14, 0x13, 0x24, 0x3f, 0xdf, 0xae, 0xf4, 0xea, 0xe9, 0x9d, 0xda, 0x95, 0xa7, 0x29, 0x11,
-	0x9f, 0x82, 0xb2, 0x30, 0x42, 0x7d, 0xc9, 0x83, 0x78, 0x0f, 0x14, 0x5a, 0x5a, 0x18, 0xe1, 0x35,
-	0x0f, 0xd2, 0x34, 0xf3, 0x69, 0x9a, 0x9f, 0x1c, 0xad, 0x0d, 0xb5, 0xad, 0x5c, 0xb2, 0xf6, 0xf7,
-	0xa0, 0x76, 0xcf, 0x84, 0xb9, 0x60, 0x96, 0x1e, 0x30, 0x93, 0x07, 0x56, 0xa8, 0x9b, 0x7c, 0xe5,
-	0x89, 0x64, 0x16, 0xc7, 0x89, 0x93, 0xc6, 0xbe, 0x81, 0x74, 0x7d, 0x72, 0x2c, 0x6f, 0xe1, 0x70,
-	0x7b, 0xf7, 0xea, 0x50, 0x92, 0x59, 0x3c, 0xce, 0x25, 0x85, 0xff, 0xbd, 0xdf, 0xad, 0x73, 0x38,
-	0xde, 0xde, 0xb0, 0xf8, 0x26, 0x76, 0xa1, 0xc4, 0x3c, 0x11, 0x38, 0x2c, 0xed, 0xdd, 0x33, 0xfb,
-	0x98, 0xb2, 0x7a, 0xef, 0x37, 0xde, 0xdb, 0xea, 0xca, 0xf7, 0x79, 0x20, 0xf0, 0x10, 0x14, 0xca,
-	0x6c, 0x27, 0x14, 0x2c, 0xc0, 0xf5, 0xe7, 0xde, 0xda, 0x8d, 0x67, 0x3d, 0xad, 0x17, 0xed, 0xdc,
-	0x0f, 0xb9, 0xb3, 0x29, 0xb4, 0x78, 0x60, 0x77, 0x16, 0x6b, 0x9f, 0x05, 0x2e, 0xb3, 0x6c, 0x16,
-	0x74, 0xee, 0x8d, 0x79, 0xe0, 0x98, 0xe9, 0x39, 0xf9, 0xa1, 0xf1, 0xeb, 0xf7, 0xb6, 0x23, 0x16,
-	0xab, 0x79, 0xc7, 0xe4, 0xcb, 0xee, 0x06, 0xb5, 0x1b, 0x53, 0xe3, 0x0f, 0x8e, 0xb0, 0x2b, 0xa9,
-	0xf3, 0xf8, 0xeb, 0xe5, 0xc7, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x3e, 0x82, 0x3d, 0x52, 0xe1,
-	0x08, 0x00, 0x00,
+	0x14, 0x0e, 0x3f, 0x6c, 0xc4, 0xb3, 0x8d, 0x37, 0x6b, 0xe3, 0x2a, 0xcc, 0x24, 0xa5, 0x9c, 0xe8,
+	0x05, 0x1a, 0x9a, 0x43, 0x0f, 0x9d, 0x49, 0x31, 0xac, 0x31, 0x63, 0x1b, 0xc8, 0x4a, 0xce, 0xc4,
+	0xbd, 0x68, 0x84, 0xb4, 0x01, 0x8d, 0x85, 0x56, 0x95, 0x96, 0x24, 0xf4, 0xd6, 0x6b, 0x8f, 0x3d,
+	0xf7, 0xef, 0xea, 0xdf, 0xd3, 0x59, 0xfd, 0x32, 0xe0, 0x3a, 0x9e, 0xfa, 0x04, 0xdf, 0x7b, 0xdf,
+	0xfb, 0xde, 0xdb, 0xa7, 0xb7, 0x6f, 0x16, 0x5e, 0xf8, 0x8c, 0x05, 0x6d, 0x6b, 0x6e, 0x3a, 0x9e,
+	0xc5, 0x6d, 0x66, 0x84, 0x73, 0x67, 0xd1, 0xf2, 0x03, 0x2e, 0x38, 0xde, 0x8d, 0x7e, 0xc2, 0x5a,
+	0x6d, 0x8b, 0xc2, 0x3e, 0x31, 0x4f, 0xc4, 0x9c, 0xda, 0x51, 0xe4, 0xf3, 0x03, 0xee, 0xf3, 0xd0,
+	0x74, 0x13, 0xe3, 0xb7, 0x33, 0xce, 0x67, 0x2e, 0x6b, 0x47, 0x68, 0xba, 0xfc, 0xd8, 0x16, 0xce,
+	0x82, 0x85, 0xc2, 0x5c, 0xf8, 0x31, 0xa1, 0xf1, 0xcf, 0x0e, 0xa0, 0x5e, 0xaa, 0x77, 0xc5, 0xc2,
+	0xd0, 0x9c, 0x31, 0xfc, 0x1a, 0x8a, 0x62, 0xe5, 0x33, 0x35, 0x57, 0xcf, 0x35, 0x2b, 0x9d, 0x97,
+	0x31, 0x35, 0x6c, 0x6d, 0xf3, 0x5a, 0xfa, 0xca, 0x67, 0x34, 0xa2, 0xe2, 0x9f, 0xa0, 0x9c, 0x49,
+	0xab, 0xf9, 0x7a, 0xae, 0xb9, 0xd7, 0xa9, 0xb5, 0xe2, 0xe4, 0xad, 0x34, 0x79, 0x4b, 0x4f, 0x19,
+	0xf4, 0x8e, 0x8c, 0x55, 0x28, 0xf9, 0xe6, 0xca, 0xe5, 0xa6, 0xad, 0x16, 0xea, 0xb9, 0xe6, 0x3e,
+	0x4d, 0x21, 0xc6, 0x50, 0x14, 0x5f, 0x1c, 0x5b, 0x2d, 0xd6, 0x73, 0xcd, 0x32, 0x8d, 0xfe, 0xe3,
+	0x0e, 0x28, 0xe9, 0x11, 0xd5, 0x9d, 0x28, 0xcd, 0x49, 0x5a, 0x9e, 0xe6, 0xcc, 0x3c, 0x66, 0x4f,
+	0x12, 0x2f, 0xcd, 0x78, 0xf8, 0x2d, 0x1c, 0x6e, 0xb5, 0x4c, 0xdd, 0xdd, 0x0c, 0xcd, 0x4e, 0x46,
+	0xa4, 0x97, 0x56, 0xac, 0x0d, 0x8c, 0x5f, 0x02, 0x58, 0x73, 0xd3, 0xf3, 0x98, 0x6b, 0x38, 0xb6,
+	0x5a, 0x8a, 0xca, 0x29, 0x27, 0x96, 0xa1, 0xdd, 0xf8, 0xab, 0x00, 0x45, 0xd9, 0x0a, 0x7c, 0x00,
+	0xe5, 0xeb, 0x51, 0x9f, 0x9c, 0x0d, 0x47, 0xa4, 0x8f, 0x9e, 0xe1, 0x7d, 0x50, 0x28, 0x19, 0x0c,
+	0x35, 0x9d, 0x50, 0x94, 0xc3, 0x15, 0x80, 0x14, 0x91, 0x3e, 0xca, 0x63, 0x05, 0x8a, 0xc3, 0xd1,
+	0x50, 0x47, 0x05, 0x5c, 0x86, 0x1d, 0x4a, 0xba, 0xfd, 0x1b, 0x54, 0xc4, 0x87, 0xb0, 0xa7, 0xd3,
+	0xee, 0x48, 0xeb, 0xf6, 0xf4, 0xe1, 0x78, 0x84, 0x76, 0xa4, 0x64, 0x6f, 0x7c, 0x35, 0xb9, 0x24,
+	0x3a, 0xe9, 0xa3, 0x5d, 0x49, 0x25, 0x94, 0x8e, 0x29, 0x2a, 0x49, 0xcf, 0x80, 0xe8, 0x86, 0xa6,
+	0x77, 0x75, 0x82, 0x14, 0x09, 0x27, 0xd7, 0x29, 0x2c, 0x4b, 0xd8, 0x27, 0x97, 0x09, 0x04, 0x7c,
+	0x0c, 0x68, 0x38, 0x7a, 0x3f, 0xbe, 0x20, 0x46, 0xef, 0xbc, 0x3b, 0x1c, 0xf5, 0xc6, 0x7d, 0x82,
+	0xf6, 0xe2, 0x02, 0xb5, 0xc9, 0x78, 0xa4, 0x11, 0x74, 0x80, 0x4f, 0x00, 0x67, 0x82, 0xc6, 0xe9,
+	0x8d, 0x41, 0xbb, 0xa3, 0x01, 0x41, 0x15, 0x19, 0x2b, 0xed, 0xef, 0xae, 0x09, 0xbd, 0x31, 0x28,
+	0xd1, 0xae, 0x2f, 0x75, 0x74, 0x28, 0xad, 0xb1, 0x25, 0xe6, 0x8f, 0xc8, 0x07, 0x1d, 0x21, 0x5c,
+	0x85, 0xe7, 0xeb, 0xd6, 0xde, 0xe5, 0x58, 0x23, 0xe8, 0xb9, 0xac, 0xe6, 0x82, 0x90, 0x49, 0xf7,
+	0x72, 0xf8, 0x9e, 0x20, 0x8c, 0xbf, 0x81, 0x23, 0xa9, 0x78, 0x3e, 0xd4, 0xf4, 0x31, 0xbd, 0x31,
+	0xce, 0xc6, 0xd4, 0xb8, 0x20, 0x37, 0xe8, 0x68, 0xb3, 0x84, 0x2b, 0xa2, 0x77, 0xfb, 0x5d, 0xbd,
+	0x8b, 0x8e, 0xa5, 0x7d, 0x72, 0x7d, 0xcf, 0x5e, 0xc5, 0x2f, 0xa0, 0x2a, 0xf9, 0x13, 0x3a, 0x7c,
+	0x2f, 0x3d, 0xd2, 0x6a, 0x9c, 0x77, 0xb5, 0x73, 0x74, 0xd2, 0xf8, 0x19, 0x94, 0x01, 0x13, 0x9a,
+	0x30, 0x05, 0xc3, 0x08, 0x0a, 0xb7, 0x6c, 0x15, 0x8d, 0x73, 0x99, 0xca, 0xbf, 0xf8, 0x15, 0x80,
+	0xc5, 0x5d, 0x97, 0x59, 0xc2, 0xe1, 0x5e, 0x34, 0xaf, 0x65, 0xba, 0x66, 0x69, 0xf4, 0x01, 0xa5,
+	0xd1, 0x57, 0x4c, 0x98, 0xb6, 0x29, 0xcc, 0x27, 0xa8, 0x50, 0x50, 0x26, 0xcb, 0x07, 0x6b, 0x38,
+	0x86, 0x9d, 0x4f, 0xa6, 0xbb, 0x64, 0x51, 0xe0, 0x3e, 0x8d, 0xc1, 0x96, 0x66, 0xe1, 0x9e, 0xe6,
+	0x67, 0x40, 0x93, 0xe5, 0xff, 0xac, 0xec, 0x9e, 0x0a, 0x7e, 0x0d, 0xca, 0x22, 0x89, 0x8e, 0xae,
+	0xd7, 0x5e, 0xa7, 0x9a, 0x5d, 0xa3, 0x75, 0x69, 0x9a, 0xd1, 0x64, 0x43, 0xfb, 0xcc, 0x7d, 0x6a,
+	0x43, 0xff, 0xc8, 0xc1, 0x61, 0xda, 0xd1, 0xd3, 0x15, 0x35, 0xbd, 0x19, 0xc3, 0x35, 0x50, 0x42,
+	0x61, 0x06, 0xe2, 0x22, 0x93, 0xca, 0x30, 0x3e, 0x81, 0x5d, 0xe6, 0xd9, 0xd2, 0x13, 0x6b, 0x25,
+	0xe8, 0xd1, 0x83, 0xd5, 0xb6, 0x0e, 0xb6, 0xbf, 0x76, 0x82, 0x29, 0x54, 0x06, 0x4c, 0xbc, 0x5b,
+	0xb2, 0x60, 0x45, 0x59, 0xb8, 0x74, 0x85, 0xfc, 0x04, 0xbf, 0x49, 0x98, 0xa4, 0x8f, 0xc1, 0x63,
+	0x67, 0xd9, 0xc8, 0x51, 0xd8, 0xca, 0x31, 0x80, 0x83, 0x28, 0x41, 0xf6, 0x6d, 0x6a, 0xa0, 0xf8,
+	0xe6, 0x8c, 0x69, 0xce, 0xef, 0xf1, 0x3e, 0xdd, 0xa1, 0x19, 0x96, 0xbe, 0x29, 0xe7, 0xb7, 0x0b,
+	0x33, 0xb8, 0x4d, 0xd2, 0x64, 0xb8, 0xf1, 0x4b, 0x34, 0x81, 0xe7, 0x4e, 0x28, 0x78, 0xb0, 0x3a,
+	0xe3, 0x81, 0x3c, 0xfc, 0xfd, 0xb6, 0xaf, 0x97, 0x92, 0xdf, 0x2a, 0xe5, 0xef, 0x3c, 0x1c, 0x27,
+	0xf1, 0x9b, 0x25, 0xbd, 0x02, 0x88, 0xfa, 0x7c, 0xea, 0x72, 0xeb, 0x36, 0x52, 0x2b, 0xd2, 0x35,
+	0x8b, 0x14, 0x65, 0x9e, 0x1d, 0x7b, 0xf3, 0x91, 0x37, 0xc3, 0x72, 0xcf, 0x47, 0x4c, 0xb9, 0xca,
+	0xd5, 0xc2, 0xe3, 0x7b, 0x3e, 0x23, 0xe3, 0x37, 0x50, 0x62, 0x9e, 0x1d, 0xc5, 0x15, 0x1f, 0x8d,
+	0x4b, 0xa9, 0xb8, 0x0e, 0x7b, 0x1e, 0xfb, 0xcc, 0x42, 0x71, 0xe6, 0x04, 0xa1, 0x88, 0x56, 0xbe,
+	0x42, 0xd7, 0x4d, 0x1b, 0x0d, 0xde, 0xfd, 0x4a, 0x83, 0x4b, 0x5b, 0x0d, 0xae, 0x43, 0x25, 0x6a,
+	0x4b, 0x34, 0x92, 0x23, 0xf6, 0x45, 0xe0, 0x0a, 0xe4, 0x1d, 0x3b, 0xe9, 0x6e, 0xde, 0xb1, 0x1b,
+	0xdf, 0xc1, 0xe1, 0x1d, 0xa3, 0xe7, 0xf2, 0x90, 0xdd, 0xa3, 0xbc, 0x01, 0xb4, 0x36, 0x4f, 0xa7,
+	0x2b, 0xc1, 0x42, 0x59, 0x72, 0x70, 0x07, 0x23, 0xf2, 0x3e, 0x5d, 0x37, 0x35, 0xfe, 0xcc, 0x25,
+	0x53, 0x42, 0x59, 0xe8, 0x73, 0x2f, 0x64, 0xb8, 0x03, 0xa5, 0x98, 0x20, 0xf9, 0x85, 0xe6, 0x5e,
+	0x47, 0x4d, 0xaf, 0xe3, 0xb6, 0x3c, 0x4d, 0x89, 0xf8, 0x05, 0x28, 0x73, 0x33, 0x34, 0x16, 0x3c,
+	0x88, 0x57, 0x88, 0x42, 0x4b, 0x73, 0x33, 0xbc, 0xe2, 0x41, 0x5a, 0x66, 0x21, 0x2d, 0xf3, 0xab,
+	0xb7, 0x62, 0x06, 0xd5, 0x8d, 0x5a, 0xb2, 0x31, 0xe9, 0x40, 0xf5, 0x23, 0x13, 0xd6, 0x9c, 0xd9,
+	0x46, 0xc0, 0x2c, 0x1e, 0xd8, 0xa1, 0x61, 0xf1, 0xa5, 0x27, 0x92, 0x31, 0x3e, 0x4a, 0x9c, 0x34,
+	0xf6, 0xf5, 0xa4, 0xeb, 0xab, 0x13, 0xfd, 0x16, 0x0e, 0x36, 0xd7, 0x96, 0x0a, 0x25, 0x59, 0xc5,
+	0xdd, 0x48, 0xa7, 0xf0, 0xbf, 0x57, 0x63, 0xe3, 0x0c, 0x8e, 0x36, 0x97, 0x53, 0x7c, 0x89, 0xdb,
+	0x72, 0xb0, 0x44, 0xe0, 0xb0, 0xb4, 0x77, 0x0f, 0xac, 0xb2, 0x94, 0xd5, 0xf9, 0xb0, 0xf6, 0xe4,
+	0xd1, 0x96, 0xbe, 0xcf, 0x03, 0x81, 0xfb, 0xa0, 0x50, 0x36, 0x73, 0x42, 0xc1, 0x02, 0xac, 0x3e,
+	0xf4, 0xe0, 0xa9, 0x3d, 0xe8, 0x69, 0x3c, 0x6b, 0xe6, 0x7e, 0xc8, 0x75, 0x26, 0x50, 0xce, 0x3c,
+	0xb8, 0x07, 0xa5, 0x1e, 0xf7, 0x3c, 0x66, 0x89, 0xa7, 0x2b, 0x9e, 0x8e, 0xa1, 0xc1, 0x83, 0x59,
+	0x6b, 0xbe, 0xf2, 0x59, 0xe0, 0x32, 0x7b, 0xc6, 0x82, 0xd6, 0x47, 0x73, 0x1a, 0x38, 0x56, 0x1a,
+	0x27, 0x5f, 0x7d, 0xbf, 0x7e, 0x3f, 0x73, 0xc4, 0x7c, 0x39, 0x6d, 0x59, 0x7c, 0xd1, 0x5e, 0xa3,
+	0xb6, 0x63, 0x6a, 0xfc, 0xfa, 0x0b, 0xdb, 0x92, 0x3a, 0x8d, 0x9f, 0x92, 0x3f, 0xfe, 0x3b, 0x00,
+	0xaa, 0x2e, 0xfc, 0x3e, 0x6e, 0x0a, 0x00, 0x00,
 }
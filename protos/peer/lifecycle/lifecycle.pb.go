@@ -6,6 +6,7 @@ package lifecycle // import "github.com/hyperledger/fabric/protos/peer/lifecycle
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -33,7 +34,7 @@ func (m *InstallChaincodeArgs) Reset()         { *m = InstallChaincodeArgs{} }
 func (m *InstallChaincodeArgs) String() string { return proto.CompactTextString(m) }
 func (*InstallChaincodeArgs) ProtoMessage()    {}
 func (*InstallChaincodeArgs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{0}
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{0}
 }
 func (m *InstallChaincodeArgs) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallChaincodeArgs.Unmarshal(m, b)
@@ -87,7 +88,7 @@ func (m *InstallChaincodeResult) Reset()         { *m = InstallChaincodeResult{}
 func (m *InstallChaincodeResult) String() string { return proto.CompactTextString(m) }
 func (*InstallChaincodeResult) ProtoMessage()    {}
 func (*InstallChaincodeResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{1}
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{1}
 }
 func (m *InstallChaincodeResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallChaincodeResult.Unmarshal(m, b)
@@ -128,7 +129,7 @@ func (m *QueryInstalledChaincodeArgs) Reset()         { *m = QueryInstalledChain
 func (m *QueryInstalledChaincodeArgs) String() string { return proto.CompactTextString(m) }
 func (*QueryInstalledChaincodeArgs) ProtoMessage()    {}
 func (*QueryInstalledChaincodeArgs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{2}
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{2}
 }
 func (m *QueryInstalledChaincodeArgs) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryInstalledChaincodeArgs.Unmarshal(m, b)
@@ -175,7 +176,7 @@ func (m *QueryInstalledChaincodeResult) Reset()         { *m = QueryInstalledCha
 func (m *QueryInstalledChaincodeResult) String() string { return proto.CompactTextString(m) }
 func (*QueryInstalledChaincodeResult) ProtoMessage()    {}
 func (*QueryInstalledChaincodeResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{3}
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{3}
 }
 func (m *QueryInstalledChaincodeResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryInstalledChaincodeResult.Unmarshal(m, b)
@@ -202,33 +203,566 @@ func (m *QueryInstalledChaincodeResult) GetHash() []byte {
 	return nil
 }
 
+// UninstallChaincodeArgs is the message used as the argument to
+// '+lifecycle.UninstallChaincode'
+type UninstallChaincodeArgs struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UninstallChaincodeArgs) Reset()         { *m = UninstallChaincodeArgs{} }
+func (m *UninstallChaincodeArgs) String() string { return proto.CompactTextString(m) }
+func (*UninstallChaincodeArgs) ProtoMessage()    {}
+func (*UninstallChaincodeArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{4}
+}
+func (m *UninstallChaincodeArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UninstallChaincodeArgs.Unmarshal(m, b)
+}
+func (m *UninstallChaincodeArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UninstallChaincodeArgs.Marshal(b, m, deterministic)
+}
+func (dst *UninstallChaincodeArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UninstallChaincodeArgs.Merge(dst, src)
+}
+func (m *UninstallChaincodeArgs) XXX_Size() int {
+	return xxx_messageInfo_UninstallChaincodeArgs.Size(m)
+}
+func (m *UninstallChaincodeArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_UninstallChaincodeArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UninstallChaincodeArgs proto.InternalMessageInfo
+
+func (m *UninstallChaincodeArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UninstallChaincodeArgs) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+// UninstallChaincodeResult is the message returned by
+// '+lifecycle.UninstallChaincode'
+type UninstallChaincodeResult struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UninstallChaincodeResult) Reset()         { *m = UninstallChaincodeResult{} }
+func (m *UninstallChaincodeResult) String() string { return proto.CompactTextString(m) }
+func (*UninstallChaincodeResult) ProtoMessage()    {}
+func (*UninstallChaincodeResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{5}
+}
+func (m *UninstallChaincodeResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UninstallChaincodeResult.Unmarshal(m, b)
+}
+func (m *UninstallChaincodeResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UninstallChaincodeResult.Marshal(b, m, deterministic)
+}
+func (dst *UninstallChaincodeResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UninstallChaincodeResult.Merge(dst, src)
+}
+func (m *UninstallChaincodeResult) XXX_Size() int {
+	return xxx_messageInfo_UninstallChaincodeResult.Size(m)
+}
+func (m *UninstallChaincodeResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_UninstallChaincodeResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UninstallChaincodeResult proto.InternalMessageInfo
+
+func (m *UninstallChaincodeResult) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+// ChaincodeDefinition is the definition of a chaincode which the organizations
+// of a channel approve, and which becomes active on the channel once committed
+type ChaincodeDefinition struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Version              string                          `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,4,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                          `protobuf:"bytes,5,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,6,opt,name=collections,proto3" json:"collections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *ChaincodeDefinition) Reset()         { *m = ChaincodeDefinition{} }
+func (m *ChaincodeDefinition) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeDefinition) ProtoMessage()    {}
+func (*ChaincodeDefinition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{6}
+}
+func (m *ChaincodeDefinition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeDefinition.Unmarshal(m, b)
+}
+func (m *ChaincodeDefinition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeDefinition.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeDefinition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeDefinition.Merge(dst, src)
+}
+func (m *ChaincodeDefinition) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeDefinition.Size(m)
+}
+func (m *ChaincodeDefinition) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeDefinition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeDefinition proto.InternalMessageInfo
+
+func (m *ChaincodeDefinition) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *ChaincodeDefinition) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ChaincodeDefinition) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *ChaincodeDefinition) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *ChaincodeDefinition) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *ChaincodeDefinition) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+// ApproveChaincodeDefinitionForMyOrgArgs is the message used as the argument to
+// '+lifecycle.ApproveChaincodeDefinitionForMyOrg'
+type ApproveChaincodeDefinitionForMyOrgArgs struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Definition           *ChaincodeDefinition `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) Reset() {
+	*m = ApproveChaincodeDefinitionForMyOrgArgs{}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) String() string { return proto.CompactTextString(m) }
+func (*ApproveChaincodeDefinitionForMyOrgArgs) ProtoMessage()    {}
+func (*ApproveChaincodeDefinitionForMyOrgArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{7}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Unmarshal(m, b)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Marshal(b, m, deterministic)
+}
+func (dst *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Merge(dst, src)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Size() int {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Size(m)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs proto.InternalMessageInfo
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetDefinition() *ChaincodeDefinition {
+	if m != nil {
+		return m.Definition
+	}
+	return nil
+}
+
+// ApproveChaincodeDefinitionForMyOrgResult is the message returned by
+// '+lifecycle.ApproveChaincodeDefinitionForMyOrg'
+type ApproveChaincodeDefinitionForMyOrgResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgResult) Reset() {
+	*m = ApproveChaincodeDefinitionForMyOrgResult{}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) String() string { return proto.CompactTextString(m) }
+func (*ApproveChaincodeDefinitionForMyOrgResult) ProtoMessage()    {}
+func (*ApproveChaincodeDefinitionForMyOrgResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{8}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Unmarshal(m, b)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Marshal(b, m, deterministic)
+}
+func (dst *ApproveChaincodeDefinitionForMyOrgResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Merge(dst, src)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Size() int {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Size(m)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult proto.InternalMessageInfo
+
+// CommitChaincodeDefinitionArgs is the message used as the argument to
+// '+lifecycle.CommitChaincodeDefinition'
+type CommitChaincodeDefinitionArgs struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Definition           *ChaincodeDefinition `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CommitChaincodeDefinitionArgs) Reset()         { *m = CommitChaincodeDefinitionArgs{} }
+func (m *CommitChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*CommitChaincodeDefinitionArgs) ProtoMessage()    {}
+func (*CommitChaincodeDefinitionArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{9}
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Unmarshal(m, b)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Marshal(b, m, deterministic)
+}
+func (dst *CommitChaincodeDefinitionArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitChaincodeDefinitionArgs.Merge(dst, src)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Size() int {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Size(m)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitChaincodeDefinitionArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitChaincodeDefinitionArgs proto.InternalMessageInfo
+
+func (m *CommitChaincodeDefinitionArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetDefinition() *ChaincodeDefinition {
+	if m != nil {
+		return m.Definition
+	}
+	return nil
+}
+
+// CommitChaincodeDefinitionResult is the message returned by
+// '+lifecycle.CommitChaincodeDefinition'
+type CommitChaincodeDefinitionResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitChaincodeDefinitionResult) Reset()         { *m = CommitChaincodeDefinitionResult{} }
+func (m *CommitChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*CommitChaincodeDefinitionResult) ProtoMessage()    {}
+func (*CommitChaincodeDefinitionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{10}
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Unmarshal(m, b)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Marshal(b, m, deterministic)
+}
+func (dst *CommitChaincodeDefinitionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitChaincodeDefinitionResult.Merge(dst, src)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Size() int {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Size(m)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitChaincodeDefinitionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitChaincodeDefinitionResult proto.InternalMessageInfo
+
+// QueryApprovalStatusArgs is the message used as the argument to
+// '+lifecycle.QueryApprovalStatus'
+type QueryApprovalStatusArgs struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Definition           *ChaincodeDefinition `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *QueryApprovalStatusArgs) Reset()         { *m = QueryApprovalStatusArgs{} }
+func (m *QueryApprovalStatusArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryApprovalStatusArgs) ProtoMessage()    {}
+func (*QueryApprovalStatusArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{11}
+}
+func (m *QueryApprovalStatusArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryApprovalStatusArgs.Unmarshal(m, b)
+}
+func (m *QueryApprovalStatusArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryApprovalStatusArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryApprovalStatusArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryApprovalStatusArgs.Merge(dst, src)
+}
+func (m *QueryApprovalStatusArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryApprovalStatusArgs.Size(m)
+}
+func (m *QueryApprovalStatusArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryApprovalStatusArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryApprovalStatusArgs proto.InternalMessageInfo
+
+func (m *QueryApprovalStatusArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryApprovalStatusArgs) GetDefinition() *ChaincodeDefinition {
+	if m != nil {
+		return m.Definition
+	}
+	return nil
+}
+
+// QueryApprovalStatusResult is the message returned by
+// '+lifecycle.QueryApprovalStatus'
+type QueryApprovalStatusResult struct {
+	Approved             map[string]bool `protobuf:"bytes,1,rep,name=approved,proto3" json:"approved,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *QueryApprovalStatusResult) Reset()         { *m = QueryApprovalStatusResult{} }
+func (m *QueryApprovalStatusResult) String() string { return proto.CompactTextString(m) }
+func (*QueryApprovalStatusResult) ProtoMessage()    {}
+func (*QueryApprovalStatusResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{12}
+}
+func (m *QueryApprovalStatusResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryApprovalStatusResult.Unmarshal(m, b)
+}
+func (m *QueryApprovalStatusResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryApprovalStatusResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryApprovalStatusResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryApprovalStatusResult.Merge(dst, src)
+}
+func (m *QueryApprovalStatusResult) XXX_Size() int {
+	return xxx_messageInfo_QueryApprovalStatusResult.Size(m)
+}
+func (m *QueryApprovalStatusResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryApprovalStatusResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryApprovalStatusResult proto.InternalMessageInfo
+
+func (m *QueryApprovalStatusResult) GetApproved() map[string]bool {
+	if m != nil {
+		return m.Approved
+	}
+	return nil
+}
+
+// QueryChaincodeDefinitionArgs is the message used as the argument to
+// '+lifecycle.QueryChaincodeDefinition'
+type QueryChaincodeDefinitionArgs struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionArgs) Reset()         { *m = QueryChaincodeDefinitionArgs{} }
+func (m *QueryChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionArgs) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{13}
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionArgs.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Size(m)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionArgs proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// QueryChaincodeDefinitionResult is the message returned by
+// '+lifecycle.QueryChaincodeDefinition'
+type QueryChaincodeDefinitionResult struct {
+	Definition           *ChaincodeDefinition `protobuf:"bytes,1,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionResult) Reset()         { *m = QueryChaincodeDefinitionResult{} }
+func (m *QueryChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionResult) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_cdad1977446ccb3b, []int{14}
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionResult.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Size(m)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionResult proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionResult) GetDefinition() *ChaincodeDefinition {
+	if m != nil {
+		return m.Definition
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*InstallChaincodeArgs)(nil), "lifecycle.InstallChaincodeArgs")
 	proto.RegisterType((*InstallChaincodeResult)(nil), "lifecycle.InstallChaincodeResult")
 	proto.RegisterType((*QueryInstalledChaincodeArgs)(nil), "lifecycle.QueryInstalledChaincodeArgs")
 	proto.RegisterType((*QueryInstalledChaincodeResult)(nil), "lifecycle.QueryInstalledChaincodeResult")
+	proto.RegisterType((*UninstallChaincodeArgs)(nil), "lifecycle.UninstallChaincodeArgs")
+	proto.RegisterType((*UninstallChaincodeResult)(nil), "lifecycle.UninstallChaincodeResult")
+	proto.RegisterType((*ChaincodeDefinition)(nil), "lifecycle.ChaincodeDefinition")
+	proto.RegisterType((*ApproveChaincodeDefinitionForMyOrgArgs)(nil), "lifecycle.ApproveChaincodeDefinitionForMyOrgArgs")
+	proto.RegisterType((*ApproveChaincodeDefinitionForMyOrgResult)(nil), "lifecycle.ApproveChaincodeDefinitionForMyOrgResult")
+	proto.RegisterType((*CommitChaincodeDefinitionArgs)(nil), "lifecycle.CommitChaincodeDefinitionArgs")
+	proto.RegisterType((*CommitChaincodeDefinitionResult)(nil), "lifecycle.CommitChaincodeDefinitionResult")
+	proto.RegisterType((*QueryApprovalStatusArgs)(nil), "lifecycle.QueryApprovalStatusArgs")
+	proto.RegisterType((*QueryApprovalStatusResult)(nil), "lifecycle.QueryApprovalStatusResult")
+	proto.RegisterMapType((map[string]bool)(nil), "lifecycle.QueryApprovalStatusResult.ApprovedEntry")
+	proto.RegisterType((*QueryChaincodeDefinitionArgs)(nil), "lifecycle.QueryChaincodeDefinitionArgs")
+	proto.RegisterType((*QueryChaincodeDefinitionResult)(nil), "lifecycle.QueryChaincodeDefinitionResult")
 }
 
 func init() {
-	proto.RegisterFile("peer/lifecycle/lifecycle.proto", fileDescriptor_lifecycle_f98901bea638af10)
-}
-
-var fileDescriptor_lifecycle_f98901bea638af10 = []byte{
-	// 255 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x91, 0x3f, 0x4f, 0xc3, 0x30,
-	0x10, 0xc5, 0x15, 0x40, 0xa0, 0x9e, 0x3a, 0x59, 0x08, 0x82, 0x10, 0xa8, 0xca, 0xd4, 0xa1, 0xb2,
-	0x87, 0x6e, 0x6c, 0xc0, 0x84, 0x58, 0x20, 0x23, 0x4b, 0xe5, 0x38, 0x57, 0xdb, 0xc2, 0x8d, 0xa3,
-	0x73, 0x82, 0x94, 0x8d, 0x8f, 0x8e, 0x6a, 0xb7, 0xe1, 0x8f, 0xd4, 0x89, 0xed, 0xf9, 0xd9, 0xef,
-	0xfc, 0xd3, 0x3b, 0xb8, 0x6d, 0x11, 0x49, 0x38, 0xbb, 0x46, 0x35, 0x28, 0x87, 0xdf, 0x8a, 0xb7,
-	0xe4, 0x3b, 0xcf, 0x26, 0xa3, 0x51, 0x7c, 0x66, 0x70, 0xfe, 0xd4, 0x84, 0x4e, 0x3a, 0xf7, 0x68,
-	0xa4, 0x6d, 0x94, 0xaf, 0xf1, 0x9e, 0x74, 0x60, 0x0c, 0x4e, 0x1a, 0xb9, 0xc1, 0x3c, 0x9b, 0x65,
-	0xf3, 0x49, 0x19, 0x35, 0xcb, 0xe1, 0xec, 0x03, 0x29, 0x58, 0xdf, 0xe4, 0x47, 0xd1, 0xde, 0x1f,
-	0xd9, 0x1d, 0x5c, 0xa9, 0x7d, 0x7c, 0x65, 0xd3, 0xbc, 0x55, 0x2b, 0xd5, 0xbb, 0xd4, 0x98, 0x1f,
-	0xcf, 0xb2, 0xf9, 0xb4, 0xbc, 0x1c, 0x1f, 0xec, 0xfe, 0x7b, 0x49, 0xd7, 0xc5, 0x02, 0x2e, 0xfe,
-	0x12, 0x94, 0x18, 0x7a, 0xd7, 0x6d, 0x19, 0x8c, 0x0c, 0x26, 0x32, 0x4c, 0xcb, 0xa8, 0x8b, 0x67,
-	0xb8, 0x7e, 0xed, 0x91, 0x86, 0x5d, 0x04, 0xeb, 0x7f, 0x60, 0x17, 0x4b, 0xb8, 0x39, 0x30, 0xec,
-	0x30, 0xc1, 0x83, 0x82, 0x85, 0x27, 0xcd, 0xcd, 0xd0, 0x22, 0x39, 0xac, 0x35, 0x12, 0x5f, 0xcb,
-	0x8a, 0xac, 0x4a, 0xed, 0x06, 0xbe, 0x6d, 0x9f, 0x8f, 0x15, 0xbf, 0x2d, 0xb5, 0xed, 0x4c, 0x5f,
-	0x71, 0xe5, 0x37, 0xe2, 0x47, 0x48, 0xa4, 0x90, 0x48, 0x21, 0xf1, 0x7b, 0x65, 0xd5, 0x69, 0xb4,
-	0x97, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd3, 0x6e, 0xec, 0xb6, 0xcb, 0x01, 0x00, 0x00,
+	proto.RegisterFile("peer/lifecycle/lifecycle.proto", fileDescriptor_lifecycle_cdad1977446ccb3b)
+}
+
+var fileDescriptor_lifecycle_cdad1977446ccb3b = []byte{
+	// 587 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x41, 0x6f, 0xd3, 0x4c,
+	0x10, 0x95, 0x9b, 0xb6, 0x5f, 0x3b, 0xe9, 0x27, 0xb5, 0x6e, 0xd5, 0xb8, 0x81, 0xa6, 0xc1, 0x07,
+	0x14, 0x41, 0xb1, 0x45, 0x72, 0x41, 0x45, 0x42, 0x0a, 0x81, 0x4a, 0x08, 0x01, 0xc5, 0x88, 0x0b,
+	0x97, 0xb0, 0xb1, 0x27, 0xce, 0xaa, 0xeb, 0x5d, 0xb3, 0xb6, 0x23, 0x59, 0xe2, 0xc0, 0x1f, 0xe1,
+	0xce, 0xcf, 0x44, 0x5e, 0x3b, 0x8e, 0x03, 0x49, 0x50, 0x55, 0xf5, 0xb6, 0xbb, 0xf3, 0xde, 0xbc,
+	0x37, 0x33, 0xbb, 0x36, 0xb4, 0x42, 0x44, 0x69, 0x33, 0x3a, 0x46, 0x37, 0x75, 0x19, 0xce, 0x57,
+	0x56, 0x28, 0x45, 0x2c, 0xf4, 0xdd, 0xf2, 0xa0, 0xd9, 0x70, 0x45, 0x10, 0x08, 0x6e, 0xbb, 0x82,
+	0x31, 0x74, 0x63, 0x2a, 0x78, 0x8e, 0x31, 0x7f, 0x68, 0x70, 0xf4, 0x86, 0x47, 0x31, 0x61, 0x6c,
+	0x30, 0x21, 0x94, 0xbb, 0xc2, 0xc3, 0xbe, 0xf4, 0x23, 0x5d, 0x87, 0x4d, 0x4e, 0x02, 0x34, 0xb4,
+	0xb6, 0xd6, 0xd9, 0x75, 0xd4, 0x5a, 0x37, 0xe0, 0xbf, 0x29, 0xca, 0x88, 0x0a, 0x6e, 0x6c, 0xa8,
+	0xe3, 0xd9, 0x56, 0xbf, 0x80, 0x13, 0x77, 0x46, 0x1f, 0xd2, 0x3c, 0xdf, 0x30, 0x24, 0xee, 0x35,
+	0xf1, 0xd1, 0xa8, 0xb5, 0xb5, 0xce, 0x9e, 0xd3, 0x28, 0x01, 0x85, 0xde, 0x55, 0x1e, 0x36, 0xcf,
+	0xe1, 0xf8, 0x4f, 0x07, 0x0e, 0x46, 0x09, 0x8b, 0x33, 0x0f, 0x13, 0x12, 0x4d, 0x94, 0x87, 0x3d,
+	0x47, 0xad, 0xcd, 0xb7, 0x70, 0xef, 0x63, 0x82, 0x32, 0x2d, 0x28, 0xe8, 0xdd, 0xc2, 0xb6, 0xd9,
+	0x83, 0xd3, 0x15, 0xc9, 0xd6, 0x38, 0xb8, 0x84, 0xe3, 0xcf, 0x9c, 0xde, 0xba, 0x67, 0xa6, 0x05,
+	0xc6, 0xdf, 0x79, 0xd6, 0xe8, 0xfe, 0xdc, 0x80, 0xc3, 0x12, 0xf7, 0x0a, 0xc7, 0x94, 0xd3, 0x6c,
+	0x90, 0x7a, 0x13, 0x76, 0x22, 0xfc, 0x96, 0x20, 0x77, 0x73, 0xe5, 0x9a, 0x53, 0xee, 0xd7, 0x4c,
+	0xec, 0x09, 0xe8, 0xc8, 0x3d, 0x21, 0x23, 0x0c, 0x90, 0xc7, 0xc3, 0x90, 0x25, 0x3e, 0xe5, 0x6a,
+	0x54, 0xbb, 0xce, 0x41, 0x25, 0x72, 0xa5, 0x02, 0xfa, 0x63, 0x38, 0x98, 0x12, 0x46, 0x3d, 0x92,
+	0x49, 0xce, 0xd0, 0x9b, 0x0a, 0xbd, 0x3f, 0x0f, 0x14, 0xe0, 0xa7, 0x70, 0x54, 0x05, 0x13, 0x49,
+	0x02, 0x8c, 0x51, 0x1a, 0x5b, 0xaa, 0x9a, 0xc3, 0x0a, 0x7e, 0x16, 0xd2, 0xfb, 0x50, 0x9f, 0xdf,
+	0xcd, 0xc8, 0xd8, 0x6e, 0x6b, 0x9d, 0x7a, 0xf7, 0xcc, 0xca, 0xaf, 0xad, 0x35, 0x28, 0x43, 0x03,
+	0xc1, 0xc7, 0xd4, 0x2f, 0xae, 0x8e, 0x53, 0xe5, 0x98, 0xdf, 0xe1, 0x61, 0x3f, 0x0c, 0xa5, 0x98,
+	0xe2, 0x92, 0x2e, 0x5d, 0x0a, 0xf9, 0x2e, 0xfd, 0x20, 0xfd, 0x95, 0x73, 0x7a, 0x01, 0xe0, 0x95,
+	0x68, 0xd5, 0xac, 0x7a, 0xb7, 0x65, 0xcd, 0x9f, 0xd4, 0x92, 0x9c, 0x4e, 0x85, 0x61, 0x3e, 0x82,
+	0xce, 0xbf, 0xd5, 0xf3, 0xe9, 0x9a, 0x11, 0x9c, 0x0e, 0x44, 0x10, 0xd0, 0x78, 0x09, 0xf4, 0xce,
+	0x0c, 0x3e, 0x80, 0xb3, 0x95, 0xa2, 0x85, 0xaf, 0x00, 0x1a, 0xea, 0x39, 0xe4, 0x85, 0x10, 0xf6,
+	0x29, 0x26, 0x71, 0x12, 0xdd, 0x99, 0xa3, 0x5f, 0x1a, 0x9c, 0x2c, 0xd1, 0x2b, 0x9e, 0xc0, 0x7b,
+	0xd8, 0x21, 0xea, 0x1c, 0x3d, 0x43, 0x6b, 0xd7, 0x3a, 0xf5, 0x6e, 0xb7, 0x92, 0x7b, 0x25, 0xcf,
+	0xea, 0x17, 0xa4, 0xd7, 0x3c, 0x96, 0xa9, 0x53, 0xe6, 0x68, 0x3e, 0x87, 0xff, 0x17, 0x42, 0xfa,
+	0x3e, 0xd4, 0xae, 0x31, 0x2d, 0x2a, 0xca, 0x96, 0xfa, 0x11, 0x6c, 0x4d, 0x09, 0x4b, 0x50, 0xd5,
+	0xb2, 0xe3, 0xe4, 0x9b, 0x8b, 0x8d, 0x67, 0x9a, 0xd9, 0x85, 0xfb, 0x4a, 0xf1, 0x06, 0x03, 0x33,
+	0xbf, 0x42, 0x6b, 0x15, 0xa7, 0x28, 0x71, 0xb1, 0x81, 0xda, 0x4d, 0x1b, 0xf8, 0xd2, 0x85, 0x73,
+	0x21, 0x7d, 0x6b, 0x92, 0x86, 0x28, 0x19, 0x7a, 0x3e, 0x4a, 0x6b, 0x4c, 0x46, 0x92, 0xba, 0xf9,
+	0xc7, 0x3d, 0xb2, 0xb2, 0x1f, 0xc4, 0x3c, 0xdf, 0x97, 0x9e, 0x4f, 0xe3, 0x49, 0x32, 0xca, 0x5e,
+	0x95, 0x5d, 0x21, 0xd9, 0x39, 0xc9, 0xce, 0x49, 0xf6, 0xe2, 0x5f, 0x65, 0xb4, 0xad, 0x8e, 0x7b,
+	0xbf, 0x07, 0x00, 0xff, 0x43, 0xbb, 0x37, 0x6e, 0x06, 0x00, 0x00,
 }
@@ -40,17 +40,20 @@ type ProposalResponse struct {
 	Payload []byte `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
 	// The endorsement of the proposal, basically
 	// the endorser's signature over the payload
-	Endorsement          *Endorsement `protobuf:"bytes,6,opt,name=endorsement,proto3" json:"endorsement,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Endorsement *Endorsement `protobuf:"bytes,6,opt,name=endorsement,proto3" json:"endorsement,omitempty"`
+	// The resources used by the chaincodes executed to simulate the
+	// proposal. They are not covered by the endorsement.
+	ResourceUsage        []*ChaincodeResourceUsage `protobuf:"bytes,7,rep,name=resource_usage,json=resourceUsage,proto3" json:"resource_usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
 }
 
 func (m *ProposalResponse) Reset()         { *m = ProposalResponse{} }
 func (m *ProposalResponse) String() string { return proto.CompactTextString(m) }
 func (*ProposalResponse) ProtoMessage()    {}
 func (*ProposalResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{0}
+	return fileDescriptor_proposal_response_62622ddec9c4b870, []int{0}
 }
 func (m *ProposalResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ProposalResponse.Unmarshal(m, b)
@@ -105,6 +108,90 @@ func (m *ProposalResponse) GetEndorsement() *Endorsement {
 	return nil
 }
 
+func (m *ProposalResponse) GetResourceUsage() []*ChaincodeResourceUsage {
+	if m != nil {
+		return m.ResourceUsage
+	}
+	return nil
+}
+
+// ChaincodeResourceUsage reports the resources used by a chaincode to
+// simulate a proposal
+type ChaincodeResourceUsage struct {
+	// The name and version of the chaincode
+	Chaincode string `protobuf:"bytes,1,opt,name=chaincode,proto3" json:"chaincode,omitempty"`
+	// The number of keys read from the state
+	StateReads uint64 `protobuf:"varint,2,opt,name=state_reads,json=stateReads,proto3" json:"state_reads,omitempty"`
+	// The number of results returned by range, rich and history queries
+	RangeQueryResults uint64 `protobuf:"varint,3,opt,name=range_query_results,json=rangeQueryResults,proto3" json:"range_query_results,omitempty"`
+	// The number of keys written to or deleted from the state
+	Writes uint64 `protobuf:"varint,4,opt,name=writes,proto3" json:"writes,omitempty"`
+	// The number of bytes read from and written to the state
+	TotalBytes           uint64   `protobuf:"varint,5,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeResourceUsage) Reset()         { *m = ChaincodeResourceUsage{} }
+func (m *ChaincodeResourceUsage) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeResourceUsage) ProtoMessage()    {}
+func (*ChaincodeResourceUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proposal_response_62622ddec9c4b870, []int{1}
+}
+func (m *ChaincodeResourceUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeResourceUsage.Unmarshal(m, b)
+}
+func (m *ChaincodeResourceUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeResourceUsage.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeResourceUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeResourceUsage.Merge(dst, src)
+}
+func (m *ChaincodeResourceUsage) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeResourceUsage.Size(m)
+}
+func (m *ChaincodeResourceUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeResourceUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeResourceUsage proto.InternalMessageInfo
+
+func (m *ChaincodeResourceUsage) GetChaincode() string {
+	if m != nil {
+		return m.Chaincode
+	}
+	return ""
+}
+
+func (m *ChaincodeResourceUsage) GetStateReads() uint64 {
+	if m != nil {
+		return m.StateReads
+	}
+	return 0
+}
+
+func (m *ChaincodeResourceUsage) GetRangeQueryResults() uint64 {
+	if m != nil {
+		return m.RangeQueryResults
+	}
+	return 0
+}
+
+func (m *ChaincodeResourceUsage) GetWrites() uint64 {
+	if m != nil {
+		return m.Writes
+	}
+	return 0
+}
+
+func (m *ChaincodeResourceUsage) GetTotalBytes() uint64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
 // A response with a representation similar to an HTTP response that can
 // be used within another message.
 type Response struct {
@@ -123,7 +210,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{1}
+	return fileDescriptor_proposal_response_62622ddec9c4b870, []int{2}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -201,7 +288,7 @@ func (m *ProposalResponsePayload) Reset()         { *m = ProposalResponsePayload
 func (m *ProposalResponsePayload) String() string { return proto.CompactTextString(m) }
 func (*ProposalResponsePayload) ProtoMessage()    {}
 func (*ProposalResponsePayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{2}
+	return fileDescriptor_proposal_response_62622ddec9c4b870, []int{3}
 }
 func (m *ProposalResponsePayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ProposalResponsePayload.Unmarshal(m, b)
@@ -259,7 +346,7 @@ func (m *Endorsement) Reset()         { *m = Endorsement{} }
 func (m *Endorsement) String() string { return proto.CompactTextString(m) }
 func (*Endorsement) ProtoMessage()    {}
 func (*Endorsement) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{3}
+	return fileDescriptor_proposal_response_62622ddec9c4b870, []int{4}
 }
 func (m *Endorsement) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Endorsement.Unmarshal(m, b)
@@ -295,38 +382,47 @@ func (m *Endorsement) GetSignature() []byte {
 
 func init() {
 	proto.RegisterType((*ProposalResponse)(nil), "protos.ProposalResponse")
+	proto.RegisterType((*ChaincodeResourceUsage)(nil), "protos.ChaincodeResourceUsage")
 	proto.RegisterType((*Response)(nil), "protos.Response")
 	proto.RegisterType((*ProposalResponsePayload)(nil), "protos.ProposalResponsePayload")
 	proto.RegisterType((*Endorsement)(nil), "protos.Endorsement")
 }
 
 func init() {
-	proto.RegisterFile("peer/proposal_response.proto", fileDescriptor_proposal_response_22a755721b685f40)
-}
-
-var fileDescriptor_proposal_response_22a755721b685f40 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x4b, 0xfb, 0x30,
-	0x14, 0xc5, 0xe9, 0xfe, 0xff, 0xcd, 0x2d, 0x9b, 0x30, 0x2a, 0x68, 0x19, 0x03, 0x47, 0x7d, 0x99,
-	0x20, 0x29, 0x28, 0x82, 0xcf, 0x03, 0xd1, 0xc7, 0x11, 0xc4, 0x07, 0x11, 0x24, 0xdd, 0xee, 0xd2,
-	0x62, 0xdb, 0x84, 0xdc, 0x54, 0xdc, 0x07, 0xf6, 0x7b, 0x48, 0xd3, 0xa6, 0xab, 0xe2, 0xd3, 0x38,
-	0x77, 0x27, 0xbf, 0x7b, 0xcf, 0xed, 0x25, 0x73, 0x05, 0xa0, 0x23, 0xa5, 0xa5, 0x92, 0xc8, 0xb3,
-	0x37, 0x0d, 0xa8, 0x64, 0x81, 0x40, 0x95, 0x96, 0x46, 0xfa, 0x03, 0xfb, 0x83, 0xb3, 0x73, 0x21,
-	0xa5, 0xc8, 0x20, 0xb2, 0x32, 0x2e, 0x77, 0x91, 0x49, 0x73, 0x40, 0xc3, 0x73, 0x55, 0x1b, 0xc3,
-	0x2f, 0x8f, 0x4c, 0xd7, 0x0d, 0x84, 0x35, 0x0c, 0x3f, 0x20, 0x47, 0x1f, 0xa0, 0x31, 0x95, 0x45,
-	0xe0, 0x2d, 0xbc, 0x65, 0x9f, 0x39, 0xe9, 0xdf, 0x91, 0x51, 0x4b, 0x08, 0x7a, 0x0b, 0x6f, 0x39,
-	0xbe, 0x9e, 0xd1, 0xba, 0x07, 0x75, 0x3d, 0xe8, 0x93, 0x73, 0xb0, 0x83, 0xd9, 0xbf, 0x22, 0x43,
-	0x37, 0x63, 0xf0, 0xdf, 0x3e, 0x9c, 0xd6, 0x2f, 0x90, 0xba, 0xbe, 0xac, 0x75, 0x54, 0x13, 0x28,
-	0xbe, 0xcf, 0x24, 0xdf, 0x06, 0xfd, 0x85, 0xb7, 0x9c, 0x30, 0x27, 0xfd, 0x5b, 0x32, 0x86, 0x62,
-	0x2b, 0x35, 0x42, 0x0e, 0x85, 0x09, 0x06, 0x16, 0x75, 0xe2, 0x50, 0xf7, 0x87, 0xbf, 0x58, 0xd7,
-	0x17, 0x3e, 0x93, 0x61, 0x1b, 0xef, 0x94, 0x0c, 0xd0, 0x70, 0x53, 0x62, 0x93, 0xae, 0x51, 0x55,
-	0xd3, 0x1c, 0x10, 0xb9, 0x00, 0x1b, 0x6d, 0xc4, 0x9c, 0xec, 0x8e, 0xf3, 0xef, 0xc7, 0x38, 0xe1,
-	0x2b, 0x39, 0xfb, 0xbd, 0xbe, 0x75, 0x33, 0xe9, 0x05, 0x39, 0x6e, 0x3f, 0x4f, 0xc2, 0x31, 0xb1,
-	0xdd, 0x26, 0x6c, 0xe2, 0x8a, 0x8f, 0x1c, 0x13, 0x7f, 0x4e, 0x46, 0xf0, 0x69, 0xa0, 0xb0, 0xcb,
-	0xee, 0x59, 0xc3, 0xa1, 0x10, 0x3e, 0x90, 0x71, 0x27, 0x91, 0x3f, 0x23, 0xc3, 0x26, 0x93, 0x6e,
-	0x60, 0xad, 0xae, 0x40, 0x98, 0x8a, 0x82, 0x9b, 0x52, 0x83, 0x03, 0xb5, 0x85, 0x55, 0x42, 0x42,
-	0xa9, 0x05, 0x4d, 0xf6, 0x0a, 0x74, 0x06, 0x5b, 0x01, 0x9a, 0xee, 0x78, 0xac, 0xd3, 0x8d, 0x5b,
-	0x5c, 0x75, 0x4d, 0xab, 0x3f, 0xa2, 0x6c, 0xde, 0xb9, 0x80, 0x97, 0x4b, 0x91, 0x9a, 0xa4, 0x8c,
-	0xe9, 0x46, 0xe6, 0x51, 0x87, 0x11, 0xd5, 0x8c, 0xfa, 0xba, 0x30, 0xaa, 0x18, 0x71, 0x7d, 0x79,
-	0x37, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0e, 0x52, 0x0b, 0x35, 0xa0, 0x02, 0x00, 0x00,
+	proto.RegisterFile("peer/proposal_response.proto", fileDescriptor_proposal_response_62622ddec9c4b870)
+}
+
+var fileDescriptor_proposal_response_62622ddec9c4b870 = []byte{
+	// 495 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x5d, 0x8b, 0xd3, 0x40,
+	0x14, 0xa5, 0x9f, 0xdb, 0x4e, 0xbb, 0xb2, 0xce, 0x42, 0x0d, 0x65, 0x71, 0x4b, 0x7c, 0xa9, 0x20,
+	0x09, 0xac, 0x08, 0x3e, 0x57, 0x16, 0x7d, 0x5c, 0x07, 0xf5, 0x41, 0x84, 0x32, 0x4d, 0xee, 0x26,
+	0xc1, 0x24, 0x13, 0xef, 0x9d, 0xa8, 0xfd, 0x4f, 0xfe, 0x08, 0x7f, 0x9a, 0xcc, 0x24, 0x93, 0xc6,
+	0x65, 0x9f, 0xc2, 0x39, 0xf7, 0xcc, 0xb9, 0x93, 0x7b, 0xee, 0xb0, 0xab, 0x0a, 0x00, 0xc3, 0x0a,
+	0x55, 0xa5, 0x48, 0xe6, 0x7b, 0x04, 0xaa, 0x54, 0x49, 0x10, 0x54, 0xa8, 0xb4, 0xe2, 0x53, 0xfb,
+	0xa1, 0xf5, 0x75, 0xa2, 0x54, 0x92, 0x43, 0x68, 0xe1, 0xa1, 0xbe, 0x0f, 0x75, 0x56, 0x00, 0x69,
+	0x59, 0x54, 0x8d, 0xd0, 0xff, 0x33, 0x64, 0x17, 0x77, 0xad, 0x89, 0x68, 0x3d, 0xb8, 0xc7, 0xce,
+	0x7e, 0x02, 0x52, 0xa6, 0x4a, 0x6f, 0xb0, 0x19, 0x6c, 0x27, 0xc2, 0x41, 0xfe, 0x96, 0xcd, 0x3b,
+	0x07, 0x6f, 0xb8, 0x19, 0x6c, 0x17, 0x37, 0xeb, 0xa0, 0xe9, 0x11, 0xb8, 0x1e, 0xc1, 0x27, 0xa7,
+	0x10, 0x27, 0x31, 0x7f, 0xc5, 0x66, 0xee, 0x8e, 0xde, 0xd8, 0x1e, 0xbc, 0x68, 0x4e, 0x50, 0xe0,
+	0xfa, 0x8a, 0x19, 0xf6, 0x6e, 0x50, 0xc9, 0x63, 0xae, 0x64, 0xec, 0x4d, 0x36, 0x83, 0xed, 0x52,
+	0x38, 0xc8, 0xdf, 0xb0, 0x05, 0x94, 0xb1, 0x42, 0x82, 0x02, 0x4a, 0xed, 0x4d, 0xad, 0xd5, 0xa5,
+	0xb3, 0xba, 0x3d, 0x95, 0x44, 0x5f, 0xc7, 0x6f, 0xd9, 0x13, 0x04, 0x52, 0x35, 0x46, 0xb0, 0xaf,
+	0x49, 0x26, 0xe0, 0x9d, 0x6d, 0x46, 0xdb, 0xc5, 0xcd, 0x73, 0x77, 0xf2, 0x5d, 0x2a, 0xb3, 0x32,
+	0x52, 0x31, 0x88, 0x56, 0xf6, 0xd9, 0xa8, 0xc4, 0x39, 0xf6, 0xa1, 0xff, 0x77, 0xc0, 0x56, 0x8f,
+	0x2b, 0xf9, 0x15, 0x9b, 0x47, 0xae, 0x62, 0xc7, 0x36, 0x17, 0x27, 0x82, 0x5f, 0xb3, 0x05, 0x69,
+	0xa9, 0x61, 0x8f, 0x20, 0x63, 0xb2, 0xa3, 0x1b, 0x0b, 0x66, 0x29, 0x61, 0x18, 0x1e, 0xb0, 0x4b,
+	0x94, 0x65, 0x02, 0xfb, 0x1f, 0x35, 0xe0, 0xd1, 0xe4, 0x59, 0xe7, 0x9a, 0xbc, 0x91, 0x15, 0x3e,
+	0xb5, 0xa5, 0x8f, 0xa6, 0x22, 0x9a, 0x02, 0x5f, 0xb1, 0xe9, 0x2f, 0xcc, 0x34, 0x90, 0x9d, 0xe6,
+	0x58, 0xb4, 0xc8, 0x34, 0xd2, 0x4a, 0xcb, 0x7c, 0x7f, 0x38, 0x9a, 0xe2, 0xa4, 0x69, 0x64, 0xa9,
+	0x9d, 0x61, 0xfc, 0x2f, 0x6c, 0xd6, 0x05, 0xbd, 0x62, 0x53, 0x73, 0x85, 0x9a, 0xda, 0x9c, 0x5b,
+	0x64, 0xc6, 0x5f, 0x00, 0xd9, 0x31, 0x0d, 0xed, 0x9f, 0x38, 0xd8, 0x0f, 0x66, 0xf4, 0x5f, 0x30,
+	0xfe, 0x37, 0xf6, 0xec, 0xe1, 0x22, 0xdd, 0xb5, 0x99, 0xbd, 0x60, 0xe7, 0xdd, 0xa2, 0xa6, 0x92,
+	0x52, 0xdb, 0x6d, 0x29, 0x96, 0x8e, 0xfc, 0x20, 0x29, 0x35, 0xf3, 0x83, 0xdf, 0x1a, 0x4a, 0xbb,
+	0x76, 0x43, 0x2b, 0x38, 0x11, 0xfe, 0x7b, 0xb6, 0xe8, 0x65, 0xcb, 0xd7, 0x6c, 0xd6, 0xa6, 0x8b,
+	0xad, 0x59, 0x87, 0x8d, 0x11, 0x65, 0x49, 0x29, 0x75, 0x8d, 0xe0, 0x8c, 0x3a, 0x62, 0x97, 0x32,
+	0x5f, 0x61, 0x12, 0xa4, 0xc7, 0x0a, 0x30, 0x87, 0x38, 0x01, 0x0c, 0xee, 0xe5, 0x01, 0xb3, 0xc8,
+	0x2d, 0x42, 0x05, 0x80, 0xbb, 0x47, 0x7e, 0x25, 0xfa, 0x2e, 0x13, 0xf8, 0xfa, 0x32, 0xc9, 0x74,
+	0x5a, 0x1f, 0x82, 0x48, 0x15, 0x61, 0xcf, 0x23, 0x6c, 0x3c, 0x9a, 0x77, 0x46, 0xa1, 0xf1, 0x38,
+	0x34, 0x6f, 0xf0, 0xf5, 0xbf, 0x01, 0x00, 0x73, 0xb6, 0x8e, 0x2d, 0xaa, 0x03, 0x00, 0x00,
 }
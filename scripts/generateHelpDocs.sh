@@ -21,7 +21,7 @@ cat docs/wrappers/license_postscript.md >> $DOC
 DOC=docs/source/commands/peerchaincode.md
 cat docs/wrappers/peer_chaincode_preamble.md > $DOC
 
-for x in "peer chaincode install" "peer chaincode instantiate" "peer chaincode invoke" "peer chaincode list" "peer chaincode package" "peer chaincode query" "peer chaincode signpackage" "peer chaincode upgrade"; do
+for x in "peer chaincode approveformyorg" "peer chaincode commit" "peer chaincode install" "peer chaincode instantiate" "peer chaincode invoke" "peer chaincode list" "peer chaincode package" "peer chaincode query" "peer chaincode signpackage" "peer chaincode uninstall" "peer chaincode upgrade"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -34,7 +34,7 @@ cat docs/wrappers/peer_chaincode_postscript.md >> $DOC
 DOC=docs/source/commands/peerchannel.md
 cat docs/wrappers/peer_channel_preamble.md > $DOC
 
-for x in "peer channel" "peer channel create" "peer channel fetch" "peer channel getinfo" "peer channel join" "peer channel list" "peer channel signconfigtx" "peer channel update"; do
+for x in "peer channel" "peer channel create" "peer channel fetch" "peer channel getinfo" "peer channel join" "peer channel list" "peer channel migrate" "peer channel signconfigtx" "peer channel update"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -60,7 +60,7 @@ cat docs/wrappers/peer_logging_postscript.md >> $DOC
 DOC=docs/source/commands/peernode.md
 cat docs/wrappers/peer_node_preamble.md > $DOC
 
-for x in "peer node start" "peer node status" "peer node reset" "peer node rollback"; do
+for x in "peer node start" "peer node status" "peer node reset" "peer node rollback" "peer node verify-ledger" "peer node rebuild-dbs"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC